@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/spf13/cobra"
+)
+
+// benchFlags は bench コマンド固有のフラグを保持します。
+type benchFlags struct {
+	Sizes    []string // --sizes 計測するペイロードサイズ
+	Parallel []int    // --parallel 計測する並列数
+	Rounds   int      // --rounds 各組み合わせの繰り返し回数
+	Keep     bool     // --keep 計測に使用したオブジェクトを削除しない
+}
+
+// benchResult は、1つの操作・サイズ・並列数の組み合わせに対する計測結果です。
+type benchResult struct {
+	Op        string
+	Size      int64
+	Parallel  int
+	Latencies []time.Duration
+	Elapsed   time.Duration
+}
+
+// benchRecord は、bench の --format json で出力する1件分の計測結果です。レイテンシはミリ秒で出力します。
+type benchRecord struct {
+	Op        string  `json:"op"`
+	Size      int64   `json:"size"`
+	Parallel  int     `json:"parallel"`
+	Objects   int     `json:"objects"`
+	MiBPerSec float64 `json:"mib_per_sec"`
+	P50Ms     float64 `json:"p50_ms"`
+	P90Ms     float64 `json:"p90_ms"`
+	P99Ms     float64 `json:"p99_ms"`
+	MaxMs     float64 `json:"max_ms"`
+}
+
+// newBenchCmd は 'bench' サブコマンドを生成します。
+func newBenchCmd() *cobra.Command {
+	var flags benchFlags
+
+	benchCmd := &cobra.Command{
+		Use:   "bench [destination_prefix]",
+		Short: "合成ペイロードでアップロード/ダウンロードのスループットとレイテンシを計測します。",
+		Long: `指定されたプレフィックス (GCS URI またはローカルディレクトリ) に合成ペイロードを書き込み、読み戻して、
+サイズと並列数の組み合わせごとにスループットとレイテンシのパーセンタイルを表示します。
+リージョン、マシンタイプ、チャンクサイズ設定などの比較に使用できます。計測に使用したオブジェクトは終了時に削除されます。`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBench(cmd, args, &flags)
+		},
+	}
+
+	benchCmd.Flags().StringSliceVar(&flags.Sizes, "sizes", []string{"1MiB", "100MiB"}, "計測するペイロードサイズ (カンマ区切り)")
+	benchCmd.Flags().IntSliceVar(&flags.Parallel, "parallel", []int{1, 4}, "計測する並列数 (カンマ区切り)")
+	benchCmd.Flags().IntVar(&flags.Rounds, "rounds", 3, "各組み合わせの繰り返し回数")
+	benchCmd.Flags().BoolVar(&flags.Keep, "keep", false, "計測に使用したオブジェクトを削除せずに残す")
+
+	return benchCmd
+}
+
+// runBench は bench コマンドの実行ロジックです。
+func runBench(cmd *cobra.Command, args []string, flags *benchFlags) error {
+	ctx := cmd.Context()
+	prefix := strings.TrimSuffix(args[0], "/")
+
+	sizes := make([]int64, 0, len(flags.Sizes))
+	for _, s := range flags.Sizes {
+		size, err := parseByteSize(s)
+		if err != nil {
+			return err
+		}
+		sizes = append(sizes, size)
+	}
+	if flags.Rounds < 1 {
+		return usageError(fmt.Errorf(tr("--rounds には1以上を指定してください: %d"), flags.Rounds))
+	}
+
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	inputReader, err := clientFactory.NewInputReader()
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+	writer, err := clientFactory.NewOutputWriter()
+	if err != nil {
+		return fmt.Errorf(tr("OutputWriterの作成に失敗しました")+": %w", err)
+	}
+
+	var results []benchResult
+	var written []string
+	defer func() {
+		if !flags.Keep {
+			cleanupBenchObjects(context.WithoutCancel(ctx), writer, written)
+		}
+	}()
+
+	runID := strconv.FormatInt(time.Now().UnixNano(), 36)
+	for _, size := range sizes {
+		for _, parallel := range flags.Parallel {
+			if parallel < 1 {
+				return usageError(fmt.Errorf(tr("--parallel には1以上を指定してください: %d"), parallel))
+			}
+
+			uris := make([]string, parallel*flags.Rounds)
+			for i := range uris {
+				uris[i] = fmt.Sprintf("%s/remoteio-bench-%s/%s-p%d-%04d", prefix, runID, formatByteSize(size), parallel, i)
+			}
+
+			upload, err := runBenchOp("upload", size, parallel, uris, func(i int, uri string) error {
+				payload := io.LimitReader(rand.NewChaCha8(benchSeed(i)), size)
+				return writer.Write(ctx, uri, payload, remoteio.WithContentType("application/octet-stream"))
+			})
+			written = append(written, uris...)
+			if err != nil {
+				return err
+			}
+			results = append(results, upload)
+
+			download, err := runBenchOp("download", size, parallel, uris, func(i int, uri string) error {
+				rc, err := inputReader.Open(ctx, uri)
+				if err != nil {
+					return err
+				}
+				defer rc.Close()
+				_, err = io.Copy(io.Discard, rc)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			results = append(results, download)
+		}
+	}
+
+	printBenchResults(cmd.OutOrStdout(), results, jsonOutput(cmd))
+	return nil
+}
+
+// runBenchOp は、uris に対する操作を parallel 並列で実行し、各操作のレイテンシと全体の所要時間を計測します。
+func runBenchOp(op string, size int64, parallel int, uris []string, do func(i int, uri string) error) (benchResult, error) {
+	result := benchResult{Op: op, Size: size, Parallel: parallel, Latencies: make([]time.Duration, len(uris))}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, parallel)
+	start := time.Now()
+	for i, uri := range uris {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			opStart := time.Now()
+			if err := do(i, uri); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf(tr("ベンチマークの%s処理に失敗しました (%s)")+": %w", op, uri, err)
+				}
+				mu.Unlock()
+				return
+			}
+			result.Latencies[i] = time.Since(opStart)
+		}()
+	}
+	wg.Wait()
+	result.Elapsed = time.Since(start)
+	return result, firstErr
+}
+
+// benchSeed は、ペイロードの乱数シードを生成します。
+func benchSeed(i int) [32]byte {
+	var seed [32]byte
+	copy(seed[:], strconv.Itoa(i))
+	return seed
+}
+
+// percentile は、ソート済みのレイテンシから p パーセンタイル (最近傍順位法) を返します。
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p/100*float64(len(sorted))+0.5) - 1
+	rank = max(0, min(rank, len(sorted)-1))
+	return sorted[rank]
+}
+
+// printBenchResults は、計測結果を表形式 (asJSON が true の場合は1件ごとに1行の JSON) で出力します。
+func printBenchResults(out io.Writer, results []benchResult, asJSON bool) {
+	if asJSON {
+		ms := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+		for _, r := range results {
+			sorted := slices.Clone(r.Latencies)
+			slices.Sort(sorted)
+			writeJSONLine(out, benchRecord{
+				Op: r.Op, Size: r.Size, Parallel: r.Parallel, Objects: len(r.Latencies),
+				MiBPerSec: float64(r.Size) * float64(len(r.Latencies)) / (1 << 20) / r.Elapsed.Seconds(),
+				P50Ms:     ms(percentile(sorted, 50)),
+				P90Ms:     ms(percentile(sorted, 90)),
+				P99Ms:     ms(percentile(sorted, 99)),
+				MaxMs:     ms(sorted[len(sorted)-1]),
+			})
+		}
+		return
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "OP\tSIZE\tPARALLEL\tOBJECTS\tMiB/s\tP50\tP90\tP99\tMAX\t")
+	for _, r := range results {
+		sorted := slices.Clone(r.Latencies)
+		slices.Sort(sorted)
+		throughput := float64(r.Size) * float64(len(r.Latencies)) / (1 << 20) / r.Elapsed.Seconds()
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\t\n",
+			r.Op, formatByteSize(r.Size), r.Parallel, len(r.Latencies), throughput,
+			percentile(sorted, 50).Round(100*time.Microsecond),
+			percentile(sorted, 90).Round(100*time.Microsecond),
+			percentile(sorted, 99).Round(100*time.Microsecond),
+			sorted[len(sorted)-1].Round(100*time.Microsecond),
+		)
+	}
+	tw.Flush()
+}
+
+// cleanupBenchObjects は、計測で書き込んだオブジェクトとファイルを削除します。
+func cleanupBenchObjects(ctx context.Context, writer remoteio.OutputWriter, uris []string) {
+	deleter, ok := writer.(remoteio.Deleter)
+	for _, uri := range uris {
+		err := errors.New(tr("OutputWriterが削除をサポートしていません"))
+		if ok {
+			err = deleter.Delete(ctx, uri)
+		}
+		if remoteio.SchemeOf(uri) == "" {
+			// 空になった計測用ディレクトリも削除する
+			os.Remove(filepath.Dir(uri))
+		}
+		if err != nil && !errors.Is(err, remoteio.ErrNotFound) {
+			fmt.Fprintln(os.Stderr, trf("警告: 計測用オブジェクトの削除に失敗しました (%s): %v", uri, err))
+		}
+	}
+}
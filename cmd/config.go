@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	clibase "github.com/shouni/go-cli-base"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+
+	"github.com/shouni/go-remote-io/pkg/factory"
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// profileEnv は、--profile を省略した場合に使用するプロファイル名を指定する環境変数です。
+const profileEnv = "REMOTEIO_PROFILE"
+
+// configFile は、--config で指定する設定ファイル (YAML) の内容です。
+//
+//	default_profile: dev
+//	aliases:
+//	  logs: gs://acme-logs
+//	profiles:
+//	  prod:
+//	    credentials: /etc/remoteio/prod-sa.json
+//	    billing_project: acme-billing
+//	    default_bucket: acme-prod-data
+//	    parallelism: 16
+//	    aliases:
+//	      prod: gs://acme-prod-reports
+type configFile struct {
+	DefaultProfile string              `yaml:"default_profile"` // --profile と REMOTEIO_PROFILE を省略した場合に使用するプロファイル
+	Aliases        map[string]string   `yaml:"aliases"`         // すべてのプロファイルで使用する URI のエイリアス (名前 → URI)
+	Profiles       map[string]*profile `yaml:"profiles"`        // 名前付きのプロファイル
+}
+
+// profile は、設定ファイルの1つのプロファイルです。フラグで明示した値はプロファイルの値より優先します。
+type profile struct {
+	Credentials    string            `yaml:"credentials"`     // GCS の認証に使用するサービスアカウントの鍵ファイルなど
+	BillingProject string            `yaml:"billing_project"` // --billing-project の既定値
+	DefaultBucket  string            `yaml:"default_bucket"`  // gs:///path の形式の URI で使用するバケット
+	Endpoint       string            `yaml:"endpoint"`        // GCS の JSON API のエンドポイント
+	Parallelism    int               `yaml:"parallelism"`     // --parallel の既定値
+	Aliases        map[string]string `yaml:"aliases"`         // このプロファイルでのみ使用する URI のエイリアス (共通のエイリアスより優先)
+}
+
+// aliasNamePattern は、エイリアスの名前の形式です。
+// Windows のドライブレター (C:) と区別するため、2文字以上とします。
+var aliasNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]+$`)
+
+// activeConfig は、選択されたプロファイルとエイリアスです。設定ファイルがない場合は空です。
+// コマンドツリーごとに AppFlags に保持し、applyConfig で設定します。
+type activeConfig struct {
+	name    string            // 選択されたプロファイルの名前 (選択されていない場合は空)
+	profile profile           // 選択されたプロファイル
+	aliases map[string]string // 共通のエイリアスとプロファイルのエイリアスを合わせたもの
+	now     time.Time         // URI のプレースホルダーの日付の展開に使用する時刻
+}
+
+// defaultConfigPath は、--config を省略した場合に読み込む設定ファイルのパス (~/.config/remoteio/config.yaml など) を返します。
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, appName, "config.yaml")
+}
+
+// loadConfig は path の設定ファイルを読み込みます。
+// explicit が false (既定のパス) の場合、ファイルが存在しなければ nil を返します。
+func loadConfig(path string, explicit bool) (*configFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, usageError(fmt.Errorf(tr("設定ファイルを開けません (%s)")+": %w", path, err))
+	}
+	defer f.Close()
+
+	var cfg configFile
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true) // キーの誤字を無視しない
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, usageError(fmt.Errorf(tr("設定ファイルの解析に失敗しました (%s)")+": %w", path, err))
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, usageError(fmt.Errorf("%s: %w", path, err))
+	}
+	return &cfg, nil
+}
+
+// validate は、エイリアスの名前と URI、プロファイルの値を検証します。
+func (c *configFile) validate() error {
+	if err := validateAliases(c.Aliases); err != nil {
+		return err
+	}
+	for name, p := range c.Profiles {
+		if p == nil {
+			return fmt.Errorf(tr("プロファイル %s が空です"), name)
+		}
+		if err := validateAliases(p.Aliases); err != nil {
+			return fmt.Errorf(tr("プロファイル %s")+": %w", name, err)
+		}
+		if p.Parallelism < 0 {
+			return fmt.Errorf(tr("プロファイル %s の parallelism には 0 以上の整数を指定してください: %d"), name, p.Parallelism)
+		}
+		if strings.ContainsAny(p.DefaultBucket, "/:") {
+			return fmt.Errorf(tr("プロファイル %s の default_bucket にはバケット名のみを指定してください: %s"), name, p.DefaultBucket)
+		}
+	}
+	return nil
+}
+
+// validateAliases は、エイリアスの名前と、エイリアスが指す URI の形式を検証します。
+func validateAliases(aliases map[string]string) error {
+	for name, target := range aliases {
+		if !aliasNamePattern.MatchString(name) {
+			return fmt.Errorf(tr("エイリアスの名前には英字で始まる2文字以上の英数字、_ と - を使用してください: %s"), name)
+		}
+		if remoteio.SchemeOf(target) == "" {
+			return fmt.Errorf(tr("エイリアス %s には gs://bucket/prefix などの URI を指定してください: %s"), name, target)
+		}
+	}
+	return nil
+}
+
+// applyConfig は、設定ファイルを読み込んでプロファイルを選択し、フラグで明示されていない値とエイリアスを cmd に適用します。
+// プロファイルは --profile、環境変数 REMOTEIO_PROFILE、設定ファイルの default_profile の順に選択します。
+// エイリアスと既定のバケットは、サブコマンドの実行前に、args とフラグの値のURIをここでまとめて解決します。
+func applyConfig(cmd *cobra.Command, args []string, appFlags *AppFlags) error {
+	path, explicit := clibase.Flags.ConfigFile, true
+	if path == "" {
+		path, explicit = defaultConfigPath(), false
+	}
+	var cfg *configFile
+	if path != "" {
+		var err error
+		if cfg, err = loadConfig(path, explicit); err != nil {
+			return err
+		}
+	}
+	if cfg == nil {
+		cfg = &configFile{}
+	}
+
+	name := appFlags.Profile
+	if name == "" {
+		name = os.Getenv(profileEnv)
+	}
+	if name == "" {
+		name = cfg.DefaultProfile
+	}
+	active := &appFlags.config
+	*active = activeConfig{aliases: maps.Clone(cfg.Aliases)}
+	if name != "" {
+		p, ok := cfg.Profiles[name]
+		if !ok {
+			return usageError(fmt.Errorf(tr("プロファイルが見つかりません: %s (定義されているプロファイル: %s)"), name, strings.Join(slices.Sorted(maps.Keys(cfg.Profiles)), ", ")))
+		}
+		active.name = name
+		active.profile = *p
+		if active.aliases == nil {
+			active.aliases = make(map[string]string)
+		}
+		maps.Copy(active.aliases, p.Aliases)
+		logger().Debug(tr("プロファイルを使用します"), slog.String("profile", name), slog.String("config", path))
+	}
+
+	// フラグで明示されていない値にプロファイルの値を適用する
+	p := active.profile
+	if p.BillingProject != "" && !cmd.Flags().Changed("billing-project") {
+		appFlags.BillingProject = p.BillingProject
+	}
+	if f := cmd.Flags().Lookup("parallel"); p.Parallelism > 0 && f != nil && f.Value.Type() == "int" && !f.Changed {
+		if err := f.Value.Set(strconv.Itoa(p.Parallelism)); err != nil {
+			return err
+		}
+	}
+	// 日付のプレースホルダーは、日付をまたいで実行しても同じ値になるよう、実行開始時の時刻で展開する
+	active.now = time.Now()
+	return active.resolveURIs(cmd, args)
+}
+
+// annotationURI は、値が URI のフラグに設定する pflag.Flag.Annotations のキーです。
+// resolveURIs は、このキーを設定したフラグの値だけを置き換えます。
+const annotationURI = "remoteio.uri"
+
+// markURIFlags は、cmd の names のフラグに、値が URI であることを示す annotationURI を設定します。
+func markURIFlags(cmd *cobra.Command, names ...string) {
+	for _, name := range names {
+		if err := cmd.Flags().SetAnnotation(name, annotationURI, []string{"true"}); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// resolveURIs は、args と、cmd の明示されたフラグのうち markURIFlags で URI を値とするフラグの値について、
+// プレースホルダー ({{date "2006/01/02"}} など)、エイリアス (prod:reports/x.csv) と既定のバケット (gs:///reports/x.csv) を
+// 使用した URI を、実際の URI に置き換えます。--metadata や --on-success などの URI ではない値は置き換えません。
+// cobra は PersistentPreRunE と RunE に同じ args のスライスを渡すため、要素を置き換えればサブコマンドに反映されます。
+func (c *activeConfig) resolveURIs(cmd *cobra.Command, args []string) error {
+	var err error
+	for i, arg := range args {
+		if args[i], err = c.resolveURI(arg); err != nil {
+			return err
+		}
+	}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if err != nil || f.Annotations[annotationURI] == nil {
+			return
+		}
+		switch v := f.Value.(type) {
+		case pflag.SliceValue:
+			if f.Value.Type() != "stringSlice" && f.Value.Type() != "stringArray" {
+				return
+			}
+			values := v.GetSlice()
+			changed := false
+			for i, value := range values {
+				var resolved string
+				if resolved, err = c.resolveURI(value); err != nil {
+					return
+				}
+				changed = changed || resolved != value
+				values[i] = resolved
+			}
+			if changed {
+				err = v.Replace(values)
+			}
+		default:
+			if f.Value.Type() != "string" {
+				return
+			}
+			var resolved string
+			if resolved, err = c.resolveURI(f.Value.String()); err == nil && resolved != f.Value.String() {
+				err = f.Value.Set(resolved)
+			}
+		}
+	})
+	return err
+}
+
+// resolveURI は、uri のプレースホルダーを展開し、エイリアスまたは既定のバケットを使用した URI を実際の URI に置き換えます。
+// それ以外の uri はそのまま返します。エイリアスの URI にプレースホルダーを含めることもできます。
+func (c *activeConfig) resolveURI(uri string) (string, error) {
+	uri, err := remoteio.ExpandURITemplate(uri, c.now)
+	if err != nil {
+		return "", err
+	}
+	if rest, ok := strings.CutPrefix(uri, "gs:///"); ok {
+		bucket := c.profile.DefaultBucket
+		if bucket == "" {
+			return "", usageError(fmt.Errorf(tr("gs:/// の形式の URI には、default_bucket を指定したプロファイルが必要です: %s"), uri))
+		}
+		return "gs://" + bucket + "/" + rest, nil
+	}
+	name, rest, ok := strings.Cut(uri, ":")
+	if !ok || strings.HasPrefix(rest, "//") {
+		return uri, nil
+	}
+	target, ok := c.aliases[name]
+	if !ok {
+		return uri, nil
+	}
+	if target, err = remoteio.ExpandURITemplate(target, c.now); err != nil {
+		return "", err
+	}
+	rest = strings.TrimPrefix(rest, "/")
+	if rest == "" {
+		return target, nil
+	}
+	return strings.TrimSuffix(target, "/") + "/" + rest, nil
+}
+
+// factoryOptions は、選択されたプロファイルの認証情報とエンドポイントを ClientFactory のオプションにします。
+func (c *activeConfig) factoryOptions() []factory.Option {
+	var opts []factory.Option
+	if p := c.profile; p.Credentials != "" {
+		opts = append(opts, factory.WithCredentialsFile(p.Credentials))
+	}
+	if p := c.profile; p.Endpoint != "" {
+		opts = append(opts, factory.WithEndpoint(p.Endpoint))
+	}
+	return opts
+}
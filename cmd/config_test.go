@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newActiveConfig は、aliases と defaultBucket のプロファイルを選択した activeConfig を返します。
+func newActiveConfig(aliases map[string]string, defaultBucket string) *activeConfig {
+	return &activeConfig{
+		profile: profile{DefaultBucket: defaultBucket},
+		aliases: aliases,
+		now:     time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestResolveURI(t *testing.T) {
+	c := newActiveConfig(map[string]string{
+		"prod":  "gs://acme-prod-reports/",
+		"daily": `gs://logs/{{date "2006/01/02"}}`,
+	}, "default-bucket")
+
+	tests := []struct {
+		name    string
+		uri     string
+		want    string
+		wantErr bool
+	}{
+		{name: "エイリアス", uri: "prod:reports/x.csv", want: "gs://acme-prod-reports/reports/x.csv"},
+		{name: "エイリアスのみ", uri: "prod:", want: "gs://acme-prod-reports/"},
+		{name: "エイリアスの先頭のスラッシュ", uri: "prod:/x.csv", want: "gs://acme-prod-reports/x.csv"},
+		{name: "プレースホルダーを含むエイリアス", uri: "daily:app.log", want: "gs://logs/2026/03/01/app.log"},
+		{name: "既定のバケット", uri: "gs:///reports/x.csv", want: "gs://default-bucket/reports/x.csv"},
+		{name: "プレースホルダー", uri: `gs://b/{{date "2006-01-02" "-24h"}}.csv`, want: "gs://b/2026-02-28.csv"},
+		{name: "スキームのある URI", uri: "gs://bucket/x.csv", want: "gs://bucket/x.csv"},
+		{name: "未定義のエイリアス", uri: "staging:x.csv", want: "staging:x.csv"},
+		{name: "ローカルファイル", uri: "./x.csv", want: "./x.csv"},
+		{name: "不正なプレースホルダー", uri: "gs://b/{{date", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.resolveURI(tt.uri)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveURI(%q) = %q, %v, wantErr %v", tt.uri, got, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveURI(%q) = %q, want %q", tt.uri, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveURIWithoutDefaultBucket(t *testing.T) {
+	_, err := newActiveConfig(nil, "").resolveURI("gs:///x.csv")
+	if ExitCode(err) != ExitUsage {
+		t.Fatalf("resolveURI() = %v, want usage error", err)
+	}
+}
+
+func TestResolveURIsOnlyReplacesURIFlags(t *testing.T) {
+	c := newActiveConfig(map[string]string{"prod": "gs://acme-prod"}, "")
+
+	var output, metadata, hook string
+	var outputs, tags []string
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().StringVar(&output, "output", "", "")
+	cmd.Flags().StringArrayVar(&outputs, "outputs", nil, "")
+	cmd.Flags().StringVar(&metadata, "metadata", "", "")
+	cmd.Flags().StringVar(&hook, "on-success", "", "")
+	cmd.Flags().StringSliceVar(&tags, "tags", nil, "")
+	markURIFlags(cmd, "output", "outputs")
+	if err := cmd.Flags().Parse([]string{
+		"--output", "prod:a.csv",
+		"--outputs", "prod:b.csv", "--outputs", `gs://b/{{date "2006"}}`,
+		"--metadata", `note={{date "2006"}}`,
+		"--on-success", "echo prod:a",
+		"--tags", "prod:x",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"prod:src.csv"}
+	if err := c.resolveURIs(cmd, args); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"gs://acme-prod/src.csv"}; !slices.Equal(args, want) {
+		t.Errorf("args = %q, want %q", args, want)
+	}
+	if want := "gs://acme-prod/a.csv"; output != want {
+		t.Errorf("--output = %q, want %q", output, want)
+	}
+	if want := []string{"gs://acme-prod/b.csv", "gs://b/2026"}; !slices.Equal(outputs, want) {
+		t.Errorf("--outputs = %q, want %q", outputs, want)
+	}
+	if want := `note={{date "2006"}}`; metadata != want {
+		t.Errorf("--metadata = %q, want %q", metadata, want)
+	}
+	if want := "echo prod:a"; hook != want {
+		t.Errorf("--on-success = %q, want %q", hook, want)
+	}
+	if want := []string{"prod:x"}; !slices.Equal(tags, want) {
+		t.Errorf("--tags = %q, want %q", tags, want)
+	}
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// streamTransform は、転送する内容に適用する変換 (暗号化や復号) です。
+type streamTransform func(ctx context.Context, r io.Reader) (io.Reader, error)
+
+// cryptTransform は、--encrypt / --decrypt と鍵の指定に応じた変換を返します。どちらも指定されていない場合は nil を返します。
+func cryptTransform(ctx context.Context, encrypt, decrypt bool, keyFile, kmsKey string) (streamTransform, error) {
+	if !encrypt && !decrypt {
+		if keyFile != "" || kmsKey != "" {
+			return nil, usageError(errors.New(tr("--encryption-key-file と --encryption-kms-key は、--encrypt または --decrypt と併せて指定してください")))
+		}
+		return nil, nil
+	}
+	kw, err := newKeyWrapper(ctx, keyFile, kmsKey)
+	if err != nil {
+		return nil, err
+	}
+	if encrypt {
+		return func(ctx context.Context, r io.Reader) (io.Reader, error) {
+			return remoteio.EncryptReader(ctx, r, kw)
+		}, nil
+	}
+	return func(ctx context.Context, r io.Reader) (io.Reader, error) {
+		return remoteio.DecryptReader(ctx, r, kw)
+	}, nil
+}
+
+// newKeyWrapper は、--encryption-key-file または --encryption-kms-key で指定された鍵の KeyWrapper を返します。
+func newKeyWrapper(ctx context.Context, keyFile, kmsKey string) (remoteio.KeyWrapper, error) {
+	switch {
+	case keyFile != "" && kmsKey != "":
+		return nil, usageError(errors.New(tr("--encryption-key-file と --encryption-kms-key は同時に指定できません")))
+	case keyFile != "":
+		key, err := readKeyFile(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		return remoteio.NewLocalKeyWrapper(key)
+	case kmsKey != "":
+		return remoteio.NewKMSKeyWrapper(ctx, kmsKey)
+	default:
+		return nil, usageError(errors.New(tr("--encrypt または --decrypt を指定する場合は、--encryption-key-file または --encryption-kms-key で鍵を指定してください")))
+	}
+}
+
+// readKeyFile は、32 バイトの鍵をファイルから読み込みます。ファイルの内容は 32 バイトのバイナリか、その Base64 表現です。
+func readKeyFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(tr("鍵ファイルの読み込みに失敗しました (%s)")+": %w", path, err)
+	}
+	if len(data) == 32 {
+		return data, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf(tr("鍵ファイルには 32 バイトの鍵 (バイナリまたは Base64) を保存してください: %s"), path)
+	}
+	return key, nil
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// annotationDryRun は、--dry-run に対応するコマンドに設定する cobra.Command.Annotations のキーです。
+const annotationDryRun = "remoteio.dry-run"
+
+// dryRunAnnotations は、--dry-run に対応するコマンドに設定する Annotations を返します。
+func dryRunAnnotations() map[string]string {
+	return map[string]string{annotationDryRun: "true"}
+}
+
+// validateDryRun は、--dry-run が対応していないコマンドに指定されていないことを検証します。
+// 対応していないコマンドで無視すると、確認のつもりで書き込み先を変更してしまうため、引数の誤りとします。
+func validateDryRun(cmd *cobra.Command) error {
+	if !appFlagsFrom(cmd.Context()).DryRun || cmd.Annotations[annotationDryRun] != "" {
+		return nil
+	}
+	return usageError(fmt.Errorf(tr("--dry-run は rcopy、sync、rrm、rmv でのみ指定できます: %s"), cmd.Name()))
+}
+
+// dryRunPlan は、--dry-run で転送・移動・削除されるファイルを、サイズとともに標準出力へ出力します。
+// --format json の場合は、ファイルごとに status が "dry-run" のレコードを出力します。
+type dryRunPlan struct {
+	out   io.Writer
+	json  bool // --format json
+	files int
+	bytes int64
+}
+
+// newDryRunPlan は、cmd の標準出力へ出力する dryRunPlan を作成します。
+func newDryRunPlan(cmd *cobra.Command) *dryRunPlan {
+	return &dryRunPlan{out: cmd.OutOrStdout(), json: jsonOutput(cmd)}
+}
+
+// transfer は、src が dst へコピーされることを出力します。size が不明な場合は -1 を指定します。
+func (p *dryRunPlan) transfer(src, dst string, size int64) {
+	p.add(resultRecord{Source: src, Destination: dst}, tr("コピー (dry-run): %s -> %s (%s)"), size)
+}
+
+// move は、src が dst へ移動されることを出力します。
+func (p *dryRunPlan) move(src, dst string, size int64) {
+	p.add(resultRecord{Source: src, Destination: dst}, tr("移動 (dry-run): %s -> %s (%s)"), size)
+}
+
+// remove は、uri が削除されることを出力します。
+func (p *dryRunPlan) remove(uri string, size int64) {
+	p.add(resultRecord{URI: uri}, tr("削除 (dry-run): %s (%s)"), size)
+}
+
+// skip は、src から dst への転送が status (statusIdentical または statusSkipped) の理由で行われないことを出力します。
+func (p *dryRunPlan) skip(src, dst, status string) {
+	if p.json {
+		writeJSONLine(p.out, resultRecord{Source: src, Destination: dst, Status: status})
+		return
+	}
+	fmt.Fprintln(p.out, trf("スキップ (dry-run): %s -> %s (%s)", src, displayDestination(dst), status))
+}
+
+// add は、1件分の対象を出力し、件数とバイト数に加えます。
+func (p *dryRunPlan) add(rec resultRecord, format string, size int64) {
+	p.files++
+	if size >= 0 {
+		p.bytes += size
+		rec.Bytes = &size
+	}
+	if p.json {
+		rec.Status = statusDryRun
+		writeJSONLine(p.out, rec)
+		return
+	}
+	sizeText := "?"
+	if size >= 0 {
+		sizeText = formatByteSize(size)
+	}
+	if rec.URI != "" {
+		fmt.Fprintf(p.out, format+"\n", rec.URI, sizeText)
+		return
+	}
+	fmt.Fprintf(p.out, format+"\n", rec.Source, displayDestination(rec.Destination), sizeText)
+}
+
+// summary は、対象の件数と合計サイズを出力します。--format json の場合は出力しません。
+func (p *dryRunPlan) summary() {
+	if p.json {
+		return
+	}
+	fmt.Fprintln(p.out, trf("%d 件のファイル (合計 %s) が対象です (dry-run のため変更していません)", p.files, formatByteSize(p.bytes)))
+}
+
+// displayDestination は、書き込み先が空 (標準出力) の場合に "-" を返します。
+func displayDestination(dst string) string {
+	if dst == "" {
+		return "-"
+	}
+	return dst
+}
+
+// statSize は、uri のサイズを返します。存在しない場合などはエラーを返し、サイズを取得できない InputReader と標準入力の場合は -1 を返します。
+func statSize(ctx context.Context, reader remoteio.InputReader, uri string) (int64, error) {
+	stater, ok := reader.(remoteio.Stater)
+	if !ok || uri == stdioPath {
+		return -1, nil
+	}
+	info, err := stater.Stat(ctx, uri)
+	if err != nil {
+		return 0, fmt.Errorf(tr("情報の取得に失敗しました (%s)")+": %w", uri, err)
+	}
+	return info.Size, nil
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shouni/go-remote-io/pkg/proxy"
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/shouni/go-remote-io/pkg/transfer"
+)
+
+// 終了コード。失敗の分類ごとにすべてのサブコマンドで同じ値を返すため、
+// ワークフローエンジンやシェルスクリプトは、エラーメッセージを解析せずに失敗の種類に応じて処理を分岐できます。
+// シグナルで中断された場合は、シェルの慣例に従い 128 + シグナル番号 (SIGINT は 130、SIGTERM は 143) を返します。
+const (
+	ExitOK                 = 0 // 成功
+	ExitError              = 1 // 分類できないエラー (ネットワークエラー、検証の失敗など)
+	ExitUsage              = 2 // 引数やフラグの誤り、無効なURI、プロキシ経由では使用できないオプション
+	ExitNotFound           = 3 // ファイル、オブジェクトまたはバケットが存在しない
+	ExitPermissionDenied   = 4 // 認証エラー、権限の不足
+	ExitPreconditionFailed = 5 // 書き込み先が前提条件 (--if-generation-match など) を満たさない
+	ExitPartial            = 6 // 一部のファイルの転送に失敗した (rcopy -r、複数のコピー元または -o の rcopy、sync、rbatch)
+)
+
+// ExitCode は、コマンドが返したエラーに対応する終了コードを返します。err が nil の場合は ExitOK を返します。
+// NewRootCmd で作成したコマンドを独自に実行する場合に、Execute と同じ終了コードを決定するために使用します。
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	// 一部の転送のみが失敗した場合は、個々の失敗の分類より優先する (中止した転送は成功に含めない)
+	var transferErr *transfer.Error
+	if errors.As(err, &transferErr) && len(transferErr.Failures)+transferErr.NotStarted < transferErr.Total {
+		return ExitPartial
+	}
+	var multiWriteErr *remoteio.MultiWriteError
+	if errors.As(err, &multiWriteErr) && len(multiWriteErr.Failures) < multiWriteErr.Total {
+		return ExitPartial
+	}
+	switch {
+	case errors.Is(err, remoteio.ErrInvalidURI), errors.Is(err, proxy.ErrUnsupportedOption):
+		return ExitUsage
+	case errors.Is(err, remoteio.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, remoteio.ErrPermissionDenied):
+		return ExitPermissionDenied
+	case errors.Is(err, remoteio.ErrPreconditionFailed), errors.Is(err, remoteio.ErrAlreadyExists):
+		return ExitPreconditionFailed
+	default:
+		return ExitError
+	}
+}
+
+// exitCause は、ExitCode が返した終了コード code の分類の説明を返します。
+// 終了コードを独自の意味で使用するコマンド (rexists) で、分類をメッセージに含めるために使用します。
+func exitCause(code int) string {
+	switch code {
+	case ExitUsage:
+		return tr("引数の誤り")
+	case ExitNotFound:
+		return tr("存在しない")
+	case ExitPermissionDenied:
+		return tr("権限不足")
+	case ExitPreconditionFailed:
+		return tr("前提条件を満たさない")
+	case ExitPartial:
+		return tr("一部の失敗")
+	default:
+		return tr("分類できないエラー")
+	}
+}
+
+// usageError は、引数やフラグの誤りを示す、ExitUsage で終了するエラーを返します。
+func usageError(err error) error {
+	return &exitError{code: ExitUsage, err: err}
+}
+
+// notFoundError は、コピーや削除の対象が見つからないことを示す、remoteio.ErrNotFound を含むエラーを返します。
+// 一覧が空の場合など、バックエンドのエラーを伴わずに対象がないと判断した場合に使用します。
+func notFoundError(format string, args ...any) error {
+	return &remoteio.Error{Kind: remoteio.ErrNotFound, Err: fmt.Errorf(format, args...)}
+}
+
+// withFallbackCode は、ExitCode で分類できない err を、ExitError の代わりに fallback で終了するエラーにします。
+// 1 を「差分がある」の結果に使用するコマンド (rdiff) で、失敗と区別するために使用します。
+func withFallbackCode(err error, fallback int) error {
+	var exitErr *exitError
+	if err == nil || errors.As(err, &exitErr) || ExitCode(err) != ExitError {
+		return err
+	}
+	return &exitError{code: fallback, err: err}
+}
+
+// classifyUsageErrors は、c 配下のコマンドの引数とフラグの誤りを ExitUsage で終了するエラーにします。
+// 必須のフラグと、同時に指定できないフラグの組み合わせは、cobra が PersistentPreRunE の後に検証するため、
+// ファクトリを初期化する前に引数と併せて検証します。
+func classifyUsageErrors(c *cobra.Command) {
+	// フラグの解析エラーの処理は、サブコマンドに引き継がれる
+	if !c.HasParent() {
+		c.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+			return usageError(err)
+		})
+	}
+	// Args が nil のサブコマンドを持つコマンドは、cobra が未知のサブコマンドを検出するため変更しない
+	if args := c.Args; args != nil || !c.HasSubCommands() {
+		c.Args = func(cmd *cobra.Command, a []string) error {
+			if args != nil {
+				if err := args(cmd, a); err != nil {
+					return usageOrExitError(err)
+				}
+			}
+			if err := cmd.ValidateRequiredFlags(); err != nil {
+				return usageError(err)
+			}
+			if err := cmd.ValidateFlagGroups(); err != nil {
+				return usageError(err)
+			}
+			return nil
+		}
+	}
+	for _, sub := range c.Commands() {
+		classifyUsageErrors(sub)
+	}
+}
+
+// usageOrExitError は、終了コードが指定されていない err を ExitUsage で終了するエラーにします。
+func usageOrExitError(err error) error {
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return err
+	}
+	return usageError(err)
+}
+
+// isUnknownCommand は、args が未知のサブコマンドを指定しているかどうかを返します。
+// cobra はこの誤りをコマンドの実行前に検出し、区別できないエラーとして返すため、同じ検索を行って判定します。
+func isUnknownCommand(rootCmd *cobra.Command, args []string) bool {
+	_, _, err := rootCmd.Find(args)
+	return err != nil
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// webhookTimeout は、--webhook の1回の送信のタイムアウトです。
+const webhookTimeout = 30 * time.Second
+
+// hookFlags は、転送ごとに実行するフック (--on-success、--on-failure、--webhook) のフラグを保持します。
+type hookFlags struct {
+	OnSuccess string // --on-success 転送が成功するたびにシェルで実行するコマンド
+	OnFailure string // --on-failure 転送が失敗するたびにシェルで実行するコマンド
+	Webhook   string // --webhook 転送ごとに結果を JSON で POST する URL
+}
+
+// addHookFlags は、フックのフラグを cmd に追加します。
+func addHookFlags(cmd *cobra.Command, flags *hookFlags) {
+	cmd.Flags().StringVar(&flags.OnSuccess, "on-success", "", "ファイルの転送が成功するたびにシェルで実行するコマンド (結果は REMOTEIO_SOURCE などの環境変数と、標準入力の JSON で渡す。失敗は警告を出力して集計の hook_failures に数え、終了コードには影響しない)")
+	cmd.Flags().StringVar(&flags.OnFailure, "on-failure", "", "ファイルの転送が失敗するたびにシェルで実行するコマンド (結果は REMOTEIO_SOURCE、REMOTEIO_ERROR などの環境変数と、標準入力の JSON で渡す。失敗は警告を出力して集計の hook_failures に数え、終了コードには影響しない)")
+	cmd.Flags().StringVar(&flags.Webhook, "webhook", "", "ファイルの転送が成功または失敗するたびに、結果を JSON で POST する URL (http:// または https://。送信の失敗は警告を出力して集計の hook_failures に数え、終了コードには影響しない)")
+}
+
+// hookPayload は、フックのコマンドの標準入力と --webhook へ送信する、1件の転送の結果です。
+type hookPayload struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"` // 転送を実行したサブコマンド (rcopy、sync、rbatch)
+	resultRecord
+}
+
+// hookSummary は、フックを指定した場合に、--format json で結果の最後に出力する集計のレコードです。
+type hookSummary struct {
+	Status       string `json:"status"`        // 常に statusSummary
+	HookFailures int64  `json:"hook_failures"` // 失敗したフックのコマンドと Webhook の送信の数
+}
+
+// hookRunner は、転送の結果ごとにフックを実行します。
+type hookRunner struct {
+	ctx     context.Context
+	command string
+	flags   hookFlags
+	errOut  io.Writer // フックのコマンドの出力先 (--format json の標準出力を乱さないよう、標準エラー出力)
+	client  *http.Client
+
+	failures atomic.Int64 // 失敗したフックのコマンドと Webhook の送信の数
+}
+
+// newHookRunner は、flags のフックが1つでも指定された場合に、cmd のコンテキストでフックを実行する hookRunner を作成します。
+// 指定されていない場合は nil を返します。
+func newHookRunner(cmd *cobra.Command, flags hookFlags) (*hookRunner, error) {
+	if flags.OnSuccess == "" && flags.OnFailure == "" && flags.Webhook == "" {
+		return nil, nil
+	}
+	if flags.Webhook != "" {
+		u, err := url.Parse(flags.Webhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, usageError(fmt.Errorf(tr("--webhook には http:// または https:// の URL を指定してください: %s"), flags.Webhook))
+		}
+	}
+	return &hookRunner{
+		ctx:     cmd.Context(),
+		command: cmd.Name(),
+		flags:   flags,
+		errOut:  cmd.ErrOrStderr(),
+		client:  &http.Client{Timeout: webhookTimeout},
+	}, nil
+}
+
+// fire は、rec が転送の成功 (コピーまたは上書き) か失敗の場合に、対応するフックを実行します。
+// スキップなどの転送しなかった結果と削除では実行しません。フックの失敗は警告を出力して数え、転送の結果には影響させません。
+func (h *hookRunner) fire(rec resultRecord) {
+	command := h.flags.OnSuccess
+	switch rec.Status {
+	case statusCopied, statusOverwritten:
+	case statusFailed:
+		command = h.flags.OnFailure
+	default:
+		return
+	}
+	payload, err := json.Marshal(hookPayload{Time: time.Now().UTC(), Command: h.command, resultRecord: rec})
+	if err != nil {
+		return
+	}
+	if command != "" {
+		if err := h.run(command, rec, payload); err != nil {
+			h.failures.Add(1)
+			logger().Warn(tr("フックのコマンドの実行に失敗しました"), slog.String("command", command), slog.String("destination", rec.Destination), slog.String("error", err.Error()))
+		}
+	}
+	if h.flags.Webhook != "" {
+		if err := h.post(payload); err != nil {
+			h.failures.Add(1)
+			logger().Warn(tr("Webhook の送信に失敗しました"), slog.String("url", h.flags.Webhook), slog.String("destination", rec.Destination), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// run は、command をシェルで実行し、結果を環境変数と標準入力の JSON で渡します。
+func (h *hookRunner) run(command string, rec resultRecord, payload []byte) error {
+	c := shellCommand(h.ctx, command)
+	c.Stdin = bytes.NewReader(payload)
+	c.Stdout, c.Stderr = h.errOut, h.errOut
+	c.Env = append(os.Environ(),
+		"REMOTEIO_COMMAND="+h.command,
+		"REMOTEIO_STATUS="+rec.Status,
+		"REMOTEIO_SOURCE="+rec.Source,
+		"REMOTEIO_DESTINATION="+rec.Destination,
+		"REMOTEIO_ERROR="+rec.Error,
+	)
+	if rec.Bytes != nil {
+		c.Env = append(c.Env, "REMOTEIO_BYTES="+strconv.FormatInt(*rec.Bytes, 10))
+	}
+	if rec.CRC32C != nil {
+		// rstat と同じく、GCS の属性と同じ base64 (ビッグエンディアン) で渡す
+		c.Env = append(c.Env, "REMOTEIO_CRC32C="+base64.StdEncoding.EncodeToString(binary.BigEndian.AppendUint32(nil, *rec.CRC32C)))
+	}
+	return c.Run()
+}
+
+// post は、payload を --webhook の URL へ POST します。2xx 以外の応答はエラーにします。
+func (h *hookRunner) post(payload []byte) error {
+	req, err := http.NewRequestWithContext(h.ctx, http.MethodPost, h.flags.Webhook, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s", resp.Status)
+	}
+	return nil
+}
+
+// withHooks は、フックが指定された場合に、w の結果ごとにフックも実行する resultWriter を返します。
+// フックが指定されていない場合は w をそのまま返します。
+func (w *resultWriter) withHooks(cmd *cobra.Command, reader remoteio.InputReader, flags hookFlags) (*resultWriter, error) {
+	hooks, err := newHookRunner(cmd, flags)
+	if err != nil || hooks == nil {
+		return w, err
+	}
+	if w == nil {
+		w = &resultWriter{}
+	}
+	if w.stater == nil {
+		// フックには、書き込み先のサイズと CRC32C を常に渡す
+		w.stater, _ = reader.(remoteio.Stater)
+	}
+	w.hooks = hooks
+	return w, nil
+}
+
+// summarizeHooks は、フックが指定された場合に、失敗したフックの数を出力します。
+// --format json では集計のレコードを標準出力へ出力し、失敗がある場合は警告をログに出力します。
+// すべての結果を出力した後に呼び出してください。
+func (w *resultWriter) summarizeHooks() {
+	if w == nil || w.hooks == nil {
+		return
+	}
+	failures := w.hooks.failures.Load()
+	if w.out != nil {
+		w.mu.Lock()
+		writeJSONLine(w.out, hookSummary{Status: statusSummary, HookFailures: failures})
+		w.mu.Unlock()
+	}
+	if failures > 0 {
+		logger().Warn(tr("一部のフックの実行に失敗しました"), slog.Int64("hook_failures", failures))
+	}
+}
+
+// hookFailures は、失敗したフックの数を返します。フックが指定されていない場合は 0 です。
+func (w *resultWriter) hookFailures() int64 {
+	if w == nil || w.hooks == nil {
+		return 0
+	}
+	return w.hooks.failures.Load()
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHookFailuresAreSummarized(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ok.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer broken.Close()
+
+	tests := []struct {
+		name         string
+		flags        hookFlags
+		records      []resultRecord
+		wantFailures int64
+	}{
+		{
+			name:    "成功したフック",
+			flags:   hookFlags{OnSuccess: "true", Webhook: ok.URL},
+			records: []resultRecord{{Source: "a", Destination: "b", Status: statusCopied}},
+		},
+		{
+			name:         "失敗したコマンド",
+			flags:        hookFlags{OnSuccess: "exit 1", OnFailure: "exit 1"},
+			records:      []resultRecord{{Source: "a", Destination: "b", Status: statusCopied}, {Source: "c", Destination: "d", Status: statusFailed, Error: "x"}},
+			wantFailures: 2,
+		},
+		{
+			name:         "失敗した Webhook",
+			flags:        hookFlags{Webhook: broken.URL},
+			records:      []resultRecord{{Source: "a", Destination: "b", Status: statusCopied}, {Source: "c", Destination: "d", Status: statusOverwritten}},
+			wantFailures: 2,
+		},
+		{
+			name:    "フックを実行しない結果",
+			flags:   hookFlags{OnSuccess: "exit 1", Webhook: broken.URL},
+			records: []resultRecord{{Source: "a", Destination: "b", Status: statusSkipped}, {URI: "c", Status: statusDeleted}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			w := &resultWriter{
+				out:   &out,
+				hooks: &hookRunner{ctx: context.Background(), command: "rcopy", flags: tt.flags, errOut: io.Discard, client: http.DefaultClient},
+			}
+			for _, rec := range tt.records {
+				w.write(rec)
+			}
+			w.summarizeHooks()
+
+			if got := w.hookFailures(); got != tt.wantFailures {
+				t.Errorf("hookFailures() = %d, want %d", got, tt.wantFailures)
+			}
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			var summary hookSummary
+			if err := json.Unmarshal([]byte(lines[len(lines)-1]), &summary); err != nil {
+				t.Fatal(err)
+			}
+			if summary.Status != statusSummary || summary.HookFailures != tt.wantFailures {
+				t.Errorf("集計のレコード = %+v, want hook_failures %d", summary, tt.wantFailures)
+			}
+		})
+	}
+}
+
+func TestSummarizeHooksWithoutHooks(t *testing.T) {
+	var out bytes.Buffer
+	w := &resultWriter{out: &out}
+	w.summarizeHooks()
+	if out.Len() != 0 {
+		t.Errorf("フックを指定しない場合に集計が出力されました: %q", out.String())
+	}
+	var nilWriter *resultWriter
+	nilWriter.summarizeHooks()
+	if got := nilWriter.hookFailures(); got != 0 {
+		t.Errorf("hookFailures() = %d, want 0", got)
+	}
+}
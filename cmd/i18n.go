@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// CLI出力でサポートする言語
+const (
+	langJA = "ja" // 日本語 (既定)
+	langEN = "en" // 英語
+)
+
+// currentLang は、ヘルプ・ログ・エラーメッセージに使用する現在の言語です。
+// ライブラリのメッセージの翻訳 (remoteio.SetMessageTranslator) と同じく、プロセス全体で共有します。
+var currentLang = struct {
+	sync.RWMutex
+	lang string
+}{lang: langJA}
+
+// detectLang は、環境変数 (LC_ALL, LC_MESSAGES, LANG の順) から既定の言語を決定します。
+// いずれも未設定、または C/POSIX ロケールの場合は日本語を返します。
+func detectLang() string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		locale := os.Getenv(key)
+		if locale == "" {
+			continue
+		}
+		if locale == "C" || locale == "POSIX" || strings.HasPrefix(strings.ToLower(locale), langJA) {
+			return langJA
+		}
+		return langEN
+	}
+	return langJA
+}
+
+// setLang は、CLI出力に使用する言語を切り替えます。
+// ライブラリ (remoteio、transfer、proxy など) のエラーとログのメッセージも、同じカタログで翻訳します。
+func setLang(lang string) error {
+	switch lang {
+	case langJA, langEN:
+		currentLang.Lock()
+		currentLang.lang = lang
+		currentLang.Unlock()
+		remoteio.SetMessageTranslator(tr)
+		return nil
+	default:
+		return fmt.Errorf("unsupported language %q (ja|en): サポートされていない言語です", lang)
+	}
+}
+
+// tr は、日本語のメッセージIDを現在の言語に翻訳します。
+// カタログに翻訳が存在しない場合は、メッセージIDをそのまま返します。
+func tr(msgid string) string {
+	currentLang.RLock()
+	lang := currentLang.lang
+	currentLang.RUnlock()
+	if lang == langEN {
+		if s, ok := catalogEN[msgid]; ok {
+			return s
+		}
+	}
+	return msgid
+}
+
+// trf は、翻訳したメッセージをフォーマット文字列として fmt.Sprintf を適用します。
+func trf(format string, args ...any) string {
+	return fmt.Sprintf(tr(format), args...)
+}
+
+// originalTexts は、翻訳前のヘルプテキスト (メッセージID) を保持します。
+// 言語を切り替えた際も、常に原文から翻訳し直すために使用します。
+// 複数のコマンドツリーから並行して翻訳される場合があるため、mu で保護します。
+var originalTexts = struct {
+	mu       sync.Mutex
+	commands map[*cobra.Command][2]string
+	flags    map[*pflag.Flag]string
+}{commands: map[*cobra.Command][2]string{}, flags: map[*pflag.Flag]string{}}
+
+// localizeCommandTree は、コマンドツリー全体の Short/Long とフラグの説明を現在の言語に翻訳します。
+func localizeCommandTree(c *cobra.Command) {
+	originalTexts.mu.Lock()
+	defer originalTexts.mu.Unlock()
+	localizeCommand(c)
+}
+
+// localizeCommand は、c とそのサブコマンドを翻訳します。originalTexts.mu を保持して呼び出します。
+func localizeCommand(c *cobra.Command) {
+	texts, ok := originalTexts.commands[c]
+	if !ok {
+		texts = [2]string{c.Short, c.Long}
+		originalTexts.commands[c] = texts
+	}
+	c.Short = tr(texts[0])
+	c.Long = tr(texts[1])
+
+	localizeFlag := func(f *pflag.Flag) {
+		usage, ok := originalTexts.flags[f]
+		if !ok {
+			usage = f.Usage
+			originalTexts.flags[f] = usage
+		}
+		f.Usage = tr(usage)
+	}
+	c.Flags().VisitAll(localizeFlag)
+	c.PersistentFlags().VisitAll(localizeFlag)
+
+	for _, sub := range c.Commands() {
+		localizeCommand(sub)
+	}
+}
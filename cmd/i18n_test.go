@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// formatVerb は、フォーマット文字列の動詞 (引数の番号 %[n] を含む) に、argIndex はその引数の番号に一致します。
+var (
+	formatVerb = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*\d*(\.\d+)?[a-zA-Z%]`)
+	argIndex   = regexp.MustCompile(`\[\d+\]`)
+)
+
+// formatVerbs は、format の動詞を引数の番号を除いて並べ替えた一覧を返します。
+func formatVerbs(format string) []string {
+	var verbs []string
+	for _, v := range formatVerb.FindAllString(format, -1) {
+		verbs = append(verbs, argIndex.ReplaceAllString(v, ""))
+	}
+	slices.Sort(verbs)
+	return verbs
+}
+
+func TestCatalogENFormatVerbs(t *testing.T) {
+	for msgid, en := range catalogEN {
+		if got, want := formatVerbs(en), formatVerbs(msgid); !slices.Equal(got, want) {
+			t.Errorf("%q の英語訳の動詞 %v が原文の %v と一致しません", msgid, got, want)
+		}
+	}
+}
+
+// isASCII は、s が ASCII の文字だけで構成されているかどうかを返します (翻訳の必要がない英語のテキスト)。
+func isASCII(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool { return r > 0x7f }) < 0
+}
+
+func TestCatalogENCoversCommandTree(t *testing.T) {
+	rootCmd, _ := newRootCmd(nil)
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		for _, text := range []string{c.Short, c.Long} {
+			if text != "" && !isASCII(text) {
+				if _, ok := catalogEN[text]; !ok {
+					t.Errorf("%s のヘルプテキストの英語訳がありません: %q", c.CommandPath(), text)
+				}
+			}
+		}
+		check := func(f *pflag.Flag) {
+			if !isASCII(f.Usage) {
+				if _, ok := catalogEN[f.Usage]; !ok {
+					t.Errorf("%s --%s の説明の英語訳がありません: %q", c.CommandPath(), f.Name, f.Usage)
+				}
+			}
+		}
+		c.Flags().VisitAll(check)
+		c.PersistentFlags().VisitAll(check)
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(rootCmd)
+}
+
+func TestSetLangTranslatesLibraryMessages(t *testing.T) {
+	t.Cleanup(func() { setLang(langJA) })
+
+	tests := []struct {
+		lang string
+		want string
+	}{
+		{lang: langEN, want: "remoteio: not found"},
+		{lang: langJA, want: "remoteio: 見つかりません"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			if err := setLang(tt.lang); err != nil {
+				t.Fatal(err)
+			}
+			if got := remoteio.ErrNotFound.Error(); got != tt.want {
+				t.Errorf("ErrNotFound.Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+)
+
+// --log-format の値 (ログの出力形式)
+const (
+	logFormatText = "text" // key=value 形式 (既定)
+	logFormatJSON = "json" // 1件ごとに1行の JSON
+)
+
+// cmdLogger は、--log-format と --quiet に従って構成されたロガーです。初期化前は nil です。
+// 組み込み先のアプリケーションのログに影響しないよう、slog.SetDefault は呼び出しません。
+// プロセス全体で共有し、最後に実行を開始したコマンドツリーの設定に従います。
+var cmdLogger atomic.Pointer[slog.Logger]
+
+// logger は、コマンドのログの出力に使用するロガーを返します。初期化前は slog.Default() を返します。
+func logger() *slog.Logger {
+	if l := cmdLogger.Load(); l != nil {
+		return l
+	}
+	return slog.Default()
+}
+
+// initLogger は、--log-format と --quiet に従って、標準エラー出力へ出力するロガーを構成します。
+// --quiet の場合は、警告とエラーのみを出力します。
+func initLogger(appFlags *AppFlags) error {
+	opts := &slog.HandlerOptions{Level: slog.LevelInfo}
+	if appFlags.Quiet {
+		opts.Level = slog.LevelWarn
+	}
+	var handler slog.Handler
+	switch appFlags.LogFormat {
+	case logFormatText:
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	case logFormatJSON:
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	default:
+		return usageError(fmt.Errorf(tr("--log-format には text または json を指定してください: %s"), appFlags.LogFormat))
+	}
+	cmdLogger.Store(slog.New(handler))
+	return nil
+}
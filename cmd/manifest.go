@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/shouni/go-remote-io/pkg/factory"
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// manifestContentType は、GCS に作成するマニフェストの Content-Type です。
+const manifestContentType = "application/x-ndjson"
+
+// manifestRecord は、--manifest のファイルへ追記する1件分のレコードです。--format json のレコードに記録した日時を加えたものです。
+type manifestRecord struct {
+	Time time.Time `json:"time"`
+	resultRecord
+}
+
+// manifestWriter は、--manifest で指定されたローカルファイルまたは GCS オブジェクトへ、転送ごとのレコードを追記します。
+// ローカルファイルへは1件ごとに追記し、GCS オブジェクトへはレコードをメモリに蓄積して Close でまとめて追記します。
+type manifestWriter struct {
+	uri      string
+	file     *os.File             // ローカルファイルの場合 (O_APPEND で開く)
+	appender remoteio.GCSAppender // GCS の場合
+	buf      bytes.Buffer         // GCS の場合に、Close で追記するレコード
+}
+
+// openManifest は、uri のマニフェストを追記用に開きます。uri にはローカルファイルまたは GCS URI (gs://) を指定できます。
+func openManifest(f factory.Factory, uri string) (*manifestWriter, error) {
+	switch remoteio.SchemeOf(uri) {
+	case "":
+		if err := os.MkdirAll(filepath.Dir(uri), 0755); err != nil {
+			return nil, fmt.Errorf(tr("マニフェスト(%s)のオープンに失敗しました")+": %w", uri, err)
+		}
+		file, err := os.OpenFile(uri, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf(tr("マニフェスト(%s)のオープンに失敗しました")+": %w", uri, err)
+		}
+		return &manifestWriter{uri: uri, file: file}, nil
+	case "gs":
+		writer, err := f.NewOutputWriter()
+		if err != nil {
+			return nil, fmt.Errorf(tr("OutputWriterの作成に失敗しました")+": %w", err)
+		}
+		appender, ok := writer.(remoteio.GCSAppender)
+		if !ok {
+			return nil, errors.New(tr("OutputWriterが追記をサポートしていません"))
+		}
+		return &manifestWriter{uri: uri, appender: appender}, nil
+	default:
+		return nil, usageError(fmt.Errorf(tr("--manifest にはローカルファイルまたは GCS URI (gs://) を指定してください: %s"), uri))
+	}
+}
+
+// Write は、1件分のレコード p を追記します。
+func (m *manifestWriter) Write(p []byte) (int, error) {
+	if m.file != nil {
+		return m.file.Write(p)
+	}
+	return m.buf.Write(p)
+}
+
+// Close は、ローカルファイルを閉じるか、蓄積したレコードを GCS オブジェクトの末尾に追記します。
+// 中断された場合もそれまでの記録を残すため、ctx のキャンセルは引き継ぎません。
+func (m *manifestWriter) Close(ctx context.Context) error {
+	if m.file != nil {
+		if err := m.file.Close(); err != nil {
+			return fmt.Errorf(tr("マニフェスト(%s)への書き込みに失敗しました")+": %w", m.uri, err)
+		}
+		return nil
+	}
+	if m.buf.Len() == 0 {
+		return nil
+	}
+	bucketName, objectPath, err := remoteio.ParseGCSURI(m.uri)
+	if err != nil {
+		return err
+	}
+	if err := m.appender.AppendToGCS(context.WithoutCancel(ctx), bucketName, objectPath, &m.buf, manifestContentType); err != nil {
+		return fmt.Errorf(tr("マニフェスト(%s)への書き込みに失敗しました")+": %w", m.uri, err)
+	}
+	return nil
+}
+
+// withManifest は、--manifest が指定された場合に、w の結果を uri のマニフェストにも追記する resultWriter を返します。
+// uri が空の場合は w をそのまま返します。コマンドの終了時に closeManifest を呼び出す必要があります。
+func (w *resultWriter) withManifest(f factory.Factory, reader remoteio.InputReader, uri string) (*resultWriter, error) {
+	if uri == "" {
+		return w, nil
+	}
+	manifest, err := openManifest(f, uri)
+	if err != nil {
+		return nil, err
+	}
+	if w == nil {
+		w = &resultWriter{}
+	}
+	if w.stater == nil {
+		// マニフェストには、書き込み先のサイズと CRC32C を常に記録する
+		w.stater, _ = reader.(remoteio.Stater)
+	}
+	w.manifest = manifest
+	return w, nil
+}
+
+// closeManifest は、マニフェストを閉じ、失敗した場合はそのエラーを *errp に加えます。マニフェストがない場合は何もしません。
+func (w *resultWriter) closeManifest(ctx context.Context, errp *error) {
+	if w == nil || w.manifest == nil {
+		return
+	}
+	if err := w.manifest.Close(ctx); err != nil {
+		*errp = errors.Join(*errp, err)
+	}
+}
@@ -0,0 +1,962 @@
+package cmd
+
+// catalogEN は、日本語のメッセージIDから英語への翻訳を保持するメッセージカタログです。
+// CLIに新しいメッセージを追加する場合は、ここに英語訳も追加してください。
+var catalogEN = map[string]string{
+	// --- ヘルプテキスト ---
+	"リモートI/O操作のためのCLIツール。": "A CLI tool for remote I/O operations.",
+	`ローカルファイルと、GCS・S3・Azure・SFTPなどのリモートURIをサポートする、リモートI/O操作のためのCLIツールです。
+
+終了コード: 0 成功、1 分類できないエラー、2 引数やフラグの誤り、3 存在しない、4 権限不足、
+5 前提条件を満たさない、6 一部の転送のみ失敗、130 / 143 シグナルによる中断`: `A CLI tool for remote I/O operations supporting local files and remote URIs such as GCS, S3, Azure, and SFTP.
+
+Exit codes: 0 success, 1 unclassified error, 2 bad arguments or flags, 3 not found, 4 permission denied,
+5 precondition failed, 6 partial transfer failure, 130 / 143 interrupted by a signal`,
+	"GCSリクエストのタイムアウト時間（秒）":                                               "Timeout for GCS requests (seconds)",
+	"CLI出力の言語 (ja|en)。省略時は LC_ALL などの環境変数から決定します":                        "Language of CLI output (ja|en). Defaults to the locale from LC_ALL and related environment variables",
+	"SFTPの認証に使用する秘密鍵ファイル (パスフレーズは環境変数 REMOTEIO_SFTP_KEY_PASSPHRASE で指定)": "Private key file for SFTP authentication (set the passphrase via REMOTEIO_SFTP_KEY_PASSPHRASE)",
+	"SFTPのホスト鍵検証に使用する known_hosts ファイル (省略時は ~/.ssh/known_hosts)":        "known_hosts file used to verify SFTP host keys (defaults to ~/.ssh/known_hosts)",
+	"SFTPのホスト鍵を検証しない (テスト環境専用)":                                          "Do not verify SFTP host keys (test environments only)",
+	"リモート/ローカルパス間で内容を読み込み、指定された出力先へ転送します。":                               "Read content from a remote/local path and transfer it to the given destination.",
+	`指定されたパス (ローカルファイル、GCS URI、S3 URI、Azure URI、または SFTP URI) から io.ReadCloser を開きます。
+読み込んだ内容は、最後の引数で指定したコピー先 (rcopy <source> <destination>) のローカルファイル、または GCS URI / S3 URI / Azure URI / SFTP URIで指定されたリモートパスへ転送されます。
+コピー元だけを指定した場合と、コピー先に - を指定した場合は標準出力へ書き出します。
+コピー元に - を指定すると標準入力から読み込み、長さが不明なストリームのまま書き込みます。
+-r を指定すると、ディレクトリまたはプレフィックス配下のすべてのファイルを、相対パスを保ったままコピー先の配下へコピーします。
+複数のコピー元を指定すると、コピー先に指定したディレクトリ (末尾が "/" の URI または既存のローカルディレクトリ) の配下へ、それぞれ同じファイル名でコピーします。
+複数のファイルは --parallel で指定した数まで同時に転送します。失敗した転送は、1つのファイルのコピーでも --retries に従って再試行します。
+-o でコピー先を指定する従来の形式は非推奨です (-o を指定した場合は、コピー元を1つだけ指定します)。
+-o を複数指定すると、コピー元を1回だけ読み込み、すべての出力先へ同時に書き出します (失敗した出力先があっても残りへの書き込みを続けます)。
+--slice-size を指定すると、リモートのファイルを指定したサイズの範囲に分割し、--parallel で指定した数まで並行してダウンロードします。
+ローカルファイルから GCS へのコピーでは、範囲ごとに一時オブジェクトとして並行してアップロードし、Compose API で連結します。
+--resumable を指定すると、アップロード済みの範囲を記録し、中断された場合は同じコマンドの再実行で続きから再開します。
+--continue を指定すると、途中までダウンロードされたローカルファイルの続きからダウンロードし、完了後に CRC32C を検証します。
+--split-size を指定すると、書き込む内容を指定したサイズごとに、コピー先の名前に連番を付けたパート (.part0001、.part0002、...) に分割して書き込みます (rcat --join で連結できます)。`: `Opens an io.ReadCloser from the given path (a local file, a GCS URI, an S3 URI, an Azure URI, or an SFTP URI).
+The content is transferred to the destination given as the last argument (rcopy <source> <destination>): a local file, or a remote path given as a GCS, S3, Azure, or SFTP URI.
+With only a source, or with - as the destination, the content is written to stdout.
+With - as the source, standard input is read and written as a stream of unknown length.
+With -r, every file under the directory or prefix is copied under the destination, preserving relative paths.
+With multiple sources, each source is copied under the directory given as the destination (a URI ending in "/" or an existing local directory), keeping its file name.
+Up to --parallel files are transferred concurrently. Failed transfers, including a single-file copy, are retried according to --retries.
+The older form that gives the destination with -o is deprecated (with -o, give exactly one source).
+With -o repeated, the source is read once and written to every output simultaneously (a failing output does not stop the others).
+With --slice-size, a remote file is split into ranges of the given size and up to --parallel ranges are downloaded concurrently.
+When copying a local file to GCS, the ranges are uploaded concurrently as temporary objects and joined with the Compose API.
+With --resumable, uploaded ranges are recorded so that an interrupted upload continues where it stopped when the same command is run again.
+With --continue, a partially downloaded local file is resumed from where it stopped and its CRC32C is verified on completion.
+With --split-size, the written content is split every given size into parts named after the destination with sequence numbers (.part0001, .part0002, ...) (they can be joined with rcat --join).`,
+	"非推奨: コピー先は最後の引数で指定してください。読み込んだ内容を書き出すファイル名（- の場合は標準出力）。複数指定すると、コピー元を1回だけ読み込み、すべての出力先へ同時に書き出し (-o を指定した場合は、コピー元を1つだけ指定します)": "Deprecated: give the destination as the last argument. Output file name (standard output for -). When repeated, the source is read once and written to every output simultaneously (with -o, give exactly one source)",
+	"進捗の出力形式 (bar: プログレスバーを表示、json: NDJSON形式の進捗レコードを出力)。値を省略した場合は bar":                                                          "progress output format (bar: show a progress bar, json: emit NDJSON progress records); bare --progress means bar",
+	"進捗の出力先ファイルまたは名前付きパイプ（省略時は標準エラー出力）":                                                                                         "File or named pipe to write progress to (stderr if omitted)",
+	"進捗の出力間隔 (省略時は bar: 200ms、json: 1s)":                                 "progress output interval (default bar: 200ms, json: 1s)",
+	"転送を許可する最大サイズ (例: 5GiB)。超過した場合は転送を中止します":                             "maximum size allowed to transfer (e.g. 5GiB); the transfer is aborted when it is exceeded",
+	"転送を許可するContent-Type (内容から判定。例: image/, application/pdf)。複数指定可":      "Content-Type allowed to transfer (detected from the content, e.g. image/, application/pdf); may be repeated",
+	"書き込む内容をスキャンする clamd のアドレス (host:port または unix:/path/to/clamd.sock)": "address of the clamd used to scan the written content (host:port or unix:/path/to/clamd.sock)",
+	"合成ペイロードでアップロード/ダウンロードのスループットとレイテンシを計測します。":                          "Measure upload/download throughput and latency with synthetic payloads.",
+	`指定されたプレフィックス (GCS URI またはローカルディレクトリ) に合成ペイロードを書き込み、読み戻して、
+サイズと並列数の組み合わせごとにスループットとレイテンシのパーセンタイルを表示します。
+リージョン、マシンタイプ、チャンクサイズ設定などの比較に使用できます。計測に使用したオブジェクトは終了時に削除されます。`: `Writes synthetic payloads to the given prefix (a GCS URI or a local directory), reads them back,
+and reports throughput and latency percentiles for each combination of size and parallelism.
+Useful for comparing regions, machine types and chunk-size settings. Objects used for the benchmark are deleted on exit.`,
+	"計測するペイロードサイズ (カンマ区切り)":                 "Payload sizes to measure (comma separated)",
+	"計測する並列数 (カンマ区切り)":                      "Parallelism levels to measure (comma separated)",
+	"各組み合わせの繰り返し回数":                         "Number of rounds for each combination",
+	"計測に使用したオブジェクトを削除せずに残す":                 "Keep the objects used for the benchmark instead of deleting them",
+	"ディレクトリ/プレフィックス配下のファイルを再帰的にコピー先の配下へコピー": "Recursively copy the files under a directory/prefix to the destination",
+	"コピー先のディレクトリ/プレフィックスをコピー元と同じ内容にそろえます。":  "Make a destination directory/prefix mirror the source.",
+	`コピー元 (ローカルディレクトリまたは GCS URI のプレフィックス) 配下のすべてのファイルを、相対パスを保ったままコピー先へコピーします。
+サイズと CRC32C チェックサムが一致するファイルは変更なしとみなしてスキップします。
+--delete を指定すると、コピー元に存在しないファイルをコピー先から削除します。コピー元が空の場合は、コピー先のすべてのファイルを削除しないよう、--allow-empty-source を指定しない限り失敗します。終了時にコピー・スキップ・削除の件数を表示します (--format json の場合は、ファイルごとの結果を出力します)。
+ファイルは --parallel で指定した数まで同時にコピーし、失敗したファイルは再試行します。
+--dry-run を指定すると、コピー・削除されるファイルとサイズを表示し、コピー先を変更せずに終了します。`: `Copies every file under the source (a local directory or a GCS URI prefix) to the destination, preserving relative paths.
+Files whose size and CRC32C checksum match are considered unchanged and skipped.
+With --delete, files that do not exist in the source are deleted from the destination. If the source is empty, the command fails instead of deleting every destination file, unless --allow-empty-source is given. Counts of copied, skipped and deleted files are printed on exit (with --format json, a result is printed for each file instead).
+Up to --parallel files are copied concurrently, and failed files are retried.
+With --dry-run, the files that would be copied or deleted are printed with their sizes and the destination is left untouched.`,
+	"コピー元に存在しないファイルをコピー先から削除":        "Delete destination files that do not exist in the source",
+	"コピー元が空の場合もコピー先のファイルを削除":         "Let --delete remove destination files even when the source is empty",
+	"ディレクトリ/プレフィックス配下のファイルを一覧表示します。": "List the files under a directory/prefix.",
+	`指定されたローカルディレクトリ、または GCS URI などのプレフィックス直下のファイルとサブディレクトリを一覧表示します。
+-r を指定するとサブディレクトリ配下のすべてのファイルを、-l を指定するとサイズ、更新日時、ストレージクラスも表示します。
+--json を指定すると、1件ごとに1行の JSON (NDJSON) で出力します。
+gs:// を指定すると、--project (省略時は環境変数 GOOGLE_CLOUD_PROJECT または CLOUDSDK_CORE_PROJECT) のプロジェクトのバケットを一覧表示します (-l では作成日時、ロケーション、ストレージクラス、均一なバケットレベルのアクセスの有無も表示します)。`: `Lists the files and subdirectories directly under the given local directory or prefix such as a GCS URI.
+With -r, every file under subdirectories is listed; with -l, size, update time and storage class are shown as well.
+With --json, each entry is written as one line of JSON (NDJSON).
+With gs://, lists the buckets in the --project project (defaults to the GOOGLE_CLOUD_PROJECT or CLOUDSDK_CORE_PROJECT environment variable); -l also shows creation time, location, storage class and whether uniform bucket-level access is enabled.`,
+	"サイズ、更新日時、ストレージクラスも表示": "Also show size, update time and storage class",
+	"NDJSON形式で出力": "Output in NDJSON format",
+	"サブディレクトリ配下のすべてのファイルを一覧": "List every file under subdirectories",
+	"ファイルまたはオブジェクトを削除します。":   "Delete files or objects.",
+	`指定されたローカルファイル、または GCS URI などで指定されたオブジェクトを削除します。
+-r を指定すると、ディレクトリまたはプレフィックス配下のすべてのファイルを削除します。
+パスには *, ?, [...] のワイルドカードを使用できます (* は "/" に一致しません)。--dry-run を指定すると、削除せずに対象とサイズのみを表示します。`: `Deletes the given local files or objects specified by GCS URIs and the like.
+With -r, every file under the directory or prefix is deleted.
+Paths may contain the wildcards *, ? and [...] (* does not match "/"). With --dry-run, the targets and their sizes are only printed and nothing is deleted.`,
+	"ディレクトリ/プレフィックス配下のすべてのファイルを削除": "Delete every file under the directory/prefix",
+	"ファイルまたはオブジェクトを移動 (名前変更) します。": "Move (rename) a file or object.",
+	`指定されたローカルファイル、または GCS URI などで指定されたオブジェクトを移動先へ移動します。
+GCS 間と S3 間はサーバー側のコピーと削除で (データをダウンロードせずに)、ローカルファイルは名前変更で移動します。
+異なるバックエンド間の移動は、コピーが完了してからコピー元を削除するため、途中で失敗してもコピー元は失われません。
+移動先が "/" で終わる場合、またはローカルディレクトリの場合は、その配下へ同じファイル名で移動します。`: `Moves the given local file or object specified by a GCS URI and the like to the destination.
+GCS-to-GCS and S3-to-S3 moves use a server-side copy and delete (no data is downloaded), and local files are renamed.
+Moves between different backends delete the source only after the copy completes, so the source is never lost on failure.
+If the destination ends with "/" or is a local directory, the file is moved under it with the same name.`,
+	"ファイルまたはオブジェクトの詳細情報を表示します。": "Show detailed information about files or objects.",
+	`指定されたローカルファイル、または GCS URI などで指定されたオブジェクトのサイズ、更新日時、Content-Type、
+CRC32C / MD5 チェックサム、世代番号、カスタムメタデータを表示します (取得できる項目はバックエンドによって異なります)。
+--json を指定すると、1件ごとに1行の JSON (NDJSON) で出力します。`: `Shows the size, update time, Content-Type, CRC32C / MD5 checksums, generation numbers and custom metadata
+of the given local files or objects specified by GCS URIs and the like (available fields depend on the backend).
+With --json, each entry is written as one line of JSON (NDJSON).`,
+	"ファイルまたはオブジェクトが存在するかどうかを終了コードで返します。": "Report whether a file or object exists via the exit code.",
+	`指定されたローカルファイル、または GCS URI などで指定されたオブジェクトが存在する場合は 0、存在しない場合は 1 を終了コードとして返します。
+確認できなかった場合は、原因 (引数の誤り、権限不足、ネットワークエラーなど) にかかわらず 2 を返し、原因はエラーメッセージに表示します。
+エラーメッセージを解析せずに、シェルスクリプトで後続の処理を分岐させるために使用します。`: `Exits with 0 if the given local file or object specified by a GCS URI and the like exists, and 1 if it does not.
+If existence could not be determined, it exits with 2 regardless of the cause (bad arguments, insufficient permissions, network errors, etc.)
+and shows the cause in the error message. Use it to gate subsequent steps in shell scripts without parsing error messages.`,
+	"GCSオブジェクトの V4 署名付きURLを生成します。": "Generate a V4 signed URL for a GCS object.",
+	`指定された GCS URI のオブジェクトにアクセスするための V4 署名付きURLを生成し、標準出力に表示します。
+署名には、ファクトリが使用する認証情報 (サービスアカウント) を使用します。
+-m PUT を指定すると、認証情報を持たないクライアントからのアップロードを許可するURLを生成できます。`: `Generates a V4 signed URL for accessing the object at the given GCS URI and prints it to stdout.
+The URL is signed with the credentials (service account) used by the factory.
+With -m PUT, the URL allows uploads from clients that have no credentials.`,
+	"署名付きURLで許可する HTTP メソッド (GET, PUT, HEAD, DELETE など)": "HTTP method allowed by the signed URL (GET, PUT, HEAD, DELETE, etc.)",
+	"署名付きURLの有効期間 (最大 168h)":                             "Validity period of the signed URL (up to 168h)",
+	"リクエストで送信する必要がある Content-Type (PUT の場合)":             "Content-Type that requests must send (for PUT)",
+	"複数のファイルまたはオブジェクトを連結して出力します。":                        "Concatenate multiple files or objects and write the result.",
+	`指定されたローカルファイル、または GCS URI などで指定されたオブジェクトを、指定された順に読み込んで1つに連結し、
+標準出力または -o で指定された出力先へ転送します。
+出力先とすべてのソースが同じバケットの GCS URI の場合は、GCS の Compose API でサーバー側で連結します (データはダウンロードされません)。
+--join を指定すると、各ソースを rcopy --split-size で分割して書き込んだ名前として扱い、そのパート (.part0001、.part0002、...) を番号順に連結して元の内容に戻します。`: `Reads the given local files or objects (GCS URIs, etc.) in order, concatenates them,
+and writes the result to stdout or to the destination given with -o.
+When the destination and all sources are GCS URIs in the same bucket, they are concatenated server-side with the GCS Compose API (no data is downloaded).
+With --join, each source is treated as a name written split by rcopy --split-size, and its parts (.part0001, .part0002, ...) are concatenated in number order to restore the original content.`,
+	"連結した内容を書き出すファイル名（省略時は標準出力）":                      "File to write the concatenated content to (default: stdout)",
+	"コピー先の既存の GCS オブジェクトの末尾に追記 (存在しない場合は新規作成)":        "Append to the end of the existing GCS object given as the destination (created if it does not exist)",
+	"同時に転送するファイル数 (-r) または同時に読み込む範囲の数 (--slice-size)": "Number of files (-r) or ranges (--slice-size) to transfer concurrently",
+	"同時に転送するファイル数": "Number of files to transfer concurrently",
+	"指定したサイズ (例: 64MiB) の範囲に分割して並行して転送 (リモートからのダウンロード、またはローカルファイルから GCS へのアップロード)":                                   "Transfer in parallel ranges of the given size (e.g. 64MiB; downloads from remote sources, or uploads from a local file to GCS)",
+	"ローカルファイルから GCS へのアップロードの進行状況を保存し、中断された場合は同じコマンドの再実行で続きから再開":                                                     "Save progress of uploads from a local file to GCS and resume an interrupted upload when the same command is run again",
+	"コピー先のローカルファイルに途中までダウンロードされている場合は続きから再開し、完了後に CRC32C を検証":                                                        "Resume a partial download in the local file given as the destination and verify its CRC32C on completion",
+	"内容のコピーに使用するバッファのサイズ (例: 1MiB。省略時は 32KiB)":                                                                       "buffer size used to copy content (e.g. 1MiB; default 32KiB)",
+	"アップロードを分割して送信する単位 (例: 8MiB。省略時は GCS: 16MiB、S3: 5MiB。GCS では 0 でバッファリングせずに送信)":                                    "chunk size for uploads (e.g. 8MiB; default GCS: 16MiB, S3: 5MiB; 0 sends to GCS without buffering)",
+	"一時的なエラー (429、5xx、接続のリセットなど) で失敗したGCSリクエストと、rcopy / sync / rbatch で失敗したファイルを再試行する回数 (省略時はリクエストは中断されるまで、ファイルは2回)": "number of times to retry GCS requests that failed with transient errors (429, 5xx, connection reset, ...) and files that failed in rcopy / sync / rbatch (default: requests until interrupted, files twice)",
+	"最初の再試行までの待ち時間 (再試行のたびに倍増し、最大30秒。省略時は 1s)":                                                                       "backoff before the first retry (doubles on each retry up to 30s; default 1s)",
+	"各操作 (読み込み・書き込み・コピーなど) のタイムアウト。転送中はこの時間データが転送されなかった場合に中断します (省略時は無制限)":                                           "timeout for each operation (read, write, copy, ...); transfers are aborted when no data flows for this long (default: unlimited)",
+	"作成するローカルファイルのパーミッション (8進数。例: 0640。省略時は 0666 から umask を除いた値)":                                                    "permission of created local files (octal, e.g. 0640; default: 0666 minus umask)",
+	"作成するローカルの出力ディレクトリのパーミッション (8進数。例: 0750。省略時は 0755)":                                                              "permission of created local output directories (octal, e.g. 0750; default: 0755)",
+	"ローカルファイルへのコピーで、コピー元の更新日時をファイルの更新日時 (mtime) に設定":                                                                 "when copying to local files, set the file modification time (mtime) to that of the source",
+	"ローカルファイルへの書き込みの完了前に、ファイルとその親ディレクトリを fsync (書き込み直後のクラッシュでも内容を失わないようにする)":                                         "fsync the file and its parent directory before a local write completes (so a crash right after the write cannot lose the data)",
+	"既存のファイル/オブジェクトを上書きせずにスキップ (GCS では存在しないことを条件に書き込み、ローカルでは O_EXCL で作成)":                                            "skip existing files/objects instead of overwriting them (GCS writes are conditioned on the object not existing; local files are created with O_EXCL)",
+	"既存のファイル/オブジェクトを上書きし、上書きしたファイルを報告 (--no-clobber とは併用できません)":                                                      "overwrite existing files/objects and report each overwritten file (cannot be combined with --no-clobber)",
+	"コピー先の GCS オブジェクトの世代番号 (generation) が一致する場合にのみ書き込み (0 の場合は存在しない場合のみ)。他の書き込みで更新されていた場合は失敗します":                     "write only if the generation of the GCS object given as the destination matches (0: only if it does not exist); fails if another writer has updated it",
+	"コピー先の GCS オブジェクトのメタデータの世代番号 (metageneration) が一致する場合にのみ書き込み":                                                    "write only if the metageneration of the GCS object given as the destination matches",
+	"コピー元の GCS オブジェクトの指定した世代番号 (generation) を読み込み (gs://bucket/object#generation と同じ)":                               "read the given generation of the source GCS object (same as gs://bucket/object#generation)",
+	"GCS オブジェクトの世代を一覧表示し、過去の世代を復元します。":                                                                               "List the generations of a GCS object and restore an old generation.",
+	`バケットのオブジェクトのバージョニングで保持された、GCS オブジェクトの現行の世代と非現行の世代を新しい順に一覧表示します。
+各行には、サイズ、更新日時、状態 (live: 現行、noncurrent: 非現行) と、その世代を読み込む URI (gs://bucket/object#generation) を表示します。
+--restore に世代番号を指定すると、その世代をサーバー側でコピーして現行のオブジェクトに戻します (誤って上書きした場合の復旧に使用します)。
+--json を指定すると、1件ごとに1行の JSON (NDJSON) で出力します。`: `Lists the live and noncurrent generations of a GCS object kept by bucket object versioning, newest first.
+Each line shows the size, update time, state (live or noncurrent) and the URI that reads that generation (gs://bucket/object#generation).
+With --restore <generation>, the generation is copied server-side back to the live object (use it to recover from an accidental overwrite).
+With --json, each generation is printed as one line of JSON (NDJSON).`,
+	"指定した世代番号の世代を現行のオブジェクトとして復元":                                                                                "restore the given generation as the live object",
+	"コピー先の GCS / S3 / Azure のオブジェクトに設定するカスタムメタデータ (key=value)。複数指定可":                                            "custom metadata (key=value) to set on the GCS / S3 / Azure object given as the destination. Can be repeated",
+	`コピー先の GCS / S3 / Azure のオブジェクトに設定する Cache-Control (例: "public, max-age=3600")`:                             `Cache-Control to set on the GCS / S3 / Azure object given as the destination (e.g. "public, max-age=3600")`,
+	"コピー先の GCS / S3 / Azure のオブジェクトに設定する Content-Encoding (例: gzip。内容は変換されません)":                                 "Content-Encoding to set on the GCS / S3 / Azure object given as the destination (e.g. gzip; the content is not converted)",
+	`コピー先の GCS / S3 / Azure のオブジェクトに設定する Content-Disposition (例: "attachment; filename=report.csv")`:            `Content-Disposition to set on the GCS / S3 / Azure object given as the destination (e.g. "attachment; filename=report.csv")`,
+	"コピー先の GCS / S3 / Azure のオブジェクトに設定する Content-Language (例: ja)":                                              "Content-Language to set on the GCS / S3 / Azure object given as the destination (e.g. ja)",
+	"コピー先の GCS / S3 / Azure のオブジェクトに設定する Content-Type (省略時は拡張子、判定できない場合は内容の先頭から判定)":                             "Content-Type to set on the GCS / S3 / Azure object given as the destination (detected from the extension, or from the start of the content, when omitted)",
+	"コピー先の GCS オブジェクトを指定した Cloud KMS の鍵 (projects/P/locations/L/keyRings/R/cryptoKeys/K) で暗号化 (CMEK)":           "encrypt the destination GCS objects with the given Cloud KMS key (projects/P/locations/L/keyRings/R/cryptoKeys/K) (CMEK)",
+	"内容をクライアント側で暗号化 (AES-256-GCM) してから書き込み (平文は書き込み先に送信されません)":                                                  "encrypt the content on the client (AES-256-GCM) before writing (plaintext is never sent to the destination)",
+	"--encrypt で暗号化された内容を読み込み、クライアント側で復号して書き込み":                                                                 "read content encrypted with --encrypt and decrypt it on the client before writing",
+	"--encrypt / --decrypt に使用する 32 バイトの鍵 (バイナリまたは Base64) のファイル":                                               "file holding the 32-byte key (binary or Base64) for --encrypt / --decrypt",
+	"--encrypt / --decrypt で、オブジェクトごとのデータ鍵をラップする Cloud KMS の鍵 (projects/P/locations/L/keyRings/R/cryptoKeys/K)": "Cloud KMS key (projects/P/locations/L/keyRings/R/cryptoKeys/K) that wraps the per-object data key for --encrypt / --decrypt",
+	"内容を gzip で圧縮しながらコピー先の GCS / S3 / Azure へ書き込み、Content-Encoding: gzip を設定 (Content-Type は圧縮前の内容から判定)":        "Compress the content with gzip while writing it to the GCS / S3 / Azure destination and set Content-Encoding: gzip (Content-Type is detected from the uncompressed content)",
+	"Content-Encoding: gzip の GCS オブジェクトを展開せずに、保存されている圧縮済みの内容のまま読み込み":                                           "Read gzip-encoded (Content-Encoding: gzip) GCS objects as stored, without decompressing them",
+	"拡張子が .gz / .zst のコピー元を展開して書き込み (-r では書き込み先の名前から拡張子を除く)":                                                    "Decompress sources with a .gz / .zst extension before writing them (with -r, the extension is removed from the destination names)",
+	"転送した内容の CRC32C を計算し、コピー元と書き込み先 (GCS) のオブジェクトの属性と比較 (一致しない場合は書き込み先を削除して失敗)":                                 "Compute the CRC32C of the transferred content and compare it with the attributes of the source and the destination (GCS) objects (on mismatch, delete the destination and fail)",
+	"--verify に加えて MD5 も計算して比較":                              "Also compute and compare MD5 in addition to --verify",
+	"ファイルまたはオブジェクトのハッシュ (CRC32C / MD5 / SHA-256) を表示・検証します。": "Print or verify the hash (CRC32C / MD5 / SHA-256) of files or objects.",
+	`指定されたローカルファイル、または GCS URI などで指定されたオブジェクトのハッシュを、sha256sum と同じ "<16進数のハッシュ>  <パス>" の形式で出力します。
+CRC32C と MD5 は、GCS などが保存している属性があればそれを使用し (内容はダウンロードしません)、ない場合と SHA-256 は内容を読み込んで計算します。
+Content-Encoding: gzip の GCS オブジェクトは、保存されている (圧縮済みの) 内容のハッシュを出力します。
+-c を指定すると、引数をチェックサムファイル (sha256sum などの出力) として読み込み、記載されたファイルとオブジェクトのハッシュを検証します。
+アルゴリズムはハッシュの長さ (8: CRC32C、32: MD5、64: SHA-256) から判定し、一致しないものがあった場合は失敗します。`: `Print the hash of the given local files or objects specified by GCS URIs etc. in the same "<hex hash>  <path>" format as sha256sum.
+CRC32C and MD5 use the attributes stored by GCS etc. when available (the content is not downloaded); otherwise, and for SHA-256, the content is read and hashed.
+For GCS objects with Content-Encoding: gzip, the hash of the stored (compressed) content is printed.
+With -c, the arguments are read as checksum files (output of sha256sum etc.) and the hashes of the listed files and objects are verified.
+The algorithm is determined from the hash length (8: CRC32C, 32: MD5, 64: SHA-256), and the command fails if any hash does not match.`,
+	"ハッシュのアルゴリズム (crc32c, md5, sha256)":      "hash algorithm (crc32c, md5, sha256)",
+	"引数のチェックサムファイルを読み込み、記載されたハッシュを検証":        "read the checksum files given as arguments and verify the hashes listed in them",
+	"2つのディレクトリ/プレフィックス配下のファイルを比較し、差分を表示します。": "Compare the files under two directories/prefixes and show the differences.",
+	`ローカルディレクトリと GCS URI のプレフィックス (または2つのプレフィックス) の配下のファイルを相対パスで対応付け、
+一方にのみ存在するファイル (left-only / right-only)、サイズが異なるファイル (size)、CRC32C チェックサムが異なるファイル (checksum) を出力します。
+チェックサムは一覧に含まれる値 (GCS) を使用し、含まれない場合 (ローカルファイル、S3 など) は内容を読み込んで計算します。
+大量のファイルを移行した後の検証に使用します。差分がない場合は 0、差分がある場合は 1 を終了コードとして返します。
+比較できなかった場合は、引数の誤りでは 2、権限不足では 4 など共通の終了コードを返し、原因を分類できない場合は 2 を返します。`: `Match the files under a local directory and a GCS URI prefix (or two prefixes) by relative path, and print
+files that exist on only one side (left-only / right-only), files whose size differs (size) and files whose CRC32C checksum differs (checksum).
+Checksums come from the listing (GCS) when available; otherwise (local files, S3, etc.) the content is read and hashed.
+Use it to verify large migrations. The exit code is 0 if there are no differences and 1 if there are differences.
+If the comparison failed, it exits with the common exit code for the cause (2 for bad arguments, 4 for insufficient permissions, etc.),
+or 2 if the cause cannot be classified.`,
+	"サイズのみを比較し、チェックサムは比較しない":                                                                            "compare sizes only, not checksums",
+	"同時にチェックサムを計算するファイル数":                                                                               "number of files whose checksums are computed concurrently",
+	"書き込み先が既に存在し、サイズと CRC32C チェックサムがコピー元と一致する場合は転送せずにスキップ":                                              "skip the transfer if the destination already exists and its size and CRC32C checksum match the source",
+	"GCSへのリクエストの料金を請求するプロジェクトID。リクエスト元による支払い (Requester Pays) が有効なバケットの読み書きに必要です":                       "Project ID to bill for GCS requests. Required to read and write buckets with Requester Pays enabled",
+	"コマンドの結果の出力形式 (text|json)。json では、一覧・情報・転送したファイルごとの結果を1行の JSON (NDJSON) で標準出力へ出力し、ログは標準エラー出力へ出力します": "output format of command results (text|json). With json, listings, object info and per-file transfer results are written to stdout as one line of JSON each (NDJSON), and logs go to stderr",
+	"標準エラー出力へ出力するログの形式 (text|json)":                                                                     "Format of logs written to stderr (text|json)",
+	"警告とエラー以外のログを出力しない":                                                                                 "Suppress logs other than warnings and errors",
+	"rcopy / sync / rrm / rmv で、転送・移動・削除されるファイルとサイズを表示し、書き込み先を変更せずに終了":                                  "For rcopy / sync / rrm / rmv, print the files that would be transferred, moved or deleted with their sizes and exit without touching any destination",
+	"終了時に、転送したファイル数、失敗と再試行の回数、バイト数、所要時間とスループットの集計を標準エラー出力へ表示":                                           "At the end, print a summary of transferred files, failures, retries, bytes, elapsed time and throughput to stderr",
+	"転送したファイルごとに、コピー元、書き込み先、サイズ、CRC32C、日時と結果を1行の JSON で追記するファイル (ローカルファイルまたは gs://)":                    "File (local or gs://) to append one JSON line per transferred file with source, destination, size, CRC32C, time and status",
+	"転送・削除したファイルごとに、コピー元、コピー先、サイズ、CRC32C、日時と結果を1行の JSON で追記するファイル (ローカルファイルまたは gs://)":                  "File (local or gs://) to append one JSON line per transferred or deleted file with source, destination, size, CRC32C, time and status",
+	"バッチファイルに記載されたコピー元と書き込み先の組をまとめて転送します。":                                                              "Transfer every source/destination pair listed in a batch file.",
+	`ローカルファイルまたは GCS URI などで指定されたバッチファイル (CSV または JSONL) を読み込み、記載されたすべての転送を --parallel で指定した数まで同時に実行します。
+CSV はヘッダー行に source、destination と任意の options の列を持ち、options には "no-clobber;content-type=text/csv" のように転送ごとの扱いを ";" 区切りで指定します。
+JSONL は1行に1件の {"source": ..., "destination": ..., "options": {"no-clobber": true, "content-type": "text/csv"}} を記載します。
+options には no-clobber、force、skip-identical、preserve、verify、content-type、cache-control、content-encoding、content-disposition、content-language と metadata.<key> を指定できます。
+転送ごとの結果を1行ずつ表示します。失敗した転送は再試行し、それでも失敗した場合は残りの転送を中止します (--continue-on-error を指定すると続行します)。`: `Reads a batch file (CSV or JSONL) given as a local path or a URI such as a GCS URI, and runs every transfer listed in it, up to --parallel at a time.
+A CSV file has a header row with source, destination and an optional options column; options holds per-transfer settings separated by ";", such as "no-clobber;content-type=text/csv".
+A JSONL file has one {"source": ..., "destination": ..., "options": {"no-clobber": true, "content-type": "text/csv"}} per line.
+Supported options are no-clobber, force, skip-identical, preserve, verify, content-type, cache-control, content-encoding, content-disposition, content-language and metadata.<key>.
+The result of each transfer is printed on its own line. Failed transfers are retried; if one still fails, the remaining transfers are canceled (use --continue-on-error to keep going).`,
+	"バッチファイルの形式 (csv|jsonl。省略時は拡張子 .csv / .jsonl / .ndjson から判定)": "Batch file format (csv|jsonl; inferred from the .csv / .jsonl / .ndjson extension when omitted)",
+	"再試行しても失敗した転送があっても、残りの転送を続行":                                  "Keep running the remaining transfers even if a transfer still fails after retries",
+	"ファイルまたはオブジェクトの末尾を表示し、追記を監視します。":                              "Print the end of a file or object and follow appended content.",
+	`指定されたローカルファイル、または GCS URI などで指定されたオブジェクトの末尾の -n 行 (または -c のサイズ) を標準出力へ表示します。
+ファイル全体はダウンロードせず、末尾の範囲のみを範囲読み込みで読み込みます。
+-f を指定すると、--interval ごとにサイズと世代番号を確認し、前回の位置から追記された範囲のみを読み込んで表示し続けます (Ctrl+C で終了します)。
+サイズが前回の位置より小さくなった場合 (切り詰め、または別の内容での置き換え) や、削除されてから再作成された場合は、先頭から表示し直します。`: `Prints the last -n lines (or -c bytes) of the given local file or object specified by a GCS URI and the like to stdout.
+Only the trailing range is read with range reads; the whole file is not downloaded.
+With -f, the size and generation are checked every --interval and only the range appended since the previous position is read and printed (press Ctrl+C to stop).
+When the size drops below the previous position (truncation or replacement with different content), or the file is deleted and recreated, output restarts from the beginning.`,
+	"末尾から表示する行数": "Number of lines to print from the end",
+	"末尾から表示するサイズ (例: 1KiB)。指定した場合は行数の代わりにサイズで表示": "Size to print from the end (e.g. 1KiB); when given, used instead of a line count",
+	"末尾を表示した後も、追記された内容を表示し続ける":                   "Keep printing appended content after printing the end",
+	"-f でサイズと世代番号を確認する間隔":                        "Interval for checking the size and generation with -f",
+	"ファイルまたはオブジェクトの先頭を表示します。":                    "Print the beginning of a file or object.",
+	`指定されたローカルファイル、または GCS URI などで指定されたオブジェクトの先頭の -n 行 (または -c のサイズ) を標準出力へ表示します。
+ファイル全体はダウンロードせず、先頭の範囲のみを範囲読み込みで読み込むため、大きなファイルのヘッダーなどを確認する場合に使用します。`: `Prints the first -n lines (or -c bytes) of the given local file or object specified by a GCS URI and the like to stdout.
+Only the leading range is read with range reads instead of downloading the whole file, which is useful for inspecting the header of a large file.`,
+	"先頭から表示する行数": "Number of lines to print from the beginning",
+	"先頭から表示するサイズ (例: 4KiB)。指定した場合は行数の代わりにサイズで表示":                  "Size to print from the beginning (e.g. 4KiB); when given, used instead of a line count",
+	"ファイルまたはオブジェクトの作成・変更・削除を監視します。":                               "Watch a file or object for creation, changes, and deletion.",
+	"情報を取得して変更を確認する間隔":                                            "Interval between checks for changes",
+	"指定した条件を満たした時点で終了 (exists: 存在する、change: 最初の変更)。省略時は変更を表示し続ける": "Exit once the condition is met (exists: the object exists, change: the first change). By default, keep printing changes",
+	"変更ごとにシェルで実行するコマンド (変更の内容は REMOTEIO_EVENT などの環境変数で渡す)":        "Shell command to run on each change (details are passed in environment variables such as REMOTEIO_EVENT)",
+	"指定した時間が経過しても --until の条件を満たさない場合は失敗 (0 の場合は無制限)":             "Fail if the --until condition is not met within this duration (0 means no limit)",
+	`指定されたローカルファイル、または GCS URI などで指定されたオブジェクトの情報を --interval ごとに取得し、
+作成 (created)、内容の置き換え (updated)、メタデータのみの更新 (metadata)、削除 (deleted) を1行ずつ表示します。
+GCS では世代番号とメタデータの世代番号で、それ以外ではサイズと更新日時で変更を判定します。
+--until exists を指定すると存在した時点で、--until change を指定すると最初の変更で終了します (上流のジョブが完了を示すマーカーを書き込むのを待つ場合に使用します)。
+--exec を指定すると、変更ごとにコマンドを実行します (REMOTEIO_EVENT、REMOTEIO_URI、REMOTEIO_GENERATION、REMOTEIO_SIZE 環境変数で変更の内容を渡します)。`: `Fetch information about the given local file, or the object given by a GCS URI or similar, every --interval,
+and print one line per creation (created), content replacement (updated), metadata-only update (metadata), or deletion (deleted).
+On GCS, changes are detected by generation and metageneration; elsewhere, by size and modification time.
+With --until exists, exit as soon as it exists; with --until change, exit on the first change (useful for waiting until an upstream job writes a completion marker).
+With --exec, run a command on each change (details are passed in the REMOTEIO_EVENT, REMOTEIO_URI, REMOTEIO_GENERATION, and REMOTEIO_SIZE environment variables).`,
+	"ローカルディレクトリを監視し、作成・変更されたファイルを自動的にアップロードします。":                  "Watch a local directory and automatically upload created and modified files.",
+	"ファイルの最後の変更からアップロードするまでの待ち時間 (この間に変更されるとさらに待つ)":               "Time to wait after a file's last change before uploading it (further changes restart the wait)",
+	"アップロードするファイルのパターン (例: '*.csv'。複数指定可。省略時はすべてのファイル)":           "Pattern of files to upload (e.g. '*.csv'; repeatable; default: all files)",
+	"アップロードしないファイルやディレクトリのパターン (例: '*.tmp'。複数指定可。--include より優先)": "Pattern of files or directories not to upload (e.g. '*.tmp'; repeatable; takes precedence over --include)",
+	"同時にアップロードするファイル数":                            "Number of files to upload concurrently",
+	"監視を開始した時点で存在するファイルもアップロード":                   "Also upload files that already exist when watching starts",
+	"書き込み先のサイズと CRC32C チェックサムが一致するファイルはアップロードしない": "Do not upload files whose destination has the same size and CRC32C checksum",
+	`ローカルディレクトリ配下 (サブディレクトリを含む) のファイルの作成・変更を OS のファイル変更通知で検知し、相対パスを保ったままコピー先 (GCS URI のプレフィックスなど) へアップロードします。
+書き込み中のファイルを何度もアップロードしないよう、最後の変更から --debounce の間変更がなかったファイルをまとめて、--parallel で指定した数まで同時にアップロードします (失敗したファイルは再試行します)。
+--include / --exclude でアップロードするファイルを絞り込めます (パターンに / を含まない場合はファイル名と、含む場合はディレクトリからの相対パスと照合します)。
+--initial を指定すると、監視を開始した時点で存在するファイルもアップロードします。Ctrl+C で終了します。`: `Detect files created or modified under a local directory (including subdirectories) through OS file change notifications, and upload them to the destination (such as a GCS URI prefix), preserving relative paths.
+To avoid uploading files that are still being written over and over, files that have not changed for --debounce since their last change are uploaded together, up to --parallel at a time (failed files are retried).
+Use --include / --exclude to filter the files to upload (patterns without / match the file name; patterns with / match the path relative to the directory).
+With --initial, files that already exist when watching starts are uploaded too. Press Ctrl+C to stop.`,
+	"リモートの URI の読み書きを中継する gRPC のプロキシを起動します。":            "Start a gRPC proxy that relays reads and writes of remote URIs.",
+	"待ち受けるアドレス (host:port。すべてのインターフェースで待ち受ける場合は :7070)": "Address to listen on (host:port; use :7070 to listen on all interfaces)",
+	"TLS のサーバー証明書ファイル (PEM。--tls-key と併せて指定)":           "TLS server certificate file (PEM; use with --tls-key)",
+	"TLS の秘密鍵ファイル (PEM)": "TLS private key file (PEM)",
+	"クライアントごとに同時に処理するリクエストの数の上限。超えたリクエストは待機 (0 の場合は無制限)":                                                     "Maximum number of requests processed concurrently per client; excess requests wait (0 means unlimited)",
+	"クライアントごとの読み込みと書き込みの合計の帯域の上限 (1秒あたり。例: 10MiB。省略時は無制限)":                                                   "Maximum combined read and write bandwidth per client (per second, e.g. 10MiB; default: unlimited)",
+	"クライアントごとの1分あたりのリクエストの数の上限。超えたリクエストは RESOURCE_EXHAUSTED で拒否 (0 の場合は無制限)":                                 "Maximum number of requests per minute per client; excess requests are rejected with RESOURCE_EXHAUSTED (0 means unlimited)",
+	"読み書きを許可する URI のスキームまたは接頭辞 (例: gs://my-bucket/、sftp://files.example.com/)。複数指定可 (省略時はすべてのリモートの URI を許可)": "URI scheme or prefix allowed to be read and written (e.g. gs://my-bucket/, sftp://files.example.com/); may be repeated (default: all remote URIs)",
+	"リモートの URI の読み書きを、指定したプロキシ (remoteio proxyd の host:port) 経由で行う (認証トークンは環境変数 REMOTEIO_PROXY_TOKEN で指定)":   "Route reads and writes of remote URIs through this proxy (host:port of remoteio proxyd; set the authentication token in the REMOTEIO_PROXY_TOKEN environment variable)",
+	"プロキシへ TLS で接続する (証明書はシステムの CA で検証)":                                                                     "Connect to the proxy over TLS (the certificate is verified against the system CAs)",
+	"プロキシの証明書の検証に使用する CA 証明書ファイル (PEM。指定した場合は TLS で接続)":                                                      "CA certificate file used to verify the proxy certificate (PEM; implies TLS)",
+	`このマシンの認証情報 (GCP のデフォルト認証情報、AWS・Azure の環境変数など) で、クライアントの代わりにリモートの URI を読み書きする gRPC のプロキシを起動します。
+認証情報を配置できないマシンでは、--proxy にこのプロキシのアドレスを指定すると、リモートの URI の読み込み・書き込み・情報の取得・一覧をプロキシ経由で行います。
+環境変数 REMOTEIO_PROXY_TOKEN を指定すると、同じトークンを指定したクライアントのみを受け付けます。トークンを盗聴されないよう、--tls-cert と --tls-key で TLS を有効にしてください。
+クライアント (テナント) ごとに異なるトークンを使用する場合は、REMOTEIO_PROXY_TOKEN にカンマ区切りで複数のトークンを指定します。
+ループバック以外のアドレスで待ち受ける場合は、TLS の有無にかかわらずトークンの指定が必要です (TLS は通信を暗号化しますが、クライアントを認証しません)。
+--allow を指定すると、読み書きできる URI をスキーム (gs:// など) またはバケット・ホストの接頭辞 (gs://my-bucket/ など) に制限します。
+--client-max-concurrent、--client-bandwidth、--client-requests-per-minute は、1つのクライアントの大量の転送がプロキシを占有しないよう、
+クライアント (トークンを指定した場合はトークン、それ以外は接続元の IP アドレス) ごとに同時実行数、帯域、リクエストの数を制限します。
+プロキシを実行するマシンのファイルを公開しないよう、ローカルファイルのパスは拒否します。Ctrl+C で終了します。`: `Start a gRPC proxy that reads and writes remote URIs on behalf of clients, using this machine's credentials (GCP application default credentials, AWS and Azure environment variables, and so on).
+On machines without credentials, pass this proxy's address to --proxy to read, write, stat, and list remote URIs through the proxy.
+If the REMOTEIO_PROXY_TOKEN environment variable is set, only clients with the same token are accepted. Enable TLS with --tls-cert and --tls-key so the token cannot be intercepted.
+To give each client (tenant) its own token, set REMOTEIO_PROXY_TOKEN to a comma-separated list of tokens.
+Listening on a non-loopback address requires a token, with or without TLS (TLS encrypts the connection but does not authenticate clients).
+With --allow, the URIs that can be read and written are restricted to schemes (such as gs://) or bucket or host prefixes (such as gs://my-bucket/).
+--client-max-concurrent, --client-bandwidth and --client-requests-per-minute limit concurrency, bandwidth and request count per client
+(per token when tokens are set, otherwise per source IP address) so that one client's bulk transfer cannot monopolize the proxy.
+Local file paths are rejected so that files on the proxy machine are not exposed. Press Ctrl+C to stop.`,
+	"リモートのプレフィックスを FUSE でローカルのディレクトリにマウントします。": "Mount a remote prefix on a local directory with FUSE.",
+	`GCS URI (gs://bucket/prefix) などのプレフィックスを FUSE のファイルシステムとしてマウントし、ローカルのパスしか扱えないツールから読み込めるようにします。
+ディレクトリは "/" 区切りの一覧で、ファイルの内容は読み込んだ範囲だけを範囲読み込みで取得するため、大きなファイルの一部だけを読むツールでも全体をダウンロードしません。
+既定では読み取り専用でマウントします。--write を指定すると、作成・変更したファイルを閉じた時点でアップロードし、削除・名前の変更もリモートへ反映します (ライトスルー)。
+Linux、macOS (macFUSE)、FreeBSD で使用できます。Ctrl+C、または fusermount -u / umount でアンマウントします。`: `Mount a prefix such as a GCS URI (gs://bucket/prefix) as a FUSE filesystem so that tools which only handle local paths can read it.
+Directories are listed with the "/" delimiter and file contents are fetched with range reads for only the ranges that are read, so tools that read part of a large file do not download all of it.
+The mount is read-only by default. With --write, created or modified files are uploaded when they are closed, and deletes and renames are applied to the remote as well (write-through).
+Available on Linux, macOS (macFUSE) and FreeBSD. Unmount with Ctrl+C, or with fusermount -u / umount.`,
+	"書き込みを許可し、作成・変更したファイルを閉じた時点でアップロード (削除・名前の変更も反映)":           "Allow writes and upload created or modified files when they are closed (deletes and renames are applied too)",
+	"ディレクトリの一覧とファイルの情報をキャッシュする時間 (他のクライアントによる変更はこの時間が経過してから反映)": "How long to cache directory listings and file information (changes by other clients appear after this time)",
+	"ディレクトリまたはプレフィックス配下のファイルを1つの tar / zip アーカイブにまとめます。":        "Bundle the files under a directory or prefix into a single tar / zip archive.",
+	`ローカルディレクトリ、または GCS URI (gs://bucket/prefix) などのプレフィックス配下のすべてのファイルを名前順に読み込み、1つのアーカイブとして書き込み先へ書き込みます。
+アーカイブ内のパスはコピー元からの相対パスです。読み込みながら書き込むため、一時ファイルを使用せず、アーカイブ全体をメモリにも保持しません。
+形式は --type (tar、tar.gz、tgz、zip) で指定します。省略した場合は書き込み先の拡張子 (.tar、.tar.gz、.tgz、.zip) から判定します。
+書き込み先に - を指定すると標準出力へ書き出します (--type が必要です)。`: `Read every file under a local directory, or under a prefix such as a GCS URI (gs://bucket/prefix), in name order and write them to the destination as a single archive.
+Paths in the archive are relative to the source. The archive is written while it is read, without temporary files and without holding the whole archive in memory.
+Choose the format with --type (tar, tar.gz, tgz, zip). When omitted, it is determined from the destination extension (.tar, .tar.gz, .tgz, .zip).
+Specify - as the destination to write to standard output (--type is required).`,
+	"アーカイブの形式: tar、tar.gz (tgz)、zip (省略時は書き込み先の拡張子から判定)": "Archive format: tar, tar.gz (tgz), zip (determined from the destination extension when omitted)",
+	"tar / zip アーカイブをディレクトリまたはプレフィックスへ展開します。":            "Extract a tar / zip archive into a directory or prefix.",
+	`ローカルファイル、または GCS URI などで指定された tar / zip アーカイブを読み込み、各ファイルを書き込み先 (ローカルディレクトリ、または gs://bucket/prefix などのプレフィックス) 配下の同じ相対パスへ書き込みます。
+tar は先頭から読み込みながら展開し、zip は末尾の目次と各ファイルの範囲だけを範囲読み込みで読み込むため、一時ファイルを使用しません。
+形式は --type (tar、tar.gz、tgz、zip) で指定します。省略した場合はアーカイブの拡張子 (.tar、.tar.gz、.tgz、.zip) から判定します。
+アーカイブに - を指定すると標準入力から読み込みます (tar / tar.gz のみ。--type が必要です)。
+書き込み先の外を指すパス (絶対パスや ..) を含むアーカイブはエラーにし、シンボリックリンクなどの通常のファイル以外のエントリは警告して読み飛ばします。`: `Read a tar / zip archive given as a local file or a URI such as a GCS URI and write each file to the same relative path under the destination (a local directory, or a prefix such as gs://bucket/prefix).
+A tar archive is extracted while it is read from the start; for a zip archive only the trailing directory and each file's range are fetched with range reads, so no temporary files are used.
+Choose the format with --type (tar, tar.gz, tgz, zip). When omitted, it is determined from the archive extension (.tar, .tar.gz, .tgz, .zip).
+Specify - as the archive to read from standard input (tar / tar.gz only; --type is required).
+Archives containing paths that point outside the destination (absolute paths or ..) are rejected, and entries other than regular files, such as symbolic links, are skipped with a warning.`,
+	"アーカイブの形式: tar、tar.gz (tgz)、zip (省略時はアーカイブの拡張子から判定)":                                 "Archive format: tar, tar.gz (tgz), zip (determined from the archive extension when omitted)",
+	"書き込む内容を指定したサイズ (例: 1GiB) ごとに、コピー先の名前に連番 (.part0001、.part0002、...) を付けたパートに分割して書き込み": "Split the written content every given size (e.g. 1GiB) into parts named after the destination with sequence numbers (.part0001, .part0002, ...)",
+	"各ソースを rcopy --split-size で分割したパートの名前として、パート (.part0001、.part0002、...) を番号順に連結":      "Treat each source as the name of parts split by rcopy --split-size and concatenate its parts (.part0001, .part0002, ...) in number order",
+	"ディレクトリ/プレフィックス配下のファイルの合計サイズと数を集計します。":                                               "Summarize the total size and number of files under a directory/prefix.",
+	`指定されたローカルディレクトリ、または GCS URI などのプレフィックス配下のすべてのファイルを一覧し、合計サイズとファイル (オブジェクト) の数を表示します。
+--depth を指定すると、その深さまでのサブディレクトリ/サブプレフィックスごとの合計も表示します (例: --depth 1 で直下のサブプレフィックスごと)。
+各行には、合計サイズ、ファイルの数とURIを表示します。サイズは読みやすい単位 (KiB、MiB、...) で表示します。--bytes を指定するとバイト数で表示し、--json を指定すると1件ごとに1行の JSON (NDJSON) で出力します。`: `List every file under the given local directory or prefix such as a GCS URI and show the total size and number of files (objects).
+With --depth, the totals of each subdirectory/sub-prefix down to that depth are also shown (e.g. --depth 1 for each immediate sub-prefix).
+Each line shows the total size, the number of files and the URI. Sizes are shown in human-readable units (KiB, MiB, ...); --bytes shows byte counts, and --json outputs one JSON object per line (NDJSON).`,
+	"サブディレクトリ/サブプレフィックスごとの合計を表示する深さ (0 の場合は全体の合計のみ)":                                     "Depth down to which the totals of each subdirectory/sub-prefix are shown (0 shows only the overall total)",
+	"サイズを読みやすい単位ではなくバイト数で表示":                                                             "Show sizes as byte counts instead of human-readable units",
+	"gs:// でバケットを一覧するプロジェクトID (省略時は環境変数 GOOGLE_CLOUD_PROJECT または CLOUDSDK_CORE_PROJECT)": "Project ID whose buckets are listed for gs:// (defaults to the GOOGLE_CLOUD_PROJECT or CLOUDSDK_CORE_PROJECT environment variable)",
+	"GCS バケットを作成します。": "Creates GCS buckets.",
+	`指定された GCS URI (gs://bucket) のバケットを、--project (省略時は環境変数 GOOGLE_CLOUD_PROJECT または CLOUDSDK_CORE_PROJECT) のプロジェクトに作成します。
+--location、--storage-class でロケーションと既定のストレージクラスを、--uniform-access で均一なバケットレベルのアクセスを、--versioning でオブジェクトのバージョニングを指定します。
+同じ名前のバケットが既に存在する場合は失敗します (終了コード 5)。`: `Creates the bucket for the given GCS URI (gs://bucket) in the --project project (defaults to the GOOGLE_CLOUD_PROJECT or CLOUDSDK_CORE_PROJECT environment variable).
+Use --location and --storage-class to set the location and default storage class, --uniform-access to enable uniform bucket-level access, and --versioning to enable object versioning.
+Fails if a bucket with the same name already exists (exit code 5).`,
+	"バケットを作成するプロジェクトID (省略時は環境変数 GOOGLE_CLOUD_PROJECT または CLOUDSDK_CORE_PROJECT)": "Project ID to create the bucket in (defaults to the GOOGLE_CLOUD_PROJECT or CLOUDSDK_CORE_PROJECT environment variable)",
+	"バケットのロケーション (例: US、ASIA-NORTHEAST1。省略時は US)":                                 "Bucket location (e.g. US, ASIA-NORTHEAST1; defaults to US)",
+	"バケットの既定のストレージクラス (例: STANDARD、NEARLINE、COLDLINE、ARCHIVE。省略時は STANDARD)":      "Default storage class of the bucket (e.g. STANDARD, NEARLINE, COLDLINE, ARCHIVE; defaults to STANDARD)",
+	"均一なバケットレベルのアクセスを有効にする (オブジェクトごとの ACL を無効にする)":                                "Enable uniform bucket-level access (disables per-object ACLs)",
+	"オブジェクトのバージョニングを有効にする":                                                        "Enable object versioning",
+	"GCS バケットを削除します。": "Removes GCS buckets.",
+	`指定された GCS URI (gs://bucket) の空のバケットを削除します。
+--force を指定すると、バケットのすべてのオブジェクトを非現行の世代を含めて削除してから、バケットを削除します (元に戻せません)。`: `Removes the empty bucket for the given GCS URI (gs://bucket).
+With --force, every object in the bucket, including noncurrent generations, is deleted before the bucket is removed (this cannot be undone).`,
+	"バケットのすべてのオブジェクト (非現行の世代を含む) を削除してからバケットを削除":                                                                                            "Delete every object in the bucket (including noncurrent generations) before removing the bucket",
+	"使用する設定ファイル (--config、省略時は ~/.config/remoteio/config.yaml) のプロファイル (省略時は環境変数 REMOTEIO_PROFILE または設定ファイルの default_profile)":              "Profile to use from the config file (--config, defaults to ~/.config/remoteio/config.yaml); defaults to the REMOTEIO_PROFILE environment variable or the default_profile in the config file",
+	"ファイルの転送が成功するたびにシェルで実行するコマンド (結果は REMOTEIO_SOURCE などの環境変数と、標準入力の JSON で渡す。失敗は警告を出力して集計の hook_failures に数え、終了コードには影響しない)":                "Shell command to run each time a file transfer succeeds (the result is passed in environment variables such as REMOTEIO_SOURCE and as JSON on stdin; failures log a warning and are counted in hook_failures of the summary without affecting the exit code)",
+	"ファイルの転送が失敗するたびにシェルで実行するコマンド (結果は REMOTEIO_SOURCE、REMOTEIO_ERROR などの環境変数と、標準入力の JSON で渡す。失敗は警告を出力して集計の hook_failures に数え、終了コードには影響しない)": "Shell command to run each time a file transfer fails (the result is passed in environment variables such as REMOTEIO_SOURCE and REMOTEIO_ERROR and as JSON on stdin; failures log a warning and are counted in hook_failures of the summary without affecting the exit code)",
+	"ファイルの転送が成功または失敗するたびに、結果を JSON で POST する URL (http:// または https://。送信の失敗は警告を出力して集計の hook_failures に数え、終了コードには影響しない)":                    "URL to POST the result to as JSON each time a file transfer succeeds or fails (http:// or https://; failed deliveries log a warning and are counted in hook_failures of the summary without affecting the exit code)",
+
+	// --- ログメッセージ ---
+	"データ転送開始": "Starting data transfer",
+	"Factoryを初期化し、コンテキストに格納しました。": "Initialized the factory and stored it in the context.",
+	"GCSクライアントのクローズに失敗しました":       "Failed to close the GCS client",
+	"GCSクライアントをクローズしました。":         "Closed the GCS client.",
+	"再帰コピー開始":                 "Starting recursive copy",
+	"ファイルをコピーしました":            "Copied file",
+	"再帰コピー完了":                 "Recursive copy finished",
+	"同期開始":                    "Starting sync",
+	"同期完了":                    "Sync complete",
+	"コピーしてから移動元を削除します":        "Copying, then deleting the source",
+	"連結開始":                    "starting concatenation",
+	"分割ダウンロード開始":              "starting sliced download",
+	"分割ダウンロード完了":              "sliced download complete",
+	"シグナル (%s) を受信したため中断しました": "interrupted by signal (%s)",
+	"書き込み先が既に存在するため、スキップしました":       "Skipped because the destination already exists",
+	"既存の書き込み先を上書きしました":              "Overwrote the existing destination",
+	"世代 %d は既に現行の世代です: %s":          "generation %d is already the live generation: %s",
+	"世代 %d を復元しました: %s (現行の世代: %s)": "restored generation %d: %s (live generation: %s)",
+	"コピー元は展開されて読み込まれたため、チェックサムを検証できません (--raw を指定すると検証できます)": "cannot verify the checksum because the source was read decompressed (use --raw to verify it)",
+	"書き込み先の削除に失敗しました":    "failed to delete the destination",
+	"コピー元のチェックサムを検証しました": "verified the source checksum",
+	"比較開始": "comparison started",
+	"比較完了": "comparison completed",
+	"書き込み先の内容がコピー元と同じため、スキップしました":                   "skipped because the destination content is identical to the source",
+	"コピー (dry-run): %s -> %s (%s)":                  "would copy (dry-run): %s -> %s (%s)",
+	"移動 (dry-run): %s -> %s (%s)":                   "would move (dry-run): %s -> %s (%s)",
+	"削除 (dry-run): %s (%s)":                         "would delete (dry-run): %s (%s)",
+	"スキップ (dry-run): %s -> %s (%s)":                 "would skip (dry-run): %s -> %s (%s)",
+	"%d 件のファイル (合計 %s) が対象です (dry-run のため変更していません)": "%d files (%s in total) would be affected (dry-run, nothing was changed)",
+	"バッチ転送開始":                                       "Batch transfer started",
+	"バッチ転送完了":                                       "Batch transfer completed",
+	"複数ファイルのコピー開始":                                  "Multi-file copy started",
+	"追記の監視開始":                                       "following appended content",
+	"ファイルが切り詰められたか置き換えられたため、先頭から表示します": "the file was truncated or replaced; printing from the beginning",
+	"ファイルが見つからないため、再作成されるまで待機します":      "the file was not found; waiting until it is recreated",
+	"監視開始": "Watch started",
+	"コマンドの実行に失敗しました":       "Command failed",
+	"ディレクトリの監視開始":          "Directory watch started",
+	"ディレクトリの監視でエラーが発生しました": "Error while watching the directory",
+	"アップロードに失敗したファイルがあります": "Some files failed to upload",
+	"認証トークンが指定されていないため、接続できるすべてのクライアントの読み書きを受け付けます":                   "No authentication token is set; accepting reads and writes from any client that can connect",
+	"--allow が指定されていないため、プロキシの認証情報でアクセスできるすべてのリモートの URI の読み書きを受け付けます": "--allow is not set; accepting reads and writes of every remote URI the proxy credentials can access",
+	"プロキシの待ち受け開始": "Proxy listening",
+	"マウント開始":      "Mount started",
+	"アンマウントされました": "Unmounted",
+	"マウント先を使用中のため、使用中のプロセスが終了するまでアンマウントを再試行します (もう一度 Ctrl+C で強制終了)": "The mount point is in use; retrying the unmount until the processes using it exit (press Ctrl+C again to force quit)",
+	"アーカイブ開始":  "Archive started",
+	"アーカイブ完了":  "Archive completed",
+	"展開開始":     "Extraction started",
+	"展開完了":     "Extraction completed",
+	"分割書き込み開始": "Split write started",
+	"分割書き込み完了": "Split write completed",
+	"以前に書き込んだ後続のパートが残っています。rcat --join で連結する前に削除してください": "Later parts from a previous write remain; delete them before joining with rcat --join",
+	"分割されたパートを連結します": "Joining split parts",
+	"集計中":         "Summarizing",
+	"バケットを作成しました": "Created bucket",
+	"バケットのオブジェクトを削除しました": "Deleted bucket objects",
+	"バケットを削除しました":        "Removed bucket",
+	"プロファイルを使用します":       "Using profile",
+	"フックのコマンドの実行に失敗しました": "Hook command failed",
+	"Webhook の送信に失敗しました": "Failed to send webhook",
+	"一部のフックの実行に失敗しました":   "Some hooks failed",
+	"-o は非推奨です。コピー先は rcopy <source> <destination> のように最後の引数で指定してください": "-o is deprecated; give the destination as the last argument, as in rcopy <source> <destination>",
+
+	// --- エラーメッセージ ---
+	"コンテキストにファクトリが見つかりません。":                      "No factory found in the context.",
+	"コンテキストの値が期待される型 (factory.Factory) ではありません。": "The context value is not of the expected type (factory.Factory).",
+	"ClientFactoryの初期化に失敗しました":                   "Failed to initialize the ClientFactory",
+	"InputReaderの作成に失敗しました":                      "Failed to create the InputReader",
+	"入力ストリームのオープンに失敗しました (%s)":                   "Failed to open the input stream (%s)",
+	"データの転送中にエラーが発生しました":                         "An error occurred while transferring data",
+	"進捗出力先(%s)のオープンに失敗しました":                      "Failed to open the progress output (%s)",
+	"サポートされていない進捗形式です: %s":                       "Unsupported progress format: %s",
+	"OutputWriterの作成に失敗しました":                     "Failed to create the OutputWriter",
+	"出力先への書き込みに失敗しました (%s)":                      "Failed to write to the destination (%s)",
+	"無効なサイズ指定です: %q":                             "Invalid size: %q",
+	"--rounds には1以上を指定してください: %d":                "--rounds must be at least 1: %d",
+	"--parallel には1以上を指定してください: %d":              "--parallel must be at least 1: %d",
+	"ベンチマークの%s処理に失敗しました (%s)":                    "Benchmark %s failed (%s)",
+	"警告: 計測用オブジェクトの削除に失敗しました (%s): %v":           "Warning: failed to delete a benchmark object (%s): %v",
+	"-r を指定する場合はコピー先を指定してください":                   "-r requires a destination",
+	"InputReaderが一覧の取得をサポートしていません":               "the InputReader does not support listing",
+	"コピー元の一覧取得に失敗しました (%s)":                      "failed to list the source (%s)",
+	"コピー対象のファイルが見つかりません: %s":                     "no files to copy found: %s",
+	"OutputWriterが削除をサポートしていません":                 "the OutputWriter does not support deletion",
+	"コピー先の一覧取得に失敗しました (%s)":                      "failed to list the destination (%s)",
+	"コピー元が空のため --delete を中止しました (%s)":            "the source is empty, so --delete was aborted (%s)",
+	"すべて削除するには --allow-empty-source を指定してください":   "pass --allow-empty-source to delete everything",
+	"コピー先のファイルの削除に失敗しました (%s)":                   "failed to delete the destination file (%s)",
+	"チェックサムの計算に失敗しました (%s)":                      "failed to compute the checksum (%s)",
+	", フックの失敗: %d":                               ", hook failures: %d",
+	"コピー: %d, スキップ: %d, 削除: %d":                  "copied: %d, skipped: %d, deleted: %d",
+	"一覧の取得に失敗しました (%s)":                          "failed to list (%s)",
+	"合計: %d ファイル, %d バイト":                        "TOTAL: %d files, %d bytes",
+	"削除に失敗しました (%s)":                             "failed to delete (%s)",
+	"%d 件のファイルを削除しました":                           "deleted %d files",
+	"一致するファイルが見つかりません: %s":                       "no files matched: %s",
+	"無効なワイルドカードです: %s":                           "invalid wildcard: %s",
+	"OutputWriterが移動をサポートしていません":                 "the OutputWriter does not support moving",
+	"移動に失敗しました (%s)":                             "failed to move (%s)",
+	"移動先へのコピーは完了しましたが、移動元の削除に失敗しました (%s)":        "the copy to the destination completed, but deleting the source failed (%s)",
+	"InputReaderが情報の取得をサポートしていません":               "the InputReader does not support stat",
+	"情報の取得に失敗しました (%s)":                          "failed to stat (%s)",
+	"存在の確認に失敗しました (%s)":                          "failed to check existence (%s)",
+	"%w (原因: %s)": "%w (cause: %s)",
+	"引数の誤り":       "bad arguments",
+	"存在しない":       "not found",
+	"権限不足":        "insufficient permissions",
+	"前提条件を満たさない":  "precondition not met",
+	"一部の失敗":       "partial failure",
+	"分類できないエラー":   "unclassified error",
+	"署名付きURLは GCS URI (gs://) のみ生成できます: %s":            "signed URLs can only be generated for GCS URIs (gs://): %s",
+	"サーバー側の連結に失敗しました (%s)":                             "server-side compose failed (%s)",
+	"--append と -r は同時に指定できません":                        "--append and -r cannot be used together",
+	"--append を指定する場合はコピー先に GCS URI (gs://) を指定してください": "--append requires a GCS URI (gs://) as the destination",
+	"OutputWriterが追記をサポートしていません":                       "OutputWriter does not support appending",
+	"出力先への追記に失敗しました (%s)":                              "failed to append to destination (%s)",
+	"--slice-size には正のサイズを指定してください: %s":                "--slice-size must be a positive size: %s",
+	"出力ディレクトリ(%s)の作成に失敗しました":                           "failed to create output directory (%s)",
+	"分割ダウンロードに失敗しました (%s)":                             "sliced download failed (%s)",
+	"OutputWriterが並行アップロードをサポートしていません":                 "OutputWriter does not support parallel uploads",
+	"--resumable は、ローカルファイルをコピー先の GCS URI (gs://) へコピーする場合にのみ指定できます (-r と --append は併用できません)":     "--resumable can only be used when copying a local file to a GCS URI (gs://) given as the destination (not with -r or --append)",
+	"アップロードの進行状況の保存先を決定できません":                                                                     "cannot determine where to save upload progress",
+	"--continue は、リモートのファイルをコピー先のローカルファイルへコピーする場合にのみ指定できます (-r、--append と --slice-size は併用できません)": "--continue can only be used when copying a remote file to a local file given as the destination (not with -r, --append or --slice-size)",
+	"--continue と --max-size、--allow-content-type、--clamd は併用できません":                               "--continue cannot be used with --max-size, --allow-content-type or --clamd",
+	"InputReaderがダウンロードの再開をサポートしていません":                                                            "InputReader does not support resuming downloads",
+	"ダウンロードに失敗しました (%s)":                                                                          "download failed (%s)",
+	"--buffer-size には正のサイズを指定してください: %s":                                                          "--buffer-size must be a positive size: %s",
+	"コピー元の更新日時の取得に失敗しました (%s)":                                                                    "failed to get the modification time of the source (%s)",
+	"%s には 8進数のパーミッション (例: 0644) を指定してください: %s":                                                   "%s must be an octal permission (e.g. 0644): %s",
+	"ローカルファイル(%s)の更新日時の設定に失敗しました":                                                                 "failed to set the modification time of local file (%s)",
+	"ローカルファイル(%s)の fsync に失敗しました":                                                                 "failed to fsync local file (%s)",
+	"書き込み先の存在の確認に失敗しました (%s)":                                                                     "failed to check whether the destination exists (%s)",
+	"--no-clobber は --append、--continue と併用できません":                                                 "--no-clobber cannot be combined with --append or --continue",
+	"--if-generation-match と --if-metageneration-match は、コピー先の GCS URI (gs://) へ1つのファイルをコピーする場合にのみ指定できます (-r、--append と --resumable は併用できません)": "--if-generation-match and --if-metageneration-match can only be used when copying a single file to a GCS URI (gs://) given as the destination (-r, --append and --resumable cannot be combined)",
+	"--generation には、GCS URI (gs://) のコピー元の世代番号を正の整数で指定してください (-r は併用できません)":                                                                   "--generation must be a positive generation number of a GCS URI (gs://) source (-r cannot be combined)",
+	"InputReaderが世代の一覧の取得をサポートしていません":                                                                                                          "InputReader does not support listing generations",
+	"世代 %d が見つかりません (%s)":                     "generation %d not found (%s)",
+	"世代の復元に失敗しました (%s)":                       "failed to restore generation (%s)",
+	"OutputWriterがサーバー側のコピーをサポートしていません":       "OutputWriter does not support server-side copy",
+	"世代の一覧の取得に失敗しました (%s)":                    "failed to list generations (%s)",
+	"--metadata は key=value の形式で指定してください: %s": "--metadata must be in key=value form: %s",
+	"--content-type、--metadata、--cache-control、--content-encoding、--content-disposition、--content-language と --gzip は、コピー先に GCS / S3 / Azure の URI を指定した場合にのみ指定できます (--append は併用できません)": "--content-type, --metadata, --cache-control, --content-encoding, --content-disposition, --content-language and --gzip can only be used when the destination is a GCS / S3 / Azure URI (cannot be combined with --append)",
+	"--kms-key は、書き込み先が GCS URI (gs://) の場合にのみ指定できます":                                             "--kms-key can only be used when the destination is a GCS URI (gs://)",
+	"--kms-key には projects/P/locations/L/keyRings/R/cryptoKeys/K の形式で鍵の名前を指定してください: %s":           "--kms-key must be a key name of the form projects/P/locations/L/keyRings/R/cryptoKeys/K: %s",
+	"--encrypt または --decrypt を指定する場合は、--encryption-key-file または --encryption-kms-key で鍵を指定してください": "--encrypt and --decrypt require a key given by --encryption-key-file or --encryption-kms-key",
+	"鍵ファイルの読み込みに失敗しました (%s)":                                                                      "failed to read the key file (%s)",
+	"--encrypt と --decrypt は --append、--continue、--resumable と併用できません":                            "--encrypt and --decrypt cannot be combined with --append, --continue or --resumable",
+	"内容の変換に失敗しました (%s)":                                                                           "failed to transform the content (%s)",
+	"--encryption-key-file と --encryption-kms-key は同時に指定できません":                                    "--encryption-key-file and --encryption-kms-key cannot be used together",
+	"--encryption-key-file と --encryption-kms-key は、--encrypt または --decrypt と併せて指定してください":         "--encryption-key-file and --encryption-kms-key must be used with --encrypt or --decrypt",
+	"鍵ファイルには 32 バイトの鍵 (バイナリまたは Base64) を保存してください: %s":                                             "the key file must contain a 32-byte key (binary or Base64): %s",
+	"--auto-decompress は --append、--continue、--resumable、--slice-size と併用できません":                   "--auto-decompress cannot be combined with --append, --continue, --resumable or --slice-size",
+	"内容の展開に失敗しました (%s)":                                                                           "failed to decompress the content (%s)",
+	"--verify は --append、--resumable と併用できません (--continue は常に CRC32C を検証します)":                     "--verify cannot be combined with --append or --resumable (--continue always verifies the CRC32C)",
+	"コピー元の情報の取得に失敗しました (%s)":                                                                      "failed to stat the source (%s)",
+	"コピー元の内容の検証に失敗しました (%s)":                                                                      "failed to verify the source content (%s)",
+	"チェックサムファイルの形式が正しくありません (%s:%d)":                                                              "invalid checksum file format (%s:%d)",
+	"チェックサムファイルのオープンに失敗しました (%s)":                                                                 "failed to open the checksum file (%s)",
+	"%d 件のハッシュが一致しませんでした":                                                                         "%d computed checksums did NOT match",
+	"%d 件のファイルを読み込めませんでした":                                                                        "%d listed files could not be read",
+	"データの読み込み中にエラーが発生しました (%s)":                                                                   "error while reading the data (%s)",
+	"チェックサムファイルの読み込みに失敗しました (%s)":                                                                 "failed to read the checksum file (%s)",
+	"ディレクトリのハッシュは計算できません: %s":                                                                     "cannot compute the hash of a directory: %s",
+	"--algorithm には crc32c、md5、sha256 のいずれかを指定してください: %s":                                         "--algorithm must be one of crc32c, md5, sha256: %s",
+	"--skip-identical はコピー先を指定した場合にのみ指定でき、--append、--continue と内容を変換するフラグ (--encrypt、--decrypt、--auto-decompress、--gzip) とは併用できません": "--skip-identical requires a destination and cannot be combined with --append, --continue or flags that convert the content (--encrypt, --decrypt, --auto-decompress, --gzip)",
+	"書き込み先の情報の取得に失敗しました (%s)":                                   "failed to stat the destination (%s)",
+	"--format には text または json を指定してください: %s":                   "--format must be text or json: %s",
+	"--format json はコピー先を指定した場合にのみ指定できます (省略すると標準出力へ内容を出力するため)": "--format json can only be used with a destination (without one the content is written to stdout)",
+	"--log-format には text または json を指定してください: %s":               "--log-format must be text or json: %s",
+	"マニフェスト(%s)のオープンに失敗しました":                                    "Failed to open manifest (%s)",
+	"マニフェスト(%s)への書き込みに失敗しました":                                   "Failed to write manifest (%s)",
+	"--manifest にはローカルファイルまたは GCS URI (gs://) を指定してください: %s":    "--manifest must be a local file or a GCS URI (gs://): %s",
+	"--dry-run は rcopy、sync、rrm、rmv でのみ指定できます: %s":              "--dry-run can only be used with rcopy, sync, rrm and rmv: %s",
+	"options に指定できない項目です: %s":                                   "unsupported option: %s",
+	"バッチファイルの形式が正しくありません (%s:%d)":                               "malformed batch file (%s:%d)",
+	"バッチファイルのオープンに失敗しました (%s)":                                  "failed to open batch file (%s)",
+	"不明な列です: %s": "unknown column: %s",
+	"バッチファイルに転送が記載されていません (%s)":                                                                                                                                          "batch file lists no transfers (%s)",
+	"content-type などのオブジェクトの属性は、書き込み先が GCS / S3 / Azure の URI の場合にのみ指定できます":                                                                                              "object attributes such as content-type can only be set when the destination is a GCS / S3 / Azure URI",
+	"options の値には文字列または真偽値を指定してください: %s":                                                                                                                                 "option values must be strings or booleans: %s",
+	"バッチファイルの読み込みに失敗しました (%s)":                                                                                                                                           "failed to read batch file (%s)",
+	"ヘッダー行に source と destination の列が必要です":                                                                                                                                "the header row must contain source and destination columns",
+	"source と destination を指定してください":                                                                                                                                     "source and destination are required",
+	"options の %s には真偽値を指定してください: %s":                                                                                                                                    "option %s must be a boolean: %s",
+	"同じコピー元と書き込み先の転送が重複しています":                                                                                                                                            "the same source and destination are listed more than once",
+	"options の no-clobber と force は同時に指定できません":                                                                                                                           "options no-clobber and force cannot be used together",
+	"--input-format には csv または jsonl を指定してください: %s":                                                                                                                      "--input-format must be csv or jsonl: %s",
+	"バッチファイルの形式を拡張子から判定できません。--input-format で csv または jsonl を指定してください: %s":                                                                                               "cannot infer the batch file format from its extension; specify csv or jsonl with --input-format: %s",
+	`複数のコピー元を指定する場合は、コピー先に末尾が "/" の URI または既存のローカルディレクトリを指定してください: %s`:                                                                                                   `with multiple sources, the destination must be a URI ending in "/" or an existing local directory: %s`,
+	"複数のコピー元が同じ書き込み先になります: %s":                                                                                                                                           "multiple sources would be written to the same destination: %s",
+	"複数のコピー元は、-r、--append、--continue、--resumable、--slice-size、--generation、--if-generation-match、--if-metageneration-match と併用できません":                                     "multiple sources cannot be used with -r, --append, --continue, --resumable, --slice-size, --generation, --if-generation-match or --if-metageneration-match",
+	"標準入力 (-) からのコピーは、-r と複数のコピー元とは併用できません":                                                                                                                              "copying from standard input (-) cannot be combined with -r or multiple sources",
+	"標準入力 (-) からのコピーは、--resumable、--slice-size、--skip-identical、--verify、--verify-md5、--preserve と併用できません":                                                               "copying from standard input (-) cannot be combined with --resumable, --slice-size, --skip-identical, --verify, --verify-md5 or --preserve",
+	"-o を複数指定する場合は、-r は併用できません":                                                                                                                                          "multiple -o outputs cannot be combined with -r",
+	"-o を複数指定する場合は、--append、--continue、--resumable、--slice-size、--skip-identical、--force、--verify、--verify-md5、--if-generation-match、--if-metageneration-match と併用できません": "multiple -o outputs cannot be combined with --append, --continue, --resumable, --slice-size, --skip-identical, --force, --verify, --verify-md5, --if-generation-match or --if-metageneration-match",
+	"-o に同じ出力先が重複しています: %s":                                                                                                                                              "the same output is given to -o more than once: %s",
+	"--format json では、-o に標準出力 (-) を指定できません":                                                                                                                             "with --format json, -o cannot be standard output (-)",
+	"読み込みに失敗しました (%s)":                                                                                                                                                   "failed to read (%s)",
+	"--bytes には 0 以上のサイズを指定してください: %s":                                                                                                                                   "--bytes must be a size of 0 or more: %s",
+	"ディレクトリは表示できません: %s":                                                                                                                                                 "cannot print a directory: %s",
+	"--lines には 0 以上の行数を指定してください: %d":                                                                                                                                    "--lines must be 0 or more: %d",
+	"--interval には正の間隔を指定してください: %s":                                                                                                                                     "--interval must be a positive duration: %s",
+	"rtail は内容を標準出力へ出力するため、--format json は指定できません":                                                                                                                       "rtail writes content to stdout, so --format json cannot be used",
+	"InputReaderが範囲読み込みをサポートしていません":                                                                                                                                      "the InputReader does not support range reads",
+	"rhead は内容を標準出力へ出力するため、--format json は指定できません":                                                                                                                       "rhead writes content to stdout, so --format json cannot be used",
+	"--until には exists または change を指定してください: %s":                                                                                                                         "--until must be exists or change: %s",
+	"--timeout には 0 以上の時間を指定してください: %s":                                                                                                                                  "--timeout must be 0 or greater: %s",
+	"%s が経過しても条件を満たさなかったため、監視を終了しました (%s)":                                                                                                                               "stopped watching because the condition was not met within %s (%s)",
+	"ディレクトリの監視の開始に失敗しました":                                                                                                                                                "failed to start watching the directory",
+	"監視するローカルディレクトリを指定してください: %s":                                                                                                                                        "specify a local directory to watch: %s",
+	"ディレクトリの監視の開始に失敗しました (%s)":                                                                                                                                           "failed to start watching the directory (%s)",
+	"パターンの書式が正しくありません: %s":                                                                                                                                               "invalid pattern: %s",
+	"--debounce には正の時間を指定してください: %s":                                                                                                                                     "--debounce must be a positive duration: %s",
+	"CA 証明書の読み込みに失敗しました":                                                                                                                                                 "failed to read the CA certificate",
+	"CA 証明書ファイルに PEM 形式の証明書が含まれていません":                                                                                                                                    "the CA certificate file contains no PEM certificates",
+	"プロキシが終了しました":                                                                                                                                                        "the proxy stopped",
+	"TLS の証明書の読み込みに失敗しました":                                                                                                                                               "failed to load the TLS certificate",
+	"待ち受けの開始に失敗しました (%s)":                                                                                                                                                "failed to listen (%s)",
+	"ループバック以外のアドレス (%s) で待ち受ける場合は、環境変数 %s の認証トークンを指定してください (TLS はクライアントを認証しません)": "to listen on a non-loopback address (%s), set an authentication token in the %s environment variable (TLS does not authenticate clients)",
+	"--client-max-concurrent と --client-requests-per-minute には 0 以上の値を指定してください":  "--client-max-concurrent and --client-requests-per-minute must be 0 or greater",
+	"--allow にはリモートの URI のスキームまたは接頭辞を指定してください: %s":                               "--allow must be a remote URI scheme or prefix: %s",
+	"マウントするリモートの URI を指定してください: %s":                                              "Specify a remote URI to mount: %s",
+	"マウント先には既存のディレクトリを指定してください: %s":                                              "Specify an existing directory as the mount point: %s",
+	"--cache-ttl には 0 以上の時間を指定してください: %s":                                        "Specify a duration of 0 or more for --cache-ttl: %s",
+	"マウントに失敗しました": "Failed to mount",
+	"--format json は書き込み先に - を指定した場合は指定できません (標準出力へアーカイブを出力するため)":                         "--format json cannot be used when the destination is - (the archive is written to standard output)",
+	"アーカイブの形式を拡張子から判定できません。--type を指定してください: %s":                                          "Cannot determine the archive format from the extension; specify --type: %s",
+	"--type には tar、tar.gz、tgz または zip を指定してください: %s":                                      "--type must be tar, tar.gz, tgz or zip: %s",
+	"zip のアーカイブは標準入力から展開できません (末尾の目次から読み込む必要があるため)":                                       "A zip archive cannot be extracted from standard input (its directory at the end must be read first)",
+	"アーカイブの作成に失敗しました":                                                                     "Failed to create the archive",
+	"アーカイブの書き込みに失敗しました":                                                                   "Failed to write the archive",
+	"アーカイブのオープンに失敗しました":                                                                   "Failed to open the archive",
+	"アーカイブの展開に失敗しました":                                                                     "Failed to extract the archive",
+	"展開先にはローカルディレクトリまたはプレフィックスを指定してください (- は指定できません)":                                     "Specify a local directory or prefix as the destination (- is not allowed)",
+	"--split-size は、1つのコピー元を1つのコピー先 (標準出力以外) へコピーする場合にのみ指定できます (-r と --dry-run は併用できません)": "--split-size can only be used when copying one source to a single destination other than standard output (-r and --dry-run cannot be combined)",
+	"--split-size は --append、--continue、--resumable、--slice-size、--skip-identical、--no-clobber、--force、--verify、--verify-md5、--preserve、--gzip、--if-generation-match、--if-metageneration-match と併用できません": "--split-size cannot be combined with --append, --continue, --resumable, --slice-size, --skip-identical, --no-clobber, --force, --verify, --verify-md5, --preserve, --gzip, --if-generation-match or --if-metageneration-match",
+	"--split-size は --max-size、--allow-content-type、--clamd と併用できません": "--split-size cannot be combined with --max-size, --allow-content-type or --clamd",
+	"--split-size には正のサイズを指定してください: %s":                               "--split-size must be a positive size: %s",
+	"--depth には 0 以上の整数を指定してください: %d":                                 "--depth must be an integer of 0 or more: %d",
+	"--project でプロジェクトIDを指定してください (または環境変数 %s を設定してください)":             "specify a project ID with --project (or set the %s environment variable)",
+	"バケットの URI は gs://bucket の形式で指定してください: %s":                        "bucket URIs must be in the form gs://bucket: %s",
+	"バケットの作成に失敗しました (%s)":                                             "failed to create bucket (%s)",
+	"バケットのオブジェクトの削除に失敗しました (%s)":                                      "failed to delete bucket objects (%s)",
+	"バケットの削除に失敗しました (%s)":                                             "failed to remove bucket (%s)",
+	"OutputWriterがバケットの作成・削除をサポートしていません":                              "OutputWriter does not support creating or removing buckets",
+	"InputReaderがバケットの一覧をサポートしていません":                                  "InputReader does not support listing buckets",
+	"バケットの一覧取得に失敗しました (プロジェクト: %s)":                                   "failed to list buckets (project: %s)",
+	"設定ファイルを開けません (%s)":                                               "cannot open config file (%s)",
+	"設定ファイルの解析に失敗しました (%s)":                                           "failed to parse config file (%s)",
+	"プロファイル %s が空です":                                                  "profile %s is empty",
+	"プロファイル %s":                                                       "profile %s",
+	"プロファイル %s の parallelism には 0 以上の整数を指定してください: %d":                 "parallelism of profile %s must be a non-negative integer: %d",
+	"プロファイル %s の default_bucket にはバケット名のみを指定してください: %s":               "default_bucket of profile %s must be a bucket name only: %s",
+	"エイリアスの名前には英字で始まる2文字以上の英数字、_ と - を使用してください: %s":                   "alias names must be at least 2 characters of letters, digits, _ and -, starting with a letter: %s",
+	"エイリアス %s には gs://bucket/prefix などの URI を指定してください: %s":            "alias %s must point to a URI such as gs://bucket/prefix: %s",
+	"プロファイルが見つかりません: %s (定義されているプロファイル: %s)":                          "profile not found: %s (defined profiles: %s)",
+	"gs:/// の形式の URI には、default_bucket を指定したプロファイルが必要です: %s":          "URIs of the form gs:/// require a profile with default_bucket: %s",
+	"--webhook には http:// または https:// の URL を指定してください: %s":           "--webhook must be an http:// or https:// URL: %s",
+	"--format json は -o を指定した場合にのみ指定できます (-o を省略すると標準出力へ内容を出力するため)":   "--format json can only be used with -o (without -o the content is written to stdout)",
+	"-o を指定した場合は、コピー元を1つだけ指定してください (最後の引数のコピー先と -o は同時に指定できません)":      "with -o, give exactly one source (-o cannot be combined with a destination given as the last argument)",
+
+	// --- ライブラリのメッセージ (remoteio、transfer、proxy などのエラーとログ) ---
+	" (%d 件は開始せずに中止しました)":                                      " (%d were cancelled before starting)",
+	"%d 件中 %d 件の書き込み先への書き込みに失敗しました":                            "failed to write to %[2]d of %[1]d destinations",
+	"%d 件中 %d 件の転送に失敗しました":                                     "%[2]d of %[1]d transfers failed",
+	"%d 個を超えるソースを連結する場合、%d 個目以降に出力先 (%s) を含めることはできません":         "when composing more than %d sources, the destination (%s) cannot be included from source %d onward",
+	"%s の値が不正です: %q (record または replay)":                       "invalid value for %s: %q (record or replay)",
+	"%s の解析に失敗しました: %w":                                        "failed to parse %s: %w",
+	"%s を指定する場合は %s でカセットファイルのパスを指定してください":                     "when %s is set, set the cassette file path with %s",
+	"%sのデコードに失敗しました (%s): %w":                                  "failed to decode %s (%s): %w",
+	"%w (書き込み先の削除にも失敗しました: %v)":                                "%w (also failed to delete the destination: %v)",
+	"%w: %s (サイズ %d != %d バイト)":                                "%w: %s (size %d != %d bytes)",
+	"%w: %s -> %s (バケットが異なります)":                                "%w: %s -> %s (different buckets)",
+	"%w: %s の間データが転送されなかったため中断しました":                            "%w: aborted because no data was transferred for %s",
+	"%w: %w (書き込み先の削除にも失敗しました: %v)":                            "%w: %w (also failed to delete the destination: %v)",
+	"%w: Cloud KMS によるデータ鍵の復号に失敗しました (鍵: %s): %w":              "%w: failed to decrypt the data key with Cloud KMS (key: %s): %w",
+	"%w: バリデータ (%s)":                                           "%w: validators (%s)",
+	"%w: ヘッダーが不完全です":                                           "%w: incomplete header",
+	"%w: 暗号化された内容ではありません":                                      "%w: the content is not encrypted",
+	"Azure Blobのプロパティの取得に失敗しました (URI: %s): %w":                 "failed to get Azure blob properties (URI: %s): %w",
+	"Azure Blobの一覧取得に失敗しました (URI: %s): %w":                     "failed to list Azure blobs (URI: %s): %w",
+	"Azure Blobの削除に失敗しました (URI: %s): %w":                       "failed to delete the Azure blob (URI: %s): %w",
+	"Azure Blobの範囲読み込みに失敗しました (URI: %s): %w":                   "failed to read a range of the Azure blob (URI: %s): %w",
+	"Azure Blobの読み込みに失敗しました (URI: %s): %w":                     "failed to read the Azure blob (URI: %s): %w",
+	"Azure URIのコンテナ名が空です: %s":                                  "the container name in the Azure URI is empty: %s",
+	"Azure URIのパース失敗: %w":                                      "failed to parse the Azure URI: %w",
+	"Azureのアカウントキーが不正です: %w":                                   "invalid Azure account key: %w",
+	"Azureの認証情報の取得に失敗しました: %w":                                 "failed to get Azure credentials: %w",
+	"Azureへのコンテンツ書き込み中にエラーが発生":                                 "Error while writing content to Azure",
+	"Azureへのコンテンツ書き込み中にエラーが発生しました: %w":                         "error while writing content to Azure: %w",
+	"Azureへの書き込みに失敗しました: Azureクライアントが初期化されていません":               "failed to write to Azure: the Azure client is not initialized",
+	"Azureへの書き込みに失敗しました: Blob名が空です":                            "failed to write to Azure: the blob name is empty",
+	"Azureへの書き込みに失敗しました: コンテナ名が空です":                            "failed to write to Azure: the container name is empty",
+	"Azureクライアントが初期化されていないため、Blobを一覧できません (URI: %s)":           "cannot list blobs because the Azure client is not initialized (URI: %s)",
+	"Azureクライアントが初期化されていないため、Blobを読み込めません (URI: %s)":           "cannot read the blob because the Azure client is not initialized (URI: %s)",
+	"Azureクライアントが構成されていません (%s または %s を指定してください)":              "the Azure client is not configured (set %s or %s)",
+	"Azureクライアントの初期化に失敗しました (%s): %w":                          "failed to initialize the Azure client (%s): %w",
+	"Azureクライアントの初期化に失敗しました: %w":                               "failed to initialize the Azure client: %w",
+	"Azureクライアントを取得できません: %w":                                  "cannot get the Azure client: %w",
+	"Azure書き込み処理完了":                                            "Azure write completed",
+	"Azure書き込み処理開始":                                            "Starting Azure write",
+	"Cloud KMS によるデータ鍵の暗号化に失敗しました (鍵: %s): %w":                 "failed to encrypt the data key with Cloud KMS (key: %s): %w",
+	"Cloud KMS の鍵は GCS への書き込みでのみ指定できます: %s":                    "a Cloud KMS key can only be given for writes to GCS: %s",
+	"Cloud KMS クライアントの初期化に失敗しました: %w":                          "failed to initialize the Cloud KMS client: %w",
+	"Content-Type の判定のための読み込みに失敗しました: %w":                      "failed to read the content to detect its Content-Type: %w",
+	"Content-Typeの判定に失敗しました: %w":                               "failed to detect the Content-Type: %w",
+	"FUSE によるマウントは %s ではサポートされていません: %w":                       "FUSE mounts are not supported on %s: %w",
+	"FakeFactory はクラウドのクライアントを保持していません":                        "FakeFactory does not hold cloud clients",
+	"FakeFactoryは既にクローズされているため、InputReaderを生成できません":            "cannot create an InputReader because the FakeFactory is already closed",
+	"FakeFactoryは既にクローズされているため、OutputWriterを生成できません":           "cannot create an OutputWriter because the FakeFactory is already closed",
+	"GCS URIのバケット名が空です: %s":                                    "the bucket name in the GCS URI is empty: %s",
+	"GCS URIのパース失敗: %w":                                        "failed to parse the GCS URI: %w",
+	"GCS Writerのクローズに失敗":                                       "Failed to close the GCS writer",
+	"GCS Writerのクローズに失敗しました (アップロード処理中のエラー): %w":               "failed to close the GCS writer (error during upload): %w",
+	"GCSへのコンテンツ書き込み中にエラーが発生":                                   "Error while writing content to GCS",
+	"GCSへのコンテンツ書き込み中にエラーが発生しました: %w":                           "error while writing content to GCS: %w",
+	"GCSへの並行アップロードに失敗しました (URI: %s): %w":                       "parallel upload to GCS failed (URI: %s): %w",
+	"GCSへの書き込みに失敗しました: GCSクライアントが初期化されていません: %w":               "failed to write to GCS: the GCS client is not initialized: %w",
+	"GCSへの書き込みに失敗しました: オブジェクトパスが空です":                           "failed to write to GCS: the object path is empty",
+	"GCSへの書き込みに失敗しました: バケット名が空です":                              "failed to write to GCS: the bucket name is empty",
+	"GCSへの追記に失敗しました: GCSクライアントが初期化されていません: %w":                 "failed to append to GCS: the GCS client is not initialized: %w",
+	"GCSへの追記に失敗しました: オブジェクトパスが空です":                             "failed to append to GCS: the object path is empty",
+	"GCSへの追記に失敗しました: バケット名が空です":                                "failed to append to GCS: the bucket name is empty",
+	"GCSオブジェクトのコピーに失敗しました (%s -> %s): %w":                      "failed to copy the GCS object (%s -> %s): %w",
+	"GCSオブジェクトの一覧取得に失敗しました (URI: %s): %w":                      "failed to list GCS objects (URI: %s): %w",
+	"GCSオブジェクトの一覧取得に失敗しました (prefix: %s): %w":                   "failed to list GCS objects (prefix: %s): %w",
+	"GCSオブジェクトの世代が見つかりません (URI: %s): %w":                       "GCS object generation not found (URI: %s): %w",
+	"GCSオブジェクトの世代の一覧取得に失敗しました (URI: %s): %w":                   "failed to list GCS object generations (URI: %s): %w",
+	"GCSオブジェクトの削除に失敗しました (URI: %s): %w":                        "failed to delete the GCS object (URI: %s): %w",
+	"GCSオブジェクトの属性の取得に失敗しました (URI: %s): %w":                     "failed to get GCS object attributes (URI: %s): %w",
+	"GCSオブジェクトの情報の取得に失敗しました (URI: %s): %w":                     "failed to get GCS object info (URI: %s): %w",
+	"GCSオブジェクトの連結に失敗しました (URI: %s): %w":                        "failed to compose GCS objects (URI: %s): %w",
+	"GCSオブジェクトへの追記に失敗しました (URI: %s): %w":                       "failed to append to the GCS object (URI: %s): %w",
+	"GCSクライアントが初期化されていないため、GCSオブジェクトの世代を一覧できません (URI: %s): %w": "cannot list GCS object generations because the GCS client is not initialized (URI: %s): %w",
+	"GCSクライアントが初期化されていないため、GCSオブジェクトをコピーできません (URI: %s): %w":   "cannot copy the GCS object because the GCS client is not initialized (URI: %s): %w",
+	"GCSクライアントが初期化されていないため、GCSオブジェクトを一覧できません (URI: %s): %w":    "cannot list GCS objects because the GCS client is not initialized (URI: %s): %w",
+	"GCSクライアントが初期化されていないため、GCSオブジェクトを削除できません (URI: %s): %w":    "cannot delete the GCS object because the GCS client is not initialized (URI: %s): %w",
+	"GCSクライアントが初期化されていないため、GCSオブジェクトを読み込めません (URI: %s): %w":    "cannot read the GCS object because the GCS client is not initialized (URI: %s): %w",
+	"GCSクライアントが初期化されていないため、GCSオブジェクトを連結できません (URI: %s): %w":    "cannot compose GCS objects because the GCS client is not initialized (URI: %s): %w",
+	"GCSクライアントが初期化されていないため、バケットを一覧できません: %w":                   "cannot list buckets because the GCS client is not initialized: %w",
+	"GCSクライアントが初期化されていないため、バケットを作成できません (URI: %s): %w":         "cannot create the bucket because the GCS client is not initialized (URI: %s): %w",
+	"GCSクライアントが初期化されていないため、バケットを削除できません (URI: %s): %w":         "cannot delete the bucket because the GCS client is not initialized (URI: %s): %w",
+	"GCSクライアントが初期化されていないため、バケットを空にできません (URI: %s): %w":         "cannot empty the bucket because the GCS client is not initialized (URI: %s): %w",
+	"GCSクライアントが初期化されていないため、署名付きURLを生成できません (URI: %s)":          "cannot generate a signed URL because the GCS client is not initialized (URI: %s)",
+	"GCSクライアントが初期化されていません":                                     "the GCS client is not initialized",
+	"GCSクライアントが初期化されていません: %w":                                 "the GCS client is not initialized: %w",
+	"GCSクライアントが指定されていません":                                      "no GCS client was given",
+	"GCSクライアントの初期化に失敗しました: %w":                                 "failed to initialize the GCS client: %w",
+	"GCSクライアントは既にクローズされています":                                   "the GCS client is already closed",
+	"GCSクライアントは既にクローズされているため、InputReaderを生成できません":              "cannot create an InputReader because the GCS client is already closed",
+	"GCSクライアントは既にクローズされているため、OutputWriterを生成できません":             "cannot create an OutputWriter because the GCS client is already closed",
+	"GCSクライアントを取得できません: %w":                                    "cannot get the GCS client: %w",
+	"GCSファイルの範囲読み込みに失敗しました (URI: %s): %w":                      "failed to read a range of the GCS file (URI: %s): %w",
+	"GCSファイルの読み込みに失敗しました (URI: %s): %w":                        "failed to read the GCS file (URI: %s): %w",
+	"GCS並行アップロード完了":                                            "GCS parallel upload completed",
+	"GCS並行アップロード開始":                                            "Starting GCS parallel upload",
+	"GCS書き込み処理完了":                                              "GCS write completed",
+	"GCS書き込み処理開始":                                              "Starting GCS write",
+	"GCS追記処理完了":                                                "GCS append completed",
+	"JSONのエンコードに失敗しました (%s): %w":                               "failed to encode JSON (%s): %w",
+	"ProxyFactory はクラウドのクライアントを保持していません (認証情報はプロキシが保持します)":     "ProxyFactory does not hold cloud clients (the proxy holds the credentials)",
+	"ProxyFactoryは既にクローズされています":                                "the ProxyFactory is already closed",
+	"S3 URIのバケット名が空です: %s":                                     "the bucket name in the S3 URI is empty: %s",
+	"S3 URIのパース失敗: %w":                                         "failed to parse the S3 URI: %w",
+	"S3へのコンテンツ書き込み中にエラーが発生":                                    "Error while writing content to S3",
+	"S3へのコンテンツ書き込み中にエラーが発生しました: %w":                            "error while writing content to S3: %w",
+	"S3への書き込みに失敗しました: S3クライアントが初期化されていません":                     "failed to write to S3: the S3 client is not initialized",
+	"S3への書き込みに失敗しました: オブジェクトキーが空です":                            "failed to write to S3: the object key is empty",
+	"S3への書き込みに失敗しました: バケット名が空です":                               "failed to write to S3: the bucket name is empty",
+	"S3オブジェクトのコピーに失敗しました (%s -> %s): %w":                       "failed to copy the S3 object (%s -> %s): %w",
+	"S3オブジェクトの一覧取得に失敗しました (URI: %s): %w":                       "failed to list S3 objects (URI: %s): %w",
+	"S3オブジェクトの削除に失敗しました (URI: %s): %w":                         "failed to delete the S3 object (URI: %s): %w",
+	"S3オブジェクトの属性の取得に失敗しました (URI: %s): %w":                      "failed to get S3 object attributes (URI: %s): %w",
+	"S3クライアントが初期化されていないため、S3オブジェクトを一覧できません (URI: %s)":          "cannot list S3 objects because the S3 client is not initialized (URI: %s)",
+	"S3クライアントが初期化されていないため、S3オブジェクトを読み込めません (URI: %s)":          "cannot read the S3 object because the S3 client is not initialized (URI: %s)",
+	"S3クライアントの設定の読み込みに失敗しました: %w":                              "failed to load the S3 client configuration: %w",
+	"S3クライアントは既にクローズされています":                                    "the S3 client is already closed",
+	"S3クライアントを取得できません: %w":                                     "cannot get the S3 client: %w",
+	"S3ファイルの範囲読み込みに失敗しました (URI: %s): %w":                       "failed to read a range of the S3 file (URI: %s): %w",
+	"S3ファイルの読み込みに失敗しました (URI: %s): %w":                         "failed to read the S3 file (URI: %s): %w",
+	"S3書き込み処理完了":                                               "S3 write completed",
+	"S3書き込み処理開始":                                               "Starting S3 write",
+	"SFTP URIのパースに失敗しました: %w":                                  "failed to parse the SFTP URI: %w",
+	"SFTP URIのパース失敗: %w":                                       "failed to parse the SFTP URI: %w",
+	"SFTP URIのホスト名が空です: %s":                                    "the host name in the SFTP URI is empty: %s",
+	"SFTPの出力ディレクトリ(%s)の作成に失敗しました: %w":                          "failed to create the SFTP output directory (%s): %w",
+	"SFTPの認証に使用できる鍵が見つかりません (鍵ファイルを指定するか、ssh-agent を起動してください)": "no key available for SFTP authentication (give a key file or start ssh-agent)",
+	"SFTPへのコンテンツ書き込み中にエラーが発生":                                  "Error while writing content to SFTP",
+	"SFTPへのコンテンツ書き込み中にエラーが発生しました: %w":                          "error while writing content to SFTP: %w",
+	"SFTPへの書き込みに失敗しました: ファイルパスが空です":                            "failed to write to SFTP: the file path is empty",
+	"SFTPへの書き込みに失敗しました: 接続先が空です":                               "failed to write to SFTP: the host is empty",
+	"SFTPサーバー(%s)とのSSHハンドシェイクに失敗しました: %w":                      "SSH handshake with the SFTP server (%s) failed: %w",
+	"SFTPサーバー(%s)への接続に失敗しました: %w":                              "failed to connect to the SFTP server (%s): %w",
+	"SFTPセッションの開始に失敗しました (%s): %w":                             "failed to start the SFTP session (%s): %w",
+	"SFTPディレクトリの一覧取得に失敗しました (URI: %s): %w":                     "failed to list the SFTP directory (URI: %s): %w",
+	"SFTPファイル(%s)の作成に失敗しました: %w":                               "failed to create the SFTP file (%s): %w",
+	"SFTPファイル(%s)の確定に失敗しました: %w":                               "failed to commit the SFTP file (%s): %w",
+	"SFTPファイルのクローズに失敗しました: %w":                                 "failed to close the SFTP file: %w",
+	"SFTPファイルのシークに失敗しました (URI: %s): %w":                        "failed to seek in the SFTP file (URI: %s): %w",
+	"SFTPファイルの削除に失敗しました (URI: %s): %w":                         "failed to delete the SFTP file (URI: %s): %w",
+	"SFTPファイルの情報の取得に失敗しました (URI: %s): %w":                      "failed to get SFTP file info (URI: %s): %w",
+	"SFTPファイルの読み込みに失敗しました (URI: %s): %w":                       "failed to read the SFTP file (URI: %s): %w",
+	"SFTP書き込み処理完了":                                             "SFTP write completed",
+	"SFTP書き込み処理開始":                                             "Starting SFTP write",
+	"URI のプレースホルダーの展開に失敗しました (%s): %w":                         "failed to expand the placeholders in the URI (%s): %w",
+	"URI のプレースホルダーの解析に失敗しました (%s): %w":                         "failed to parse the placeholders in the URI (%s): %w",
+	"YAMLのエンコードに失敗しました (%s): %w":                               "failed to encode YAML (%s): %w",
+	"Zstandard 形式の内容の展開に失敗しました (%s): %w":                       "failed to decompress Zstandard content (%s): %w",
+	"clamd (%s) への接続に失敗しました: %w":                               "failed to connect to clamd (%s): %w",
+	"clamd からの応答の読み取りに失敗しました: %w":                              "failed to read the response from clamd: %w",
+	"clamd がエラーを返しました: %s":                                     "clamd returned an error: %s",
+	"clamd へのコマンド送信に失敗しました: %w":                                "failed to send the command to clamd: %w",
+	"clamd へのデータ送信に失敗しました: %w":                                 "failed to send data to clamd: %w",
+	"gzip による圧縮は GCS、S3 と Azure への書き込みでのみ指定できます: %s":           "gzip compression can only be given for writes to GCS, S3 and Azure: %s",
+	"gzip 形式の内容の展開に失敗しました (%s): %w":                            "failed to decompress gzip content (%s): %w",
+	"known_hosts (%s) の読み込みに失敗しました: %w":                        "failed to read known_hosts (%s): %w",
+	"proxy: プロキシ経由の書き込みでは使用できないオプションです":                        "proxy: option not supported for writes through the proxy",
+	"remoteio: クライアントは既にクローズされています":                            "remoteio: client already closed",
+	"remoteio: サーバー側でコピーできない組み合わせです":                           "remoteio: server-side copy not supported for this combination",
+	"remoteio: サーバー側で移動できない組み合わせです":                            "remoteio: server-side move not supported for this combination",
+	"remoteio: サーバー側で連結できない組み合わせです":                            "remoteio: server-side compose not supported for this combination",
+	"remoteio: チェックサムがコピー元と一致しません":                             "remoteio: checksum does not match the source",
+	"remoteio: 復号に失敗しました (鍵が異なるか、内容が改ざんされています)":                "remoteio: decryption failed (wrong key or tampered content)",
+	"remoteio: 書き込み先が前提条件 (世代番号) を満たしません":                      "remoteio: destination does not meet the precondition (generation)",
+	"remoteio: 書き込み先が既に存在します":                                  "remoteio: destination already exists",
+	"remoteio: 権限がありません":                                       "remoteio: permission denied",
+	"remoteio: 無効なURIです":                                       "remoteio: invalid URI",
+	"remoteio: 見つかりません":                                        "remoteio: not found",
+	"zip のアーカイブは ExtractZip で展開してください":                         "extract zip archives with ExtractZip",
+	"すべての書き込み先への書き込みに失敗しました":                                   "failed to write to every destination",
+	"ずらす期間は1つだけ指定してください: %q":                                   "give only one offset duration: %q",
+	"アップロードの進行状況(%s)の保存に失敗しました: %w":                            "failed to save the upload progress (%s): %w",
+	"アップロードの進行状況(%s)の解析に失敗しました: %w":                            "failed to parse the upload progress (%s): %w",
+	"アップロードの進行状況(%s)の読み込みに失敗しました: %w":                          "failed to read the upload progress (%s): %w",
+	"アップロードの進行状況の保存先の作成に失敗しました: %w":                            "failed to create the directory for the upload progress: %w",
+	"アップロードの進行状況の削除に失敗しました":                                    "Failed to delete the upload progress",
+	"アーカイブから展開したファイルの書き込みに失敗しました (%s): %w":                     "failed to write a file extracted from the archive (%s): %w",
+	"アーカイブするファイルが見つかりません (%s): %w":                             "no files to archive were found (%s): %w",
+	"アーカイブするファイルのオープンに失敗しました (%s): %w":                         "failed to open a file to archive (%s): %w",
+	"アーカイブするファイルの一覧の取得に失敗しました (%s): %w":                        "failed to list the files to archive (%s): %w",
+	"アーカイブに展開先の外を指すパスが含まれています: %s":                             "the archive contains a path outside the destination: %s",
+	"アーカイブの gzip の展開に失敗しました: %w":                               "failed to decompress the gzip archive: %w",
+	"アーカイブの形式には tar、tar.gz または zip を指定してください: %s":              "the archive format must be tar, tar.gz or zip: %s",
+	"アーカイブの書き出しに失敗しました (%s): %w":                               "failed to write the archive (%s): %w",
+	"アーカイブの書き出しに失敗しました: %w":                                    "failed to write the archive: %w",
+	"アーカイブの読み込みに失敗しました (%s): %w":                               "failed to read the archive (%s): %w",
+	"アーカイブの読み込みに失敗しました: %w":                                    "failed to read the archive: %w",
+	"アーカイブへのファイルの追加に失敗しました (%s): %w":                           "failed to add a file to the archive (%s): %w",
+	"アーカイブ中にファイルのサイズが変わりました (%s): 一覧 %d バイト、読み込み %d バイト":       "the file size changed while archiving (%s): listed %d bytes, read %d bytes",
+	"オブジェクトの削除に失敗しました (URI: %s): %w":                           "failed to delete the object (URI: %s): %w",
+	"カセット(%s)の書き込みに失敗しました: %w":                                 "failed to write the cassette (%s): %w",
+	"カセット(%s)の解析に失敗しました: %w":                                   "failed to parse the cassette (%s): %w",
+	"カセット(%s)の読み込みに失敗しました: %w":                                 "failed to read the cassette (%s): %w",
+	"カセットに記録されていないリクエストです: %s":                                 "request not recorded in the cassette: %s",
+	"カセットのエンコードに失敗しました: %w":                                    "failed to encode the cassette: %w",
+	"コピー元の読み込みに失敗しました: %w":                                     "failed to read the source: %w",
+	"コピー完了": "Copy completed",
+	"コンテンツスキャンで脅威が検出されました":                          "the content scan detected a threat",
+	"サイズの上限 (%d バイト) を超えています: %s":                   "exceeds the size limit (%d bytes): %s",
+	"サポートされていないアーカイブの形式です: %s":                      "unsupported archive format: %s",
+	"サポートされていないスキームです (%s://): %s":                  "unsupported scheme (%s://): %s",
+	"スキーム %s:// はランダムアクセスをサポートしていません: %s":           "scheme %s:// does not support random access: %s",
+	"スキーム %s:// は一覧の取得をサポートしていません: %s":              "scheme %s:// does not support listing: %s",
+	"スキーム %s:// は上書きの防止をサポートしていません: %s":             "scheme %s:// does not support no-clobber: %s",
+	"スキーム %s:// は削除をサポートしていません: %s":                 "scheme %s:// does not support deletion: %s",
+	"スキーム %s:// は情報の取得をサポートしていません: %s":              "scheme %s:// does not support stat: %s",
+	"スキーム %s:// は書き込みをサポートしていません: %s":               "scheme %s:// does not support writes: %s",
+	"スキーム %s:// は書き込み先の削除をサポートしていません":               "scheme %s:// does not support deleting the destination",
+	"スキーム %s:// は読み込みをサポートしていません: %s":               "scheme %s:// does not support reads: %s",
+	"ダウンロードが中断されました (%s): %w":                       "the download was interrupted (%s): %w",
+	"ダウンロードしたサイズがコピー元と一致しません (%s): %d / %d バイト":     "the downloaded size does not match the source (%s): %d / %d bytes",
+	"ダウンロードを再開します":                                  "Resuming download",
+	"ダウンロード完了":                                      "Download completed",
+	"チェックサムの計算に失敗しました (%s): %w":                     "failed to compute the checksum (%s): %w",
+	"ディレクトリ(%s)の fsync に失敗しました: %w":                 "failed to fsync the directory (%s): %w",
+	"ディレクトリ(%s)のオープンに失敗しました: %w":                    "failed to open the directory (%s): %w",
+	"ディレクトリです":                                      "is a directory",
+	"ディレクトリではありません":                                 "not a directory",
+	"ディレクトリはダウンロードできません: %s":                        "cannot download a directory: %s",
+	"ディレクトリは分割ダウンロードできません: %s":                      "cannot download a directory in slices: %s",
+	"データ鍵の生成に失敗しました: %w":                            "failed to generate the data key: %w",
+	"ナンスの生成に失敗しました: %w":                             "failed to generate the nonce: %w",
+	"バケットが空ではないため削除できません (URI: %s): %w":             "cannot delete the bucket because it is not empty (URI: %s): %w",
+	"バケットのオブジェクトの一覧取得に失敗しました (URI: %s): %w":         "failed to list the objects in the bucket (URI: %s): %w",
+	"バケットの一覧取得に失敗しました (プロジェクト: %s): %w":             "failed to list buckets (project: %s): %w",
+	"バケットの作成に失敗しました (URI: %s): %w":                  "failed to create the bucket (URI: %s): %w",
+	"バケットの削除に失敗しました (URI: %s): %w":                  "failed to delete the bucket (URI: %s): %w",
+	"バケットは既に存在します (URI: %s): %w":                    "the bucket already exists (URI: %s): %w",
+	"バケットを一覧するプロジェクトIDを指定してください":                    "give the project ID whose buckets to list",
+	"バケットを作成するプロジェクトIDを指定してください":                    "give the project ID in which to create the bucket",
+	"バケットを空にしました":                                   "Emptied the bucket",
+	"バケット作成完了":                                      "Bucket created",
+	"バケット削除完了":                                      "Bucket deleted",
+	"パートのサイズには正の値を指定してください: %d":                     "the part size must be positive: %d",
+	"パートの書き込みに失敗しました (%s): %w":                      "failed to write the part (%s): %w",
+	"パートの確認に失敗しました (%s): %w":                        "failed to check the part (%s): %w",
+	"ファイルまたは分割のサイズが前回と異なるため、最初からアップロードします":          "The file or slice size differs from the previous run; uploading from the beginning",
+	"プロキシで許可されていない URI です: %s":                      "URI not allowed by the proxy: %s",
+	"プロキシのInputReaderが一覧の取得をサポートしていません":             "the proxy's InputReader does not support listing",
+	"プロキシのInputReaderが情報の取得をサポートしていません":             "the proxy's InputReader does not support stat",
+	"プロキシのInputReaderが範囲読み込みをサポートしていません":            "the proxy's InputReader does not support range reads",
+	"クライアントのリクエストの数が上限 (1分あたり %d 件) を超えました":         "the client exceeded its request quota (%d per minute)",
+	"プロキシの認証に失敗しました (トークンが一致しません)":                  "proxy authentication failed (token mismatch)",
+	"プロキシは \"..\" を含む URI を扱いません: %s":               "the proxy does not accept URIs containing \"..\": %s",
+	"プロキシはローカルファイルのパスを扱いません: %s":                    "the proxy does not accept local file paths: %s",
+	"プロキシへの接続の作成に失敗しました (%s): %w":                   "failed to create the connection to the proxy (%s): %w",
+	"マウントに失敗しました (%s): %w":                          "mount failed (%s): %w",
+	"ラップしたデータ鍵が大きすぎます (%d バイト)":                     "the wrapped data key is too large (%d bytes)",
+	"リモートの読み書きに失敗しました":                              "Remote read or write failed",
+	"ローカルディレクトリ(%s)の一覧取得に失敗しました: %w":                "failed to list the local directory (%s): %w",
+	"ローカルファイル(%s)がコピー元より大きいため再開できません (%d > %d バイト)": "cannot resume because the local file (%s) is larger than the source (%d > %d bytes)",
+	"ローカルファイル(%s)の fsync に失敗しました: %w":               "failed to fsync the local file (%s): %w",
+	"ローカルファイル(%s)のクローズに失敗しました: %w":                  "failed to close the local file (%s): %w",
+	"ローカルファイル(%s)のパーミッションの設定に失敗しました: %w":            "failed to set the permissions of the local file (%s): %w",
+	"ローカルファイル(%s)の作成に失敗しました: %w":                    "failed to create the local file (%s): %w",
+	"ローカルファイル(%s)の削除に失敗しました: %w":                    "failed to delete the local file (%s): %w",
+	"ローカルファイル(%s)の更新日時の設定に失敗しました: %w":               "failed to set the modification time of the local file (%s): %w",
+	"ローカルファイル(%s)の確定に失敗しました: %w":                    "failed to commit the local file (%s): %w",
+	"ローカルファイル(%s)の移動に失敗しました: %w":                    "failed to move the local file (%s): %w",
+	"ローカルファイル(%s)へのコンテンツ書き込み中にエラーが発生しました: %w":       "error while writing content to the local file (%s): %w",
+	"ローカルファイルの fsync に失敗":                           "Failed to fsync the local file",
+	"ローカルファイルのオープンに失敗しました: %w":                      "failed to open the local file: %w",
+	"ローカルファイルのシークに失敗しました: %w":                       "failed to seek in the local file: %w",
+	"ローカルファイルの作成に失敗":                                "Failed to create the local file",
+	"ローカルファイルの情報の取得に失敗しました: %w":                     "failed to get local file info: %w",
+	"ローカルファイルへのコンテンツ書き込み中にエラーが発生":                   "Error while writing content to the local file",
+	"ローカル書き込み処理完了":                                  "Local write completed",
+	"ローカル書き込み処理開始":                                  "Starting local write",
+	"上書きの防止と世代番号の前提条件は同時に指定できません: %s":               "no-clobber and generation preconditions cannot be combined: %s",
+	"不明な故障注入の設定キーです: %q":                            "unknown fault injection key: %q",
+	"世代の一覧は GCS URI (gs://) でのみ取得できます: %s":          "generations can only be listed for GCS URIs (gs://): %s",
+	"世代番号の前提条件は GCS への書き込みでのみ指定できます: %s":            "generation preconditions can only be given for writes to GCS: %s",
+	"並行アップロード用の一時オブジェクトの削除に失敗しました":                  "Failed to delete the temporary objects of the parallel upload",
+	"出力ディレクトリ(%s)の作成に失敗しました: %w":                    "failed to create the output directory (%s): %w",
+	"出力ディレクトリの作成に失敗":                                "Failed to create the output directory",
+	"分割されたパートが見つかりません (%s): %w":                     "no split parts found (%s): %w",
+	"別のファイルシステムへの移動のため、コピーしてから削除します":                "Moving to another file system; copying and then deleting",
+	"削除完了": "Deleted",
+	"同じ書き込み先が重複しています: %s":                "duplicate destination: %s",
+	"故障注入: %s がタイムアウトしました (%s): %w":      "fault injection: %s timed out (%s): %w",
+	"故障注入: %s が失敗しました (%s): %w":          "fault injection: %s failed (%s): %w",
+	"故障注入: ストリームが切断されました: %w":            "fault injection: stream disconnected: %w",
+	"故障注入の設定値が不正です (%s): %w":             "invalid fault injection value (%s): %w",
+	"暗号の初期化に失敗しました: %w":                  "failed to initialize the cipher: %w",
+	"暗号化できる内容の大きさの上限を超えました":              "the content exceeds the maximum size that can be encrypted",
+	"暗号鍵は %d バイトである必要があります (%d バイト)":     "the encryption key must be %d bytes (got %d bytes)",
+	"書き込みが終了しました":                        "the write has finished",
+	"書き込み先が指定されていません":                    "no destination given",
+	"書き込み先のチェックサムを検証しました":                "Verified the destination checksum",
+	"書き込み処理完了":                           "Write completed",
+	"書き込み処理開始":                           "Starting write",
+	"書き込む内容の読み込みに失敗しました (%s): %w":        "failed to read the content to write (%s): %w",
+	"最初のメッセージで書き込み先を指定してください":            "give the destination in the first message",
+	"検査結果のメタデータの記録に失敗しました: %w":           "failed to record the scan result metadata: %w",
+	"無効なAzure URI形式: 'az://'で始まる必要があります": "invalid Azure URI: must start with 'az://'",
+	"無効なAzure URI形式です: %s (Blob名が空です)":   "invalid Azure URI: %s (the blob name is empty)",
+	"無効なGCS URI形式: 'gs://'で始まる必要があります":   "invalid GCS URI: must start with 'gs://'",
+	"無効なGCS URI形式です: %s (gs://bucket-name/object-name の形式で指定してください。スラッシュの数が不正です)":                 "invalid GCS URI: %s (use the form gs://bucket-name/object-name; the number of slashes is wrong)",
+	"無効なGCS URI形式です: %s (オブジェクト名が空です)":                                                            "invalid GCS URI: %s (the object name is empty)",
+	"無効なGCS URI形式です: %s (オブジェクト名が空です。このInputReaderは単一のGCSオブジェクトの読み込みに特化しており、ディレクトリパスはサポートしていません)": "invalid GCS URI: %s (the object name is empty; this InputReader reads single GCS objects and does not support directory paths)",
+	"無効なGCS URI形式です: %s (バケット名が空です)":                                                              "invalid GCS URI: %s (the bucket name is empty)",
+	"無効なGCS URI形式です: %s -> %s (オブジェクト名が空です)":                                                      "invalid GCS URI: %s -> %s (the object name is empty)",
+	"無効なS3 URI形式: 's3://'で始まる必要があります":                                                             "invalid S3 URI: must start with 's3://'",
+	"無効なS3 URI形式です: %s (オブジェクトキーが空です)":                                                            "invalid S3 URI: %s (the object key is empty)",
+	"無効なSFTP URI形式: 'sftp://'で始まる必要があります":                                                         "invalid SFTP URI: must start with 'sftp://'",
+	"無効なSFTPの接続先です: %s":                                                                           "invalid SFTP host: %s",
+	"無効な故障注入の設定です: %q (key=value の形式で指定してください)":                                                   "invalid fault injection setting: %q (use key=value)",
+	"環境変数 %s が設定されていません":                                                                          "environment variable %s is not set",
+	"確率は 0.0〜1.0 の範囲で指定してください: %v":                                                                "the probability must be between 0.0 and 1.0: %v",
+	"秘密鍵(%s)の解析に失敗しました: %w":                                                                       "failed to parse the private key (%s): %w",
+	"秘密鍵(%s)の読み込みに失敗しました: %w":                                                                     "failed to read the private key (%s): %w",
+	"移動元のローカルファイル(%s)の削除に失敗しました: %w":                                                              "failed to delete the source local file (%s): %w",
+	"移動元の削除に失敗しました (URI: %s): %w":                                                                 "failed to delete the source (URI: %s): %w",
+	"移動元と移動先が同じです: %s":                                                                            "the source and the destination are the same: %s",
+	"移動完了": "Move completed",
+	"範囲 %d-%d の読み込みが途中で終了しました (%s): %d / %d バイト": "reading range %d-%d ended early (%s): %d / %d bytes",
+	"範囲 %d-%d の読み込みに失敗しました (%s): %w":             "failed to read range %d-%d (%s): %w",
+	"範囲読み込みのオフセットが負です (%s): %d":                  "negative range read offset (%s): %d",
+	"範囲読み込みの読み飛ばしに失敗しました (%s): %w":               "failed to skip to the range read offset (%s): %w",
+	"署名付きURLの有効期間は0より長く7日以内で指定してください: %s":        "the signed URL lifetime must be greater than 0 and at most 7 days: %s",
+	"署名付きURLの生成に失敗しました (URI: %s): %w":            "failed to generate the signed URL (URI: %s): %w",
+	"記録するリクエストボディの読み込みに失敗しました: %w":               "failed to read the request body to record: %w",
+	"記録するレスポンスボディの読み込みに失敗しました: %w":               "failed to read the response body to record: %w",
+	"記録用トランスポートの初期化に失敗しました: %w":                  "failed to initialize the recording transport: %w",
+	"許可されていないContent-Typeです (%s): %s":            "Content-Type not allowed (%s): %s",
+	"転送がバリデータにより拒否されました":                         "the transfer was rejected by a validator",
+	"転送に失敗したため再試行します":                            "Transfer failed; retrying",
+	"追記用の一時オブジェクトの削除に失敗しました":                     "Failed to delete the temporary object used for appending",
+	"通常のファイル以外のエントリを読み飛ばします":                     "Skipping an entry that is not a regular file",
+	"連結するソースが指定されていません":                          "no sources to compose",
+	"連結完了": "Compose completed",
+	"InputReaderの作成に失敗しました: %w":         "failed to create the InputReader: %w",
+	"OutputWriterの作成に失敗しました: %w":        "failed to create the OutputWriter: %w",
+	"入力ストリームのオープンに失敗しました (%s): %w":      "failed to open the input stream (%s): %w",
+	"出力先への書き込みに失敗しました (%s): %w":         "failed to write to the destination (%s): %w",
+	"サーバー側のコピーに失敗しました (%s -> %s): %w":   "server-side copy failed (%s -> %s): %w",
+	"コピー元の情報の取得に失敗しました (%s): %w":        "failed to get source info (%s): %w",
+	"コピー元は展開されて読み込まれたため、チェックサムを検証できません": "Cannot verify the checksum because the source was decompressed while reading",
+	"コピー元の内容の検証に失敗しました (%s): %w":        "failed to verify the source content (%s): %w",
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// --format の値 (コマンドの結果の出力形式)
+const (
+	formatText = "text" // 人が読むための形式 (既定)
+	formatJSON = "json" // 1件ごとに1行の JSON (NDJSON) で標準出力へ出力する
+)
+
+// 結果レコードの status の値
+const (
+	statusCopied      = "copied"      // コピーした
+	statusOverwritten = "overwritten" // 既存の書き込み先を上書きした (--force)
+	statusSkipped     = "skipped"     // 書き込み先が既に存在するためコピーしなかった (--no-clobber)
+	statusIdentical   = "identical"   // 書き込み先の内容が同じためコピーしなかった (--skip-identical、sync)
+	statusMoved       = "moved"       // 移動した
+	statusDeleted     = "deleted"     // 削除した
+	statusArchived    = "archived"    // アーカイブに追加した (rarchive)
+	statusExtracted   = "extracted"   // アーカイブから展開した (rextract)
+	statusDryRun      = "dry-run"     // --dry-run のため実行しなかった
+	statusCanceled    = "canceled"    // 先行する転送が失敗したため開始しなかった (rbatch)
+	statusFailed      = "failed"      // 失敗した (error に理由を設定する)
+	statusSummary     = "summary"     // 結果の最後に出力する集計 (フックを指定した場合の hook_failures)
+)
+
+// validateFormat は、--format の値を検証します。
+func validateFormat(format string) error {
+	switch format {
+	case formatText, formatJSON:
+		return nil
+	default:
+		return usageError(fmt.Errorf(tr("--format には text または json を指定してください: %s"), format))
+	}
+}
+
+// jsonOutput は、cmd のコマンドツリーで --format json が指定されたかどうかを返します。
+func jsonOutput(cmd *cobra.Command) bool {
+	return appFlagsFrom(cmd.Context()).Format == formatJSON
+}
+
+// writeJSONLine は、v を1行の JSON として w へ出力します。
+func writeJSONLine(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// resultRecord は、--format json で転送・移動・削除したファイルごとに出力するレコードです。
+type resultRecord struct {
+	Source      string  `json:"source,omitempty"`
+	Destination string  `json:"destination,omitempty"`
+	URI         string  `json:"uri,omitempty"` // 削除したファイル (source と destination は省略)
+	Status      string  `json:"status"`
+	Bytes       *int64  `json:"bytes,omitempty"`  // 書き込み先のサイズ (取得できない場合は省略)
+	CRC32C      *uint32 `json:"crc32c,omitempty"` // 書き込み先の CRC32C チェックサム (取得できない場合は省略)
+	Error       string  `json:"error,omitempty"`
+}
+
+// resultWriter は、--format json で処理したファイルごとの結果を標準出力へ出力し、--manifest のマニフェストへ追記し、フックを実行します。
+// どちらも指定されていない場合は nil で、nil のメソッドは何もしません (人が読むためのログは従来どおり標準エラー出力へ出力されます)。
+// 複数のゴルーチンから並行して使用できます。
+type resultWriter struct {
+	out      io.Writer       // --format text の場合は nil
+	text     io.Writer       // 結果を1件ごとに1行のテキストで表示する出力先 (rbatch の --format text)。nil の場合は表示しない
+	manifest *manifestWriter // --manifest を指定しない場合は nil
+	hooks    *hookRunner     // --on-success、--on-failure、--webhook を指定しない場合は nil
+	stater   remoteio.Stater // 書き込み先のサイズとチェックサムの取得に使用する (nil の場合は省略する)
+
+	mu sync.Mutex
+}
+
+// newResultWriter は、--format json が指定された場合に、cmd の標準出力へ出力する resultWriter を作成します。
+// reader が Stater を満たす場合は、コピーした書き込み先のサイズと CRC32C を取得してレコードに含めます。
+func newResultWriter(cmd *cobra.Command, reader remoteio.InputReader) *resultWriter {
+	if !jsonOutput(cmd) {
+		return nil
+	}
+	stater, _ := reader.(remoteio.Stater)
+	return &resultWriter{out: cmd.OutOrStdout(), stater: stater}
+}
+
+// newTextResultWriter は、--format json が指定されていない場合に、結果を1件ごとに1行のテキストで cmd の標準出力へ表示する resultWriter を作成します。
+// --format json の場合は newResultWriter と同じです。
+func newTextResultWriter(cmd *cobra.Command, reader remoteio.InputReader) *resultWriter {
+	if jsonOutput(cmd) {
+		return newResultWriter(cmd, reader)
+	}
+	return &resultWriter{text: cmd.OutOrStdout()}
+}
+
+// transferred は、src から dst への転送の結果を出力します。
+// status が statusCopied、statusOverwritten または statusMoved の場合は、書き込み先のサイズと CRC32C を取得して含めます。
+func (w *resultWriter) transferred(ctx context.Context, src, dst, status string) {
+	if w == nil {
+		return
+	}
+	rec := resultRecord{Source: src, Destination: dst, Status: status}
+	if w.stater != nil && (status == statusCopied || status == statusOverwritten || status == statusMoved) {
+		if info, err := w.stater.Stat(ctx, dst); err == nil {
+			rec.Bytes = &info.Size
+			rec.CRC32C = info.CRC32C
+		}
+	}
+	w.write(rec)
+}
+
+// archiveEntry は、アーカイブに追加した、またはアーカイブから展開した src から dst へのファイル (size バイト) を出力します。
+func (w *resultWriter) archiveEntry(src, dst, status string, size int64) {
+	if w == nil {
+		return
+	}
+	w.write(resultRecord{Source: src, Destination: dst, Status: status, Bytes: &size})
+}
+
+// failed は、src から dst への転送が err で失敗したことを出力します。
+func (w *resultWriter) failed(src, dst string, err error) {
+	if w == nil {
+		return
+	}
+	w.write(resultRecord{Source: src, Destination: dst, Status: statusFailed, Error: err.Error()})
+}
+
+// canceled は、src から dst への転送を、先行する転送の失敗により開始しなかったことを出力します。
+func (w *resultWriter) canceled(src, dst string) {
+	if w == nil {
+		return
+	}
+	w.write(resultRecord{Source: src, Destination: dst, Status: statusCanceled})
+}
+
+// deleted は、uri の削除の結果を出力します。err が nil でない場合は失敗として出力します。
+func (w *resultWriter) deleted(uri string, err error) {
+	if w == nil {
+		return
+	}
+	rec := resultRecord{URI: uri, Status: statusDeleted}
+	if err != nil {
+		rec.Status = statusFailed
+		rec.Error = err.Error()
+	}
+	w.write(rec)
+}
+
+// write は、rec を1行の JSON として出力し、マニフェストには記録した日時を加えて追記します。
+// フックは、他の転送の結果の出力を待たせないよう、ロックの外で実行します。
+func (w *resultWriter) write(rec resultRecord) {
+	w.output(rec)
+	if w.hooks != nil {
+		w.hooks.fire(rec)
+	}
+}
+
+// output は、rec を標準出力とマニフェストへ出力します。
+func (w *resultWriter) output(rec resultRecord) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.out != nil {
+		writeJSONLine(w.out, rec)
+	}
+	if w.text != nil {
+		fmt.Fprintln(w.text, rec.text())
+	}
+	if w.manifest != nil {
+		writeJSONLine(w.manifest, manifestRecord{Time: time.Now().UTC(), resultRecord: rec})
+	}
+}
+
+// text は、rec を人が読むための1行のテキストにします。
+func (rec resultRecord) text() string {
+	line := rec.Status + ": " + rec.URI
+	if rec.URI == "" {
+		line = rec.Status + ": " + rec.Source + " -> " + rec.Destination
+	}
+	if rec.Error != "" {
+		line += ": " + rec.Error
+	}
+	return line
+}
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cloud.google.com/go/storage"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// --progress の値 (進捗の出力形式)
+const (
+	progressFormatJSON = "json" // NDJSON形式の進捗レコード
+	progressFormatBar  = "bar"  // 端末向けのプログレスバー
+)
+
+// defaultProgressInterval は、--progress-interval を省略した場合の format ごとの出力間隔を返します。
+// 端末に表示するバーは、滑らかに見えるよう短い間隔で更新します。
+func defaultProgressInterval(format string) time.Duration {
+	if format == progressFormatBar {
+		return 200 * time.Millisecond
+	}
+	return time.Second
+}
+
+// progressBarWidth は、プログレスバーの棒の部分の文字数です。
+const progressBarWidth = 30
+
+// progressRecord は、--progress=json で1行ずつ出力される進捗レコードです。
+type progressRecord struct {
+	Time       time.Time `json:"time"`
+	File       string    `json:"file"`
+	Bytes      int64     `json:"bytes"`
+	TotalBytes int64     `json:"total_bytes"`       // 総バイト数が不明な場合は -1
+	Rate       float64   `json:"rate"`              // 開始からの平均転送速度 (バイト/秒)
+	ETASeconds *float64  `json:"eta_sec,omitempty"` // 総バイト数が不明な場合は省略
+	Done       bool      `json:"done"`
+}
+
+// progressReporter は、転送中のバイト数を計測し、一定間隔で NDJSON の進捗レコードまたはプログレスバーを出力します。
+type progressReporter struct {
+	format   string // progressFormatJSON または progressFormatBar
+	out      io.Writer
+	closer   io.Closer // --progress-file で開いたファイル (標準エラー出力の場合は nil)
+	interval time.Duration
+
+	mu      sync.Mutex
+	enc     *json.Encoder
+	file    string
+	total   int64
+	start   time.Time
+	bytes   atomic.Int64
+	stop    chan struct{}
+	stopped sync.WaitGroup
+}
+
+// newProgressReporter は、進捗の出力先を開いて format 形式の progressReporter を作成します。
+// path が空の場合は標準エラー出力へ、それ以外は指定されたファイル (名前付きパイプを含む) へ出力します。
+func newProgressReporter(format, path string, interval time.Duration) (*progressReporter, error) {
+	p := &progressReporter{format: format, out: os.Stderr, interval: interval}
+	if path != "" {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf(tr("進捗出力先(%s)のオープンに失敗しました")+": %w", path, err)
+		}
+		p.out = f
+		p.closer = f
+	}
+	p.enc = json.NewEncoder(p.out)
+	return p, nil
+}
+
+// Track は、r から読み込まれたバイト数を計測するリーダーを返し、定期的な進捗出力を開始します。
+// total が不明な場合は -1 を指定します。
+func (p *progressReporter) Track(file string, total int64, r io.Reader) io.Reader {
+	p.Start(file, total)
+	return p.Wrap(r)
+}
+
+// Wrap は、r から読み込まれたバイト数を進捗に加算するリーダーを返します。
+// 複数のファイルをまとめて1つの進捗として計測する場合は、Start の後にファイルごとに呼び出します。
+func (p *progressReporter) Wrap(r io.Reader) io.Reader {
+	return &countingReader{r: r, n: &p.bytes}
+}
+
+// Add は、ストリームを経由せずに転送されたバイト数 (サーバー側のコピーなど) を進捗に加算します。
+func (p *progressReporter) Add(n int64) {
+	if n > 0 {
+		p.bytes.Add(n)
+	}
+}
+
+// progressFunc は、1つのファイルの転送で通知された進捗 (ストリームごとの累計のバイト数) を、進捗に加算する remoteio.ProgressFunc を返します。
+// 再試行などで新しいストリームの通知が始まった場合は、その先頭から加算します。
+func (p *progressReporter) progressFunc() remoteio.ProgressFunc {
+	var last int64
+	return func(done, _ int64) {
+		if done < last {
+			last = 0
+		}
+		p.Add(done - last)
+		last = done
+	}
+}
+
+// WrapWriterAt は、w の各位置へ書き込まれたバイト数を進捗に加算する io.WriterAt を返します。
+// 範囲ごとに並行して書き込む分割ダウンロードの計測に使用します。
+func (p *progressReporter) WrapWriterAt(w io.WriterAt) io.WriterAt {
+	return &countingWriterAt{w: w, n: &p.bytes}
+}
+
+// Start は、定期的な進捗出力を開始します。total が不明な場合は -1 を指定します。
+func (p *progressReporter) Start(file string, total int64) {
+	p.file = file
+	p.total = total
+	p.start = time.Now()
+	p.stop = make(chan struct{})
+
+	p.stopped.Add(1)
+	go func() {
+		defer p.stopped.Done()
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.emit(false)
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Finish は、定期出力を停止して最終レコード (done: true) またはバーを出力し、出力先を閉じます。
+func (p *progressReporter) Finish() error {
+	if p.stop != nil {
+		close(p.stop)
+		p.stopped.Wait()
+		p.emit(true)
+	}
+	if p.closer != nil {
+		return p.closer.Close()
+	}
+	return nil
+}
+
+// emit は、現在の進捗を1レコードとして出力します。
+func (p *progressReporter) emit(done bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	bytes := p.bytes.Load()
+	rec := progressRecord{
+		Time:       time.Now(),
+		File:       p.file,
+		Bytes:      bytes,
+		TotalBytes: p.total,
+		Done:       done,
+	}
+	if elapsed := time.Since(p.start).Seconds(); elapsed > 0 {
+		rec.Rate = float64(bytes) / elapsed
+	}
+	if p.total >= 0 && rec.Rate > 0 {
+		eta := float64(p.total-bytes) / rec.Rate
+		if eta < 0 {
+			eta = 0
+		}
+		rec.ETASeconds = &eta
+	}
+	// 進捗出力の失敗は転送自体を失敗させない
+	if p.format == progressFormatBar {
+		p.renderBar(rec)
+		return
+	}
+	_ = p.enc.Encode(rec)
+}
+
+// renderBar は、rec をプログレスバーとして同じ行に上書き表示します。完了時は改行します。
+// 総バイト数が不明な場合は、バーと残り時間を省略します。
+func (p *progressReporter) renderBar(rec progressRecord) {
+	line := fmt.Sprintf("%s/s", formatByteSize(int64(rec.Rate)))
+	if rec.TotalBytes > 0 {
+		ratio := min(float64(rec.Bytes)/float64(rec.TotalBytes), 1)
+		filled := int(ratio * progressBarWidth)
+		bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+		if filled > 0 && filled < progressBarWidth {
+			bar = bar[:filled-1] + ">" + bar[filled:]
+		}
+		line = fmt.Sprintf("[%s] %5.1f%% %s/%s %s", bar, ratio*100, formatByteSize(rec.Bytes), formatByteSize(rec.TotalBytes), line)
+		if rec.ETASeconds != nil && !rec.Done {
+			line += " ETA " + (time.Duration(*rec.ETASeconds) * time.Second).String()
+		}
+	} else {
+		line = fmt.Sprintf("%s %s", formatByteSize(rec.Bytes), line)
+	}
+	// 前回の表示の方が長い場合に残らないよう、行末までを消去する
+	fmt.Fprintf(p.out, "\r%s %s\x1b[K", p.file, line)
+	if rec.Done {
+		fmt.Fprintln(p.out)
+	}
+}
+
+// countingReader は、読み込んだバイト数を n に加算する io.Reader です。
+type countingReader struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// countingWriterAt は、書き込んだバイト数を n に加算する io.WriterAt です。
+type countingWriterAt struct {
+	w io.WriterAt
+	n *atomic.Int64
+}
+
+func (c *countingWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	n, err := c.w.WriteAt(p, off)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// streamSize は、開かれた入力ストリームの総バイト数を返します。不明な場合は -1 を返します。
+func streamSize(rc io.ReadCloser) int64 {
+	switch s := rc.(type) {
+	case *os.File:
+		if fi, err := s.Stat(); err == nil && fi.Mode().IsRegular() {
+			return fi.Size()
+		}
+	case *storage.Reader:
+		return s.Attrs.Size
+	}
+	return -1
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/shouni/go-remote-io/pkg/factory"
+	"github.com/shouni/go-remote-io/pkg/proxy"
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// proxydFlags は proxyd コマンド固有のフラグを保持します。
+type proxydFlags struct {
+	Listen  string   // --listen 待ち受けるアドレス
+	TLSCert string   // --tls-cert TLS のサーバー証明書ファイル
+	TLSKey  string   // --tls-key TLS の秘密鍵ファイル
+	Allow   []string // --allow 読み書きを許可する URI の接頭辞
+
+	ClientMaxConcurrent     int    // --client-max-concurrent クライアントごとの同時実行数の上限
+	ClientBandwidth         string // --client-bandwidth クライアントごとの帯域の上限 (1秒あたりのサイズ)
+	ClientRequestsPerMinute int    // --client-requests-per-minute クライアントごとの1分あたりのリクエストの数の上限
+}
+
+// newProxydCmd は 'proxyd' サブコマンドを生成します。
+func newProxydCmd() *cobra.Command {
+	var flags proxydFlags
+
+	proxydCmd := &cobra.Command{
+		Use:   "proxyd",
+		Short: "リモートの URI の読み書きを中継する gRPC のプロキシを起動します。",
+		Long: `このマシンの認証情報 (GCP のデフォルト認証情報、AWS・Azure の環境変数など) で、クライアントの代わりにリモートの URI を読み書きする gRPC のプロキシを起動します。
+認証情報を配置できないマシンでは、--proxy にこのプロキシのアドレスを指定すると、リモートの URI の読み込み・書き込み・情報の取得・一覧をプロキシ経由で行います。
+環境変数 REMOTEIO_PROXY_TOKEN を指定すると、同じトークンを指定したクライアントのみを受け付けます。トークンを盗聴されないよう、--tls-cert と --tls-key で TLS を有効にしてください。
+クライアント (テナント) ごとに異なるトークンを使用する場合は、REMOTEIO_PROXY_TOKEN にカンマ区切りで複数のトークンを指定します。
+ループバック以外のアドレスで待ち受ける場合は、TLS の有無にかかわらずトークンの指定が必要です (TLS は通信を暗号化しますが、クライアントを認証しません)。
+--allow を指定すると、読み書きできる URI をスキーム (gs:// など) またはバケット・ホストの接頭辞 (gs://my-bucket/ など) に制限します。
+--client-max-concurrent、--client-bandwidth、--client-requests-per-minute は、1つのクライアントの大量の転送がプロキシを占有しないよう、
+クライアント (トークンを指定した場合はトークン、それ以外は接続元の IP アドレス) ごとに同時実行数、帯域、リクエストの数を制限します。
+プロキシを実行するマシンのファイルを公開しないよう、ローカルファイルのパスは拒否します。Ctrl+C で終了します。`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProxyd(cmd, &flags)
+		},
+	}
+
+	proxydCmd.Flags().StringVar(&flags.Listen, "listen", "127.0.0.1:7070", "待ち受けるアドレス (host:port。すべてのインターフェースで待ち受ける場合は :7070)")
+	proxydCmd.Flags().StringVar(&flags.TLSCert, "tls-cert", "", "TLS のサーバー証明書ファイル (PEM。--tls-key と併せて指定)")
+	proxydCmd.Flags().StringVar(&flags.TLSKey, "tls-key", "", "TLS の秘密鍵ファイル (PEM)")
+	proxydCmd.Flags().StringSliceVar(&flags.Allow, "allow", nil, "読み書きを許可する URI のスキームまたは接頭辞 (例: gs://my-bucket/、sftp://files.example.com/)。複数指定可 (省略時はすべてのリモートの URI を許可)")
+	proxydCmd.Flags().IntVar(&flags.ClientMaxConcurrent, "client-max-concurrent", 0, "クライアントごとに同時に処理するリクエストの数の上限。超えたリクエストは待機 (0 の場合は無制限)")
+	proxydCmd.Flags().StringVar(&flags.ClientBandwidth, "client-bandwidth", "", "クライアントごとの読み込みと書き込みの合計の帯域の上限 (1秒あたり。例: 10MiB。省略時は無制限)")
+	proxydCmd.Flags().IntVar(&flags.ClientRequestsPerMinute, "client-requests-per-minute", 0, "クライアントごとの1分あたりのリクエストの数の上限。超えたリクエストは RESOURCE_EXHAUSTED で拒否 (0 の場合は無制限)")
+	proxydCmd.MarkFlagsRequiredTogether("tls-cert", "tls-key")
+
+	markURIFlags(proxydCmd, "allow")
+	return proxydCmd
+}
+
+// runProxyd は proxyd コマンドの実行ロジックです。
+func runProxyd(cmd *cobra.Command, flags *proxydFlags) error {
+	ctx := cmd.Context()
+
+	// 1. ClientFactory とリーダー・ライターの取得 (DI)
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	inputReader, err := clientFactory.NewInputReader()
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+	writer, err := clientFactory.NewOutputWriter()
+	if err != nil {
+		return fmt.Errorf(tr("OutputWriterの作成に失敗しました")+": %w", err)
+	}
+
+	// 2. gRPC のサーバーを構成する
+	var serverOpts []proxy.ServerOption
+	tokens := splitTokens(os.Getenv(proxy.TokenEnv))
+	if err := checkListenAuth(flags.Listen, tokens); err != nil {
+		return err
+	}
+	if len(tokens) > 0 {
+		serverOpts = append(serverOpts, proxy.WithRequiredToken(tokens...))
+	} else {
+		logger().Warn(tr("認証トークンが指定されていないため、接続できるすべてのクライアントの読み書きを受け付けます"), slog.String("env", proxy.TokenEnv))
+	}
+	if len(flags.Allow) > 0 {
+		for _, prefix := range flags.Allow {
+			if remoteio.SchemeOf(prefix) == "" {
+				return usageError(fmt.Errorf(tr("--allow にはリモートの URI のスキームまたは接頭辞を指定してください: %s"), prefix))
+			}
+		}
+		serverOpts = append(serverOpts, proxy.WithAllowedPrefixes(flags.Allow...))
+	} else {
+		logger().Warn(tr("--allow が指定されていないため、プロキシの認証情報でアクセスできるすべてのリモートの URI の読み書きを受け付けます"))
+	}
+	limits, err := flags.clientLimits()
+	if err != nil {
+		return err
+	}
+	serverOpts = append(serverOpts, proxy.WithClientLimits(limits))
+	var grpcOpts []grpc.ServerOption
+	if flags.TLSCert != "" {
+		cert, err := tls.LoadX509KeyPair(flags.TLSCert, flags.TLSKey)
+		if err != nil {
+			return fmt.Errorf(tr("TLS の証明書の読み込みに失敗しました")+": %w", err)
+		}
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewServerTLSFromCert(&cert)))
+	}
+	server := proxy.NewServer(inputReader, writer, serverOpts...).NewGRPCServer(grpcOpts...)
+
+	// 3. 中断されるまで待ち受ける
+	lis, err := net.Listen("tcp", flags.Listen)
+	if err != nil {
+		return fmt.Errorf(tr("待ち受けの開始に失敗しました (%s)")+": %w", flags.Listen, err)
+	}
+	logger().Info(tr("プロキシの待ち受け開始"), slog.String("address", lis.Addr().String()), slog.Bool("tls", flags.TLSCert != ""), slog.Bool("auth", len(tokens) > 0))
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(lis)
+	}()
+	select {
+	case err := <-served:
+		return fmt.Errorf(tr("プロキシが終了しました")+": %w", err)
+	case <-ctx.Done():
+		// 処理中の読み書きを中断して終了する
+		server.Stop()
+		return ctx.Err()
+	}
+}
+
+// splitTokens は、環境変数 REMOTEIO_PROXY_TOKEN のカンマ区切りのトークンを分割します。空のトークンは除きます。
+func splitTokens(env string) []string {
+	var tokens []string
+	for token := range strings.SplitSeq(env, ",") {
+		if token = strings.TrimSpace(token); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// clientLimits は、--client-max-concurrent などのフラグを、プロキシのクライアントごとの上限にします。
+func (f *proxydFlags) clientLimits() (proxy.ClientLimits, error) {
+	if f.ClientMaxConcurrent < 0 || f.ClientRequestsPerMinute < 0 {
+		return proxy.ClientLimits{}, usageError(errors.New(tr("--client-max-concurrent と --client-requests-per-minute には 0 以上の値を指定してください")))
+	}
+	limits := proxy.ClientLimits{MaxConcurrent: f.ClientMaxConcurrent, RequestsPerMinute: f.ClientRequestsPerMinute}
+	if f.ClientBandwidth != "" {
+		bps, err := parseByteSize(f.ClientBandwidth)
+		if err != nil {
+			return proxy.ClientLimits{}, err
+		}
+		limits.BytesPerSecond = bps
+	}
+	return limits, nil
+}
+
+// checkListenAuth は、ループバック以外のアドレス listen で待ち受ける場合に、認証トークン tokens が指定されていることを確認します。
+// サーバーの TLS は通信を暗号化しますが、クライアントを識別しないため、TLS を有効にした場合もトークンを要求します。
+func checkListenAuth(listen string, tokens []string) error {
+	if len(tokens) == 0 && !isLoopbackAddress(listen) {
+		return usageError(fmt.Errorf(tr("ループバック以外のアドレス (%s) で待ち受ける場合は、環境変数 %s の認証トークンを指定してください (TLS はクライアントを認証しません)"), listen, proxy.TokenEnv))
+	}
+	return nil
+}
+
+// isLoopbackAddress は、待ち受けるアドレス listen (host:port) がループバックアドレスのみで待ち受けるかどうかを返します。
+// ホストを省略した場合 (:7070) や、名前を解決できない場合は false を返します。
+func isLoopbackAddress(listen string) bool {
+	addr, err := net.ResolveTCPAddr("tcp", listen)
+	if err != nil || addr.IP == nil {
+		return false
+	}
+	return addr.IP.IsLoopback()
+}
+
+// newProxyFactory は、--proxy で指定したプロキシ経由で読み書きする Factory を作成します。
+func newProxyFactory(appFlags *AppFlags) (factory.Factory, error) {
+	var opts []proxy.DialOption
+	if token := os.Getenv(proxy.TokenEnv); token != "" {
+		opts = append(opts, proxy.WithToken(token))
+	}
+	if appFlags.ProxyTLS || appFlags.ProxyCA != "" {
+		cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+		if appFlags.ProxyCA != "" {
+			pem, err := os.ReadFile(appFlags.ProxyCA)
+			if err != nil {
+				return nil, fmt.Errorf(tr("CA 証明書の読み込みに失敗しました")+": %w", err)
+			}
+			cfg.RootCAs = x509.NewCertPool()
+			if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, errors.New(tr("CA 証明書ファイルに PEM 形式の証明書が含まれていません"))
+			}
+		}
+		opts = append(opts, proxy.WithTLSConfig(cfg))
+	}
+	return factory.NewProxyFactory(appFlags.Proxy, opts...)
+}
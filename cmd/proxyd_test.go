@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/shouni/go-remote-io/pkg/proxy"
+)
+
+func TestCheckListenAuth(t *testing.T) {
+	tests := []struct {
+		name    string
+		listen  string
+		tokens  []string
+		wantErr bool
+	}{
+		{name: "ループバックでトークンなし", listen: "127.0.0.1:7070"},
+		{name: "IPv6 ループバックでトークンなし", listen: "[::1]:7070"},
+		{name: "localhost でトークンなし", listen: "localhost:7070"},
+		{name: "すべてのインターフェースでトークンなし", listen: ":7070", wantErr: true},
+		{name: "外部アドレスでトークンなし", listen: "0.0.0.0:7070", wantErr: true},
+		{name: "すべてのインターフェースでトークンあり", listen: ":7070", tokens: []string{"secret"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkListenAuth(tt.listen, tt.tokens)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkListenAuth(%q) = %v, wantErr %v", tt.listen, err, tt.wantErr)
+			}
+			if err != nil && ExitCode(err) != ExitUsage {
+				t.Errorf("ExitCode() = %d, want %d", ExitCode(err), ExitUsage)
+			}
+		})
+	}
+}
+
+func TestSplitTokens(t *testing.T) {
+	tests := []struct {
+		env  string
+		want []string
+	}{
+		{env: "", want: nil},
+		{env: "secret", want: []string{"secret"}},
+		{env: "tenant-a, tenant-b,,", want: []string{"tenant-a", "tenant-b"}},
+	}
+	for _, tt := range tests {
+		if got := splitTokens(tt.env); !slices.Equal(got, tt.want) {
+			t.Errorf("splitTokens(%q) = %q, want %q", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestProxydClientLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   proxydFlags
+		want    proxy.ClientLimits
+		wantErr bool
+	}{
+		{name: "指定なし"},
+		{
+			name:  "すべての上限",
+			flags: proxydFlags{ClientMaxConcurrent: 4, ClientBandwidth: "10MiB", ClientRequestsPerMinute: 600},
+			want:  proxy.ClientLimits{MaxConcurrent: 4, BytesPerSecond: 10 << 20, RequestsPerMinute: 600},
+		},
+		{name: "不正な帯域", flags: proxydFlags{ClientBandwidth: "fast"}, wantErr: true},
+		{name: "負の同時実行数", flags: proxydFlags{ClientMaxConcurrent: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.flags.clientLimits()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("clientLimits() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && ExitCode(err) != ExitUsage {
+				t.Errorf("ExitCode() = %d, want %d", ExitCode(err), ExitUsage)
+			}
+			if got != tt.want {
+				t.Errorf("clientLimits() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
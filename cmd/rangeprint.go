@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// rangeBlockSize は、rhead と rtail が行の区切りを探すために1回に読み込むサイズです。
+const rangeBlockSize = 64 * 1024
+
+// rangePrinter は、rhead と rtail が1つのファイルまたはオブジェクトの一部の範囲のみを読み込んで out へ書き出すための状態です。
+type rangePrinter struct {
+	ctx    context.Context
+	ranger remoteio.RangeInputReader
+	stater remoteio.Stater
+	uri    string
+	out    io.Writer
+}
+
+// newRangePrinter は、reader が範囲読み込みと情報の取得をサポートしている場合に rangePrinter を作成します。
+func newRangePrinter(ctx context.Context, reader remoteio.InputReader, uri string, out io.Writer) (*rangePrinter, error) {
+	ranger, ok := reader.(remoteio.RangeInputReader)
+	if !ok {
+		return nil, errors.New(tr("InputReaderが範囲読み込みをサポートしていません"))
+	}
+	stater, ok := reader.(remoteio.Stater)
+	if !ok {
+		return nil, errors.New(tr("InputReaderが情報の取得をサポートしていません"))
+	}
+	return &rangePrinter{ctx: ctx, ranger: ranger, stater: stater, uri: uri, out: out}, nil
+}
+
+// stat は、ファイルまたはオブジェクトの情報を返します。
+func (p *rangePrinter) stat() (remoteio.ObjectInfo, error) {
+	info, err := p.stater.Stat(p.ctx, p.uri)
+	if err != nil {
+		return remoteio.ObjectInfo{}, fmt.Errorf(tr("情報の取得に失敗しました (%s)")+": %w", p.uri, err)
+	}
+	if info.IsPrefix {
+		return remoteio.ObjectInfo{}, fmt.Errorf(tr("ディレクトリは表示できません: %s"), p.uri)
+	}
+	return info, nil
+}
+
+// rangeURI は、info の範囲を読み込む URI を返します。GCS では、情報を取得した世代を読み込むよう世代番号を指定します。
+func (p *rangePrinter) rangeURI(info remoteio.ObjectInfo) string {
+	if !remoteio.IsGCSURI(p.uri) || info.Generation <= 0 {
+		return p.uri
+	}
+	if _, generation := remoteio.SplitGCSGeneration(p.uri); generation > 0 {
+		return p.uri
+	}
+	return remoteio.GCSGenerationURI(p.uri, info.Generation)
+}
+
+// lineStart は、info の末尾の n 行の開始位置を返します。末尾から rangeBlockSize ずつ遡って改行を数えます。
+// 末尾の改行は、最後の行の終端として数えません。
+func (p *rangePrinter) lineStart(info remoteio.ObjectInfo, n int) (int64, error) {
+	if n == 0 {
+		return info.Size, nil
+	}
+	buf := make([]byte, rangeBlockSize)
+	count := 0
+	for end := info.Size; end > 0; {
+		start := max(end-rangeBlockSize, 0)
+		block := buf[:end-start]
+		if err := p.readAt(info, start, block); err != nil {
+			return 0, err
+		}
+		for i := len(block) - 1; i >= 0; i-- {
+			if block[i] != '\n' || start+int64(i) == info.Size-1 {
+				continue
+			}
+			if count++; count == n {
+				return start + int64(i) + 1, nil
+			}
+		}
+		end = start
+	}
+	return 0, nil
+}
+
+// readAt は、info の offset から len(buf) バイトを buf へ読み込みます。
+func (p *rangePrinter) readAt(info remoteio.ObjectInfo, offset int64, buf []byte) error {
+	rc, err := p.ranger.OpenRange(p.ctx, p.rangeURI(info), offset, int64(len(buf)))
+	if err != nil {
+		return fmt.Errorf(tr("入力ストリームのオープンに失敗しました (%s)")+": %w", p.uri, err)
+	}
+	defer rc.Close()
+	if _, err := io.ReadFull(rc, buf); err != nil {
+		return fmt.Errorf(tr("読み込みに失敗しました (%s)")+": %w", p.uri, err)
+	}
+	return nil
+}
+
+// copyRange は、info の offset から end の直前までを out へ書き出し、書き出した後の位置を返します。
+// 途中で失敗した場合も、書き出した分だけ進めた位置を返します。
+func (p *rangePrinter) copyRange(info remoteio.ObjectInfo, offset, end int64) (int64, error) {
+	if offset >= end {
+		return offset, nil
+	}
+	rc, err := p.ranger.OpenRange(p.ctx, p.rangeURI(info), offset, end-offset)
+	if err != nil {
+		return offset, fmt.Errorf(tr("入力ストリームのオープンに失敗しました (%s)")+": %w", p.uri, err)
+	}
+	defer rc.Close()
+	n, err := io.Copy(p.out, rc)
+	if err != nil {
+		return offset + n, fmt.Errorf(tr("データの転送中にエラーが発生しました")+": %w", err)
+	}
+	return offset + n, nil
+}
+
+// copyHeadLines は、info の先頭の n 行を out へ書き出します。
+// 先頭から rangeBlockSize ずつ範囲読み込みし、n 行目の改行までを書き出した時点で読み込みを終えます。
+func (p *rangePrinter) copyHeadLines(info remoteio.ObjectInfo, n int) error {
+	buf := make([]byte, rangeBlockSize)
+	count := 0
+	for start := int64(0); start < info.Size && count < n; {
+		block := buf[:min(rangeBlockSize, info.Size-start)]
+		if err := p.readAt(info, start, block); err != nil {
+			return err
+		}
+		for i, b := range block {
+			if b != '\n' {
+				continue
+			}
+			if count++; count == n {
+				block = block[:i+1]
+				break
+			}
+		}
+		if _, err := p.out.Write(block); err != nil {
+			return fmt.Errorf(tr("データの転送中にエラーが発生しました")+": %w", err)
+		}
+		start += int64(len(block))
+	}
+	return nil
+}
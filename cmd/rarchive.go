@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/shouni/go-remote-io/pkg/archive"
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/spf13/cobra"
+)
+
+// rarchiveFlags は rarchive コマンド固有のフラグを保持します。
+type rarchiveFlags struct {
+	Type string // --type アーカイブの形式 (省略時は書き込み先の拡張子から判定)
+}
+
+// newRarchiveCmd は 'rarchive' サブコマンドを生成します。
+func newRarchiveCmd() *cobra.Command {
+	var flags rarchiveFlags
+
+	rarchiveCmd := &cobra.Command{
+		Use:   "rarchive [source] [destination]",
+		Short: "ディレクトリまたはプレフィックス配下のファイルを1つの tar / zip アーカイブにまとめます。",
+		Long: `ローカルディレクトリ、または GCS URI (gs://bucket/prefix) などのプレフィックス配下のすべてのファイルを名前順に読み込み、1つのアーカイブとして書き込み先へ書き込みます。
+アーカイブ内のパスはコピー元からの相対パスです。読み込みながら書き込むため、一時ファイルを使用せず、アーカイブ全体をメモリにも保持しません。
+形式は --type (tar、tar.gz、tgz、zip) で指定します。省略した場合は書き込み先の拡張子 (.tar、.tar.gz、.tgz、.zip) から判定します。
+書き込み先に - を指定すると標準出力へ書き出します (--type が必要です)。`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRarchive(cmd, args, &flags)
+		},
+	}
+
+	rarchiveCmd.Flags().StringVar(&flags.Type, "type", "", "アーカイブの形式: tar、tar.gz (tgz)、zip (省略時は書き込み先の拡張子から判定)")
+
+	return rarchiveCmd
+}
+
+// runRarchive は rarchive コマンドの実行ロジックです。
+func runRarchive(cmd *cobra.Command, args []string, flags *rarchiveFlags) error {
+	ctx := cmd.Context()
+	srcPath, dstPath := args[0], args[1]
+	format, err := archiveFormat(flags.Type, dstPath)
+	if err != nil {
+		return err
+	}
+	toStdout := dstPath == stdioPath
+	if toStdout && jsonOutput(cmd) {
+		return usageError(errors.New(tr("--format json は書き込み先に - を指定した場合は指定できません (標準出力へアーカイブを出力するため)")))
+	}
+
+	// 1. ClientFactory とリーダー・ライターの取得 (DI)
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	// 一覧のサイズとアーカイブに書き込む内容が一致するよう、保存されている内容のまま読み込む
+	inputReader, err := clientFactory.NewInputReader(remoteio.WithReadOptions(remoteio.WithReadCompressed(true)))
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+
+	// 標準出力へ書き出す場合は、結果の表示がアーカイブに混ざらないよう表示しない
+	var results *resultWriter
+	if !toStdout {
+		results = newTextResultWriter(cmd, inputReader)
+	}
+	var (
+		files int
+		total int64
+	)
+	opts := []archive.Option{
+		archive.WithLogger(logger()),
+		archive.WithOnEntry(func(e archive.Entry) {
+			files++
+			total += e.Size
+			results.archiveEntry(e.URI, e.Name, statusArchived, e.Size)
+		}),
+	}
+	logger().Info(tr("アーカイブ開始"), slog.String("source", srcPath), slog.String("destination", dstPath), slog.String("type", string(format)))
+
+	// 2. アーカイブを書き出す
+	if toStdout {
+		if err := archive.Create(ctx, cmd.OutOrStdout(), format, inputReader, srcPath, opts...); err != nil {
+			return fmt.Errorf(tr("アーカイブの作成に失敗しました")+": %w", err)
+		}
+	} else {
+		writer, err := clientFactory.NewOutputWriter()
+		if err != nil {
+			return fmt.Errorf(tr("OutputWriterの作成に失敗しました")+": %w", err)
+		}
+		// 作成したアーカイブをパイプで書き込み先へ渡す。作成に失敗した場合はパイプをエラーで閉じ、書き込みを中断させる
+		pr, pw := io.Pipe()
+		created := make(chan error, 1)
+		go func() {
+			err := archive.Create(ctx, pw, format, inputReader, srcPath, opts...)
+			pw.CloseWithError(err)
+			created <- err
+		}()
+		writeErr := writer.Write(ctx, dstPath, pr)
+		// 書き込みが先に失敗した場合は、作成側の書き込みを中断させる
+		pr.CloseWithError(io.ErrClosedPipe)
+		createErr := <-created
+		if writeErr != nil && (createErr == nil || errors.Is(createErr, io.ErrClosedPipe)) {
+			return fmt.Errorf(tr("アーカイブの書き込みに失敗しました")+" (%s): %w", dstPath, writeErr)
+		}
+		if createErr != nil {
+			return fmt.Errorf(tr("アーカイブの作成に失敗しました")+": %w", createErr)
+		}
+	}
+
+	logger().Info(tr("アーカイブ完了"), slog.Int("files", files), slog.Int64("bytes", total))
+	return nil
+}
+
+// archiveFormat は、--type の値、または省略された場合はアーカイブのパス name の拡張子からアーカイブの形式を決定します。
+func archiveFormat(typ, name string) (archive.Format, error) {
+	if typ != "" {
+		format, err := archive.ParseFormat(typ)
+		if err != nil {
+			return "", usageError(fmt.Errorf(tr("--type には tar、tar.gz、tgz または zip を指定してください: %s"), typ))
+		}
+		return format, nil
+	}
+	format, ok := archive.FormatOf(name)
+	if !ok {
+		return "", usageError(fmt.Errorf(tr("アーカイブの形式を拡張子から判定できません。--type を指定してください: %s"), name))
+	}
+	return format, nil
+}
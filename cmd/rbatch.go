@@ -0,0 +1,366 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/shouni/go-remote-io/pkg/transfer"
+	"github.com/spf13/cobra"
+)
+
+// バッチファイルの形式 (--input-format)
+const (
+	batchFormatCSV   = "csv"   // ヘッダー行に source、destination と任意の options の列を持つ CSV
+	batchFormatJSONL = "jsonl" // 1行に1件の {"source", "destination", "options"} の JSON (NDJSON)
+)
+
+// rbatchFlags は rbatch コマンド固有のフラグを保持します。
+type rbatchFlags struct {
+	InputFormat     string    // --input-format バッチファイルの形式 (csv|jsonl)
+	Parallel        int       // --parallel 同時に転送するファイル数
+	ContinueOnError bool      // --continue-on-error 失敗した転送があっても残りの転送を続行
+	Stats           bool      // --stats 終了時に転送の集計を表示
+	Manifest        string    // --manifest 転送ごとのレコードを追記するファイル
+	Hooks           hookFlags // --on-success、--on-failure、--webhook 転送ごとに実行するフック
+}
+
+// batchRow は、バッチファイルの1件分の転送です。
+type batchRow struct {
+	Line        int               // バッチファイルの行番号 (エラーメッセージに使用する)
+	Source      string            // コピー元
+	Destination string            // 書き込み先
+	Options     map[string]string // 転送の扱い (rcopy のフラグ名をキーとし、真偽値のフラグは "true" / "false")
+}
+
+// newRbatchCmd は 'rbatch' サブコマンドを生成します。
+func newRbatchCmd() *cobra.Command {
+	var flags rbatchFlags
+
+	rbatchCmd := &cobra.Command{
+		Use:   "rbatch [batch_file]",
+		Short: "バッチファイルに記載されたコピー元と書き込み先の組をまとめて転送します。",
+		Long: `ローカルファイルまたは GCS URI などで指定されたバッチファイル (CSV または JSONL) を読み込み、記載されたすべての転送を --parallel で指定した数まで同時に実行します。
+CSV はヘッダー行に source、destination と任意の options の列を持ち、options には "no-clobber;content-type=text/csv" のように転送ごとの扱いを ";" 区切りで指定します。
+JSONL は1行に1件の {"source": ..., "destination": ..., "options": {"no-clobber": true, "content-type": "text/csv"}} を記載します。
+options には no-clobber、force、skip-identical、preserve、verify、content-type、cache-control、content-encoding、content-disposition、content-language と metadata.<key> を指定できます。
+転送ごとの結果を1行ずつ表示します。失敗した転送は再試行し、それでも失敗した場合は残りの転送を中止します (--continue-on-error を指定すると続行します)。`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRbatch(cmd, args, &flags)
+		},
+	}
+
+	rbatchCmd.Flags().StringVar(&flags.InputFormat, "input-format", "", "バッチファイルの形式 (csv|jsonl。省略時は拡張子 .csv / .jsonl / .ndjson から判定)")
+	rbatchCmd.Flags().IntVar(&flags.Parallel, "parallel", transfer.DefaultParallelism, "同時に転送するファイル数")
+	rbatchCmd.Flags().BoolVar(&flags.ContinueOnError, "continue-on-error", false, "再試行しても失敗した転送があっても、残りの転送を続行")
+	rbatchCmd.Flags().BoolVar(&flags.Stats, "stats", false, "終了時に、転送したファイル数、失敗と再試行の回数、バイト数、所要時間とスループットの集計を標準エラー出力へ表示")
+	rbatchCmd.Flags().StringVar(&flags.Manifest, "manifest", "", "転送したファイルごとに、コピー元、書き込み先、サイズ、CRC32C、日時と結果を1行の JSON で追記するファイル (ローカルファイルまたは gs://)")
+	addHookFlags(rbatchCmd, &flags.Hooks)
+
+	markURIFlags(rbatchCmd, "manifest")
+	return rbatchCmd
+}
+
+// runRbatch は rbatch コマンドの実行ロジックです。
+func runRbatch(cmd *cobra.Command, args []string, flags *rbatchFlags) (err error) {
+	ctx := cmd.Context()
+	batchFile := args[0]
+
+	format, err := batchFormat(batchFile, flags.InputFormat)
+	if err != nil {
+		return err
+	}
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	inputReader, err := clientFactory.NewInputReader()
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+
+	// 1. バッチファイルを読み込み、すべての行の転送の扱いを先に確定させる
+	rows, err := readBatchFile(ctx, inputReader, batchFile, format)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf(tr("バッチファイルに転送が記載されていません (%s)"), batchFile)
+	}
+	jobs := make([]transfer.Job, len(rows))
+	jobOptions := make(map[transfer.Job]transferOptions, len(rows))
+	for i, row := range rows {
+		job := transfer.Job{Source: row.Source, Destination: row.Destination}
+		if _, dup := jobOptions[job]; dup {
+			return fmt.Errorf(tr("バッチファイルの形式が正しくありません (%s:%d)")+": "+tr("同じコピー元と書き込み先の転送が重複しています"), batchFile, row.Line)
+		}
+		opts, err := row.transferOptions()
+		if err != nil {
+			return fmt.Errorf(tr("バッチファイルの形式が正しくありません (%s:%d)")+": %w", batchFile, row.Line, err)
+		}
+		jobs[i] = job
+		jobOptions[job] = opts
+	}
+
+	// 2. すべての転送を並行して実行する
+	writer, err := clientFactory.NewOutputWriter()
+	if err != nil {
+		return fmt.Errorf(tr("OutputWriterの作成に失敗しました")+": %w", err)
+	}
+	results, err := newTextResultWriter(cmd, inputReader).withManifest(clientFactory, inputReader, flags.Manifest)
+	if err != nil {
+		return err
+	}
+	defer results.closeManifest(ctx, &err)
+	if results, err = results.withHooks(cmd, inputReader, flags.Hooks); err != nil {
+		return err
+	}
+	defer results.summarizeHooks()
+	opts := transferOptions{results: results, stopOnFailure: !flags.ContinueOnError, jobOptions: jobOptions}
+	if flags.Stats {
+		opts.stats = cmd.ErrOrStderr()
+	}
+
+	logger().Info(tr("バッチ転送開始"), slog.String("batch", batchFile), slog.Int("files", len(jobs)))
+	if err := runTransfers(ctx, inputReader, writer, jobs, flags.Parallel, opts, nil); err != nil {
+		return err
+	}
+	logger().Info(tr("バッチ転送完了"), slog.Int("files", len(jobs)))
+	return nil
+}
+
+// batchFormat は、バッチファイル uri の形式を返します。format が空の場合は拡張子から判定します。
+func batchFormat(uri, format string) (string, error) {
+	if format == "" {
+		name, _ := remoteio.SplitGCSGeneration(uri)
+		switch strings.ToLower(path.Ext(name)) {
+		case ".csv":
+			return batchFormatCSV, nil
+		case ".jsonl", ".ndjson":
+			return batchFormatJSONL, nil
+		}
+		return "", usageError(fmt.Errorf(tr("バッチファイルの形式を拡張子から判定できません。--input-format で csv または jsonl を指定してください: %s"), uri))
+	}
+	if format != batchFormatCSV && format != batchFormatJSONL {
+		return "", usageError(fmt.Errorf(tr("--input-format には csv または jsonl を指定してください: %s"), format))
+	}
+	return format, nil
+}
+
+// readBatchFile は、バッチファイル uri を format の形式で読み込みます。
+func readBatchFile(ctx context.Context, reader remoteio.InputReader, uri, format string) ([]batchRow, error) {
+	rc, err := reader.Open(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf(tr("バッチファイルのオープンに失敗しました (%s)")+": %w", uri, err)
+	}
+	defer rc.Close()
+
+	var rows []batchRow
+	if format == batchFormatCSV {
+		rows, err = parseBatchCSV(rc)
+	} else {
+		rows, err = parseBatchJSONL(rc)
+	}
+	var lineErr *batchLineError
+	if errors.As(err, &lineErr) {
+		return nil, fmt.Errorf(tr("バッチファイルの形式が正しくありません (%s:%d)")+": %w", uri, lineErr.line, lineErr.err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(tr("バッチファイルの読み込みに失敗しました (%s)")+": %w", uri, err)
+	}
+	return rows, nil
+}
+
+// batchLineError は、バッチファイルの line 行目の形式の誤りです。
+type batchLineError struct {
+	line int
+	err  error
+}
+
+func (e *batchLineError) Error() string {
+	return fmt.Sprintf("%d: %v", e.line, e.err)
+}
+
+func (e *batchLineError) Unwrap() error {
+	return e.err
+}
+
+// parseBatchCSV は、ヘッダー行に source、destination と任意の options の列を持つ CSV を読み込みます。
+func parseBatchCSV(r io.Reader) ([]batchRow, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	headerLine, _ := cr.FieldPos(0)
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !slices.Contains([]string{"source", "destination", "options"}, name) {
+			return nil, &batchLineError{line: headerLine, err: fmt.Errorf(tr("不明な列です: %s"), name)}
+		}
+		columns[name] = i
+	}
+	_, hasSource := columns["source"]
+	_, hasDestination := columns["destination"]
+	if !hasSource || !hasDestination {
+		return nil, &batchLineError{line: headerLine, err: errors.New(tr("ヘッダー行に source と destination の列が必要です"))}
+	}
+
+	var rows []batchRow
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		row := batchRow{Line: line, Source: strings.TrimSpace(record[columns["source"]]), Destination: strings.TrimSpace(record[columns["destination"]])}
+		if i, ok := columns["options"]; ok {
+			row.Options = parseBatchOptions(record[i])
+		}
+		if err := row.validate(); err != nil {
+			return nil, &batchLineError{line: line, err: err}
+		}
+		rows = append(rows, row)
+	}
+}
+
+// parseBatchOptions は、CSV の options 列 ("no-clobber;content-type=text/csv" のような ";" 区切りの key または key=value) を読み込みます。
+// 値のない key は "true" とみなします。
+func parseBatchOptions(s string) map[string]string {
+	opts := map[string]string{}
+	for _, opt := range strings.Split(s, ";") {
+		opt = strings.TrimSpace(opt)
+		if opt == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(opt, "=")
+		if !ok {
+			value = "true"
+		}
+		opts[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return opts
+}
+
+// batchJSONRow は、JSONL のバッチファイルの1行です。
+type batchJSONRow struct {
+	Source      string         `json:"source"`
+	Destination string         `json:"destination"`
+	Options     map[string]any `json:"options"`
+}
+
+// parseBatchJSONL は、1行に1件の {"source", "destination", "options"} の JSON を読み込みます。空行は無視します。
+func parseBatchJSONL(r io.Reader) ([]batchRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var rows []batchRow
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		var v batchJSONRow
+		if err := dec.Decode(&v); err != nil {
+			return nil, &batchLineError{line: line, err: err}
+		}
+		row := batchRow{Line: line, Source: v.Source, Destination: v.Destination, Options: map[string]string{}}
+		for key, value := range v.Options {
+			switch value := value.(type) {
+			case string:
+				row.Options[key] = value
+			case bool:
+				row.Options[key] = strconv.FormatBool(value)
+			default:
+				return nil, &batchLineError{line: line, err: fmt.Errorf(tr("options の値には文字列または真偽値を指定してください: %s"), key)}
+			}
+		}
+		if err := row.validate(); err != nil {
+			return nil, &batchLineError{line: line, err: err}
+		}
+		rows = append(rows, row)
+	}
+	return rows, scanner.Err()
+}
+
+// validate は、コピー元と書き込み先が指定されていることを検証します。
+func (row batchRow) validate() error {
+	if row.Source == "" || row.Destination == "" {
+		return errors.New(tr("source と destination を指定してください"))
+	}
+	return nil
+}
+
+// transferOptions は、行の options から転送の扱いを組み立てます。書き込みのオプションは rcopy の同名のフラグと同じです。
+func (row batchRow) transferOptions() (transferOptions, error) {
+	var f rcopyFlags
+	bools := map[string]*bool{
+		"no-clobber":     &f.NoClobber,
+		"force":          &f.Force,
+		"skip-identical": &f.SkipIdentical,
+		"preserve":       &f.Preserve,
+		"verify":         &f.Verify,
+	}
+	strs := map[string]*string{
+		"content-type":        &f.ContentType,
+		"cache-control":       &f.CacheControl,
+		"content-encoding":    &f.ContentEncoding,
+		"content-disposition": &f.ContentDisposition,
+		"content-language":    &f.ContentLanguage,
+	}
+	for key, value := range row.Options {
+		if b, ok := bools[key]; ok {
+			v, err := strconv.ParseBool(value)
+			if err != nil {
+				return transferOptions{}, fmt.Errorf(tr("options の %s には真偽値を指定してください: %s"), key, value)
+			}
+			*b = v
+			continue
+		}
+		if s, ok := strs[key]; ok {
+			*s = value
+			continue
+		}
+		name, ok := strings.CutPrefix(key, "metadata.")
+		if !ok || name == "" {
+			return transferOptions{}, fmt.Errorf(tr("options に指定できない項目です: %s"), key)
+		}
+		f.Metadata = append(f.Metadata, name+"="+value)
+	}
+	if f.NoClobber && f.Force {
+		return transferOptions{}, errors.New(tr("options の no-clobber と force は同時に指定できません"))
+	}
+	writeOpts, err := f.objectOptions()
+	if err != nil {
+		return transferOptions{}, err
+	}
+	if writeOpts != nil && !slices.Contains([]string{"gs", "s3", "az"}, remoteio.SchemeOf(row.Destination)) {
+		return transferOptions{}, errors.New(tr("content-type などのオブジェクトの属性は、書き込み先が GCS / S3 / Azure の URI の場合にのみ指定できます"))
+	}
+	if f.NoClobber {
+		// 確認の後に他のプロセスが作成した場合も上書きしないよう、存在しないことを条件に書き込む
+		writeOpts = append(writeOpts, remoteio.WithWriteNoClobber())
+	}
+	return transferOptions{preserve: f.Preserve, noClobber: f.NoClobber, force: f.Force, writeOpts: writeOpts, verify: f.Verify, skipIdentical: f.SkipIdentical}, nil
+}
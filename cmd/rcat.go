@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/spf13/cobra"
+)
+
+// rcatFlags は rcat コマンド固有のフラグを保持します。
+type rcatFlags struct {
+	OutputFilename string // -o, --output 出力先
+	Raw            bool   // --raw Content-Encoding: gzip の GCS オブジェクトを展開せずに読み込み
+	Join           bool   // --join 各ソースを rcopy --split-size で分割したパートの名前として、パートを番号順に連結
+}
+
+// newRcatCmd は 'rcat' サブコマンドを生成します。
+func newRcatCmd() *cobra.Command {
+	var flags rcatFlags
+
+	rcatCmd := &cobra.Command{
+		Use:   "rcat [source_path...]",
+		Short: "複数のファイルまたはオブジェクトを連結して出力します。",
+		Long: `指定されたローカルファイル、または GCS URI などで指定されたオブジェクトを、指定された順に読み込んで1つに連結し、
+標準出力または -o で指定された出力先へ転送します。
+出力先とすべてのソースが同じバケットの GCS URI の場合は、GCS の Compose API でサーバー側で連結します (データはダウンロードされません)。
+--join を指定すると、各ソースを rcopy --split-size で分割して書き込んだ名前として扱い、そのパート (.part0001、.part0002、...) を番号順に連結して元の内容に戻します。`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRcat(cmd, args, &flags)
+		},
+	}
+
+	rcatCmd.Flags().StringVarP(&flags.OutputFilename, "output", "o", "", "連結した内容を書き出すファイル名（省略時は標準出力）")
+	rcatCmd.Flags().BoolVar(&flags.Join, "join", false, "各ソースを rcopy --split-size で分割したパートの名前として、パート (.part0001、.part0002、...) を番号順に連結")
+	rcatCmd.Flags().BoolVar(&flags.Raw, "raw", false, "Content-Encoding: gzip の GCS オブジェクトを展開せずに、保存されている圧縮済みの内容のまま読み込み")
+
+	markURIFlags(rcatCmd, "output")
+	return rcatCmd
+}
+
+// runRcat は rcat コマンドの実行ロジックです。
+func runRcat(cmd *cobra.Command, args []string, flags *rcatFlags) (err error) {
+	ctx := cmd.Context()
+	if jsonOutput(cmd) && flags.OutputFilename == "" {
+		return usageError(errors.New(tr("--format json は -o を指定した場合にのみ指定できます (-o を省略すると標準出力へ内容を出力するため)")))
+	}
+
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	var readerOpts []remoteio.Option
+	if flags.Raw {
+		readerOpts = append(readerOpts, remoteio.WithReadOptions(remoteio.WithReadCompressed(true)))
+	}
+	inputReader, err := clientFactory.NewInputReader(readerOpts...)
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+
+	if flags.Join {
+		if args, err = splitParts(ctx, inputReader, args); err != nil {
+			return err
+		}
+	}
+
+	src := &concatReader{ctx: ctx, reader: inputReader, uris: args}
+	defer src.Close()
+
+	if flags.OutputFilename == "" {
+		logger().Info(tr("連結開始"), slog.Int("sources", len(args)), slog.String("output", "stdout"))
+		if _, err := io.Copy(cmd.OutOrStdout(), src); err != nil {
+			return fmt.Errorf(tr("データの転送中にエラーが発生しました")+": %w", err)
+		}
+		return nil
+	}
+
+	outputPath := flags.OutputFilename
+	writer, err := clientFactory.NewOutputWriter()
+	if err != nil {
+		return fmt.Errorf(tr("OutputWriterの作成に失敗しました")+": %w", err)
+	}
+	// --format json では連結した書き込み先の結果を出力する (連結元は複数のため省略する)
+	results := newResultWriter(cmd, inputReader)
+	defer func() {
+		if err != nil {
+			results.failed("", outputPath, err)
+			return
+		}
+		results.transferred(ctx, "", outputPath, statusCopied)
+	}()
+
+	// 同じバケット内の GCS オブジェクトは、サーバー側で連結する
+	if composer, ok := writer.(remoteio.Composer); ok {
+		err = composer.Compose(ctx, outputPath, args...)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, remoteio.ErrComposeUnsupported) {
+			return fmt.Errorf(tr("サーバー側の連結に失敗しました (%s)")+": %w", outputPath, err)
+		}
+	}
+
+	logger().Info(tr("連結開始"), slog.Int("sources", len(args)), slog.String("output", outputPath))
+	if err := writer.Write(ctx, outputPath, src); err != nil {
+		return fmt.Errorf(tr("出力先への書き込みに失敗しました (%s)")+": %w", outputPath, err)
+	}
+	return nil
+}
+
+// splitParts は、rcopy --split-size で分割して書き込んだ名前 names のパートのURIを、names の順、パートの番号順に返します。
+func splitParts(ctx context.Context, reader remoteio.InputReader, names []string) ([]string, error) {
+	stater, ok := reader.(remoteio.Stater)
+	if !ok {
+		return nil, errors.New(tr("InputReaderが情報の取得をサポートしていません"))
+	}
+	var uris []string
+	for _, name := range names {
+		parts, err := remoteio.SplitParts(ctx, stater, name)
+		if err != nil {
+			return nil, err
+		}
+		logger().Debug(tr("分割されたパートを連結します"), slog.String("source", name), slog.Int("parts", len(parts)))
+		uris = append(uris, parts...)
+	}
+	return uris, nil
+}
+
+// concatReader は、uris を順に開いて、1つのストリームとして読み込む io.ReadCloser です。
+// 各ソースは、直前のソースを読み終えてから開きます。
+type concatReader struct {
+	ctx     context.Context
+	reader  remoteio.InputReader
+	uris    []string
+	current io.ReadCloser
+}
+
+func (c *concatReader) Read(p []byte) (int, error) {
+	for {
+		if c.current == nil {
+			if len(c.uris) == 0 {
+				return 0, io.EOF
+			}
+			rc, err := c.reader.Open(c.ctx, c.uris[0])
+			if err != nil {
+				return 0, fmt.Errorf(tr("入力ストリームのオープンに失敗しました (%s)")+": %w", c.uris[0], err)
+			}
+			c.current = rc
+			c.uris = c.uris[1:]
+		}
+		n, err := c.current.Read(p)
+		if err == io.EOF {
+			err = c.current.Close()
+			c.current = nil
+			if n > 0 || err != nil {
+				return n, err
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+// Close は、読み込み中のソースを閉じます。
+func (c *concatReader) Close() error {
+	if c.current == nil {
+		return nil
+	}
+	err := c.current.Close()
+	c.current = nil
+	return err
+}
@@ -1,41 +1,663 @@
 package cmd
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/shouni/go-remote-io/pkg/factory"
+	"github.com/shouni/go-remote-io/pkg/proxy"
 	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/shouni/go-remote-io/pkg/transfer"
 	"github.com/spf13/cobra"
 )
 
+// stdioPath は、コピー元に指定すると標準入力から、コピー先に指定すると標準出力へ転送するパスです。
+const stdioPath = "-"
+
 // rcopyFlags は rcopy コマンド固有のフラグを保持します。
 type rcopyFlags struct {
-	OutputFilename string // -o, --output 出力ファイル名
+	Outputs            []string      // -o, --output 出力先 (複数指定した場合は、1回の読み込みですべての出力先へ書き出す)
+	OutputFilename     string        // 出力ファイル名 (Outputs の最初の出力先。標準出力の場合は空)
+	Progress           string        // --progress 進捗の出力形式 (json)
+	ProgressFile       string        // --progress-file 進捗の出力先 (省略時は標準エラー出力)
+	ProgressInterval   time.Duration // --progress-interval 進捗の出力間隔
+	MaxSize            string        // --max-size 転送を許可する最大サイズ
+	AllowTypes         []string      // --allow-content-type 転送を許可するContent-Type
+	Clamd              string        // --clamd コンテンツスキャンに使用する clamd のアドレス
+	Recursive          bool          // -r, --recursive ディレクトリ/プレフィックス配下を再帰的にコピー
+	Append             bool          // --append 既存の GCS オブジェクトの末尾に追記
+	Parallel           int           // --parallel 同時に転送するファイル数 (-r) または範囲の数 (--slice-size)
+	SliceSize          string        // --slice-size 分割ダウンロードで1つの範囲として読み込むサイズ
+	SplitSize          string        // --split-size 書き込む内容を分割する1つのパートのサイズ
+	Resumable          bool          // --resumable 中断されたアップロードを再実行時に再開
+	Continue           bool          // --continue 途中までダウンロードされたローカルファイルの続きから再開
+	BufferSize         string        // --buffer-size コピーに使用するバッファのサイズ
+	ChunkSize          string        // --chunk-size アップロードを分割して送信する単位
+	FileMode           string        // --file-mode 作成するローカルファイルのパーミッション
+	DirMode            string        // --dir-mode 作成するローカルディレクトリのパーミッション
+	Preserve           bool          // --preserve コピー元の更新日時をローカルファイルに設定
+	Fsync              bool          // --fsync ローカルファイルの書き込み完了前に fsync
+	NoClobber          bool          // --no-clobber 既存の書き込み先を上書きせずにスキップ
+	Force              bool          // --force 既存の書き込み先を上書き
+	IfGeneration       int64         // --if-generation-match 書き込み先の世代番号の前提条件
+	IfMetageneration   int64         // --if-metageneration-match 書き込み先のメタデータの世代番号の前提条件
+	Generation         int64         // --generation 読み込む GCS オブジェクトの世代番号
+	KMSKey             string        // --kms-key アップロードしたオブジェクトの暗号化に使用する Cloud KMS の鍵
+	Encrypt            bool          // --encrypt 内容をクライアント側で暗号化してから書き込み
+	Decrypt            bool          // --decrypt 読み込んだ内容をクライアント側で復号
+	EncryptionKeyFile  string        // --encryption-key-file 暗号化と復号に使用するローカルの鍵ファイル
+	EncryptionKMSKey   string        // --encryption-kms-key 暗号化と復号に使用するデータ鍵をラップする Cloud KMS の鍵
+	ContentType        string        // --content-type 書き込み先に設定する Content-Type
+	Metadata           []string      // --metadata 書き込み先に設定するカスタムメタデータ (key=value)
+	CacheControl       string        // --cache-control 書き込み先に設定する Cache-Control
+	ContentEncoding    string        // --content-encoding 書き込み先に設定する Content-Encoding
+	ContentDisposition string        // --content-disposition 書き込み先に設定する Content-Disposition
+	ContentLanguage    string        // --content-language 書き込み先に設定する Content-Language
+	Gzip               bool          // --gzip 内容を gzip で圧縮して書き込み
+	Raw                bool          // --raw Content-Encoding: gzip の GCS オブジェクトを展開せずに読み込み
+	AutoDecompress     bool          // --auto-decompress 拡張子 (.gz / .zst) に応じて内容を展開
+	Verify             bool          // --verify 転送した内容のチェックサムを検証
+	VerifyMD5          bool          // --verify-md5 --verify で MD5 も検証
+	SkipIdentical      bool          // --skip-identical 書き込み先の内容が同じ場合はスキップ
+	Stats              bool          // --stats 終了時に転送の集計を表示
+	Manifest           string        // --manifest 転送ごとのレコードを追記するファイル
+	Hooks              hookFlags     // --on-success、--on-failure、--webhook 転送ごとに実行するフック
 }
 
-var flags rcopyFlags // フラグ変数の名前を 'flags' に変更
+// newRcopyCmd は 'rcopy' サブコマンドを生成します。
+func newRcopyCmd() *cobra.Command {
+	var flags rcopyFlags
 
-// rcopyCmd は 'rcopy' サブコマンドを定義します。
-var rcopyCmd = &cobra.Command{
-	Use:   "rcopy [source_path]", // コマンド名を rcopy に変更
-	Short: "リモート/ローカルパス間で内容を読み込み、指定された出力先へ転送します。",
-	Long: `指定されたパス (ローカルファイル、または GCS URI) から io.ReadCloser を開きます。
-読み込んだ内容は、標準出力、ローカルファイル、または GCS URIで指定されたリモートパスへ転送されます。`,
-	Args: cobra.ExactArgs(1), // 1つのパス引数を必須とする
-	RunE: runRcopy,           // 実行関数名を runRcopy に変更
-}
+	rcopyCmd := &cobra.Command{
+		Use:   "rcopy <source>... [<destination>]",
+		Short: "リモート/ローカルパス間で内容を読み込み、指定された出力先へ転送します。",
+		Long: `指定されたパス (ローカルファイル、GCS URI、S3 URI、Azure URI、または SFTP URI) から io.ReadCloser を開きます。
+読み込んだ内容は、最後の引数で指定したコピー先 (rcopy <source> <destination>) のローカルファイル、または GCS URI / S3 URI / Azure URI / SFTP URIで指定されたリモートパスへ転送されます。
+コピー元だけを指定した場合と、コピー先に - を指定した場合は標準出力へ書き出します。
+コピー元に - を指定すると標準入力から読み込み、長さが不明なストリームのまま書き込みます。
+-r を指定すると、ディレクトリまたはプレフィックス配下のすべてのファイルを、相対パスを保ったままコピー先の配下へコピーします。
+複数のコピー元を指定すると、コピー先に指定したディレクトリ (末尾が "/" の URI または既存のローカルディレクトリ) の配下へ、それぞれ同じファイル名でコピーします。
+複数のファイルは --parallel で指定した数まで同時に転送します。失敗した転送は、1つのファイルのコピーでも --retries に従って再試行します。
+-o でコピー先を指定する従来の形式は非推奨です (-o を指定した場合は、コピー元を1つだけ指定します)。
+-o を複数指定すると、コピー元を1回だけ読み込み、すべての出力先へ同時に書き出します (失敗した出力先があっても残りへの書き込みを続けます)。
+--slice-size を指定すると、リモートのファイルを指定したサイズの範囲に分割し、--parallel で指定した数まで並行してダウンロードします。
+ローカルファイルから GCS へのコピーでは、範囲ごとに一時オブジェクトとして並行してアップロードし、Compose API で連結します。
+--resumable を指定すると、アップロード済みの範囲を記録し、中断された場合は同じコマンドの再実行で続きから再開します。
+--continue を指定すると、途中までダウンロードされたローカルファイルの続きからダウンロードし、完了後に CRC32C を検証します。
+--split-size を指定すると、書き込む内容を指定したサイズごとに、コピー先の名前に連番を付けたパート (.part0001、.part0002、...) に分割して書き込みます (rcat --join で連結できます)。`,
+		Annotations: dryRunAnnotations(),
+		Args:        cobra.MinimumNArgs(1), // 1つ以上のパス引数を必須とする (-o を省略して2つ以上指定した場合、最後の引数はコピー先)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRcopy(cmd, args, &flags)
+		},
+	}
 
-func init() {
 	// フラグの初期化
-	rcopyCmd.Flags().StringVarP(&flags.OutputFilename, "output", "o", "", "読み込んだ内容を書き出すファイル名（省略時は標準出力）")
+	rcopyCmd.Flags().StringArrayVarP(&flags.Outputs, "output", "o", nil, "非推奨: コピー先は最後の引数で指定してください。読み込んだ内容を書き出すファイル名（- の場合は標準出力）。複数指定すると、コピー元を1回だけ読み込み、すべての出力先へ同時に書き出し (-o を指定した場合は、コピー元を1つだけ指定します)")
+	rcopyCmd.Flags().BoolVarP(&flags.Recursive, "recursive", "r", false, "ディレクトリ/プレフィックス配下のファイルを再帰的にコピー先の配下へコピー")
+	rcopyCmd.Flags().IntVar(&flags.Parallel, "parallel", transfer.DefaultParallelism, "同時に転送するファイル数 (-r) または同時に読み込む範囲の数 (--slice-size)")
+	rcopyCmd.Flags().StringVar(&flags.SliceSize, "slice-size", "", "指定したサイズ (例: 64MiB) の範囲に分割して並行して転送 (リモートからのダウンロード、またはローカルファイルから GCS へのアップロード)")
+	rcopyCmd.Flags().StringVar(&flags.SplitSize, "split-size", "", "書き込む内容を指定したサイズ (例: 1GiB) ごとに、コピー先の名前に連番 (.part0001、.part0002、...) を付けたパートに分割して書き込み")
+	rcopyCmd.Flags().BoolVar(&flags.Resumable, "resumable", false, "ローカルファイルから GCS へのアップロードの進行状況を保存し、中断された場合は同じコマンドの再実行で続きから再開")
+	rcopyCmd.Flags().BoolVar(&flags.Continue, "continue", false, "コピー先のローカルファイルに途中までダウンロードされている場合は続きから再開し、完了後に CRC32C を検証")
+	rcopyCmd.Flags().StringVar(&flags.BufferSize, "buffer-size", "", "内容のコピーに使用するバッファのサイズ (例: 1MiB。省略時は 32KiB)")
+	rcopyCmd.Flags().StringVar(&flags.ChunkSize, "chunk-size", "", "アップロードを分割して送信する単位 (例: 8MiB。省略時は GCS: 16MiB、S3: 5MiB。GCS では 0 でバッファリングせずに送信)")
+	rcopyCmd.Flags().StringVar(&flags.FileMode, "file-mode", "", "作成するローカルファイルのパーミッション (8進数。例: 0640。省略時は 0666 から umask を除いた値)")
+	rcopyCmd.Flags().StringVar(&flags.DirMode, "dir-mode", "", "作成するローカルの出力ディレクトリのパーミッション (8進数。例: 0750。省略時は 0755)")
+	rcopyCmd.Flags().BoolVar(&flags.Preserve, "preserve", false, "ローカルファイルへのコピーで、コピー元の更新日時をファイルの更新日時 (mtime) に設定")
+	rcopyCmd.Flags().BoolVar(&flags.Fsync, "fsync", false, "ローカルファイルへの書き込みの完了前に、ファイルとその親ディレクトリを fsync (書き込み直後のクラッシュでも内容を失わないようにする)")
+	rcopyCmd.Flags().BoolVar(&flags.NoClobber, "no-clobber", false, "既存のファイル/オブジェクトを上書きせずにスキップ (GCS では存在しないことを条件に書き込み、ローカルでは O_EXCL で作成)")
+	rcopyCmd.Flags().BoolVar(&flags.Force, "force", false, "既存のファイル/オブジェクトを上書きし、上書きしたファイルを報告 (--no-clobber とは併用できません)")
+	rcopyCmd.MarkFlagsMutuallyExclusive("no-clobber", "force")
+	rcopyCmd.Flags().Int64Var(&flags.Generation, "generation", 0, "コピー元の GCS オブジェクトの指定した世代番号 (generation) を読み込み (gs://bucket/object#generation と同じ)")
+	rcopyCmd.Flags().Int64Var(&flags.IfGeneration, "if-generation-match", 0, "コピー先の GCS オブジェクトの世代番号 (generation) が一致する場合にのみ書き込み (0 の場合は存在しない場合のみ)。他の書き込みで更新されていた場合は失敗します")
+	rcopyCmd.Flags().Int64Var(&flags.IfMetageneration, "if-metageneration-match", 0, "コピー先の GCS オブジェクトのメタデータの世代番号 (metageneration) が一致する場合にのみ書き込み")
+	rcopyCmd.MarkFlagsMutuallyExclusive("no-clobber", "if-generation-match")
+	rcopyCmd.MarkFlagsMutuallyExclusive("no-clobber", "if-metageneration-match")
+	rcopyCmd.Flags().BoolVar(&flags.Encrypt, "encrypt", false, "内容をクライアント側で暗号化 (AES-256-GCM) してから書き込み (平文は書き込み先に送信されません)")
+	rcopyCmd.Flags().BoolVar(&flags.Decrypt, "decrypt", false, "--encrypt で暗号化された内容を読み込み、クライアント側で復号して書き込み")
+	rcopyCmd.MarkFlagsMutuallyExclusive("encrypt", "decrypt")
+	rcopyCmd.Flags().StringVar(&flags.EncryptionKeyFile, "encryption-key-file", "", "--encrypt / --decrypt に使用する 32 バイトの鍵 (バイナリまたは Base64) のファイル")
+	rcopyCmd.Flags().StringVar(&flags.EncryptionKMSKey, "encryption-kms-key", "", "--encrypt / --decrypt で、オブジェクトごとのデータ鍵をラップする Cloud KMS の鍵 (projects/P/locations/L/keyRings/R/cryptoKeys/K)")
+	rcopyCmd.Flags().StringVar(&flags.KMSKey, "kms-key", "", "コピー先の GCS オブジェクトを指定した Cloud KMS の鍵 (projects/P/locations/L/keyRings/R/cryptoKeys/K) で暗号化 (CMEK)")
+	rcopyCmd.Flags().StringVar(&flags.ContentType, "content-type", "", "コピー先の GCS / S3 / Azure のオブジェクトに設定する Content-Type (省略時は拡張子、判定できない場合は内容の先頭から判定)")
+	rcopyCmd.Flags().StringArrayVar(&flags.Metadata, "metadata", nil, "コピー先の GCS / S3 / Azure のオブジェクトに設定するカスタムメタデータ (key=value)。複数指定可")
+	rcopyCmd.Flags().StringVar(&flags.CacheControl, "cache-control", "", "コピー先の GCS / S3 / Azure のオブジェクトに設定する Cache-Control (例: \"public, max-age=3600\")")
+	rcopyCmd.Flags().StringVar(&flags.ContentEncoding, "content-encoding", "", "コピー先の GCS / S3 / Azure のオブジェクトに設定する Content-Encoding (例: gzip。内容は変換されません)")
+	rcopyCmd.Flags().StringVar(&flags.ContentDisposition, "content-disposition", "", "コピー先の GCS / S3 / Azure のオブジェクトに設定する Content-Disposition (例: \"attachment; filename=report.csv\")")
+	rcopyCmd.Flags().StringVar(&flags.ContentLanguage, "content-language", "", "コピー先の GCS / S3 / Azure のオブジェクトに設定する Content-Language (例: ja)")
+	rcopyCmd.Flags().BoolVar(&flags.Gzip, "gzip", false, "内容を gzip で圧縮しながらコピー先の GCS / S3 / Azure へ書き込み、Content-Encoding: gzip を設定 (Content-Type は圧縮前の内容から判定)")
+	rcopyCmd.MarkFlagsMutuallyExclusive("gzip", "content-encoding")
+	rcopyCmd.Flags().BoolVar(&flags.SkipIdentical, "skip-identical", false, "書き込み先が既に存在し、サイズと CRC32C チェックサムがコピー元と一致する場合は転送せずにスキップ")
+	rcopyCmd.Flags().BoolVar(&flags.Stats, "stats", false, "終了時に、転送したファイル数、失敗と再試行の回数、バイト数、所要時間とスループットの集計を標準エラー出力へ表示")
+	rcopyCmd.Flags().StringVar(&flags.Manifest, "manifest", "", "転送したファイルごとに、コピー元、書き込み先、サイズ、CRC32C、日時と結果を1行の JSON で追記するファイル (ローカルファイルまたは gs://)")
+	addHookFlags(rcopyCmd, &flags.Hooks)
+	rcopyCmd.Flags().BoolVar(&flags.Verify, "verify", false, "転送した内容の CRC32C を計算し、コピー元と書き込み先 (GCS) のオブジェクトの属性と比較 (一致しない場合は書き込み先を削除して失敗)")
+	rcopyCmd.Flags().BoolVar(&flags.VerifyMD5, "verify-md5", false, "--verify に加えて MD5 も計算して比較")
+	rcopyCmd.Flags().BoolVar(&flags.AutoDecompress, "auto-decompress", false, "拡張子が .gz / .zst のコピー元を展開して書き込み (-r では書き込み先の名前から拡張子を除く)")
+	rcopyCmd.Flags().BoolVar(&flags.Raw, "raw", false, "Content-Encoding: gzip の GCS オブジェクトを展開せずに、保存されている圧縮済みの内容のまま読み込み")
+	rcopyCmd.MarkFlagsMutuallyExclusive("auto-decompress", "raw")
+	rcopyCmd.Flags().BoolVar(&flags.Append, "append", false, "コピー先の既存の GCS オブジェクトの末尾に追記 (存在しない場合は新規作成)")
+	rcopyCmd.Flags().StringVar(&flags.Progress, "progress", "", "進捗の出力形式 (bar: プログレスバーを表示、json: NDJSON形式の進捗レコードを出力)。値を省略した場合は bar")
+	rcopyCmd.Flags().Lookup("progress").NoOptDefVal = progressFormatBar
+	rcopyCmd.Flags().StringVar(&flags.ProgressFile, "progress-file", "", "進捗の出力先ファイルまたは名前付きパイプ（省略時は標準エラー出力）")
+	rcopyCmd.Flags().DurationVar(&flags.ProgressInterval, "progress-interval", 0, "進捗の出力間隔 (省略時は bar: 200ms、json: 1s)")
+
+	rcopyCmd.Flags().StringVar(&flags.MaxSize, "max-size", "", "転送を許可する最大サイズ (例: 5GiB)。超過した場合は転送を中止します")
+	rcopyCmd.Flags().StringSliceVar(&flags.AllowTypes, "allow-content-type", nil, "転送を許可するContent-Type (内容から判定。例: image/, application/pdf)。複数指定可")
+
+	rcopyCmd.Flags().StringVar(&flags.Clamd, "clamd", "", "書き込む内容をスキャンする clamd のアドレス (host:port または unix:/path/to/clamd.sock)")
+
+	markURIFlags(rcopyCmd, "output", "manifest")
+	return rcopyCmd
+}
+
+// writerOptions は、フラグに応じた OutputWriter のオプション (バリデータなど) を組み立てます。
+func (f *rcopyFlags) writerOptions() ([]remoteio.Option, error) {
+	var validators []remoteio.Validator
+	if f.MaxSize != "" {
+		limit, err := parseByteSize(f.MaxSize)
+		if err != nil {
+			return nil, err
+		}
+		validators = append(validators, remoteio.MaxSize(limit))
+	}
+	if len(f.AllowTypes) > 0 {
+		validators = append(validators, remoteio.AllowContentTypes(f.AllowTypes...))
+	}
+	if f.Clamd != "" {
+		validators = append(validators, remoteio.NewClamAVScanner(f.Clamd))
+	}
+	if len(validators) == 0 {
+		return nil, nil
+	}
+	return []remoteio.Option{remoteio.WithValidators(validators...)}, nil
+}
+
+// bufferOptions は、--buffer-size と --chunk-size に応じた InputReader / OutputWriter のオプションを組み立てます。
+func bufferOptions(bufferSize, chunkSize string) ([]remoteio.Option, error) {
+	var opts []remoteio.Option
+	if bufferSize != "" {
+		n, err := parseByteSize(bufferSize)
+		if err != nil {
+			return nil, err
+		}
+		if n <= 0 {
+			return nil, usageError(fmt.Errorf(tr("--buffer-size には正のサイズを指定してください: %s"), bufferSize))
+		}
+		opts = append(opts, remoteio.WithBufferSize(int(n)))
+	}
+	if chunkSize != "" {
+		n, err := parseByteSize(chunkSize)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, remoteio.WithChunkSize(int(n)))
+	}
+	return opts, nil
+}
+
+// kmsKeyOptions は、--kms-key に応じた OutputWriter のオプションを組み立てます。dst は書き込み先で、GCS URI である必要があります。
+func kmsKeyOptions(keyName, dst string) ([]remoteio.Option, error) {
+	if keyName == "" {
+		return nil, nil
+	}
+	if !remoteio.IsGCSURI(dst) {
+		return nil, usageError(errors.New(tr("--kms-key は、書き込み先が GCS URI (gs://) の場合にのみ指定できます")))
+	}
+	if !strings.HasPrefix(keyName, "projects/") || !strings.Contains(keyName, "/cryptoKeys/") {
+		return nil, usageError(fmt.Errorf(tr("--kms-key には projects/P/locations/L/keyRings/R/cryptoKeys/K の形式で鍵の名前を指定してください: %s"), keyName))
+	}
+	return []remoteio.Option{remoteio.WithKMSKeyName(keyName)}, nil
+}
+
+// localFileOptions は、--file-mode、--dir-mode、--fsync で指定されたローカルファイルの作成方法です (パーミッションが 0 の場合は既定値)。
+type localFileOptions struct {
+	file      fs.FileMode
+	dir       fs.FileMode
+	fsync     bool
+	noClobber bool // createFile で既存のファイルを上書きしない (--no-clobber)
+}
+
+// parseLocalFileOptions は、--file-mode と --dir-mode の8進数の値を解析します。
+func parseLocalFileOptions(fileMode, dirMode string, fsync bool) (localFileOptions, error) {
+	p := localFileOptions{fsync: fsync}
+	for _, m := range []struct {
+		flag  string
+		value string
+		dst   *fs.FileMode
+	}{{"--file-mode", fileMode, &p.file}, {"--dir-mode", dirMode, &p.dir}} {
+		if m.value == "" {
+			continue
+		}
+		n, err := strconv.ParseUint(m.value, 8, 32)
+		if err != nil || n == 0 || n > 0777 {
+			return localFileOptions{}, usageError(fmt.Errorf(tr("%s には 8進数のパーミッション (例: 0644) を指定してください: %s"), m.flag, m.value))
+		}
+		*m.dst = fs.FileMode(n)
+	}
+	return p, nil
+}
+
+// options は、パーミッションと fsync を指定する InputReader / OutputWriter のオプションを返します。
+func (p localFileOptions) options() []remoteio.Option {
+	var opts []remoteio.Option
+	if p.file != 0 {
+		opts = append(opts, remoteio.WithFileMode(p.file))
+	}
+	if p.dir != 0 {
+		opts = append(opts, remoteio.WithDirMode(p.dir))
+	}
+	if p.fsync {
+		opts = append(opts, remoteio.WithFsync())
+	}
+	return opts
+}
+
+// createFile は、出力ディレクトリを作成してから、ローカルファイル path を作成します。
+// noClobber が指定されている場合は O_EXCL で作成し、既に存在すれば remoteio.ErrAlreadyExists を返します。
+func (p localFileOptions) createFile(path string) (*os.File, error) {
+	if dir := filepath.Dir(path); dir != "" && dir != "." {
+		dirPerm := p.dir
+		if dirPerm == 0 {
+			dirPerm = 0755
+		}
+		if err := os.MkdirAll(dir, dirPerm); err != nil {
+			return nil, fmt.Errorf(tr("出力ディレクトリ(%s)の作成に失敗しました")+": %w", dir, err)
+		}
+	}
+	flag, perm := os.O_RDWR|os.O_CREATE|os.O_TRUNC, fs.FileMode(0666)
+	if p.noClobber {
+		flag = os.O_RDWR | os.O_CREATE | os.O_EXCL
+	}
+	if p.file != 0 {
+		perm = p.file
+	}
+	file, err := os.OpenFile(path, flag, perm)
+	if p.noClobber && errors.Is(err, fs.ErrExist) {
+		return nil, fmt.Errorf("%w: %s", remoteio.ErrAlreadyExists, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.file == 0 {
+		return file, nil
+	}
+	// umask や既存のファイルのパーミッションにかかわらず、指定されたパーミッションにする
+	if err := file.Chmod(p.file); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// sync は、--fsync が指定されている場合に、書き込んだファイル path とその親ディレクトリを fsync します。
+func (p localFileOptions) sync(path string) error {
+	if !p.fsync {
+		return nil
+	}
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := file.Sync(); err != nil {
+		return err
+	}
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
+
+// sourceModTime は、--preserve でローカルファイルに設定する、コピー元の更新日時を取得します。
+func sourceModTime(ctx context.Context, reader remoteio.InputReader, uri string) (time.Time, error) {
+	stater, ok := reader.(remoteio.Stater)
+	if !ok {
+		return time.Time{}, errors.New(tr("InputReaderが情報の取得をサポートしていません"))
+	}
+	info, err := stater.Stat(ctx, uri)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(tr("コピー元の更新日時の取得に失敗しました (%s)")+": %w", uri, err)
+	}
+	return info.Updated, nil
+}
+
+// preserveOptions は、preserve が指定され、出力先がローカルファイルの場合に、コピー元の更新日時を設定する書き込みオプションを返します。
+func preserveOptions(ctx context.Context, reader remoteio.InputReader, src, dst string, preserve bool) ([]remoteio.WriteOption, error) {
+	if !preserve || remoteio.SchemeOf(dst) != "" {
+		return nil, nil
+	}
+	modTime, err := sourceModTime(ctx, reader, src)
+	if err != nil {
+		return nil, err
+	}
+	return []remoteio.WriteOption{remoteio.WithModTime(modTime)}, nil
+}
+
+// preconditionOptions は、--if-generation-match と --if-metageneration-match に応じた書き込みオプションを組み立てます。
+func (f *rcopyFlags) preconditionOptions(cmd *cobra.Command) []remoteio.WriteOption {
+	var opts []remoteio.WriteOption
+	if cmd.Flags().Changed("if-generation-match") {
+		opts = append(opts, remoteio.WithIfGenerationMatch(f.IfGeneration))
+	}
+	if cmd.Flags().Changed("if-metageneration-match") {
+		opts = append(opts, remoteio.WithIfMetagenerationMatch(f.IfMetageneration))
+	}
+	return opts
+}
+
+// objectOptions は、--content-type、--metadata、--gzip と、--cache-control などの HTTP ヘッダーのフラグに応じた書き込みオプションを組み立てます。
+// 指定されていない場合は nil を返します。
+func (f *rcopyFlags) objectOptions() ([]remoteio.WriteOption, error) {
+	var opts []remoteio.WriteOption
+	if f.ContentType != "" {
+		opts = append(opts, remoteio.WithContentType(f.ContentType))
+	}
+	if len(f.Metadata) > 0 {
+		md := make(map[string]string, len(f.Metadata))
+		for _, kv := range f.Metadata {
+			key, value, ok := strings.Cut(kv, "=")
+			if !ok || key == "" {
+				return nil, usageError(fmt.Errorf(tr("--metadata は key=value の形式で指定してください: %s"), kv))
+			}
+			md[key] = value
+		}
+		opts = append(opts, remoteio.WithMetadata(md))
+	}
+	if f.CacheControl != "" {
+		opts = append(opts, remoteio.WithCacheControl(f.CacheControl))
+	}
+	if f.ContentEncoding != "" {
+		opts = append(opts, remoteio.WithContentEncoding(f.ContentEncoding))
+	}
+	if f.ContentDisposition != "" {
+		opts = append(opts, remoteio.WithContentDisposition(f.ContentDisposition))
+	}
+	if f.ContentLanguage != "" {
+		opts = append(opts, remoteio.WithContentLanguage(f.ContentLanguage))
+	}
+	if f.Gzip {
+		opts = append(opts, remoteio.WithGzip())
+	}
+	return opts, nil
+}
+
+// sliceOptions は、--slice-size に応じた分割ダウンロードのオプションを組み立てます。
+// 分割ダウンロードが指定されていない場合は nil を返します。
+func (f *rcopyFlags) sliceOptions() ([]remoteio.SliceOption, error) {
+	if f.SliceSize == "" {
+		return nil, nil
+	}
+	size, err := parseByteSize(f.SliceSize)
+	if err != nil {
+		return nil, err
+	}
+	if size <= 0 {
+		return nil, usageError(fmt.Errorf(tr("--slice-size には正のサイズを指定してください: %s"), f.SliceSize))
+	}
+	return []remoteio.SliceOption{remoteio.WithSliceSize(size), remoteio.WithSliceParallelism(f.Parallel)}, nil
+}
+
+// progressReporter は、--progress に応じた進捗レポーターを作成します。進捗出力が指定されていない場合は nil を返します。
+func (f *rcopyFlags) progressReporter() (*progressReporter, error) {
+	switch f.Progress {
+	case "":
+		return nil, nil
+	case progressFormatJSON, progressFormatBar:
+		interval := f.ProgressInterval
+		if interval <= 0 {
+			interval = defaultProgressInterval(f.Progress)
+		}
+		return newProgressReporter(f.Progress, f.ProgressFile, interval)
+	default:
+		return nil, usageError(fmt.Errorf(tr("サポートされていない進捗形式です: %s"), f.Progress))
+	}
+}
+
+// splitDestination は、-o を省略して2つ以上の引数を指定した場合 (rcopy <source>... <destination>) に、
+// 最後の引数をコピー先として Outputs に設定し、コピー元の引数を返します。
+// -o を指定した場合は、すべての引数をコピー元として返します (コピー元が1つであることは rcopyFlagRules で検証します)。
+func (f *rcopyFlags) splitDestination(args []string) []string {
+	if len(f.Outputs) > 0 || len(args) < 2 {
+		return args
+	}
+	f.Outputs = []string{args[len(args)-1]}
+	return args[:len(args)-1]
+}
+
+// rcopyInvocation は、フラグの組み合わせの検証に使用する、rcopy の1回の呼び出しの内容です。
+type rcopyInvocation struct {
+	flags         *rcopyFlags
+	sources       []string // コピー元 (splitDestination で最後の引数のコピー先を除いたもの)
+	output        string   // コピー先 (標準出力の場合は空)
+	outputFlag    bool     // -o でコピー先を指定した
+	generation    bool     // --generation を指定した
+	preconditions bool     // --if-generation-match または --if-metageneration-match を指定した
+	objectOpts    bool     // 書き込み先のオブジェクトに設定するフラグ (--content-type、--metadata、--gzip など) を指定した
+	json          bool     // --format json
+	dryRun        bool     // --dry-run
+}
+
+// multiple は、複数のコピー元を指定したかどうかを返します。
+func (inv *rcopyInvocation) multiple() bool { return len(inv.sources) > 1 }
+
+// fromStdin は、コピー元に標準入力 (-) を指定したかどうかを返します。
+func (inv *rcopyInvocation) fromStdin() bool { return slices.Contains(inv.sources, stdioPath) }
+
+// tee は、-o を複数指定したかどうかを返します。
+func (inv *rcopyInvocation) tee() bool { return len(inv.flags.Outputs) > 1 }
+
+// rcopyFlagRule は、rcopy のフラグの組み合わせの制約です。violated が true を返す場合は、message の使用方法の誤りとします。
+type rcopyFlagRule struct {
+	violated func(inv *rcopyInvocation) bool
+	message  string
+}
+
+// rcopyFlagRules は、rcopy で同時に指定できないフラグと引数の組み合わせの一覧です。先に一致したものを報告します。
+var rcopyFlagRules = []rcopyFlagRule{
+	{
+		violated: func(inv *rcopyInvocation) bool { return inv.outputFlag && inv.multiple() },
+		message:  "-o を指定した場合は、コピー元を1つだけ指定してください (最後の引数のコピー先と -o は同時に指定できません)",
+	},
+	{
+		violated: func(inv *rcopyInvocation) bool { return inv.tee() && inv.flags.Recursive },
+		message:  "-o を複数指定する場合は、-r は併用できません",
+	},
+	{
+		violated: func(inv *rcopyInvocation) bool {
+			f := inv.flags
+			return inv.tee() && (f.Append || f.Continue || f.Resumable || f.SliceSize != "" || f.SkipIdentical || f.Force || f.Verify || f.VerifyMD5 || inv.preconditions)
+		},
+		message: "-o を複数指定する場合は、--append、--continue、--resumable、--slice-size、--skip-identical、--force、--verify、--verify-md5、--if-generation-match、--if-metageneration-match と併用できません",
+	},
+	{
+		violated: func(inv *rcopyInvocation) bool {
+			return inv.generation && (!remoteio.IsGCSURI(inv.sources[0]) || inv.flags.Recursive || inv.flags.Generation <= 0)
+		},
+		message: "--generation には、GCS URI (gs://) のコピー元の世代番号を正の整数で指定してください (-r は併用できません)",
+	},
+	{
+		violated: func(inv *rcopyInvocation) bool { return inv.json && inv.output == "" },
+		message:  "--format json はコピー先を指定した場合にのみ指定できます (省略すると標準出力へ内容を出力するため)",
+	},
+	{
+		violated: func(inv *rcopyInvocation) bool { return inv.flags.Append && inv.flags.Recursive },
+		message:  "--append と -r は同時に指定できません",
+	},
+	{
+		violated: func(inv *rcopyInvocation) bool { return inv.flags.Append && !remoteio.IsGCSURI(inv.output) },
+		message:  "--append を指定する場合はコピー先に GCS URI (gs://) を指定してください",
+	},
+	{
+		violated: func(inv *rcopyInvocation) bool {
+			f := inv.flags
+			return f.Resumable && (f.Recursive || f.Append || remoteio.SchemeOf(inv.sources[0]) != "" || !remoteio.IsGCSURI(inv.output))
+		},
+		message: "--resumable は、ローカルファイルをコピー先の GCS URI (gs://) へコピーする場合にのみ指定できます (-r と --append は併用できません)",
+	},
+	{
+		violated: func(inv *rcopyInvocation) bool {
+			f := inv.flags
+			return f.Continue && (f.Recursive || f.Append || f.SliceSize != "" || remoteio.SchemeOf(inv.sources[0]) == "" || inv.output == "" || remoteio.SchemeOf(inv.output) != "")
+		},
+		message: "--continue は、リモートのファイルをコピー先のローカルファイルへコピーする場合にのみ指定できます (-r、--append と --slice-size は併用できません)",
+	},
+	{
+		violated: func(inv *rcopyInvocation) bool {
+			return inv.flags.NoClobber && (inv.flags.Append || inv.flags.Continue)
+		},
+		message: "--no-clobber は --append、--continue と併用できません",
+	},
+	{
+		violated: func(inv *rcopyInvocation) bool {
+			f := inv.flags
+			return inv.preconditions && (f.Recursive || f.Append || f.Resumable || !remoteio.IsGCSURI(inv.output))
+		},
+		message: "--if-generation-match と --if-metageneration-match は、コピー先の GCS URI (gs://) へ1つのファイルをコピーする場合にのみ指定できます (-r、--append と --resumable は併用できません)",
+	},
+	{
+		violated: func(inv *rcopyInvocation) bool {
+			return inv.objectOpts && (inv.flags.Append || !slices.Contains([]string{"gs", "s3", "az"}, remoteio.SchemeOf(inv.output)))
+		},
+		message: "--content-type、--metadata、--cache-control、--content-encoding、--content-disposition、--content-language と --gzip は、コピー先に GCS / S3 / Azure の URI を指定した場合にのみ指定できます (--append は併用できません)",
+	},
+	{
+		violated: func(inv *rcopyInvocation) bool {
+			f := inv.flags
+			return (f.Encrypt || f.Decrypt) && (f.Append || f.Continue || f.Resumable)
+		},
+		message: "--encrypt と --decrypt は --append、--continue、--resumable と併用できません",
+	},
+	{
+		violated: func(inv *rcopyInvocation) bool {
+			f := inv.flags
+			return (f.Verify || f.VerifyMD5) && (f.Append || f.Resumable)
+		},
+		message: "--verify は --append、--resumable と併用できません (--continue は常に CRC32C を検証します)",
+	},
+	{
+		violated: func(inv *rcopyInvocation) bool {
+			f := inv.flags
+			return f.AutoDecompress && (f.Append || f.Continue || f.Resumable || f.SliceSize != "")
+		},
+		message: "--auto-decompress は --append、--continue、--resumable、--slice-size と併用できません",
+	},
+	{
+		violated: func(inv *rcopyInvocation) bool {
+			f := inv.flags
+			return f.SkipIdentical && (f.Append || f.Continue || inv.output == "" || f.Encrypt || f.Decrypt || f.AutoDecompress || f.Gzip)
+		},
+		message: "--skip-identical はコピー先を指定した場合にのみ指定でき、--append、--continue と内容を変換するフラグ (--encrypt、--decrypt、--auto-decompress、--gzip) とは併用できません",
+	},
+	{
+		// 標準入力は一度しか読み込めず、サイズや属性も取得できない
+		violated: func(inv *rcopyInvocation) bool { return inv.fromStdin() && (inv.multiple() || inv.flags.Recursive) },
+		message:  "標準入力 (-) からのコピーは、-r と複数のコピー元とは併用できません",
+	},
+	{
+		violated: func(inv *rcopyInvocation) bool {
+			f := inv.flags
+			return inv.fromStdin() && (f.Resumable || f.SliceSize != "" || f.SkipIdentical || f.Verify || f.VerifyMD5 || f.Preserve)
+		},
+		message: "標準入力 (-) からのコピーは、--resumable、--slice-size、--skip-identical、--verify、--verify-md5、--preserve と併用できません",
+	},
+	{
+		violated: func(inv *rcopyInvocation) bool {
+			f := inv.flags
+			return inv.multiple() && (f.Recursive || f.Append || f.Continue || f.Resumable || f.SliceSize != "" || inv.generation || inv.preconditions)
+		},
+		message: "複数のコピー元は、-r、--append、--continue、--resumable、--slice-size、--generation、--if-generation-match、--if-metageneration-match と併用できません",
+	},
+	{
+		violated: func(inv *rcopyInvocation) bool {
+			return inv.flags.SplitSize != "" && (inv.tee() || inv.multiple() || inv.flags.Recursive || inv.output == "" || inv.dryRun)
+		},
+		message: "--split-size は、1つのコピー元を1つのコピー先 (標準出力以外) へコピーする場合にのみ指定できます (-r と --dry-run は併用できません)",
+	},
+	{
+		violated: func(inv *rcopyInvocation) bool {
+			f := inv.flags
+			return f.SplitSize != "" && (f.Append || f.Continue || f.Resumable || f.SliceSize != "" || f.SkipIdentical || f.NoClobber || f.Force ||
+				f.Verify || f.VerifyMD5 || f.Preserve || f.Gzip || inv.preconditions)
+		},
+		message: "--split-size は --append、--continue、--resumable、--slice-size、--skip-identical、--no-clobber、--force、--verify、--verify-md5、--preserve、--gzip、--if-generation-match、--if-metageneration-match と併用できません",
+	},
+	{
+		// バリデータは書き込みごと (パートごと) に内容を検査するため、内容全体に対する検査にならない
+		violated: func(inv *rcopyInvocation) bool {
+			f := inv.flags
+			return f.SplitSize != "" && (f.MaxSize != "" || len(f.AllowTypes) > 0 || f.Clamd != "")
+		},
+		message: "--split-size は --max-size、--allow-content-type、--clamd と併用できません",
+	},
+}
+
+// validate は、inv のフラグと引数の組み合わせを rcopyFlagRules で検証し、最初に一致した制約の使用方法の誤りを返します。
+func (inv *rcopyInvocation) validate() error {
+	for _, rule := range rcopyFlagRules {
+		if rule.violated(inv) {
+			return usageError(errors.New(tr(rule.message)))
+		}
+	}
+	return nil
 }
 
 // runRcopy は rcopy コマンドの実行ロジックです。
-func runRcopy(cmd *cobra.Command, args []string) error {
+func runRcopy(cmd *cobra.Command, args []string, flags *rcopyFlags) (err error) {
 	ctx := cmd.Context()
+	if len(flags.Outputs) == 1 {
+		logger().Warn(tr("-o は非推奨です。コピー先は rcopy <source> <destination> のように最後の引数で指定してください"))
+	}
+	args = flags.splitDestination(args)
 	inputPath := args[0] // 読み込むファイルパスまたはURI
+	// コピー先の "-" は省略した場合と同じく標準出力へ書き出す
+	if len(flags.Outputs) > 0 && flags.Outputs[0] != stdioPath {
+		flags.OutputFilename = flags.Outputs[0]
+	}
+	objectOpts, err := flags.objectOptions()
+	if err != nil {
+		return err
+	}
+	preconditionOpts := flags.preconditionOptions(cmd)
+	inv := &rcopyInvocation{
+		flags:         flags,
+		sources:       args,
+		output:        flags.OutputFilename,
+		outputFlag:    cmd.Flags().Changed("output"),
+		generation:    cmd.Flags().Changed("generation"),
+		preconditions: preconditionOpts != nil,
+		objectOpts:    objectOpts != nil,
+		json:          jsonOutput(cmd),
+		dryRun:        appFlagsFrom(cmd.Context()).DryRun,
+	}
+	if err := inv.validate(); err != nil {
+		return err
+	}
+	tee := inv.tee()
+	if tee {
+		if err := flags.validateTee(inv.json); err != nil {
+			return err
+		}
+	}
+	if inv.generation {
+		inputPath = remoteio.GCSGenerationURI(inputPath, flags.Generation)
+	}
 
 	// 1. ClientFactory の取得 (DI)
 	clientFactory, err := GetFactoryFromContext(ctx)
@@ -44,93 +666,1209 @@ func runRcopy(cmd *cobra.Command, args []string) error {
 	}
 
 	// 2. InputReader の取得 (入力依存性の注入)
-	inputReader, err := clientFactory.NewInputReader()
+	ioOpts, err := bufferOptions(flags.BufferSize, flags.ChunkSize)
 	if err != nil {
-		return fmt.Errorf("InputReaderの作成に失敗しました: %w", err)
+		return err
 	}
-
-	// 3. 読み込みストリームのオープン
-	rc, err := inputReader.Open(ctx, inputPath)
+	localOpts, err := parseLocalFileOptions(flags.FileMode, flags.DirMode, flags.Fsync)
 	if err != nil {
-		return fmt.Errorf("入力ストリームのオープンに失敗しました (%s): %w", inputPath, err)
+		return err
 	}
-	defer rc.Close() // 読み込みストリームは必ずクローズする
+	ioOpts = append(ioOpts, localOpts.options()...)
+	if flags.Raw {
+		ioOpts = append(ioOpts, remoteio.WithReadOptions(remoteio.WithReadCompressed(true)))
+	}
+	readerOpts := ioOpts
+	inputReader, err := clientFactory.NewInputReader(readerOpts...)
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+	if flags.NoClobber {
+		// 以降の ioOpts は OutputWriter の作成にのみ使用する
+		ioOpts = append(ioOpts, remoteio.WithNoClobber())
+		localOpts.noClobber = true
+	}
+	kmsOpts, err := kmsKeyOptions(flags.KMSKey, flags.OutputFilename)
+	if err != nil {
+		return err
+	}
+	ioOpts = append(ioOpts, kmsOpts...)
 
-	// 4. 出力先の決定とデータの転送
-	if flags.OutputFilename != "" {
-		outputPath := flags.OutputFilename
+	reporter, err := flags.progressReporter()
+	if err != nil {
+		return err
+	}
+	if reporter != nil {
+		defer reporter.Finish()
+	}
 
-		if remoteio.IsGCSURI(outputPath) {
-			// GCS URIが指定された場合
-			writer, err := clientFactory.NewOutputWriter()
-			if err != nil {
-				return fmt.Errorf("GCSOutputWriterの作成に失敗しました: %w", err)
+	transform, err := cryptTransform(ctx, flags.Encrypt, flags.Decrypt, flags.EncryptionKeyFile, flags.EncryptionKMSKey)
+	if err != nil {
+		return err
+	}
+	if flags.Encrypt {
+		// 暗号化した内容は拡張子や内容から Content-Type を判定できないため、バイナリとする (--content-type で上書きできる)
+		objectOpts = append([]remoteio.WriteOption{remoteio.WithContentType("application/octet-stream")}, objectOpts...)
+	}
+	multiple := inv.multiple()
+	fromStdin := inv.fromStdin()
+	if multiple {
+		if !isDirDestination(flags.OutputFilename) {
+			return usageError(fmt.Errorf(tr("複数のコピー元を指定する場合は、コピー先に末尾が \"/\" の URI または既存のローカルディレクトリを指定してください: %s"), flags.OutputFilename))
+		}
+		destinations := make(map[string]bool, len(args))
+		for _, job := range multipleSourceJobs(args, flags.OutputFilename, flags.AutoDecompress) {
+			if destinations[job.Destination] {
+				return usageError(fmt.Errorf(tr("複数のコピー元が同じ書き込み先になります: %s"), job.Destination))
 			}
-
-			// writerがGCSOutputWriterインターフェースを満たすかチェック
-			gcsWriter, ok := writer.(remoteio.GCSOutputWriter)
-			if !ok {
-				return fmt.Errorf("FactoryがGCS出力用のWriterインターフェース(remoteio.GCSOutputWriter)を提供していません")
+			destinations[job.Destination] = true
+		}
+	}
+	var splitSize int64
+	if flags.SplitSize != "" {
+		if splitSize, err = parseByteSize(flags.SplitSize); err != nil {
+			return err
+		}
+		if splitSize <= 0 {
+			return usageError(fmt.Errorf(tr("--split-size には正のサイズを指定してください: %s"), flags.SplitSize))
+		}
+	}
+	if appFlagsFrom(cmd.Context()).DryRun {
+		return planRcopy(cmd, inputReader, args, flags)
+	}
+	results, err := newResultWriter(cmd, inputReader).withManifest(clientFactory, inputReader, flags.Manifest)
+	if err != nil {
+		return err
+	}
+	defer results.closeManifest(ctx, &err)
+	if results, err = results.withHooks(cmd, inputReader, flags.Hooks); err != nil {
+		return err
+	}
+	defer results.summarizeHooks()
+	transferOpts := transferOptions{preserve: flags.Preserve, noClobber: flags.NoClobber, force: flags.Force, writeOpts: objectOpts, transform: transform, decompress: flags.AutoDecompress,
+		raw: flags.Raw, verify: flags.Verify || flags.VerifyMD5, verifyMD5: flags.VerifyMD5, skipIdentical: flags.SkipIdentical, results: results}
+	if flags.Stats {
+		transferOpts.stats = cmd.ErrOrStderr()
+	}
+	// 書き込み時に指定するオプションや内容の変換がある場合は、サーバー側のコピーと並行アップロードは行わない
+	writeOnlyOpts := append(preconditionOpts, objectOpts...)
+	// --format json では1つのファイルのコピーの結果を出力する (-r と複数のコピー元の場合は runTransfers がファイルごとに出力する)
+	status := statusCopied
+	if tee {
+		return runRcopyTee(cmd, clientFactory, inputReader, inputPath, flags, ioOpts, transferOpts, reporter)
+	}
+	if splitSize > 0 {
+		return runRcopySplit(cmd, clientFactory, inputReader, inputPath, splitSize, flags, ioOpts, transferOpts, reporter)
+	}
+	if !flags.Recursive && !multiple {
+		start := time.Now()
+		defer func() {
+			if transferOpts.stats != nil {
+				printStats(transferOpts.stats, singleCopyStats(ctx, inputReader, inputPath, flags.OutputFilename, status, time.Since(start), err))
 			}
-
-			// URIをバケット名とオブジェクトパスにパース
-			bucket, object, err := remoteio.ParseGCSURI(outputPath)
 			if err != nil {
-				return fmt.Errorf("GCS URIのパースに失敗しました: %w", err)
+				results.failed(inputPath, flags.OutputFilename, err)
+				return
 			}
+			results.transferred(ctx, inputPath, flags.OutputFilename, status)
+		}()
+	}
+	if flags.Continue {
+		return continueDownload(ctx, inputReader, inputPath, flags, reporter)
+	}
+	if flags.Recursive {
+		return runRcopyRecursive(cmd, clientFactory, inputReader, inputPath, flags, ioOpts, transferOpts, reporter)
+	}
+	if multiple {
+		return runRcopyMultiple(cmd, clientFactory, inputReader, args, flags, ioOpts, transferOpts, reporter)
+	}
 
-			slog.Info("データ転送開始",
-				slog.String("input", inputPath),
-				slog.String("output", outputPath),
-				slog.String("type", "GCS"),
-			)
+	// --skip-identical が指定され、書き込み先の内容がコピー元と同じ場合は転送しない
+	identical, err := transferOpts.identical(ctx, inputReader, inputPath, flags.OutputFilename)
+	if err != nil {
+		return err
+	}
+	if identical {
+		reportIdentical(inputPath, flags.OutputFilename)
+		status = statusIdentical
+		return nil
+	}
 
-			if err := gcsWriter.WriteToGCS(ctx, bucket, object, rc, ""); err != nil {
-				return fmt.Errorf("GCSへのコンテンツ書き込みに失敗しました: %w", err)
+	// --no-clobber または --force が指定された場合は、既存の書き込み先を確認し、スキップ・上書きしたことを報告する
+	if flags.OutputFilename != "" {
+		opts := transferOptions{noClobber: flags.NoClobber, force: flags.Force}
+		existed, existsErr := opts.destinationExists(ctx, inputReader, flags.OutputFilename)
+		if existsErr != nil {
+			return existsErr
+		}
+		if existed && flags.NoClobber {
+			reportSkipped(inputPath, flags.OutputFilename)
+			status = statusSkipped
+			return nil
+		}
+		defer func() {
+			switch {
+			case errors.Is(err, remoteio.ErrAlreadyExists):
+				// 確認の後に他のプロセスが作成した場合も、書き込まずにスキップする
+				reportSkipped(inputPath, flags.OutputFilename)
+				status = statusSkipped
+				err = nil
+			case err == nil && existed:
+				reportOverwritten(inputPath, flags.OutputFilename)
+				status = statusOverwritten
 			}
+		}()
+	}
 
-			return nil
+	// 分割ダウンロードは範囲読み込みができるリモートのコピー元で、分割アップロードはローカルファイルから GCS への場合に行う
+	sliceOpts, err := flags.sliceOptions()
+	if err != nil {
+		return err
+	}
+	slicer, sliced := inputReader.(remoteio.SlicedInputReader)
+	sliced = sliced && sliceOpts != nil && remoteio.SchemeOf(inputPath) != ""
 
-		} else {
-			// ローカルファイルが指定された場合
-			writer, err := clientFactory.NewOutputWriter()
+	// 3. 出力先の決定
+	var writer remoteio.OutputWriter
+	if flags.OutputFilename != "" {
+		validatorOpts, err := flags.writerOptions()
+		if err != nil {
+			return err
+		}
+		writerOpts := append(ioOpts, validatorOpts...)
+		newWriter := func() (remoteio.OutputWriter, error) {
+			w, err := clientFactory.NewOutputWriter(writerOpts...)
 			if err != nil {
-				return fmt.Errorf("LocalOutputWriterの作成に失敗しました: %w", err)
+				return nil, fmt.Errorf(tr("OutputWriterの作成に失敗しました")+": %w", err)
 			}
+			return w, nil
+		}
 
-			// writerがLocalOutputWriterインターフェースを満たすかチェック
-			localWriter, ok := writer.(remoteio.LocalOutputWriter)
-			if !ok {
-				return fmt.Errorf("Factoryがローカルファイル出力用のWriterインターフェース(remoteio.LocalOutputWriter)を提供していません")
+		if (sliceOpts != nil || flags.Resumable) && !flags.Append && len(validatorOpts) == 0 && writeOnlyOpts == nil && !transferOpts.streamsContent() && remoteio.SchemeOf(inputPath) == "" && remoteio.IsGCSURI(flags.OutputFilename) {
+			uploadOpts := append([]remoteio.SliceOption{remoteio.WithSliceParallelism(flags.Parallel)}, sliceOpts...)
+			if flags.Resumable {
+				checkpoint, err := uploadCheckpointPath(inputPath, flags.OutputFilename)
+				if err != nil {
+					return err
+				}
+				uploadOpts = append(uploadOpts, remoteio.WithUploadCheckpoint(checkpoint))
+			}
+			if writer, err = newWriter(); err != nil {
+				return err
 			}
+			return uploadParallelToGCS(ctx, writer, inputPath, flags.OutputFilename, uploadOpts, reporter)
+		}
 
-			slog.Info("データ転送開始",
-				slog.String("input", inputPath),
-				slog.String("output", outputPath),
-				slog.String("type", "LocalFile"),
-			)
+		// ローカルファイルへの分割ダウンロードは、各範囲をファイルの対応する位置へ直接書き込む
+		// (バリデータは内容を先頭から順に検査するため、指定された場合はストリームとして書き込む)
+		if sliced && !flags.Append && len(validatorOpts) == 0 && !transferOpts.streamsContent() && remoteio.SchemeOf(flags.OutputFilename) == "" {
+			return downloadSlicedToFile(ctx, inputReader, slicer, inputPath, flags.OutputFilename, localOpts, flags.Preserve, sliceOpts, reporter)
+		}
 
-			// WriteToLocalにrcを渡して書き込みを実行
-			if err := localWriter.WriteToLocal(ctx, outputPath, rc); err != nil {
-				return fmt.Errorf("ローカルファイルへの書き込みに失敗しました: %w", err)
+		// 標準入力からのコピーと --append 以外は、サーバー側のコピーと内容の転送の振り分け、再試行、進捗と検証を transfer.Run が行う
+		if !fromStdin && !flags.Append {
+			runOpts := []transfer.RunOption{
+				transfer.WithReaderOptions(readerOpts...),
+				transfer.WithWriterOptions(writerOpts...),
+				transfer.WithWriteOptions(preconditionOpts...),
 			}
+			if sliced {
+				runOpts = append(runOpts, transfer.WithSliceOptions(sliceOpts...))
+			}
+			return runRcopyFile(ctx, clientFactory, inputReader, inputPath, flags.OutputFilename, transferOpts, reporter, runOpts...)
+		}
+		if writer, err = newWriter(); err != nil {
+			return err
+		}
+	}
 
-			return nil
+	// 4. 読み込みストリームのオープン (分割ダウンロードの場合は、後続の範囲を並行して先読みする)
+	var rc io.ReadCloser
+	switch {
+	case fromStdin:
+		// 長さが不明なストリームとして、終端まで読み込んだ内容を書き込む
+		rc = io.NopCloser(cmd.InOrStdin())
+	case sliced:
+		rc, err = slicer.OpenSliced(ctx, inputPath, sliceOpts...)
+	default:
+		rc, err = transferOpts.open(ctx, inputReader, inputPath)
+	}
+	if err != nil {
+		return fmt.Errorf(tr("入力ストリームのオープンに失敗しました (%s)")+": %w", inputPath, err)
+	}
+	defer rc.Close() // 読み込みストリームは必ずクローズする
+
+	// 進捗出力が指定された場合は、読み込みストリームを計測用リーダーでラップする
+	var src io.Reader = rc
+	if reporter != nil {
+		total := streamSize(rc)
+		if sliced || total < 0 {
+			total = objectSize(ctx, inputReader, inputPath)
+		}
+		src = reporter.Track(inputPath, total, rc)
+	}
+	verification, src, err := transferOpts.startVerification(ctx, inputReader, inputPath, src)
+	if err != nil {
+		return err
+	}
+	if transferOpts.rewritesContent() {
+		cr, err := transferOpts.convert(ctx, inputPath, src)
+		if err != nil {
+			return err
+		}
+		defer cr.Close()
+		src = cr
+	}
+
+	// 5. データの転送
+	if writer != nil {
+		outputPath := flags.OutputFilename
+
+		// 出力先の種類はURIのスキームで判別し、書き込みは OutputWriter.Write に委譲する
+		outputType := remoteio.SchemeOf(outputPath)
+		if outputType == "" {
+			outputType = "LocalFile"
+		}
+		logger().Info(tr("データ転送開始"),
+			slog.String("input", inputPath),
+			slog.String("output", outputPath),
+			slog.String("type", outputType),
+		)
+
+		if flags.Append {
+			return appendToGCS(ctx, writer, outputPath, src)
+		}
+		writeOpts, err := preserveOptions(ctx, inputReader, inputPath, outputPath, flags.Preserve)
+		if err != nil {
+			return err
+		}
+		writeOpts = append(writeOpts, writeOnlyOpts...)
+		writeOpts = append(writeOpts, transferOpts.verifyOptions()...)
+		if err := writer.Write(ctx, outputPath, src, writeOpts...); err != nil {
+			return fmt.Errorf(tr("出力先への書き込みに失敗しました (%s)")+": %w", outputPath, err)
 		}
+		return verification.finish(ctx, writer, outputPath)
 	} else {
 		// 標準出力に出力する場合
 		writer := os.Stdout
 
-		slog.Info("データ転送開始",
+		logger().Info(tr("データ転送開始"),
 			slog.String("input", inputPath),
 			slog.String("output", "stdout"),
 			slog.String("type", "Stdout"),
 		)
 
-		// 5. 読み込みと書き込みの実行 (標準出力の場合)
-		if _, err := io.Copy(writer, rc); err != nil {
-			return fmt.Errorf("データの転送中にエラーが発生しました: %w", err)
+		// 6. 読み込みと書き込みの実行 (標準出力の場合)
+		if _, err := io.Copy(writer, src); err != nil {
+			return fmt.Errorf(tr("データの転送中にエラーが発生しました")+": %w", err)
 		}
-		return nil
+		return verification.finish(ctx, nil, "")
+	}
+}
+
+// runRcopyFile は、1つのファイル inputPath を outputPath へ transfer.Run で転送します。
+// opts の書き込みのオプション、内容の展開・変換・検証と、--retries、--retry-backoff の再試行を適用し、進捗を reporter へ出力します。
+func runRcopyFile(ctx context.Context, clientFactory factory.Factory, inputReader remoteio.InputReader, inputPath, outputPath string, opts transferOptions, reporter *progressReporter, runOpts ...transfer.RunOption) error {
+	// 出力先の種類はURIのスキームで判別し、書き込みは OutputWriter.Write に委譲する
+	outputType := remoteio.SchemeOf(outputPath)
+	if outputType == "" {
+		outputType = "LocalFile"
+	}
+	logger().Info(tr("データ転送開始"),
+		slog.String("input", inputPath),
+		slog.String("output", outputPath),
+		slog.String("type", outputType),
+	)
+
+	fileOpts, err := opts.runOptions(ctx, inputReader, inputPath, outputPath, reporter)
+	if err != nil {
+		return err
+	}
+	if reporter != nil {
+		reporter.Start(inputPath, objectSize(ctx, inputReader, inputPath))
+	}
+	runOpts = append(runOpts, fileOpts...)
+	runOpts = append(runOpts, transfer.WithEngineOptions(transferEngineOptions(ctx)...))
+	return transfer.Run(ctx, clientFactory, inputPath, outputPath, runOpts...)
+}
+
+// runRcopyRecursive は、inputPath 配下のすべてのファイルを、相対パスを保ったまま -o の配下へコピーします。
+// opts は各ファイルの転送に適用します。reporter が nil でない場合は、すべてのファイルの合計を1つの進捗として出力します。
+func runRcopyRecursive(cmd *cobra.Command, clientFactory factory.Factory, inputReader remoteio.InputReader, inputPath string, flags *rcopyFlags, ioOpts []remoteio.Option, opts transferOptions, reporter *progressReporter) error {
+	ctx := cmd.Context()
+
+	outputPath := flags.OutputFilename
+	if outputPath == "" {
+		return usageError(errors.New(tr("-r を指定する場合はコピー先を指定してください")))
+	}
+
+	objects, jobs, err := recursiveJobs(ctx, inputReader, inputPath, outputPath, opts.decompress)
+	if err != nil {
+		return err
+	}
+
+	writerOpts, err := flags.writerOptions()
+	if err != nil {
+		return err
+	}
+	writer, err := clientFactory.NewOutputWriter(append(ioOpts, writerOpts...)...)
+	if err != nil {
+		return fmt.Errorf(tr("OutputWriterの作成に失敗しました")+": %w", err)
+	}
+
+	var total int64
+	for _, obj := range objects {
+		total += obj.Size
+	}
+	if reporter != nil {
+		reporter.Start(inputPath, total)
+	}
+
+	logger().Info(tr("再帰コピー開始"),
+		slog.String("input", inputPath),
+		slog.String("output", outputPath),
+		slog.Int("files", len(objects)),
+		slog.Int64("bytes", total),
+	)
+
+	if err := runTransfers(ctx, inputReader, writer, jobs, flags.Parallel, opts, reporter); err != nil {
+		return err
+	}
+
+	logger().Info(tr("再帰コピー完了"), slog.Int("files", len(objects)), slog.Int64("bytes", total))
+	return nil
+}
+
+// runRcopyMultiple は、複数のコピー元 sources を、-o のディレクトリの配下へそれぞれ同じファイル名でコピーします。
+// opts は各ファイルの転送に適用します。reporter が nil でない場合は、すべてのファイルの合計を1つの進捗として出力します。
+func runRcopyMultiple(cmd *cobra.Command, clientFactory factory.Factory, inputReader remoteio.InputReader, sources []string, flags *rcopyFlags, ioOpts []remoteio.Option, opts transferOptions, reporter *progressReporter) error {
+	ctx := cmd.Context()
+	outputPath := flags.OutputFilename
+
+	writerOpts, err := flags.writerOptions()
+	if err != nil {
+		return err
+	}
+	writer, err := clientFactory.NewOutputWriter(append(ioOpts, writerOpts...)...)
+	if err != nil {
+		return fmt.Errorf(tr("OutputWriterの作成に失敗しました")+": %w", err)
+	}
+
+	if reporter != nil {
+		var total int64
+		for _, src := range sources {
+			if size := objectSize(ctx, inputReader, src); size > 0 {
+				total += size
+			}
+		}
+		reporter.Start(outputPath, total)
+	}
+
+	logger().Info(tr("複数ファイルのコピー開始"), slog.String("output", outputPath), slog.Int("files", len(sources)))
+	return runTransfers(ctx, inputReader, writer, multipleSourceJobs(sources, outputPath, opts.decompress), flags.Parallel, opts, reporter)
+}
+
+// validateTee は、-o を複数指定した場合 (コピー元を1回だけ読み込み、すべての出力先へ書き出す) の各出力先を検証します。
+// フラグの組み合わせは rcopyFlagRules で検証します。asJSON は --format json が指定されたかどうかです。
+func (f *rcopyFlags) validateTee(asJSON bool) error {
+	objectOpts, err := f.objectOptions()
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(f.Outputs))
+	for _, out := range f.Outputs {
+		if seen[out] {
+			return usageError(fmt.Errorf(tr("-o に同じ出力先が重複しています: %s"), out))
+		}
+		seen[out] = true
+		if out == stdioPath && asJSON {
+			return usageError(errors.New(tr("--format json では、-o に標準出力 (-) を指定できません")))
+		}
+		if objectOpts != nil && !slices.Contains([]string{"gs", "s3", "az"}, remoteio.SchemeOf(out)) {
+			return usageError(errors.New(tr("--content-type、--metadata、--cache-control、--content-encoding、--content-disposition、--content-language と --gzip は、コピー先に GCS / S3 / Azure の URI を指定した場合にのみ指定できます (--append は併用できません)")))
+		}
+		if _, err := kmsKeyOptions(f.KMSKey, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// teeJobs は、コピー元 src を -o の各出力先 outputs へ書き出す転送を返します。標準出力の書き込み先は空です。
+func teeJobs(src string, outputs []string) []transfer.Job {
+	jobs := make([]transfer.Job, len(outputs))
+	for i, out := range outputs {
+		if out == stdioPath {
+			out = ""
+		}
+		jobs[i] = transfer.Job{Source: src, Destination: out}
+	}
+	return jobs
+}
+
+// runRcopyTee は、inputPath を1回だけ読み込み、-o で指定したすべての出力先へ remoteio.MultiWrite で同時に書き出します。
+// 失敗した出力先があっても、残りの出力先への書き込みを続け、出力先ごとの結果を出力します。
+func runRcopyTee(cmd *cobra.Command, clientFactory factory.Factory, inputReader remoteio.InputReader, inputPath string, flags *rcopyFlags, ioOpts []remoteio.Option, opts transferOptions, reporter *progressReporter) error {
+	ctx := cmd.Context()
+
+	writerOpts, err := flags.writerOptions()
+	if err != nil {
+		return err
+	}
+	writer, err := clientFactory.NewOutputWriter(append(ioOpts, writerOpts...)...)
+	if err != nil {
+		return fmt.Errorf(tr("OutputWriterの作成に失敗しました")+": %w", err)
+	}
+	writeOpts, err := preserveOptions(ctx, inputReader, inputPath, "", flags.Preserve)
+	if err != nil {
+		return err
+	}
+	writeOpts = append(writeOpts, opts.writeOpts...)
+
+	var rc io.ReadCloser
+	if inputPath == stdioPath {
+		rc = io.NopCloser(cmd.InOrStdin())
+	} else if rc, err = opts.open(ctx, inputReader, inputPath); err != nil {
+		return fmt.Errorf(tr("入力ストリームのオープンに失敗しました (%s)")+": %w", inputPath, err)
+	}
+	defer rc.Close()
+	var src io.Reader = rc
+	if reporter != nil {
+		total := streamSize(rc)
+		if total < 0 {
+			total = objectSize(ctx, inputReader, inputPath)
+		}
+		src = reporter.Track(inputPath, total, rc)
+	}
+	if opts.rewritesContent() {
+		cr, err := opts.convert(ctx, inputPath, src)
+		if err != nil {
+			return err
+		}
+		defer cr.Close()
+		src = cr
+	}
+	var written atomic.Int64
+	src = &countingReader{r: src, n: &written}
+
+	logger().Info(tr("データ転送開始"), slog.String("input", inputPath), slog.Any("outputs", flags.Outputs))
+	start := time.Now()
+	err = remoteio.MultiWrite(ctx, teeWriter{OutputWriter: writer, stdout: os.Stdout}, flags.Outputs, src, writeOpts...)
+	elapsed := time.Since(start)
+
+	// 出力先ごとの結果を出力する (読み込みが失敗した場合は、すべての出力先を失敗とする)
+	failed := map[string]error{}
+	var multiErr *remoteio.MultiWriteError
+	if errors.As(err, &multiErr) {
+		for _, f := range multiErr.Failures {
+			failed[f.URI] = f.Err
+		}
+	}
+	stats := &transfer.Stats{Elapsed: elapsed}
+	var failures []remoteio.WriteFailure
+	for i, job := range teeJobs(inputPath, flags.Outputs) {
+		out := flags.Outputs[i]
+		dstErr, ok := failed[out]
+		if err != nil && multiErr == nil {
+			dstErr, ok = err, true
+		}
+		jobStats := transfer.JobStats{Job: job, Duration: elapsed}
+		switch {
+		case errors.Is(dstErr, remoteio.ErrAlreadyExists):
+			reportSkipped(inputPath, displayDestination(job.Destination))
+			opts.results.transferred(ctx, job.Source, job.Destination, statusSkipped)
+		case ok:
+			jobStats.Err = dstErr
+			failures = append(failures, remoteio.WriteFailure{URI: out, Err: dstErr})
+			opts.results.failed(job.Source, job.Destination, dstErr)
+		default:
+			jobStats.Bytes = written.Load()
+			opts.results.transferred(ctx, job.Source, job.Destination, statusCopied)
+		}
+		stats.Jobs = append(stats.Jobs, jobStats)
+	}
+	if opts.stats != nil {
+		printStats(opts.stats, stats)
+	}
+	if err != nil && multiErr == nil {
+		return err
+	}
+	if failures != nil {
+		// --no-clobber でスキップした出力先は失敗に含めない
+		return &remoteio.MultiWriteError{Failures: failures, Total: len(flags.Outputs)}
+	}
+	return nil
+}
+
+// runRcopySplit は、inputPath の内容を splitSize バイトごとに remoteio.SplitWrite で -o の連番のパートへ分割して書き込みます。
+// 書き込んだパートごとの結果を出力します。
+func runRcopySplit(cmd *cobra.Command, clientFactory factory.Factory, inputReader remoteio.InputReader, inputPath string, splitSize int64, flags *rcopyFlags, ioOpts []remoteio.Option, opts transferOptions, reporter *progressReporter) error {
+	ctx := cmd.Context()
+	outputPath := flags.OutputFilename
+
+	writerOpts, err := flags.writerOptions()
+	if err != nil {
+		return err
+	}
+	writer, err := clientFactory.NewOutputWriter(append(ioOpts, writerOpts...)...)
+	if err != nil {
+		return fmt.Errorf(tr("OutputWriterの作成に失敗しました")+": %w", err)
+	}
+
+	var rc io.ReadCloser
+	if inputPath == stdioPath {
+		rc = io.NopCloser(cmd.InOrStdin())
+	} else if rc, err = opts.open(ctx, inputReader, inputPath); err != nil {
+		return fmt.Errorf(tr("入力ストリームのオープンに失敗しました (%s)")+": %w", inputPath, err)
+	}
+	defer rc.Close()
+	var src io.Reader = rc
+	if reporter != nil {
+		total := streamSize(rc)
+		if total < 0 {
+			total = objectSize(ctx, inputReader, inputPath)
+		}
+		src = reporter.Track(inputPath, total, rc)
+	}
+	if opts.rewritesContent() {
+		cr, err := opts.convert(ctx, inputPath, src)
+		if err != nil {
+			return err
+		}
+		defer cr.Close()
+		src = cr
+	}
+
+	logger().Info(tr("分割書き込み開始"), slog.String("input", inputPath), slog.String("output", outputPath), slog.Int64("split_size", splitSize))
+	start := time.Now()
+	parts, err := remoteio.SplitWrite(ctx, writer, outputPath, src, splitSize, opts.writeOpts...)
+	elapsed := time.Since(start)
+
+	// パートごとの結果を出力する
+	stats := &transfer.Stats{Elapsed: elapsed}
+	var total int64
+	for _, part := range parts {
+		total += part.Size
+		opts.results.transferred(ctx, inputPath, part.URI, statusCopied)
+		stats.Jobs = append(stats.Jobs, transfer.JobStats{Job: transfer.Job{Source: inputPath, Destination: part.URI}, Bytes: part.Size, Duration: elapsed})
+	}
+	if err != nil {
+		next := remoteio.SplitPartURI(outputPath, len(parts)+1)
+		opts.results.failed(inputPath, next, err)
+		stats.Jobs = append(stats.Jobs, transfer.JobStats{Job: transfer.Job{Source: inputPath, Destination: next}, Duration: elapsed, Err: err})
+	}
+	if opts.stats != nil {
+		printStats(opts.stats, stats)
+	}
+	if err != nil {
+		return err
+	}
+
+	// 以前により多くのパートに分割して書き込んでいた場合は、連結すると古い内容が含まれるため警告する
+	if stater, ok := inputReader.(remoteio.Stater); ok {
+		next := remoteio.SplitPartURI(outputPath, len(parts)+1)
+		if exists, err := stater.Exists(ctx, next); err == nil && exists {
+			logger().Warn(tr("以前に書き込んだ後続のパートが残っています。rcat --join で連結する前に削除してください"), slog.String("uri", next))
+		}
+	}
+	logger().Info(tr("分割書き込み完了"), slog.Int("parts", len(parts)), slog.Int64("bytes", total))
+	return nil
+}
+
+// teeWriter は、-o を複数指定した場合に、標準出力 (-) への書き込みを stdout へ、それ以外を OutputWriter へ委譲します。
+type teeWriter struct {
+	remoteio.OutputWriter
+	stdout io.Writer
+}
+
+// Write は remoteio.OutputWriter インターフェースを実装します。標準出力への書き込みでは opts を無視します。
+func (w teeWriter) Write(ctx context.Context, destURI string, r io.Reader, opts ...remoteio.WriteOption) error {
+	if destURI != stdioPath {
+		return w.OutputWriter.Write(ctx, destURI, r, opts...)
+	}
+	if _, err := io.Copy(w.stdout, r); err != nil {
+		return fmt.Errorf(tr("データの転送中にエラーが発生しました")+": %w", err)
+	}
+	return nil
+}
+
+// multipleSourceJobs は、コピー元 sources をディレクトリ dir の配下へそれぞれ同じファイル名でコピーする転送を返します。
+// decompress の場合は、展開したファイルを圧縮形式の拡張子を除いた名前で書き込みます。
+func multipleSourceJobs(sources []string, dir string, decompress bool) []transfer.Job {
+	jobs := make([]transfer.Job, len(sources))
+	for i, src := range sources {
+		name, _ := remoteio.SplitGCSGeneration(src)
+		dst := moveDestination(name, dir)
+		if decompress {
+			dst, _ = remoteio.TrimCompressionExt(dst)
+		}
+		jobs[i] = transfer.Job{Source: src, Destination: dst}
+	}
+	return jobs
+}
+
+// recursiveJobs は、inputPath 配下のすべてのファイルと、それぞれを outputPath の配下へコピーする転送を返します。
+// decompress の場合は、展開したファイルを圧縮形式の拡張子を除いた名前で書き込みます。
+func recursiveJobs(ctx context.Context, inputReader remoteio.InputReader, inputPath, outputPath string, decompress bool) ([]remoteio.ObjectInfo, []transfer.Job, error) {
+	lister, ok := inputReader.(remoteio.ObjectLister)
+	if !ok {
+		return nil, nil, errors.New(tr("InputReaderが一覧の取得をサポートしていません"))
+	}
+	objects, err := lister.ListObjects(ctx, inputPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf(tr("コピー元の一覧取得に失敗しました (%s)")+": %w", inputPath, err)
+	}
+	if len(objects) == 0 {
+		return nil, nil, notFoundError(tr("コピー対象のファイルが見つかりません: %s"), inputPath)
+	}
+
+	jobs := make([]transfer.Job, len(objects))
+	for i, obj := range objects {
+		name := obj.Name
+		if decompress {
+			name, _ = remoteio.TrimCompressionExt(name)
+		}
+		jobs[i] = transfer.Job{Source: obj.URI, Destination: remoteio.JoinURI(outputPath, name)}
+	}
+	return objects, jobs, nil
+}
+
+// planRcopy は、--dry-run で rcopy がコピー元 sources からコピーするファイルを表示します。書き込み先は変更しません。
+// --no-clobber と --skip-identical が指定された場合は、スキップされるファイルも表示します。
+func planRcopy(cmd *cobra.Command, inputReader remoteio.InputReader, sources []string, flags *rcopyFlags) error {
+	ctx := cmd.Context()
+	opts := transferOptions{noClobber: flags.NoClobber, skipIdentical: flags.SkipIdentical}
+
+	var objects []remoteio.ObjectInfo
+	var jobs []transfer.Job
+	if flags.Recursive {
+		if flags.OutputFilename == "" {
+			return usageError(errors.New(tr("-r を指定する場合はコピー先を指定してください")))
+		}
+		var err error
+		if objects, jobs, err = recursiveJobs(ctx, inputReader, sources[0], flags.OutputFilename, flags.AutoDecompress); err != nil {
+			return err
+		}
+	} else {
+		jobs = []transfer.Job{{Source: sources[0], Destination: flags.OutputFilename}}
+		switch {
+		case len(sources) > 1:
+			jobs = multipleSourceJobs(sources, flags.OutputFilename, flags.AutoDecompress)
+		case len(flags.Outputs) > 1:
+			jobs = teeJobs(sources[0], flags.Outputs)
+		}
+		for _, job := range jobs {
+			size, err := statSize(ctx, inputReader, job.Source)
+			if err != nil {
+				return err
+			}
+			objects = append(objects, remoteio.ObjectInfo{URI: job.Source, Size: size})
+		}
+	}
+
+	plan := newDryRunPlan(cmd)
+	for i, job := range jobs {
+		if job.Destination != "" {
+			identical, err := opts.identical(ctx, inputReader, job.Source, job.Destination)
+			if err != nil {
+				return err
+			}
+			if identical {
+				plan.skip(job.Source, job.Destination, statusIdentical)
+				continue
+			}
+			existed, err := opts.destinationExists(ctx, inputReader, job.Destination)
+			if err != nil {
+				return err
+			}
+			if existed {
+				plan.skip(job.Source, job.Destination, statusSkipped)
+				continue
+			}
+		}
+		plan.transfer(job.Source, job.Destination, objects[i].Size)
+	}
+	plan.summary()
+	return nil
+}
+
+// transferRetries は、複数ファイルの転送で、失敗したファイルごとに再試行する回数の既定値です (--retries で変更できます)。
+const transferRetries = 2
+
+// runTransfers は、jobs を最大 parallel 件ずつ並行して copyObject で転送します。
+// 失敗したファイルは --retries と --retry-backoff に従って再試行し、それでも失敗したファイルがある場合は、すべての転送が終わってからまとめてエラーを返します。
+// 各ファイルの扱い (更新日時の保持、既存の書き込み先のスキップ・上書きの報告) は opts で指定します。
+func runTransfers(ctx context.Context, reader remoteio.InputReader, writer remoteio.OutputWriter, jobs []transfer.Job, parallel int, opts transferOptions, reporter *progressReporter) error {
+	engineOpts := append(transferEngineOptions(ctx), transfer.WithParallelism(parallel))
+	if opts.stopOnFailure {
+		engineOpts = append(engineOpts, transfer.WithStopOnFailure())
+	}
+	engine := transfer.New(engineOpts...)
+	stats, err := engine.RunWithStats(ctx, jobs, func(ctx context.Context, job transfer.Job) error {
+		jobOpts := opts.forJob(job)
+		identical, err := jobOpts.identical(ctx, reader, job.Source, job.Destination)
+		if err != nil {
+			return err
+		}
+		if identical {
+			reportIdentical(job.Source, job.Destination)
+			jobOpts.results.transferred(ctx, job.Source, job.Destination, statusIdentical)
+			return nil
+		}
+		existed, err := jobOpts.destinationExists(ctx, reader, job.Destination)
+		if err != nil {
+			return err
+		}
+		if existed && jobOpts.noClobber {
+			reportSkipped(job.Source, job.Destination)
+			jobOpts.results.transferred(ctx, job.Source, job.Destination, statusSkipped)
+			return nil
+		}
+		err = copyObject(ctx, reader, writer, job.Source, job.Destination, jobOpts, reporter)
+		switch {
+		case errors.Is(err, remoteio.ErrAlreadyExists):
+			reportSkipped(job.Source, job.Destination)
+			jobOpts.results.transferred(ctx, job.Source, job.Destination, statusSkipped)
+		case err != nil:
+			return err
+		case existed:
+			reportOverwritten(job.Source, job.Destination)
+			jobOpts.results.transferred(ctx, job.Source, job.Destination, statusOverwritten)
+		default:
+			logger().Info(tr("ファイルをコピーしました"), slog.String("source", job.Source), slog.String("destination", job.Destination))
+			jobOpts.results.transferred(ctx, job.Source, job.Destination, statusCopied)
+		}
+		return nil
+	})
+	if opts.stats != nil {
+		printStats(opts.stats, stats)
+	}
+	// 再試行しても失敗したファイルは、すべての転送が終わってから出力する
+	var transferErr *transfer.Error
+	if errors.As(err, &transferErr) {
+		for _, f := range transferErr.Failures {
+			opts.results.failed(f.Job.Source, f.Job.Destination, f.Err)
+		}
+		if transferErr.NotStarted > 0 {
+			reportCanceled(jobs, stats, opts.results)
+		}
+	}
+	return err
+}
+
+// transferEngineOptions は、失敗した転送を --retries と --retry-backoff に従って再試行する、転送エンジンのオプションを返します。
+func transferEngineOptions(ctx context.Context) []transfer.Option {
+	appFlags := appFlagsFrom(ctx)
+	retries := transferRetries
+	if appFlags.Retries >= 0 {
+		retries = appFlags.Retries
+	}
+	opts := []transfer.Option{
+		transfer.WithRetries(retries),
+		transfer.WithRetryIf(retryableTransferError),
+		transfer.WithLogger(logger()),
+	}
+	if appFlags.RetryBackoff > 0 {
+		opts = append(opts, transfer.WithRetryBackoff(appFlags.RetryBackoff))
+	}
+	return opts
+}
+
+// reportCanceled は、jobs のうち、先行する転送の失敗により開始しなかった転送を results に出力します。
+func reportCanceled(jobs []transfer.Job, stats *transfer.Stats, results *resultWriter) {
+	started := make(map[transfer.Job]bool, len(stats.Jobs))
+	for _, j := range stats.Jobs {
+		started[j.Job] = true
+	}
+	for _, job := range jobs {
+		if !started[job] {
+			results.canceled(job.Source, job.Destination)
+		}
+	}
+}
+
+// transferOptions は、runTransfers で転送する各ファイルの扱いです。
+type transferOptions struct {
+	preserve      bool                   // コピー元の更新日時をローカルファイルに設定する (--preserve)
+	noClobber     bool                   // 既存の書き込み先をスキップする (--no-clobber)
+	force         bool                   // 既存の書き込み先を上書きし、上書きしたことを報告する (--force)
+	writeOpts     []remoteio.WriteOption // すべての書き込みに指定するオプション (--metadata、--cache-control など)
+	transform     streamTransform        // 読み込んだ内容に適用する変換 (--encrypt、--decrypt)。nil の場合は変換しない
+	decompress    bool                   // コピー元の拡張子 (.gz / .zst) に応じて内容を展開する (--auto-decompress)
+	raw           bool                   // Content-Encoding: gzip の GCS オブジェクトを展開せずに読み込む (--raw)
+	verify        bool                   // 転送した内容のチェックサムをコピー元と書き込み先の属性と比較する (--verify)
+	verifyMD5     bool                   // --verify で MD5 も計算して比較する (--verify-md5)
+	skipIdentical bool                   // 書き込み先のサイズと CRC32C がコピー元と一致する場合はスキップする (--skip-identical)
+	results       *resultWriter          // ファイルごとの結果の出力先 (--format json)。nil の場合は出力しない
+	stats         io.Writer              // 転送の集計の出力先 (--stats)。nil の場合は出力しない
+	stopOnFailure bool                   // 再試行しても失敗したファイルがあった時点で、残りのファイルの転送を中止する (rbatch)
+
+	// jobOptions は、転送ごとに扱いを変える場合の、転送ごとの扱いです (rbatch)。
+	// 含まれる転送には、results と stats を除き、この transferOptions の代わりに適用します。
+	jobOptions map[transfer.Job]transferOptions
+}
+
+// forJob は、転送 job に適用する扱いを返します。
+func (o transferOptions) forJob(job transfer.Job) transferOptions {
+	jobOpts, ok := o.jobOptions[job]
+	if !ok {
+		return o
+	}
+	jobOpts.results, jobOpts.stats = o.results, o.stats
+	return jobOpts
+}
+
+// rewritesContent は、読み込んだ内容を展開または変換して書き込むかどうかを返します。
+func (o transferOptions) rewritesContent() bool {
+	return o.transform != nil || o.decompress
+}
+
+// streamsContent は、内容をクライアントで読み込んで書き込む必要があるかどうかを返します。
+// 内容を書き換える場合や検証する場合は、サーバー側のコピーや並行アップロードは行いません。
+func (o transferOptions) streamsContent() bool {
+	return o.rewritesContent() || o.verify
+}
+
+// opensCompressed は、コピー元 src を保存されている内容のまま (GCS で展開せずに) 開くかどうかを返します。
+// 展開する圧縮形式のコピー元は、二重に展開されないよう保存されている内容のまま開きます。
+func (o transferOptions) opensCompressed(src string) bool {
+	if o.raw {
+		return true
+	}
+	if !o.decompress {
+		return false
+	}
+	name, _ := remoteio.SplitGCSGeneration(src)
+	_, compressed := remoteio.TrimCompressionExt(name)
+	return compressed
+}
+
+// open は、コピー元 src を開きます。
+func (o transferOptions) open(ctx context.Context, reader remoteio.InputReader, src string) (io.ReadCloser, error) {
+	if !o.raw && o.opensCompressed(src) {
+		if opener, ok := reader.(remoteio.OptionOpener); ok {
+			return opener.OpenWith(ctx, src, remoteio.WithReadCompressed(true))
+		}
+	}
+	return reader.Open(ctx, src)
+}
+
+// convert は、コピー元 src から読み込んだ r に、展開 (--auto-decompress) と変換 (--encrypt、--decrypt) を適用します。
+// 返された io.ReadCloser は、転送後に必ず Close してください (r は閉じません)。
+func (o transferOptions) convert(ctx context.Context, src string, r io.Reader) (io.ReadCloser, error) {
+	rc := io.NopCloser(r)
+	if o.decompress {
+		name, _ := remoteio.SplitGCSGeneration(src)
+		drc, err := remoteio.DecompressReader(r, name)
+		if err != nil {
+			return nil, fmt.Errorf(tr("内容の展開に失敗しました (%s)")+": %w", src, err)
+		}
+		rc = drc
+	}
+	if o.transform != nil {
+		transformed, err := o.transform(ctx, rc)
+		if err != nil {
+			rc.Close()
+			return nil, fmt.Errorf(tr("内容の変換に失敗しました (%s)")+": %w", src, err)
+		}
+		rc = struct {
+			io.Reader
+			io.Closer
+		}{transformed, rc}
+	}
+	return rc, nil
+}
+
+// destinationExists は、--no-clobber または --force が指定された場合に、書き込み先 dst が既に存在するかどうかを確認します。
+// どちらも指定されていない場合は、確認せずに false を返します。
+func (o transferOptions) destinationExists(ctx context.Context, reader remoteio.InputReader, dst string) (bool, error) {
+	if !o.noClobber && !o.force {
+		return false, nil
+	}
+	stater, ok := reader.(remoteio.Stater)
+	if !ok {
+		return false, errors.New(tr("InputReaderが情報の取得をサポートしていません"))
+	}
+	exists, err := stater.Exists(ctx, dst)
+	if err != nil {
+		return false, fmt.Errorf(tr("書き込み先の存在の確認に失敗しました (%s)")+": %w", dst, err)
+	}
+	return exists, nil
+}
+
+// identical は、--skip-identical が指定された場合に、書き込み先 dst が存在し、サイズと CRC32C チェックサムがコピー元 src と一致するかどうかを返します。
+// チェックサムは GCS などの属性を使用し、ない場合 (ローカルファイルや S3 など) は内容を読み込んで計算します。
+// 指定されていない場合は、確認せずに false を返します。
+func (o transferOptions) identical(ctx context.Context, reader remoteio.InputReader, src, dst string) (bool, error) {
+	if !o.skipIdentical {
+		return false, nil
+	}
+	stater, ok := reader.(remoteio.Stater)
+	if !ok {
+		return false, errors.New(tr("InputReaderが情報の取得をサポートしていません"))
+	}
+	exists, err := stater.Exists(ctx, dst)
+	if err != nil {
+		return false, fmt.Errorf(tr("書き込み先の存在の確認に失敗しました (%s)")+": %w", dst, err)
+	}
+	if !exists {
+		return false, nil
+	}
+	srcInfo, err := stater.Stat(ctx, src)
+	if err != nil {
+		return false, fmt.Errorf(tr("コピー元の情報の取得に失敗しました (%s)")+": %w", src, err)
+	}
+	dstInfo, err := stater.Stat(ctx, dst)
+	if err != nil {
+		return false, fmt.Errorf(tr("書き込み先の情報の取得に失敗しました (%s)")+": %w", dst, err)
+	}
+	if srcInfo.IsPrefix || dstInfo.IsPrefix || srcInfo.Size != dstInfo.Size {
+		return false, nil
+	}
+	srcCRC, err := contentCRC32C(ctx, reader, srcInfo)
+	if err != nil {
+		return false, err
+	}
+	dstCRC, err := contentCRC32C(ctx, reader, dstInfo)
+	if err != nil {
+		return false, err
+	}
+	return srcCRC == dstCRC, nil
+}
+
+// reportIdentical は、--skip-identical により内容が同じ書き込み先へコピーしなかったことを報告します。
+func reportIdentical(src, dst string) {
+	logger().Info(tr("書き込み先の内容がコピー元と同じため、スキップしました"), slog.String("source", src), slog.String("destination", dst))
+}
+
+// reportSkipped は、--no-clobber により既存の書き込み先へコピーしなかったことを報告します。
+func reportSkipped(src, dst string) {
+	logger().Info(tr("書き込み先が既に存在するため、スキップしました"), slog.String("source", src), slog.String("destination", dst))
+}
+
+// reportOverwritten は、--force により既存の書き込み先を上書きしたことを報告します。
+func reportOverwritten(src, dst string) {
+	logger().Info(tr("既存の書き込み先を上書きしました"), slog.String("source", src), slog.String("destination", dst))
+}
+
+// retryableTransferError は、転送のエラーが再試行で成功する可能性があるかどうかを判定します。
+// バリデータによる拒否、復号の失敗、コピー元が存在しない場合、権限の不足や URI の誤りは、再試行しても結果が変わらないため再試行しません。
+func retryableTransferError(err error) bool {
+	switch {
+	case errors.Is(err, remoteio.ErrValidationFailed), errors.Is(err, remoteio.ErrDecryptionFailed):
+		return false
+	case errors.Is(err, remoteio.ErrNotFound), errors.Is(err, remoteio.ErrPermissionDenied), errors.Is(err, remoteio.ErrInvalidURI):
+		return false
+	case errors.Is(err, proxy.ErrUnsupportedOption):
+		return false
+	default:
+		return true
+	}
+}
+
+// copyObject は、src を開いて dst へ transfer.Copy で書き込みます。
+// サーバー側でコピーできる組み合わせ (GCS 間など) の場合は、データをクライアントに転送せずにコピーします。
+// ただし opts.writeOpts が指定された場合や内容を展開・変換・検証する場合は、内容を転送して書き込みます。
+func copyObject(ctx context.Context, reader remoteio.InputReader, writer remoteio.OutputWriter, src, dst string, opts transferOptions, reporter *progressReporter) error {
+	runOpts, err := opts.runOptions(ctx, reader, src, dst, reporter)
+	if err != nil {
+		return err
+	}
+	return transfer.Copy(ctx, reader, writer, src, dst, runOpts...)
+}
+
+// runOptions は、src から dst への転送に opts の扱いを適用する transfer.RunOption を返します。
+// reporter が nil でない場合は、転送したバイト数を進捗に加算します。
+func (o transferOptions) runOptions(ctx context.Context, reader remoteio.InputReader, src, dst string, reporter *progressReporter) ([]transfer.RunOption, error) {
+	writeOpts, err := preserveOptions(ctx, reader, src, dst, o.preserve)
+	if err != nil {
+		return nil, err
+	}
+	runOpts := []transfer.RunOption{
+		transfer.WithEngineOptions(transfer.WithLogger(logger())),
+		transfer.WithWriteOptions(append(writeOpts, o.writeOpts...)...),
+	}
+	if o.opensCompressed(src) {
+		runOpts = append(runOpts, transfer.WithCompressedRead())
+	}
+	if o.rewritesContent() {
+		runOpts = append(runOpts, transfer.WithReadTransform(func(ctx context.Context, r io.Reader) (io.ReadCloser, error) {
+			return o.convert(ctx, src, r)
+		}))
+	}
+	if o.verify {
+		runOpts = append(runOpts, transfer.WithVerification(o.verifyMD5))
+	}
+	if reporter != nil {
+		runOpts = append(runOpts, transfer.WithProgress(reporter.progressFunc()))
+	}
+	return runOpts, nil
+}
+
+// downloadSlicedToFile は、inputPath を範囲ごとに並行して読み込み、ローカルファイル outputPath の対応する位置へ書き込みます。
+// 失敗した場合は、書き込み途中のファイルを削除します。
+// preserve が指定された場合は、ファイルの更新日時をコピー元に合わせます。
+func downloadSlicedToFile(ctx context.Context, reader remoteio.InputReader, slicer remoteio.SlicedInputReader, inputPath, outputPath string, localOpts localFileOptions, preserve bool, opts []remoteio.SliceOption, reporter *progressReporter) error {
+	var modTime time.Time
+	if preserve {
+		t, err := sourceModTime(ctx, reader, inputPath)
+		if err != nil {
+			return err
+		}
+		modTime = t
+	}
+	file, err := localOpts.createFile(outputPath)
+	if err != nil {
+		return fmt.Errorf(tr("出力先への書き込みに失敗しました (%s)")+": %w", outputPath, err)
+	}
+
+	var w io.WriterAt = file
+	if reporter != nil {
+		reporter.Start(inputPath, objectSize(ctx, reader, inputPath))
+		w = reporter.WrapWriterAt(file)
+	}
+
+	logger().Info(tr("分割ダウンロード開始"), slog.String("input", inputPath), slog.String("output", outputPath))
+	size, err := slicer.DownloadSliced(ctx, inputPath, w, opts...)
+	if err == nil {
+		err = file.Close()
+	} else {
+		file.Close()
+	}
+	if err != nil {
+		os.Remove(outputPath)
+		return fmt.Errorf(tr("分割ダウンロードに失敗しました (%s)")+": %w", inputPath, err)
+	}
+	if !modTime.IsZero() {
+		if err := os.Chtimes(outputPath, time.Time{}, modTime); err != nil {
+			return fmt.Errorf(tr("ローカルファイル(%s)の更新日時の設定に失敗しました")+": %w", outputPath, err)
+		}
+	}
+	if err := localOpts.sync(outputPath); err != nil {
+		return fmt.Errorf(tr("ローカルファイル(%s)の fsync に失敗しました")+": %w", outputPath, err)
+	}
+	logger().Info(tr("分割ダウンロード完了"), slog.String("output", outputPath), slog.Int64("bytes", size))
+	return nil
+}
+
+// uploadParallelToGCS は、ローカルファイル inputPath を範囲ごとに並行して GCS URI の outputPath へアップロードします。
+func uploadParallelToGCS(ctx context.Context, writer remoteio.OutputWriter, inputPath, outputPath string, opts []remoteio.SliceOption, reporter *progressReporter) error {
+	parallelWriter, ok := writer.(remoteio.GCSParallelWriter)
+	if !ok {
+		return errors.New(tr("OutputWriterが並行アップロードをサポートしていません"))
+	}
+	bucketName, objectPath, err := remoteio.ParseGCSURI(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := parallelWriter.WriteToGCSParallel(ctx, bucketName, objectPath, inputPath, "", opts...); err != nil {
+		return fmt.Errorf(tr("出力先への書き込みに失敗しました (%s)")+": %w", outputPath, err)
+	}
+	// 各範囲は並行してアップロードされるため、進捗は完了時にまとめて加算する
+	if reporter != nil {
+		if info, err := os.Stat(inputPath); err == nil {
+			reporter.Start(inputPath, info.Size())
+			reporter.Add(info.Size())
+		}
+	}
+	return nil
+}
+
+// uploadCheckpointPath は、inputPath から outputPath へのアップロードの進行状況を保存するファイルのパスを返します。
+// 同じコピー元とコピー先で再実行した場合に同じファイルを参照するよう、ユーザーのキャッシュディレクトリ配下に両者のハッシュで配置します。
+func uploadCheckpointPath(inputPath, outputPath string) (string, error) {
+	absInput, err := filepath.Abs(inputPath)
+	if err != nil {
+		return "", err
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf(tr("アップロードの進行状況の保存先を決定できません")+": %w", err)
+	}
+	sum := sha256.Sum256([]byte(absInput + "\x00" + outputPath))
+	return filepath.Join(cacheDir, "remoteio", "uploads", hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// continueDownload は、-o のローカルファイルに途中までダウンロードされた inputPath の続きをダウンロードします。
+// 内容を先頭から順に検査するバリデータは、途中から再開する場合には適用できないため併用できません。
+func continueDownload(ctx context.Context, reader remoteio.InputReader, inputPath string, flags *rcopyFlags, reporter *progressReporter) error {
+	writerOpts, err := flags.writerOptions()
+	if err != nil {
+		return err
+	}
+	if len(writerOpts) > 0 {
+		return usageError(errors.New(tr("--continue と --max-size、--allow-content-type、--clamd は併用できません")))
+	}
+	downloader, ok := reader.(remoteio.ResumableDownloader)
+	if !ok {
+		return errors.New(tr("InputReaderがダウンロードの再開をサポートしていません"))
+	}
+
+	if reporter != nil {
+		reporter.Start(inputPath, objectSize(ctx, reader, inputPath))
+		// ダウンロード済みの部分も進捗に含める
+		if info, err := os.Stat(flags.OutputFilename); err == nil {
+			reporter.Add(info.Size())
+		}
+	}
+	n, err := downloader.ContinueDownload(ctx, inputPath, flags.OutputFilename)
+	if reporter != nil {
+		reporter.Add(n)
+	}
+	if err != nil {
+		return fmt.Errorf(tr("ダウンロードに失敗しました (%s)")+": %w", inputPath, err)
+	}
+	if flags.Preserve {
+		modTime, err := sourceModTime(ctx, reader, inputPath)
+		if err != nil {
+			return err
+		}
+		if err := os.Chtimes(flags.OutputFilename, time.Time{}, modTime); err != nil {
+			return fmt.Errorf(tr("ローカルファイル(%s)の更新日時の設定に失敗しました")+": %w", flags.OutputFilename, err)
+		}
+	}
+	return nil
+}
+
+// appendToGCS は、r の内容を GCS URI の outputPath の末尾に追記します。
+func appendToGCS(ctx context.Context, writer remoteio.OutputWriter, outputPath string, r io.Reader) error {
+	appender, ok := writer.(remoteio.GCSAppender)
+	if !ok {
+		return errors.New(tr("OutputWriterが追記をサポートしていません"))
+	}
+	bucketName, objectPath, err := remoteio.ParseGCSURI(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := appender.AppendToGCS(ctx, bucketName, objectPath, r, ""); err != nil {
+		return fmt.Errorf(tr("出力先への追記に失敗しました (%s)")+": %w", outputPath, err)
+	}
+	return nil
+}
+
+// singleCopyStats は、-r を指定しない1つのファイルのコピーの --stats の集計を作成します。
+// エンジンを経由しないため、コピーしたバイト数はコピー元 (標準入力の場合は書き込み先) のサイズとし、再試行の回数は含めません。
+func singleCopyStats(ctx context.Context, reader remoteio.InputReader, src, dst, status string, elapsed time.Duration, err error) *transfer.Stats {
+	job := transfer.JobStats{Job: transfer.Job{Source: src, Destination: dst}, Duration: elapsed, Err: err}
+	if err == nil && (status == statusCopied || status == statusOverwritten) {
+		sized := src
+		if src == stdioPath {
+			sized = dst
+		}
+		job.Bytes = max(objectSize(ctx, reader, sized), 0)
+	}
+	return &transfer.Stats{Jobs: []transfer.JobStats{job}, Elapsed: elapsed}
+}
+
+// objectSize は、uri のサイズを返します。取得できない場合 (標準入力を含む) は -1 を返します。
+func objectSize(ctx context.Context, reader remoteio.InputReader, uri string) int64 {
+	stater, ok := reader.(remoteio.Stater)
+	if !ok || uri == stdioPath {
+		return -1
+	}
+	info, err := stater.Stat(ctx, uri)
+	if err != nil {
+		return -1
 	}
+	return info.Size
 }
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitDestination(t *testing.T) {
+	tests := []struct {
+		name        string
+		outputs     []string
+		args        []string
+		wantSources []string
+		wantOutputs []string
+	}{
+		{name: "コピー元のみ", args: []string{"a"}, wantSources: []string{"a"}},
+		{name: "コピー元とコピー先", args: []string{"a", "b"}, wantSources: []string{"a"}, wantOutputs: []string{"b"}},
+		{name: "複数のコピー元", args: []string{"a", "b", "dir/"}, wantSources: []string{"a", "b"}, wantOutputs: []string{"dir/"}},
+		{name: "-o", outputs: []string{"out"}, args: []string{"a"}, wantSources: []string{"a"}, wantOutputs: []string{"out"}},
+		{name: "-o と最後の引数", outputs: []string{"out"}, args: []string{"a", "b"}, wantSources: []string{"a", "b"}, wantOutputs: []string{"out"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := &rcopyFlags{Outputs: tt.outputs}
+			sources := flags.splitDestination(tt.args)
+			if !slices.Equal(sources, tt.wantSources) || !slices.Equal(flags.Outputs, tt.wantOutputs) {
+				t.Errorf("splitDestination(%q) = %q, Outputs %q, want %q, %q", tt.args, sources, flags.Outputs, tt.wantSources, tt.wantOutputs)
+			}
+		})
+	}
+}
+
+func TestRcopyInvocationValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		inv     rcopyInvocation
+		wantErr bool
+	}{
+		{name: "コピー先への1つのファイルのコピー", inv: rcopyInvocation{flags: &rcopyFlags{}, sources: []string{"a.txt"}, output: "gs://bucket/a.txt"}},
+		{name: "標準出力へのコピー", inv: rcopyInvocation{flags: &rcopyFlags{}, sources: []string{"a.txt"}}},
+		{
+			name:    "最後の引数のコピー先と -o",
+			inv:     rcopyInvocation{flags: &rcopyFlags{Outputs: []string{"out.txt"}}, sources: []string{"a.txt", "b.txt"}, output: "out.txt", outputFlag: true},
+			wantErr: true,
+		},
+		{name: "-o と1つのコピー元", inv: rcopyInvocation{flags: &rcopyFlags{Outputs: []string{"out.txt"}}, sources: []string{"a.txt"}, output: "out.txt", outputFlag: true}},
+		{
+			name:    "複数の -o と -r",
+			inv:     rcopyInvocation{flags: &rcopyFlags{Outputs: []string{"a/", "b/"}, Recursive: true}, sources: []string{"dir"}, output: "a/", outputFlag: true},
+			wantErr: true,
+		},
+		{
+			name:    "複数の -o と --verify",
+			inv:     rcopyInvocation{flags: &rcopyFlags{Outputs: []string{"a", "b"}, Verify: true}, sources: []string{"src"}, output: "a", outputFlag: true},
+			wantErr: true,
+		},
+		{name: "--generation と GCS のコピー元", inv: rcopyInvocation{flags: &rcopyFlags{Generation: 3}, sources: []string{"gs://bucket/a"}, output: "a", generation: true}},
+		{name: "--generation とローカルのコピー元", inv: rcopyInvocation{flags: &rcopyFlags{Generation: 3}, sources: []string{"a"}, output: "b", generation: true}, wantErr: true},
+		{name: "--format json と標準出力", inv: rcopyInvocation{flags: &rcopyFlags{}, sources: []string{"a"}, json: true}, wantErr: true},
+		{name: "--append と GCS のコピー先", inv: rcopyInvocation{flags: &rcopyFlags{Append: true}, sources: []string{"a"}, output: "gs://bucket/log"}},
+		{name: "--append と -r", inv: rcopyInvocation{flags: &rcopyFlags{Append: true, Recursive: true}, sources: []string{"a"}, output: "gs://bucket/log"}, wantErr: true},
+		{name: "--append とローカルのコピー先", inv: rcopyInvocation{flags: &rcopyFlags{Append: true}, sources: []string{"a"}, output: "log"}, wantErr: true},
+		{name: "--resumable と GCS のコピー元", inv: rcopyInvocation{flags: &rcopyFlags{Resumable: true}, sources: []string{"gs://bucket/a"}, output: "gs://bucket/b"}, wantErr: true},
+		{name: "--continue とリモートのコピー元", inv: rcopyInvocation{flags: &rcopyFlags{Continue: true}, sources: []string{"gs://bucket/a"}, output: "a"}},
+		{name: "--continue とリモートのコピー先", inv: rcopyInvocation{flags: &rcopyFlags{Continue: true}, sources: []string{"gs://bucket/a"}, output: "gs://bucket/b"}, wantErr: true},
+		{name: "--no-clobber と --append", inv: rcopyInvocation{flags: &rcopyFlags{NoClobber: true, Append: true}, sources: []string{"a"}, output: "gs://bucket/log"}, wantErr: true},
+		{name: "前提条件とローカルのコピー先", inv: rcopyInvocation{flags: &rcopyFlags{}, sources: []string{"a"}, output: "b", preconditions: true}, wantErr: true},
+		{name: "オブジェクトのフラグとローカルのコピー先", inv: rcopyInvocation{flags: &rcopyFlags{}, sources: []string{"a"}, output: "b", objectOpts: true}, wantErr: true},
+		{name: "オブジェクトのフラグと S3 のコピー先", inv: rcopyInvocation{flags: &rcopyFlags{}, sources: []string{"a"}, output: "s3://bucket/b", objectOpts: true}},
+		{name: "--encrypt と --continue", inv: rcopyInvocation{flags: &rcopyFlags{Encrypt: true, Continue: true}, sources: []string{"gs://bucket/a"}, output: "a"}, wantErr: true},
+		{name: "--verify と --append", inv: rcopyInvocation{flags: &rcopyFlags{Verify: true, Append: true}, sources: []string{"a"}, output: "gs://bucket/log"}, wantErr: true},
+		{name: "--auto-decompress と --slice-size", inv: rcopyInvocation{flags: &rcopyFlags{AutoDecompress: true, SliceSize: "8MiB"}, sources: []string{"a.gz"}, output: "a"}, wantErr: true},
+		{name: "--skip-identical と --gzip", inv: rcopyInvocation{flags: &rcopyFlags{SkipIdentical: true, Gzip: true}, sources: []string{"a"}, output: "gs://bucket/a"}, wantErr: true},
+		{name: "標準入力と -r", inv: rcopyInvocation{flags: &rcopyFlags{Recursive: true}, sources: []string{"-"}, output: "gs://bucket/a"}, wantErr: true},
+		{name: "標準入力と --verify", inv: rcopyInvocation{flags: &rcopyFlags{Verify: true}, sources: []string{"-"}, output: "gs://bucket/a"}, wantErr: true},
+		{name: "複数のコピー元", inv: rcopyInvocation{flags: &rcopyFlags{}, sources: []string{"a", "b"}, output: "gs://bucket/dir/"}},
+		{name: "複数のコピー元と --generation", inv: rcopyInvocation{flags: &rcopyFlags{}, sources: []string{"gs://b/a", "gs://b/b"}, output: "dir/", generation: true}, wantErr: true},
+		{name: "--split-size", inv: rcopyInvocation{flags: &rcopyFlags{SplitSize: "1GiB"}, sources: []string{"a"}, output: "gs://bucket/a"}},
+		{name: "--split-size と --dry-run", inv: rcopyInvocation{flags: &rcopyFlags{SplitSize: "1GiB"}, sources: []string{"a"}, output: "gs://bucket/a", dryRun: true}, wantErr: true},
+		{name: "--split-size と --force", inv: rcopyInvocation{flags: &rcopyFlags{SplitSize: "1GiB", Force: true}, sources: []string{"a"}, output: "gs://bucket/a"}, wantErr: true},
+		{name: "--split-size と --max-size", inv: rcopyInvocation{flags: &rcopyFlags{SplitSize: "1GiB", MaxSize: "5GiB"}, sources: []string{"a"}, output: "gs://bucket/a"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.inv.validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && ExitCode(err) != ExitUsage {
+				t.Errorf("ExitCode() = %d, want %d", ExitCode(err), ExitUsage)
+			}
+		})
+	}
+}
+
+func TestRcopyFlagRulesAreTranslated(t *testing.T) {
+	for _, rule := range rcopyFlagRules {
+		if _, ok := catalogEN[rule.message]; !ok {
+			t.Errorf("英語訳がありません: %q", rule.message)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/shouni/go-remote-io/pkg/transfer"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+// rdiff コマンドの終了コード (diff コマンドと同じ)。比較できなかった場合は、原因に応じた共通の終了コード (ExitPermissionDenied など) を返す
+const (
+	diffExitDifferent = 1 // 差分がある
+	diffExitError     = 2 // 比較できなかった (原因を分類できない場合)
+)
+
+// rdiff コマンドが出力する差分の種類
+const (
+	diffLeftOnly  = "left-only"  // 1つ目のパスにのみ存在する
+	diffRightOnly = "right-only" // 2つ目のパスにのみ存在する
+	diffSize      = "size"       // サイズが異なる
+	diffChecksum  = "checksum"   // サイズは同じで CRC32C チェックサムが異なる
+)
+
+// rdiffFlags は rdiff コマンド固有のフラグを保持します。
+type rdiffFlags struct {
+	SizeOnly bool // --size-only サイズのみを比較
+	JSON     bool // --json NDJSON形式で出力
+	Parallel int  // --parallel 同時にチェックサムを計算するファイル数
+}
+
+// diffRecord は、rdiff が出力する1件分の差分です。
+type diffRecord struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	LeftSize  *int64 `json:"left_size,omitempty"`
+	RightSize *int64 `json:"right_size,omitempty"`
+}
+
+// newRdiffCmd は 'rdiff' サブコマンドを生成します。
+func newRdiffCmd() *cobra.Command {
+	var flags rdiffFlags
+
+	rdiffCmd := &cobra.Command{
+		Use:   "rdiff [left_path] [right_path]",
+		Short: "2つのディレクトリ/プレフィックス配下のファイルを比較し、差分を表示します。",
+		Long: `ローカルディレクトリと GCS URI のプレフィックス (または2つのプレフィックス) の配下のファイルを相対パスで対応付け、
+一方にのみ存在するファイル (left-only / right-only)、サイズが異なるファイル (size)、CRC32C チェックサムが異なるファイル (checksum) を出力します。
+チェックサムは一覧に含まれる値 (GCS) を使用し、含まれない場合 (ローカルファイル、S3 など) は内容を読み込んで計算します。
+大量のファイルを移行した後の検証に使用します。差分がない場合は 0、差分がある場合は 1 を終了コードとして返します。
+比較できなかった場合は、引数の誤りでは 2、権限不足では 4 など共通の終了コードを返し、原因を分類できない場合は 2 を返します。`,
+		Args: cobra.ExactArgs(2),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return withFallbackCode(cmd.Root().PersistentPreRunE(cmd, args), diffExitError)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withFallbackCode(runRdiff(cmd, args, &flags), diffExitError)
+		},
+	}
+
+	rdiffCmd.Flags().BoolVar(&flags.SizeOnly, "size-only", false, "サイズのみを比較し、チェックサムは比較しない")
+	rdiffCmd.Flags().BoolVar(&flags.JSON, "json", false, "NDJSON形式で出力")
+	rdiffCmd.Flags().IntVar(&flags.Parallel, "parallel", transfer.DefaultParallelism, "同時にチェックサムを計算するファイル数")
+
+	return rdiffCmd
+}
+
+// runRdiff は rdiff コマンドの実行ロジックです。
+func runRdiff(cmd *cobra.Command, args []string, flags *rdiffFlags) error {
+	if jsonOutput(cmd) {
+		flags.JSON = true // --format json は --json と同じ
+	}
+	ctx := cmd.Context()
+	leftPath, rightPath := args[0], args[1]
+	if flags.Parallel < 1 {
+		return usageError(fmt.Errorf(tr("--parallel には1以上を指定してください: %d"), flags.Parallel))
+	}
+
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	inputReader, err := clientFactory.NewInputReader()
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+	lister, ok := inputReader.(remoteio.ObjectLister)
+	if !ok {
+		return errors.New(tr("InputReaderが一覧の取得をサポートしていません"))
+	}
+
+	// 1. 両方の一覧を取得する (存在しないディレクトリは空とみなす)
+	left, err := lister.ListObjects(ctx, leftPath)
+	if err != nil && !errors.Is(err, remoteio.ErrNotFound) {
+		return fmt.Errorf(tr("一覧の取得に失敗しました (%s)")+": %w", leftPath, err)
+	}
+	right, err := lister.ListObjects(ctx, rightPath)
+	if err != nil && !errors.Is(err, remoteio.ErrNotFound) {
+		return fmt.Errorf(tr("一覧の取得に失敗しました (%s)")+": %w", rightPath, err)
+	}
+	rightByName := make(map[string]remoteio.ObjectInfo, len(right))
+	for _, obj := range right {
+		rightByName[obj.Name] = obj
+	}
+
+	logger().Info(tr("比較開始"),
+		slog.String("left", leftPath),
+		slog.String("right", rightPath),
+		slog.Int("left_files", len(left)),
+		slog.Int("right_files", len(right)),
+	)
+
+	// 2. 名前で対応付け、サイズが同じファイルはチェックサムを並行して比較する
+	var diffs []diffRecord
+	var pairs [][2]remoteio.ObjectInfo
+	for _, l := range left {
+		r, found := rightByName[l.Name]
+		delete(rightByName, l.Name)
+		switch {
+		case !found:
+			diffs = append(diffs, diffRecord{Name: l.Name, Status: diffLeftOnly, LeftSize: &l.Size})
+		case l.Size != r.Size:
+			diffs = append(diffs, diffRecord{Name: l.Name, Status: diffSize, LeftSize: &l.Size, RightSize: &r.Size})
+		case !flags.SizeOnly:
+			pairs = append(pairs, [2]remoteio.ObjectInfo{l, r})
+		}
+	}
+	for _, r := range rightByName {
+		diffs = append(diffs, diffRecord{Name: r.Name, Status: diffRightOnly, RightSize: &r.Size})
+	}
+
+	different := make([]bool, len(pairs))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(flags.Parallel)
+	for i, pair := range pairs {
+		g.Go(func() error {
+			l, err := contentCRC32C(gctx, inputReader, pair[0])
+			if err != nil {
+				return err
+			}
+			r, err := contentCRC32C(gctx, inputReader, pair[1])
+			if err != nil {
+				return err
+			}
+			different[i] = l != r
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	for i, pair := range pairs {
+		if different[i] {
+			diffs = append(diffs, diffRecord{Name: pair[0].Name, Status: diffChecksum, LeftSize: &pair[0].Size, RightSize: &pair[1].Size})
+		}
+	}
+
+	// 3. 名前順に出力する
+	slices.SortFunc(diffs, func(a, b diffRecord) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	out := cmd.OutOrStdout()
+	for _, d := range diffs {
+		if err := printDiff(out, d, flags.JSON); err != nil {
+			return err
+		}
+	}
+
+	logger().Info(tr("比較完了"), slog.Int("files", len(left)+len(rightByName)), slog.Int("differences", len(diffs)))
+	if len(diffs) > 0 {
+		// 差分があることはエラーではないため、メッセージは表示しない
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return &exitError{code: diffExitDifferent}
+	}
+	return nil
+}
+
+// printDiff は、1件の差分を出力します。
+func printDiff(w io.Writer, d diffRecord, asJSON bool) error {
+	if asJSON {
+		data, err := json.Marshal(d)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+	var err error
+	if d.Status == diffSize {
+		_, err = fmt.Fprintf(w, "%-10s  %s  (%d != %d)\n", d.Status, d.Name, *d.LeftSize, *d.RightSize)
+	} else {
+		_, err = fmt.Fprintf(w, "%-10s  %s\n", d.Status, d.Name)
+	}
+	return err
+}
+
+// contentCRC32C は、obj の CRC32C チェックサムを返します。一覧に含まれない場合やローカルファイルの場合は、内容を読み込んで計算します。
+func contentCRC32C(ctx context.Context, reader remoteio.InputReader, obj remoteio.ObjectInfo) (uint32, error) {
+	sum, ok, err := objectCRC32C(obj)
+	if err != nil || ok {
+		return sum, err
+	}
+	rc, err := reader.Open(ctx, obj.URI)
+	if err != nil {
+		return 0, fmt.Errorf(tr("チェックサムの計算に失敗しました (%s)")+": %w", obj.URI, err)
+	}
+	defer rc.Close()
+	h := remoteio.NewCRC32C()
+	if _, err := io.Copy(h, rc); err != nil {
+		return 0, fmt.Errorf(tr("チェックサムの計算に失敗しました (%s)")+": %w", obj.URI, err)
+	}
+	return h.Sum32(), nil
+}
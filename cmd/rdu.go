@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/spf13/cobra"
+)
+
+// rduFlags は rdu コマンド固有のフラグを保持します。
+type rduFlags struct {
+	Depth int  // --depth サブディレクトリ/サブプレフィックスごとの集計を表示する深さ
+	Bytes bool // --bytes サイズを読みやすい単位ではなくバイト数で表示
+	JSON  bool // --json NDJSON形式で出力
+}
+
+// duRecord は、rdu の --json で出力する1件分のレコードです。
+type duRecord struct {
+	URI     string `json:"uri"`
+	Name    string `json:"name"`  // 集計の起点からの相対パス ("/" 区切り)。起点自体の合計の場合は空
+	Depth   int    `json:"depth"` // 起点からの深さ。起点自体の合計の場合は 0
+	Bytes   int64  `json:"bytes"`
+	Objects int64  `json:"objects"`
+}
+
+// newRduCmd は 'rdu' サブコマンドを生成します。
+func newRduCmd() *cobra.Command {
+	var flags rduFlags
+
+	rduCmd := &cobra.Command{
+		Use:   "rdu [path]",
+		Short: "ディレクトリ/プレフィックス配下のファイルの合計サイズと数を集計します。",
+		Long: `指定されたローカルディレクトリ、または GCS URI などのプレフィックス配下のすべてのファイルを一覧し、合計サイズとファイル (オブジェクト) の数を表示します。
+--depth を指定すると、その深さまでのサブディレクトリ/サブプレフィックスごとの合計も表示します (例: --depth 1 で直下のサブプレフィックスごと)。
+各行には、合計サイズ、ファイルの数とURIを表示します。サイズは読みやすい単位 (KiB、MiB、...) で表示します。--bytes を指定するとバイト数で表示し、--json を指定すると1件ごとに1行の JSON (NDJSON) で出力します。`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRdu(cmd, args, &flags)
+		},
+	}
+
+	rduCmd.Flags().IntVar(&flags.Depth, "depth", 0, "サブディレクトリ/サブプレフィックスごとの合計を表示する深さ (0 の場合は全体の合計のみ)")
+	rduCmd.Flags().BoolVar(&flags.Bytes, "bytes", false, "サイズを読みやすい単位ではなくバイト数で表示")
+	rduCmd.Flags().BoolVar(&flags.JSON, "json", false, "NDJSON形式で出力")
+
+	return rduCmd
+}
+
+// runRdu は rdu コマンドの実行ロジックです。
+func runRdu(cmd *cobra.Command, args []string, flags *rduFlags) error {
+	if jsonOutput(cmd) {
+		flags.JSON = true // --format json は --json と同じ
+	}
+	ctx := cmd.Context()
+	path := args[0]
+	if flags.Depth < 0 {
+		return usageError(fmt.Errorf(tr("--depth には 0 以上の整数を指定してください: %d"), flags.Depth))
+	}
+
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	inputReader, err := clientFactory.NewInputReader()
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+	lister, ok := inputReader.(remoteio.ObjectLister)
+	if !ok {
+		return errors.New(tr("InputReaderが一覧の取得をサポートしていません"))
+	}
+
+	// 大量のファイルがあってもすべてを保持しないよう、ページごとに集計する
+	usage := newDiskUsage(flags.Depth)
+	opts := []remoteio.ListOption{remoteio.WithPageSize(rlsPageSize)}
+	for {
+		page, err := lister.ListObjectsPage(ctx, path, opts...)
+		if err != nil {
+			return fmt.Errorf(tr("一覧の取得に失敗しました (%s)")+": %w", path, err)
+		}
+		for _, obj := range page.Objects {
+			if !obj.IsPrefix {
+				usage.add(obj.Name, obj.Size)
+			}
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		opts = append(opts, remoteio.WithPageToken(page.NextPageToken))
+		logger().Debug(tr("集計中"), slog.String("path", path), slog.Int64("objects", usage.total.objects))
+	}
+
+	return usage.print(cmd.OutOrStdout(), path, flags)
+}
+
+// duEntry は、1つのディレクトリ/プレフィックス配下の集計です。
+type duEntry struct {
+	bytes   int64
+	objects int64
+}
+
+// diskUsage は、rdu の集計です。
+type diskUsage struct {
+	depth  int
+	total  duEntry
+	groups map[string]*duEntry // 起点からの深さが depth 以下のサブディレクトリ/サブプレフィックスごとの集計 (キーは相対パス)
+}
+
+// newDiskUsage は、深さ depth までのサブディレクトリ/サブプレフィックスごとに集計する diskUsage を作成します。
+func newDiskUsage(depth int) *diskUsage {
+	return &diskUsage{depth: depth, groups: make(map[string]*duEntry)}
+}
+
+// add は、起点からの相対パスが name でサイズが size のファイルを、全体と、それを含む深さ depth までの各サブディレクトリの集計に加えます。
+func (u *diskUsage) add(name string, size int64) {
+	u.total.bytes += size
+	u.total.objects++
+	dirs := strings.Split(name, "/")
+	dirs = dirs[:len(dirs)-1] // ファイル名を除く
+	for i := 1; i <= min(u.depth, len(dirs)); i++ {
+		key := strings.Join(dirs[:i], "/")
+		e, ok := u.groups[key]
+		if !ok {
+			e = &duEntry{}
+			u.groups[key] = e
+		}
+		e.bytes += size
+		e.objects++
+	}
+}
+
+// print は、サブディレクトリ/サブプレフィックスごとの集計を名前順に出力し、最後に起点 path 全体の合計を出力します。
+func (u *diskUsage) print(w io.Writer, path string, flags *rduFlags) error {
+	names := make([]string, 0, len(u.groups))
+	for name := range u.groups {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		e := u.groups[name]
+		uri := remoteio.JoinURI(path, name)
+		if remoteio.SchemeOf(path) != "" {
+			uri += "/"
+		}
+		rec := duRecord{URI: uri, Name: name, Depth: strings.Count(name, "/") + 1, Bytes: e.bytes, Objects: e.objects}
+		if err := printDuRecord(w, rec, flags); err != nil {
+			return err
+		}
+	}
+	return printDuRecord(w, duRecord{URI: path, Bytes: u.total.bytes, Objects: u.total.objects}, flags)
+}
+
+// printDuRecord は、フラグに応じた形式で1件分を出力します。
+func printDuRecord(w io.Writer, rec duRecord, flags *rduFlags) error {
+	if flags.JSON {
+		return writeJSONLine(w, rec)
+	}
+	size := formatByteSize(rec.Bytes)
+	if flags.Bytes {
+		size = fmt.Sprint(rec.Bytes)
+	}
+	_, err := fmt.Fprintf(w, "%12s  %10d  %s\n", size, rec.Objects, rec.URI)
+	return err
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/spf13/cobra"
+)
+
+// rexists コマンドの終了コード。確認できなかった場合は、原因にかかわらず existsExitError を返し、原因はメッセージに含める
+const (
+	existsExitNotFound = 1 // 存在しない
+	existsExitError    = 2 // 確認できなかった
+)
+
+// existsRecord は、rexists の --format json で出力するレコードです。
+type existsRecord struct {
+	URI    string `json:"uri"`
+	Exists bool   `json:"exists"`
+}
+
+// newRexistsCmd は 'rexists' サブコマンドを生成します。
+func newRexistsCmd() *cobra.Command {
+	rexistsCmd := &cobra.Command{
+		Use:   "rexists [path]",
+		Short: "ファイルまたはオブジェクトが存在するかどうかを終了コードで返します。",
+		Long: `指定されたローカルファイル、または GCS URI などで指定されたオブジェクトが存在する場合は 0、存在しない場合は 1 を終了コードとして返します。
+確認できなかった場合は、原因 (引数の誤り、権限不足、ネットワークエラーなど) にかかわらず 2 を返し、原因はエラーメッセージに表示します。
+エラーメッセージを解析せずに、シェルスクリプトで後続の処理を分岐させるために使用します。`,
+		Args: cobra.ExactArgs(1),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return existsFailure(cmd.Root().PersistentPreRunE(cmd, args))
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			exists, err := runRexists(cmd, args)
+			if err != nil {
+				return existsFailure(err)
+			}
+			if !exists {
+				// 存在しないことはエラーではないため、メッセージは表示しない
+				cmd.SilenceErrors = true
+				return &exitError{code: existsExitNotFound}
+			}
+			return nil
+		},
+	}
+	return rexistsCmd
+}
+
+// existsFailure は、存在を確認できなかった err を、共通の終了コードの分類にかかわらず existsExitError で終了するエラーにします。
+// 「存在しない」の 1 と区別できるよう、共通の終了コードの分類はメッセージに含めます。
+func existsFailure(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{code: existsExitError, err: fmt.Errorf(tr("%w (原因: %s)"), err, exitCause(ExitCode(err)))}
+}
+
+// runRexists は rexists コマンドの実行ロジックです。uri が存在するかどうかを返します。
+func runRexists(cmd *cobra.Command, args []string) (bool, error) {
+	ctx := cmd.Context()
+	uri := args[0]
+	cmd.SilenceUsage = true
+
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	inputReader, err := clientFactory.NewInputReader()
+	if err != nil {
+		return false, fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+	stater, ok := inputReader.(remoteio.Stater)
+	if !ok {
+		return false, errors.New(tr("InputReaderが情報の取得をサポートしていません"))
+	}
+
+	exists, err := stater.Exists(ctx, uri)
+	if err != nil {
+		return false, fmt.Errorf(tr("存在の確認に失敗しました (%s)")+": %w", uri, err)
+	}
+	if jsonOutput(cmd) {
+		writeJSONLine(cmd.OutOrStdout(), existsRecord{URI: uri, Exists: exists})
+	}
+	return exists, nil
+}
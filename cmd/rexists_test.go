@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/shouni/go-remote-io/pkg/factory"
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/shouni/go-remote-io/pkg/remoteiotest"
+)
+
+func TestRexistsExitCodes(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		fault     error // Exists に注入するエラー
+		wantCode  int
+		wantCause string // エラーメッセージに含まれる原因の分類
+	}{
+		{name: "存在する", args: []string{"gs://bucket/a.txt"}, wantCode: ExitOK},
+		{name: "存在しない", args: []string{"gs://bucket/missing.txt"}, wantCode: existsExitNotFound},
+		{name: "権限不足", args: []string{"gs://bucket/a.txt"}, fault: &remoteio.Error{Kind: remoteio.ErrPermissionDenied, Err: errors.New("403")}, wantCode: existsExitError, wantCause: "権限不足"},
+		{name: "ネットワークエラー", args: []string{"gs://bucket/a.txt"}, fault: errors.New("connection reset"), wantCode: existsExitError, wantCause: "分類できないエラー"},
+		{name: "引数の誤り", args: []string{"gs://bucket/a.txt", "extra"}, wantCode: existsExitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := remoteiotest.NewStore()
+			store.Put("gs://bucket/a.txt", []byte("a"))
+			if tt.fault != nil {
+				store.FailOn(remoteiotest.OpExists, tt.args[0], tt.fault)
+			}
+			rootCmd := NewRootCmd(factory.NewFakeFactory(store))
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(append([]string{"--quiet", "rexists"}, tt.args...))
+
+			err := rootCmd.Execute()
+			if got := ExitCode(err); got != tt.wantCode {
+				t.Fatalf("ExitCode() = %d, want %d (err: %v)", got, tt.wantCode, err)
+			}
+			if tt.wantCause != "" && !strings.Contains(err.Error(), tt.wantCause) {
+				t.Errorf("エラー = %q, want 原因 %q を含む", err, tt.wantCause)
+			}
+		})
+	}
+}
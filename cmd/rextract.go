@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/shouni/go-remote-io/pkg/archive"
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/spf13/cobra"
+)
+
+// rextractFlags は rextract コマンド固有のフラグを保持します。
+type rextractFlags struct {
+	Type string // --type アーカイブの形式 (省略時はアーカイブの拡張子から判定)
+}
+
+// newRextractCmd は 'rextract' サブコマンドを生成します。
+func newRextractCmd() *cobra.Command {
+	var flags rextractFlags
+
+	rextractCmd := &cobra.Command{
+		Use:   "rextract [archive] [destination]",
+		Short: "tar / zip アーカイブをディレクトリまたはプレフィックスへ展開します。",
+		Long: `ローカルファイル、または GCS URI などで指定された tar / zip アーカイブを読み込み、各ファイルを書き込み先 (ローカルディレクトリ、または gs://bucket/prefix などのプレフィックス) 配下の同じ相対パスへ書き込みます。
+tar は先頭から読み込みながら展開し、zip は末尾の目次と各ファイルの範囲だけを範囲読み込みで読み込むため、一時ファイルを使用しません。
+形式は --type (tar、tar.gz、tgz、zip) で指定します。省略した場合はアーカイブの拡張子 (.tar、.tar.gz、.tgz、.zip) から判定します。
+アーカイブに - を指定すると標準入力から読み込みます (tar / tar.gz のみ。--type が必要です)。
+書き込み先の外を指すパス (絶対パスや ..) を含むアーカイブはエラーにし、シンボリックリンクなどの通常のファイル以外のエントリは警告して読み飛ばします。`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRextract(cmd, args, &flags)
+		},
+	}
+
+	rextractCmd.Flags().StringVar(&flags.Type, "type", "", "アーカイブの形式: tar、tar.gz (tgz)、zip (省略時はアーカイブの拡張子から判定)")
+
+	return rextractCmd
+}
+
+// runRextract は rextract コマンドの実行ロジックです。
+func runRextract(cmd *cobra.Command, args []string, flags *rextractFlags) error {
+	ctx := cmd.Context()
+	srcPath, dstPath := args[0], args[1]
+	format, err := archiveFormat(flags.Type, srcPath)
+	if err != nil {
+		return err
+	}
+	if dstPath == stdioPath {
+		return usageError(errors.New(tr("展開先にはローカルディレクトリまたはプレフィックスを指定してください (- は指定できません)")))
+	}
+	fromStdin := srcPath == stdioPath
+	if fromStdin && format == archive.FormatZip {
+		return usageError(errors.New(tr("zip のアーカイブは標準入力から展開できません (末尾の目次から読み込む必要があるため)")))
+	}
+
+	// 1. ClientFactory とリーダー・ライターの取得 (DI)
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	// Content-Encoding: gzip のアーカイブを展開して読み込まないよう、保存されている内容のまま読み込む
+	inputReader, err := clientFactory.NewInputReader(remoteio.WithReadOptions(remoteio.WithReadCompressed(true)))
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+	writer, err := clientFactory.NewOutputWriter()
+	if err != nil {
+		return fmt.Errorf(tr("OutputWriterの作成に失敗しました")+": %w", err)
+	}
+
+	results := newTextResultWriter(cmd, inputReader)
+	var (
+		files int
+		total int64
+	)
+	opts := []archive.Option{
+		archive.WithLogger(logger()),
+		archive.WithOnEntry(func(e archive.Entry) {
+			files++
+			total += e.Size
+			results.archiveEntry(e.Name, e.URI, statusExtracted, e.Size)
+		}),
+	}
+	logger().Info(tr("展開開始"), slog.String("archive", srcPath), slog.String("destination", dstPath), slog.String("type", string(format)))
+
+	// 2. アーカイブを展開する
+	if format == archive.FormatZip {
+		err = extractZip(cmd, inputReader, writer, srcPath, dstPath, opts)
+	} else {
+		var rc io.ReadCloser
+		if fromStdin {
+			rc = io.NopCloser(cmd.InOrStdin())
+		} else if rc, err = inputReader.Open(ctx, srcPath); err != nil {
+			return fmt.Errorf(tr("アーカイブのオープンに失敗しました")+" (%s): %w", srcPath, err)
+		}
+		err = archive.Extract(ctx, rc, format, writer, dstPath, opts...)
+		rc.Close()
+	}
+	if err != nil {
+		return fmt.Errorf(tr("アーカイブの展開に失敗しました")+": %w", err)
+	}
+
+	logger().Info(tr("展開完了"), slog.Int("files", files), slog.Int64("bytes", total))
+	return nil
+}
+
+// extractZip は、zip のアーカイブ srcPath を範囲読み込みで開き、dstPath 配下へ展開します。
+func extractZip(cmd *cobra.Command, reader remoteio.InputReader, writer remoteio.OutputWriter, srcPath, dstPath string, opts []archive.Option) error {
+	ctx := cmd.Context()
+	ranger, ok := reader.(remoteio.RangeInputReader)
+	if !ok {
+		return errors.New(tr("InputReaderが範囲読み込みをサポートしていません"))
+	}
+	ra, err := ranger.OpenReaderAt(ctx, srcPath)
+	if err != nil {
+		return fmt.Errorf(tr("アーカイブのオープンに失敗しました")+" (%s): %w", srcPath, err)
+	}
+	defer ra.Close()
+	return archive.ExtractZip(ctx, ra, ra.Size(), writer, dstPath, opts...)
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/spf13/cobra"
+)
+
+// rhash コマンドで指定できるハッシュのアルゴリズム
+const (
+	hashCRC32C = "crc32c"
+	hashMD5    = "md5"
+	hashSHA256 = "sha256"
+)
+
+// rhashFlags は rhash コマンド固有のフラグを保持します。
+type rhashFlags struct {
+	Algorithm string // -a, --algorithm ハッシュのアルゴリズム
+	Check     bool   // -c, --check 引数のチェックサムファイルに記載されたハッシュを検証
+}
+
+// hashRecord は、rhash の --format json で出力する1件分のレコードです。
+type hashRecord struct {
+	URI       string `json:"uri"`
+	Algorithm string `json:"algorithm"`
+	Hash      string `json:"hash,omitempty"`
+	Expected  string `json:"expected,omitempty"` // -c の場合の、チェックサムファイルに記載されたハッシュ
+	Status    string `json:"status,omitempty"`   // -c の場合の検証結果 (ok / failed)
+	Error     string `json:"error,omitempty"`
+}
+
+// rhash -c の --format json で出力する検証結果
+const (
+	hashStatusOK     = "ok"
+	hashStatusFailed = "failed"
+)
+
+// newRhashCmd は 'rhash' サブコマンドを生成します。
+func newRhashCmd() *cobra.Command {
+	var flags rhashFlags
+
+	rhashCmd := &cobra.Command{
+		Use:   "rhash [path...]",
+		Short: "ファイルまたはオブジェクトのハッシュ (CRC32C / MD5 / SHA-256) を表示・検証します。",
+		Long: `指定されたローカルファイル、または GCS URI などで指定されたオブジェクトのハッシュを、sha256sum と同じ "<16進数のハッシュ>  <パス>" の形式で出力します。
+CRC32C と MD5 は、GCS などが保存している属性があればそれを使用し (内容はダウンロードしません)、ない場合と SHA-256 は内容を読み込んで計算します。
+Content-Encoding: gzip の GCS オブジェクトは、保存されている (圧縮済みの) 内容のハッシュを出力します。
+-c を指定すると、引数をチェックサムファイル (sha256sum などの出力) として読み込み、記載されたファイルとオブジェクトのハッシュを検証します。
+アルゴリズムはハッシュの長さ (8: CRC32C、32: MD5、64: SHA-256) から判定し、一致しないものがあった場合は失敗します。`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRhash(cmd, args, &flags)
+		},
+	}
+
+	rhashCmd.Flags().StringVarP(&flags.Algorithm, "algorithm", "a", hashSHA256, "ハッシュのアルゴリズム (crc32c, md5, sha256)")
+	rhashCmd.Flags().BoolVarP(&flags.Check, "check", "c", false, "引数のチェックサムファイルを読み込み、記載されたハッシュを検証")
+
+	return rhashCmd
+}
+
+// runRhash は rhash コマンドの実行ロジックです。
+func runRhash(cmd *cobra.Command, args []string, flags *rhashFlags) error {
+	ctx := cmd.Context()
+	if !slices.Contains([]string{hashCRC32C, hashMD5, hashSHA256}, flags.Algorithm) {
+		return usageError(fmt.Errorf(tr("--algorithm には crc32c、md5、sha256 のいずれかを指定してください: %s"), flags.Algorithm))
+	}
+
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	// 保存されている属性のハッシュと一致するよう、GCS で展開せずに読み込む
+	inputReader, err := clientFactory.NewInputReader(remoteio.WithReadOptions(remoteio.WithReadCompressed(true)))
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+
+	if flags.Check {
+		return checkHashes(cmd, inputReader, args)
+	}
+	out := cmd.OutOrStdout()
+	for _, uri := range args {
+		sum, err := objectHash(ctx, inputReader, uri, flags.Algorithm)
+		if err != nil {
+			return err
+		}
+		if jsonOutput(cmd) {
+			writeJSONLine(out, hashRecord{URI: uri, Algorithm: flags.Algorithm, Hash: sum})
+			continue
+		}
+		fmt.Fprintf(out, "%s  %s\n", sum, uri)
+	}
+	return nil
+}
+
+// objectHash は、uri の内容の algorithm のハッシュを16進数で返します。
+// CRC32C と MD5 は、コピー元が保存している属性があればそれを使用し、ない場合は内容を読み込んで計算します。
+func objectHash(ctx context.Context, reader remoteio.InputReader, uri, algorithm string) (string, error) {
+	if stater, ok := reader.(remoteio.Stater); ok {
+		info, err := stater.Stat(ctx, uri)
+		if err != nil {
+			return "", fmt.Errorf(tr("情報の取得に失敗しました (%s)")+": %w", uri, err)
+		}
+		if info.IsPrefix {
+			return "", fmt.Errorf(tr("ディレクトリのハッシュは計算できません: %s"), uri)
+		}
+		switch {
+		case algorithm == hashCRC32C && info.CRC32C != nil:
+			return fmt.Sprintf("%08x", *info.CRC32C), nil
+		case algorithm == hashMD5 && info.MD5 != nil:
+			return hex.EncodeToString(info.MD5), nil
+		}
+	}
+
+	var h hash.Hash
+	switch algorithm {
+	case hashCRC32C:
+		h = remoteio.NewCRC32C()
+	case hashMD5:
+		h = md5.New()
+	default:
+		h = sha256.New()
+	}
+	rc, err := reader.Open(ctx, uri)
+	if err != nil {
+		return "", fmt.Errorf(tr("入力ストリームのオープンに失敗しました (%s)")+": %w", uri, err)
+	}
+	defer rc.Close()
+	if _, err := io.Copy(h, rc); err != nil {
+		return "", fmt.Errorf(tr("データの読み込み中にエラーが発生しました (%s)")+": %w", uri, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashAlgorithmOf は、16進数のハッシュの長さからアルゴリズムを判定します。判定できない場合は空文字列を返します。
+func hashAlgorithmOf(sum string) string {
+	if _, err := hex.DecodeString(sum); err != nil {
+		return ""
+	}
+	switch len(sum) {
+	case 8:
+		return hashCRC32C
+	case 32:
+		return hashMD5
+	case 64:
+		return hashSHA256
+	default:
+		return ""
+	}
+}
+
+// printCheckResult は、-c の1件分の検証結果を、sha256sum -c と同じ形式または asJSON が true (--format json) の場合は1行の JSON で出力します。
+func printCheckResult(w io.Writer, rec hashRecord, asJSON bool) {
+	switch {
+	case asJSON:
+		writeJSONLine(w, rec)
+	case rec.Error != "":
+		fmt.Fprintf(w, "%s: FAILED open or read\n", rec.URI)
+	case rec.Status == hashStatusOK:
+		fmt.Fprintf(w, "%s: OK\n", rec.URI)
+	default:
+		fmt.Fprintf(w, "%s: FAILED\n", rec.URI)
+	}
+}
+
+// checkHashes は、チェックサムファイル files に記載された各行 ("<ハッシュ>  <パス>" または "<ハッシュ> *<パス>") のハッシュを検証し、
+// sha256sum -c と同様に "<パス>: OK" または "<パス>: FAILED" を出力します。一致しないものがあった場合はエラーを返します。
+func checkHashes(cmd *cobra.Command, reader remoteio.InputReader, files []string) error {
+	ctx := cmd.Context()
+	out := cmd.OutOrStdout()
+	var mismatched, unreadable int
+	for _, file := range files {
+		rc, err := reader.Open(ctx, file)
+		if err != nil {
+			return fmt.Errorf(tr("チェックサムファイルのオープンに失敗しました (%s)")+": %w", file, err)
+		}
+		scanner := bufio.NewScanner(rc)
+		for lineNo := 1; scanner.Scan(); lineNo++ {
+			line := strings.TrimRight(scanner.Text(), "\r")
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			want, name, ok := strings.Cut(line, " ")
+			name = strings.TrimPrefix(strings.TrimPrefix(name, " "), "*")
+			algorithm := hashAlgorithmOf(want)
+			if !ok || name == "" || algorithm == "" {
+				rc.Close()
+				return fmt.Errorf(tr("チェックサムファイルの形式が正しくありません (%s:%d)"), file, lineNo)
+			}
+			got, err := objectHash(ctx, reader, name, algorithm)
+			rec := hashRecord{URI: name, Algorithm: algorithm, Hash: got, Expected: want, Status: hashStatusFailed}
+			switch {
+			case err != nil:
+				fmt.Fprintf(cmd.ErrOrStderr(), "%v\n", err)
+				rec.Error = err.Error()
+				unreadable++
+			case strings.EqualFold(got, want):
+				rec.Status = hashStatusOK
+			default:
+				mismatched++
+			}
+			printCheckResult(out, rec, jsonOutput(cmd))
+		}
+		err = scanner.Err()
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf(tr("チェックサムファイルの読み込みに失敗しました (%s)")+": %w", file, err)
+		}
+	}
+
+	var errs []error
+	if unreadable > 0 {
+		errs = append(errs, fmt.Errorf(tr("%d 件のファイルを読み込めませんでした"), unreadable))
+	}
+	if mismatched > 0 {
+		errs = append(errs, fmt.Errorf(tr("%d 件のハッシュが一致しませんでした"), mismatched))
+	}
+	if len(errs) > 0 {
+		cmd.SilenceUsage = true
+	}
+	return errors.Join(errs...)
+}
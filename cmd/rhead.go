@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// rheadFlags は rhead コマンド固有のフラグを保持します。
+type rheadFlags struct {
+	Lines int    // -n, --lines 先頭から表示する行数
+	Bytes string // -c, --bytes 先頭から表示するサイズ
+}
+
+// newRheadCmd は 'rhead' サブコマンドを生成します。
+func newRheadCmd() *cobra.Command {
+	var flags rheadFlags
+
+	rheadCmd := &cobra.Command{
+		Use:   "rhead [path]",
+		Short: "ファイルまたはオブジェクトの先頭を表示します。",
+		Long: `指定されたローカルファイル、または GCS URI などで指定されたオブジェクトの先頭の -n 行 (または -c のサイズ) を標準出力へ表示します。
+ファイル全体はダウンロードせず、先頭の範囲のみを範囲読み込みで読み込むため、大きなファイルのヘッダーなどを確認する場合に使用します。`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRhead(cmd, args, &flags)
+		},
+	}
+
+	rheadCmd.Flags().IntVarP(&flags.Lines, "lines", "n", 10, "先頭から表示する行数")
+	rheadCmd.Flags().StringVarP(&flags.Bytes, "bytes", "c", "", "先頭から表示するサイズ (例: 4KiB)。指定した場合は行数の代わりにサイズで表示")
+	rheadCmd.MarkFlagsMutuallyExclusive("lines", "bytes")
+
+	return rheadCmd
+}
+
+// runRhead は rhead コマンドの実行ロジックです。
+func runRhead(cmd *cobra.Command, args []string, flags *rheadFlags) error {
+	ctx := cmd.Context()
+	uri := args[0]
+	if jsonOutput(cmd) {
+		return usageError(errors.New(tr("rhead は内容を標準出力へ出力するため、--format json は指定できません")))
+	}
+	if flags.Lines < 0 {
+		return usageError(fmt.Errorf(tr("--lines には 0 以上の行数を指定してください: %d"), flags.Lines))
+	}
+	headBytes := int64(-1)
+	if flags.Bytes != "" {
+		n, err := parseByteSize(flags.Bytes)
+		if err != nil || n < 0 {
+			return usageError(fmt.Errorf(tr("--bytes には 0 以上のサイズを指定してください: %s"), flags.Bytes))
+		}
+		headBytes = n
+	}
+
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	inputReader, err := clientFactory.NewInputReader()
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+	printer, err := newRangePrinter(ctx, inputReader, uri, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+	info, err := printer.stat()
+	if err != nil {
+		return err
+	}
+	if headBytes >= 0 {
+		_, err = printer.copyRange(info, 0, min(headBytes, info.Size))
+		return err
+	}
+	return printer.copyHeadLines(info, flags.Lines)
+}
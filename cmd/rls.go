@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/spf13/cobra"
+)
+
+// rlsPageSize は、rls コマンドが1回の一覧取得で要求する件数です。
+const rlsPageSize = 1000
+
+// rlsFlags は rls コマンド固有のフラグを保持します。
+type rlsFlags struct {
+	Long      bool   // -l, --long サイズ・更新日時・ストレージクラスを表示
+	JSON      bool   // --json NDJSON形式で出力
+	Recursive bool   // -r, --recursive サブディレクトリ配下も一覧
+	Project   string // --project gs:// でバケットを一覧するプロジェクトID
+}
+
+// objectRecord は、rls / rstat の --json で出力する1件分のレコードです。
+type objectRecord struct {
+	URI             string            `json:"uri"`
+	Name            string            `json:"name"`
+	Prefix          bool              `json:"prefix,omitempty"`
+	Size            int64             `json:"size"`
+	Updated         *time.Time        `json:"updated,omitempty"`
+	StorageClass    string            `json:"storage_class,omitempty"`
+	CRC32C          *uint32           `json:"crc32c,omitempty"`
+	ContentType     string            `json:"content_type,omitempty"`
+	MD5             []byte            `json:"md5,omitempty"`
+	Generation      int64             `json:"generation,omitempty"`
+	Metageneration  int64             `json:"metageneration,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+	ContentEncoding string            `json:"content_encoding,omitempty"`
+	KMSKeyName      string            `json:"kms_key_name,omitempty"`
+}
+
+// newObjectRecord は、obj を JSON 出力用のレコードに変換します。
+func newObjectRecord(obj remoteio.ObjectInfo) objectRecord {
+	rec := objectRecord{
+		URI:             obj.URI,
+		Name:            obj.Name,
+		Prefix:          obj.IsPrefix,
+		Size:            obj.Size,
+		StorageClass:    obj.StorageClass,
+		CRC32C:          obj.CRC32C,
+		ContentType:     obj.ContentType,
+		MD5:             obj.MD5,
+		Generation:      obj.Generation,
+		Metageneration:  obj.Metageneration,
+		Metadata:        obj.Metadata,
+		ContentEncoding: obj.ContentEncoding,
+		KMSKeyName:      obj.KMSKeyName,
+	}
+	if !obj.Updated.IsZero() {
+		updated := obj.Updated.UTC()
+		rec.Updated = &updated
+	}
+	return rec
+}
+
+// bucketRecord は、rls gs:// の --json で出力する1件分のレコードです。
+type bucketRecord struct {
+	URI                      string            `json:"uri"`
+	Name                     string            `json:"name"`
+	Location                 string            `json:"location,omitempty"`
+	LocationType             string            `json:"location_type,omitempty"`
+	StorageClass             string            `json:"storage_class,omitempty"`
+	UniformBucketLevelAccess bool              `json:"uniform_bucket_level_access"`
+	Versioning               bool              `json:"versioning"`
+	Created                  *time.Time        `json:"created,omitempty"`
+	Labels                   map[string]string `json:"labels,omitempty"`
+}
+
+// newRlsCmd は 'rls' サブコマンドを生成します。
+func newRlsCmd() *cobra.Command {
+	var flags rlsFlags
+
+	rlsCmd := &cobra.Command{
+		Use:   "rls [path]",
+		Short: "ディレクトリ/プレフィックス配下のファイルを一覧表示します。",
+		Long: `指定されたローカルディレクトリ、または GCS URI などのプレフィックス直下のファイルとサブディレクトリを一覧表示します。
+-r を指定するとサブディレクトリ配下のすべてのファイルを、-l を指定するとサイズ、更新日時、ストレージクラスも表示します。
+--json を指定すると、1件ごとに1行の JSON (NDJSON) で出力します。
+gs:// を指定すると、--project (省略時は環境変数 GOOGLE_CLOUD_PROJECT または CLOUDSDK_CORE_PROJECT) のプロジェクトのバケットを一覧表示します (-l では作成日時、ロケーション、ストレージクラス、均一なバケットレベルのアクセスの有無も表示します)。`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRls(cmd, args, &flags)
+		},
+	}
+
+	rlsCmd.Flags().BoolVarP(&flags.Long, "long", "l", false, "サイズ、更新日時、ストレージクラスも表示")
+	rlsCmd.Flags().BoolVar(&flags.JSON, "json", false, "NDJSON形式で出力")
+	rlsCmd.Flags().BoolVarP(&flags.Recursive, "recursive", "r", false, "サブディレクトリ配下のすべてのファイルを一覧")
+	rlsCmd.Flags().StringVar(&flags.Project, "project", "", "gs:// でバケットを一覧するプロジェクトID (省略時は環境変数 GOOGLE_CLOUD_PROJECT または CLOUDSDK_CORE_PROJECT)")
+
+	return rlsCmd
+}
+
+// runRls は rls コマンドの実行ロジックです。
+func runRls(cmd *cobra.Command, args []string, flags *rlsFlags) error {
+	if jsonOutput(cmd) {
+		flags.JSON = true // --format json は --json と同じ
+	}
+	ctx := cmd.Context()
+	path := args[0]
+	if strings.TrimRight(path, "/") == "gs:" {
+		return runRlsBuckets(cmd, flags)
+	}
+
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	inputReader, err := clientFactory.NewInputReader()
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+	lister, ok := inputReader.(remoteio.ObjectLister)
+	if !ok {
+		return errors.New(tr("InputReaderが一覧の取得をサポートしていません"))
+	}
+
+	opts := []remoteio.ListOption{remoteio.WithPageSize(rlsPageSize)}
+	if !flags.Recursive {
+		opts = append(opts, remoteio.WithDelimiter("/"))
+	}
+
+	// 大量のファイルがあっても順次表示できるよう、ページごとに出力する
+	out := cmd.OutOrStdout()
+	var files int
+	var total int64
+	for {
+		page, err := lister.ListObjectsPage(ctx, path, opts...)
+		if err != nil {
+			return fmt.Errorf(tr("一覧の取得に失敗しました (%s)")+": %w", path, err)
+		}
+		for _, obj := range page.Objects {
+			if err := printRlsEntry(out, obj, flags); err != nil {
+				return err
+			}
+			if !obj.IsPrefix {
+				files++
+				total += obj.Size
+			}
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		opts = append(opts, remoteio.WithPageToken(page.NextPageToken))
+	}
+
+	if flags.Long && !flags.JSON {
+		fmt.Fprintln(out, trf("合計: %d ファイル, %d バイト", files, total))
+	}
+	return nil
+}
+
+// runRlsBuckets は、rls gs:// でプロジェクトのバケットを一覧表示します。
+func runRlsBuckets(cmd *cobra.Command, flags *rlsFlags) error {
+	ctx := cmd.Context()
+	project, err := resolveProject(flags.Project)
+	if err != nil {
+		return err
+	}
+
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	inputReader, err := clientFactory.NewInputReader()
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+	lister, ok := inputReader.(remoteio.BucketLister)
+	if !ok {
+		return errors.New(tr("InputReaderがバケットの一覧をサポートしていません"))
+	}
+	buckets, err := lister.ListBuckets(ctx, project)
+	if err != nil {
+		return fmt.Errorf(tr("バケットの一覧取得に失敗しました (プロジェクト: %s)")+": %w", project, err)
+	}
+
+	out := cmd.OutOrStdout()
+	for _, b := range buckets {
+		if err := printBucketEntry(out, b, flags); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printBucketEntry は、フラグに応じた形式でバケット1件分を出力します。
+func printBucketEntry(w io.Writer, b remoteio.BucketInfo, flags *rlsFlags) error {
+	switch {
+	case flags.JSON:
+		rec := bucketRecord{
+			URI:                      b.URI,
+			Name:                     b.Name,
+			Location:                 b.Location,
+			LocationType:             b.LocationType,
+			StorageClass:             b.StorageClass,
+			UniformBucketLevelAccess: b.UniformBucketLevelAccess,
+			Versioning:               b.Versioning,
+			Labels:                   b.Labels,
+		}
+		if !b.Created.IsZero() {
+			created := b.Created.UTC()
+			rec.Created = &created
+		}
+		return writeJSONLine(w, rec)
+	case flags.Long:
+		access := "fine-grained"
+		if b.UniformBucketLevelAccess {
+			access = "uniform"
+		}
+		_, err := fmt.Fprintf(w, "%-20s  %-16s  %-8s  %-12s  %s/\n", b.Created.UTC().Format(time.RFC3339), b.Location, b.StorageClass, access, b.URI)
+		return err
+	default:
+		_, err := fmt.Fprintln(w, b.URI+"/")
+		return err
+	}
+}
+
+// printRlsEntry は、フラグに応じた形式で1件分を出力します。
+func printRlsEntry(w io.Writer, obj remoteio.ObjectInfo, flags *rlsFlags) error {
+	switch {
+	case flags.JSON:
+		data, err := json.Marshal(newObjectRecord(obj))
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case flags.Long && obj.IsPrefix:
+		_, err := fmt.Fprintf(w, "%12s  %-20s  %-8s  %s\n", "", "", "", obj.URI)
+		return err
+	case flags.Long:
+		storageClass := obj.StorageClass
+		if storageClass == "" {
+			storageClass = "-"
+		}
+		_, err := fmt.Fprintf(w, "%12d  %-20s  %-8s  %s\n", obj.Size, obj.Updated.UTC().Format(time.RFC3339), storageClass, obj.URI)
+		return err
+	default:
+		_, err := fmt.Fprintln(w, obj.URI)
+		return err
+	}
+}
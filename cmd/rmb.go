@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/spf13/cobra"
+)
+
+// projectEnvVars は、--project を省略した場合にプロジェクトIDを取得する環境変数です (先に設定されているものを使用します)。
+var projectEnvVars = []string{"GOOGLE_CLOUD_PROJECT", "CLOUDSDK_CORE_PROJECT"}
+
+// resolveProject は、--project の値、または省略された場合は環境変数からプロジェクトIDを返します。
+func resolveProject(project string) (string, error) {
+	if project != "" {
+		return project, nil
+	}
+	for _, name := range projectEnvVars {
+		if v := os.Getenv(name); v != "" {
+			return v, nil
+		}
+	}
+	return "", usageError(fmt.Errorf(tr("--project でプロジェクトIDを指定してください (または環境変数 %s を設定してください)"), strings.Join(projectEnvVars, " / ")))
+}
+
+// rmbFlags は rmb コマンド固有のフラグを保持します。
+type rmbFlags struct {
+	Project       string // --project バケットを作成するプロジェクトID
+	Location      string // --location バケットのロケーション
+	StorageClass  string // --storage-class バケットの既定のストレージクラス
+	UniformAccess bool   // --uniform-access 均一なバケットレベルのアクセスを有効にする
+	Versioning    bool   // --versioning オブジェクトのバージョニングを有効にする
+}
+
+// newRmbCmd は 'rmb' サブコマンドを生成します。
+func newRmbCmd() *cobra.Command {
+	var flags rmbFlags
+
+	rmbCmd := &cobra.Command{
+		Use:   "rmb [gcs_uri...]",
+		Short: "GCS バケットを作成します。",
+		Long: `指定された GCS URI (gs://bucket) のバケットを、--project (省略時は環境変数 GOOGLE_CLOUD_PROJECT または CLOUDSDK_CORE_PROJECT) のプロジェクトに作成します。
+--location、--storage-class でロケーションと既定のストレージクラスを、--uniform-access で均一なバケットレベルのアクセスを、--versioning でオブジェクトのバージョニングを指定します。
+同じ名前のバケットが既に存在する場合は失敗します (終了コード 5)。`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRmb(cmd, args, &flags)
+		},
+	}
+
+	rmbCmd.Flags().StringVar(&flags.Project, "project", "", "バケットを作成するプロジェクトID (省略時は環境変数 GOOGLE_CLOUD_PROJECT または CLOUDSDK_CORE_PROJECT)")
+	rmbCmd.Flags().StringVar(&flags.Location, "location", "", "バケットのロケーション (例: US、ASIA-NORTHEAST1。省略時は US)")
+	rmbCmd.Flags().StringVar(&flags.StorageClass, "storage-class", "", "バケットの既定のストレージクラス (例: STANDARD、NEARLINE、COLDLINE、ARCHIVE。省略時は STANDARD)")
+	rmbCmd.Flags().BoolVar(&flags.UniformAccess, "uniform-access", false, "均一なバケットレベルのアクセスを有効にする (オブジェクトごとの ACL を無効にする)")
+	rmbCmd.Flags().BoolVar(&flags.Versioning, "versioning", false, "オブジェクトのバージョニングを有効にする")
+
+	return rmbCmd
+}
+
+// runRmb は rmb コマンドの実行ロジックです。
+func runRmb(cmd *cobra.Command, args []string, flags *rmbFlags) error {
+	ctx := cmd.Context()
+	for _, uri := range args {
+		if !remoteio.IsGCSURI(uri) {
+			return usageError(fmt.Errorf(tr("バケットの URI は gs://bucket の形式で指定してください: %s"), uri))
+		}
+	}
+	project, err := resolveProject(flags.Project)
+	if err != nil {
+		return err
+	}
+	var opts []remoteio.BucketOption
+	if flags.Location != "" {
+		opts = append(opts, remoteio.WithBucketLocation(flags.Location))
+	}
+	if flags.StorageClass != "" {
+		opts = append(opts, remoteio.WithBucketStorageClass(strings.ToUpper(flags.StorageClass)))
+	}
+	if flags.UniformAccess {
+		opts = append(opts, remoteio.WithUniformBucketLevelAccess())
+	}
+	if flags.Versioning {
+		opts = append(opts, remoteio.WithBucketVersioning())
+	}
+
+	manager, err := bucketManager(cmd)
+	if err != nil {
+		return err
+	}
+	for _, uri := range args {
+		if err := manager.CreateBucket(ctx, strings.TrimSuffix(uri, "/"), project, opts...); err != nil {
+			return fmt.Errorf(tr("バケットの作成に失敗しました (%s)")+": %w", uri, err)
+		}
+		logger().Info(tr("バケットを作成しました"), slog.String("uri", uri), slog.String("project", project))
+	}
+	return nil
+}
+
+// rrbFlags は rrb コマンド固有のフラグを保持します。
+type rrbFlags struct {
+	Force bool // --force バケットのすべてのオブジェクトを削除してからバケットを削除する
+}
+
+// newRrbCmd は 'rrb' サブコマンドを生成します。
+func newRrbCmd() *cobra.Command {
+	var flags rrbFlags
+
+	rrbCmd := &cobra.Command{
+		Use:   "rrb [gcs_uri...]",
+		Short: "GCS バケットを削除します。",
+		Long: `指定された GCS URI (gs://bucket) の空のバケットを削除します。
+--force を指定すると、バケットのすべてのオブジェクトを非現行の世代を含めて削除してから、バケットを削除します (元に戻せません)。`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRrb(cmd, args, &flags)
+		},
+	}
+
+	rrbCmd.Flags().BoolVar(&flags.Force, "force", false, "バケットのすべてのオブジェクト (非現行の世代を含む) を削除してからバケットを削除")
+
+	return rrbCmd
+}
+
+// runRrb は rrb コマンドの実行ロジックです。
+func runRrb(cmd *cobra.Command, args []string, flags *rrbFlags) error {
+	ctx := cmd.Context()
+	for _, uri := range args {
+		if !remoteio.IsGCSURI(uri) {
+			return usageError(fmt.Errorf(tr("バケットの URI は gs://bucket の形式で指定してください: %s"), uri))
+		}
+	}
+
+	manager, err := bucketManager(cmd)
+	if err != nil {
+		return err
+	}
+	for _, uri := range args {
+		uri = strings.TrimSuffix(uri, "/")
+		if flags.Force {
+			deleted, err := manager.EmptyBucket(ctx, uri)
+			if err != nil {
+				return fmt.Errorf(tr("バケットのオブジェクトの削除に失敗しました (%s)")+": %w", uri, err)
+			}
+			logger().Info(tr("バケットのオブジェクトを削除しました"), slog.String("uri", uri), slog.Int("objects", deleted))
+		}
+		if err := manager.DeleteBucket(ctx, uri); err != nil {
+			return fmt.Errorf(tr("バケットの削除に失敗しました (%s)")+": %w", uri, err)
+		}
+		logger().Info(tr("バケットを削除しました"), slog.String("uri", uri))
+	}
+	return nil
+}
+
+// bucketManager は、ファクトリから作成した OutputWriter を BucketManager として返します。
+func bucketManager(cmd *cobra.Command) (remoteio.BucketManager, error) {
+	clientFactory, err := GetFactoryFromContext(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+	writer, err := clientFactory.NewOutputWriter()
+	if err != nil {
+		return nil, fmt.Errorf(tr("OutputWriterの作成に失敗しました")+": %w", err)
+	}
+	manager, ok := writer.(remoteio.BucketManager)
+	if !ok {
+		return nil, errors.New(tr("OutputWriterがバケットの作成・削除をサポートしていません"))
+	}
+	return manager, nil
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shouni/go-remote-io/pkg/fusefs"
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// rmountFlags は rmount コマンド固有のフラグを保持します。
+type rmountFlags struct {
+	Write    bool          // --write 書き込みを許可し、閉じたファイルをアップロードする
+	CacheTTL time.Duration // --cache-ttl ディレクトリの一覧とファイルの情報をキャッシュする時間
+}
+
+// newRmountCmd は 'rmount' サブコマンドを生成します。
+func newRmountCmd() *cobra.Command {
+	var flags rmountFlags
+
+	rmountCmd := &cobra.Command{
+		Use:   "rmount [uri] [mountpoint]",
+		Short: "リモートのプレフィックスを FUSE でローカルのディレクトリにマウントします。",
+		Long: `GCS URI (gs://bucket/prefix) などのプレフィックスを FUSE のファイルシステムとしてマウントし、ローカルのパスしか扱えないツールから読み込めるようにします。
+ディレクトリは "/" 区切りの一覧で、ファイルの内容は読み込んだ範囲だけを範囲読み込みで取得するため、大きなファイルの一部だけを読むツールでも全体をダウンロードしません。
+既定では読み取り専用でマウントします。--write を指定すると、作成・変更したファイルを閉じた時点でアップロードし、削除・名前の変更もリモートへ反映します (ライトスルー)。
+Linux、macOS (macFUSE)、FreeBSD で使用できます。Ctrl+C、または fusermount -u / umount でアンマウントします。`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRmount(cmd, args, &flags)
+		},
+	}
+
+	rmountCmd.Flags().BoolVar(&flags.Write, "write", false, "書き込みを許可し、作成・変更したファイルを閉じた時点でアップロード (削除・名前の変更も反映)")
+	rmountCmd.Flags().DurationVar(&flags.CacheTTL, "cache-ttl", fusefs.DefaultCacheTTL, "ディレクトリの一覧とファイルの情報をキャッシュする時間 (他のクライアントによる変更はこの時間が経過してから反映)")
+
+	return rmountCmd
+}
+
+// runRmount は rmount コマンドの実行ロジックです。
+func runRmount(cmd *cobra.Command, args []string, flags *rmountFlags) error {
+	ctx := cmd.Context()
+	uri, dir := args[0], args[1]
+	if remoteio.SchemeOf(uri) == "" {
+		return usageError(fmt.Errorf(tr("マウントするリモートの URI を指定してください: %s"), uri))
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return usageError(fmt.Errorf(tr("マウント先には既存のディレクトリを指定してください: %s"), dir))
+	}
+	if flags.CacheTTL < 0 {
+		return usageError(fmt.Errorf(tr("--cache-ttl には 0 以上の時間を指定してください: %s"), flags.CacheTTL))
+	}
+
+	// 1. ClientFactory とリーダー・ライターの取得 (DI)
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	inputReader, err := clientFactory.NewInputReader()
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+	opts := []fusefs.Option{fusefs.WithCacheTTL(flags.CacheTTL), fusefs.WithLogger(logger())}
+	if flags.Write {
+		writer, err := clientFactory.NewOutputWriter()
+		if err != nil {
+			return fmt.Errorf(tr("OutputWriterの作成に失敗しました")+": %w", err)
+		}
+		opts = append(opts, fusefs.WithWriteThrough(writer))
+	}
+
+	// 2. マウントする
+	mp, err := fusefs.Mount(ctx, dir, uri, inputReader, opts...)
+	if err != nil {
+		return fmt.Errorf(tr("マウントに失敗しました")+": %w", err)
+	}
+	logger().Info(tr("マウント開始"), slog.String("uri", uri), slog.String("mountpoint", dir), slog.Bool("write", flags.Write))
+
+	// 3. 中断されるか、外部からアンマウントされるまで待つ
+	unmounted := make(chan struct{})
+	go func() {
+		mp.Wait()
+		close(unmounted)
+	}()
+	select {
+	case <-unmounted:
+		logger().Info(tr("アンマウントされました"), slog.String("mountpoint", dir))
+		return nil
+	case <-ctx.Done():
+		// 使用中のプロセスがある間はアンマウントできないため、終了するまで再試行する (2回目のシグナルで即時終了)
+		if err := mp.Unmount(); err != nil {
+			logger().Warn(tr("マウント先を使用中のため、使用中のプロセスが終了するまでアンマウントを再試行します (もう一度 Ctrl+C で強制終了)"), slog.String("mountpoint", dir), slog.Any("error", err))
+			ticker := time.NewTicker(time.Second)
+			defer ticker.Stop()
+			for err != nil {
+				select {
+				case <-unmounted:
+					err = nil
+				case <-ticker.C:
+					err = mp.Unmount()
+				}
+			}
+		}
+		<-unmounted
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/spf13/cobra"
+)
+
+// newRmvCmd は 'rmv' サブコマンドを生成します。
+func newRmvCmd() *cobra.Command {
+	rmvCmd := &cobra.Command{
+		Use:   "rmv [source] [destination]",
+		Short: "ファイルまたはオブジェクトを移動 (名前変更) します。",
+		Long: `指定されたローカルファイル、または GCS URI などで指定されたオブジェクトを移動先へ移動します。
+GCS 間と S3 間はサーバー側のコピーと削除で (データをダウンロードせずに)、ローカルファイルは名前変更で移動します。
+異なるバックエンド間の移動は、コピーが完了してからコピー元を削除するため、途中で失敗してもコピー元は失われません。
+移動先が "/" で終わる場合、またはローカルディレクトリの場合は、その配下へ同じファイル名で移動します。`,
+		Annotations: dryRunAnnotations(),
+		Args:        cobra.ExactArgs(2),
+		RunE:        runRmv,
+	}
+	return rmvCmd
+}
+
+// runRmv は rmv コマンドの実行ロジックです。
+func runRmv(cmd *cobra.Command, args []string) (err error) {
+	ctx := cmd.Context()
+	srcPath, dstPath := args[0], moveDestination(args[0], args[1])
+
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	writer, err := clientFactory.NewOutputWriter()
+	if err != nil {
+		return fmt.Errorf(tr("OutputWriterの作成に失敗しました")+": %w", err)
+	}
+	mover, ok := writer.(remoteio.Mover)
+	if !ok {
+		return errors.New(tr("OutputWriterが移動をサポートしていません"))
+	}
+	inputReader, err := clientFactory.NewInputReader()
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+	if appFlagsFrom(cmd.Context()).DryRun {
+		size, err := statSize(ctx, inputReader, srcPath)
+		if err != nil {
+			return err
+		}
+		plan := newDryRunPlan(cmd)
+		plan.move(srcPath, dstPath, size)
+		plan.summary()
+		return nil
+	}
+	results := newResultWriter(cmd, inputReader)
+	defer func() {
+		if err != nil {
+			results.failed(srcPath, dstPath, err)
+			return
+		}
+		results.transferred(ctx, srcPath, dstPath, statusMoved)
+	}()
+
+	// 1. サーバー側 (またはローカルの名前変更) で移動する
+	err = mover.Move(ctx, srcPath, dstPath)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, remoteio.ErrMoveUnsupported) {
+		return fmt.Errorf(tr("移動に失敗しました (%s)")+": %w", srcPath, err)
+	}
+
+	// 2. 異なるバックエンド間では、コピーが完了してからコピー元を削除する
+	deleter, ok := writer.(remoteio.Deleter)
+	if !ok {
+		return errors.New(tr("OutputWriterが削除をサポートしていません"))
+	}
+	logger().Info(tr("コピーしてから移動元を削除します"), slog.String("source", srcPath), slog.String("destination", dstPath))
+	if err := copyObject(ctx, inputReader, writer, srcPath, dstPath, transferOptions{}, nil); err != nil {
+		return err
+	}
+	if err := deleter.Delete(ctx, srcPath); err != nil {
+		return fmt.Errorf(tr("移動先へのコピーは完了しましたが、移動元の削除に失敗しました (%s)")+": %w", srcPath, err)
+	}
+	return nil
+}
+
+// moveDestination は、移動先がディレクトリを指す場合 ("/" で終わる、または既存のローカルディレクトリ) に、
+// 移動元と同じファイル名を連結した移動先を返します。
+func moveDestination(src, dst string) string {
+	if !isDirDestination(dst) {
+		return dst
+	}
+	name := path.Base(src)
+	if remoteio.SchemeOf(src) == "" {
+		name = filepath.Base(src)
+	}
+	return remoteio.JoinURI(dst, name)
+}
+
+// isDirDestination は、書き込み先 dst がディレクトリを指す ("/" で終わる、または既存のローカルディレクトリ) かどうかを返します。
+func isDirDestination(dst string) bool {
+	if strings.HasSuffix(dst, "/") {
+		return true
+	}
+	if remoteio.SchemeOf(dst) != "" {
+		return false
+	}
+	info, err := os.Stat(dst)
+	return err == nil && info.IsDir()
+}
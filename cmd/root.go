@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/shouni/go-remote-io/pkg/factory"
+	"github.com/shouni/go-remote-io/pkg/remoteio"
 )
 
 const (
@@ -26,106 +28,342 @@ func GetFactoryFromContext(ctx context.Context) (factory.Factory, error) {
 	val := ctx.Value(FactoryKey{})
 
 	if val == nil {
-		return nil, fmt.Errorf("コンテキストにファクトリが見つかりません。")
+		return nil, errors.New(tr("コンテキストにファクトリが見つかりません。"))
 	}
 
 	// 型アサーションは factory.Factory インターフェースに対して行う
 	f, ok := val.(factory.Factory)
 	if !ok {
-		return nil, fmt.Errorf("コンテキストの値が期待される型 (factory.Factory) ではありません。")
+		return nil, errors.New(tr("コンテキストの値が期待される型 (factory.Factory) ではありません。"))
 	}
 
 	return f, nil
 }
 
 // AppFlags はこのアプリケーション固有の永続フラグを保持
+// NewRootCmd で構築したコマンドツリーごとに作成し、実行中のコマンドのコンテキストから appFlagsFrom で取り出します。
 type AppFlags struct {
-	TimeoutSec int // --timeout ClientFactory初期化時のコンテキストタイムアウト（秒）
+	TimeoutSec     int           // --timeout ClientFactory初期化時のコンテキストタイムアウト（秒）
+	OpTimeout      time.Duration // --op-timeout 各操作のタイムアウト (転送中は無通信の時間)
+	Lang           string        // --lang CLI出力の言語 (ja|en)
+	SFTPKey        string        // --sftp-key SFTPの認証に使用する秘密鍵ファイル
+	SFTPKnownHosts string        // --sftp-known-hosts SFTPのホスト鍵検証に使用する known_hosts ファイル
+	SFTPInsecure   bool          // --sftp-insecure-ignore-host-key SFTPのホスト鍵を検証しない
+	Retries        int           // --retries 一時的なエラーで失敗したリクエストを再試行する回数 (負の場合は既定)
+	RetryBackoff   time.Duration // --retry-backoff 最初の再試行までの待ち時間の上限 (0 の場合は既定)
+	BillingProject string        // --billing-project GCS へのリクエストの料金を請求するプロジェクト (Requester Pays のバケット用)
+	Format         string        // --format コマンドの結果の出力形式 (text|json)
+	LogFormat      string        // --log-format ログの出力形式 (text|json)
+	Quiet          bool          // --quiet 警告とエラー以外のログを出力しない
+	DryRun         bool          // --dry-run 転送・移動・削除の対象を表示し、書き込み先を変更せずに終了
+	Proxy          string        // --proxy リモートの URI の読み書きを経由させるプロキシ (host:port)
+	ProxyTLS       bool          // --proxy-tls プロキシへ TLS で接続する
+	ProxyCA        string        // --proxy-ca プロキシの証明書の検証に使用する CA 証明書ファイル
+	Profile        string        // --profile 使用する設定ファイルのプロファイル
+
+	config activeConfig // 選択されたプロファイルとエイリアス (applyConfig で設定する)
 }
 
-var appFlags AppFlags
+// sftpPassphraseEnv は、SFTPの秘密鍵のパスフレーズを指定する環境変数です。
+// コマンドライン引数に秘密情報を残さないよう、フラグではなく環境変数で受け取ります。
+const sftpPassphraseEnv = "REMOTEIO_SFTP_KEY_PASSPHRASE"
+
+// appFlagsKey は context.Context に *AppFlags を格納・取得するための非公開キーです。
+type appFlagsKey struct{}
+
+// appFlagsFrom は、コマンドのコンテキスト ctx に格納されたコマンドツリーの *AppFlags を返します。
+// PersistentPreRunE の前など、格納されていない場合は各フラグの既定値を返します。
+func appFlagsFrom(ctx context.Context) *AppFlags {
+	if ctx != nil {
+		if flags, ok := ctx.Value(appFlagsKey{}).(*AppFlags); ok {
+			return flags
+		}
+	}
+	return &AppFlags{TimeoutSec: defaultTimeoutSec, Retries: -1, Format: formatText, LogFormat: logFormatText}
+}
 
-// rootCmd の定義
-var rootCmd = &cobra.Command{
-	Use:   appName,
-	Short: "リモートI/O操作のためのCLIツール。",
-	Long:  "ローカルファイルとGCS URIをサポートする、リモートI/O操作のためのCLIツールです。",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Help()
-	},
+// injectAppFlags は、コマンドのコンテキストにコマンドツリーの *AppFlags を格納します。
+func injectAppFlags(cmd *cobra.Command, flags *AppFlags) {
+	cmd.SetContext(context.WithValue(cmd.Context(), appFlagsKey{}, flags))
 }
 
 // --- アプリケーション固有のカスタム関数 ---
 
-// addAppPersistentFlags は、アプリケーション固有の永続フラグをルートコマンドに追加します。
-func addAppPersistentFlags(rootCmd *cobra.Command) {
+// addAppPersistentFlags は、アプリケーション固有の永続フラグを、値を flags に格納するようルートコマンドに追加します。
+func addAppPersistentFlags(rootCmd *cobra.Command, appFlags *AppFlags) {
 	// 1. アプリケーション固有フラグの登録
 	rootCmd.PersistentFlags().IntVar(&appFlags.TimeoutSec, "timeout", defaultTimeoutSec, "GCSリクエストのタイムアウト時間（秒）")
+	rootCmd.PersistentFlags().DurationVar(&appFlags.OpTimeout, "op-timeout", 0, "各操作 (読み込み・書き込み・コピーなど) のタイムアウト。転送中はこの時間データが転送されなかった場合に中断します (省略時は無制限)")
+	rootCmd.PersistentFlags().StringVar(&appFlags.Lang, "lang", detectLang(), "CLI出力の言語 (ja|en)。省略時は LC_ALL などの環境変数から決定します")
+	rootCmd.PersistentFlags().IntVar(&appFlags.Retries, "retries", -1, "一時的なエラー (429、5xx、接続のリセットなど) で失敗したGCSリクエストと、rcopy / sync / rbatch で失敗したファイルを再試行する回数 (省略時はリクエストは中断されるまで、ファイルは2回)")
+	rootCmd.PersistentFlags().DurationVar(&appFlags.RetryBackoff, "retry-backoff", 0, "最初の再試行までの待ち時間 (再試行のたびに倍増し、最大30秒。省略時は 1s)")
+	rootCmd.PersistentFlags().StringVar(&appFlags.Format, "format", formatText, "コマンドの結果の出力形式 (text|json)。json では、一覧・情報・転送したファイルごとの結果を1行の JSON (NDJSON) で標準出力へ出力し、ログは標準エラー出力へ出力します")
+	rootCmd.PersistentFlags().StringVar(&appFlags.LogFormat, "log-format", logFormatText, "標準エラー出力へ出力するログの形式 (text|json)")
+	rootCmd.PersistentFlags().BoolVarP(&appFlags.Quiet, "quiet", "q", false, "警告とエラー以外のログを出力しない")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "verbose")
+	rootCmd.PersistentFlags().BoolVar(&appFlags.DryRun, "dry-run", false, "rcopy / sync / rrm / rmv で、転送・移動・削除されるファイルとサイズを表示し、書き込み先を変更せずに終了")
+	rootCmd.PersistentFlags().StringVar(&appFlags.BillingProject, "billing-project", "", "GCSへのリクエストの料金を請求するプロジェクトID。リクエスト元による支払い (Requester Pays) が有効なバケットの読み書きに必要です")
+	rootCmd.PersistentFlags().StringVar(&appFlags.Profile, "profile", "", "使用する設定ファイル (--config、省略時は ~/.config/remoteio/config.yaml) のプロファイル (省略時は環境変数 REMOTEIO_PROFILE または設定ファイルの default_profile)")
+
+	// SFTP の鍵認証の設定 (省略時は ssh-agent と ~/.ssh の既定の鍵、~/.ssh/known_hosts を使用)
+	rootCmd.PersistentFlags().StringVar(&appFlags.SFTPKey, "sftp-key", "", "SFTPの認証に使用する秘密鍵ファイル (パスフレーズは環境変数 REMOTEIO_SFTP_KEY_PASSPHRASE で指定)")
+	rootCmd.PersistentFlags().StringVar(&appFlags.SFTPKnownHosts, "sftp-known-hosts", "", "SFTPのホスト鍵検証に使用する known_hosts ファイル (省略時は ~/.ssh/known_hosts)")
+	rootCmd.PersistentFlags().BoolVar(&appFlags.SFTPInsecure, "sftp-insecure-ignore-host-key", false, "SFTPのホスト鍵を検証しない (テスト環境専用)")
+
+	// プロキシ経由の読み書きの設定 (認証トークンは環境変数 REMOTEIO_PROXY_TOKEN で指定)
+	rootCmd.PersistentFlags().StringVar(&appFlags.Proxy, "proxy", "", "リモートの URI の読み書きを、指定したプロキシ (remoteio proxyd の host:port) 経由で行う (認証トークンは環境変数 REMOTEIO_PROXY_TOKEN で指定)")
+	rootCmd.PersistentFlags().BoolVar(&appFlags.ProxyTLS, "proxy-tls", false, "プロキシへ TLS で接続する (証明書はシステムの CA で検証)")
+	rootCmd.PersistentFlags().StringVar(&appFlags.ProxyCA, "proxy-ca", "", "プロキシの証明書の検証に使用する CA 証明書ファイル (PEM。指定した場合は TLS で接続)")
+}
+
+// factoryOptions は、フラグに応じた ClientFactory のオプションを組み立てます。
+func factoryOptions(appFlags *AppFlags) []factory.Option {
+	opts := []factory.Option{
+		factory.WithLogger(logger()),
+		factory.WithIOOptions(remoteio.WithSFTPConfig(remoteio.SFTPConfig{
+			KeyFile:               appFlags.SFTPKey,
+			KeyPassphrase:         os.Getenv(sftpPassphraseEnv),
+			KnownHostsFile:        appFlags.SFTPKnownHosts,
+			InsecureIgnoreHostKey: appFlags.SFTPInsecure,
+		})),
+	}
+	if appFlags.OpTimeout > 0 {
+		opts = append(opts, factory.WithIOOptions(remoteio.WithOpTimeout(appFlags.OpTimeout)))
+	}
+	if appFlags.Retries >= 0 || appFlags.RetryBackoff > 0 {
+		policy := remoteio.RetryPolicy{InitialBackoff: appFlags.RetryBackoff}
+		if appFlags.Retries >= 0 {
+			policy.MaxAttempts = appFlags.Retries + 1
+		}
+		opts = append(opts, factory.WithRetryPolicy(policy))
+	}
+	if appFlags.BillingProject != "" {
+		opts = append(opts, factory.WithBillingProject(appFlags.BillingProject))
+	}
+	return append(opts, appFlags.config.factoryOptions()...)
+}
+
+// initLang は、--lang フラグの値 lang に従って言語を設定し、コマンドツリーのヘルプテキストを翻訳します。
+// 他のアプリケーションに組み込まれた場合もこのツリーだけを翻訳するよう、remoteio のルートを受け取ります。
+func initLang(rootCmd *cobra.Command, lang string) error {
+	if err := setLang(lang); err != nil {
+		return usageError(err)
+	}
+	localizeCommandTree(rootCmd)
+	return nil
 }
 
 // initAppPreRunE は、clibase共通処理の後に実行される、アプリケーション固有のPersistentPreRunEです。
 // ここでFactoryを初期化し、Contextに格納します。
-func initAppPreRunE(cmd *cobra.Command, args []string) (factory.Factory, error) {
+func initAppPreRunE(cmd *cobra.Command, args []string, appFlags *AppFlags) (factory.Factory, error) {
 	ctx := cmd.Context()
 
 	// GCSクライアント初期化のためのコンテキストを設定
 	initCtx, cancel := context.WithTimeout(ctx, time.Duration(appFlags.TimeoutSec)*time.Second)
 	defer cancel() // 必ずキャンセルを呼び出す
 
-	// 2. Factory の初期化 (GCS Client が一度だけ作成される)
-	clientFactory, err := factory.NewClientFactory(initCtx)
+	// 2. Factory の初期化 (GCS Client は最初の GCS へのアクセス時に一度だけ作成される)
+	var clientFactory factory.Factory
+	var err error
+	if appFlags.Proxy != "" {
+		clientFactory, err = newProxyFactory(appFlags)
+	} else {
+		clientFactory, err = factory.NewClientFactory(initCtx, factoryOptions(appFlags)...)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("ClientFactoryの初期化に失敗しました: %w", err)
+		return nil, fmt.Errorf(tr("ClientFactoryの初期化に失敗しました")+": %w", err)
 	}
 
 	if clibase.Flags.Verbose {
-		slog.Info("Factory（GCSクライアント含む）を初期化し、コンテキストに格納しました。")
+		logger().Info(tr("Factoryを初期化し、コンテキストに格納しました。"))
 	}
 
 	// コマンドのコンテキストに Factory を格納
-	newCtx := context.WithValue(ctx, FactoryKey{}, clientFactory)
-	cmd.SetContext(newCtx)
+	injectFactory(cmd, clientFactory)
 
 	return clientFactory, nil
 }
 
-// --- エントリポイント ---
+// injectFactory は、コマンドのコンテキストに Factory を格納します。
+func injectFactory(cmd *cobra.Command, f factory.Factory) {
+	cmd.SetContext(context.WithValue(cmd.Context(), FactoryKey{}, f))
+}
 
-// Execute は、rootCmd を実行するメイン関数です。
-func Execute() {
-	// 実行時にFactoryを保持するための変数。Close()のために必要。
-	var factoryInstance factory.Factory
+// closeFactory は、Factory をクローズし、結果をログに出力します。
+func closeFactory(f factory.Factory) {
+	if err := f.Close(); err != nil {
+		logger().Warn(tr("GCSクライアントのクローズに失敗しました"), slog.String("error", err.Error()))
+	} else if clibase.Flags.Verbose {
+		logger().Info(tr("GCSクライアントをクローズしました。"))
+	}
+}
+
+// --- コマンドツリーの構築 ---
+
+// NewRootCmd は、remoteio のコマンドツリーを構築して返します。
+// 戻り値は他の cobra アプリケーションに AddCommand で組み込むことができます。
+//
+// f を指定した場合、そのFactoryが全サブコマンドで使用され、クローズは呼び出し元の責任となります。
+// f が nil の場合は、実行のたびに ClientFactory を初期化し、コマンドの終了時 (エラー終了を含む) にクローズします。
+// フラグの値と、選択された設定ファイルのプロファイルとエイリアスは、戻り値のコマンドツリーごとに保持します。
+// ただし、言語 (--lang)、ログの出力 (--log-format、--quiet) と clibase の共通フラグ (--config、--verbose) は
+// ライブラリのメッセージの翻訳と同じくプロセス全体で共有し、最後に実行を開始したツリーの設定に従います。
+// 異なる言語やログの設定で複数のツリーを並行して実行しないでください。
+// また、ツリーの構築では clibase の共通フラグを登録するため、NewRootCmd は並行して呼び出さないでください。
+func NewRootCmd(f factory.Factory) *cobra.Command {
+	rootCmd, _ := newRootCmd(f)
+	return rootCmd
+}
 
-	// 1. 永続フラグの追加と共通フラグの登録
-	addAppPersistentFlags(rootCmd)
+// newRootCmd は NewRootCmd の実装です。
+// 自前で初期化した Factory を、PersistentPreRunE の後のエラー終了時にも確実にクローズするための関数を併せて返します。
+func newRootCmd(f factory.Factory) (*cobra.Command, func()) {
+	appFlags := &AppFlags{}
+	var owned factory.Factory
+	closeOwned := func() {
+		if owned != nil {
+			closeFactory(owned)
+			owned = nil
+		}
+	}
 
-	// 2. PersistentPreRunE の設定
-	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
-		f, err := initAppPreRunE(cmd, args)
+	var rootCmd *cobra.Command
+	addFlags := func(rootCmd *cobra.Command) { addAppPersistentFlags(rootCmd, appFlags) }
+	rootCmd = clibase.NewRootCmd(appName, addFlags, func(cmd *cobra.Command, args []string) error {
+		injectAppFlags(cmd, appFlags)
+		if err := initLang(rootCmd, appFlags.Lang); err != nil {
+			return err
+		}
+		if err := validateFormat(appFlags.Format); err != nil {
+			return err
+		}
+		if err := initLogger(appFlags); err != nil {
+			return err
+		}
+		if err := applyConfig(cmd, args, appFlags); err != nil {
+			return err
+		}
+		if err := validateDryRun(cmd); err != nil {
+			return err
+		}
+		// 注入された Factory があればそれを使用する
+		if f != nil {
+			injectFactory(cmd, f)
+			return nil
+		}
+		created, err := initAppPreRunE(cmd, args, appFlags)
 		if err != nil {
 			return err
 		}
-		factoryInstance = f // Factory インスタンスを外部変数に格納
+		owned = created
 		return nil
+	})
+	rootCmd.Short = "リモートI/O操作のためのCLIツール。"
+	rootCmd.Long = `ローカルファイルと、GCS・S3・Azure・SFTPなどのリモートURIをサポートする、リモートI/O操作のためのCLIツールです。
+
+終了コード: 0 成功、1 分類できないエラー、2 引数やフラグの誤り、3 存在しない、4 権限不足、
+5 前提条件を満たさない、6 一部の転送のみ失敗、130 / 143 シグナルによる中断`
+	rootCmd.PersistentPostRun = func(cmd *cobra.Command, args []string) {
+		closeOwned()
 	}
 
-	// 3. サブコマンドの登録
-	rootCmd.AddCommand(rcopyCmd)
-	// rootCmd.AddCommand(remoteWriteCmd) // 必要に応じて追加
+	// サブコマンドの登録
+	rootCmd.AddCommand(newRcopyCmd())
+	rootCmd.AddCommand(newBenchCmd())
+	rootCmd.AddCommand(newSyncCmd())
+	rootCmd.AddCommand(newRlsCmd())
+	rootCmd.AddCommand(newRduCmd())
+	rootCmd.AddCommand(newRrmCmd())
+	rootCmd.AddCommand(newRmbCmd())
+	rootCmd.AddCommand(newRrbCmd())
+	rootCmd.AddCommand(newRmvCmd())
+	rootCmd.AddCommand(newRstatCmd())
+	rootCmd.AddCommand(newRexistsCmd())
+	rootCmd.AddCommand(newRversionsCmd())
+	rootCmd.AddCommand(newRhashCmd())
+	rootCmd.AddCommand(newRdiffCmd())
+	rootCmd.AddCommand(newRsignCmd())
+	rootCmd.AddCommand(newRcatCmd())
+	rootCmd.AddCommand(newRbatchCmd())
+	rootCmd.AddCommand(newRheadCmd())
+	rootCmd.AddCommand(newRtailCmd())
+	rootCmd.AddCommand(newRwatchCmd())
+	rootCmd.AddCommand(newRwatchUploadCmd())
+	rootCmd.AddCommand(newProxydCmd())
+	rootCmd.AddCommand(newRmountCmd())
+	rootCmd.AddCommand(newRarchiveCmd())
+	rootCmd.AddCommand(newRextractCmd())
+	classifyUsageErrors(rootCmd)
+	closeOnError(rootCmd, closeOwned)
+
+	// ヘルプ表示は PersistentPreRunE を経由しないため、表示直前に翻訳を適用する
+	defaultHelp := rootCmd.HelpFunc()
+	rootCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		if err := initLang(rootCmd, appFlags.Lang); err != nil {
+			logger().Warn(err.Error())
+		}
+		defaultHelp(cmd, args)
+	})
+
+	return rootCmd, closeOwned
+}
 
-	// 4. defer によるリソースクリーンアップの設定 (リソースリーク対策)
-	defer func() {
-		if factoryInstance != nil {
-			if err := factoryInstance.Close(); err != nil {
-				slog.Info("警告: GCSクライアントのクローズに失敗しました: %v", err)
-			} else if clibase.Flags.Verbose {
-				slog.Info("GCSクライアントをクローズしました。")
+// closeOnError は、c とそのサブコマンドの RunE がエラーを返した場合に closeOwned を呼び出すようにします。
+// cobra はエラー終了時に PersistentPostRun を実行しないため、組み込み先のアプリケーションから実行された場合も、
+// 自前で初期化した Factory をここでクローズします。
+func closeOnError(c *cobra.Command, closeOwned func()) {
+	if run := c.RunE; run != nil {
+		c.RunE = func(cmd *cobra.Command, args []string) error {
+			err := run(cmd, args)
+			if err != nil {
+				closeOwned()
 			}
+			return err
+		}
+	}
+	for _, sub := range c.Commands() {
+		closeOnError(sub, closeOwned)
+	}
+}
+
+// --- エントリポイント ---
+
+// Execute は、rootCmd を実行するメイン関数です。
+func Execute() {
+	rootCmd, closeOwned := newRootCmd(nil)
+	silenceOnSignal(rootCmd)
+
+	// Ctrl-C (SIGINT) と SIGTERM では、コマンドのコンテキストをキャンセルして後始末をしてから終了する
+	ctx, stop := notifySignals(context.Background())
+	err := rootCmd.ExecuteContext(ctx)
+	// RunE の外 (サブコマンドの PersistentPreRunE など) で失敗した場合に備えて、ここでも確実にクローズする
+	closeOwned()
+	if sigErr, ok := interruptedBy(ctx); ok {
+		stop()
+		logger().Warn(sigErr.Error())
+		os.Exit(sigErr.exitCode())
+	}
+	stop()
+	if err != nil {
+		if isUnknownCommand(rootCmd, os.Args[1:]) {
+			os.Exit(ExitUsage)
 		}
-	}()
+		os.Exit(ExitCode(err))
+	}
+}
+
+// exitError は、エラーの分類によらない終了コードでプロセスを終了させるためのエラーです。
+// err が nil の場合は、メッセージを表示せずに終了コードのみを返します。
+type exitError struct {
+	code int
+	err  error
+}
 
-	// 5. rootCmd.Execute() を直接呼び出します。
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+func (e *exitError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit status %d", e.code)
 	}
+	return e.err.Error()
 }
+
+func (e *exitError) Unwrap() error { return e.err }
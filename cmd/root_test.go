@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shouni/go-remote-io/pkg/factory"
+	"github.com/shouni/go-remote-io/pkg/remoteiotest"
+)
+
+func TestCommandTreesHaveSeparateFlags(t *testing.T) {
+	store := remoteiotest.NewStore()
+	store.Put("gs://bucket/a.txt", []byte("hello"))
+	f := factory.NewFakeFactory(store)
+
+	run := func(args ...string) string {
+		t.Helper()
+		var out bytes.Buffer
+		rootCmd := NewRootCmd(f)
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs(args)
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("%q: %v", args, err)
+		}
+		return out.String()
+	}
+	if got := run("--format", "json", "rstat", "gs://bucket/a.txt"); !strings.HasPrefix(got, "{") {
+		t.Errorf("--format json の出力 = %q, want JSON", got)
+	}
+	// 先に実行したツリーの --format json が、別のツリーに引き継がれないこと
+	if got := run("rstat", "gs://bucket/a.txt"); got == "" || strings.HasPrefix(got, "{") {
+		t.Errorf("--format を省略した出力 = %q, want text", got)
+	}
+}
+
+func TestCommandTreesHaveSeparateProfiles(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv(profileEnv, "")
+	config := "profiles:\n  a:\n    default_bucket: bucket-a\n  b:\n    default_bucket: bucket-b\n"
+	if err := os.MkdirAll(filepath.Join(configHome, appName), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configHome, appName, "config.yaml"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	store := remoteiotest.NewStore()
+	store.Put("gs://bucket-a/x.txt", []byte("a"))
+	store.Put("gs://bucket-b/x.txt", []byte("b"))
+	f := factory.NewFakeFactory(store)
+
+	// 異なるプロファイルを選択したツリーを並行して実行し、それぞれのプロファイルの既定のバケットで解決されること
+	// (ツリーの構築は clibase の共通フラグを登録するため、並行して行わない)
+	type run struct {
+		profile string
+		rootCmd *cobra.Command
+		out     bytes.Buffer
+	}
+	var runs []*run
+	for i := range 20 {
+		r := &run{profile: []string{"a", "b"}[i%2], rootCmd: NewRootCmd(f)}
+		r.rootCmd.SetOut(&r.out)
+		r.rootCmd.SetArgs([]string{"--profile", r.profile, "--quiet", "rstat", "gs:///x.txt"})
+		runs = append(runs, r)
+	}
+	var wg sync.WaitGroup
+	for _, r := range runs {
+		wg.Go(func() {
+			if err := r.rootCmd.Execute(); err != nil {
+				t.Errorf("--profile %s: %v", r.profile, err)
+				return
+			}
+			if want := "gs://bucket-" + r.profile + "/x.txt"; !strings.Contains(r.out.String(), want) {
+				t.Errorf("--profile %s の出力 = %q, want %q を含む", r.profile, r.out.String(), want)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func TestCloseOnError(t *testing.T) {
+	errFailed := errors.New("失敗")
+	tests := []struct {
+		name       string
+		err        error
+		wantCloses int
+	}{
+		{name: "正常終了", wantCloses: 0},
+		{name: "エラー終了", err: errFailed, wantCloses: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "root"}
+			rootCmd.AddCommand(&cobra.Command{Use: "sub", RunE: func(cmd *cobra.Command, args []string) error { return tt.err }})
+			closes := 0
+			closeOnError(rootCmd, func() { closes++ })
+			rootCmd.SetArgs([]string{"sub"})
+			rootCmd.SilenceErrors, rootCmd.SilenceUsage = true, true
+
+			if err := rootCmd.Execute(); !errors.Is(err, tt.err) {
+				t.Fatalf("Execute() = %v, want %v", err, tt.err)
+			}
+			if closes != tt.wantCloses {
+				t.Errorf("closeOwned の呼び出し = %d 回, want %d", closes, tt.wantCloses)
+			}
+		})
+	}
+}
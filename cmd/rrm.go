@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/spf13/cobra"
+)
+
+// rrmFlags は rrm コマンド固有のフラグを保持します。
+type rrmFlags struct {
+	Recursive bool // -r, --recursive ディレクトリ/プレフィックス配下をすべて削除
+}
+
+// newRrmCmd は 'rrm' サブコマンドを生成します。
+func newRrmCmd() *cobra.Command {
+	var flags rrmFlags
+
+	rrmCmd := &cobra.Command{
+		Use:   "rrm [path...]",
+		Short: "ファイルまたはオブジェクトを削除します。",
+		Long: `指定されたローカルファイル、または GCS URI などで指定されたオブジェクトを削除します。
+-r を指定すると、ディレクトリまたはプレフィックス配下のすべてのファイルを削除します。
+パスには *, ?, [...] のワイルドカードを使用できます (* は "/" に一致しません)。--dry-run を指定すると、削除せずに対象とサイズのみを表示します。`,
+		Annotations: dryRunAnnotations(),
+		Args:        cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRrm(cmd, args, &flags)
+		},
+	}
+
+	rrmCmd.Flags().BoolVarP(&flags.Recursive, "recursive", "r", false, "ディレクトリ/プレフィックス配下のすべてのファイルを削除")
+
+	return rrmCmd
+}
+
+// runRrm は rrm コマンドの実行ロジックです。
+func runRrm(cmd *cobra.Command, args []string, flags *rrmFlags) error {
+	ctx := cmd.Context()
+
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	inputReader, err := clientFactory.NewInputReader()
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+	writer, err := clientFactory.NewOutputWriter()
+	if err != nil {
+		return fmt.Errorf(tr("OutputWriterの作成に失敗しました")+": %w", err)
+	}
+	deleter, ok := writer.(remoteio.Deleter)
+	if !ok {
+		return errors.New(tr("OutputWriterが削除をサポートしていません"))
+	}
+	lister, _ := inputReader.(remoteio.ObjectLister)
+
+	// 1. すべての引数の削除対象を先に確定させる
+	var targets []remoteio.ObjectInfo
+	for _, arg := range args {
+		objects, err := expandTargets(ctx, lister, arg, flags.Recursive)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, objects...)
+	}
+
+	out := cmd.OutOrStdout()
+	if appFlagsFrom(cmd.Context()).DryRun {
+		plan := newDryRunPlan(cmd)
+		for _, obj := range targets {
+			size := obj.Size
+			if size < 0 {
+				if size, err = statSize(ctx, inputReader, obj.URI); err != nil {
+					return err
+				}
+			}
+			plan.remove(obj.URI, size)
+		}
+		plan.summary()
+		return nil
+	}
+
+	// 2. 削除の実行
+	results := newResultWriter(cmd, nil)
+	for _, obj := range targets {
+		err := deleter.Delete(ctx, obj.URI)
+		results.deleted(obj.URI, err)
+		if err != nil {
+			return fmt.Errorf(tr("削除に失敗しました (%s)")+": %w", obj.URI, err)
+		}
+	}
+	if flags.Recursive {
+		// ローカルディレクトリを再帰的に削除した場合は、空になったディレクトリも削除する
+		for _, arg := range args {
+			if remoteio.SchemeOf(arg) == "" && !hasWildcard(arg) {
+				removeEmptyDirs(arg)
+			}
+		}
+	}
+	if !jsonOutput(cmd) {
+		fmt.Fprintln(out, trf("%d 件のファイルを削除しました", len(targets)))
+	}
+	return nil
+}
+
+// expandTargets は、引数 arg が指す削除対象のファイルを返します。
+// ワイルドカードを含む場合は一致するファイルを、recursive の場合は配下のすべてのファイルを一覧します。
+// どちらでもない場合は arg のみを返し、一覧していないためサイズは -1 (不明) になります。
+func expandTargets(ctx context.Context, lister remoteio.ObjectLister, arg string, recursive bool) ([]remoteio.ObjectInfo, error) {
+	if !hasWildcard(arg) && !recursive {
+		return []remoteio.ObjectInfo{{URI: arg, Size: -1}}, nil
+	}
+	if lister == nil {
+		return nil, errors.New(tr("InputReaderが一覧の取得をサポートしていません"))
+	}
+
+	var objects []remoteio.ObjectInfo
+	var err error
+	if hasWildcard(arg) {
+		objects, err = matchWildcard(ctx, lister, arg, recursive)
+	} else if objects, err = lister.ListObjects(ctx, arg); err != nil {
+		err = fmt.Errorf(tr("一覧の取得に失敗しました (%s)")+": %w", arg, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 {
+		return nil, notFoundError(tr("一致するファイルが見つかりません: %s"), arg)
+	}
+	return objects, nil
+}
+
+// hasWildcard は、p がワイルドカード文字を含むかどうかを判定します。
+func hasWildcard(p string) bool {
+	return strings.ContainsAny(p, "*?[")
+}
+
+// matchWildcard は、ワイルドカードを含む pattern に一致するファイルを一覧します。
+// 最初のワイルドカードを含む階層より上を起点として一覧し、起点からの相対パスを path.Match で照合します。
+// recursive の場合は、パターンに一致するディレクトリ/プレフィックス配下のファイルも含めます。
+func matchWildcard(ctx context.Context, lister remoteio.ObjectLister, pattern string, recursive bool) ([]remoteio.ObjectInfo, error) {
+	base, rel := ".", pattern
+	if i := strings.LastIndex(pattern[:strings.IndexAny(pattern, "*?[")], "/"); i >= 0 {
+		base, rel = pattern[:i], pattern[i+1:]
+	}
+	if _, err := path.Match(rel, ""); err != nil {
+		return nil, fmt.Errorf(tr("無効なワイルドカードです: %s")+": %w", pattern, err)
+	}
+
+	var opts []remoteio.ListOption
+	if !recursive && !strings.Contains(rel, "/") {
+		// 一致するのは起点の直下のみのため、サブディレクトリ配下は一覧しない
+		opts = append(opts, remoteio.WithDelimiter("/"))
+	}
+	objects, err := lister.ListObjects(ctx, base, opts...)
+	if err != nil {
+		return nil, fmt.Errorf(tr("一覧の取得に失敗しました (%s)")+": %w", base, err)
+	}
+
+	depth := strings.Count(rel, "/") + 1
+	var matched []remoteio.ObjectInfo
+	for _, obj := range objects {
+		if obj.IsPrefix {
+			continue
+		}
+		name := obj.Name
+		if recursive {
+			// パターンと同じ階層までで照合する
+			if parts := strings.SplitN(name, "/", depth+1); len(parts) > depth {
+				name = strings.Join(parts[:depth], "/")
+			}
+		}
+		if ok, _ := path.Match(rel, name); ok {
+			matched = append(matched, obj)
+		}
+	}
+	return matched, nil
+}
+
+// removeEmptyDirs は、root 配下の空のディレクトリを深い順に削除します。空でないディレクトリは残ります。
+func removeEmptyDirs(root string) {
+	var dirs []string
+	filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			dirs = append(dirs, p)
+		}
+		return nil
+	})
+	for i := len(dirs) - 1; i >= 0; i-- {
+		os.Remove(dirs[i])
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/spf13/cobra"
+)
+
+// rsignFlags は rsign コマンド固有のフラグを保持します。
+type rsignFlags struct {
+	Method      string        // -m, --method 許可する HTTP メソッド
+	Duration    time.Duration // -d, --duration 有効期間
+	ContentType string        // --content-type リクエストで送信する必要がある Content-Type
+}
+
+// signedURLRecord は、rsign の --format json で出力するレコードです。
+type signedURLRecord struct {
+	URI     string    `json:"uri"`
+	Method  string    `json:"method"`
+	URL     string    `json:"url"`
+	Expires time.Time `json:"expires"` // 有効期限 (おおよその時刻)
+}
+
+// newRsignCmd は 'rsign' サブコマンドを生成します。
+func newRsignCmd() *cobra.Command {
+	var flags rsignFlags
+
+	rsignCmd := &cobra.Command{
+		Use:   "rsign [gcs_uri]",
+		Short: "GCSオブジェクトの V4 署名付きURLを生成します。",
+		Long: `指定された GCS URI のオブジェクトにアクセスするための V4 署名付きURLを生成し、標準出力に表示します。
+署名には、ファクトリが使用する認証情報 (サービスアカウント) を使用します。
+-m PUT を指定すると、認証情報を持たないクライアントからのアップロードを許可するURLを生成できます。`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRsign(cmd, args, &flags)
+		},
+	}
+
+	rsignCmd.Flags().StringVarP(&flags.Method, "method", "m", "GET", "署名付きURLで許可する HTTP メソッド (GET, PUT, HEAD, DELETE など)")
+	rsignCmd.Flags().DurationVarP(&flags.Duration, "duration", "d", remoteio.DefaultSignedURLExpiry, "署名付きURLの有効期間 (最大 168h)")
+	rsignCmd.Flags().StringVar(&flags.ContentType, "content-type", "", "リクエストで送信する必要がある Content-Type (PUT の場合)")
+
+	return rsignCmd
+}
+
+// runRsign は rsign コマンドの実行ロジックです。
+func runRsign(cmd *cobra.Command, args []string, flags *rsignFlags) error {
+	ctx := cmd.Context()
+	uri := args[0]
+
+	if !remoteio.IsGCSURI(uri) {
+		return fmt.Errorf(tr("署名付きURLは GCS URI (gs://) のみ生成できます: %s"), uri)
+	}
+
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	client, err := clientFactory.Client()
+	if err != nil {
+		return err
+	}
+
+	signedURL, err := remoteio.SignedURL(client, uri,
+		remoteio.WithSignMethod(flags.Method),
+		remoteio.WithSignExpiry(flags.Duration),
+		remoteio.WithSignContentType(flags.ContentType),
+	)
+	if err != nil {
+		return err
+	}
+	if jsonOutput(cmd) {
+		return writeJSONLine(cmd.OutOrStdout(), signedURLRecord{URI: uri, Method: flags.Method, URL: signedURL, Expires: time.Now().Add(flags.Duration).UTC()})
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), signedURL)
+	return nil
+}
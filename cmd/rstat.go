@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/spf13/cobra"
+)
+
+// rstatFlags は rstat コマンド固有のフラグを保持します。
+type rstatFlags struct {
+	JSON bool // --json NDJSON形式で出力
+}
+
+// newRstatCmd は 'rstat' サブコマンドを生成します。
+func newRstatCmd() *cobra.Command {
+	var flags rstatFlags
+
+	rstatCmd := &cobra.Command{
+		Use:   "rstat [path...]",
+		Short: "ファイルまたはオブジェクトの詳細情報を表示します。",
+		Long: `指定されたローカルファイル、または GCS URI などで指定されたオブジェクトのサイズ、更新日時、Content-Type、
+CRC32C / MD5 チェックサム、世代番号、カスタムメタデータを表示します (取得できる項目はバックエンドによって異なります)。
+--json を指定すると、1件ごとに1行の JSON (NDJSON) で出力します。`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRstat(cmd, args, &flags)
+		},
+	}
+
+	rstatCmd.Flags().BoolVar(&flags.JSON, "json", false, "NDJSON形式で出力")
+
+	return rstatCmd
+}
+
+// runRstat は rstat コマンドの実行ロジックです。
+func runRstat(cmd *cobra.Command, args []string, flags *rstatFlags) error {
+	if jsonOutput(cmd) {
+		flags.JSON = true // --format json は --json と同じ
+	}
+	ctx := cmd.Context()
+
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	inputReader, err := clientFactory.NewInputReader()
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+	stater, ok := inputReader.(remoteio.Stater)
+	if !ok {
+		return errors.New(tr("InputReaderが情報の取得をサポートしていません"))
+	}
+
+	out := cmd.OutOrStdout()
+	var errs []error
+	for _, uri := range args {
+		info, err := stater.Stat(ctx, uri)
+		if err != nil {
+			err = fmt.Errorf(tr("情報の取得に失敗しました (%s)")+": %w", uri, err)
+			if !flags.JSON {
+				return err
+			}
+			// JSON の場合は失敗したパスもレコードとして出力し、残りのパスの情報を取得する
+			writeJSONLine(out, resultRecord{URI: uri, Status: statusFailed, Error: err.Error()})
+			errs = append(errs, err)
+			continue
+		}
+		if flags.JSON {
+			data, err := json.Marshal(newObjectRecord(info))
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(out, string(data))
+			continue
+		}
+		printStat(out, info)
+	}
+	return errors.Join(errs...)
+}
+
+// printStat は、info を項目ごとに1行ずつ出力します。値のない項目は省略します。
+// チェックサムは gsutil と同じく Base64 で表示します。
+func printStat(w io.Writer, info remoteio.ObjectInfo) {
+	fmt.Fprintf(w, "%s:\n", info.URI)
+	field := func(name string, value any) {
+		fmt.Fprintf(w, "    %-18s%v\n", name+":", value)
+	}
+
+	if info.IsPrefix {
+		field("Type", "directory")
+		return
+	}
+	field("Size", info.Size)
+	if !info.Updated.IsZero() {
+		field("Updated", info.Updated.UTC().Format(time.RFC3339))
+	}
+	if info.ContentType != "" {
+		field("Content-Type", info.ContentType)
+	}
+	if info.ContentEncoding != "" {
+		field("Content-Encoding", info.ContentEncoding)
+	}
+	if info.StorageClass != "" {
+		field("Storage class", info.StorageClass)
+	}
+	if info.CRC32C != nil {
+		field("Hash (crc32c)", base64.StdEncoding.EncodeToString(binary.BigEndian.AppendUint32(nil, *info.CRC32C)))
+	}
+	if info.MD5 != nil {
+		field("Hash (md5)", base64.StdEncoding.EncodeToString(info.MD5))
+	}
+	if info.Generation != 0 {
+		field("Generation", info.Generation)
+	}
+	if info.Metageneration != 0 {
+		field("Metageneration", info.Metageneration)
+	}
+	if info.KMSKeyName != "" {
+		field("KMS key", info.KMSKeyName)
+	}
+	if len(info.Metadata) > 0 {
+		field("Metadata", "")
+		keys := make([]string, 0, len(info.Metadata))
+		for k := range info.Metadata {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			fmt.Fprintf(w, "        %-14s%s\n", k+":", info.Metadata[k])
+		}
+	}
+}
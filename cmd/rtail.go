@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/spf13/cobra"
+)
+
+// rtailFlags は rtail コマンド固有のフラグを保持します。
+type rtailFlags struct {
+	Lines    int           // -n, --lines 末尾から表示する行数
+	Bytes    string        // -c, --bytes 末尾から表示するサイズ
+	Follow   bool          // -f, --follow 追記された内容を表示し続ける
+	Interval time.Duration // --interval -f でサイズを確認する間隔
+}
+
+// newRtailCmd は 'rtail' サブコマンドを生成します。
+func newRtailCmd() *cobra.Command {
+	var flags rtailFlags
+
+	rtailCmd := &cobra.Command{
+		Use:   "rtail [path]",
+		Short: "ファイルまたはオブジェクトの末尾を表示し、追記を監視します。",
+		Long: `指定されたローカルファイル、または GCS URI などで指定されたオブジェクトの末尾の -n 行 (または -c のサイズ) を標準出力へ表示します。
+ファイル全体はダウンロードせず、末尾の範囲のみを範囲読み込みで読み込みます。
+-f を指定すると、--interval ごとにサイズと世代番号を確認し、前回の位置から追記された範囲のみを読み込んで表示し続けます (Ctrl+C で終了します)。
+サイズが前回の位置より小さくなった場合 (切り詰め、または別の内容での置き換え) や、削除されてから再作成された場合は、先頭から表示し直します。`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRtail(cmd, args, &flags)
+		},
+	}
+
+	rtailCmd.Flags().IntVarP(&flags.Lines, "lines", "n", 10, "末尾から表示する行数")
+	rtailCmd.Flags().StringVarP(&flags.Bytes, "bytes", "c", "", "末尾から表示するサイズ (例: 1KiB)。指定した場合は行数の代わりにサイズで表示")
+	rtailCmd.MarkFlagsMutuallyExclusive("lines", "bytes")
+	rtailCmd.Flags().BoolVarP(&flags.Follow, "follow", "f", false, "末尾を表示した後も、追記された内容を表示し続ける")
+	rtailCmd.Flags().DurationVar(&flags.Interval, "interval", time.Second, "-f でサイズと世代番号を確認する間隔")
+
+	return rtailCmd
+}
+
+// runRtail は rtail コマンドの実行ロジックです。
+func runRtail(cmd *cobra.Command, args []string, flags *rtailFlags) error {
+	ctx := cmd.Context()
+	uri := args[0]
+	if jsonOutput(cmd) {
+		return usageError(errors.New(tr("rtail は内容を標準出力へ出力するため、--format json は指定できません")))
+	}
+	if flags.Lines < 0 {
+		return usageError(fmt.Errorf(tr("--lines には 0 以上の行数を指定してください: %d"), flags.Lines))
+	}
+	tailBytes := int64(-1)
+	if flags.Bytes != "" {
+		n, err := parseByteSize(flags.Bytes)
+		if err != nil || n < 0 {
+			return usageError(fmt.Errorf(tr("--bytes には 0 以上のサイズを指定してください: %s"), flags.Bytes))
+		}
+		tailBytes = n
+	}
+	if flags.Interval <= 0 {
+		return usageError(fmt.Errorf(tr("--interval には正の間隔を指定してください: %s"), flags.Interval))
+	}
+
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	inputReader, err := clientFactory.NewInputReader()
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+	printer, err := newRangePrinter(ctx, inputReader, uri, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+
+	// 1. 末尾の範囲を表示する
+	info, err := printer.stat()
+	if err != nil {
+		return err
+	}
+	start := max(info.Size-tailBytes, 0)
+	if tailBytes < 0 {
+		if start, err = printer.lineStart(info, flags.Lines); err != nil {
+			return err
+		}
+	}
+	offset, err := printer.copyRange(info, start, info.Size)
+	if err != nil {
+		return err
+	}
+	if !flags.Follow {
+		return nil
+	}
+
+	// 2. 追記された範囲を表示し続ける
+	logger().Info(tr("追記の監視開始"), slog.String("uri", uri), slog.Duration("interval", flags.Interval))
+	return printer.follow(offset, flags.Interval)
+}
+
+// follow は、interval ごとにサイズと世代番号を確認し、offset 以降に追記された範囲を out へ書き出し続けます。
+// ctx がキャンセルされるまで終了しません。
+func (p *rangePrinter) follow(offset int64, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	missing := false
+	for {
+		select {
+		case <-p.ctx.Done():
+			return p.ctx.Err()
+		case <-ticker.C:
+		}
+		info, err := p.stat()
+		if errors.Is(err, remoteio.ErrNotFound) {
+			if !missing {
+				logger().Warn(tr("ファイルが見つからないため、再作成されるまで待機します"), slog.String("uri", p.uri))
+			}
+			missing = true
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if missing || info.Size < offset {
+			// 削除・切り詰め・置き換えられた場合は、新しい内容を先頭から表示する
+			logger().Warn(tr("ファイルが切り詰められたか置き換えられたため、先頭から表示します"), slog.String("uri", p.uri))
+			offset, missing = 0, false
+		}
+		offset, err = p.copyRange(info, offset, info.Size)
+		if errors.Is(err, remoteio.ErrNotFound) {
+			// 情報を取得した後に置き換えられた世代は、次の確認で読み込む
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
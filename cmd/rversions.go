@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/shouni/go-remote-io/pkg/factory"
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/spf13/cobra"
+)
+
+// rversionsFlags は rversions コマンド固有のフラグを保持します。
+type rversionsFlags struct {
+	JSON    bool  // --json NDJSON形式で出力
+	Restore int64 // --restore 現行の世代として復元する世代番号
+}
+
+// versionRecord は、rversions の --json で出力する1件分のレコードです。
+type versionRecord struct {
+	objectRecord
+	Live    bool       `json:"live"`
+	Deleted *time.Time `json:"deleted,omitempty"`
+}
+
+// newRversionsCmd は 'rversions' サブコマンドを生成します。
+func newRversionsCmd() *cobra.Command {
+	var flags rversionsFlags
+
+	rversionsCmd := &cobra.Command{
+		Use:   "rversions [gcs_uri]",
+		Short: "GCS オブジェクトの世代を一覧表示し、過去の世代を復元します。",
+		Long: `バケットのオブジェクトのバージョニングで保持された、GCS オブジェクトの現行の世代と非現行の世代を新しい順に一覧表示します。
+各行には、サイズ、更新日時、状態 (live: 現行、noncurrent: 非現行) と、その世代を読み込む URI (gs://bucket/object#generation) を表示します。
+--restore に世代番号を指定すると、その世代をサーバー側でコピーして現行のオブジェクトに戻します (誤って上書きした場合の復旧に使用します)。
+--json を指定すると、1件ごとに1行の JSON (NDJSON) で出力します。`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRversions(cmd, args, &flags)
+		},
+	}
+
+	rversionsCmd.Flags().BoolVar(&flags.JSON, "json", false, "NDJSON形式で出力")
+	rversionsCmd.Flags().Int64Var(&flags.Restore, "restore", 0, "指定した世代番号の世代を現行のオブジェクトとして復元")
+
+	return rversionsCmd
+}
+
+// runRversions は rversions コマンドの実行ロジックです。
+func runRversions(cmd *cobra.Command, args []string, flags *rversionsFlags) error {
+	if jsonOutput(cmd) {
+		flags.JSON = true // --format json は --json と同じ
+	}
+	ctx := cmd.Context()
+	uri, _ := remoteio.SplitGCSGeneration(args[0])
+
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	inputReader, err := clientFactory.NewInputReader()
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+	lister, ok := inputReader.(remoteio.VersionLister)
+	if !ok {
+		return errors.New(tr("InputReaderが世代の一覧の取得をサポートしていません"))
+	}
+	versions, err := lister.ListVersions(ctx, uri)
+	if err != nil {
+		return fmt.Errorf(tr("世代の一覧の取得に失敗しました (%s)")+": %w", uri, err)
+	}
+
+	if cmd.Flags().Changed("restore") {
+		return restoreVersion(cmd, clientFactory, inputReader, uri, versions, flags.Restore)
+	}
+
+	out := cmd.OutOrStdout()
+	for _, v := range versions {
+		if err := printVersion(out, v, flags.JSON); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printVersion は、1つの世代を出力します。
+func printVersion(w io.Writer, v remoteio.ObjectVersion, asJSON bool) error {
+	if asJSON {
+		rec := versionRecord{objectRecord: newObjectRecord(v.ObjectInfo), Live: v.Live}
+		if !v.Deleted.IsZero() {
+			deleted := v.Deleted.UTC()
+			rec.Deleted = &deleted
+		}
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+	state := "live"
+	if !v.Live {
+		state = "noncurrent"
+	}
+	_, err := fmt.Fprintf(w, "%12d  %-20s  %-10s  %s\n", v.Size, v.Updated.UTC().Format(time.RFC3339), state, v.URI)
+	return err
+}
+
+// restoreVersion は、versions のうち世代番号が generation の世代を、uri の現行のオブジェクトへサーバー側でコピーします。
+func restoreVersion(cmd *cobra.Command, clientFactory factory.Factory, reader remoteio.InputReader, uri string, versions []remoteio.ObjectVersion, generation int64) error {
+	ctx := cmd.Context()
+
+	var target *remoteio.ObjectVersion
+	for i := range versions {
+		if versions[i].Generation == generation {
+			target = &versions[i]
+			break
+		}
+	}
+	if target == nil {
+		return notFoundError(tr("世代 %d が見つかりません (%s)"), generation, uri)
+	}
+	results := newResultWriter(cmd, reader)
+	if target.Live {
+		if results != nil {
+			results.transferred(ctx, target.URI, uri, statusIdentical)
+			return nil
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), trf("世代 %d は既に現行の世代です: %s", generation, uri))
+		return nil
+	}
+
+	writer, err := clientFactory.NewOutputWriter()
+	if err != nil {
+		return fmt.Errorf(tr("OutputWriterの作成に失敗しました")+": %w", err)
+	}
+	copier, ok := writer.(remoteio.Copier)
+	if !ok {
+		return errors.New(tr("OutputWriterがサーバー側のコピーをサポートしていません"))
+	}
+	if err := copier.CopyObject(ctx, target.URI, uri); err != nil {
+		results.failed(target.URI, uri, err)
+		return fmt.Errorf(tr("世代の復元に失敗しました (%s)")+": %w", target.URI, err)
+	}
+	if results != nil {
+		results.transferred(ctx, target.URI, uri, statusCopied)
+		return nil
+	}
+
+	// 復元によって作成された新しい世代を表示する
+	live := "-"
+	if stater, ok := reader.(remoteio.Stater); ok {
+		if info, err := stater.Stat(ctx, uri); err == nil {
+			live = fmt.Sprint(info.Generation)
+		}
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), trf("世代 %d を復元しました: %s (現行の世代: %s)", generation, uri, live))
+	return nil
+}
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/spf13/cobra"
+)
+
+// rwatch が出力する変更の種類
+const (
+	watchCreated  = "created"  // 作成された (削除された後に再作成された場合を含む)
+	watchUpdated  = "updated"  // 内容が置き換えられた (GCS では世代番号、それ以外ではサイズか更新日時が変わった)
+	watchMetadata = "metadata" // メタデータのみが更新された (GCS のメタデータの世代番号が変わった)
+	watchDeleted  = "deleted"  // 削除された
+)
+
+// rwatch の --until に指定できる終了条件
+const (
+	untilExists = "exists" // 存在する (既に存在する場合はすぐに終了する)
+	untilChange = "change" // 監視を開始してから最初の変更
+)
+
+// rwatchFlags は rwatch コマンド固有のフラグを保持します。
+type rwatchFlags struct {
+	Interval time.Duration // --interval 世代番号を確認する間隔
+	Until    string        // --until 終了条件 (exists|change)。省略時は変更を出力し続ける
+	Exec     string        // --exec 変更ごとに実行するコマンド
+	Timeout  time.Duration // --timeout 監視を打ち切るまでの時間 (0 の場合は無制限)
+}
+
+// watchRecord は、rwatch が --format json で出力する1件分の変更です。削除された場合は、削除される前の情報を出力します。
+type watchRecord struct {
+	Time  time.Time `json:"time"`
+	Event string    `json:"event"`
+	objectRecord
+}
+
+// newRwatchCmd は 'rwatch' サブコマンドを生成します。
+func newRwatchCmd() *cobra.Command {
+	var flags rwatchFlags
+
+	rwatchCmd := &cobra.Command{
+		Use:   "rwatch [path]",
+		Short: "ファイルまたはオブジェクトの作成・変更・削除を監視します。",
+		Long: `指定されたローカルファイル、または GCS URI などで指定されたオブジェクトの情報を --interval ごとに取得し、
+作成 (created)、内容の置き換え (updated)、メタデータのみの更新 (metadata)、削除 (deleted) を1行ずつ表示します。
+GCS では世代番号とメタデータの世代番号で、それ以外ではサイズと更新日時で変更を判定します。
+--until exists を指定すると存在した時点で、--until change を指定すると最初の変更で終了します (上流のジョブが完了を示すマーカーを書き込むのを待つ場合に使用します)。
+--exec を指定すると、変更ごとにコマンドを実行します (REMOTEIO_EVENT、REMOTEIO_URI、REMOTEIO_GENERATION、REMOTEIO_SIZE 環境変数で変更の内容を渡します)。`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRwatch(cmd, args, &flags)
+		},
+	}
+
+	rwatchCmd.Flags().DurationVar(&flags.Interval, "interval", 5*time.Second, "情報を取得して変更を確認する間隔")
+	rwatchCmd.Flags().StringVar(&flags.Until, "until", "", "指定した条件を満たした時点で終了 (exists: 存在する、change: 最初の変更)。省略時は変更を表示し続ける")
+	rwatchCmd.Flags().StringVar(&flags.Exec, "exec", "", "変更ごとにシェルで実行するコマンド (変更の内容は REMOTEIO_EVENT などの環境変数で渡す)")
+	rwatchCmd.Flags().DurationVar(&flags.Timeout, "timeout", 0, "指定した時間が経過しても --until の条件を満たさない場合は失敗 (0 の場合は無制限)")
+
+	return rwatchCmd
+}
+
+// runRwatch は rwatch コマンドの実行ロジックです。
+func runRwatch(cmd *cobra.Command, args []string, flags *rwatchFlags) error {
+	ctx := cmd.Context()
+	uri := args[0]
+	if flags.Interval <= 0 {
+		return usageError(fmt.Errorf(tr("--interval には正の間隔を指定してください: %s"), flags.Interval))
+	}
+	if flags.Until != "" && flags.Until != untilExists && flags.Until != untilChange {
+		return usageError(fmt.Errorf(tr("--until には exists または change を指定してください: %s"), flags.Until))
+	}
+	if flags.Timeout < 0 {
+		return usageError(fmt.Errorf(tr("--timeout には 0 以上の時間を指定してください: %s"), flags.Timeout))
+	}
+
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	inputReader, err := clientFactory.NewInputReader()
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+	stater, ok := inputReader.(remoteio.Stater)
+	if !ok {
+		return errors.New(tr("InputReaderが情報の取得をサポートしていません"))
+	}
+	if flags.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, flags.Timeout)
+		defer cancel()
+	}
+
+	w := &watcher{ctx: ctx, stater: stater, uri: uri, out: cmd.OutOrStdout(), errOut: cmd.ErrOrStderr(), exec: flags.Exec, json: jsonOutput(cmd)}
+	prev, exists, err := w.stat()
+	if err != nil {
+		return err
+	}
+	if exists && flags.Until == untilExists {
+		return nil
+	}
+	logger().Info(tr("監視開始"), slog.String("uri", uri), slog.Bool("exists", exists), slog.Duration("interval", flags.Interval))
+
+	ticker := time.NewTicker(flags.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && cmd.Context().Err() == nil {
+				return fmt.Errorf(tr("%s が経過しても条件を満たさなかったため、監視を終了しました (%s)"), flags.Timeout, uri)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		cur, curExists, err := w.stat()
+		if err != nil {
+			return err
+		}
+		event := watchEvent(prev, exists, cur, curExists)
+		if curExists {
+			prev = cur
+		}
+		exists = curExists
+		if event == "" {
+			continue
+		}
+		if err := w.emit(event, prev); err != nil {
+			if flags.Until != "" {
+				return err
+			}
+			logger().Warn(tr("コマンドの実行に失敗しました"), slog.String("command", flags.Exec), slog.String("error", err.Error()))
+		}
+		if flags.Until == untilChange || (flags.Until == untilExists && exists) {
+			return nil
+		}
+	}
+}
+
+// watchEvent は、前回の情報 prev と今回の情報 cur を比較し、変更の種類を返します。変更がない場合は空文字列を返します。
+func watchEvent(prev remoteio.ObjectInfo, prevExists bool, cur remoteio.ObjectInfo, curExists bool) string {
+	switch {
+	case !prevExists && curExists:
+		return watchCreated
+	case prevExists && !curExists:
+		return watchDeleted
+	case !curExists:
+		return ""
+	case cur.Generation != prev.Generation:
+		return watchUpdated
+	case cur.Generation == 0 && (cur.Size != prev.Size || !cur.Updated.Equal(prev.Updated)):
+		// 世代番号のないバックエンドは、サイズと更新日時で判定する
+		return watchUpdated
+	case cur.Metageneration != prev.Metageneration:
+		return watchMetadata
+	default:
+		return ""
+	}
+}
+
+// watcher は、rwatch が1つのファイルまたはオブジェクトを監視するための状態です。
+type watcher struct {
+	ctx    context.Context
+	stater remoteio.Stater
+	uri    string
+	out    io.Writer
+	errOut io.Writer
+	exec   string // 変更ごとに実行するコマンド。空の場合は実行しない
+	json   bool   // --format json
+}
+
+// stat は、ファイルまたはオブジェクトの情報と、存在するかどうかを返します。
+func (w *watcher) stat() (remoteio.ObjectInfo, bool, error) {
+	info, err := w.stater.Stat(w.ctx, w.uri)
+	if errors.Is(err, remoteio.ErrNotFound) {
+		return remoteio.ObjectInfo{}, false, nil
+	}
+	if err != nil {
+		return remoteio.ObjectInfo{}, false, fmt.Errorf(tr("情報の取得に失敗しました (%s)")+": %w", w.uri, err)
+	}
+	return info, true, nil
+}
+
+// emit は、変更 event を出力し、--exec が指定された場合はコマンドを実行します。
+// 削除された場合の info は、削除される前の情報です。
+func (w *watcher) emit(event string, info remoteio.ObjectInfo) error {
+	now := time.Now().UTC()
+	if w.json {
+		rec := watchRecord{Time: now, Event: event, objectRecord: newObjectRecord(info)}
+		rec.URI = w.uri
+		writeJSONLine(w.out, rec)
+	} else {
+		target := w.uri
+		if remoteio.IsGCSURI(w.uri) && info.Generation > 0 && event != watchDeleted {
+			target = remoteio.GCSGenerationURI(w.uri, info.Generation)
+		}
+		fmt.Fprintf(w.out, "%s %s %s (%s)\n", now.Format(time.RFC3339), event, target, formatByteSize(info.Size))
+	}
+	if w.exec == "" {
+		return nil
+	}
+	c := shellCommand(w.ctx, w.exec)
+	c.Stdout, c.Stderr = w.out, w.errOut
+	c.Env = append(os.Environ(),
+		"REMOTEIO_EVENT="+event,
+		"REMOTEIO_URI="+w.uri,
+		"REMOTEIO_GENERATION="+strconv.FormatInt(info.Generation, 10),
+		"REMOTEIO_SIZE="+strconv.FormatInt(info.Size, 10),
+	)
+	if err := c.Run(); err != nil {
+		return fmt.Errorf(tr("コマンドの実行に失敗しました")+" (%s): %w", w.exec, err)
+	}
+	return nil
+}
+
+// shellCommand は、command をシェル (Windows では cmd.exe、それ以外では sh) で実行するコマンドを返します。
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/shouni/go-remote-io/pkg/transfer"
+	"github.com/spf13/cobra"
+)
+
+// rwatchUploadFlags は rwatch-upload コマンド固有のフラグを保持します。
+type rwatchUploadFlags struct {
+	Debounce      time.Duration // --debounce 最後の変更からアップロードするまでの待ち時間
+	Include       []string      // --include アップロードするファイルのパターン
+	Exclude       []string      // --exclude アップロードしないファイルのパターン
+	Parallel      int           // --parallel 同時にアップロードするファイル数
+	Initial       bool          // --initial 監視を開始した時点で存在するファイルもアップロードする
+	SkipIdentical bool          // --skip-identical 書き込み先のサイズと CRC32C が一致するファイルをスキップ
+}
+
+// newRwatchUploadCmd は 'rwatch-upload' サブコマンドを生成します。
+func newRwatchUploadCmd() *cobra.Command {
+	var flags rwatchUploadFlags
+
+	rwatchUploadCmd := &cobra.Command{
+		Use:   "rwatch-upload [directory] [destination]",
+		Short: "ローカルディレクトリを監視し、作成・変更されたファイルを自動的にアップロードします。",
+		Long: `ローカルディレクトリ配下 (サブディレクトリを含む) のファイルの作成・変更を OS のファイル変更通知で検知し、相対パスを保ったままコピー先 (GCS URI のプレフィックスなど) へアップロードします。
+書き込み中のファイルを何度もアップロードしないよう、最後の変更から --debounce の間変更がなかったファイルをまとめて、--parallel で指定した数まで同時にアップロードします (失敗したファイルは再試行します)。
+--include / --exclude でアップロードするファイルを絞り込めます (パターンに / を含まない場合はファイル名と、含む場合はディレクトリからの相対パスと照合します)。
+--initial を指定すると、監視を開始した時点で存在するファイルもアップロードします。Ctrl+C で終了します。`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRwatchUpload(cmd, args, &flags)
+		},
+	}
+
+	rwatchUploadCmd.Flags().DurationVar(&flags.Debounce, "debounce", 2*time.Second, "ファイルの最後の変更からアップロードするまでの待ち時間 (この間に変更されるとさらに待つ)")
+	rwatchUploadCmd.Flags().StringArrayVar(&flags.Include, "include", nil, "アップロードするファイルのパターン (例: '*.csv'。複数指定可。省略時はすべてのファイル)")
+	rwatchUploadCmd.Flags().StringArrayVar(&flags.Exclude, "exclude", nil, "アップロードしないファイルやディレクトリのパターン (例: '*.tmp'。複数指定可。--include より優先)")
+	rwatchUploadCmd.Flags().IntVar(&flags.Parallel, "parallel", transfer.DefaultParallelism, "同時にアップロードするファイル数")
+	rwatchUploadCmd.Flags().BoolVar(&flags.Initial, "initial", false, "監視を開始した時点で存在するファイルもアップロード")
+	rwatchUploadCmd.Flags().BoolVar(&flags.SkipIdentical, "skip-identical", false, "書き込み先のサイズと CRC32C チェックサムが一致するファイルはアップロードしない")
+
+	return rwatchUploadCmd
+}
+
+// runRwatchUpload は rwatch-upload コマンドの実行ロジックです。
+func runRwatchUpload(cmd *cobra.Command, args []string, flags *rwatchUploadFlags) error {
+	ctx := cmd.Context()
+	dir, dstPath := args[0], args[1]
+	if flags.Debounce <= 0 {
+		return usageError(fmt.Errorf(tr("--debounce には正の時間を指定してください: %s"), flags.Debounce))
+	}
+	filter, err := newPathFilter(flags.Include, flags.Exclude)
+	if err != nil {
+		return usageError(err)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return usageError(fmt.Errorf(tr("監視するローカルディレクトリを指定してください: %s"), dir))
+	}
+
+	// 1. ClientFactory とリーダー・ライターの取得 (DI)
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	inputReader, err := clientFactory.NewInputReader()
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+	writer, err := clientFactory.NewOutputWriter()
+	if err != nil {
+		return fmt.Errorf(tr("OutputWriterの作成に失敗しました")+": %w", err)
+	}
+
+	// 2. ディレクトリ配下のすべてのディレクトリの監視を開始する
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf(tr("ディレクトリの監視の開始に失敗しました")+": %w", err)
+	}
+	defer fsw.Close()
+	opts := transferOptions{skipIdentical: flags.SkipIdentical, results: newTextResultWriter(cmd, inputReader)}
+	u := &dirUploader{
+		root:     dir,
+		dst:      dstPath,
+		fsw:      fsw,
+		filter:   filter,
+		debounce: flags.Debounce,
+		pending:  make(map[string]time.Time),
+		upload: func(ctx context.Context, jobs []transfer.Job) error {
+			return runTransfers(ctx, inputReader, writer, jobs, flags.Parallel, opts, nil)
+		},
+	}
+	if err := u.addTree(dir, flags.Initial); err != nil {
+		return err
+	}
+	logger().Info(tr("ディレクトリの監視開始"), slog.String("directory", dir), slog.String("destination", dstPath), slog.Duration("debounce", flags.Debounce))
+
+	// 3. 変更されたファイルをアップロードし続ける
+	return u.run(ctx)
+}
+
+// dirUploader は、rwatch-upload がローカルディレクトリを監視し、変更されたファイルをアップロードするための状態です。
+type dirUploader struct {
+	root     string // 監視するディレクトリ
+	dst      string // アップロード先のプレフィックス
+	fsw      *fsnotify.Watcher
+	filter   pathFilter
+	debounce time.Duration
+	upload   func(ctx context.Context, jobs []transfer.Job) error
+
+	pending map[string]time.Time // 変更を検知したファイルと、最後に変更を検知した日時
+}
+
+// run は、ctx がキャンセルされるまで、ファイルの変更を検知し、--debounce の間変更がなかったファイルをアップロードし続けます。
+// アップロードしている間に検知した変更は、アップロードが終わってから次にまとめてアップロードします。
+func (u *dirUploader) run(ctx context.Context) error {
+	ticker := time.NewTicker(max(u.debounce/4, 10*time.Millisecond))
+	defer ticker.Stop()
+	var done chan error // アップロード中の場合は、その結果を受け取るチャネル
+	for {
+		select {
+		case <-ctx.Done():
+			if done != nil {
+				<-done
+			}
+			return ctx.Err()
+		case ev, ok := <-u.fsw.Events:
+			if !ok {
+				return nil
+			}
+			u.handle(ev)
+		case err, ok := <-u.fsw.Errors:
+			if !ok {
+				return nil
+			}
+			// 通知のあふれ (fsnotify.ErrEventOverflow) などは、以降の変更で再度検知できるため監視を続ける
+			logger().Warn(tr("ディレクトリの監視でエラーが発生しました"), slog.String("directory", u.root), slog.String("error", err.Error()))
+		case err := <-done:
+			done = nil
+			if err != nil {
+				// 失敗したファイルは、次に変更されたときに再度アップロードする
+				logger().Warn(tr("アップロードに失敗したファイルがあります"), slog.String("error", err.Error()))
+			}
+		case now := <-ticker.C:
+			if done != nil {
+				continue
+			}
+			jobs := u.due(now)
+			if len(jobs) == 0 {
+				continue
+			}
+			done = make(chan error, 1)
+			go func() {
+				done <- u.upload(ctx, jobs)
+			}()
+		}
+	}
+}
+
+// handle は、ファイルの変更の通知 ev を処理します。
+// 作成されたディレクトリは監視を開始し、配下に既に存在するファイル (移動してきたディレクトリなど) もアップロードします。
+func (u *dirUploader) handle(ev fsnotify.Event) {
+	rel, ok := u.rel(ev.Name)
+	if !ok {
+		return
+	}
+	if ev.Has(fsnotify.Remove) || ev.Has(fsnotify.Rename) {
+		delete(u.pending, ev.Name)
+		return
+	}
+	if !ev.Has(fsnotify.Create) && !ev.Has(fsnotify.Write) {
+		return
+	}
+	info, err := os.Lstat(ev.Name)
+	if err != nil {
+		// 検知した後に削除されたファイルは無視する
+		return
+	}
+	switch {
+	case info.IsDir() && ev.Has(fsnotify.Create):
+		if u.filter.excluded(rel) {
+			return
+		}
+		if err := u.addTree(ev.Name, true); err != nil {
+			logger().Warn(tr("ディレクトリの監視でエラーが発生しました"), slog.String("directory", ev.Name), slog.String("error", err.Error()))
+		}
+	case info.Mode().IsRegular() && u.filter.match(rel):
+		u.pending[ev.Name] = time.Now()
+	}
+}
+
+// addTree は、dir とその配下のすべてのディレクトリの監視を開始します。
+// queue が true の場合は、配下に存在するファイルをアップロードの対象に加えます。--exclude に一致するディレクトリは監視しません。
+func (u *dirUploader) addTree(dir string, queue bool) error {
+	now := time.Now()
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && p != u.root {
+				// 一覧の取得中に削除されたファイルやディレクトリは無視する
+				return nil
+			}
+			return fmt.Errorf(tr("ディレクトリの監視の開始に失敗しました (%s)")+": %w", p, err)
+		}
+		rel, _ := u.rel(p)
+		if d.IsDir() {
+			if p != u.root && u.filter.excluded(rel) {
+				return filepath.SkipDir
+			}
+			if err := u.fsw.Add(p); err != nil {
+				return fmt.Errorf(tr("ディレクトリの監視の開始に失敗しました (%s)")+": %w", p, err)
+			}
+			return nil
+		}
+		if queue && d.Type().IsRegular() && u.filter.match(rel) {
+			u.pending[p] = now
+		}
+		return nil
+	})
+}
+
+// due は、最後に変更を検知してから --debounce 以上経過したファイルを、アップロードの対象から取り出して転送にします。
+func (u *dirUploader) due(now time.Time) []transfer.Job {
+	var jobs []transfer.Job
+	for p, changed := range u.pending {
+		if now.Sub(changed) < u.debounce {
+			continue
+		}
+		delete(u.pending, p)
+		rel, _ := u.rel(p)
+		jobs = append(jobs, transfer.Job{Source: p, Destination: remoteio.JoinURI(u.dst, rel)})
+	}
+	slices.SortFunc(jobs, func(a, b transfer.Job) int { return strings.Compare(a.Source, b.Source) })
+	return jobs
+}
+
+// rel は、監視するディレクトリからの p の相対パスを、/ 区切りで返します。ディレクトリの外のパスの場合は ok が false になります。
+func (u *dirUploader) rel(p string) (string, bool) {
+	rel, err := filepath.Rel(u.root, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// pathFilter は、--include / --exclude のパターンで、ディレクトリからの相対パスを絞り込みます。
+// / を含まないパターンはファイル名 (最後の要素) と、含むパターンは相対パス全体と path.Match で照合します。
+type pathFilter struct {
+	include []string // 空の場合は、すべてのパスを対象とする
+	exclude []string
+}
+
+// newPathFilter は、パターンの書式を検証して pathFilter を作成します。
+func newPathFilter(include, exclude []string) (pathFilter, error) {
+	for _, pattern := range slices.Concat(include, exclude) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return pathFilter{}, fmt.Errorf(tr("パターンの書式が正しくありません: %s"), pattern)
+		}
+	}
+	return pathFilter{include: include, exclude: exclude}, nil
+}
+
+// match は、相対パス rel のファイルが --include のいずれかに一致し、--exclude のいずれにも一致しないかどうかを判定します。
+func (f pathFilter) match(rel string) bool {
+	if f.excluded(rel) {
+		return false
+	}
+	return len(f.include) == 0 || matchAny(f.include, rel)
+}
+
+// excluded は、相対パス rel が --exclude のいずれかに一致するかどうかを判定します。
+func (f pathFilter) excluded(rel string) bool {
+	return matchAny(f.exclude, rel)
+}
+
+// matchAny は、相対パス rel が patterns のいずれかに一致するかどうかを判定します。
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		name := rel
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(rel)
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
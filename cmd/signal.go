@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// signalError は、シグナルを受信してコマンドを中断したことを表す、コンテキストのキャンセルの理由です。
+type signalError struct {
+	sig os.Signal
+}
+
+func (e *signalError) Error() string {
+	return fmt.Sprintf(tr("シグナル (%s) を受信したため中断しました"), e.sig)
+}
+
+// exitCode は、シェルの慣例に従い 128 + シグナル番号 (SIGINT は 130、SIGTERM は 143) を返します。
+func (e *signalError) exitCode() int {
+	if s, ok := e.sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 1
+}
+
+// notifySignals は、SIGINT または SIGTERM を受信した場合に *signalError を理由としてキャンセルされるコンテキストを返します。
+// キャンセルされたコマンドは、実行中の GCS への書き込みを確定せずに中止し、書き込み途中のローカルファイルを削除してから終了します。
+// 後始末が終わらない場合に備えて、2回目のシグナルでは既定の動作 (即時終了) に戻します。
+func notifySignals(parent context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(parent)
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-sigCh:
+			signal.Stop(sigCh)
+			cancel(&signalError{sig: sig})
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(sigCh)
+		cancel(nil)
+	}
+}
+
+// interruptedBy は、ctx がシグナルによってキャンセルされた場合に、その理由を返します。
+func interruptedBy(ctx context.Context) (*signalError, bool) {
+	var sigErr *signalError
+	ok := errors.As(context.Cause(ctx), &sigErr)
+	return sigErr, ok
+}
+
+// silenceOnSignal は、c 配下のコマンドがシグナルで中断された場合に、cobra によるエラーと使い方の表示を抑止します。
+// 中断の理由は Execute が表示します。
+func silenceOnSignal(c *cobra.Command) {
+	if run := c.RunE; run != nil {
+		c.RunE = func(cmd *cobra.Command, args []string) error {
+			err := run(cmd, args)
+			if _, ok := interruptedBy(cmd.Context()); ok && err != nil {
+				cmd.SilenceErrors = true
+				cmd.SilenceUsage = true
+			}
+			return err
+		}
+	}
+	for _, sub := range c.Commands() {
+		silenceOnSignal(sub)
+	}
+}
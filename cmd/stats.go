@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shouni/go-remote-io/pkg/transfer"
+)
+
+// printStats は、--stats で指定された転送の集計 (ファイル数、失敗と再試行の回数、バイト数、所要時間とスループット) を表形式で出力します。
+func printStats(w io.Writer, stats *transfer.Stats) {
+	if stats == nil {
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "FILES\tSUCCEEDED\tFAILED\tRETRIES\tBYTES\tELAPSED\tMiB/s\t")
+	fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%s\t%s\t%.1f\t\n",
+		len(stats.Jobs), stats.Succeeded(), stats.Failed(), stats.Retries(),
+		formatByteSize(stats.Bytes()), stats.Elapsed.Round(time.Millisecond), stats.Throughput()/(1<<20))
+	tw.Flush()
+}
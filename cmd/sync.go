@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/shouni/go-remote-io/pkg/transfer"
+	"github.com/spf13/cobra"
+)
+
+// syncFlags は sync コマンド固有のフラグを保持します。
+type syncFlags struct {
+	Delete     bool      // --delete コピー元に存在しないファイルをコピー先から削除
+	AllowEmpty bool      // --allow-empty-source コピー元が空の場合も --delete でコピー先のファイルを削除
+	Parallel   int       // --parallel 同時に転送するファイル数
+	BufferSize string    // --buffer-size コピーに使用するバッファのサイズ
+	ChunkSize  string    // --chunk-size アップロードを分割して送信する単位
+	FileMode   string    // --file-mode 作成するローカルファイルのパーミッション
+	DirMode    string    // --dir-mode 作成するローカルディレクトリのパーミッション
+	Preserve   bool      // --preserve コピー元の更新日時をローカルファイルに設定
+	Fsync      bool      // --fsync ローカルファイルの書き込み完了前に fsync
+	KMSKey     string    // --kms-key アップロードしたオブジェクトの暗号化に使用する Cloud KMS の鍵
+	Stats      bool      // --stats 終了時に転送の集計を表示
+	Manifest   string    // --manifest 転送ごとのレコードを追記するファイル
+	Hooks      hookFlags // --on-success、--on-failure、--webhook 転送ごとに実行するフック
+}
+
+// syncSummary は、sync コマンドで処理したファイル数の集計です。
+type syncSummary struct {
+	Copied       int
+	Skipped      int
+	Deleted      int
+	HookFailures int64 // 失敗したフックの数 (フックを指定しない場合は 0)
+}
+
+// newSyncCmd は 'sync' サブコマンドを生成します。
+func newSyncCmd() *cobra.Command {
+	var flags syncFlags
+
+	syncCmd := &cobra.Command{
+		Use:   "sync [source] [destination]",
+		Short: "コピー先のディレクトリ/プレフィックスをコピー元と同じ内容にそろえます。",
+		Long: `コピー元 (ローカルディレクトリまたは GCS URI のプレフィックス) 配下のすべてのファイルを、相対パスを保ったままコピー先へコピーします。
+サイズと CRC32C チェックサムが一致するファイルは変更なしとみなしてスキップします。
+--delete を指定すると、コピー元に存在しないファイルをコピー先から削除します。コピー元が空の場合は、コピー先のすべてのファイルを削除しないよう、--allow-empty-source を指定しない限り失敗します。終了時にコピー・スキップ・削除の件数を表示します (--format json の場合は、ファイルごとの結果を出力します)。
+ファイルは --parallel で指定した数まで同時にコピーし、失敗したファイルは再試行します。
+--dry-run を指定すると、コピー・削除されるファイルとサイズを表示し、コピー先を変更せずに終了します。`,
+		Annotations: dryRunAnnotations(),
+		Args:        cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSync(cmd, args, &flags)
+		},
+	}
+
+	syncCmd.Flags().BoolVar(&flags.Delete, "delete", false, "コピー元に存在しないファイルをコピー先から削除")
+	syncCmd.Flags().BoolVar(&flags.AllowEmpty, "allow-empty-source", false, "コピー元が空の場合もコピー先のファイルを削除")
+	syncCmd.Flags().IntVar(&flags.Parallel, "parallel", transfer.DefaultParallelism, "同時に転送するファイル数")
+	syncCmd.Flags().StringVar(&flags.BufferSize, "buffer-size", "", "内容のコピーに使用するバッファのサイズ (例: 1MiB。省略時は 32KiB)")
+	syncCmd.Flags().StringVar(&flags.ChunkSize, "chunk-size", "", "アップロードを分割して送信する単位 (例: 8MiB。省略時は GCS: 16MiB、S3: 5MiB。GCS では 0 でバッファリングせずに送信)")
+	syncCmd.Flags().StringVar(&flags.FileMode, "file-mode", "", "作成するローカルファイルのパーミッション (8進数。例: 0640。省略時は 0666 から umask を除いた値)")
+	syncCmd.Flags().StringVar(&flags.DirMode, "dir-mode", "", "作成するローカルの出力ディレクトリのパーミッション (8進数。例: 0750。省略時は 0755)")
+	syncCmd.Flags().BoolVar(&flags.Preserve, "preserve", false, "ローカルファイルへのコピーで、コピー元の更新日時をファイルの更新日時 (mtime) に設定")
+	syncCmd.Flags().BoolVar(&flags.Fsync, "fsync", false, "ローカルファイルへの書き込みの完了前に、ファイルとその親ディレクトリを fsync (書き込み直後のクラッシュでも内容を失わないようにする)")
+	syncCmd.Flags().BoolVar(&flags.Stats, "stats", false, "終了時に、転送したファイル数、失敗と再試行の回数、バイト数、所要時間とスループットの集計を標準エラー出力へ表示")
+	syncCmd.Flags().StringVar(&flags.Manifest, "manifest", "", "転送・削除したファイルごとに、コピー元、コピー先、サイズ、CRC32C、日時と結果を1行の JSON で追記するファイル (ローカルファイルまたは gs://)")
+	addHookFlags(syncCmd, &flags.Hooks)
+
+	syncCmd.Flags().StringVar(&flags.KMSKey, "kms-key", "", "コピー先の GCS オブジェクトを指定した Cloud KMS の鍵 (projects/P/locations/L/keyRings/R/cryptoKeys/K) で暗号化 (CMEK)")
+
+	markURIFlags(syncCmd, "manifest")
+	return syncCmd
+}
+
+// runSync は sync コマンドの実行ロジックです。
+func runSync(cmd *cobra.Command, args []string, flags *syncFlags) (err error) {
+	ctx := cmd.Context()
+	srcPath, dstPath := args[0], args[1]
+
+	// 1. ClientFactory とリーダー・ライターの取得 (DI)
+	clientFactory, err := GetFactoryFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	ioOpts, err := bufferOptions(flags.BufferSize, flags.ChunkSize)
+	if err != nil {
+		return err
+	}
+	localOpts, err := parseLocalFileOptions(flags.FileMode, flags.DirMode, flags.Fsync)
+	if err != nil {
+		return err
+	}
+	ioOpts = append(ioOpts, localOpts.options()...)
+	inputReader, err := clientFactory.NewInputReader(ioOpts...)
+	if err != nil {
+		return fmt.Errorf(tr("InputReaderの作成に失敗しました")+": %w", err)
+	}
+	kmsOpts, err := kmsKeyOptions(flags.KMSKey, dstPath)
+	if err != nil {
+		return err
+	}
+	writer, err := clientFactory.NewOutputWriter(append(ioOpts, kmsOpts...)...)
+	if err != nil {
+		return fmt.Errorf(tr("OutputWriterの作成に失敗しました")+": %w", err)
+	}
+	lister, ok := inputReader.(remoteio.ObjectLister)
+	if !ok {
+		return errors.New(tr("InputReaderが一覧の取得をサポートしていません"))
+	}
+	var deleter remoteio.Deleter
+	if flags.Delete {
+		if deleter, ok = writer.(remoteio.Deleter); !ok {
+			return errors.New(tr("OutputWriterが削除をサポートしていません"))
+		}
+	}
+
+	// 2. コピー元とコピー先の一覧を取得する (コピー先のディレクトリが存在しない場合は空とみなす)
+	srcObjects, err := lister.ListObjects(ctx, srcPath)
+	if err != nil {
+		return fmt.Errorf(tr("コピー元の一覧取得に失敗しました (%s)")+": %w", srcPath, err)
+	}
+	// コピー元のパスの誤りやマウントの失敗で一覧が空になった場合に、コピー先をすべて削除しないようにする
+	if flags.Delete && len(srcObjects) == 0 && !flags.AllowEmpty {
+		return usageError(fmt.Errorf(tr("コピー元が空のため --delete を中止しました (%s)")+": "+tr("すべて削除するには --allow-empty-source を指定してください"), srcPath))
+	}
+	dstObjects, err := lister.ListObjects(ctx, dstPath)
+	if err != nil && !errors.Is(err, remoteio.ErrNotFound) {
+		return fmt.Errorf(tr("コピー先の一覧取得に失敗しました (%s)")+": %w", dstPath, err)
+	}
+	existing := make(map[string]remoteio.ObjectInfo, len(dstObjects))
+	for _, obj := range dstObjects {
+		existing[obj.Name] = obj
+	}
+
+	logger().Info(tr("同期開始"),
+		slog.String("source", srcPath),
+		slog.String("destination", dstPath),
+		slog.Int("files", len(srcObjects)),
+	)
+
+	if appFlagsFrom(cmd.Context()).DryRun {
+		return planSync(cmd, srcObjects, dstObjects, existing, dstPath, flags.Delete)
+	}
+
+	// 3. 変更のあったファイルのみを並行してコピーする
+	results, err := newResultWriter(cmd, inputReader).withManifest(clientFactory, inputReader, flags.Manifest)
+	if err != nil {
+		return err
+	}
+	defer results.closeManifest(ctx, &err)
+	if results, err = results.withHooks(cmd, inputReader, flags.Hooks); err != nil {
+		return err
+	}
+	defer results.summarizeHooks()
+	var summary syncSummary
+	var jobs []transfer.Job
+	for _, obj := range srcObjects {
+		dst, found := existing[obj.Name]
+		delete(existing, obj.Name)
+		if found {
+			same, err := sameObject(obj, dst)
+			if err != nil {
+				return err
+			}
+			if same {
+				summary.Skipped++
+				results.transferred(ctx, obj.URI, dst.URI, statusIdentical)
+				continue
+			}
+		}
+
+		jobs = append(jobs, transfer.Job{Source: obj.URI, Destination: remoteio.JoinURI(dstPath, obj.Name)})
+	}
+	// コピーに失敗したファイルがある場合は、コピー先の削除は行わない
+	opts := transferOptions{preserve: flags.Preserve, results: results}
+	if flags.Stats {
+		opts.stats = cmd.ErrOrStderr()
+	}
+	if err := runTransfers(ctx, inputReader, writer, jobs, flags.Parallel, opts, nil); err != nil {
+		return err
+	}
+	summary.Copied = len(jobs)
+
+	// 4. --delete が指定された場合は、コピー元に存在しないファイルを削除する
+	if deleter != nil {
+		for _, obj := range dstObjects {
+			if _, extraneous := existing[obj.Name]; !extraneous {
+				continue
+			}
+			err := deleter.Delete(ctx, obj.URI)
+			results.deleted(obj.URI, err)
+			if err != nil {
+				return fmt.Errorf(tr("コピー先のファイルの削除に失敗しました (%s)")+": %w", obj.URI, err)
+			}
+			summary.Deleted++
+		}
+	}
+
+	summary.HookFailures = results.hookFailures()
+	logger().Info(tr("同期完了"),
+		slog.Int("copied", summary.Copied),
+		slog.Int("skipped", summary.Skipped),
+		slog.Int("deleted", summary.Deleted),
+		slog.Int64("hook_failures", summary.HookFailures),
+	)
+	if !jsonOutput(cmd) {
+		line := trf("コピー: %d, スキップ: %d, 削除: %d", summary.Copied, summary.Skipped, summary.Deleted)
+		if summary.HookFailures > 0 {
+			line += trf(", フックの失敗: %d", summary.HookFailures)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), line)
+	}
+	return nil
+}
+
+// planSync は、--dry-run で sync がコピー・削除するファイルを表示します。コピー先は変更しません。
+// existing は、コピー先の一覧をコピー先からの相対パスで引くマップです (呼び出し後は変更されます)。
+func planSync(cmd *cobra.Command, srcObjects, dstObjects []remoteio.ObjectInfo, existing map[string]remoteio.ObjectInfo, dstPath string, deleteExtraneous bool) error {
+	plan := newDryRunPlan(cmd)
+	for _, obj := range srcObjects {
+		dst, found := existing[obj.Name]
+		delete(existing, obj.Name)
+		if found {
+			same, err := sameObject(obj, dst)
+			if err != nil {
+				return err
+			}
+			if same {
+				plan.skip(obj.URI, dst.URI, statusIdentical)
+				continue
+			}
+		}
+		plan.transfer(obj.URI, remoteio.JoinURI(dstPath, obj.Name), obj.Size)
+	}
+	if deleteExtraneous {
+		for _, obj := range dstObjects {
+			if _, extraneous := existing[obj.Name]; extraneous {
+				plan.remove(obj.URI, obj.Size)
+			}
+		}
+	}
+	plan.summary()
+	return nil
+}
+
+// sameObject は、src と dst のサイズと CRC32C チェックサムが一致するかどうかを判定します。
+// どちらかのチェックサムが取得できない場合は、変更ありとみなします。
+func sameObject(src, dst remoteio.ObjectInfo) (bool, error) {
+	if src.Size != dst.Size {
+		return false, nil
+	}
+	srcCRC, ok, err := objectCRC32C(src)
+	if err != nil || !ok {
+		return false, err
+	}
+	dstCRC, ok, err := objectCRC32C(dst)
+	if err != nil || !ok {
+		return false, err
+	}
+	return srcCRC == dstCRC, nil
+}
+
+// objectCRC32C は、obj の CRC32C チェックサムを返します。
+// 一覧にチェックサムが含まれないローカルファイルは内容から計算し、それ以外で取得できない場合は ok が false になります。
+func objectCRC32C(obj remoteio.ObjectInfo) (sum uint32, ok bool, err error) {
+	if obj.CRC32C != nil {
+		return *obj.CRC32C, true, nil
+	}
+	if remoteio.SchemeOf(obj.URI) != "" {
+		return 0, false, nil
+	}
+
+	file, err := os.Open(obj.URI)
+	if err != nil {
+		return 0, false, fmt.Errorf(tr("チェックサムの計算に失敗しました (%s)")+": %w", obj.URI, err)
+	}
+	defer file.Close()
+
+	h := remoteio.NewCRC32C()
+	if _, err := io.Copy(h, file); err != nil {
+		return 0, false, fmt.Errorf(tr("チェックサムの計算に失敗しました (%s)")+": %w", obj.URI, err)
+	}
+	return h.Sum32(), true, nil
+}
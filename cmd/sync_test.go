@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shouni/go-remote-io/pkg/factory"
+)
+
+func TestSyncDeleteWithEmptySource(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantCode    int
+		wantDeleted bool
+	}{
+		{name: "--delete", args: []string{"--delete"}, wantCode: ExitUsage},
+		{name: "--allow-empty-source", args: []string{"--delete", "--allow-empty-source"}, wantCode: ExitOK, wantDeleted: true},
+		{name: "--delete なし", wantCode: ExitOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, dst := t.TempDir(), t.TempDir()
+			kept := filepath.Join(dst, "a.txt")
+			if err := os.WriteFile(kept, []byte("a"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			f, err := factory.NewClientFactory(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			rootCmd := NewRootCmd(f)
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+			rootCmd.SetArgs(append([]string{"--quiet", "sync", src, dst}, tt.args...))
+
+			err = rootCmd.Execute()
+			if got := ExitCode(err); got != tt.wantCode {
+				t.Fatalf("ExitCode() = %d, want %d (err: %v)", got, tt.wantCode, err)
+			}
+			if _, err := os.Stat(kept); os.IsNotExist(err) != tt.wantDeleted {
+				t.Errorf("コピー先のファイルの削除 = %v, want %v", os.IsNotExist(err), tt.wantDeleted)
+			}
+		})
+	}
+}
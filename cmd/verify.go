@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// sourceVerification は、--verify でコピー元から読み込んだ内容を、コピー元の属性と比較するための状態です。
+type sourceVerification struct {
+	src        string
+	info       remoteio.ObjectInfo
+	reader     *remoteio.ChecksumReader
+	transcoded bool // コピー元が GCS で展開されて読み込まれ、保存されている内容のチェックサムと比較できない場合は true
+}
+
+// startVerification は、--verify が指定された場合にコピー元 src の属性を取得し、r をチェックサムを計算するリーダーで包みます。
+// 指定されていない場合は、nil と r をそのまま返します。
+func (o transferOptions) startVerification(ctx context.Context, reader remoteio.InputReader, src string, r io.Reader) (*sourceVerification, io.Reader, error) {
+	if !o.verify {
+		return nil, r, nil
+	}
+	stater, ok := reader.(remoteio.Stater)
+	if !ok {
+		return nil, nil, errors.New(tr("InputReaderが情報の取得をサポートしていません"))
+	}
+	info, err := stater.Stat(ctx, src)
+	if err != nil {
+		return nil, nil, fmt.Errorf(tr("コピー元の情報の取得に失敗しました (%s)")+": %w", src, err)
+	}
+	v := &sourceVerification{
+		src:        src,
+		info:       info,
+		reader:     remoteio.NewChecksumReader(r, o.verifyMD5),
+		transcoded: info.ContentEncoding == "gzip" && remoteio.IsGCSURI(src) && !o.opensCompressed(src),
+	}
+	return v, v.reader, nil
+}
+
+// verifyOptions は、--verify が指定された場合に、書き込む内容を検証する書き込みオプションを返します。
+func (o transferOptions) verifyOptions() []remoteio.WriteOption {
+	if !o.verify {
+		return nil
+	}
+	return []remoteio.WriteOption{remoteio.WithVerify(nil, o.verifyMD5)}
+}
+
+// finish は、読み込んだ内容のチェックサムをコピー元の属性と比較します。一致しない場合は、書き込んだ dst を削除します。
+// v が nil の場合は何もしません。
+func (v *sourceVerification) finish(ctx context.Context, writer remoteio.OutputWriter, dst string) error {
+	if v == nil {
+		return nil
+	}
+	sums := v.reader.Checksums()
+	if v.transcoded {
+		logger().Warn(tr("コピー元は展開されて読み込まれたため、チェックサムを検証できません (--raw を指定すると検証できます)"), slog.String("source", v.src))
+		return nil
+	}
+	if err := sums.Verify(v.info); err != nil {
+		if deleter, ok := writer.(remoteio.Deleter); ok && dst != "" {
+			if rmErr := deleter.Delete(context.WithoutCancel(ctx), dst); rmErr != nil {
+				logger().Warn(tr("書き込み先の削除に失敗しました"), slog.String("destination", dst), slog.String("error", rmErr.Error()))
+			}
+		}
+		return fmt.Errorf(tr("コピー元の内容の検証に失敗しました (%s)")+": %w", v.src, err)
+	}
+	attrs := []any{slog.String("source", v.src), slog.Int64("bytes", sums.Size), slog.String("crc32c", sums.CRC32CBase64())}
+	if sums.MD5 != nil {
+		attrs = append(attrs, slog.String("md5", base64.StdEncoding.EncodeToString(sums.MD5)))
+	}
+	logger().Info(tr("コピー元のチェックサムを検証しました"), attrs...)
+	return nil
+}
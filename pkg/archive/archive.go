@@ -0,0 +1,100 @@
+// Package archive は、ディレクトリやプレフィックス配下のファイルを tar (.tar / .tar.gz) または zip の1つのアーカイブへ書き出し、
+// アーカイブをディレクトリやプレフィックスへ展開します。
+// いずれも remoteio の InputReader / OutputWriter でストリーミングしながら読み書きし、一時ファイルを使用しません。
+package archive
+
+import (
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"strings"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// Format は、アーカイブの形式です。
+type Format string
+
+// アーカイブの形式
+const (
+	FormatTar   Format = "tar"    // 非圧縮の tar
+	FormatTarGz Format = "tar.gz" // gzip で圧縮した tar
+	FormatZip   Format = "zip"    // zip (Deflate で圧縮)
+)
+
+// ParseFormat は、形式名 (tar、tar.gz、tgz、zip) を Format に変換します。
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "tar":
+		return FormatTar, nil
+	case "tar.gz", "tgz":
+		return FormatTarGz, nil
+	case "zip":
+		return FormatZip, nil
+	default:
+		return "", fmt.Errorf(remoteio.Message("アーカイブの形式には tar、tar.gz または zip を指定してください: %s"), s)
+	}
+}
+
+// FormatOf は、name の拡張子 (.tar、.tar.gz、.tgz、.zip) からアーカイブの形式を判定します。
+func FormatOf(name string) (Format, bool) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar"):
+		return FormatTar, true
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return FormatTarGz, true
+	case strings.HasSuffix(lower, ".zip"):
+		return FormatZip, true
+	default:
+		return "", false
+	}
+}
+
+// Entry は、アーカイブに追加した、またはアーカイブから展開したファイルです。
+type Entry struct {
+	Name string // アーカイブ内のパス ("/" 区切り)
+	URI  string // 追加元または展開先のURI (ローカルファイルの場合はパス)
+	Size int64  // サイズ (バイト数)
+}
+
+// Option は、Create と Extract の動作を変更する関数型オプションです。
+type Option func(*options)
+
+type options struct {
+	onEntry func(Entry)
+	logger  *slog.Logger
+}
+
+// WithOnEntry は、ファイルをアーカイブに追加するたび、またはアーカイブから展開するたびに fn を呼び出します。
+func WithOnEntry(fn func(Entry)) Option {
+	return func(o *options) {
+		o.onEntry = fn
+	}
+}
+
+// WithLogger は、展開しなかったエントリ (シンボリックリンクなど) を警告するロガーを設定します (既定は slog.Default())。
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{onEntry: func(Entry) {}, logger: slog.Default()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// entryName は、アーカイブ内のパス name を検証し、正規化した "/" 区切りの相対パスを返します。
+// 展開先の外へ書き込まないよう、絶対パスや ".." を含むパスはエラーにします。
+func entryName(name string) (string, error) {
+	clean := path.Clean(strings.ReplaceAll(name, `\`, "/"))
+	if strings.HasPrefix(clean, "/") || !fs.ValidPath(clean) || clean == "." {
+		return "", fmt.Errorf(remoteio.Message("アーカイブに展開先の外を指すパスが含まれています: %s"), name)
+	}
+	return clean, nil
+}
@@ -0,0 +1,143 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// Create は、srcURI (ローカルディレクトリ、または GCS などのプレフィックス) 配下のすべてのファイルを名前順に読み込み、
+// format のアーカイブとして w へ書き出します。アーカイブ内のパスは srcURI からの相対パスです。
+// reader は remoteio.ObjectLister を満たす必要があります。
+// w が途中で失敗した場合や ctx がキャンセルされた場合は、書き出しを中断してエラーを返します (アーカイブは不完全なままです)。
+func Create(ctx context.Context, w io.Writer, format Format, reader remoteio.InputReader, srcURI string, opts ...Option) error {
+	o := newOptions(opts)
+	lister, ok := reader.(remoteio.ObjectLister)
+	if !ok {
+		return errors.New(remoteio.Message("InputReaderが一覧の取得をサポートしていません"))
+	}
+	objects, err := lister.ListObjects(ctx, srcURI)
+	if err != nil {
+		return fmt.Errorf(remoteio.Message("アーカイブするファイルの一覧の取得に失敗しました (%s): %w"), srcURI, err)
+	}
+	if len(objects) == 0 {
+		return fmt.Errorf(remoteio.Message("アーカイブするファイルが見つかりません (%s): %w"), srcURI, remoteio.ErrNotFound)
+	}
+
+	aw, err := newArchiveWriter(w, format)
+	if err != nil {
+		return err
+	}
+	for _, obj := range objects {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		name, err := entryName(obj.Name)
+		if err != nil {
+			return err
+		}
+		if err := addFile(ctx, aw, reader, name, obj); err != nil {
+			return err
+		}
+		o.onEntry(Entry{Name: name, URI: obj.URI, Size: obj.Size})
+	}
+	if err := aw.Close(); err != nil {
+		return fmt.Errorf(remoteio.Message("アーカイブの書き出しに失敗しました: %w"), err)
+	}
+	return nil
+}
+
+// addFile は、obj の内容をアーカイブのエントリ name として書き出します。
+func addFile(ctx context.Context, aw archiveWriter, reader remoteio.InputReader, name string, obj remoteio.ObjectInfo) error {
+	rc, err := reader.Open(ctx, obj.URI)
+	if err != nil {
+		return fmt.Errorf(remoteio.Message("アーカイブするファイルのオープンに失敗しました (%s): %w"), obj.URI, err)
+	}
+	defer rc.Close()
+	ew, err := aw.Create(name, obj)
+	if err != nil {
+		return fmt.Errorf(remoteio.Message("アーカイブの書き出しに失敗しました (%s): %w"), name, err)
+	}
+	n, err := io.Copy(ew, rc)
+	if err != nil {
+		return fmt.Errorf(remoteio.Message("アーカイブへのファイルの追加に失敗しました (%s): %w"), obj.URI, err)
+	}
+	if n != obj.Size {
+		// tar はエントリのサイズを先に書き出すため、一覧の後に変更されたファイルは追加できない
+		return fmt.Errorf(remoteio.Message("アーカイブ中にファイルのサイズが変わりました (%s): 一覧 %d バイト、読み込み %d バイト"), obj.URI, obj.Size, n)
+	}
+	return nil
+}
+
+// archiveWriter は、tar と zip の書き出しの共通のインターフェースです。
+type archiveWriter interface {
+	// Create は、obj の情報でエントリ name を開始し、内容を書き込む io.Writer を返します。
+	Create(name string, obj remoteio.ObjectInfo) (io.Writer, error)
+	// Close は、アーカイブの終端を書き出します。w は閉じません。
+	Close() error
+}
+
+// newArchiveWriter は、format のアーカイブを w へ書き出す archiveWriter を作成します。
+func newArchiveWriter(w io.Writer, format Format) (archiveWriter, error) {
+	switch format {
+	case FormatTar:
+		return &tarWriter{tw: tar.NewWriter(w)}, nil
+	case FormatTarGz:
+		zw := gzip.NewWriter(w)
+		return &tarWriter{tw: tar.NewWriter(zw), zw: zw}, nil
+	case FormatZip:
+		return &zipWriter{zw: zip.NewWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf(remoteio.Message("サポートされていないアーカイブの形式です: %s"), format)
+	}
+}
+
+// tarWriter は、tar (gzip で圧縮する場合を含む) の archiveWriter です。
+type tarWriter struct {
+	tw *tar.Writer
+	zw *gzip.Writer // 圧縮しない場合は nil
+}
+
+func (t *tarWriter) Create(name string, obj remoteio.ObjectInfo) (io.Writer, error) {
+	err := t.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     obj.Size,
+		Mode:     0o644,
+		ModTime:  obj.Updated,
+	})
+	return t.tw, err
+}
+
+func (t *tarWriter) Close() error {
+	if err := t.tw.Close(); err != nil {
+		return err
+	}
+	if t.zw != nil {
+		return t.zw.Close()
+	}
+	return nil
+}
+
+// zipWriter は、zip の archiveWriter です。書き出し先はシークできないため、サイズと CRC はデータの後に書き出します。
+type zipWriter struct {
+	zw *zip.Writer
+}
+
+func (z *zipWriter) Create(name string, obj remoteio.ObjectInfo) (io.Writer, error) {
+	return z.zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: obj.Updated,
+	})
+}
+
+func (z *zipWriter) Close() error {
+	return z.zw.Close()
+}
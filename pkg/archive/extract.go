@@ -0,0 +1,175 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// Extract は、r から tar (FormatTar / FormatTarGz) のアーカイブを先頭から順に読み込み、
+// 各ファイルを dstURI (ローカルディレクトリ、または GCS などのプレフィックス) 配下の同じ相対パスへ書き込みます。
+// ディレクトリのエントリは書き込まず、シンボリックリンクなどの通常のファイル以外のエントリは警告して読み飛ばします。
+// zip は末尾の目次から読み込む必要があるため、ExtractZip を使用してください。
+func Extract(ctx context.Context, r io.Reader, format Format, writer remoteio.OutputWriter, dstURI string, opts ...Option) error {
+	o := newOptions(opts)
+	switch format {
+	case FormatTar:
+	case FormatTarGz:
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return fmt.Errorf(remoteio.Message("アーカイブの gzip の展開に失敗しました: %w"), err)
+		}
+		defer zr.Close()
+		r = zr
+	case FormatZip:
+		return errors.New(remoteio.Message("zip のアーカイブは ExtractZip で展開してください"))
+	default:
+		return fmt.Errorf(remoteio.Message("サポートされていないアーカイブの形式です: %s"), format)
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf(remoteio.Message("アーカイブの読み込みに失敗しました: %w"), err)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeReg:
+		case tar.TypeDir:
+			continue
+		default:
+			o.logger.Warn(remoteio.Message("通常のファイル以外のエントリを読み飛ばします"), slog.String("name", hdr.Name), slog.String("type", string(hdr.Typeflag)))
+			continue
+		}
+		if err := extractFile(ctx, tr, hdr.Name, writer, dstURI, &o); err != nil {
+			return err
+		}
+	}
+}
+
+// ExtractZip は、ra (サイズ size) から zip のアーカイブを読み込み、
+// 各ファイルを dstURI (ローカルディレクトリ、または GCS などのプレフィックス) 配下の同じ相対パスへ書き込みます。
+// ra には remoteio.RangeInputReader.OpenReaderAt で開いたファイルを渡せます。目次と各ファイルの範囲だけを読み込みます。
+func ExtractZip(ctx context.Context, ra io.ReaderAt, size int64, writer remoteio.OutputWriter, dstURI string, opts ...Option) error {
+	o := newOptions(opts)
+	// zip.Reader は小さな単位で ReadAt を呼び出すため、リモートへの範囲読み込みが細切れにならないようブロック単位でまとめる
+	zr, err := zip.NewReader(&blockReaderAt{ra: ra, size: size}, size)
+	if err != nil {
+		return fmt.Errorf(remoteio.Message("アーカイブの読み込みに失敗しました: %w"), err)
+	}
+	for _, f := range zr.File {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		mode := f.Mode()
+		if mode.IsDir() {
+			continue
+		}
+		if !mode.IsRegular() {
+			o.logger.Warn(remoteio.Message("通常のファイル以外のエントリを読み飛ばします"), slog.String("name", f.Name), slog.String("type", mode.Type().String()))
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf(remoteio.Message("アーカイブの読み込みに失敗しました (%s): %w"), f.Name, err)
+		}
+		err = extractFile(ctx, rc, f.Name, writer, dstURI, &o)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractFile は、アーカイブのエントリ rawName の内容 r を dstURI 配下へ書き込みます。
+func extractFile(ctx context.Context, r io.Reader, rawName string, writer remoteio.OutputWriter, dstURI string, o *options) error {
+	name, err := entryName(rawName)
+	if err != nil {
+		return err
+	}
+	uri := remoteio.JoinURI(dstURI, name)
+	cr := &countingReader{r: r}
+	if err := writer.Write(ctx, uri, cr); err != nil {
+		return fmt.Errorf(remoteio.Message("アーカイブから展開したファイルの書き込みに失敗しました (%s): %w"), uri, err)
+	}
+	o.onEntry(Entry{Name: name, URI: uri, Size: cr.n})
+	return nil
+}
+
+// countingReader は、読み込んだバイト数を数える io.Reader です。
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// zipBlockSize は、blockReaderAt が1回の ReadAt で読み込む単位です。
+const zipBlockSize = 1 << 20
+
+// blockReaderAt は、直前に読み込んだブロックを保持し、同じブロック内の ReadAt をメモリから返す io.ReaderAt です。
+type blockReaderAt struct {
+	ra   io.ReaderAt
+	size int64
+
+	mu  sync.Mutex
+	off int64  // buf の先頭の位置
+	buf []byte // 直前に読み込んだブロック
+}
+
+func (b *blockReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) >= zipBlockSize {
+		return b.ra.ReadAt(p, off)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := 0
+	for n < len(p) {
+		pos := off + int64(n)
+		if pos >= b.size {
+			return n, io.EOF
+		}
+		if pos < b.off || pos >= b.off+int64(len(b.buf)) {
+			if err := b.fill(pos); err != nil {
+				return n, err
+			}
+		}
+		n += copy(p[n:], b.buf[pos-b.off:])
+	}
+	return n, nil
+}
+
+// fill は、pos を含むブロックを読み込みます。b.mu を保持して呼び出します。
+func (b *blockReaderAt) fill(pos int64) error {
+	start := pos - pos%zipBlockSize
+	length := min(int64(zipBlockSize), b.size-start)
+	if cap(b.buf) < int(length) {
+		b.buf = make([]byte, zipBlockSize)
+	}
+	buf := b.buf[:length]
+	if n, err := b.ra.ReadAt(buf, start); err != nil && !(errors.Is(err, io.EOF) && n == len(buf)) {
+		b.buf = b.buf[:0]
+		return err
+	}
+	b.off, b.buf = start, buf
+	return nil
+}
@@ -0,0 +1,119 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/shouni/go-remote-io/pkg/remoteiotest"
+)
+
+func TestEntryName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "a.txt", want: "a.txt"},
+		{name: "dir/sub/a.txt", want: "dir/sub/a.txt"},
+		{name: "./dir//a.txt", want: "dir/a.txt"},
+		{name: "dir/../a.txt", want: "a.txt"},
+		{name: `dir\a.txt`, want: "dir/a.txt"},
+		{name: "../a.txt", wantErr: true},
+		{name: "dir/../../a.txt", wantErr: true},
+		{name: `..\a.txt`, wantErr: true},
+		{name: "/etc/passwd", wantErr: true},
+		{name: `\etc\passwd`, wantErr: true},
+		{name: ".", wantErr: true},
+		{name: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := entryName(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("entryName(%q) = %q, %v, wantErr %v", tt.name, got, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("entryName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+// tarOf は、names のファイル (内容はそれぞれの名前) を含む tar のアーカイブを返します。
+func tarOf(t *testing.T, names ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range names {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(name)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// zipOf は、names のファイル (内容はそれぞれの名前) を含む zip のアーカイブを返します。
+func zipOf(t *testing.T, names ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range names {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractRejectsEscapingPaths(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    []string // 展開された URI
+		wantErr bool
+	}{
+		{name: "展開先の配下のみ", entries: []string{"a.txt", "dir/b.txt"}, want: []string{"gs://bucket/out/a.txt", "gs://bucket/out/dir/b.txt"}},
+		{name: "親ディレクトリへの参照", entries: []string{"a.txt", "../escape.txt"}, want: []string{"gs://bucket/out/a.txt"}, wantErr: true},
+		{name: "絶対パス", entries: []string{"/etc/cron.d/job"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		extractors := map[string]func(store *remoteiotest.Store) error{
+			"tar": func(store *remoteiotest.Store) error {
+				return Extract(context.Background(), bytes.NewReader(tarOf(t, tt.entries...)), FormatTar, remoteiotest.NewWriter(store), "gs://bucket/out/")
+			},
+			"zip": func(store *remoteiotest.Store) error {
+				data := zipOf(t, tt.entries...)
+				return ExtractZip(context.Background(), bytes.NewReader(data), int64(len(data)), remoteiotest.NewWriter(store), "gs://bucket/out/")
+			},
+		}
+		for format, extract := range extractors {
+			t.Run(tt.name+"/"+format, func(t *testing.T) {
+				store := remoteiotest.NewStore()
+				err := extract(store)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("展開のエラー = %v, wantErr %v", err, tt.wantErr)
+				}
+				if got := store.URIs(); !slices.Equal(got, tt.want) {
+					t.Errorf("展開された URI = %q, want %q", got, tt.want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Package cassette は、バックエンド (GCS) とのHTTPのやり取りをファイルに記録し、
+// オフラインで再生するための http.RoundTripper を提供します。
+// ネットワークや認証情報なしで、CLIの結合テストや下流のCIを決定的に実行するために使用します。
+package cassette
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// 記録・再生モードを指定する環境変数
+const (
+	// PathEnv は、カセットファイルのパスを指定する環境変数です。
+	PathEnv = "REMOTEIO_CASSETTE"
+	// ModeEnv は、カセットのモード (record または replay) を指定する環境変数です。
+	ModeEnv = "REMOTEIO_CASSETTE_MODE"
+)
+
+// カセットのモード
+const (
+	ModeRecord = "record" // 実際のバックエンドへ送信し、やり取りを記録する
+	ModeReplay = "replay" // 記録済みのやり取りを再生し、ネットワークへは送信しない
+)
+
+// volatileQueryParams は、実行ごとに値が変わるため照合時に無視するクエリパラメータです。
+var volatileQueryParams = []string{"upload_id"}
+
+// Interaction は、1回のHTTPリクエストとレスポンスの記録です。
+type Interaction struct {
+	Method             string      `json:"method"`
+	URL                string      `json:"url"`                  // ホストを除いたパスとクエリ
+	RequestBodySHA256  string      `json:"request_body_sha256"`  // 送信したペイロードのハッシュ (監査用)
+	Status             int         `json:"status"`               // レスポンスのステータスコード
+	ResponseHeader     http.Header `json:"response_header"`      // レスポンスヘッダー
+	ResponseBody       []byte      `json:"response_body"`        // レスポンスボディ (JSONではBase64)
+	ResponseBodySHA256 string      `json:"response_body_sha256"` // レスポンスボディのハッシュ
+}
+
+// Cassette は、記録されたやり取りの一覧です。
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Load は、path からカセットを読み込みます。
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(remoteio.Message("カセット(%s)の読み込みに失敗しました: %w"), path, err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf(remoteio.Message("カセット(%s)の解析に失敗しました: %w"), path, err)
+	}
+	return &c, nil
+}
+
+// Save は、カセットを path に書き込みます。
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf(remoteio.Message("カセットのエンコードに失敗しました: %w"), err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf(remoteio.Message("カセット(%s)の書き込みに失敗しました: %w"), path, err)
+	}
+	return nil
+}
+
+// =================================================================
+// 記録
+// =================================================================
+
+// Recorder は、Transport へ送信したリクエストとそのレスポンスを記録する http.RoundTripper です。
+// リクエスト・レスポンスのボディはメモリ上に読み込まれるため、テスト用途に限定してください。
+type Recorder struct {
+	Transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder は、transport へのやり取りを記録する Recorder を作成します。
+func NewRecorder(transport http.RoundTripper) *Recorder {
+	return &Recorder{Transport: transport}
+}
+
+// RoundTrip は、リクエストを Transport へ送信し、やり取りを記録します。
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, reqHash, err := hashRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf(remoteio.Message("記録するレスポンスボディの読み込みに失敗しました: %w"), err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Method:             req.Method,
+		URL:                requestKey(req.URL),
+		RequestBodySHA256:  reqHash,
+		Status:             resp.StatusCode,
+		ResponseHeader:     resp.Header.Clone(),
+		ResponseBody:       body,
+		ResponseBodySHA256: sha256Hex(body),
+	})
+	return resp, nil
+}
+
+// Save は、これまでに記録したやり取りを path に書き込みます。
+func (r *Recorder) Save(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cassette.Save(path)
+}
+
+// =================================================================
+// 再生
+// =================================================================
+
+// Replayer は、カセットに記録されたレスポンスを返す http.RoundTripper です。
+// 同じメソッドとURLのリクエストには、記録された順にレスポンスを返します。
+type Replayer struct {
+	mu        sync.Mutex
+	remaining map[string][]Interaction
+}
+
+// NewReplayer は、c を再生する Replayer を作成します。
+func NewReplayer(c *Cassette) *Replayer {
+	remaining := make(map[string][]Interaction)
+	for _, in := range c.Interactions {
+		key := in.Method + " " + in.URL
+		remaining[key] = append(remaining[key], in)
+	}
+	return &Replayer{remaining: remaining}
+}
+
+// RoundTrip は、リクエストに対応する記録済みのレスポンスを返します。
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+
+	key := req.Method + " " + requestKey(req.URL)
+	r.mu.Lock()
+	queue := r.remaining[key]
+	if len(queue) == 0 {
+		r.mu.Unlock()
+		return nil, fmt.Errorf(remoteio.Message("カセットに記録されていないリクエストです: %s"), key)
+	}
+	in := queue[0]
+	r.remaining[key] = queue[1:]
+	r.mu.Unlock()
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+		StatusCode:    in.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        in.ResponseHeader.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(in.ResponseBody)),
+		ContentLength: int64(len(in.ResponseBody)),
+		Request:       req,
+	}, nil
+}
+
+// =================================================================
+// 内部ヘルパー
+// =================================================================
+
+// requestKey は、ホストと揮発的なクエリパラメータを除いた照合用のURLを返します。
+func requestKey(u *url.URL) string {
+	query := u.Query()
+	for _, p := range volatileQueryParams {
+		query.Del(p)
+	}
+	key := u.EscapedPath()
+	if encoded := query.Encode(); encoded != "" {
+		key += "?" + encoded
+	}
+	return key
+}
+
+// hashRequestBody は、リクエストボディのSHA-256を計算し、ボディを再送信できるよう複製したリクエストを返します。
+func hashRequestBody(req *http.Request) (*http.Request, string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, sha256Hex(nil), nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, "", fmt.Errorf(remoteio.Message("記録するリクエストボディの読み込みに失敗しました: %w"), err)
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = io.NopCloser(bytes.NewReader(body))
+	return clone, sha256Hex(body), nil
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -2,9 +2,22 @@ package factory
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
 
 	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+
+	"github.com/shouni/go-remote-io/pkg/cassette"
 	"github.com/shouni/go-remote-io/pkg/remoteio"
 )
 
@@ -12,66 +25,294 @@ import (
 type Factory interface {
 	// Client はファクトリが保持するGCSクライアントを返します。
 	Client() (*storage.Client, error)
-	// NewInputReader は GCSクライアントを注入した InputReader を生成します。
-	NewInputReader() (remoteio.InputReader, error)
-	// NewOutputWriter は GCSクライアントを注入した OutputWriter を生成します。
-	// OutputWriter は GCSOutputWriter と LocalOutputWriter の両方を満たします。
-	NewOutputWriter() (remoteio.OutputWriter, error)
+	// S3Client はファクトリが保持するS3クライアントを返します。
+	S3Client() (*s3.Client, error)
+	// AzureClient はファクトリが保持するAzure Blob Storageクライアントを返します。
+	// 環境変数で Azure が構成されていない場合はエラーを返します。
+	AzureClient() (*azblob.Client, error)
+	// NewInputReader は GCSクライアントとS3クライアントを注入した InputReader を生成します。
+	// opts で追加の構成 (remoteio.Option) を指定できます。
+	NewInputReader(opts ...remoteio.Option) (remoteio.InputReader, error)
+	// NewOutputWriter は各バックエンドのクライアントを注入した OutputWriter を生成します。
+	// OutputWriter.Write は書き込み先のURIに応じて処理を振り分けるため、呼び出し元での型アサーションは不要です。
+	// opts でバリデータなどの追加の構成 (remoteio.Option) を指定できます。
+	NewOutputWriter(opts ...remoteio.Option) (remoteio.OutputWriter, error)
 	// Close は保持しているリソースを解放します。
 	Close() error
 }
 
 // ClientFactory は Factory インターフェースを実装し、GCSクライアントと関連するI/Oコンポーネントを管理します。
+// GCSクライアントは最初の GCS へのアクセス時 (または Client の呼び出し時) に一度だけ作成されるため、
+// ローカルファイルのみを扱う場合は GCP の認証情報がない環境でも動作します。
 type ClientFactory struct {
-	gcsClient *storage.Client
+	mu        sync.Mutex      // gcsClient、gcsErr、closed を保護する
+	ctx       context.Context // GCSクライアントの作成に使用するコンテキスト (キャンセルは引き継がない)
+	gcsClient *storage.Client // 作成前は nil
+	gcsErr    error           // GCSクライアントの作成に失敗した場合のエラー
+	closed    bool            // Close が呼び出された場合は true
+
+	s3Client    *s3.Client
+	azureClient *azblob.Client    // Azure が構成されていない場合は nil
+	ioOptions   []remoteio.Option // 生成する InputReader / OutputWriter に共通で適用するオプション
+
+	clientOptions []option.ClientOption // GCSクライアントの作成に使用するオプション (認証情報、スコープ、HTTPクライアントなど)
+	retry         []storage.RetryOption // nil の場合はGCSクライアントの既定の再試行の設定に従う
+
+	recorder     *cassette.Recorder // 記録モードの場合のレコーダー
+	cassettePath string             // 記録したやり取りを保存するパス
 }
 
 // NewClientFactory は新しい Factory インターフェースの実装である ClientFactory インスタンスを作成します。
-func NewClientFactory(ctx context.Context) (Factory, error) {
-	// クライアントの初期化はここで一度だけ行われます。
-	client, err := storage.NewClient(ctx)
+// opts でファクトリの構成 (Option) を指定できます。
+func NewClientFactory(ctx context.Context, opts ...Option) (Factory, error) {
+	// GCSクライアントは後から作成されるため、初期化用のコンテキストのキャンセルやタイムアウトは引き継がない
+	f := &ClientFactory{ctx: context.WithoutCancel(ctx)}
+
+	// 呼び出し元が指定したオプションを、クライアントの作成前に適用
+	for _, opt := range opts {
+		opt(f)
+	}
+	// 呼び出し元が指定した remoteio.Option は、環境変数から構成されたオプションより後に適用する
+	callerIOOptions := f.ioOptions
+	f.ioOptions = []remoteio.Option{remoteio.WithGCSClientFunc(f.Client)}
+
+	// テストやCI向けに、環境変数で記録・再生モードが指定されていればHTTPクライアントを差し替える
+	cassetteOpts, err := f.cassetteOptions(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("GCSクライアントの初期化に失敗しました: %w", err)
+		return nil, err
+	}
+	f.clientOptions = append(f.clientOptions, cassetteOpts...)
+
+	// S3クライアントの初期化 (認証情報は環境変数や共有設定ファイルから、リクエスト時に解決される)
+	s3Client, err := newS3Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	f.s3Client = s3Client
+	f.ioOptions = append(f.ioOptions, remoteio.WithS3Client(s3Client))
+
+	// Azure Blob Storageクライアントの初期化 (環境変数で構成されている場合のみ)
+	azureClient, err := newAzureClient()
+	if err != nil {
+		return nil, err
+	}
+	if azureClient != nil {
+		f.azureClient = azureClient
+		f.ioOptions = append(f.ioOptions, remoteio.WithAzureClient(azureClient))
+	}
+
+	// テスト用の故障注入が環境変数で指定されていれば有効にする
+	if spec := os.Getenv(remoteio.FaultInjectionEnv); spec != "" {
+		faults, err := remoteio.ParseFaultConfig(spec)
+		if err != nil {
+			return nil, fmt.Errorf(remoteio.Message("%s の解析に失敗しました: %w"), remoteio.FaultInjectionEnv, err)
+		}
+		f.ioOptions = append(f.ioOptions, remoteio.WithFaultInjection(faults))
 	}
+	f.ioOptions = append(f.ioOptions, callerIOOptions...)
 
 	// ファクトリ構造体に注入
-	return &ClientFactory{gcsClient: client}, nil
+	return f, nil
+}
+
+// S3PathStyleEnv は、S3 へのリクエストをパス形式 (https://endpoint/bucket/key) にする環境変数です。
+// MinIO などの S3 互換ストレージを AWS_ENDPOINT_URL_S3 と併せて使用する場合に "true" を指定します。
+const S3PathStyleEnv = "REMOTEIO_S3_PATH_STYLE"
+
+// newS3Client は、AWS の標準の設定 (環境変数、共有設定ファイル) からS3クライアントを作成します。
+func newS3Client(ctx context.Context) (*s3.Client, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(remoteio.Message("S3クライアントの設定の読み込みに失敗しました: %w"), err)
+	}
+	pathStyle := os.Getenv(S3PathStyleEnv) == "true"
+	return s3.NewFromConfig(cfg, func(o *s3.Options) {
+		o.UsePathStyle = pathStyle
+	}), nil
+}
+
+// Azure Blob Storage の接続情報を指定する環境変数
+const (
+	// AzureConnectionStringEnv は、接続文字列を指定する環境変数です (最優先)。
+	AzureConnectionStringEnv = "AZURE_STORAGE_CONNECTION_STRING"
+	// AzureAccountEnv は、ストレージアカウント名を指定する環境変数です。
+	AzureAccountEnv = "AZURE_STORAGE_ACCOUNT"
+	// AzureKeyEnv は、アカウントキーを指定する環境変数です。
+	// 省略した場合は DefaultAzureCredential (環境変数、マネージドID、Azure CLI など) で認証します。
+	AzureKeyEnv = "AZURE_STORAGE_KEY"
+)
+
+// newAzureClient は、環境変数からAzure Blob Storageクライアントを作成します。
+// いずれの環境変数も指定されていない場合は nil を返します。
+func newAzureClient() (*azblob.Client, error) {
+	if connStr := os.Getenv(AzureConnectionStringEnv); connStr != "" {
+		client, err := azblob.NewClientFromConnectionString(connStr, nil)
+		if err != nil {
+			return nil, fmt.Errorf(remoteio.Message("Azureクライアントの初期化に失敗しました (%s): %w"), AzureConnectionStringEnv, err)
+		}
+		return client, nil
+	}
+
+	account := os.Getenv(AzureAccountEnv)
+	if account == "" {
+		return nil, nil
+	}
+	serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", account)
+
+	if key := os.Getenv(AzureKeyEnv); key != "" {
+		cred, err := azblob.NewSharedKeyCredential(account, key)
+		if err != nil {
+			return nil, fmt.Errorf(remoteio.Message("Azureのアカウントキーが不正です: %w"), err)
+		}
+		client, err := azblob.NewClientWithSharedKeyCredential(serviceURL, cred, nil)
+		if err != nil {
+			return nil, fmt.Errorf(remoteio.Message("Azureクライアントの初期化に失敗しました: %w"), err)
+		}
+		return client, nil
+	}
+
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf(remoteio.Message("Azureの認証情報の取得に失敗しました: %w"), err)
+	}
+	client, err := azblob.NewClient(serviceURL, cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf(remoteio.Message("Azureクライアントの初期化に失敗しました: %w"), err)
+	}
+	return client, nil
+}
+
+// cassetteOptions は、環境変数 (cassette.ModeEnv, cassette.PathEnv) に応じて、
+// GCSとのやり取りを記録・再生するためのクライアントオプションを返します。
+func (f *ClientFactory) cassetteOptions(ctx context.Context) ([]option.ClientOption, error) {
+	mode := os.Getenv(cassette.ModeEnv)
+	if mode == "" {
+		return nil, nil
+	}
+	path := os.Getenv(cassette.PathEnv)
+	if path == "" {
+		return nil, fmt.Errorf(remoteio.Message("%s を指定する場合は %s でカセットファイルのパスを指定してください"), cassette.ModeEnv, cassette.PathEnv)
+	}
+
+	switch mode {
+	case cassette.ModeRecord:
+		// 認証済みのトランスポートをレコーダーでラップする (エミュレータに対しては認証しない)
+		// 呼び出し元が指定した認証情報やスコープは、既定のスコープより優先する
+		transportOpts := append([]option.ClientOption{option.WithScopes(storage.ScopeFullControl)}, f.clientOptions...)
+		if os.Getenv("STORAGE_EMULATOR_HOST") != "" {
+			transportOpts = append(transportOpts, option.WithoutAuthentication())
+		}
+		transport, err := htransport.NewTransport(ctx, http.DefaultTransport, transportOpts...)
+		if err != nil {
+			return nil, fmt.Errorf(remoteio.Message("記録用トランスポートの初期化に失敗しました: %w"), err)
+		}
+		f.recorder = cassette.NewRecorder(transport)
+		f.cassettePath = path
+		return []option.ClientOption{option.WithHTTPClient(&http.Client{Transport: f.recorder})}, nil
+	case cassette.ModeReplay:
+		c, err := cassette.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		return []option.ClientOption{option.WithHTTPClient(&http.Client{Transport: cassette.NewReplayer(c)})}, nil
+	default:
+		return nil, fmt.Errorf(remoteio.Message("%s の値が不正です: %q (record または replay)"), cassette.ModeEnv, mode)
+	}
 }
 
 // Close は保持しているGCSクライアントをクローズし、リソースを解放します。
+// 記録モードの場合は、記録したやり取りをカセットファイルに保存します。
 // クローズに成功した場合、またはクライアントが既にnilの場合はnilを返します。
 func (f *ClientFactory) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+
+	var errs []error
+	if f.recorder != nil {
+		errs = append(errs, f.recorder.Save(f.cassettePath))
+		f.recorder = nil
+	}
 	if f.gcsClient != nil {
-		err := f.gcsClient.Close()
+		errs = append(errs, f.gcsClient.Close())
 		f.gcsClient = nil
-		return err
 	}
-	return nil
+	// S3・Azureクライアントには解放するリソースがないため、参照のみを外す
+	f.s3Client = nil
+	f.azureClient = nil
+	return errors.Join(errs...)
 }
 
 // Client は、ファクトリが保持するGCSクライアントを返します。
+// 最初の呼び出しでクライアントを作成し、以降は同じクライアント (作成に失敗した場合は同じエラー) を返します。
+// 並行して呼び出しても安全です。
 func (f *ClientFactory) Client() (*storage.Client, error) {
-	if f.gcsClient == nil {
-		// クライアントがnilの場合、NewClientFactoryの失敗、またはClose()が呼び出されたことを意味する
-		return nil, fmt.Errorf("GCSクライアントは既にクローズされています")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil, closedError("GCSクライアントは既にクローズされています")
+	}
+	if f.gcsClient == nil && f.gcsErr == nil {
+		f.gcsClient, f.gcsErr = f.newGCSClient()
 	}
-	return f.gcsClient, nil
+	return f.gcsClient, f.gcsErr
+}
+
+// newGCSClient は、ファクトリのオプションに従ってGCSクライアントを作成します。
+func (f *ClientFactory) newGCSClient() (*storage.Client, error) {
+	client, err := storage.NewClient(f.ctx, f.clientOptions...)
+	if err != nil {
+		return nil, fmt.Errorf(remoteio.Message("GCSクライアントの初期化に失敗しました: %w"), err)
+	}
+	if f.retry != nil {
+		client.SetRetry(f.retry...)
+	}
+	return client, nil
+}
+
+// S3Client は、ファクトリが保持するS3クライアントを返します。
+func (f *ClientFactory) S3Client() (*s3.Client, error) {
+	if f.s3Client == nil {
+		return nil, closedError("S3クライアントは既にクローズされています")
+	}
+	return f.s3Client, nil
+}
+
+// AzureClient は、ファクトリが保持するAzure Blob Storageクライアントを返します。
+func (f *ClientFactory) AzureClient() (*azblob.Client, error) {
+	if f.azureClient == nil {
+		return nil, fmt.Errorf(remoteio.Message("Azureクライアントが構成されていません (%s または %s を指定してください)"), AzureConnectionStringEnv, AzureAccountEnv)
+	}
+	return f.azureClient, nil
 }
 
 // NewInputReader は、GCSクライアントを注入した InputReader の具象実装を返します。
-func (f *ClientFactory) NewInputReader() (remoteio.InputReader, error) {
-	if f.gcsClient == nil {
-		return nil, fmt.Errorf("GCSクライアントは既にクローズされているため、InputReaderを生成できません")
+func (f *ClientFactory) NewInputReader(opts ...remoteio.Option) (remoteio.InputReader, error) {
+	if f.isClosed() {
+		return nil, closedError("GCSクライアントは既にクローズされているため、InputReaderを生成できません")
 	}
-	return remoteio.NewLocalGCSInputReader(f.gcsClient), nil
+	// GCSクライアントは最初の GCS へのアクセス時に Client で取得する (remoteio.WithGCSClientFunc)
+	return remoteio.NewLocalGCSInputReader(nil, append(slices.Clone(f.ioOptions), opts...)...), nil
 }
 
-// NewOutputWriter は、GCSクライアントを注入した UniversalIOWriter の具象実装を返します。
-// UniversalIOWriter は GCSOutputWriter と LocalOutputWriter の両方を満たします。
-func (f *ClientFactory) NewOutputWriter() (remoteio.OutputWriter, error) {
-	if f.gcsClient == nil {
-		return nil, fmt.Errorf("GCSクライアントは既にクローズされているため、OutputWriterを生成できません")
+// NewOutputWriter は、各バックエンドのクライアントを注入した UniversalIOWriter の具象実装を返します。
+// UniversalIOWriter は OutputWriter に加え、GCSOutputWriter などのバックエンド固有のインターフェースも満たします。
+func (f *ClientFactory) NewOutputWriter(opts ...remoteio.Option) (remoteio.OutputWriter, error) {
+	if f.isClosed() {
+		return nil, closedError("GCSクライアントは既にクローズされているため、OutputWriterを生成できません")
 	}
 
-	return remoteio.NewUniversalIOWriter(f.gcsClient), nil
+	return remoteio.NewUniversalIOWriter(nil, append(slices.Clone(f.ioOptions), opts...)...), nil
+}
+
+// closedError は、クローズされたファクトリやクライアントを使用したことを示す、remoteio.ErrClientClosed を含むエラーを返します。
+func closedError(msg string) error {
+	return &remoteio.Error{Kind: remoteio.ErrClientClosed, Err: errors.New(remoteio.Message(msg))}
+}
+
+// isClosed は、Close が呼び出されたかどうかを返します。
+func (f *ClientFactory) isClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
 }
@@ -0,0 +1,86 @@
+package factory
+
+import (
+	"fmt"
+	"sync"
+
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/shouni/go-remote-io/pkg/remoteiotest"
+)
+
+// errFakeNoClient は、FakeFactory にクライアントを要求した場合のエラーです。
+var errFakeNoClient = remoteio.NewMessageError("FakeFactory はクラウドのクライアントを保持していません")
+
+// FakeFactory は、remoteiotest.Store を読み書きする InputReader / OutputWriter を生成する、テスト用の Factory の実装です。
+// Factory を受け取るアプリケーションのコード (コンテキストから Factory を取得する cmd など) に注入すると、
+// クラウドのストレージやエミュレータなしで実行でき、読み書きの内容と呼び出しを Store で確認できます。
+type FakeFactory struct {
+	store *remoteiotest.Store
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewFakeFactory は、store を読み書きする FakeFactory を作成します。
+func NewFakeFactory(store *remoteiotest.Store) *FakeFactory {
+	return &FakeFactory{store: store}
+}
+
+// Store は、ファクトリが読み書きする Store を返します。
+func (f *FakeFactory) Store() *remoteiotest.Store {
+	return f.store
+}
+
+// Client は Factory インターフェースを実装します。FakeFactory は GCS クライアントを保持しないため、常にエラーを返します。
+func (f *FakeFactory) Client() (*storage.Client, error) {
+	return nil, fmt.Errorf(remoteio.Message("GCSクライアントを取得できません: %w"), errFakeNoClient)
+}
+
+// S3Client は Factory インターフェースを実装します。常にエラーを返します。
+func (f *FakeFactory) S3Client() (*s3.Client, error) {
+	return nil, fmt.Errorf(remoteio.Message("S3クライアントを取得できません: %w"), errFakeNoClient)
+}
+
+// AzureClient は Factory インターフェースを実装します。常にエラーを返します。
+func (f *FakeFactory) AzureClient() (*azblob.Client, error) {
+	return nil, fmt.Errorf(remoteio.Message("Azureクライアントを取得できません: %w"), errFakeNoClient)
+}
+
+// NewInputReader は、Store を読み込む remoteiotest.Reader を返します。opts は無視されます。
+func (f *FakeFactory) NewInputReader(opts ...remoteio.Option) (remoteio.InputReader, error) {
+	if f.Closed() {
+		return nil, closedError("FakeFactoryは既にクローズされているため、InputReaderを生成できません")
+	}
+	return remoteiotest.NewReader(f.store), nil
+}
+
+// NewOutputWriter は、Store へ書き込む remoteiotest.Writer を返します。opts は無視されます。
+func (f *FakeFactory) NewOutputWriter(opts ...remoteio.Option) (remoteio.OutputWriter, error) {
+	if f.Closed() {
+		return nil, closedError("FakeFactoryは既にクローズされているため、OutputWriterを生成できません")
+	}
+	return remoteiotest.NewWriter(f.store), nil
+}
+
+// Close は Factory インターフェースを実装します。以降の NewInputReader / NewOutputWriter はエラーを返します。
+// Store の内容は Close の後も確認できます。
+func (f *FakeFactory) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	return nil
+}
+
+// Closed は、Close が呼び出されたかどうかを返します。リソースの解放を確認するテストで使用します。
+func (f *FakeFactory) Closed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+// 型アサーションチェック
+var _ Factory = (*FakeFactory)(nil)
@@ -0,0 +1,88 @@
+package factory
+
+import (
+	"log/slog"
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/api/option"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// Option は、ClientFactory の構成を変更する関数型オプションです。
+// オプションは GCS クライアントの作成前に適用されるため、認証情報やトランスポートも指定できます。
+type Option func(*ClientFactory)
+
+// WithIOOptions は、ファクトリが生成するすべての InputReader / OutputWriter に適用する remoteio.Option を追加します。
+// 環境変数から構成されたオプションより後に適用されます。
+func WithIOOptions(opts ...remoteio.Option) Option {
+	return func(f *ClientFactory) {
+		f.ioOptions = append(f.ioOptions, opts...)
+	}
+}
+
+// WithRetryPolicy は、ファクトリが保持する GCS クライアントのすべてのリクエストを p に従って再試行するよう設定します。
+// ファクトリが生成する InputReader / OutputWriter に加えて、Client で取得したクライアントを直接使用する場合にも適用されます。
+func WithRetryPolicy(p remoteio.RetryPolicy) Option {
+	return func(f *ClientFactory) {
+		f.retry = p.GCSRetryOptions()
+	}
+}
+
+// WithBillingProject は、ファクトリが生成する InputReader / OutputWriter の GCS へのリクエストの料金を project に請求します。
+// リクエスト元による支払い (Requester Pays) が有効なバケットを読み書きする場合に指定します (remoteio.WithBillingProject)。
+func WithBillingProject(project string) Option {
+	return WithIOOptions(remoteio.WithBillingProject(project))
+}
+
+// WithLogger は、ファクトリが生成する InputReader / OutputWriter のログの出力先を logger にします (remoteio.WithLogger)。
+// 省略した場合は slog.Default() に出力します。
+func WithLogger(logger *slog.Logger) Option {
+	return WithIOOptions(remoteio.WithLogger(logger))
+}
+
+// WithTracerProvider は、ファクトリが生成する InputReader / OutputWriter の読み込みと書き込みを tp の OpenTelemetry のスパンとして記録します
+// (remoteio.WithTracerProvider)。省略した場合はスパンを記録しません。
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return WithIOOptions(remoteio.WithTracerProvider(tp))
+}
+
+// WithCredentialsFile は、GCS クライアントの認証に、path のサービスアカウントの鍵ファイルなどの認証情報を使用します。
+// 省略した場合は、アプリケーションのデフォルト認証情報 (GOOGLE_APPLICATION_CREDENTIALS など) を使用します。
+func WithCredentialsFile(path string) Option {
+	return WithClientOptions(option.WithCredentialsFile(path))
+}
+
+// WithCredentialsJSON は、GCS クライアントの認証に、JSON 形式の認証情報 (サービスアカウントの鍵など) を使用します。
+// Secret Manager などから取得した認証情報を、ファイルに書き出さずに指定する場合に使用します。
+func WithCredentialsJSON(data []byte) Option {
+	return WithClientOptions(option.WithCredentialsJSON(data))
+}
+
+// WithScopes は、GCS クライアントの認証で要求する OAuth2 のスコープを指定します。
+// 省略した場合は storage.ScopeFullControl です。読み込みのみの場合は storage.ScopeReadOnly を指定すると権限を絞れます。
+func WithScopes(scopes ...string) Option {
+	return WithClientOptions(option.WithScopes(scopes...))
+}
+
+// WithEndpoint は、GCS へのリクエストを endpoint (JSON API のエンドポイント。例: https://storage.example.com/storage/v1/) へ送信します。
+// Private Service Connect などの独自のエンドポイントを使用する場合に指定します。エミュレータには STORAGE_EMULATOR_HOST を使用してください。
+func WithEndpoint(endpoint string) Option {
+	return WithClientOptions(option.WithEndpoint(endpoint))
+}
+
+// WithHTTPClient は、GCS へのリクエストに client を使用します。プロキシやトレースなどのトランスポートを差し替える場合に指定します。
+// client は認証を自ら行う必要があり、WithCredentialsFile などの認証に関するオプションは無視されます。
+func WithHTTPClient(client *http.Client) Option {
+	return WithClientOptions(option.WithHTTPClient(client))
+}
+
+// WithClientOptions は、GCS クライアントの作成 (storage.NewClient) に opts を追加します。
+// 他のオプションで指定できないエンドポイントやユーザーエージェントなどを指定する場合に使用します。
+// 環境変数で記録・再生モード (cassette.ModeEnv) が指定されている場合は、HTTP クライアントはカセットのものに置き換えられます。
+func WithClientOptions(opts ...option.ClientOption) Option {
+	return func(f *ClientFactory) {
+		f.clientOptions = append(f.clientOptions, opts...)
+	}
+}
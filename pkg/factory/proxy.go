@@ -0,0 +1,95 @@
+package factory
+
+import (
+	"fmt"
+	"sync"
+
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"google.golang.org/grpc"
+
+	"github.com/shouni/go-remote-io/pkg/proxy"
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// errProxyNoClient は、ProxyFactory にクライアントを要求した場合のエラーです。
+var errProxyNoClient = remoteio.NewMessageError("ProxyFactory はクラウドのクライアントを保持していません (認証情報はプロキシが保持します)")
+
+// ProxyFactory は、リモートの URI をプロキシ (remoteio proxyd) 経由で読み書きする InputReader / OutputWriter を生成する Factory の実装です。
+// GCS などの認証情報を配置できないマシンで、認証情報を持つプロキシにリモートの読み書きを任せる場合に使用します。
+// ローカルファイルのパスは、プロキシを経由せずに直接読み書きします。
+type ProxyFactory struct {
+	mu   sync.Mutex
+	conn *grpc.ClientConn // Close の後は nil
+}
+
+// NewProxyFactory は、target (host:port) で待ち受けるプロキシへ接続する ProxyFactory を作成します。
+// 接続は最初のリクエストで確立されるため、プロキシが起動していない場合のエラーは読み書きの時点で返されます。
+func NewProxyFactory(target string, opts ...proxy.DialOption) (*ProxyFactory, error) {
+	conn, err := proxy.Dial(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ProxyFactory{conn: conn}, nil
+}
+
+// Client は Factory インターフェースを実装します。ProxyFactory は GCS クライアントを保持しないため、常にエラーを返します。
+func (f *ProxyFactory) Client() (*storage.Client, error) {
+	return nil, fmt.Errorf(remoteio.Message("GCSクライアントを取得できません: %w"), errProxyNoClient)
+}
+
+// S3Client は Factory インターフェースを実装します。常にエラーを返します。
+func (f *ProxyFactory) S3Client() (*s3.Client, error) {
+	return nil, fmt.Errorf(remoteio.Message("S3クライアントを取得できません: %w"), errProxyNoClient)
+}
+
+// AzureClient は Factory インターフェースを実装します。常にエラーを返します。
+func (f *ProxyFactory) AzureClient() (*azblob.Client, error) {
+	return nil, fmt.Errorf(remoteio.Message("Azureクライアントを取得できません: %w"), errProxyNoClient)
+}
+
+// NewInputReader は、プロキシ経由で読み込む proxy.Client を返します。opts はローカルファイルの読み込みに適用します。
+func (f *ProxyFactory) NewInputReader(opts ...remoteio.Option) (remoteio.InputReader, error) {
+	conn, err := f.connection()
+	if err != nil {
+		return nil, err
+	}
+	return proxy.NewClient(conn, opts...), nil
+}
+
+// NewOutputWriter は、プロキシ経由で書き込む proxy.Client を返します。
+// opts はローカルファイルの書き込みに適用します (remoteio.WithNoClobber と remoteio.WithKMSKeyName はプロキシ経由の書き込みにも適用し、
+// remoteio.WithValidators を指定した場合、プロキシ経由の書き込みは proxy.ErrUnsupportedOption で失敗します)。
+func (f *ProxyFactory) NewOutputWriter(opts ...remoteio.Option) (remoteio.OutputWriter, error) {
+	conn, err := f.connection()
+	if err != nil {
+		return nil, err
+	}
+	return proxy.NewClient(conn, opts...), nil
+}
+
+// Close は Factory インターフェースを実装し、プロキシへの接続を閉じます。
+func (f *ProxyFactory) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.conn == nil {
+		return nil
+	}
+	err := f.conn.Close()
+	f.conn = nil
+	return err
+}
+
+// connection は、プロキシへの接続を返します。Close の後はエラーを返します。
+func (f *ProxyFactory) connection() (*grpc.ClientConn, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.conn == nil {
+		return nil, closedError("ProxyFactoryは既にクローズされています")
+	}
+	return f.conn, nil
+}
+
+// 型アサーションチェック
+var _ Factory = (*ProxyFactory)(nil)
@@ -0,0 +1,199 @@
+//go:build linux || darwin || freebsd
+
+package fusefs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+)
+
+// =================================================================
+// 1. 読み込み用のハンドル
+// =================================================================
+
+// readHandle は、ファイルを範囲読み込みで読み込むハンドルです。
+// 順番に読み込む場合は開いたストリームから続けて読み込み、離れた位置を読み込む場合はその位置からストリームを開き直します。
+type readHandle struct {
+	node *fileNode
+	uri  string
+	size int64 // 開いた時点のサイズ
+
+	mu  sync.Mutex
+	rc  io.ReadCloser // 読み込み中のストリーム。開いていない場合は nil
+	pos int64         // rc の次に読み込む位置
+}
+
+// Read は fs.FileReader インターフェースを実装します。
+func (h *readHandle) Read(ctx context.Context, dest []byte, off int64) (fuse.ReadResult, syscall.Errno) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if off >= h.size {
+		return fuse.ReadResultData(nil), 0
+	}
+	if h.rc == nil || off != h.pos {
+		h.closeStream()
+		// ストリームは後続の Read でも使用するため、要求ごとのコンテキストではなくマウントのコンテキストで開く
+		rc, err := h.node.m.ranger.OpenRange(h.node.m.ctx, h.uri, off, -1)
+		if err != nil {
+			return nil, h.node.m.errno("read", h.uri, err)
+		}
+		h.rc, h.pos = rc, off
+	}
+	n, err := io.ReadFull(h.rc, dest)
+	h.pos += int64(n)
+	if err != nil {
+		h.closeStream()
+		if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, h.node.m.errno("read", h.uri, err)
+		}
+	}
+	return fuse.ReadResultData(dest[:n]), 0
+}
+
+// Release は fs.FileReleaser インターフェースを実装します。
+func (h *readHandle) Release(ctx context.Context) syscall.Errno {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closeStream()
+	return 0
+}
+
+// closeStream は、読み込み中のストリームを閉じます。h.mu を保持して呼び出します。
+func (h *readHandle) closeStream() {
+	if h.rc != nil {
+		h.rc.Close()
+		h.rc = nil
+	}
+}
+
+// =================================================================
+// 2. 書き込み用のハンドル
+// =================================================================
+
+// writeHandle は、一時ファイルに書き込み、閉じた時点 (Flush / Fsync / Release) で内容全体をアップロードするハンドルです。
+type writeHandle struct {
+	node *fileNode
+
+	mu    sync.Mutex
+	tmp   *os.File
+	dirty bool // アップロードしていない変更がある
+}
+
+// Read は fs.FileReader インターフェースを実装し、書き込み中の内容を読み込みます。
+func (h *writeHandle) Read(ctx context.Context, dest []byte, off int64) (fuse.ReadResult, syscall.Errno) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n, err := h.tmp.ReadAt(dest, off)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, h.node.m.errno("read", h.tmp.Name(), err)
+	}
+	return fuse.ReadResultData(dest[:n]), 0
+}
+
+// Write は fs.FileWriter インターフェースを実装します。
+func (h *writeHandle) Write(ctx context.Context, data []byte, off int64) (uint32, syscall.Errno) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n, err := h.tmp.WriteAt(data, off)
+	if n > 0 {
+		h.dirty = true
+	}
+	if err != nil {
+		return uint32(n), h.node.m.errno("write", h.tmp.Name(), err)
+	}
+	return uint32(n), 0
+}
+
+// Flush は fs.FileFlusher インターフェースを実装し、変更をアップロードします。
+// close(2) のたびに呼び出されるため、アップロードの失敗は close のエラーとして返ります。
+func (h *writeHandle) Flush(ctx context.Context) syscall.Errno {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.upload(ctx)
+}
+
+// Fsync は fs.FileFsyncer インターフェースを実装し、変更をアップロードします。
+func (h *writeHandle) Fsync(ctx context.Context, flags uint32) syscall.Errno {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.upload(ctx)
+}
+
+// Release は fs.FileReleaser インターフェースを実装し、アップロードしていない変更があればアップロードしてから一時ファイルを削除します。
+func (h *writeHandle) Release(ctx context.Context) syscall.Errno {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	errno := h.upload(ctx)
+	h.discard()
+	h.node.closeWriter()
+	return errno
+}
+
+// getattr は、書き込み中の内容の属性を out に設定します。
+func (h *writeHandle) getattr(out *fuse.Attr) syscall.Errno {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	info, err := h.tmp.Stat()
+	if err != nil {
+		return h.node.m.errno("stat", h.tmp.Name(), err)
+	}
+	setFileAttr(out, h.node.m.perm(false), info.Size(), info.ModTime())
+	return 0
+}
+
+// truncate は、書き込み中の内容を size バイトに切り詰めます (size が大きい場合は 0 で埋めます)。
+func (h *writeHandle) truncate(size int64) syscall.Errno {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := h.tmp.Truncate(size); err != nil {
+		return h.node.m.errno("truncate", h.tmp.Name(), err)
+	}
+	h.dirty = true
+	return 0
+}
+
+// upload は、変更がある場合に一時ファイルの内容全体をアップロードします。h.mu を保持して呼び出します。
+func (h *writeHandle) upload(ctx context.Context) syscall.Errno {
+	if !h.dirty {
+		return 0
+	}
+	info, err := h.tmp.Stat()
+	if err != nil {
+		return h.node.m.errno("stat", h.tmp.Name(), err)
+	}
+	uri, errno := h.node.m.uriOf(h.node.EmbeddedInode())
+	if errno != 0 {
+		return errno
+	}
+	if err := h.node.m.writer.Write(ctx, uri, io.NewSectionReader(h.tmp, 0, info.Size())); err != nil {
+		return h.node.m.errno("write", uri, err)
+	}
+	h.dirty = false
+	h.node.uploaded(info.Size(), time.Now())
+	return 0
+}
+
+// discard は、一時ファイルを閉じて削除します。
+func (h *writeHandle) discard() {
+	h.tmp.Close()
+	os.Remove(h.tmp.Name())
+}
+
+// 型アサーションチェック
+var (
+	_ fs.FileReader   = (*readHandle)(nil)
+	_ fs.FileReleaser = (*readHandle)(nil)
+	_ fs.FileReader   = (*writeHandle)(nil)
+	_ fs.FileWriter   = (*writeHandle)(nil)
+	_ fs.FileFlusher  = (*writeHandle)(nil)
+	_ fs.FileFsyncer  = (*writeHandle)(nil)
+	_ fs.FileReleaser = (*writeHandle)(nil)
+)
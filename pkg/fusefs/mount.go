@@ -0,0 +1,139 @@
+//go:build linux || darwin || freebsd
+
+package fusefs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// Mount は、rootURI 配下を dir にマウントし、アンマウントされるまでバックグラウンドで要求を処理します。
+// reader は remoteio.ObjectLister と remoteio.RangeInputReader を満たす必要があります (LocalGCSInputReader は両方を満たします)。
+// WithWriteThrough を指定しない場合は読み取り専用でマウントします。
+// ctx はファイルの読み込みのストリームに使用し、キャンセルされると読み込み中のファイルはエラーになります。
+func Mount(ctx context.Context, dir, rootURI string, reader remoteio.InputReader, opts ...Option) (*MountPoint, error) {
+	lister, ok := reader.(remoteio.ObjectLister)
+	if !ok {
+		return nil, errors.New(remoteio.Message("InputReaderが一覧の取得をサポートしていません"))
+	}
+	ranger, ok := reader.(remoteio.RangeInputReader)
+	if !ok {
+		return nil, errors.New(remoteio.Message("InputReaderが範囲読み込みをサポートしていません"))
+	}
+	o := newOptions(opts)
+
+	m := &mountFS{
+		ctx:     ctx,
+		root:    rootURI,
+		lister:  lister,
+		ranger:  ranger,
+		writer:  o.writer,
+		ttl:     o.cacheTTL,
+		logger:  o.logger,
+		mounted: time.Now(),
+	}
+	mountOpts := fuse.MountOptions{
+		FsName: rootURI,
+		Name:   "remoteio",
+		// fusermount がない環境でも root であれば直接マウントする
+		DirectMount: true,
+	}
+	if m.writer == nil {
+		mountOpts.Options = append(mountOpts.Options, "ro")
+	}
+	ttl := o.cacheTTL
+	server, err := fs.Mount(dir, &dirNode{m: m}, &fs.Options{
+		MountOptions:    mountOpts,
+		EntryTimeout:    &ttl,
+		AttrTimeout:     &ttl,
+		NegativeTimeout: &ttl,
+		UID:             uint32(os.Getuid()),
+		GID:             uint32(os.Getgid()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf(remoteio.Message("マウントに失敗しました (%s): %w"), dir, err)
+	}
+	return &MountPoint{dir: dir, unmount: server.Unmount, wait: server.Wait}, nil
+}
+
+// mountFS は、マウントしたファイルシステムのすべてのノードが共有する状態です。
+type mountFS struct {
+	ctx     context.Context
+	root    string // マウントした URI
+	lister  remoteio.ObjectLister
+	ranger  remoteio.RangeInputReader
+	writer  remoteio.OutputWriter // nil の場合は読み取り専用
+	ttl     time.Duration
+	logger  *slog.Logger
+	mounted time.Time // ディレクトリの更新日時として使用
+}
+
+// uriOf は、ノード n の URI を返します。名前の変更に追従するため、マウントのルートからの名前を辿って求めます。
+// リモートから削除されるなどしてルートから辿れなくなったノードの場合は ENOENT を返します。
+func (m *mountFS) uriOf(n *fs.Inode) (string, syscall.Errno) {
+	var names []string
+	for !n.IsRoot() {
+		name, parent := n.Parent()
+		if parent == nil {
+			return "", syscall.ENOENT
+		}
+		names = append(names, name)
+		n = parent
+	}
+	if len(names) == 0 {
+		return m.root, 0
+	}
+	slices.Reverse(names)
+	return remoteio.JoinURI(m.root, strings.Join(names, "/")), 0
+}
+
+// perm は、読み取り専用かどうかに応じたパーミッションを返します。
+func (m *mountFS) perm(dir bool) uint32 {
+	switch {
+	case dir && m.writer == nil:
+		return 0o555
+	case dir:
+		return 0o755
+	case m.writer == nil:
+		return 0o444
+	default:
+		return 0o644
+	}
+}
+
+// errno は、リモートの読み書きのエラー err を errno に変換し、原因をログに出力します。
+func (m *mountFS) errno(op, uri string, err error) syscall.Errno {
+	errno := toErrno(err)
+	if errno != syscall.ENOENT && errno != syscall.EINTR {
+		m.logger.Warn(remoteio.Message("リモートの読み書きに失敗しました"), slog.String("op", op), slog.String("uri", uri), slog.Any("error", err))
+	}
+	return errno
+}
+
+// toErrno は、remoteio のエラーの分類を errno に変換します。
+func toErrno(err error) syscall.Errno {
+	switch {
+	case errors.Is(err, remoteio.ErrNotFound):
+		return syscall.ENOENT
+	case errors.Is(err, remoteio.ErrPermissionDenied):
+		return syscall.EACCES
+	case errors.Is(err, remoteio.ErrAlreadyExists):
+		return syscall.EEXIST
+	case errors.Is(err, context.Canceled):
+		return syscall.EINTR
+	default:
+		return syscall.EIO
+	}
+}
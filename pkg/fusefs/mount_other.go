@@ -0,0 +1,17 @@
+//go:build !linux && !darwin && !freebsd
+
+package fusefs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// Mount は、FUSE をサポートしないプラットフォームでは常に errors.ErrUnsupported を返します。
+func Mount(ctx context.Context, dir, rootURI string, reader remoteio.InputReader, opts ...Option) (*MountPoint, error) {
+	return nil, fmt.Errorf(remoteio.Message("FUSE によるマウントは %s ではサポートされていません: %w"), runtime.GOOS, errors.ErrUnsupported)
+}
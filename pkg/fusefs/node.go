@@ -0,0 +1,543 @@
+//go:build linux || darwin || freebsd
+
+package fusefs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// listPageSize は、ディレクトリを一覧する際に1回のリクエストで取得する件数です。
+const listPageSize = 1000
+
+// =================================================================
+// 1. ディレクトリ
+// =================================================================
+
+// dirNode は、リモートのプレフィックス (マウントのルートを含む) を表すディレクトリです。
+// 直下のエントリは "/" 区切りの一覧で取得し、mountFS.ttl の間キャッシュします。
+type dirNode struct {
+	fs.Inode
+	m *mountFS
+
+	mu      sync.Mutex
+	entries map[string]remoteio.ObjectInfo // 直下のファイルとディレクトリ (ディレクトリは IsPrefix)。未取得の場合は nil
+	listed  time.Time                      // entries を一覧した日時
+	created map[string]bool                // Mkdir で作成したディレクトリ (配下のファイルがなくなっても Rmdir まで表示する)
+}
+
+// Getattr は fs.NodeGetattrer インターフェースを実装します。
+func (d *dirNode) Getattr(ctx context.Context, f fs.FileHandle, out *fuse.AttrOut) syscall.Errno {
+	d.attr(&out.Attr)
+	return 0
+}
+
+// Setattr は fs.NodeSetattrer インターフェースを実装します。
+// ディレクトリにはリモートの属性がないため、cp -p や mv が属性を複製できるよう変更を無視して成功させます。
+func (d *dirNode) Setattr(ctx context.Context, f fs.FileHandle, in *fuse.SetAttrIn, out *fuse.AttrOut) syscall.Errno {
+	if d.m.writer == nil {
+		return syscall.EROFS
+	}
+	d.attr(&out.Attr)
+	return 0
+}
+
+// Setxattr は fs.NodeSetxattrer インターフェースを実装します。
+// 拡張属性は保存できないため ENOTSUP を返し、cp -p などに ACL などの複製を省略させます。
+func (d *dirNode) Setxattr(ctx context.Context, attr string, data []byte, flags uint32) syscall.Errno {
+	return syscall.ENOTSUP
+}
+
+// Lookup は fs.NodeLookuper インターフェースを実装します。
+func (d *dirNode) Lookup(ctx context.Context, name string, out *fuse.EntryOut) (*fs.Inode, syscall.Errno) {
+	info, ok, errno := d.entry(ctx, name)
+	if errno != 0 {
+		return nil, errno
+	}
+	if !ok {
+		return nil, syscall.ENOENT
+	}
+	child := d.childInode(ctx, name, info)
+	child.Operations().(attrNode).attr(&out.Attr)
+	return child, 0
+}
+
+// Readdir は fs.NodeReaddirer インターフェースを実装し、直下のエントリを名前順で返します。
+func (d *dirNode) Readdir(ctx context.Context) (fs.DirStream, syscall.Errno) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if errno := d.refresh(ctx); errno != 0 {
+		return nil, errno
+	}
+	list := make([]fuse.DirEntry, 0, len(d.entries))
+	for name, info := range d.entries {
+		mode := uint32(fuse.S_IFREG)
+		if info.IsPrefix {
+			mode = fuse.S_IFDIR
+		}
+		list = append(list, fuse.DirEntry{Name: name, Mode: mode})
+	}
+	slices.SortFunc(list, func(a, b fuse.DirEntry) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return fs.NewListDirStream(list), 0
+}
+
+// Mkdir は fs.NodeMkdirer インターフェースを実装します。
+// GCS などにはディレクトリが存在しないため、作成したディレクトリは配下にファイルを書き込むまでリモートには存在せず、マウント中のみ表示します。
+func (d *dirNode) Mkdir(ctx context.Context, name string, mode uint32, out *fuse.EntryOut) (*fs.Inode, syscall.Errno) {
+	if d.m.writer == nil {
+		return nil, syscall.EROFS
+	}
+	if _, ok, errno := d.entry(ctx, name); errno != 0 {
+		return nil, errno
+	} else if ok {
+		return nil, syscall.EEXIST
+	}
+	d.mu.Lock()
+	if d.created == nil {
+		d.created = make(map[string]bool)
+	}
+	d.created[name] = true
+	d.mu.Unlock()
+	d.setEntry(name, remoteio.ObjectInfo{Name: name + "/", IsPrefix: true})
+
+	child := &dirNode{m: d.m}
+	child.attr(&out.Attr)
+	return d.NewInode(ctx, child, fs.StableAttr{Mode: fuse.S_IFDIR}), 0
+}
+
+// Create は fs.NodeCreater インターフェースを実装します。作成したファイルは閉じた時点でアップロードします。
+func (d *dirNode) Create(ctx context.Context, name string, flags uint32, mode uint32, out *fuse.EntryOut) (*fs.Inode, fs.FileHandle, uint32, syscall.Errno) {
+	if d.m.writer == nil {
+		return nil, nil, 0, syscall.EROFS
+	}
+	node := &fileNode{m: d.m, mtime: time.Now()}
+	child := d.NewInode(ctx, node, fs.StableAttr{Mode: fuse.S_IFREG})
+	h, errno := node.openWrite(ctx, true)
+	if errno != 0 {
+		return nil, nil, 0, errno
+	}
+	d.setEntry(name, remoteio.ObjectInfo{Name: name, Updated: node.mtime})
+	node.attr(&out.Attr)
+	return child, h, 0, 0
+}
+
+// Unlink は fs.NodeUnlinker インターフェースを実装します。
+func (d *dirNode) Unlink(ctx context.Context, name string) syscall.Errno {
+	deleter, ok := d.m.writer.(remoteio.Deleter)
+	if !ok {
+		return syscall.EROFS
+	}
+	info, ok, errno := d.entry(ctx, name)
+	switch {
+	case errno != 0:
+		return errno
+	case !ok:
+		return syscall.ENOENT
+	case info.IsPrefix:
+		return syscall.EISDIR
+	}
+	uri, errno := d.childURI(name)
+	if errno != 0 {
+		return errno
+	}
+	if err := deleter.Delete(ctx, uri); err != nil && !errors.Is(err, remoteio.ErrNotFound) {
+		return d.m.errno("delete", uri, err)
+	}
+	d.removeEntry(name)
+	return 0
+}
+
+// Rmdir は fs.NodeRmdirer インターフェースを実装します。空のディレクトリのみを削除できます。
+func (d *dirNode) Rmdir(ctx context.Context, name string) syscall.Errno {
+	if d.m.writer == nil {
+		return syscall.EROFS
+	}
+	info, ok, errno := d.entry(ctx, name)
+	switch {
+	case errno != 0:
+		return errno
+	case !ok:
+		return syscall.ENOENT
+	case !info.IsPrefix:
+		return syscall.ENOTDIR
+	}
+	uri, errno := d.childURI(name)
+	if errno != 0 {
+		return errno
+	}
+	page, err := d.m.lister.ListObjectsPage(ctx, uri, remoteio.WithDelimiter("/"), remoteio.WithPageSize(1))
+	if err != nil {
+		return d.m.errno("list", uri, err)
+	}
+	if len(page.Objects) > 0 {
+		return syscall.ENOTEMPTY
+	}
+	if ch := d.GetChild(name); ch != nil {
+		if child, ok := ch.Operations().(*dirNode); ok && child.hasEntries() {
+			return syscall.ENOTEMPTY
+		}
+	}
+	d.mu.Lock()
+	delete(d.created, name)
+	d.mu.Unlock()
+	d.removeEntry(name)
+	return 0
+}
+
+// Rename は fs.NodeRenamer インターフェースを実装し、ファイルをサーバー側で移動します。
+// ディレクトリの場合や writer が移動をサポートしない場合は EXDEV を返し、mv などにコピーと削除で移動させます。
+func (d *dirNode) Rename(ctx context.Context, name string, newParent fs.InodeEmbedder, newName string, flags uint32) syscall.Errno {
+	if d.m.writer == nil {
+		return syscall.EROFS
+	}
+	if flags != 0 {
+		return syscall.ENOTSUP
+	}
+	dst, ok := newParent.(*dirNode)
+	if !ok {
+		return syscall.EXDEV
+	}
+	info, ok, errno := d.entry(ctx, name)
+	switch {
+	case errno != 0:
+		return errno
+	case !ok:
+		return syscall.ENOENT
+	case info.IsPrefix:
+		return syscall.EXDEV
+	}
+	mover, ok := d.m.writer.(remoteio.Mover)
+	if !ok {
+		return syscall.EXDEV
+	}
+	if target, ok, errno := dst.entry(ctx, newName); errno != 0 {
+		return errno
+	} else if ok && target.IsPrefix {
+		return syscall.EISDIR
+	}
+
+	src, errno := d.childURI(name)
+	if errno != 0 {
+		return errno
+	}
+	dstURI, errno := dst.childURI(newName)
+	if errno != 0 {
+		return errno
+	}
+	if err := mover.Move(ctx, src, dstURI); err != nil {
+		if errors.Is(err, remoteio.ErrMoveUnsupported) {
+			return syscall.EXDEV
+		}
+		return d.m.errno("rename", src, err)
+	}
+	d.removeEntry(name)
+	info.Name = newName
+	dst.setEntry(newName, info)
+	return 0
+}
+
+// attr は、ディレクトリの属性を out に設定します。
+func (d *dirNode) attr(out *fuse.Attr) {
+	out.Mode = fuse.S_IFDIR | d.m.perm(true)
+	out.Nlink = 2
+	out.SetTimes(nil, &d.m.mounted, &d.m.mounted)
+}
+
+// childURI は、直下のエントリ name の URI を返します。
+func (d *dirNode) childURI(name string) (string, syscall.Errno) {
+	uri, errno := d.m.uriOf(d.EmbeddedInode())
+	if errno != 0 {
+		return "", errno
+	}
+	return remoteio.JoinURI(uri, name), 0
+}
+
+// childInode は、直下のエントリ name のノードを返します。既存のノードの種類が一致する場合は、情報を更新して再利用します。
+func (d *dirNode) childInode(ctx context.Context, name string, info remoteio.ObjectInfo) *fs.Inode {
+	if ch := d.GetChild(name); ch != nil {
+		switch n := ch.Operations().(type) {
+		case *dirNode:
+			if info.IsPrefix {
+				return ch
+			}
+		case *fileNode:
+			if !info.IsPrefix {
+				n.update(info)
+				return ch
+			}
+		}
+	}
+	if info.IsPrefix {
+		return d.NewInode(ctx, &dirNode{m: d.m}, fs.StableAttr{Mode: fuse.S_IFDIR})
+	}
+	return d.NewInode(ctx, &fileNode{m: d.m, size: info.Size, mtime: info.Updated}, fs.StableAttr{Mode: fuse.S_IFREG})
+}
+
+// entry は、直下のエントリ name の情報を返します。
+func (d *dirNode) entry(ctx context.Context, name string) (remoteio.ObjectInfo, bool, syscall.Errno) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if errno := d.refresh(ctx); errno != 0 {
+		return remoteio.ObjectInfo{}, false, errno
+	}
+	info, ok := d.entries[name]
+	return info, ok, 0
+}
+
+// hasEntries は、キャッシュしている直下のエントリがあるかどうかを返します。
+func (d *dirNode) hasEntries() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.entries) > 0
+}
+
+// setEntry は、書き込みなどで作成したエントリをキャッシュに反映します。
+func (d *dirNode) setEntry(name string, info remoteio.ObjectInfo) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.entries != nil {
+		d.entries[name] = info
+	}
+}
+
+// removeEntry は、削除したエントリをキャッシュから取り除きます。
+func (d *dirNode) removeEntry(name string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.entries, name)
+}
+
+// refresh は、キャッシュの有効期限が切れている場合に直下のエントリを一覧し直します。d.mu を保持して呼び出します。
+func (d *dirNode) refresh(ctx context.Context) syscall.Errno {
+	if d.entries != nil && time.Since(d.listed) < d.m.ttl {
+		return 0
+	}
+	uri, errno := d.m.uriOf(d.EmbeddedInode())
+	if errno != 0 {
+		return errno
+	}
+	entries := make(map[string]remoteio.ObjectInfo)
+	opts := []remoteio.ListOption{remoteio.WithDelimiter("/"), remoteio.WithPageSize(listPageSize)}
+	for token := ""; ; {
+		page, err := d.m.lister.ListObjectsPage(ctx, uri, append(opts, remoteio.WithPageToken(token))...)
+		if err != nil {
+			return d.m.errno("list", uri, err)
+		}
+		for _, obj := range page.Objects {
+			name := strings.TrimSuffix(obj.Name, "/")
+			if name == "" || name == "." || name == ".." || strings.Contains(name, "/") {
+				continue
+			}
+			// 同じ名前のオブジェクトとプレフィックスがある場合は、配下を辿れるようディレクトリを優先する
+			if prev, ok := entries[name]; ok && prev.IsPrefix {
+				continue
+			}
+			entries[name] = obj
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		token = page.NextPageToken
+	}
+	for name := range d.created {
+		if _, ok := entries[name]; !ok {
+			entries[name] = remoteio.ObjectInfo{Name: name + "/", IsPrefix: true}
+		}
+	}
+	d.entries, d.listed = entries, time.Now()
+	return 0
+}
+
+// =================================================================
+// 2. ファイル
+// =================================================================
+
+// fileNode は、リモートのファイル (オブジェクト) を表すノードです。
+type fileNode struct {
+	fs.Inode
+	m *mountFS
+
+	mu      sync.Mutex
+	size    int64
+	mtime   time.Time
+	writers int // 開いている書き込み用のハンドルの数
+}
+
+// Getattr は fs.NodeGetattrer インターフェースを実装します。書き込み中の場合は一時ファイルのサイズを返します。
+func (f *fileNode) Getattr(ctx context.Context, fh fs.FileHandle, out *fuse.AttrOut) syscall.Errno {
+	if h, ok := fh.(*writeHandle); ok {
+		return h.getattr(&out.Attr)
+	}
+	f.attr(&out.Attr)
+	return 0
+}
+
+// Setattr は fs.NodeSetattrer インターフェースを実装します。
+// サイズの変更 (truncate) のみを反映し、パーミッションや更新日時の変更は無視します。
+func (f *fileNode) Setattr(ctx context.Context, fh fs.FileHandle, in *fuse.SetAttrIn, out *fuse.AttrOut) syscall.Errno {
+	if size, ok := in.GetSize(); ok {
+		if f.m.writer == nil {
+			return syscall.EROFS
+		}
+		if h, ok := fh.(*writeHandle); ok {
+			if errno := h.truncate(int64(size)); errno != 0 {
+				return errno
+			}
+		} else {
+			// 開かずに truncate した場合は、その場で書き換えてアップロードする
+			h, errno := f.openWrite(ctx, size == 0)
+			if errno != 0 {
+				return errno
+			}
+			errno = h.truncate(int64(size))
+			if rerr := h.Release(ctx); errno == 0 {
+				errno = rerr
+			}
+			if errno != 0 {
+				return errno
+			}
+		}
+	}
+	return f.Getattr(ctx, fh, out)
+}
+
+// Setxattr は fs.NodeSetxattrer インターフェースを実装します。拡張属性は保存できないため ENOTSUP を返します。
+func (f *fileNode) Setxattr(ctx context.Context, attr string, data []byte, flags uint32) syscall.Errno {
+	return syscall.ENOTSUP
+}
+
+// Open は fs.NodeOpener インターフェースを実装します。
+// 読み込み用の場合は範囲読み込みのハンドルを、書き込み用の場合は一時ファイルに書き込むハンドルを返します。
+func (f *fileNode) Open(ctx context.Context, flags uint32) (fs.FileHandle, uint32, syscall.Errno) {
+	if flags&syscall.O_ACCMODE == syscall.O_RDONLY {
+		uri, errno := f.m.uriOf(f.EmbeddedInode())
+		if errno != 0 {
+			return nil, 0, errno
+		}
+		f.mu.Lock()
+		size := f.size
+		f.mu.Unlock()
+		return &readHandle{node: f, uri: uri, size: size}, 0, 0
+	}
+	if f.m.writer == nil {
+		return nil, 0, syscall.EROFS
+	}
+	h, errno := f.openWrite(ctx, flags&syscall.O_TRUNC != 0)
+	if errno != 0 {
+		return nil, 0, errno
+	}
+	return h, 0, 0
+}
+
+// openWrite は、書き込み用のハンドルを開きます。
+// truncate が false の場合は、一部だけを書き換えられるよう現在の内容を一時ファイルへダウンロードします。
+func (f *fileNode) openWrite(ctx context.Context, truncate bool) (*writeHandle, syscall.Errno) {
+	tmp, err := os.CreateTemp("", "remoteio-mount-*")
+	if err != nil {
+		return nil, f.m.errno("open", os.TempDir(), err)
+	}
+	h := &writeHandle{node: f, tmp: tmp, dirty: truncate}
+	if !truncate {
+		uri, errno := f.m.uriOf(f.EmbeddedInode())
+		if errno != 0 {
+			h.discard()
+			return nil, errno
+		}
+		rc, err := f.m.ranger.OpenRange(ctx, uri, 0, -1)
+		if err == nil {
+			_, err = io.Copy(tmp, rc)
+			rc.Close()
+		}
+		if err != nil {
+			h.discard()
+			return nil, f.m.errno("open", uri, err)
+		}
+	}
+	f.mu.Lock()
+	f.writers++
+	f.mu.Unlock()
+	return h, 0
+}
+
+// attr は、ファイルの属性を out に設定します。
+func (f *fileNode) attr(out *fuse.Attr) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	setFileAttr(out, f.m.perm(false), f.size, f.mtime)
+}
+
+// update は、一覧で取得した情報を反映します。書き込み中の場合は、書き込み中の内容を優先します。
+func (f *fileNode) update(info remoteio.ObjectInfo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.writers == 0 {
+		f.size, f.mtime = info.Size, info.Updated
+	}
+}
+
+// uploaded は、アップロードした内容のサイズと日時をノードと親ディレクトリのキャッシュに反映します。
+func (f *fileNode) uploaded(size int64, mtime time.Time) {
+	f.mu.Lock()
+	f.size, f.mtime = size, mtime
+	f.mu.Unlock()
+	if name, parent := f.Parent(); parent != nil {
+		if d, ok := parent.Operations().(*dirNode); ok {
+			d.setEntry(name, remoteio.ObjectInfo{Name: name, Size: size, Updated: mtime})
+		}
+	}
+}
+
+// closeWriter は、書き込み用のハンドルを閉じたことを記録します。
+func (f *fileNode) closeWriter() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.writers--
+}
+
+// setFileAttr は、ファイルの属性を out に設定します。
+func setFileAttr(out *fuse.Attr, perm uint32, size int64, mtime time.Time) {
+	out.Mode = fuse.S_IFREG | perm
+	out.Nlink = 1
+	out.Size = uint64(size)
+	out.Blocks = (uint64(size) + 511) / 512
+	out.SetTimes(nil, &mtime, &mtime)
+}
+
+// attrNode は、属性を設定できるノードです。
+type attrNode interface {
+	attr(out *fuse.Attr)
+}
+
+// 型アサーションチェック
+var (
+	_ fs.NodeGetattrer  = (*dirNode)(nil)
+	_ fs.NodeSetattrer  = (*dirNode)(nil)
+	_ fs.NodeSetxattrer = (*dirNode)(nil)
+	_ fs.NodeLookuper   = (*dirNode)(nil)
+	_ fs.NodeReaddirer  = (*dirNode)(nil)
+	_ fs.NodeMkdirer    = (*dirNode)(nil)
+	_ fs.NodeCreater    = (*dirNode)(nil)
+	_ fs.NodeUnlinker   = (*dirNode)(nil)
+	_ fs.NodeRmdirer    = (*dirNode)(nil)
+	_ fs.NodeRenamer    = (*dirNode)(nil)
+	_ fs.NodeGetattrer  = (*fileNode)(nil)
+	_ fs.NodeSetattrer  = (*fileNode)(nil)
+	_ fs.NodeSetxattrer = (*fileNode)(nil)
+	_ fs.NodeOpener     = (*fileNode)(nil)
+	_ attrNode          = (*dirNode)(nil)
+	_ attrNode          = (*fileNode)(nil)
+)
@@ -0,0 +1,403 @@
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/shouni/go-remote-io/pkg/proxy/proxypb"
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// defaultListPageSize は、ListObjects が1回のリクエストで要求する件数です。
+// 一覧の全体を1つのメッセージで返すと、gRPC の最大受信サイズを超える場合があるため、ページに分割します。
+const defaultListPageSize = 1000
+
+// =================================================================
+// 1. 接続
+// =================================================================
+
+// DialOption は、Dial の構成を変更する関数型オプションです。
+type DialOption func(*dialConfig)
+
+type dialConfig struct {
+	token    string
+	tls      *tls.Config
+	grpcOpts []grpc.DialOption
+}
+
+// WithToken は、プロキシの認証に使用する共有トークンを送信します (サーバーは WithRequiredToken で指定します)。
+func WithToken(token string) DialOption {
+	return func(c *dialConfig) {
+		c.token = token
+	}
+}
+
+// WithTLSConfig は、プロキシへ cfg の TLS で接続します。省略した場合は TLS を使用しません。
+func WithTLSConfig(cfg *tls.Config) DialOption {
+	return func(c *dialConfig) {
+		c.tls = cfg
+	}
+}
+
+// WithGRPCDialOptions は、gRPC の接続の作成 (grpc.NewClient) に opts を追加します。
+func WithGRPCDialOptions(opts ...grpc.DialOption) DialOption {
+	return func(c *dialConfig) {
+		c.grpcOpts = append(c.grpcOpts, opts...)
+	}
+}
+
+// Dial は、target (host:port) で待ち受けるプロキシへの gRPC の接続を作成します。
+// 接続は最初のリクエストで確立されます。不要になった接続は呼び出し元が Close してください。
+func Dial(target string, opts ...DialOption) (*grpc.ClientConn, error) {
+	var c dialConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	transport := insecure.NewCredentials()
+	if c.tls != nil {
+		transport = credentials.NewTLS(c.tls)
+	}
+	grpcOpts := []grpc.DialOption{grpc.WithTransportCredentials(transport)}
+	if c.token != "" {
+		grpcOpts = append(grpcOpts, grpc.WithPerRPCCredentials(tokenCredentials{token: c.token, requireTLS: c.tls != nil}))
+	}
+	conn, err := grpc.NewClient(target, append(grpcOpts, c.grpcOpts...)...)
+	if err != nil {
+		return nil, fmt.Errorf(remoteio.Message("プロキシへの接続の作成に失敗しました (%s): %w"), target, err)
+	}
+	return conn, nil
+}
+
+// =================================================================
+// 2. Client
+// =================================================================
+
+// Client は、リモートの URI をプロキシ経由で読み書きする InputReader / OutputWriter の実装です。
+// ローカルファイルのパスはプロキシを経由せず、このマシンで直接読み書きします。
+// remoteio.RangeInputReader、remoteio.Stater、remoteio.ObjectLister も満たします。
+type Client struct {
+	rpc         proxypb.RemoteIOClient
+	local       *remoteio.LocalGCSInputReader // ローカルファイルの読み込みに使用する
+	localWriter *remoteio.UniversalIOWriter   // ローカルファイルの書き込みに使用する
+	settings    remoteio.Settings             // プロキシ経由の書き込みに適用する構成 (上書きの防止、Cloud KMS の鍵、バリデータ)
+}
+
+// NewClient は、conn (通常は Dial で作成したもの) のプロキシを経由して読み書きする Client を作成します。
+// opts はローカルファイルの読み書きに適用します。プロキシ経由の書き込みには、remoteio.WithNoClobber と remoteio.WithKMSKeyName を適用し、
+// remoteio.WithValidators を指定した場合は、内容を検査できないため ErrUnsupportedOption で失敗します。
+func NewClient(conn grpc.ClientConnInterface, opts ...remoteio.Option) *Client {
+	return &Client{
+		rpc:         proxypb.NewRemoteIOClient(conn),
+		local:       remoteio.NewLocalGCSInputReader(nil, opts...),
+		localWriter: remoteio.NewUniversalIOWriter(nil, opts...),
+		settings:    remoteio.ResolveOptions(opts...),
+	}
+}
+
+// Open は InputReader インターフェースを実装します。
+func (c *Client) Open(ctx context.Context, filePath string) (io.ReadCloser, error) {
+	if isLocal(filePath) {
+		return c.local.Open(ctx, filePath)
+	}
+	return c.OpenRange(ctx, filePath, 0, -1)
+}
+
+// OpenRange は RangeInputReader インターフェースを実装します。
+// 最初のチャンクを受信してから返すため、存在しない場合などのエラーは OpenRange が返します。
+func (c *Client) OpenRange(ctx context.Context, filePath string, offset, length int64) (io.ReadCloser, error) {
+	if isLocal(filePath) {
+		return c.local.OpenRange(ctx, filePath, offset, length)
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf(remoteio.Message("範囲読み込みのオフセットが負です (%s): %d"), filePath, offset)
+	}
+	if length == 0 {
+		return io.NopCloser(strings.NewReader("")), nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.rpc.Open(ctx, &proxypb.OpenRequest{Uri: filePath, Offset: offset, Length: length})
+	if err != nil {
+		cancel()
+		return nil, fromStatus(err)
+	}
+	first, err := stream.Recv()
+	if err == io.EOF {
+		cancel()
+		return io.NopCloser(strings.NewReader("")), nil
+	}
+	if err != nil {
+		cancel()
+		return nil, fromStatus(err)
+	}
+	return &chunkReader{stream: stream, buf: first.GetData(), cancel: cancel}, nil
+}
+
+// OpenReaderAt は RangeInputReader インターフェースを実装します。
+// ReadAt の呼び出しごとに、OpenRange で必要な範囲のみを読み込みます。
+func (c *Client) OpenReaderAt(ctx context.Context, filePath string) (remoteio.ReadAtCloser, error) {
+	if isLocal(filePath) {
+		return c.local.OpenReaderAt(ctx, filePath)
+	}
+	info, err := c.Stat(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	return &readerAt{ctx: ctx, c: c, uri: filePath, size: info.Size}, nil
+}
+
+// Stat は Stater インターフェースを実装します。
+func (c *Client) Stat(ctx context.Context, uri string) (remoteio.ObjectInfo, error) {
+	if isLocal(uri) {
+		return c.local.Stat(ctx, uri)
+	}
+	msg, err := c.rpc.Stat(ctx, &proxypb.StatRequest{Uri: uri})
+	if err != nil {
+		return remoteio.ObjectInfo{}, fromStatus(err)
+	}
+	return fromProtoInfo(msg), nil
+}
+
+// Exists は Stater インターフェースを実装します。
+func (c *Client) Exists(ctx context.Context, uri string) (bool, error) {
+	if isLocal(uri) {
+		return c.local.Exists(ctx, uri)
+	}
+	_, err := c.Stat(ctx, uri)
+	if errors.Is(err, remoteio.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ListObjects は ObjectLister インターフェースを実装します。
+func (c *Client) ListObjects(ctx context.Context, prefixURI string, opts ...remoteio.ListOption) ([]remoteio.ObjectInfo, error) {
+	if isLocal(prefixURI) {
+		return c.local.ListObjects(ctx, prefixURI, opts...)
+	}
+	settings := remoteio.ResolveListOptions(opts...)
+	if settings.PageSize <= 0 {
+		settings.PageSize = defaultListPageSize
+	}
+	var objects []remoteio.ObjectInfo
+	for {
+		page, err := c.listPage(ctx, prefixURI, settings)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, page.Objects...)
+		if page.NextPageToken == "" {
+			return objects, nil
+		}
+		settings.PageToken = page.NextPageToken
+	}
+}
+
+// ListObjectsPage は ObjectLister インターフェースを実装します。
+func (c *Client) ListObjectsPage(ctx context.Context, prefixURI string, opts ...remoteio.ListOption) (remoteio.ObjectPage, error) {
+	if isLocal(prefixURI) {
+		return c.local.ListObjectsPage(ctx, prefixURI, opts...)
+	}
+	return c.listPage(ctx, prefixURI, remoteio.ResolveListOptions(opts...))
+}
+
+// listPage は、プロキシから prefixURI 配下の1ページ分を取得します。
+func (c *Client) listPage(ctx context.Context, prefixURI string, settings remoteio.ListSettings) (remoteio.ObjectPage, error) {
+	resp, err := c.rpc.List(ctx, &proxypb.ListRequest{
+		Uri:       prefixURI,
+		Delimiter: settings.Delimiter,
+		PageSize:  int32(settings.PageSize),
+		PageToken: settings.PageToken,
+	})
+	if err != nil {
+		return remoteio.ObjectPage{}, fromStatus(err)
+	}
+	page := remoteio.ObjectPage{NextPageToken: resp.GetNextPageToken()}
+	for _, msg := range resp.GetObjects() {
+		page.Objects = append(page.Objects, fromProtoInfo(msg))
+	}
+	return page, nil
+}
+
+// Write は OutputWriter インターフェースを実装します。
+// r の内容を分割してプロキシへストリーミングし、r の読み込みが失敗した場合は、書き込み先を確定させずに中止します。
+// opts のうち、書き込み先に保存される内容と属性に関わるもの (Content-Type、カスタムメタデータ、HTTP ヘッダー、上書きの防止、
+// 世代番号の前提条件、Cloud KMS の鍵、gzip による圧縮、検証) をプロキシへ送信します。バッファのサイズなどの調整は適用しません。
+// WithTransforms の変換は、プロキシへ送信する前にこのプロセスで適用します。
+// remoteio.WithValidators を指定したクライアントでは、何も送信せずに ErrUnsupportedOption を返します。
+func (c *Client) Write(ctx context.Context, destURI string, r io.Reader, opts ...remoteio.WriteOption) error {
+	if isLocal(destURI) {
+		return c.localWriter.Write(ctx, destURI, r, opts...)
+	}
+	if len(c.settings.Validators) > 0 {
+		// 書き込む内容の検査はプロキシに中継できないため、検査せずに書き込まない
+		return fmt.Errorf(remoteio.Message("%w: バリデータ (%s)"), ErrUnsupportedOption, destURI)
+	}
+	settings := remoteio.ResolveWriteOptions(opts...)
+	header := writeHeader(destURI, settings, c.settings)
+	r = remoteio.ApplyTransforms(r, opts...)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.rpc.Write(ctx)
+	if err != nil {
+		return fromStatus(err)
+	}
+	if err := stream.Send(&proxypb.WriteRequest{Payload: &proxypb.WriteRequest_Header{Header: header}}); err != nil {
+		return c.closeWrite(stream, settings)
+	}
+	buf := make([]byte, chunkSize)
+	for {
+		n, readErr := io.ReadFull(r, buf)
+		if n > 0 {
+			if err := stream.Send(&proxypb.WriteRequest{Payload: &proxypb.WriteRequest_Data{Data: buf[:n]}}); err != nil {
+				// プロキシが書き込みを中止した場合は、その理由を返す
+				return c.closeWrite(stream, settings)
+			}
+		}
+		if errors.Is(readErr, io.EOF) || errors.Is(readErr, io.ErrUnexpectedEOF) {
+			break
+		}
+		if readErr != nil {
+			// ストリームをキャンセルし、プロキシに書き込み先を確定させない
+			cancel()
+			return fmt.Errorf(remoteio.Message("書き込む内容の読み込みに失敗しました (%s): %w"), destURI, readErr)
+		}
+	}
+	return c.closeWrite(stream, settings)
+}
+
+// writeHeader は、書き込みの設定 settings とクライアントの構成 cfg から、プロキシへ送信する書き込みのヘッダーを作成します。
+func writeHeader(destURI string, settings remoteio.WriteSettings, cfg remoteio.Settings) *proxypb.WriteHeader {
+	kmsKeyName := settings.KMSKeyName
+	if kmsKeyName == "" {
+		kmsKeyName = cfg.KMSKeyName
+	}
+	return &proxypb.WriteHeader{
+		Uri:                   destURI,
+		ContentType:           settings.ContentType,
+		Metadata:              settings.Metadata,
+		NoClobber:             settings.NoClobber || cfg.NoClobber,
+		IfGenerationMatch:     settings.IfGenerationMatch,
+		IfMetagenerationMatch: settings.IfMetagenerationMatch,
+		CacheControl:          settings.CacheControl,
+		ContentEncoding:       settings.ContentEncoding,
+		ContentDisposition:    settings.ContentDisposition,
+		ContentLanguage:       settings.ContentLanguage,
+		KmsKeyName:            kmsKeyName,
+		Gzip:                  settings.Gzip,
+		Verify:                settings.Verify,
+		VerifyMd5:             settings.VerifyMD5,
+	}
+}
+
+// closeWrite は、書き込みのストリームを閉じて、プロキシの書き込みの結果を返します。
+// 検証を指定した書き込みでは、プロキシが計算したチェックサムを settings.VerifySums に格納します。
+func (c *Client) closeWrite(stream grpc.ClientStreamingClient[proxypb.WriteRequest, proxypb.WriteResponse], settings remoteio.WriteSettings) error {
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return fromStatus(err)
+	}
+	if sums := resp.GetChecksums(); sums != nil && settings.VerifySums != nil {
+		*settings.VerifySums = remoteio.Checksums{Size: sums.GetSize(), CRC32C: sums.GetCrc32C(), MD5: sums.GetMd5()}
+	}
+	return nil
+}
+
+// isLocal は、path がプロキシを経由しないローカルファイルのパスかどうかを判定します。
+func isLocal(path string) bool {
+	return remoteio.SchemeOf(path) == ""
+}
+
+// =================================================================
+// 3. 内部ヘルパー
+// =================================================================
+
+// chunkReader は、プロキシから受信したチャンクを順に読み込む io.ReadCloser です。
+type chunkReader struct {
+	stream grpc.ServerStreamingClient[proxypb.Chunk]
+	buf    []byte // 受信したチャンクのうち、まだ読み込んでいない部分
+	cancel context.CancelFunc
+	err    error // 受信が終了した場合のエラー (終端の場合は io.EOF)
+}
+
+// Read は io.Reader インターフェースを実装します。
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		chunk, err := r.stream.Recv()
+		if err == io.EOF {
+			r.err = io.EOF
+			continue
+		}
+		if err != nil {
+			r.err = fromStatus(err)
+			continue
+		}
+		r.buf = chunk.GetData()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// Close は io.Closer インターフェースを実装します。読み込みの途中で閉じた場合は、ストリームをキャンセルします。
+func (r *chunkReader) Close() error {
+	r.cancel()
+	return nil
+}
+
+// readerAt は、プロキシ経由の範囲読み込みでランダムアクセスする remoteio.ReadAtCloser です。
+type readerAt struct {
+	ctx  context.Context
+	c    *Client
+	uri  string
+	size int64
+}
+
+// ReadAt は io.ReaderAt インターフェースを実装します。
+func (r *readerAt) ReadAt(p []byte, off int64) (int, error) {
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	rc, err := r.c.OpenRange(r.ctx, r.uri, off, int64(len(p)))
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+	n, err := io.ReadFull(rc, p)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		// 終端より先を要求した場合
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Size は ReadAtCloser インターフェースを実装します。
+func (r *readerAt) Size() int64 {
+	return r.size
+}
+
+// Close は io.Closer インターフェースを実装します。
+func (r *readerAt) Close() error {
+	return nil
+}
+
+// 型アサーションチェック
+var (
+	_ remoteio.InputReader      = (*Client)(nil)
+	_ remoteio.RangeInputReader = (*Client)(nil)
+	_ remoteio.Stater           = (*Client)(nil)
+	_ remoteio.ObjectLister     = (*Client)(nil)
+	_ remoteio.OutputWriter     = (*Client)(nil)
+)
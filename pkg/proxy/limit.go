@@ -0,0 +1,217 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/shouni/go-remote-io/pkg/proxy/proxypb"
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// idleClientTTL は、リクエストを処理していないクライアントの上限の状態を保持する時間です。
+// 接続元の IP アドレスで識別する場合に、状態が際限なく増えないよう、これより長く使用されていない状態は破棄します。
+const idleClientTTL = 10 * time.Minute
+
+// ClientLimits は、WithClientLimits で指定する、クライアントごとの利用の上限です。0 の項目は制限しません。
+// クライアントは、WithRequiredToken を指定した場合は送信されたトークンで、指定しない場合は接続元の IP アドレスで識別します。
+type ClientLimits struct {
+	// MaxConcurrent は、1つのクライアントのリクエストを同時に処理する数の上限です。
+	// 超えたリクエストは、同じクライアントの処理中のリクエストが終わるまで待ちます。
+	MaxConcurrent int
+	// BytesPerSecond は、1つのクライアントの読み込み (Open) と書き込み (Write) の合計の帯域 (バイト/秒) の上限です。
+	BytesPerSecond int64
+	// RequestsPerMinute は、1つのクライアントが1分間に送信できるリクエストの数の上限 (クォータ) です。
+	// 超えたリクエストは RESOURCE_EXHAUSTED で拒否します。
+	RequestsPerMinute int
+}
+
+// WithClientLimits は、クライアントごとに同時実行数、帯域とリクエストの数を制限します。
+// 1つのクライアント (テナント) の大量の読み込みが、プロキシを占有しないようにするために使用します。
+func WithClientLimits(limits ClientLimits) ServerOption {
+	return func(s *Server) {
+		s.limits = limits
+	}
+}
+
+// enabled は、いずれかの上限が指定されたかどうかを返します。
+func (l ClientLimits) enabled() bool {
+	return l.MaxConcurrent > 0 || l.BytesPerSecond > 0 || l.RequestsPerMinute > 0
+}
+
+// clientLimiter は、1つのクライアントの上限を適用するセマフォとレートリミッターです。nil の項目は制限しません。
+type clientLimiter struct {
+	slots    *semaphore.Weighted
+	bytes    *rate.Limiter
+	requests *rate.Limiter
+
+	active   int       // 処理中のリクエストの数 (limiters.mu で保護する)
+	lastUsed time.Time // 最後のリクエストが終わった時刻 (limiters.mu で保護する)
+}
+
+// limiters は、クライアントごとの clientLimiter を管理し、gRPC のインターセプターで上限を適用します。
+type limiters struct {
+	limits  ClientLimits
+	byToken bool // トークンでクライアントを識別する (false の場合は接続元の IP アドレス)
+
+	mu      sync.Mutex
+	clients map[string]*clientLimiter
+}
+
+// newLimiters は、limits を適用する limiters を作成します。
+func newLimiters(limits ClientLimits, byToken bool) *limiters {
+	return &limiters{limits: limits, byToken: byToken, clients: make(map[string]*clientLimiter)}
+}
+
+// unary は、単項のリクエストにクライアントの同時実行数とリクエストの数の上限を適用します。
+func (l *limiters) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	_, release, err := l.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return handler(ctx, req)
+}
+
+// stream は、ストリームのリクエストにクライアントの上限を適用し、送受信する内容の帯域を制限します。
+func (l *limiters) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	c, release, err := l.acquire(ss.Context())
+	if err != nil {
+		return err
+	}
+	defer release()
+	if c.bytes != nil {
+		ss = &limitedStream{ServerStream: ss, bytes: c.bytes}
+	}
+	return handler(srv, ss)
+}
+
+// acquire は、ctx のリクエストのクライアントのリクエストの数を数え、同時実行の枠を確保します。
+// 処理の終了時に呼び出す関数を返します。クォータを超えた場合は RESOURCE_EXHAUSTED を返します。
+func (l *limiters) acquire(ctx context.Context) (*clientLimiter, func(), error) {
+	key := l.clientKey(ctx)
+	c := l.get(key)
+	done := func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		c.active--
+		c.lastUsed = time.Now()
+	}
+	if c.requests != nil && !c.requests.Allow() {
+		done()
+		return nil, nil, status.Error(codes.ResourceExhausted, fmt.Sprintf(remoteio.Message("クライアントのリクエストの数が上限 (1分あたり %d 件) を超えました"), l.limits.RequestsPerMinute))
+	}
+	if c.slots != nil {
+		if err := c.slots.Acquire(ctx, 1); err != nil {
+			done()
+			return nil, nil, toStatus(err)
+		}
+	}
+	return c, func() {
+		if c.slots != nil {
+			c.slots.Release(1)
+		}
+		done()
+	}, nil
+}
+
+// get は、key のクライアントの clientLimiter を、処理中のリクエストに数えて返します。
+// 新しいクライアントの場合は作成し、併せて idleClientTTL より長く使用されていないクライアントの状態を破棄します。
+func (l *limiters) get(key string) *clientLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c, ok := l.clients[key]
+	if !ok {
+		now := time.Now()
+		for k, idle := range l.clients {
+			if idle.active == 0 && now.Sub(idle.lastUsed) > idleClientTTL {
+				delete(l.clients, k)
+			}
+		}
+		c = l.newClientLimiter()
+		l.clients[key] = c
+	}
+	c.active++
+	return c
+}
+
+// newClientLimiter は、l.limits を適用する clientLimiter を作成します。
+func (l *limiters) newClientLimiter() *clientLimiter {
+	c := &clientLimiter{}
+	if n := l.limits.MaxConcurrent; n > 0 {
+		c.slots = semaphore.NewWeighted(int64(n))
+	}
+	if bps := l.limits.BytesPerSecond; bps > 0 {
+		// 1回で待つバイト数の上限 (バースト) は、1秒分か1メッセージ分の大きい方にする
+		c.bytes = rate.NewLimiter(rate.Limit(bps), int(max(bps, chunkSize)))
+	}
+	if rpm := l.limits.RequestsPerMinute; rpm > 0 {
+		c.requests = rate.NewLimiter(rate.Every(time.Minute/time.Duration(rpm)), rpm)
+	}
+	return c
+}
+
+// clientKey は、ctx のリクエストのクライアントを識別するキーを返します。
+// トークンで識別する場合は認証済みのトークン、それ以外は接続元の IP アドレス (取得できない場合はアドレス全体) です。
+func (l *limiters) clientKey(ctx context.Context) string {
+	if l.byToken {
+		return "token:" + requestToken(ctx)
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "peer:"
+	}
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return "peer:" + host
+	}
+	return "peer:" + addr
+}
+
+// limitedStream は、送受信する内容のバイト数に応じて、クライアントの帯域の上限まで待つ grpc.ServerStream です。
+type limitedStream struct {
+	grpc.ServerStream
+	bytes *rate.Limiter
+}
+
+// SendMsg は、読み込んだ内容 (proxypb.Chunk) を送信する前に、帯域の上限まで待ちます。
+func (s *limitedStream) SendMsg(m any) error {
+	if chunk, ok := m.(*proxypb.Chunk); ok {
+		if err := s.wait(len(chunk.GetData())); err != nil {
+			return err
+		}
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+// RecvMsg は、書き込む内容 (proxypb.WriteRequest) を受信した後に、帯域の上限まで待ちます。
+func (s *limitedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if req, ok := m.(*proxypb.WriteRequest); ok {
+		return s.wait(len(req.GetData()))
+	}
+	return nil
+}
+
+// wait は、n バイトを送受信できるまで待ちます。バーストより大きい場合は、バーストずつに分けて待ちます。
+func (s *limitedStream) wait(n int) error {
+	for n > 0 {
+		step := min(n, s.bytes.Burst())
+		if err := s.bytes.WaitN(s.Context(), step); err != nil {
+			return toStatus(err)
+		}
+		n -= step
+	}
+	return nil
+}
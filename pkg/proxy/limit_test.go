@@ -0,0 +1,146 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/shouni/go-remote-io/pkg/remoteiotest"
+)
+
+// blockingReader は、release が閉じられるまで Open を待たせる remoteio.InputReader です。
+type blockingReader struct {
+	*remoteiotest.Reader
+	started chan struct{}
+	release chan struct{}
+}
+
+func (r *blockingReader) Open(ctx context.Context, uri string) (io.ReadCloser, error) {
+	r.started <- struct{}{}
+	<-r.release
+	return r.Reader.Open(ctx, uri)
+}
+
+func TestClientLimitsRequestQuota(t *testing.T) {
+	store := remoteiotest.NewStore()
+	store.Put("gs://bucket/a.txt", []byte("x"))
+	srv := NewServer(remoteiotest.NewReader(store), remoteiotest.NewWriter(store),
+		WithRequiredToken("tenant-a", "tenant-b"), WithClientLimits(ClientLimits{RequestsPerMinute: 2}))
+	dial := serveProxy(t, srv)
+	a := NewClient(dial(WithToken("tenant-a")))
+	b := NewClient(dial(WithToken("tenant-b")))
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		client   *Client
+		wantCode codes.Code
+	}{
+		{name: "1件目", client: a, wantCode: codes.OK},
+		{name: "2件目", client: a, wantCode: codes.OK},
+		{name: "上限を超えたリクエスト", client: a, wantCode: codes.ResourceExhausted},
+		{name: "別のトークンのクライアント", client: b, wantCode: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.client.Stat(ctx, "gs://bucket/a.txt")
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("Stat() = %v, want %v", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestClientLimitsMaxConcurrent(t *testing.T) {
+	store := remoteiotest.NewStore()
+	store.Put("gs://bucket/a.txt", []byte("x"))
+	reader := &blockingReader{Reader: remoteiotest.NewReader(store), started: make(chan struct{}, 1), release: make(chan struct{})}
+	srv := NewServer(reader, remoteiotest.NewWriter(store),
+		WithRequiredToken("tenant-a", "tenant-b"), WithClientLimits(ClientLimits{MaxConcurrent: 1}))
+	dial := serveProxy(t, srv)
+	a := NewClient(dial(WithToken("tenant-a")))
+	b := NewClient(dial(WithToken("tenant-b")))
+
+	// tenant-a の読み込みで、同時実行の枠を使い切る
+	opened := make(chan error, 1)
+	go func() {
+		rc, err := a.Open(context.Background(), "gs://bucket/a.txt")
+		if err == nil {
+			_, err = io.ReadAll(rc)
+			rc.Close()
+		}
+		opened <- err
+	}()
+	<-reader.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := a.Stat(ctx, "gs://bucket/a.txt"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("同じクライアントの Stat() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if _, err := b.Stat(context.Background(), "gs://bucket/a.txt"); err != nil {
+		t.Errorf("別のクライアントの Stat() = %v", err)
+	}
+
+	close(reader.release)
+	if err := <-opened; err != nil {
+		t.Fatalf("Open() = %v", err)
+	}
+	if _, err := a.Stat(context.Background(), "gs://bucket/a.txt"); err != nil {
+		t.Errorf("読み込みの終了後の Stat() = %v", err)
+	}
+}
+
+func TestClientLimitsBandwidth(t *testing.T) {
+	const bps = 4 * chunkSize
+	data := bytes.Repeat([]byte("x"), 5*chunkSize) // バースト (1秒分) を 1 チャンク超える
+	store := remoteiotest.NewStore()
+	store.Put("gs://bucket/big.bin", data)
+	srv := NewServer(remoteiotest.NewReader(store), remoteiotest.NewWriter(store), WithClientLimits(ClientLimits{BytesPerSecond: bps}))
+	client := NewClient(startProxy(t, srv))
+	ctx := context.Background()
+
+	start := time.Now()
+	rc, err := client.Open(ctx, "gs://bucket/big.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("Open() = %d バイト, %v", len(got), err)
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("読み込みの所要時間 = %v, want 帯域の上限により 250ms 程度", elapsed)
+	}
+
+	start = time.Now()
+	if err := client.Write(ctx, "gs://bucket/copy.bin", bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("書き込みの所要時間 = %v, want 読み込みと合計した帯域の上限により 1.25s 程度", elapsed)
+	}
+}
+
+func TestLimitersPruneIdleClients(t *testing.T) {
+	l := newLimiters(ClientLimits{MaxConcurrent: 1}, false)
+	busy := l.get("peer:10.0.0.1")
+	idle := l.get("peer:10.0.0.2")
+	busy.lastUsed = time.Now().Add(-2 * idleClientTTL) // 処理中のため破棄しない
+	idle.active, idle.lastUsed = 0, time.Now().Add(-2*idleClientTTL)
+
+	l.get("peer:10.0.0.3")
+	if _, ok := l.clients["peer:10.0.0.2"]; ok {
+		t.Error("使用されていないクライアントの状態が破棄されませんでした")
+	}
+	if _, ok := l.clients["peer:10.0.0.1"]; !ok {
+		t.Error("処理中のクライアントの状態が破棄されました")
+	}
+}
@@ -0,0 +1,178 @@
+// Package proxy は、GCS などの認証情報を持つプロセス (踏み台) を経由してリモートの URI を読み書きするための、
+// gRPC のサーバー (Server) と、それを呼び出す InputReader / OutputWriter の実装 (Client) を提供します。
+// 認証情報を配置できないマシンでも、Client を使用すると remoteio と同じインターフェースでリモートの URI を読み書きできます。
+package proxy
+
+//go:generate protoc --proto_path=proxypb --go_out=proxypb --go_opt=paths=source_relative --go-grpc_out=proxypb --go-grpc_opt=paths=source_relative proxy.proto
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/shouni/go-remote-io/pkg/proxy/proxypb"
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// TokenEnv は、プロキシの認証に使用する共有トークンを指定する環境変数です。
+// コマンドライン引数に秘密情報を残さないよう、CLI ではフラグではなく環境変数で受け取ります。
+const TokenEnv = "REMOTEIO_PROXY_TOKEN"
+
+// chunkSize は、読み込み・書き込みの内容を分割して送信する1メッセージあたりのバイト数です。
+// gRPC の既定の最大受信サイズ (4MiB) より十分に小さくします。
+const chunkSize = 256 * 1024
+
+// authorizationKey は、共有トークンを送信する gRPC のメタデータのキーです。
+const authorizationKey = "authorization"
+
+// ErrUnsupportedOption は、プロキシ経由の書き込みに、プロキシへ中継できないオプション (バリデータなど) が指定されたことを示します。
+// 指定されたオプションを無視して書き込まないよう、Client.Write は書き込みを開始せずにこのエラーを返します。
+var ErrUnsupportedOption = remoteio.NewMessageError("proxy: プロキシ経由の書き込みでは使用できないオプションです")
+
+// =================================================================
+// 1. エラーの変換
+// =================================================================
+
+// kindCodes は、remoteio のエラーの分類と gRPC のステータスコードの対応です。
+var kindCodes = []struct {
+	kind error
+	code codes.Code
+}{
+	{remoteio.ErrNotFound, codes.NotFound},
+	{remoteio.ErrPermissionDenied, codes.PermissionDenied},
+	{remoteio.ErrInvalidURI, codes.InvalidArgument},
+	{remoteio.ErrAlreadyExists, codes.AlreadyExists},
+}
+
+// toStatus は、サーバーの処理のエラー err を、分類 (remoteio.KindOf) に対応するステータスコードの gRPC のエラーにします。
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	kind := remoteio.KindOf(err)
+	for _, kc := range kindCodes {
+		if kind == kc.kind {
+			return status.Error(kc.code, err.Error())
+		}
+	}
+	return status.Error(codes.Unknown, err.Error())
+}
+
+// fromStatus は、プロキシが返した gRPC のエラー err を、ステータスコードに対応する分類を含む remoteio.Error にします。
+// errors.Is(err, remoteio.ErrNotFound) などで、プロキシを経由しない場合と同じように判定できます。
+func fromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || err == nil {
+		return err
+	}
+	switch st.Code() {
+	case codes.Canceled:
+		return context.Canceled
+	case codes.DeadlineExceeded:
+		return context.DeadlineExceeded
+	case codes.Unauthenticated:
+		return &remoteio.Error{Kind: remoteio.ErrPermissionDenied, Err: err}
+	}
+	for _, kc := range kindCodes {
+		if st.Code() == kc.code {
+			return &remoteio.Error{Kind: kc.kind, Err: errors.New(st.Message())}
+		}
+	}
+	return err
+}
+
+// =================================================================
+// 2. ObjectInfo の変換
+// =================================================================
+
+// toProtoInfo は、remoteio.ObjectInfo を gRPC のメッセージにします。
+func toProtoInfo(info remoteio.ObjectInfo) *proxypb.ObjectInfo {
+	msg := &proxypb.ObjectInfo{
+		Uri:             info.URI,
+		Name:            info.Name,
+		Size:            info.Size,
+		Crc32C:          info.CRC32C,
+		StorageClass:    info.StorageClass,
+		IsPrefix:        info.IsPrefix,
+		ContentType:     info.ContentType,
+		ContentEncoding: info.ContentEncoding,
+		Md5:             info.MD5,
+		Generation:      info.Generation,
+		Metageneration:  info.Metageneration,
+		Metadata:        info.Metadata,
+		KmsKeyName:      info.KMSKeyName,
+	}
+	if !info.Updated.IsZero() {
+		msg.Updated = timestamppb.New(info.Updated)
+	}
+	return msg
+}
+
+// fromProtoInfo は、gRPC のメッセージを remoteio.ObjectInfo にします。
+func fromProtoInfo(msg *proxypb.ObjectInfo) remoteio.ObjectInfo {
+	info := remoteio.ObjectInfo{
+		URI:             msg.GetUri(),
+		Name:            msg.GetName(),
+		Size:            msg.GetSize(),
+		CRC32C:          msg.Crc32C,
+		StorageClass:    msg.GetStorageClass(),
+		IsPrefix:        msg.GetIsPrefix(),
+		ContentType:     msg.GetContentType(),
+		ContentEncoding: msg.GetContentEncoding(),
+		MD5:             msg.GetMd5(),
+		Generation:      msg.GetGeneration(),
+		Metageneration:  msg.GetMetageneration(),
+		Metadata:        msg.GetMetadata(),
+		KMSKeyName:      msg.GetKmsKeyName(),
+	}
+	if msg.GetUpdated() != nil {
+		info.Updated = msg.GetUpdated().AsTime().In(time.Local)
+	}
+	return info
+}
+
+// =================================================================
+// 3. 共有トークンによる認証
+// =================================================================
+
+// tokenCredentials は、共有トークンを gRPC のメタデータで送信する credentials.PerRPCCredentials です。
+type tokenCredentials struct {
+	token      string
+	requireTLS bool
+}
+
+// GetRequestMetadata は credentials.PerRPCCredentials インターフェースを実装します。
+func (c tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{authorizationKey: "Bearer " + c.token}, nil
+}
+
+// RequireTransportSecurity は credentials.PerRPCCredentials インターフェースを実装します。
+func (c tokenCredentials) RequireTransportSecurity() bool {
+	return c.requireTLS
+}
+
+// requestToken は、ctx の gRPC のメタデータから、クライアントが送信した共有トークンを取り出します。
+func requestToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get(authorizationKey) {
+		if token, ok := strings.CutPrefix(v, "Bearer "); ok {
+			return token
+		}
+	}
+	return ""
+}
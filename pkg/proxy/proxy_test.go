@@ -0,0 +1,250 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/shouni/go-remote-io/pkg/remoteiotest"
+)
+
+// startProxy は、srv をインプロセスの bufconn で起動し、そのプロキシへ接続するクライアントの接続を返します。
+func startProxy(t *testing.T, srv *Server, opts ...DialOption) *grpc.ClientConn {
+	t.Helper()
+	return serveProxy(t, srv)(opts...)
+}
+
+// serveProxy は、srv をインプロセスの bufconn で起動し、同じプロキシへ接続するクライアントの接続を作成する関数を返します。
+func serveProxy(t *testing.T, srv *Server) func(opts ...DialOption) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	gs := srv.NewGRPCServer()
+	go gs.Serve(lis)
+	t.Cleanup(gs.Stop)
+
+	dialer := func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}
+	return func(opts ...DialOption) *grpc.ClientConn {
+		t.Helper()
+		conn, err := Dial("passthrough:///bufnet", append(opts, WithGRPCDialOptions(grpc.WithContextDialer(dialer)))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+}
+
+// recordingWriter は、受け取った書き込みの設定を記録する remoteio.OutputWriter です。
+type recordingWriter struct {
+	mu       sync.Mutex
+	settings remoteio.WriteSettings
+	data     []byte
+}
+
+func (w *recordingWriter) Write(ctx context.Context, destURI string, r io.Reader, opts ...remoteio.WriteOption) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	settings := remoteio.ResolveWriteOptions(opts...)
+	if settings.VerifySums != nil {
+		*settings.VerifySums = remoteio.Checksums{Size: int64(len(data)), CRC32C: 42}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.settings, w.data = settings, data
+	return nil
+}
+
+func TestClientReadWrite(t *testing.T) {
+	store := remoteiotest.NewStore()
+	store.Put("gs://bucket/in.txt", []byte("hello"))
+	conn := startProxy(t, NewServer(remoteiotest.NewReader(store), remoteiotest.NewWriter(store)))
+	client := NewClient(conn)
+	ctx := context.Background()
+
+	rc, err := client.Open(ctx, "gs://bucket/in.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil || string(got) != "hello" {
+		t.Fatalf("Open() = %q, %v, want %q", got, err, "hello")
+	}
+
+	if err := client.Write(ctx, "gs://bucket/out.txt", strings.NewReader("world"), remoteio.WithContentType("text/x-test")); err != nil {
+		t.Fatal(err)
+	}
+	obj, ok := store.Get("gs://bucket/out.txt")
+	if !ok || string(obj.Data) != "world" || obj.ContentType != "text/x-test" {
+		t.Fatalf("書き込み先 = %+v, %v", obj, ok)
+	}
+
+	err = client.Write(ctx, "gs://bucket/out.txt", strings.NewReader("again"), remoteio.WithWriteNoClobber())
+	if !errors.Is(err, remoteio.ErrAlreadyExists) {
+		t.Errorf("Write(WithWriteNoClobber) = %v, want %v", err, remoteio.ErrAlreadyExists)
+	}
+}
+
+func TestClientWriteForwardsOptions(t *testing.T) {
+	w := &recordingWriter{}
+	conn := startProxy(t, NewServer(remoteiotest.NewReader(remoteiotest.NewStore()), w))
+	client := NewClient(conn, remoteio.WithKMSKeyName("projects/p/locations/l/keyRings/r/cryptoKeys/k"))
+
+	var sums remoteio.Checksums
+	err := client.Write(context.Background(), "gs://bucket/obj", strings.NewReader("content"),
+		remoteio.WithIfMetagenerationMatch(3),
+		remoteio.WithCacheControl("no-store"),
+		remoteio.WithContentDisposition("attachment"),
+		remoteio.WithContentLanguage("ja"),
+		remoteio.WithGzip(),
+		remoteio.WithVerify(&sums, true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := w.settings
+	if got.IfMetagenerationMatch == nil || *got.IfMetagenerationMatch != 3 {
+		t.Errorf("IfMetagenerationMatch = %v, want 3", got.IfMetagenerationMatch)
+	}
+	if got.CacheControl != "no-store" || got.ContentDisposition != "attachment" || got.ContentLanguage != "ja" {
+		t.Errorf("ヘッダー = %q, %q, %q", got.CacheControl, got.ContentDisposition, got.ContentLanguage)
+	}
+	if got.KMSKeyName != "projects/p/locations/l/keyRings/r/cryptoKeys/k" {
+		t.Errorf("KMSKeyName = %q", got.KMSKeyName)
+	}
+	if !got.Gzip || !got.Verify || !got.VerifyMD5 {
+		t.Errorf("Gzip, Verify, VerifyMD5 = %v, %v, %v, want true", got.Gzip, got.Verify, got.VerifyMD5)
+	}
+	if sums.Size != int64(len("content")) || sums.CRC32C != 42 {
+		t.Errorf("チェックサム = %+v", sums)
+	}
+}
+
+func TestClientWriteRejectsValidators(t *testing.T) {
+	w := &recordingWriter{}
+	conn := startProxy(t, NewServer(remoteiotest.NewReader(remoteiotest.NewStore()), w))
+	client := NewClient(conn, remoteio.WithValidators(remoteio.MaxSize(1)))
+
+	err := client.Write(context.Background(), "gs://bucket/obj", strings.NewReader("content"))
+	if !errors.Is(err, ErrUnsupportedOption) {
+		t.Fatalf("Write() = %v, want %v", err, ErrUnsupportedOption)
+	}
+	if w.data != nil {
+		t.Errorf("プロキシへ書き込まれました: %q", w.data)
+	}
+}
+
+func TestServerAuthorization(t *testing.T) {
+	store := remoteiotest.NewStore()
+	store.Put("gs://bucket/in.txt", []byte("hello"))
+	srv := NewServer(remoteiotest.NewReader(store), remoteiotest.NewWriter(store), WithRequiredToken("secret", "tenant-b"))
+
+	tests := []struct {
+		name    string
+		opts    []DialOption
+		wantErr error
+	}{
+		{name: "トークンなし", wantErr: remoteio.ErrPermissionDenied},
+		{name: "トークンの不一致", opts: []DialOption{WithToken("wrong")}, wantErr: remoteio.ErrPermissionDenied},
+		{name: "トークンの一致", opts: []DialOption{WithToken("secret")}},
+		{name: "2つ目のトークンの一致", opts: []DialOption{WithToken("tenant-b")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(startProxy(t, srv, tt.opts...))
+			_, err := client.Stat(context.Background(), "gs://bucket/in.txt")
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Stat() = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Stat() = %v, want %v", err, tt.wantErr)
+			}
+			err = client.Write(context.Background(), "gs://bucket/out.txt", strings.NewReader("x"))
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Write() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServerAllowedPrefixes(t *testing.T) {
+	store := remoteiotest.NewStore()
+	for _, uri := range []string{"gs://bucket/data/a.txt", "gs://bucket-other/a.txt", "gs://secret/a.txt", "s3://bucket/a.txt"} {
+		store.Put(uri, []byte("x"))
+	}
+	srv := NewServer(remoteiotest.NewReader(store), remoteiotest.NewWriter(store), WithAllowedPrefixes("gs://bucket/data/", "s3://"))
+	client := NewClient(startProxy(t, srv))
+
+	tests := []struct {
+		uri     string
+		wantErr error
+	}{
+		{uri: "gs://bucket/data/a.txt"},
+		{uri: "s3://bucket/a.txt"},
+		{uri: "gs://bucket-other/a.txt", wantErr: remoteio.ErrPermissionDenied},
+		{uri: "gs://secret/a.txt", wantErr: remoteio.ErrPermissionDenied},
+		{uri: "gs://bucket/data/../../secret/a.txt", wantErr: remoteio.ErrInvalidURI},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			rc, err := client.Open(context.Background(), tt.uri)
+			if err == nil {
+				rc.Close()
+			}
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Open() = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Open() = %v, want %v", err, tt.wantErr)
+			}
+			err = client.Write(context.Background(), tt.uri, bytes.NewReader([]byte("y")))
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Write() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServerRejectsLocalPaths(t *testing.T) {
+	srv := NewServer(remoteiotest.NewReader(remoteiotest.NewStore()), &recordingWriter{})
+	_, err := srv.Stat(context.Background(), nil)
+	if !errors.Is(fromStatus(err), remoteio.ErrInvalidURI) {
+		t.Fatalf("Stat(\"\") = %v, want %v", err, remoteio.ErrInvalidURI)
+	}
+	if err := srv.checkURI("/etc/passwd"); !errors.Is(fromStatus(err), remoteio.ErrInvalidURI) {
+		t.Fatalf("checkURI(\"/etc/passwd\") = %v, want %v", err, remoteio.ErrInvalidURI)
+	}
+}
+
+func TestMatchPrefix(t *testing.T) {
+	tests := []struct {
+		uri, prefix string
+		want        bool
+	}{
+		{"gs://bucket/a", "gs://", true},
+		{"gs://bucket/a", "gs://bucket", true},
+		{"gs://bucket", "gs://bucket", true},
+		{"gs://bucket-other/a", "gs://bucket", false},
+		{"gs://bucket/a", "gs://bucket/", true},
+		{"gs://bucket/ab", "gs://bucket/a/", false},
+		{"s3://bucket/a", "gs://", false},
+	}
+	for _, tt := range tests {
+		if got := matchPrefix(tt.uri, tt.prefix); got != tt.want {
+			t.Errorf("matchPrefix(%q, %q) = %v, want %v", tt.uri, tt.prefix, got, tt.want)
+		}
+	}
+}
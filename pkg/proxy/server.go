@@ -0,0 +1,334 @@
+package proxy
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/shouni/go-remote-io/pkg/proxy/proxypb"
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// ServerOption は、Server の構成を変更する関数型オプションです。
+type ServerOption func(*Server)
+
+// WithRequiredToken は、クライアントに tokens のいずれかのトークンの送信を要求します (クライアントは WithToken で指定します)。
+// いずれにも一致しないリクエストは UNAUTHENTICATED で拒否します。トークンを盗聴されないよう、TLS と併せて使用してください。
+// クライアント (テナント) ごとに異なるトークンを指定すると、WithClientLimits の上限をトークンごとに適用します。
+func WithRequiredToken(tokens ...string) ServerOption {
+	return func(s *Server) {
+		for _, token := range tokens {
+			if token != "" {
+				s.tokens = append(s.tokens, token)
+			}
+		}
+	}
+}
+
+// WithAllowedPrefixes は、読み書きを許可する URI を、スキーム (例: "gs://") またはバケット・ホストなどの接頭辞
+// (例: "gs://my-bucket/"、"sftp://files.example.com/data/") に制限します。いずれにも一致しない URI は PERMISSION_DENIED で拒否します。
+// 末尾が "/" ではない接頭辞 (例: "gs://my-bucket") は、その URI 自身と、その下 ("gs://my-bucket/...") に一致します。
+// 省略時は、プロキシの認証情報でアクセスできるすべてのリモートの URI を許可します。
+func WithAllowedPrefixes(prefixes ...string) ServerOption {
+	return func(s *Server) {
+		s.allowed = append(s.allowed, prefixes...)
+	}
+}
+
+// Server は、proxypb.RemoteIOServer を実装し、クライアントの代わりに reader と writer でリモートの URI を読み書きするプロキシです。
+// プロキシを実行するマシンのファイルを公開しないよう、ローカルファイルのパスは INVALID_ARGUMENT で拒否します。
+type Server struct {
+	proxypb.UnimplementedRemoteIOServer
+
+	reader  remoteio.InputReader
+	writer  remoteio.OutputWriter
+	tokens  []string     // 空の場合は認証しない
+	allowed []string     // 空の場合はすべてのリモートの URI を許可する
+	limits  ClientLimits // クライアントごとの上限 (ゼロ値の場合は制限しない)
+}
+
+// NewServer は、reader と writer (通常は factory.Factory が生成したもの) で読み書きする Server を作成します。
+// Open の範囲指定には reader が remoteio.RangeInputReader を、Stat と List にはそれぞれ remoteio.Stater と remoteio.ObjectLister を満たす必要があります。
+func NewServer(reader remoteio.InputReader, writer remoteio.OutputWriter, opts ...ServerOption) *Server {
+	s := &Server{reader: reader, writer: writer}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// NewGRPCServer は、s を登録し、WithRequiredToken を指定した場合は認証を、WithClientLimits を指定した場合はクライアントごとの上限の適用を行う
+// grpc.Server を作成します。opts で TLS の資格情報 (grpc.Creds) などを指定できます。
+func (s *Server) NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	// 認証に失敗したリクエストをクォータに数えないよう、認証してから上限を適用する
+	if len(s.tokens) > 0 {
+		opts = append(opts, grpc.ChainUnaryInterceptor(s.authorizeUnary), grpc.ChainStreamInterceptor(s.authorizeStream))
+	}
+	if s.limits.enabled() {
+		l := newLimiters(s.limits, len(s.tokens) > 0)
+		opts = append(opts, grpc.ChainUnaryInterceptor(l.unary), grpc.ChainStreamInterceptor(l.stream))
+	}
+	gs := grpc.NewServer(opts...)
+	proxypb.RegisterRemoteIOServer(gs, s)
+	return gs
+}
+
+// Open は proxypb.RemoteIOServer インターフェースを実装します。
+func (s *Server) Open(req *proxypb.OpenRequest, stream grpc.ServerStreamingServer[proxypb.Chunk]) error {
+	ctx := stream.Context()
+	if err := s.checkURI(req.GetUri()); err != nil {
+		return err
+	}
+	var rc io.ReadCloser
+	var err error
+	if req.GetOffset() == 0 && req.GetLength() < 0 {
+		rc, err = s.reader.Open(ctx, req.GetUri())
+	} else {
+		ranger, ok := s.reader.(remoteio.RangeInputReader)
+		if !ok {
+			return status.Error(codes.Unimplemented, remoteio.Message("プロキシのInputReaderが範囲読み込みをサポートしていません"))
+		}
+		rc, err = ranger.OpenRange(ctx, req.GetUri(), req.GetOffset(), req.GetLength())
+	}
+	if err != nil {
+		return toStatus(err)
+	}
+	defer rc.Close()
+
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := io.ReadFull(rc, buf)
+		if n > 0 {
+			if err := stream.Send(&proxypb.Chunk{Data: buf[:n]}); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if err != nil {
+			return toStatus(err)
+		}
+	}
+}
+
+// Write は proxypb.RemoteIOServer インターフェースを実装します。
+// 受信した内容は受信した順に writer へストリーミングし、すべてを受信してから書き込み先を確定させます。
+func (s *Server) Write(stream grpc.ClientStreamingServer[proxypb.WriteRequest, proxypb.WriteResponse]) error {
+	ctx := stream.Context()
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	header := first.GetHeader()
+	if header == nil {
+		return status.Error(codes.InvalidArgument, remoteio.Message("最初のメッセージで書き込み先を指定してください"))
+	}
+	if err := s.checkURI(header.GetUri()); err != nil {
+		return err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err == io.EOF {
+				pw.Close()
+				return
+			}
+			if err != nil {
+				// クライアントが中止した場合は、書き込み先を確定させない
+				pw.CloseWithError(err)
+				return
+			}
+			if _, err := pw.Write(req.GetData()); err != nil {
+				return
+			}
+		}
+	}()
+	cr := &countingReader{r: pr}
+	opts, sums := writeOptions(header)
+	err = s.writer.Write(ctx, header.GetUri(), cr, opts...)
+	// 書き込みが途中で終了した場合も、受信のゴルーチンを終了させる
+	pr.CloseWithError(errors.New(remoteio.Message("書き込みが終了しました")))
+	if err != nil {
+		return toStatus(err)
+	}
+	resp := &proxypb.WriteResponse{Bytes: cr.n}
+	if sums != nil {
+		resp.Checksums = &proxypb.Checksums{Size: sums.Size, Crc32C: sums.CRC32C, Md5: sums.MD5}
+	}
+	return stream.SendAndClose(resp)
+}
+
+// Stat は proxypb.RemoteIOServer インターフェースを実装します。
+func (s *Server) Stat(ctx context.Context, req *proxypb.StatRequest) (*proxypb.ObjectInfo, error) {
+	if err := s.checkURI(req.GetUri()); err != nil {
+		return nil, err
+	}
+	stater, ok := s.reader.(remoteio.Stater)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, remoteio.Message("プロキシのInputReaderが情報の取得をサポートしていません"))
+	}
+	info, err := stater.Stat(ctx, req.GetUri())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProtoInfo(info), nil
+}
+
+// List は proxypb.RemoteIOServer インターフェースを実装します。
+func (s *Server) List(ctx context.Context, req *proxypb.ListRequest) (*proxypb.ListResponse, error) {
+	if err := s.checkURI(req.GetUri()); err != nil {
+		return nil, err
+	}
+	lister, ok := s.reader.(remoteio.ObjectLister)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, remoteio.Message("プロキシのInputReaderが一覧の取得をサポートしていません"))
+	}
+	var opts []remoteio.ListOption
+	if req.GetDelimiter() != "" {
+		opts = append(opts, remoteio.WithDelimiter(req.GetDelimiter()))
+	}
+	if req.GetPageSize() > 0 {
+		opts = append(opts, remoteio.WithPageSize(int(req.GetPageSize())))
+	}
+	if req.GetPageToken() != "" {
+		opts = append(opts, remoteio.WithPageToken(req.GetPageToken()))
+	}
+	page, err := lister.ListObjectsPage(ctx, req.GetUri(), opts...)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &proxypb.ListResponse{NextPageToken: page.NextPageToken}
+	for _, obj := range page.Objects {
+		resp.Objects = append(resp.Objects, toProtoInfo(obj))
+	}
+	return resp, nil
+}
+
+// authorizeUnary は、共有トークンが一致しない単項のリクエストを拒否します。
+func (s *Server) authorizeUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// authorizeStream は、共有トークンが一致しないストリームのリクエストを拒否します。
+func (s *Server) authorizeStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authorize(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// authorize は、ctx のリクエストのトークンが、WithRequiredToken のいずれかのトークンに一致することを検証します。
+func (s *Server) authorize(ctx context.Context) error {
+	token := []byte(requestToken(ctx))
+	matched := 0
+	for _, want := range s.tokens {
+		matched |= subtle.ConstantTimeCompare(token, []byte(want))
+	}
+	if matched != 1 {
+		return status.Error(codes.Unauthenticated, remoteio.Message("プロキシの認証に失敗しました (トークンが一致しません)"))
+	}
+	return nil
+}
+
+// checkURI は、uri がリモートの URI (スキームを持つ URI) で、WithAllowedPrefixes で許可された接頭辞に一致することを確認します。
+func (s *Server) checkURI(uri string) error {
+	if remoteio.SchemeOf(uri) == "" {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf(remoteio.Message("プロキシはローカルファイルのパスを扱いません: %s"), uri))
+	}
+	if len(s.allowed) == 0 {
+		return nil
+	}
+	// SFTP などでは ".." でパスを遡れるため、接頭辞の外に出られないよう拒否する
+	if slices.Contains(strings.Split(uri, "/"), "..") {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf(remoteio.Message("プロキシは \"..\" を含む URI を扱いません: %s"), uri))
+	}
+	for _, prefix := range s.allowed {
+		if matchPrefix(uri, prefix) {
+			return nil
+		}
+	}
+	return status.Error(codes.PermissionDenied, fmt.Sprintf(remoteio.Message("プロキシで許可されていない URI です: %s"), uri))
+}
+
+// matchPrefix は、uri が許可された接頭辞 prefix に一致するかどうかを返します。
+func matchPrefix(uri, prefix string) bool {
+	if strings.HasSuffix(prefix, "/") {
+		return strings.HasPrefix(uri, prefix)
+	}
+	// "gs://my-bucket" が "gs://my-bucket-other/..." に一致しないよう、区切りを確認する
+	return uri == prefix || strings.HasPrefix(uri, prefix+"/")
+}
+
+// writeOptions は、書き込みのヘッダーを remoteio.WriteOption にします。
+// ヘッダーで検証が指定された場合は、書き込み後にチェックサムが格納される remoteio.Checksums も返します。
+func writeOptions(header *proxypb.WriteHeader) ([]remoteio.WriteOption, *remoteio.Checksums) {
+	var opts []remoteio.WriteOption
+	if header.GetContentType() != "" {
+		opts = append(opts, remoteio.WithContentType(header.GetContentType()))
+	}
+	if len(header.GetMetadata()) > 0 {
+		opts = append(opts, remoteio.WithMetadata(header.GetMetadata()))
+	}
+	if header.GetNoClobber() {
+		opts = append(opts, remoteio.WithWriteNoClobber())
+	}
+	if header.IfGenerationMatch != nil {
+		opts = append(opts, remoteio.WithIfGenerationMatch(header.GetIfGenerationMatch()))
+	}
+	if header.IfMetagenerationMatch != nil {
+		opts = append(opts, remoteio.WithIfMetagenerationMatch(header.GetIfMetagenerationMatch()))
+	}
+	if header.GetCacheControl() != "" {
+		opts = append(opts, remoteio.WithCacheControl(header.GetCacheControl()))
+	}
+	if header.GetContentEncoding() != "" {
+		opts = append(opts, remoteio.WithContentEncoding(header.GetContentEncoding()))
+	}
+	if header.GetContentDisposition() != "" {
+		opts = append(opts, remoteio.WithContentDisposition(header.GetContentDisposition()))
+	}
+	if header.GetContentLanguage() != "" {
+		opts = append(opts, remoteio.WithContentLanguage(header.GetContentLanguage()))
+	}
+	if header.GetKmsKeyName() != "" {
+		opts = append(opts, remoteio.WithWriteKMSKeyName(header.GetKmsKeyName()))
+	}
+	if header.GetGzip() {
+		opts = append(opts, remoteio.WithGzip())
+	}
+	if !header.GetVerify() {
+		return opts, nil
+	}
+	sums := new(remoteio.Checksums)
+	return append(opts, remoteio.WithVerify(sums, header.GetVerifyMd5())), sums
+}
+
+// countingReader は、読み込んだバイト数を数える io.Reader です。
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read は io.Reader インターフェースを実装します。
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// 型アサーションチェック
+var _ proxypb.RemoteIOServer = (*Server)(nil)
@@ -0,0 +1,474 @@
+package remoteio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/spf13/afero"
+	"google.golang.org/api/iterator"
+)
+
+// =================================================================
+// 1. コンストラクタ
+// =================================================================
+
+// NewAferoFs は、ローカルファイルと gs:// URI の両方を扱う afero.Fs を返します。
+// afero を前提に作られたアプリケーションは、この Fs に差し替えるだけで GCS 上のオブジェクトを透過的に扱えます。
+//
+// gs:// URI の場合、Open と Stat は r の GCS クライアントを使用し、Create で開いたファイルへの書き込みは
+// Close の時点で w.OpenWrite を通じて確定されます (バリデータと故障注入も適用されます)。
+// GCS にはディレクトリが存在しないため、Mkdir と MkdirAll は何もせず、"/" 区切りのプレフィックスをディレクトリとして扱います。
+// GCS のファイルは追記やランダム書き込み、読み書き両用のオープン、Chmod / Chown / Chtimes をサポートしません。
+//
+// ローカルファイルパスの場合は afero.NewOsFs() へそのまま委譲します。
+func NewAferoFs(r *LocalGCSInputReader, w *UniversalIOWriter) afero.Fs {
+	return &aferoFs{ctx: context.Background(), r: r, w: w, local: afero.NewOsFs()}
+}
+
+// aferoFs は、パスに応じてローカルファイルシステムと GCS へ処理を振り分ける afero.Fs の実装です。
+type aferoFs struct {
+	ctx   context.Context
+	r     *LocalGCSInputReader
+	w     *UniversalIOWriter
+	local afero.Fs
+}
+
+// =================================================================
+// 2. afero.Fs の実装
+// =================================================================
+
+// Name は afero.Fs インターフェースを実装します。
+func (a *aferoFs) Name() string { return "RemoteIOFs" }
+
+// Create は、書き込み用のファイルを開きます。gs:// URI の場合、内容は Close の時点でオブジェクトとして確定されます。
+func (a *aferoFs) Create(name string) (afero.File, error) {
+	if !IsGCSURI(name) {
+		return a.local.Create(name)
+	}
+	if _, _, err := a.gcsPath("create", name); err != nil {
+		return nil, err
+	}
+	wc, err := a.w.OpenWrite(a.ctx, name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "create", Path: name, Err: err}
+	}
+	return &aferoWriteFile{uri: name, wc: wc}, nil
+}
+
+// Open は、読み込み用のファイルまたはディレクトリを開きます。
+func (a *aferoFs) Open(name string) (afero.File, error) {
+	if !IsGCSURI(name) {
+		return a.local.Open(name)
+	}
+	fsys, objectName, err := a.gcsPath("open", name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := fsys.Open(objectName)
+	if err != nil {
+		return nil, withPath(err, name)
+	}
+	switch f := f.(type) {
+	case *gcsDir:
+		return &aferoDir{gcsDir: f, uri: name}, nil
+	default:
+		return &aferoReadFile{gcsFile: f.(*gcsFile), uri: name}, nil
+	}
+}
+
+// OpenFile は、flag に応じて読み込み用または書き込み用のファイルを開きます。
+// gs:// URI の場合、O_RDWR と O_APPEND はサポートされず、perm は無視されます。
+func (a *aferoFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if !IsGCSURI(name) {
+		return a.local.OpenFile(name, flag, perm)
+	}
+	if flag&(os.O_RDWR|os.O_APPEND) != 0 {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: errors.ErrUnsupported}
+	}
+	if flag&os.O_WRONLY == 0 {
+		return a.Open(name)
+	}
+	if flag&os.O_EXCL != 0 {
+		if _, err := a.Stat(name); err == nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return a.Create(name)
+}
+
+// Stat は、ファイルまたはディレクトリの情報を返します。
+func (a *aferoFs) Stat(name string) (os.FileInfo, error) {
+	if !IsGCSURI(name) {
+		return a.local.Stat(name)
+	}
+	fsys, objectName, err := a.gcsPath("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := fsys.Stat(objectName)
+	if err != nil {
+		return nil, withPath(err, name)
+	}
+	return info, nil
+}
+
+// Remove は、ファイルを削除します。gs:// URI のプレフィックス (ディレクトリ) は削除できません。
+func (a *aferoFs) Remove(name string) error {
+	if !IsGCSURI(name) {
+		return a.local.Remove(name)
+	}
+	fsys, objectName, err := a.gcsPath("remove", name)
+	if err != nil {
+		return err
+	}
+	if err := fsys.bucket.Object(objectName).Delete(a.ctx); err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			err = fs.ErrNotExist
+		}
+		return &fs.PathError{Op: "remove", Path: name, Err: err}
+	}
+	return nil
+}
+
+// RemoveAll は、ファイルとその配下のすべてのファイルを削除します。存在しない場合は何もしません。
+func (a *aferoFs) RemoveAll(name string) error {
+	if !IsGCSURI(name) {
+		return a.local.RemoveAll(name)
+	}
+	fsys, objectName, err := a.gcsPath("removeall", name)
+	if err != nil {
+		return err
+	}
+
+	prefix := ""
+	if objectName != "." {
+		prefix = objectName + "/"
+		if err := fsys.bucket.Object(objectName).Delete(a.ctx); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			return &fs.PathError{Op: "removeall", Path: name, Err: err}
+		}
+	}
+	it := fsys.bucket.Objects(a.ctx, &storage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return &fs.PathError{Op: "removeall", Path: name, Err: err}
+		}
+		if err := fsys.bucket.Object(attrs.Name).Delete(a.ctx); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			return &fs.PathError{Op: "removeall", Path: name, Err: err}
+		}
+	}
+}
+
+// Rename は、ファイルの名前を変更します。
+// gs:// URI の場合は同じプロジェクト内のオブジェクト同士のみサポートし、サーバー側でコピーしてから元のオブジェクトを削除します。
+func (a *aferoFs) Rename(oldname, newname string) error {
+	if !IsGCSURI(oldname) && !IsGCSURI(newname) {
+		return a.local.Rename(oldname, newname)
+	}
+	if !IsGCSURI(oldname) || !IsGCSURI(newname) {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: errors.ErrUnsupported}
+	}
+
+	src, srcName, err := a.gcsPath("rename", oldname)
+	if err != nil {
+		return err
+	}
+	dst, dstName, err := a.gcsPath("rename", newname)
+	if err != nil {
+		return err
+	}
+	srcObj := src.bucket.Object(srcName)
+	if _, err := dst.bucket.Object(dstName).CopierFrom(srcObj).Run(a.ctx); err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			err = fs.ErrNotExist
+		}
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: err}
+	}
+	if err := srcObj.Delete(a.ctx); err != nil {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: err}
+	}
+	return nil
+}
+
+// Mkdir は、ディレクトリを作成します。gs:// URI の場合はディレクトリが存在しないため何もしません。
+func (a *aferoFs) Mkdir(name string, perm os.FileMode) error {
+	if !IsGCSURI(name) {
+		return a.local.Mkdir(name, perm)
+	}
+	return nil
+}
+
+// MkdirAll は、親ディレクトリを含めてディレクトリを作成します。gs:// URI の場合は何もしません。
+func (a *aferoFs) MkdirAll(name string, perm os.FileMode) error {
+	if !IsGCSURI(name) {
+		return a.local.MkdirAll(name, perm)
+	}
+	return nil
+}
+
+// Chmod は、ファイルのモードを変更します。gs:// URI ではサポートされません。
+func (a *aferoFs) Chmod(name string, mode os.FileMode) error {
+	if !IsGCSURI(name) {
+		return a.local.Chmod(name, mode)
+	}
+	return &fs.PathError{Op: "chmod", Path: name, Err: errors.ErrUnsupported}
+}
+
+// Chown は、ファイルの所有者を変更します。gs:// URI ではサポートされません。
+func (a *aferoFs) Chown(name string, uid, gid int) error {
+	if !IsGCSURI(name) {
+		return a.local.Chown(name, uid, gid)
+	}
+	return &fs.PathError{Op: "chown", Path: name, Err: errors.ErrUnsupported}
+}
+
+// Chtimes は、ファイルのアクセス時刻と更新時刻を変更します。gs:// URI ではサポートされません。
+func (a *aferoFs) Chtimes(name string, atime, mtime time.Time) error {
+	if !IsGCSURI(name) {
+		return a.local.Chtimes(name, atime, mtime)
+	}
+	return &fs.PathError{Op: "chtimes", Path: name, Err: errors.ErrUnsupported}
+}
+
+// =================================================================
+// 3. 内部ヘルパー
+// =================================================================
+
+// gcsPath は、gs:// URI をバケットの gcsFS とその中のパス (バケット直下の場合は ".") に変換します。
+func (a *aferoFs) gcsPath(op, uri string) (*gcsFS, string, error) {
+	if a.r == nil {
+		return nil, "", &fs.PathError{Op: op, Path: uri, Err: errors.New(Message("GCSクライアントが初期化されていません"))}
+	}
+	client, err := a.r.gcs()
+	if err != nil {
+		return nil, "", &fs.PathError{Op: op, Path: uri, Err: fmt.Errorf(Message("GCSクライアントが初期化されていません: %w"), err)}
+	}
+	bucketName, objectPath, err := ParseGCSURI(uri)
+	if err != nil {
+		return nil, "", &fs.PathError{Op: op, Path: uri, Err: err}
+	}
+	name := strings.TrimSuffix(objectPath, "/")
+	if name == "" {
+		name = "."
+	}
+	return &gcsFS{ctx: a.ctx, bucket: a.r.cfg.gcsBucket(client, bucketName)}, name, nil
+}
+
+// withPath は、gcsFS が返した *fs.PathError のパスをURIに置き換えます。
+func withPath(err error, uri string) error {
+	var pe *fs.PathError
+	if errors.As(err, &pe) {
+		pe.Path = uri
+	}
+	return err
+}
+
+// =================================================================
+// 4. ファイル
+// =================================================================
+
+// aferoReadFile は、GCS オブジェクトを読み込む afero.File です。
+type aferoReadFile struct {
+	*gcsFile
+	uri string
+}
+
+func (f *aferoReadFile) Name() string { return f.uri }
+func (f *aferoReadFile) Sync() error  { return nil }
+
+func (f *aferoReadFile) Readdir(int) ([]os.FileInfo, error) {
+	return nil, &fs.PathError{Op: "readdir", Path: f.uri, Err: errNotDirectory}
+}
+
+func (f *aferoReadFile) Readdirnames(int) ([]string, error) {
+	return nil, &fs.PathError{Op: "readdir", Path: f.uri, Err: errNotDirectory}
+}
+
+func (f *aferoReadFile) Write([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "write", Path: f.uri, Err: fs.ErrPermission}
+}
+
+func (f *aferoReadFile) WriteAt([]byte, int64) (int, error) {
+	return 0, &fs.PathError{Op: "write", Path: f.uri, Err: fs.ErrPermission}
+}
+
+func (f *aferoReadFile) WriteString(string) (int, error) {
+	return 0, &fs.PathError{Op: "write", Path: f.uri, Err: fs.ErrPermission}
+}
+
+func (f *aferoReadFile) Truncate(int64) error {
+	return &fs.PathError{Op: "truncate", Path: f.uri, Err: fs.ErrPermission}
+}
+
+// aferoDir は、GCS のプレフィックスを表す afero.File です。
+type aferoDir struct {
+	*gcsDir
+	uri string
+}
+
+func (d *aferoDir) Name() string { return d.uri }
+func (d *aferoDir) Sync() error  { return nil }
+
+// Readdir は、ディレクトリ直下のエントリの情報を最大 count 件返します。count が 0 以下の場合はすべて返します。
+func (d *aferoDir) Readdir(count int) ([]os.FileInfo, error) {
+	entries, err := d.ReadDir(count)
+	if err != nil {
+		return nil, withPath(err, d.uri)
+	}
+	infos := make([]os.FileInfo, 0, len(entries))
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// Readdirnames は、ディレクトリ直下のエントリの名前を最大 n 件返します。n が 0 以下の場合はすべて返します。
+func (d *aferoDir) Readdirnames(n int) ([]string, error) {
+	entries, err := d.ReadDir(n)
+	if err != nil {
+		return nil, withPath(err, d.uri)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names, nil
+}
+
+func (d *aferoDir) ReadAt([]byte, int64) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.uri, Err: errIsDirectory}
+}
+
+func (d *aferoDir) Seek(int64, int) (int64, error) {
+	return 0, &fs.PathError{Op: "seek", Path: d.uri, Err: errIsDirectory}
+}
+
+func (d *aferoDir) Write([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "write", Path: d.uri, Err: errIsDirectory}
+}
+
+func (d *aferoDir) WriteAt([]byte, int64) (int, error) {
+	return 0, &fs.PathError{Op: "write", Path: d.uri, Err: errIsDirectory}
+}
+
+func (d *aferoDir) WriteString(string) (int, error) {
+	return 0, &fs.PathError{Op: "write", Path: d.uri, Err: errIsDirectory}
+}
+
+func (d *aferoDir) Truncate(int64) error {
+	return &fs.PathError{Op: "truncate", Path: d.uri, Err: errIsDirectory}
+}
+
+// aferoWriteFile は、GCS オブジェクトへ書き込む afero.File です。
+// 書き込んだ内容は UniversalIOWriter.OpenWrite へストリーミングされ、Close の時点で確定されます。
+type aferoWriteFile struct {
+	uri     string
+	wc      io.WriteCloser
+	written int64
+	closed  bool
+	err     error
+}
+
+func (f *aferoWriteFile) Name() string { return f.uri }
+
+// Write は、p をオブジェクトへストリーミングします。
+func (f *aferoWriteFile) Write(p []byte) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "write", Path: f.uri, Err: fs.ErrClosed}
+	}
+	n, err := f.wc.Write(p)
+	f.written += int64(n)
+	if err != nil {
+		return n, &fs.PathError{Op: "write", Path: f.uri, Err: err}
+	}
+	return n, nil
+}
+
+func (f *aferoWriteFile) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+// Close は、書き込みを終了し、オブジェクトが確定されるまで待ちます。
+func (f *aferoWriteFile) Close() error {
+	if f.closed {
+		return f.err
+	}
+	f.closed = true
+	if err := f.wc.Close(); err != nil {
+		f.err = &fs.PathError{Op: "close", Path: f.uri, Err: err}
+	}
+	return f.err
+}
+
+// Sync は、ストリーミング中の内容を途中で確定できないため何もしません。
+func (f *aferoWriteFile) Sync() error { return nil }
+
+// Stat は、これまでに書き込んだサイズを持つファイル情報を返します。
+func (f *aferoWriteFile) Stat() (os.FileInfo, error) {
+	_, objectPath, _ := ParseGCSURI(f.uri)
+	return &writingObjectInfo{name: path.Base(objectPath), size: f.written}, nil
+}
+
+func (f *aferoWriteFile) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: f.uri, Err: fs.ErrPermission}
+}
+
+func (f *aferoWriteFile) ReadAt([]byte, int64) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: f.uri, Err: fs.ErrPermission}
+}
+
+func (f *aferoWriteFile) Seek(int64, int) (int64, error) {
+	return 0, &fs.PathError{Op: "seek", Path: f.uri, Err: errors.ErrUnsupported}
+}
+
+func (f *aferoWriteFile) WriteAt([]byte, int64) (int, error) {
+	return 0, &fs.PathError{Op: "write", Path: f.uri, Err: errors.ErrUnsupported}
+}
+
+func (f *aferoWriteFile) Truncate(int64) error {
+	return &fs.PathError{Op: "truncate", Path: f.uri, Err: errors.ErrUnsupported}
+}
+
+func (f *aferoWriteFile) Readdir(int) ([]os.FileInfo, error) {
+	return nil, &fs.PathError{Op: "readdir", Path: f.uri, Err: errNotDirectory}
+}
+
+func (f *aferoWriteFile) Readdirnames(int) ([]string, error) {
+	return nil, &fs.PathError{Op: "readdir", Path: f.uri, Err: errNotDirectory}
+}
+
+// writingObjectInfo は、書き込み中のオブジェクトの fs.FileInfo です。
+type writingObjectInfo struct {
+	name string
+	size int64
+}
+
+func (i *writingObjectInfo) Name() string       { return i.name }
+func (i *writingObjectInfo) Size() int64        { return i.size }
+func (i *writingObjectInfo) Mode() fs.FileMode  { return 0o644 }
+func (i *writingObjectInfo) ModTime() time.Time { return time.Now() }
+func (i *writingObjectInfo) IsDir() bool        { return false }
+func (i *writingObjectInfo) Sys() any           { return nil }
+
+// 型アサーションチェック
+var _ afero.Fs = (*aferoFs)(nil)
+var _ afero.File = (*aferoReadFile)(nil)
+var _ afero.File = (*aferoDir)(nil)
+var _ afero.File = (*aferoWriteFile)(nil)
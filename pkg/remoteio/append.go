@@ -0,0 +1,83 @@
+package remoteio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"cloud.google.com/go/storage"
+)
+
+// GCSAppender は、既存の GCS オブジェクトの末尾にコンテンツを追記するためのインターフェースです。
+type GCSAppender interface {
+	// AppendToGCS は、指定されたバケットとオブジェクトパスのオブジェクトの末尾に io.Reader の内容を追記します。
+	// オブジェクトが存在しない場合は、新しく作成します。
+	AppendToGCS(ctx context.Context, bucketName, objectPath string, contentReader io.Reader, contentType string) error
+}
+
+// AppendToGCS は GCSAppender インターフェースを実装します。
+// GCS のオブジェクトは変更できないため、追記する内容を一時オブジェクトとしてアップロードし、
+// Compose API で既存のオブジェクトと連結してから一時オブジェクトを削除します。
+// 連結は既存のオブジェクトの世代番号を前提条件とするため、同時に別の書き込みがあった場合は失敗し、内容は失われません。
+// Content-Type は既存のオブジェクトのものを維持し、contentType はオブジェクトを新しく作成する場合にのみ使用します。
+func (w *UniversalIOWriter) AppendToGCS(ctx context.Context, bucketName, objectPath string, contentReader io.Reader, contentType string) (err error) {
+	defer classifyError(&err)
+	targetURI := fmt.Sprintf("gs://%s/%s", bucketName, objectPath)
+
+	if bucketName == "" {
+		return invalidURIError("GCSへの追記に失敗しました: バケット名が空です")
+	}
+	if objectPath == "" {
+		return invalidURIError("GCSへの追記に失敗しました: オブジェクトパスが空です")
+	}
+	client, err := w.gcs()
+	if err != nil {
+		return fmt.Errorf(Message("GCSへの追記に失敗しました: GCSクライアントが初期化されていません: %w"), err)
+	}
+	if err := w.cfg.faults.beforeOp("AppendToGCS", targetURI); err != nil {
+		return err
+	}
+
+	bucket := w.cfg.gcsBucket(client, bucketName)
+	obj := bucket.Object(objectPath)
+
+	attrs, err := obj.Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		// 追記先がまだない場合は、通常の書き込みで作成する
+		return w.WriteToGCS(ctx, bucketName, objectPath, contentReader, contentType)
+	}
+	if err != nil {
+		return fmt.Errorf(Message("GCSオブジェクトの情報の取得に失敗しました (URI: %s): %w"), targetURI, err)
+	}
+
+	// 1. 追記する内容を一時オブジェクトとしてアップロードする
+	partPath := fmt.Sprintf("%s.remoteio-append-%d", objectPath, time.Now().UnixNano())
+	if err := w.WriteToGCS(ctx, bucketName, partPath, contentReader, attrs.ContentType); err != nil {
+		return err
+	}
+	part := bucket.Object(partPath)
+	defer func() {
+		// 一時オブジェクトの削除に失敗しても、追記自体は完了しているため警告に留める
+		if err := part.Delete(context.WithoutCancel(ctx)); err != nil {
+			w.cfg.log().Warn(Message("追記用の一時オブジェクトの削除に失敗しました"), slog.String("uri", fmt.Sprintf("gs://%s/%s", bucketName, partPath)), slog.String("error", err.Error()))
+		}
+	}()
+
+	// 2. 既存のオブジェクトと一時オブジェクトを連結する
+	composer := obj.If(storage.Conditions{GenerationMatch: attrs.Generation}).ComposerFrom(obj, part)
+	composer.ContentType = attrs.ContentType
+	composer.Metadata = attrs.Metadata
+	composer.KMSKeyName = w.cfg.kmsKeyName
+	if _, err := composer.Run(ctx); err != nil {
+		return fmt.Errorf(Message("GCSオブジェクトへの追記に失敗しました (URI: %s): %w"), targetURI, err)
+	}
+
+	w.cfg.log().Info(Message("GCS追記処理完了"), slog.String("uri", targetURI))
+	return nil
+}
+
+// 型アサーションチェック
+var _ GCSAppender = (*UniversalIOWriter)(nil)
@@ -0,0 +1,305 @@
+package remoteio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+)
+
+// =================================================================
+// 1. インターフェース定義
+// =================================================================
+
+// AzureOutputWriter は、Azure Blob Storage にコンテンツを書き込むためのインターフェースです。
+type AzureOutputWriter interface {
+	// WriteToAzure は、指定されたコンテナとBlob名に io.Reader からコンテンツを書き込みます。
+	WriteToAzure(ctx context.Context, containerName, blobName string, contentReader io.Reader, contentType string) error
+}
+
+// =================================================================
+// 2. 読み込み (AzureInputReader)
+// =================================================================
+
+// AzureInputReader は、Azure Blob の読み込みのみを処理する InputReader の具象実装です。
+// ローカルファイルや GCS と併せて扱う場合は、WithAzureClient を指定した LocalGCSInputReader を使用してください。
+type AzureInputReader struct {
+	azureClient *azblob.Client
+	cfg         config
+}
+
+// NewAzureInputReader は AzureInputReader の新しいインスタンスを作成します。
+func NewAzureInputReader(client *azblob.Client, opts ...Option) *AzureInputReader {
+	return &AzureInputReader{azureClient: client, cfg: newConfig(opts)}
+}
+
+// Open は、az:// URI で指定されたBlobのストリームを開きます。
+func (r *AzureInputReader) Open(ctx context.Context, uri string) (_ io.ReadCloser, err error) {
+	defer classifyError(&err)
+	if err := r.cfg.faults.beforeOp("Open", uri); err != nil {
+		return nil, err
+	}
+	rc, err := openAzureBlob(ctx, r.azureClient, uri)
+	if err != nil {
+		return nil, err
+	}
+	return r.cfg.wrapReadCloser(rc), nil
+}
+
+// openAzureBlob は、Azure URI からBlobを読み込み、io.ReadCloser を返します。
+func openAzureBlob(ctx context.Context, client *azblob.Client, azureURI string) (io.ReadCloser, error) {
+	containerName, blobName, err := azureBlobName(client, azureURI)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.DownloadStream(ctx, containerName, blobName, nil)
+	if err != nil {
+		return nil, fmt.Errorf(Message("Azure Blobの読み込みに失敗しました (URI: %s): %w"), azureURI, err)
+	}
+	return resp.Body, nil
+}
+
+// openAzureRange は、Blob の offset から length バイト (負の場合は終端まで) を読み込みます。
+func openAzureRange(ctx context.Context, client *azblob.Client, azureURI string, offset, length int64) (io.ReadCloser, error) {
+	containerName, blobName, err := azureBlobName(client, azureURI)
+	if err != nil {
+		return nil, err
+	}
+
+	// Count が 0 の場合は終端まで読み込まれる
+	httpRange := blob.HTTPRange{Offset: offset}
+	if length > 0 {
+		httpRange.Count = length
+	}
+	resp, err := client.DownloadStream(ctx, containerName, blobName, &azblob.DownloadStreamOptions{Range: httpRange})
+	if err != nil {
+		return nil, fmt.Errorf(Message("Azure Blobの範囲読み込みに失敗しました (URI: %s): %w"), azureURI, err)
+	}
+	return resp.Body, nil
+}
+
+// statAzureBlob は、Blob のプロパティを返します。
+func statAzureBlob(ctx context.Context, client *azblob.Client, azureURI string) (ObjectInfo, error) {
+	containerName, blobName, err := azureBlobName(client, azureURI)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	props, err := client.ServiceClient().NewContainerClient(containerName).NewBlobClient(blobName).GetProperties(ctx, nil)
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf(Message("Azure Blobのプロパティの取得に失敗しました (URI: %s): %w"), azureURI, err)
+	}
+	info := ObjectInfo{
+		URI:  azureURI,
+		Name: path.Base(blobName),
+		MD5:  props.ContentMD5,
+	}
+	if props.ContentLength != nil {
+		info.Size = *props.ContentLength
+	}
+	if props.LastModified != nil {
+		info.Updated = *props.LastModified
+	}
+	if props.ContentType != nil {
+		info.ContentType = *props.ContentType
+	}
+	if props.ContentEncoding != nil {
+		info.ContentEncoding = *props.ContentEncoding
+	}
+	if props.AccessTier != nil {
+		info.StorageClass = *props.AccessTier
+	}
+	if len(props.Metadata) > 0 {
+		info.Metadata = make(map[string]string, len(props.Metadata))
+		for k, v := range props.Metadata {
+			if v != nil {
+				info.Metadata[k] = *v
+			}
+		}
+	}
+	return info, nil
+}
+
+// listAzureBlobs は、Azure のプレフィックス配下のBlobを一覧します。
+func listAzureBlobs(ctx context.Context, client *azblob.Client, azureURI string) ([]ObjectInfo, error) {
+	if client == nil {
+		return nil, fmt.Errorf(Message("Azureクライアントが初期化されていないため、Blobを一覧できません (URI: %s)"), azureURI)
+	}
+	containerName, blobName, err := ParseAzureURI(azureURI)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := listPrefix(blobName)
+	var objects []ObjectInfo
+	pager := client.NewListBlobsFlatPager(containerName, &azblob.ListBlobsFlatOptions{Prefix: &prefix})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf(Message("Azure Blobの一覧取得に失敗しました (URI: %s): %w"), azureURI, err)
+		}
+		for _, item := range page.Segment.BlobItems {
+			if item.Name == nil || strings.HasSuffix(*item.Name, "/") {
+				continue
+			}
+			obj := ObjectInfo{
+				URI:  fmt.Sprintf("az://%s/%s", containerName, *item.Name),
+				Name: strings.TrimPrefix(*item.Name, prefix),
+			}
+			if props := item.Properties; props != nil {
+				if props.ContentLength != nil {
+					obj.Size = *props.ContentLength
+				}
+				if props.LastModified != nil {
+					obj.Updated = *props.LastModified
+				}
+				if props.AccessTier != nil {
+					obj.StorageClass = string(*props.AccessTier)
+				}
+			}
+			objects = append(objects, obj)
+		}
+	}
+	return objects, nil
+}
+
+// azureBlobName は、読み込み対象の Azure URI を検証し、コンテナ名とBlob名を返します。
+func azureBlobName(client *azblob.Client, azureURI string) (containerName, blobName string, err error) {
+	if client == nil {
+		return "", "", fmt.Errorf(Message("Azureクライアントが初期化されていないため、Blobを読み込めません (URI: %s)"), azureURI)
+	}
+
+	containerName, blobName, err = ParseAzureURI(azureURI)
+	if err != nil {
+		return "", "", err
+	}
+	if blobName == "" {
+		return "", "", invalidURIError("無効なAzure URI形式です: %s (Blob名が空です)", azureURI)
+	}
+	return containerName, blobName, nil
+}
+
+// azureMetadata は、メタデータを Azure の形式に変換します。md が空の場合は nil を返します。
+// Azure のメタデータ名は C# の識別子である必要があるため、"-" を "_" に置き換えます。
+func azureMetadata(md map[string]string) map[string]*string {
+	if len(md) == 0 {
+		return nil
+	}
+	metadata := make(map[string]*string, len(md))
+	for k, v := range md {
+		metadata[strings.ReplaceAll(k, "-", "_")] = &v
+	}
+	return metadata
+}
+
+// =================================================================
+// 3. 書き込み (UniversalIOWriter)
+// =================================================================
+
+// WriteToAzure は AzureOutputWriter インターフェースを実装します。
+// ブロックをステージングしてから最後にコミットするため、中止された書き込みはBlobとして確定されません。
+// contentType が空の場合は、blobName の拡張子または内容から Content-Type を判定します。
+func (w *UniversalIOWriter) WriteToAzure(ctx context.Context, containerName, blobName string, contentReader io.Reader, contentType string) (err error) {
+	defer classifyError(&err)
+	targetURI := fmt.Sprintf("az://%s/%s", containerName, blobName)
+
+	if containerName == "" {
+		return invalidURIError("Azureへの書き込みに失敗しました: コンテナ名が空です")
+	}
+	if blobName == "" {
+		return invalidURIError("Azureへの書き込みに失敗しました: Blob名が空です")
+	}
+	client := w.cfg.azureClient
+	if client == nil {
+		return errors.New(Message("Azureへの書き込みに失敗しました: Azureクライアントが初期化されていません"))
+	}
+
+	if err := w.cfg.faults.beforeOp("WriteToAzure", targetURI); err != nil {
+		return err
+	}
+	contentReader = w.cfg.wrapWriteStream(contentReader)
+	// Content-Type が指定されていない場合は、拡張子または内容から判定する
+	contentType, contentReader, err = resolveContentType(blobName, contentType, contentReader)
+	if err != nil {
+		return err
+	}
+
+	w.cfg.log().Info(Message("Azure書き込み処理開始"), slog.String("uri", targetURI), slog.String("content_type", contentType))
+
+	blobClient := client.ServiceClient().NewContainerClient(containerName).NewBlobClient(blobName)
+	info := TransferInfo{URI: targetURI, ContentType: contentType}
+	err = w.cfg.writeValidated(ctx, info, contentReader, func(ctx context.Context, r io.Reader, verdict func() error) error {
+		// 検査で拒否された場合は、ストリームの終端の代わりにエラーを返してコミットさせない
+		vr := &verdictReader{r: r, verdict: verdict}
+		opts := &azblob.UploadStreamOptions{
+			HTTPHeaders: &blob.HTTPHeaders{
+				BlobContentType:        &contentType,
+				BlobCacheControl:       optional(w.cfg.headers.cacheControl),
+				BlobContentEncoding:    optional(w.cfg.headers.contentEncoding),
+				BlobContentDisposition: optional(w.cfg.headers.contentDisposition),
+				BlobContentLanguage:    optional(w.cfg.headers.contentLanguage),
+			},
+			Metadata: azureMetadata(w.cfg.metadata),
+		}
+		if w.cfg.chunkSize != nil && *w.cfg.chunkSize > 0 {
+			opts.BlockSize = int64(*w.cfg.chunkSize)
+		}
+		if w.cfg.noClobber {
+			// ブロックの一覧を確定する時点で、Blob が存在しないことを確認する
+			opts.AccessConditions = &blob.AccessConditions{
+				ModifiedAccessConditions: &blob.ModifiedAccessConditions{IfNoneMatch: to.Ptr(azcore.ETagAny)},
+			}
+		}
+		_, err := client.UploadStream(ctx, containerName, blobName, vr, opts)
+		if vr.err != nil {
+			return vr.err
+		}
+		if w.cfg.noClobber && isPreconditionFailed(err) {
+			return destinationExists(targetURI)
+		}
+		if err != nil {
+			w.cfg.log().Error(Message("Azureへのコンテンツ書き込み中にエラーが発生"), slog.String("uri", targetURI), slog.String("error", err.Error()))
+			return fmt.Errorf(Message("Azureへのコンテンツ書き込み中にエラーが発生しました: %w"), err)
+		}
+		return nil
+	}, writeTarget{
+		remove: func(ctx context.Context) error {
+			_, err := client.DeleteBlob(ctx, containerName, blobName, nil)
+			return err
+		},
+		setMetadata: func(ctx context.Context, md map[string]string) error {
+			_, err := blobClient.SetMetadata(ctx, azureMetadata(md), nil)
+			return err
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	w.cfg.log().Info(Message("Azure書き込み処理完了"), slog.String("uri", targetURI))
+	return nil
+}
+
+// deleteAzureBlob は、Azure URI で指定された Blob を削除します。
+func deleteAzureBlob(ctx context.Context, client *azblob.Client, azureURI string) error {
+	containerName, blobName, err := azureBlobName(client, azureURI)
+	if err != nil {
+		return err
+	}
+	if _, err := client.DeleteBlob(ctx, containerName, blobName, nil); err != nil {
+		return fmt.Errorf(Message("Azure Blobの削除に失敗しました (URI: %s): %w"), azureURI, err)
+	}
+	return nil
+}
+
+// 型アサーションチェック
+var _ InputReader = (*AzureInputReader)(nil)
+var _ AzureOutputWriter = (*UniversalIOWriter)(nil)
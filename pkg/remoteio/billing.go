@@ -0,0 +1,12 @@
+package remoteio
+
+// WithBillingProject は、GCS へのリクエストの料金を project (プロジェクト ID) に請求します。
+// リクエスト元による支払い (Requester Pays) が有効なバケットは、請求先のプロジェクトを指定しないと
+// 読み込み・書き込み・一覧などのすべてのリクエストが 400 エラーで失敗するため、このオプションで指定します。
+// 呼び出し元の認証情報には、project の serviceusage.services.use 権限が必要です。
+// 空の場合は請求先を指定しません。InputReader と OutputWriter の両方に適用されます。
+func WithBillingProject(project string) Option {
+	return func(c *config) {
+		c.userProject = project
+	}
+}
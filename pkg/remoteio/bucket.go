@@ -0,0 +1,237 @@
+package remoteio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+// BucketLister は、プロジェクトの GCS バケットを一覧するためのインターフェースです。
+type BucketLister interface {
+	// ListBuckets は、projectID のプロジェクトのバケットを名前順に返します。
+	ListBuckets(ctx context.Context, projectID string) ([]BucketInfo, error)
+}
+
+// BucketManager は、GCS バケットを作成・削除するためのインターフェースです。
+// テスト環境の準備と片付けなど、簡単なプロビジョニングに使用します。
+type BucketManager interface {
+	// CreateBucket は、projectID のプロジェクトに uri (gs://bucket) のバケットを作成します。
+	// 同じ名前のバケットが既に存在する場合は ErrAlreadyExists を含むエラーを返します。
+	CreateBucket(ctx context.Context, uri, projectID string, opts ...BucketOption) error
+	// DeleteBucket は、uri (gs://bucket) の空のバケットを削除します。
+	DeleteBucket(ctx context.Context, uri string) error
+	// EmptyBucket は、uri (gs://bucket) のバケットのすべてのオブジェクトを、非現行の世代を含めて削除し、削除した数を返します。
+	EmptyBucket(ctx context.Context, uri string) (int, error)
+}
+
+// BucketInfo は、ListBuckets が返すバケットの情報です。
+type BucketInfo struct {
+	URI                      string            // バケットのURI (gs://bucket)
+	Name                     string            // バケット名
+	Location                 string            // ロケーション (例: US、ASIA-NORTHEAST1)
+	LocationType             string            // ロケーションの種類 (region、dual-region、multi-region)
+	StorageClass             string            // 既定のストレージクラス
+	UniformBucketLevelAccess bool              // 均一なバケットレベルのアクセスが有効な場合は true
+	Versioning               bool              // オブジェクトのバージョニングが有効な場合は true
+	Created                  time.Time         // 作成日時
+	Labels                   map[string]string // ラベル
+}
+
+// BucketOption は、CreateBucket で作成するバケットの属性を指定する関数型オプションです。
+type BucketOption func(*storage.BucketAttrs)
+
+// WithBucketLocation は、作成するバケットのロケーション (例: US、ASIA-NORTHEAST1) を指定します。省略時は US です。
+func WithBucketLocation(location string) BucketOption {
+	return func(a *storage.BucketAttrs) {
+		a.Location = location
+	}
+}
+
+// WithBucketStorageClass は、作成するバケットの既定のストレージクラス (例: STANDARD、NEARLINE) を指定します。省略時は STANDARD です。
+func WithBucketStorageClass(storageClass string) BucketOption {
+	return func(a *storage.BucketAttrs) {
+		a.StorageClass = storageClass
+	}
+}
+
+// WithUniformBucketLevelAccess は、作成するバケットの均一なバケットレベルのアクセスを有効にします (オブジェクトごとの ACL を無効にします)。
+func WithUniformBucketLevelAccess() BucketOption {
+	return func(a *storage.BucketAttrs) {
+		a.UniformBucketLevelAccess = storage.UniformBucketLevelAccess{Enabled: true}
+	}
+}
+
+// WithBucketVersioning は、作成するバケットのオブジェクトのバージョニングを有効にします。
+func WithBucketVersioning() BucketOption {
+	return func(a *storage.BucketAttrs) {
+		a.VersioningEnabled = true
+	}
+}
+
+// bucketNameOf は、バケットの URI (gs://bucket または gs://bucket/) からバケット名を返します。
+func bucketNameOf(uri string) (string, error) {
+	bucketName, objectName, err := ParseGCSURI(uri)
+	if err != nil {
+		return "", err
+	}
+	if bucketName == "" || objectName != "" {
+		return "", invalidURIError("バケットの URI は gs://bucket の形式で指定してください: %s", uri)
+	}
+	return bucketName, nil
+}
+
+// ListBuckets は BucketLister インターフェースを実装します。
+func (r *LocalGCSInputReader) ListBuckets(ctx context.Context, projectID string) (_ []BucketInfo, err error) {
+	defer classifyError(&err)
+	if projectID == "" {
+		return nil, errors.New(Message("バケットを一覧するプロジェクトIDを指定してください"))
+	}
+	client, err := r.gcs()
+	if err != nil {
+		return nil, fmt.Errorf(Message("GCSクライアントが初期化されていないため、バケットを一覧できません: %w"), err)
+	}
+	if err := r.cfg.faults.beforeOp("ListBuckets", projectID); err != nil {
+		return nil, err
+	}
+	ctx, cancel := r.cfg.opContext(ctx)
+	defer cancel()
+
+	it := client.Buckets(ctx, projectID)
+	var buckets []BucketInfo
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf(Message("バケットの一覧取得に失敗しました (プロジェクト: %s): %w"), projectID, err)
+		}
+		buckets = append(buckets, BucketInfo{
+			URI:                      "gs://" + attrs.Name,
+			Name:                     attrs.Name,
+			Location:                 attrs.Location,
+			LocationType:             attrs.LocationType,
+			StorageClass:             attrs.StorageClass,
+			UniformBucketLevelAccess: attrs.UniformBucketLevelAccess.Enabled,
+			Versioning:               attrs.VersioningEnabled,
+			Created:                  attrs.Created,
+			Labels:                   attrs.Labels,
+		})
+	}
+	return buckets, nil
+}
+
+// CreateBucket は BucketManager インターフェースを実装します。
+func (w *UniversalIOWriter) CreateBucket(ctx context.Context, uri, projectID string, opts ...BucketOption) (err error) {
+	defer classifyError(&err)
+	bucketName, err := bucketNameOf(uri)
+	if err != nil {
+		return err
+	}
+	if projectID == "" {
+		return errors.New(Message("バケットを作成するプロジェクトIDを指定してください"))
+	}
+	client, err := w.gcs()
+	if err != nil {
+		return fmt.Errorf(Message("GCSクライアントが初期化されていないため、バケットを作成できません (URI: %s): %w"), uri, err)
+	}
+	if err := w.cfg.faults.beforeOp("CreateBucket", uri); err != nil {
+		return err
+	}
+	ctx, cancel := w.cfg.opContext(ctx)
+	defer cancel()
+
+	attrs := &storage.BucketAttrs{}
+	for _, opt := range opts {
+		opt(attrs)
+	}
+	if err := w.cfg.gcsBucket(client, bucketName).Create(ctx, projectID, attrs); err != nil {
+		if httpStatus(err) == http.StatusConflict {
+			return &Error{Kind: ErrAlreadyExists, Err: fmt.Errorf(Message("バケットは既に存在します (URI: %s): %w"), uri, err)}
+		}
+		return fmt.Errorf(Message("バケットの作成に失敗しました (URI: %s): %w"), uri, err)
+	}
+	w.cfg.log().Info(Message("バケット作成完了"), slog.String("uri", uri), slog.String("project", projectID))
+	return nil
+}
+
+// DeleteBucket は BucketManager インターフェースを実装します。
+// バケットにオブジェクトが残っている場合は失敗するため、先に EmptyBucket で空にします。
+func (w *UniversalIOWriter) DeleteBucket(ctx context.Context, uri string) (err error) {
+	defer classifyError(&err)
+	bucketName, err := bucketNameOf(uri)
+	if err != nil {
+		return err
+	}
+	client, err := w.gcs()
+	if err != nil {
+		return fmt.Errorf(Message("GCSクライアントが初期化されていないため、バケットを削除できません (URI: %s): %w"), uri, err)
+	}
+	if err := w.cfg.faults.beforeOp("DeleteBucket", uri); err != nil {
+		return err
+	}
+	ctx, cancel := w.cfg.opContext(ctx)
+	defer cancel()
+
+	if err := w.cfg.gcsBucket(client, bucketName).Delete(ctx); err != nil {
+		// 空ではないバケットの削除は 409 (エミュレータでは 412) で失敗する
+		if status := httpStatus(err); status == http.StatusConflict || status == http.StatusPreconditionFailed {
+			return fmt.Errorf(Message("バケットが空ではないため削除できません (URI: %s): %w"), uri, err)
+		}
+		return fmt.Errorf(Message("バケットの削除に失敗しました (URI: %s): %w"), uri, err)
+	}
+	w.cfg.log().Info(Message("バケット削除完了"), slog.String("uri", uri))
+	return nil
+}
+
+// EmptyBucket は BucketManager インターフェースを実装します。
+// 保持期間のロックやオブジェクトの保持 (hold) が設定されたオブジェクトは削除できずにエラーになります。
+func (w *UniversalIOWriter) EmptyBucket(ctx context.Context, uri string) (_ int, err error) {
+	defer classifyError(&err)
+	bucketName, err := bucketNameOf(uri)
+	if err != nil {
+		return 0, err
+	}
+	client, err := w.gcs()
+	if err != nil {
+		return 0, fmt.Errorf(Message("GCSクライアントが初期化されていないため、バケットを空にできません (URI: %s): %w"), uri, err)
+	}
+	if err := w.cfg.faults.beforeOp("EmptyBucket", uri); err != nil {
+		return 0, err
+	}
+	bucket := w.cfg.gcsBucket(client, bucketName)
+
+	// 非現行の世代も削除するため、すべての世代を一覧して世代番号を指定して削除する
+	it := bucket.Objects(ctx, &storage.Query{Versions: true})
+	deleted := 0
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return deleted, fmt.Errorf(Message("バケットのオブジェクトの一覧取得に失敗しました (URI: %s): %w"), uri, err)
+		}
+		opCtx, cancel := w.cfg.opContext(ctx)
+		err = bucket.Object(attrs.Name).Generation(attrs.Generation).Delete(opCtx)
+		cancel()
+		if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			return deleted, fmt.Errorf(Message("オブジェクトの削除に失敗しました (URI: %s): %w"), GCSGenerationURI("gs://"+bucketName+"/"+attrs.Name, attrs.Generation), err)
+		}
+		deleted++
+	}
+	w.cfg.log().Info(Message("バケットを空にしました"), slog.String("uri", uri), slog.Int("objects", deleted))
+	return deleted, nil
+}
+
+// 型アサーションチェック
+var (
+	_ BucketLister  = (*LocalGCSInputReader)(nil)
+	_ BucketManager = (*UniversalIOWriter)(nil)
+)
@@ -0,0 +1,144 @@
+package remoteio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 構造化データの書き込み時に設定する Content-Type
+const (
+	ContentTypeJSON = "application/json"
+	ContentTypeYAML = "application/yaml"
+)
+
+// =================================================================
+// 1. エンコードオプション
+// =================================================================
+
+// EncodeOption は、WriteJSON / WriteYAML のエンコード方法を変更する関数型オプションです。
+type EncodeOption func(*encodeConfig)
+
+// encodeConfig は、エンコード時の設定を保持します。
+type encodeConfig struct {
+	indent      int    // インデント幅 (JSON では 0 の場合は改行なし)
+	contentType string // 空の場合は形式ごとの既定値
+	escapeHTML  bool   // JSON で <, >, & をエスケープするか
+}
+
+// WithIndent は、出力を n 個の空白でインデントします。
+// JSON の既定はインデントなしの1行、YAML の既定は 4 です。
+func WithIndent(n int) EncodeOption {
+	return func(c *encodeConfig) {
+		c.indent = n
+	}
+}
+
+// WithEncodedContentType は、書き込み時に設定する Content-Type を上書きします。
+func WithEncodedContentType(contentType string) EncodeOption {
+	return func(c *encodeConfig) {
+		c.contentType = contentType
+	}
+}
+
+// WithHTMLEscape は、JSON の文字列中の <, >, & をエスケープします (既定ではエスケープしません)。
+// YAML では無視されます。
+func WithHTMLEscape() EncodeOption {
+	return func(c *encodeConfig) {
+		c.escapeHTML = true
+	}
+}
+
+func newEncodeConfig(opts []EncodeOption, defaultContentType string, defaultIndent int) encodeConfig {
+	c := encodeConfig{indent: defaultIndent, contentType: defaultContentType}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// =================================================================
+// 2. JSON
+// =================================================================
+
+// ReadJSON は、uri (GCS URI またはローカルパス) の内容を JSON として T にデコードします。
+func ReadJSON[T any](ctx context.Context, reader InputReader, uri string) (T, error) {
+	return readDecoded[T](ctx, reader, uri, "JSON", func(r io.Reader, v any) error {
+		return json.NewDecoder(r).Decode(v)
+	})
+}
+
+// WriteJSON は、v を JSON にエンコードして uri に書き込みます。
+// エンコードはアップロード前にメモリ上で行われるため、エンコードに失敗した場合は何も書き込まれません。
+func WriteJSON(ctx context.Context, writer OutputWriter, uri string, v any, opts ...EncodeOption) error {
+	cfg := newEncodeConfig(opts, ContentTypeJSON, 0)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(cfg.escapeHTML)
+	if cfg.indent > 0 {
+		enc.SetIndent("", strings.Repeat(" ", cfg.indent))
+	}
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf(Message("JSONのエンコードに失敗しました (%s): %w"), uri, err)
+	}
+	return writer.Write(ctx, uri, &buf, WithContentType(cfg.contentType))
+}
+
+// =================================================================
+// 3. YAML
+// =================================================================
+
+// ReadYAML は、uri (GCS URI またはローカルパス) の内容を YAML として T にデコードします。
+// 複数のドキュメントを含む場合は、最初のドキュメントのみをデコードします。
+func ReadYAML[T any](ctx context.Context, reader InputReader, uri string) (T, error) {
+	return readDecoded[T](ctx, reader, uri, "YAML", func(r io.Reader, v any) error {
+		err := yaml.NewDecoder(r).Decode(v)
+		if err == io.EOF {
+			// 空のドキュメントはゼロ値として扱う
+			return nil
+		}
+		return err
+	})
+}
+
+// WriteYAML は、v を YAML にエンコードして uri に書き込みます。
+// エンコードはアップロード前にメモリ上で行われるため、エンコードに失敗した場合は何も書き込まれません。
+func WriteYAML(ctx context.Context, writer OutputWriter, uri string, v any, opts ...EncodeOption) error {
+	cfg := newEncodeConfig(opts, ContentTypeYAML, 4)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(cfg.indent)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf(Message("YAMLのエンコードに失敗しました (%s): %w"), uri, err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf(Message("YAMLのエンコードに失敗しました (%s): %w"), uri, err)
+	}
+	return writer.Write(ctx, uri, &buf, WithContentType(cfg.contentType))
+}
+
+// =================================================================
+// 4. 内部ヘルパー
+// =================================================================
+
+// readDecoded は、uri を開いて decode で T にデコードします。
+func readDecoded[T any](ctx context.Context, reader InputReader, uri, format string, decode func(io.Reader, any) error) (T, error) {
+	var v T
+	rc, err := reader.Open(ctx, uri)
+	if err != nil {
+		return v, err
+	}
+	defer rc.Close()
+
+	if err := decode(rc, &v); err != nil {
+		return v, fmt.Errorf(Message("%sのデコードに失敗しました (%s): %w"), format, uri, err)
+	}
+	return v, nil
+}
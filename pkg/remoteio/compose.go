@@ -0,0 +1,126 @@
+package remoteio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"cloud.google.com/go/storage"
+)
+
+// ErrComposeUnsupported は、サーバー側で連結できない組み合わせ (GCS 以外、異なるバケット間など) の場合に
+// Compose が返すエラーです。この場合、呼び出し元は各ソースを順に読み込んで書き込んでください。
+var ErrComposeUnsupported = NewMessageError("remoteio: サーバー側で連結できない組み合わせです")
+
+// maxComposeSources は、GCS の Compose API が1回のリクエストで受け付けるソースの最大数です。
+const maxComposeSources = 32
+
+// Composer は、複数のオブジェクトをサーバー側で連結するためのインターフェースです。
+type Composer interface {
+	// Compose は、srcURIs のオブジェクトをこの順に連結して dstURI へ書き込みます。
+	Compose(ctx context.Context, dstURI string, srcURIs ...string) error
+}
+
+// Compose は Composer インターフェースを実装します。
+// dstURI と srcURIs がすべて同じバケットの GCS URI の場合に、GCS の Compose API でデータを転送せずに連結します。
+// Content-Type は先頭のソースから引き継ぎ、カスタムメタデータと HTTP ヘッダー (Cache-Control など) は書き込みごとの設定を適用します。
+// ソースが32個を超える場合は、dstURI に32個ずつ繰り返し連結します。
+// コンテンツはストリーミングされないため、バリデータが設定されている場合は ErrComposeUnsupported を返します。
+func (w *UniversalIOWriter) Compose(ctx context.Context, dstURI string, srcURIs ...string) (err error) {
+	defer classifyError(&err)
+	if len(srcURIs) == 0 {
+		return errors.New(Message("連結するソースが指定されていません"))
+	}
+	if len(w.cfg.validators) > 0 || !IsGCSURI(dstURI) {
+		return fmt.Errorf("%w: %s", ErrComposeUnsupported, dstURI)
+	}
+	client, err := w.gcs()
+	if err != nil {
+		return fmt.Errorf(Message("GCSクライアントが初期化されていないため、GCSオブジェクトを連結できません (URI: %s): %w"), dstURI, err)
+	}
+
+	bucketName, dstObject, err := ParseGCSURI(dstURI)
+	if err != nil {
+		return fmt.Errorf(Message("GCS URIのパース失敗: %w"), err)
+	}
+	if dstObject == "" {
+		return invalidURIError("無効なGCS URI形式です: %s (オブジェクト名が空です)", dstURI)
+	}
+	bucket := w.cfg.gcsBucket(client, bucketName)
+
+	srcs := make([]*storage.ObjectHandle, 0, len(srcURIs))
+	for _, uri := range srcURIs {
+		if !IsGCSURI(uri) {
+			return fmt.Errorf("%w: %s -> %s", ErrComposeUnsupported, uri, dstURI)
+		}
+		srcBucket, srcObject, err := ParseGCSURI(uri)
+		if err != nil {
+			return fmt.Errorf(Message("GCS URIのパース失敗: %w"), err)
+		}
+		if srcBucket != bucketName {
+			return fmt.Errorf(Message("%w: %s -> %s (バケットが異なります)"), ErrComposeUnsupported, uri, dstURI)
+		}
+		if srcObject == "" {
+			return invalidURIError("無効なGCS URI形式です: %s (オブジェクト名が空です)", uri)
+		}
+		srcs = append(srcs, bucket.Object(srcObject))
+	}
+	// 繰り返し連結する場合、途中で dstURI を上書きするため、2回目以降のソースに dstURI を含めることはできない
+	if len(srcs) > maxComposeSources && slices.Contains(srcURIs[maxComposeSources:], dstURI) {
+		return fmt.Errorf(Message("%d 個を超えるソースを連結する場合、%d 個目以降に出力先 (%s) を含めることはできません"), maxComposeSources, maxComposeSources, dstURI)
+	}
+
+	if err := w.cfg.faults.beforeOp("Compose", dstURI); err != nil {
+		return err
+	}
+	ctx, cancel := w.cfg.opContext(ctx)
+	defer cancel()
+
+	first, err := srcs[0].Attrs(ctx)
+	if err != nil {
+		return fmt.Errorf(Message("GCSオブジェクトの情報の取得に失敗しました (URI: %s): %w"), srcURIs[0], err)
+	}
+
+	dst := bucket.Object(dstObject)
+	batch := srcs[:min(len(srcs), maxComposeSources)]
+	rest := srcs[len(batch):]
+	// 上書きを防止する場合は、最初の連結でのみ出力先が存在しないことを確認する (以降は連結済みの出力先に連結する)
+	target, conditional := dst, w.cfg.noClobber
+	if conditional {
+		target = dst.If(storage.Conditions{DoesNotExist: true})
+	}
+	for {
+		composer := target.ComposerFrom(batch...)
+		composer.ContentType = first.ContentType
+		composer.KMSKeyName = w.cfg.kmsKeyName
+		// 連結後のオブジェクトはソースのメタデータを引き継がないため、連結のたびに設定する
+		composer.Metadata = w.cfg.metadata
+		composer.CacheControl = w.cfg.headers.cacheControl
+		composer.ContentEncoding = w.cfg.headers.contentEncoding
+		composer.ContentDisposition = w.cfg.headers.contentDisposition
+		composer.ContentLanguage = w.cfg.headers.contentLanguage
+		_, err := composer.Run(ctx)
+		if conditional && isPreconditionFailed(err) {
+			return destinationExists(dstURI)
+		}
+		target, conditional = dst, false
+		if err != nil {
+			return fmt.Errorf(Message("GCSオブジェクトの連結に失敗しました (URI: %s): %w"), dstURI, err)
+		}
+		if len(rest) == 0 {
+			break
+		}
+		// 連結済みの dstURI を先頭のソースとして、残りを続けて連結する
+		n := min(len(rest), maxComposeSources-1)
+		batch = append([]*storage.ObjectHandle{dst}, rest[:n]...)
+		rest = rest[n:]
+	}
+
+	w.cfg.log().Info(Message("連結完了"), slog.String("destination", dstURI), slog.Int("sources", len(srcURIs)))
+	return nil
+}
+
+// 型アサーションチェック
+var _ Composer = (*UniversalIOWriter)(nil)
@@ -0,0 +1,79 @@
+package remoteio
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/option"
+)
+
+// fakeComposeServer は、GCS の JSON API のうちオブジェクトの情報の取得と連結に応答するフェイクを起動し、
+// 連結のリクエストで指定された出力先の属性を composed へ記録するクライアントを返します。
+func fakeComposeServer(t *testing.T, composed *[]map[string]any) *storage.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/storage/v1/b/bucket/o/")
+		if r.Method == http.MethodPost && strings.HasSuffix(name, "/compose") {
+			var req struct {
+				Destination map[string]any `json:"destination"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			*composed = append(*composed, req.Destination)
+			name = strings.TrimSuffix(name, "/compose")
+		}
+		json.NewEncoder(w).Encode(map[string]any{"bucket": "bucket", "name": name, "contentType": "text/csv"})
+	}))
+	t.Cleanup(srv.Close)
+	client, err := storage.NewClient(context.Background(), option.WithEndpoint(srv.URL+"/storage/v1/"), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestComposeAppliesMetadataAndHeaders(t *testing.T) {
+	var composed []map[string]any
+	w := NewUniversalIOWriter(fakeComposeServer(t, &composed))
+	w.cfg.metadata = map[string]string{"owner": "team-a"}
+	w.cfg.headers = objectHeaders{cacheControl: "no-cache", contentDisposition: "attachment", contentEncoding: "gzip", contentLanguage: "ja"}
+
+	// 32個を超えるソースは繰り返し連結されるため、すべての連結で設定されることを確認する
+	srcs := make([]string, maxComposeSources+1)
+	for i := range srcs {
+		srcs[i] = "gs://bucket/part" + strings.Repeat("x", i)
+	}
+	if err := w.Compose(context.Background(), "gs://bucket/out.csv", srcs...); err != nil {
+		t.Fatalf("Compose() = %v", err)
+	}
+	if len(composed) != 2 {
+		t.Fatalf("連結の回数 = %d, want 2", len(composed))
+	}
+	for i, dst := range composed {
+		want := map[string]string{
+			"contentType":        "text/csv",
+			"cacheControl":       "no-cache",
+			"contentDisposition": "attachment",
+			"contentEncoding":    "gzip",
+			"contentLanguage":    "ja",
+		}
+		for key, value := range want {
+			if dst[key] != value {
+				t.Errorf("%d 回目の連結の %s = %v, want %q", i+1, key, dst[key], value)
+			}
+		}
+		md, _ := dst["metadata"].(map[string]any)
+		if !maps.Equal(md, map[string]any{"owner": "team-a"}) {
+			t.Errorf("%d 回目の連結のメタデータ = %v, want owner=team-a", i+1, md)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package remoteio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// GCSParallelWriter は、大きなローカルファイルを分割して並行して GCS へアップロードするためのインターフェースです。
+type GCSParallelWriter interface {
+	// WriteToGCSParallel は、ローカルファイル localPath を範囲ごとに一時オブジェクトとして並行してアップロードし、
+	// Compose API で連結して、指定されたバケットとオブジェクトパスに書き込みます。
+	WriteToGCSParallel(ctx context.Context, bucketName, objectPath, localPath, contentType string, opts ...SliceOption) error
+}
+
+// WriteToGCSParallel は GCSParallelWriter インターフェースを実装します。
+// 分割のサイズと並行数は、分割ダウンロードと同じ WithSliceSize と WithSliceParallelism で指定します。
+// カスタムメタデータと HTTP ヘッダー (Cache-Control など) は、最後の連結で作成するオブジェクトに設定します。
+// 一時オブジェクトは、成功・失敗にかかわらず終了時に削除します。ただし WithUploadCheckpoint を指定した場合は、
+// 失敗時にアップロード済みの一時オブジェクトを残し、再実行時に再利用します。
+// 各範囲を順に検査できないため、バリデータが設定されている場合や、ファイルが分割のサイズ以下の場合は WriteToGCS で1本のストリームとしてアップロードします。
+func (w *UniversalIOWriter) WriteToGCSParallel(ctx context.Context, bucketName, objectPath, localPath, contentType string, opts ...SliceOption) (err error) {
+	defer classifyError(&err)
+	targetURI := fmt.Sprintf("gs://%s/%s", bucketName, objectPath)
+	o := newSliceOptions(opts)
+
+	file, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf(Message("ローカルファイルのオープンに失敗しました: %w"), err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf(Message("ローカルファイルの情報の取得に失敗しました: %w"), err)
+	}
+	size := info.Size()
+	if len(w.cfg.validators) > 0 || size <= o.size {
+		return w.WriteToGCS(ctx, bucketName, objectPath, file, contentType)
+	}
+	if err := w.cfg.faults.beforeOp("WriteToGCSParallel", targetURI); err != nil {
+		return err
+	}
+	// 一時オブジェクトの名前からは判定できないため、Content-Type は連結後のオブジェクトの名前とファイルの先頭から判定する
+	contentType, _, err = resolveContentType(objectPath, contentType, io.NewSectionReader(file, 0, sniffLen))
+	if err != nil {
+		return err
+	}
+
+	// 1. 進行状況を読み込む (保存しない場合や、前回とファイルや分割のサイズが異なる場合は最初からアップロードする)
+	cp := &uploadCheckpoint{
+		path:        o.checkpoint,
+		Destination: targetURI,
+		Source:      localPath,
+		Size:        size,
+		ModTime:     info.ModTime(),
+		PartSize:    o.size,
+		logger:      w.cfg.log(),
+	}
+	if err := cp.load(); err != nil {
+		return err
+	}
+	if cp.PartPrefix == "" {
+		cp.PartPrefix = fmt.Sprintf("%s.remoteio-part-%d-", objectPath, time.Now().UnixNano())
+	}
+
+	w.cfg.log().Info(Message("GCS並行アップロード開始"),
+		slog.String("uri", targetURI),
+		slog.Int64("bytes", size),
+		slog.Int64("part_size", o.size),
+		slog.Int("resumed_parts", len(cp.Completed)),
+	)
+
+	var parts []string
+	for offset := int64(0); offset < size; offset += o.size {
+		parts = append(parts, fmt.Sprintf("gs://%s/%s%06d", bucketName, cp.PartPrefix, len(parts)))
+	}
+	succeeded := false
+	defer func() {
+		// 進行状況を保存する場合は、失敗時に一時オブジェクトを残して再開に使用する
+		if !succeeded && cp.path != "" {
+			return
+		}
+		w.deleteParts(ctx, parts)
+	}()
+
+	// 2. 未アップロードの範囲を一時オブジェクトとして並行してアップロードする
+	// (上書きの防止は最後の連結で行い、前回の実行で残った一時オブジェクトは上書きする)
+	partWriter := *w
+	partWriter.cfg.noClobber = false
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(o.parallelism)
+	for i, partURI := range parts {
+		offset := int64(i) * o.size
+		length := min(o.size, size-offset)
+		if cp.done(i) && w.partExists(ctx, partURI, length) {
+			continue
+		}
+		section := io.NewSectionReader(file, offset, length)
+		g.Go(func() error {
+			_, partPath, err := ParseGCSURI(partURI)
+			if err != nil {
+				return err
+			}
+			if err := partWriter.WriteToGCS(gctx, bucketName, partPath, section, contentType); err != nil {
+				return err
+			}
+			return cp.complete(i)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return fmt.Errorf(Message("GCSへの並行アップロードに失敗しました (URI: %s): %w"), targetURI, err)
+	}
+
+	// 3. 一時オブジェクトを連結して、最終的なオブジェクトを作成する
+	if err := w.Compose(ctx, targetURI, parts...); err != nil {
+		return err
+	}
+	succeeded = true
+	cp.remove()
+
+	w.cfg.log().Info(Message("GCS並行アップロード完了"), slog.String("uri", targetURI), slog.Int("parts", len(parts)))
+	return nil
+}
+
+// deleteParts は、並行アップロードの一時オブジェクトを削除します。
+// 削除に失敗しても、アップロードの結果は変わらないため警告に留めます。
+func (w *UniversalIOWriter) deleteParts(ctx context.Context, parts []string) {
+	for _, uri := range parts {
+		err := w.deleteGCSObject(context.WithoutCancel(ctx), uri)
+		if err != nil && !isNotExist(err) {
+			w.cfg.log().Warn(Message("並行アップロード用の一時オブジェクトの削除に失敗しました"), slog.String("uri", uri), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// partExists は、再開時に再利用する一時オブジェクトが、期待するサイズで存在するかどうかを確認します。
+func (w *UniversalIOWriter) partExists(ctx context.Context, partURI string, size int64) bool {
+	bucketName, objectPath, err := ParseGCSURI(partURI)
+	if err != nil {
+		return false
+	}
+	client, err := w.gcs()
+	if err != nil {
+		return false
+	}
+	attrs, err := w.cfg.gcsBucket(client, bucketName).Object(objectPath).Attrs(ctx)
+	return err == nil && attrs.Size == size
+}
+
+// uploadCheckpoint は、並行複合アップロードの進行状況です。path が空の場合は保存しません。
+type uploadCheckpoint struct {
+	path   string
+	logger *slog.Logger
+	mu     sync.Mutex
+
+	Destination string    `json:"destination"`
+	Source      string    `json:"source"`
+	Size        int64     `json:"size"`
+	ModTime     time.Time `json:"mod_time"`
+	PartSize    int64     `json:"part_size"`
+	PartPrefix  string    `json:"part_prefix"`
+	Completed   []int     `json:"completed"` // アップロード済みの範囲の番号
+}
+
+// load は、保存された進行状況を読み込みます。アップロード先、ファイルと分割のサイズが一致する場合のみ再利用します。
+func (c *uploadCheckpoint) load() error {
+	if c.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf(Message("アップロードの進行状況(%s)の読み込みに失敗しました: %w"), c.path, err)
+	}
+	var saved uploadCheckpoint
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf(Message("アップロードの進行状況(%s)の解析に失敗しました: %w"), c.path, err)
+	}
+	if saved.Destination != c.Destination || saved.Source != c.Source || saved.Size != c.Size ||
+		!saved.ModTime.Equal(c.ModTime) || saved.PartSize != c.PartSize {
+		c.logger.Info(Message("ファイルまたは分割のサイズが前回と異なるため、最初からアップロードします"), slog.String("checkpoint", c.path))
+		return nil
+	}
+	c.PartPrefix = saved.PartPrefix
+	c.Completed = saved.Completed
+	return nil
+}
+
+// done は、i 番目の範囲がアップロード済みとして記録されているかどうかを返します。
+func (c *uploadCheckpoint) done(i int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, n := range c.Completed {
+		if n == i {
+			return true
+		}
+	}
+	return false
+}
+
+// complete は、i 番目の範囲をアップロード済みとして記録し、進行状況を保存します。
+func (c *uploadCheckpoint) complete(i int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Completed = append(c.Completed, i)
+	if c.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf(Message("アップロードの進行状況の保存先の作成に失敗しました: %w"), err)
+	}
+	// 書き込み途中で中断されても壊れた状態を残さないよう、一時ファイルに書き込んでから置き換える
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf(Message("アップロードの進行状況(%s)の保存に失敗しました: %w"), c.path, err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf(Message("アップロードの進行状況(%s)の保存に失敗しました: %w"), c.path, err)
+	}
+	return nil
+}
+
+// remove は、アップロードの完了後に進行状況を削除します。
+func (c *uploadCheckpoint) remove() {
+	if c.path == "" {
+		return
+	}
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		c.logger.Warn(Message("アップロードの進行状況の削除に失敗しました"), slog.String("checkpoint", c.path), slog.String("error", err.Error()))
+	}
+}
+
+// 型アサーションチェック
+var _ GCSParallelWriter = (*UniversalIOWriter)(nil)
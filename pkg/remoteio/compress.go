@@ -0,0 +1,109 @@
+package remoteio
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// compressionExts は、DecompressReader が展開できる圧縮形式の拡張子です。
+var compressionExts = []string{".gz", ".zst"}
+
+// TrimCompressionExt は、name の末尾が圧縮形式の拡張子 (.gz / .zst) の場合に、拡張子を除いた名前と true を返します。
+// それ以外の場合は name と false を返します。展開した内容の書き込み先の名前を決めるために使用します。
+func TrimCompressionExt(name string) (string, bool) {
+	ext := strings.ToLower(path.Ext(name))
+	for _, e := range compressionExts {
+		if ext == e {
+			return name[:len(name)-len(ext)], true
+		}
+	}
+	return name, false
+}
+
+// DecompressReader は、name の拡張子に対応する形式 (.gz: gzip、.zst: Zstandard) で r を展開しながら読み込むリーダーを返します。
+// 拡張子が圧縮形式でない場合は、r をそのまま返します。返されたリーダーは、読み込み後に必ず Close してください (r は閉じません)。
+// GCS の Content-Encoding: gzip のオブジェクトを読み込む場合は、二重に展開しないよう WithReadCompressed(true) で開いてください。
+func DecompressReader(r io.Reader, name string) (io.ReadCloser, error) {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".gz":
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf(Message("gzip 形式の内容の展開に失敗しました (%s): %w"), name, err)
+		}
+		return zr, nil
+	case ".zst":
+		zr, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, fmt.Errorf(Message("Zstandard 形式の内容の展開に失敗しました (%s): %w"), name, err)
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		return io.NopCloser(r), nil
+	}
+}
+
+// WithGzip は、内容を gzip で圧縮しながら書き込み、書き込み先に Content-Encoding: gzip を設定します。
+// Content-Type は圧縮前の内容 (と書き込み先の拡張子) から判定されます。
+// GCS では、Content-Encoding: gzip のオブジェクトは読み込み時に展開されて返されます (解凍トランスコーディング)。
+// GCS、S3 と Azure への書き込みでのみ指定でき、他の書き込み先ではエラーになります。
+func WithGzip() WriteOption {
+	return func(o *writeOptions) {
+		o.gzip = true
+	}
+}
+
+// WithReadCompressed は、Content-Encoding: gzip の GCS オブジェクトを読み込む場合に、compressed が true であれば
+// 保存されている圧縮済みの内容をそのまま返し、false (既定) であれば展開して返す (解凍トランスコーディング) ことを指定します。
+// 圧縮済みのまま読み込むと転送量が減り、別の書き込み先へそのままコピーする場合に高速です。
+// GCS 以外の読み込みでは無視されます (S3 と Azure は常に保存されている内容を返します)。
+func WithReadCompressed(compressed bool) ReadOption {
+	return func(o *readOptions) {
+		o.compressed = compressed
+	}
+}
+
+// gzipReader は、src の内容を gzip で圧縮して返すリーダーです。読み込みに応じて src を読み込み、圧縮します。
+type gzipReader struct {
+	src  io.Reader
+	zw   *gzip.Writer
+	buf  bytes.Buffer // 圧縮済みでまだ返していない内容
+	in   []byte       // src から読み込むためのバッファ
+	done bool         // src を最後まで読み込み、圧縮を終えた場合は true
+}
+
+// newGzipReader は、src を圧縮して返すリーダーを返します。
+func newGzipReader(src io.Reader) *gzipReader {
+	g := &gzipReader{src: src, in: make([]byte, 32<<10)}
+	g.zw = gzip.NewWriter(&g.buf)
+	return g
+}
+
+// Read は io.Reader インターフェースを実装します。
+func (g *gzipReader) Read(p []byte) (int, error) {
+	for g.buf.Len() == 0 && !g.done {
+		n, err := g.src.Read(g.in)
+		if n > 0 {
+			if _, werr := g.zw.Write(g.in[:n]); werr != nil {
+				return 0, werr
+			}
+		}
+		if err == io.EOF {
+			if cerr := g.zw.Close(); cerr != nil {
+				return 0, cerr
+			}
+			g.done = true
+		} else if err != nil {
+			return 0, err
+		}
+	}
+	if g.buf.Len() == 0 {
+		return 0, io.EOF
+	}
+	return g.buf.Read(p)
+}
@@ -0,0 +1,37 @@
+package remoteio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+)
+
+// sniffLen は、Content-Type の判定に使用するストリーム先頭のバイト数です (http.DetectContentType が参照する最大長)。
+const sniffLen = 512
+
+// resolveContentType は、書き込み先に設定する Content-Type を決定します。
+// contentType が指定されている場合はそれを使用し、空の場合は書き込み先の名前 name の拡張子から mime.TypeByExtension で判定します。
+// 拡張子から判定できない場合は、r の先頭 512 バイトを http.DetectContentType で判定します (読み込んだ内容は戻り値のリーダーに含まれます)。
+// 内容が空の場合は DefaultContentType を返します。
+func resolveContentType(name, contentType string, r io.Reader) (string, io.Reader, error) {
+	if contentType != "" {
+		return contentType, r, nil
+	}
+	if byExt := mime.TypeByExtension(path.Ext(name)); byExt != "" {
+		return byExt, r, nil
+	}
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", nil, fmt.Errorf(Message("Content-Type の判定のための読み込みに失敗しました: %w"), err)
+	}
+	head = head[:n]
+	r = io.MultiReader(bytes.NewReader(head), r)
+	if n == 0 {
+		return DefaultContentType, r, nil
+	}
+	return http.DetectContentType(head), r, nil
+}
@@ -0,0 +1,87 @@
+package remoteio
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"cloud.google.com/go/storage"
+)
+
+// ErrCopyUnsupported は、データを転送せずにコピーできない組み合わせ (異なるバックエンド間、ローカルファイルなど) の場合に
+// CopyObject が返すエラーです。この場合、呼び出し元は読み込んだ内容を書き込んでください。
+var ErrCopyUnsupported = NewMessageError("remoteio: サーバー側でコピーできない組み合わせです")
+
+// Copier は、データをクライアントに転送せずに、サーバー側でオブジェクトをコピーするためのインターフェースです。
+type Copier interface {
+	// CopyObject は、srcURI のオブジェクトを dstURI へサーバー側でコピーします。
+	CopyObject(ctx context.Context, srcURI, dstURI string) error
+}
+
+// CopyObject は Copier インターフェースを実装します。
+// GCS 間 (バケットをまたぐ場合を含む) と S3 間はサーバー側でコピーし、Content-Type やカスタムメタデータなどはコピー元から引き継がれます。
+// コンテンツはストリーミングされないため、バリデータが設定されている場合は内容を検査できないので ErrCopyUnsupported を返します。
+func (w *UniversalIOWriter) CopyObject(ctx context.Context, srcURI, dstURI string) (err error) {
+	defer classifyError(&err)
+	h, ok, err := lookupScheme(srcURI)
+	if err != nil {
+		return err
+	}
+	if !ok || h.copy == nil || SchemeOf(srcURI) != SchemeOf(dstURI) || len(w.cfg.validators) > 0 {
+		return fmt.Errorf("%w: %s -> %s", ErrCopyUnsupported, srcURI, dstURI)
+	}
+	if err := w.cfg.faults.beforeOp("CopyObject", srcURI); err != nil {
+		return err
+	}
+	ctx, cancel := w.cfg.opContext(ctx)
+	defer cancel()
+	if err := h.copy(ctx, w, srcURI, dstURI); err != nil {
+		return err
+	}
+
+	w.cfg.log().Info(Message("コピー完了"), slog.String("source", srcURI), slog.String("destination", dstURI))
+	return nil
+}
+
+// copyGCSObject は、GCS オブジェクトをサーバー側でコピーします。
+func (w *UniversalIOWriter) copyGCSObject(ctx context.Context, srcURI, dstURI string) error {
+	client, err := w.gcs()
+	if err != nil {
+		return fmt.Errorf(Message("GCSクライアントが初期化されていないため、GCSオブジェクトをコピーできません (URI: %s): %w"), srcURI, err)
+	}
+	// コピー元は "#generation" で世代番号を指定できる
+	srcBase, srcGeneration := SplitGCSGeneration(srcURI)
+	srcBucket, srcObject, err := ParseGCSURI(srcBase)
+	if err != nil {
+		return fmt.Errorf(Message("GCS URIのパース失敗: %w"), err)
+	}
+	dstBucket, dstObject, err := ParseGCSURI(dstURI)
+	if err != nil {
+		return fmt.Errorf(Message("GCS URIのパース失敗: %w"), err)
+	}
+	if srcObject == "" || dstObject == "" {
+		return invalidURIError("無効なGCS URI形式です: %s -> %s (オブジェクト名が空です)", srcURI, dstURI)
+	}
+
+	src := w.cfg.gcsBucket(client, srcBucket).Object(srcObject)
+	if srcGeneration > 0 {
+		src = src.Generation(srcGeneration)
+	}
+	dst := w.cfg.gcsBucket(client, dstBucket).Object(dstObject)
+	if w.cfg.noClobber {
+		dst = dst.If(storage.Conditions{DoesNotExist: true})
+	}
+	// Copier は、大きなオブジェクトやストレージクラスの異なるバケット間でも、完了するまで書き換えを繰り返す
+	copier := dst.CopierFrom(src)
+	copier.DestinationKMSKeyName = w.cfg.kmsKeyName
+	if _, err := copier.Run(ctx); err != nil {
+		if w.cfg.noClobber && isPreconditionFailed(err) {
+			return destinationExists(dstURI)
+		}
+		return fmt.Errorf(Message("GCSオブジェクトのコピーに失敗しました (%s -> %s): %w"), srcURI, dstURI, err)
+	}
+	return nil
+}
+
+// 型アサーションチェック
+var _ Copier = (*UniversalIOWriter)(nil)
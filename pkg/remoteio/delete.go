@@ -0,0 +1,49 @@
+package remoteio
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// Deleter は、URIで指定されたファイルまたはオブジェクトを削除するためのインターフェースです。
+type Deleter interface {
+	// Delete は、uri (GCS URI、S3 URI、Azure URI、SFTP URI、またはローカルファイルパス) を削除します。
+	Delete(ctx context.Context, uri string) error
+}
+
+// Delete は Deleter インターフェースを実装します。
+// URIのスキームに登録されたバックエンドで削除し、スキームがない場合はローカルファイルを削除します。
+// RegisterScheme で登録した独自スキームの削除はサポートされません。
+func (w *UniversalIOWriter) Delete(ctx context.Context, uri string) (err error) {
+	defer classifyError(&err)
+	if err := w.cfg.faults.beforeOp("Delete", uri); err != nil {
+		return err
+	}
+
+	h, ok, err := lookupScheme(uri)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !ok:
+		if err := os.Remove(uri); err != nil {
+			return fmt.Errorf(Message("ローカルファイル(%s)の削除に失敗しました: %w"), uri, err)
+		}
+	case h.remove != nil:
+		ctx, cancel := w.cfg.opContext(ctx)
+		defer cancel()
+		if err := h.remove(ctx, w, uri); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf(Message("スキーム %s:// は削除をサポートしていません: %s"), SchemeOf(uri), uri)
+	}
+
+	w.cfg.log().Info(Message("削除完了"), slog.String("uri", uri))
+	return nil
+}
+
+// 型アサーションチェック
+var _ Deleter = (*UniversalIOWriter)(nil)
@@ -0,0 +1,303 @@
+package remoteio
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	cloudkms "google.golang.org/api/cloudkms/v1"
+	"google.golang.org/api/option"
+)
+
+// ErrDecryptionFailed は、暗号化された内容を復号できなかった (鍵が異なる、内容が改ざんまたは切り詰められている) 場合に返されるエラーです。
+var ErrDecryptionFailed = NewMessageError("remoteio: 復号に失敗しました (鍵が異なるか、内容が改ざんされています)")
+
+const (
+	// encryptionMagic は、EncryptReader が出力する内容の先頭に付加される識別子 (形式のバージョンを含む) です。
+	encryptionMagic = "RIOENC\x00\x01"
+	// encryptionChunkSize は、暗号化する平文の区切りの大きさです。区切りごとに認証タグ (16 バイト) が付加されます。
+	encryptionChunkSize = 64 << 10
+	// encryptionNoncePrefixLen は、ナンスのうちオブジェクトごとに乱数で決める部分の長さです (残りは区切りの番号と最後の区切りかどうか)。
+	encryptionNoncePrefixLen = 7
+	// dataKeyLen は、オブジェクトごとに生成するデータ鍵 (AES-256) の長さです。
+	dataKeyLen = 32
+)
+
+// KeyWrapper は、オブジェクトごとに生成したデータ鍵を、鍵暗号鍵で暗号化 (ラップ) して内容と一緒に保存するためのインターフェースです (エンベロープ暗号化)。
+type KeyWrapper interface {
+	// WrapKey は、データ鍵を暗号化します。
+	WrapKey(ctx context.Context, dataKey []byte) ([]byte, error)
+	// UnwrapKey は、WrapKey で暗号化したデータ鍵を復号します。
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// localKeyWrapper は、ローカルの鍵 (AES-256) でデータ鍵をラップする KeyWrapper です。
+type localKeyWrapper struct {
+	aead cipher.AEAD
+}
+
+// NewLocalKeyWrapper は、32 バイトの鍵 key (AES-256-GCM) でデータ鍵をラップする KeyWrapper を返します。
+func NewLocalKeyWrapper(key []byte) (KeyWrapper, error) {
+	if len(key) != dataKeyLen {
+		return nil, fmt.Errorf(Message("暗号鍵は %d バイトである必要があります (%d バイト)"), dataKeyLen, len(key))
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &localKeyWrapper{aead: aead}, nil
+}
+
+// WrapKey は KeyWrapper インターフェースを実装します。ラップした鍵は、ナンスと暗号文を連結したものです。
+func (w *localKeyWrapper) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	nonce := make([]byte, w.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf(Message("ナンスの生成に失敗しました: %w"), err)
+	}
+	return w.aead.Seal(nonce, nonce, dataKey, nil), nil
+}
+
+// UnwrapKey は KeyWrapper インターフェースを実装します。
+func (w *localKeyWrapper) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	n := w.aead.NonceSize()
+	if len(wrapped) < n {
+		return nil, ErrDecryptionFailed
+	}
+	dataKey, err := w.aead.Open(nil, wrapped[:n], wrapped[n:], nil)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return dataKey, nil
+}
+
+// kmsKeyWrapper は、Cloud KMS の鍵でデータ鍵をラップする KeyWrapper です。
+type kmsKeyWrapper struct {
+	keys    *cloudkms.ProjectsLocationsKeyRingsCryptoKeysService
+	keyName string
+}
+
+// NewKMSKeyWrapper は、Cloud KMS の鍵 keyName ("projects/P/locations/L/keyRings/R/cryptoKeys/K") でデータ鍵をラップする KeyWrapper を返します。
+// 認証にはアプリケーションのデフォルト認証情報を使用し、opts でクライアントの構成を変更できます。
+// 鍵の暗号化と復号の権限 (roles/cloudkms.cryptoKeyEncrypterDecrypter) が必要です。
+func NewKMSKeyWrapper(ctx context.Context, keyName string, opts ...option.ClientOption) (KeyWrapper, error) {
+	svc, err := cloudkms.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf(Message("Cloud KMS クライアントの初期化に失敗しました: %w"), err)
+	}
+	return &kmsKeyWrapper{keys: svc.Projects.Locations.KeyRings.CryptoKeys, keyName: keyName}, nil
+}
+
+// WrapKey は KeyWrapper インターフェースを実装します。
+func (w *kmsKeyWrapper) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	resp, err := w.keys.Encrypt(w.keyName, &cloudkms.EncryptRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(dataKey),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf(Message("Cloud KMS によるデータ鍵の暗号化に失敗しました (鍵: %s): %w"), w.keyName, err)
+	}
+	return base64.StdEncoding.DecodeString(resp.Ciphertext)
+}
+
+// UnwrapKey は KeyWrapper インターフェースを実装します。
+func (w *kmsKeyWrapper) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	resp, err := w.keys.Decrypt(w.keyName, &cloudkms.DecryptRequest{
+		Ciphertext: base64.StdEncoding.EncodeToString(wrapped),
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf(Message("%w: Cloud KMS によるデータ鍵の復号に失敗しました (鍵: %s): %w"), ErrDecryptionFailed, w.keyName, err)
+	}
+	return base64.StdEncoding.DecodeString(resp.Plaintext)
+}
+
+// EncryptReader は、r の内容をクライアント側で暗号化して読み込むリーダーを返します。
+// オブジェクトごとに生成したデータ鍵で、内容を 64KiB ごとに AES-256-GCM で暗号化し、データ鍵は kw でラップして先頭に保存します。
+// 区切りごとに番号と最後の区切りかどうかを認証するため、内容の並べ替えや切り詰めも DecryptReader で検出されます。
+// 書き込み先には平文が送信されないため、バケットの暗号化の設定にかかわらず内容を保護できます。
+func EncryptReader(ctx context.Context, r io.Reader, kw KeyWrapper) (io.Reader, error) {
+	dataKey := make([]byte, dataKeyLen)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf(Message("データ鍵の生成に失敗しました: %w"), err)
+	}
+	wrapped, err := kw.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) > 0xffff {
+		return nil, fmt.Errorf(Message("ラップしたデータ鍵が大きすぎます (%d バイト)"), len(wrapped))
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// ヘッダー: 識別子、ラップしたデータ鍵の長さと内容、ナンスの乱数部分
+	var header bytes.Buffer
+	header.WriteString(encryptionMagic)
+	binary.Write(&header, binary.BigEndian, uint16(len(wrapped)))
+	header.Write(wrapped)
+	prefix := make([]byte, encryptionNoncePrefixLen)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, fmt.Errorf(Message("ナンスの生成に失敗しました: %w"), err)
+	}
+	header.Write(prefix)
+
+	return &cryptReader{
+		src:     r,
+		aead:    aead,
+		stream:  newChunkStream(prefix, header.Bytes()),
+		in:      encryptionChunkSize,
+		pending: header.Bytes(),
+		seal:    true,
+	}, nil
+}
+
+// DecryptReader は、EncryptReader で暗号化された r の内容を復号して読み込むリーダーを返します。
+// ヘッダーを読み込んでデータ鍵を kw でアンラップするため、r の先頭を読み込みます。
+// 鍵が異なる場合や、内容が改ざんまたは切り詰められている場合は ErrDecryptionFailed を返します。
+// 区切りごとに認証してから返すため、途中で失敗した場合も、それまでに返した内容は改ざんされていません。
+func DecryptReader(ctx context.Context, r io.Reader, kw KeyWrapper) (io.Reader, error) {
+	var header bytes.Buffer
+	tr := io.TeeReader(r, &header)
+	magic := make([]byte, len(encryptionMagic))
+	if _, err := io.ReadFull(tr, magic); err != nil || string(magic) != encryptionMagic {
+		return nil, fmt.Errorf(Message("%w: 暗号化された内容ではありません"), ErrDecryptionFailed)
+	}
+	var wrappedLen uint16
+	if err := binary.Read(tr, binary.BigEndian, &wrappedLen); err != nil {
+		return nil, fmt.Errorf(Message("%w: ヘッダーが不完全です"), ErrDecryptionFailed)
+	}
+	wrapped := make([]byte, wrappedLen)
+	prefix := make([]byte, encryptionNoncePrefixLen)
+	if _, err := io.ReadFull(tr, wrapped); err != nil {
+		return nil, fmt.Errorf(Message("%w: ヘッダーが不完全です"), ErrDecryptionFailed)
+	}
+	if _, err := io.ReadFull(tr, prefix); err != nil {
+		return nil, fmt.Errorf(Message("%w: ヘッダーが不完全です"), ErrDecryptionFailed)
+	}
+	dataKey, err := kw.UnwrapKey(ctx, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecryptionFailed, err)
+	}
+	return &cryptReader{
+		src:    r,
+		aead:   aead,
+		stream: newChunkStream(prefix, header.Bytes()),
+		in:     encryptionChunkSize + aead.Overhead(),
+	}, nil
+}
+
+// newGCM は、鍵 key の AES-GCM を返します。
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf(Message("暗号の初期化に失敗しました: %w"), err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkStream は、区切りごとのナンス (乱数部分、区切りの番号、最後の区切りかどうか) と追加認証データ (ヘッダー) を管理します。
+type chunkStream struct {
+	nonce   []byte
+	counter uint32
+	aad     []byte
+}
+
+// newChunkStream は、ナンスの乱数部分 prefix とヘッダー aad の chunkStream を返します。
+func newChunkStream(prefix, aad []byte) *chunkStream {
+	nonce := make([]byte, encryptionNoncePrefixLen+5)
+	copy(nonce, prefix)
+	return &chunkStream{nonce: nonce, aad: aad}
+}
+
+// next は、次の区切りのナンスを返します。last は最後の区切りかどうかです。
+func (s *chunkStream) next(last bool) ([]byte, error) {
+	if s.counter == ^uint32(0) {
+		return nil, errors.New(Message("暗号化できる内容の大きさの上限を超えました"))
+	}
+	binary.BigEndian.PutUint32(s.nonce[encryptionNoncePrefixLen:], s.counter)
+	s.nonce[len(s.nonce)-1] = 0
+	if last {
+		s.nonce[len(s.nonce)-1] = 1
+	}
+	s.counter++
+	return s.nonce, nil
+}
+
+// cryptReader は、src を区切りごとに暗号化 (seal が true の場合) または復号して返すリーダーです。
+type cryptReader struct {
+	src     io.Reader
+	aead    cipher.AEAD
+	stream  *chunkStream
+	seal    bool   // true の場合は暗号化、false の場合は復号する
+	in      int    // 1つの区切りとして読み込む入力の大きさ
+	buf     []byte // 入力の区切りと、最後の区切りかどうかを判定するための先読みの1バイト
+	carry   bool   // buf の先頭に前回の先読みの1バイトがある場合は true
+	out     []byte // 区切りごとの出力のバッファ
+	pending []byte // まだ返していない出力
+	done    bool   // 最後の区切りを処理した場合は true
+}
+
+// Read は io.Reader インターフェースを実装します。
+func (c *cryptReader) Read(p []byte) (int, error) {
+	for len(c.pending) == 0 {
+		if c.done {
+			return 0, io.EOF
+		}
+		if err := c.nextChunk(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+	return n, nil
+}
+
+// nextChunk は、入力の次の区切りを読み込んで処理し、結果を pending に設定します。
+func (c *cryptReader) nextChunk() error {
+	if c.buf == nil {
+		c.buf = make([]byte, c.in+1)
+	}
+	start := 0
+	if c.carry {
+		start = 1
+	}
+	n, err := io.ReadFull(c.src, c.buf[start:])
+	n += start
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		c.done = true
+	case err != nil:
+		return err
+	}
+	chunk := c.buf[:min(n, c.in)]
+	nonce, err := c.stream.next(c.done)
+	if err != nil {
+		return err
+	}
+	if c.seal {
+		c.out = c.aead.Seal(c.out[:0], nonce, chunk, c.stream.aad)
+	} else {
+		c.out, err = c.aead.Open(c.out[:0], nonce, chunk, c.stream.aad)
+		if err != nil {
+			return ErrDecryptionFailed
+		}
+	}
+	c.pending = c.out
+	// 先読みした1バイトを次の区切りの先頭に移す
+	if !c.done {
+		c.buf[0] = c.buf[c.in]
+		c.carry = true
+	}
+	return nil
+}
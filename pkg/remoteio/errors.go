@@ -0,0 +1,103 @@
+package remoteio
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"google.golang.org/api/googleapi"
+)
+
+// 失敗の分類を表すエラー。InputReader と OutputWriter の各メソッドが返すエラーは、
+// 原因を判定できる場合にこれらを含むため、バックエンドやメッセージの文言に依存せずに errors.Is で判定できます。
+var (
+	// ErrNotFound は、ファイル、オブジェクトまたはバケットが存在しないことを示します
+	// (GCS の 404、storage.ErrObjectNotExist、S3 の NoSuchKey、Azure の BlobNotFound、fs.ErrNotExist など)。
+	ErrNotFound = NewMessageError("remoteio: 見つかりません")
+	// ErrPermissionDenied は、認証または権限が不足していることを示します (HTTP の 401 と 403、fs.ErrPermission など)。
+	ErrPermissionDenied = NewMessageError("remoteio: 権限がありません")
+	// ErrInvalidURI は、URI の形式が正しくないことを示します (バケット名やオブジェクト名が空の場合など)。
+	ErrInvalidURI = NewMessageError("remoteio: 無効なURIです")
+	// ErrAlreadyExists は、上書きの防止 (WithNoClobber) が指定された書き込みで、書き込み先が既に存在することを示します。
+	// この場合、既存のファイルやオブジェクトは変更されません。CreateBucket で作成するバケットが既に存在する場合にも使用します。
+	ErrAlreadyExists = NewMessageError("remoteio: 書き込み先が既に存在します")
+	// ErrClientClosed は、クローズされたクライアントやファクトリを使用したことを示します。
+	ErrClientClosed = NewMessageError("remoteio: クライアントは既にクローズされています")
+)
+
+// errorKinds は、Classify が判定する分類の一覧です。
+var errorKinds = []error{ErrNotFound, ErrPermissionDenied, ErrInvalidURI, ErrAlreadyExists, ErrClientClosed}
+
+// Error は、元のエラー Err に失敗の分類 Kind を付与したエラーです。
+// メッセージは元のエラーのままで、errors.Is(err, ErrNotFound) による分類の判定と、
+// errors.As による元のエラー (*googleapi.Error など) の取り出しの両方ができます。
+type Error struct {
+	Kind error // ErrNotFound、ErrPermissionDenied、ErrInvalidURI、ErrAlreadyExists、ErrClientClosed のいずれか
+	Err  error // 元のエラー
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+
+func (e *Error) Unwrap() []error { return []error{e.Kind, e.Err} }
+
+// Classify は、err の原因を判定し、分類 (ErrNotFound など) を含むエラーを返します。
+// err が既に分類を含む場合や、原因を判定できない場合は err をそのまま返します。nil の場合は nil を返します。
+// remoteio の各メソッドが返すエラーは分類済みのため、独自のバックエンドのエラーを判定する場合に使用します。
+func Classify(err error) error {
+	if err == nil || KindOf(err) != nil {
+		return err
+	}
+	var kind error
+	switch status := httpStatus(err); {
+	case isNotExist(err) || errors.Is(err, storage.ErrBucketNotExist) || status == http.StatusNotFound:
+		kind = ErrNotFound
+	case errors.Is(err, fs.ErrPermission) || status == http.StatusUnauthorized || status == http.StatusForbidden:
+		kind = ErrPermissionDenied
+	default:
+		return err
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// KindOf は、err が含む分類 (ErrNotFound など) を返します。分類を含まない場合は nil を返します。
+// 終了コードの決定など、分類ごとに処理を振り分ける場合に使用します。
+func KindOf(err error) error {
+	for _, kind := range errorKinds {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
+
+// classifyError は、*errp を Classify で分類します。名前付きの戻り値に対して defer で呼び出します。
+func classifyError(errp *error) {
+	*errp = Classify(*errp)
+}
+
+// invalidURIError は、URI の形式が正しくないことを示す、ErrInvalidURI を含むエラーを返します。
+// format は Message で翻訳してから使用します。
+func invalidURIError(format string, args ...any) error {
+	return &Error{Kind: ErrInvalidURI, Err: fmt.Errorf(Message(format), args...)}
+}
+
+// httpStatus は、err に含まれるバックエンドの HTTP レスポンスのステータスコードを返します。含まれない場合は 0 を返します。
+func httpStatus(err error) int {
+	var gErr *googleapi.Error
+	// S3 のエラー (*awshttp.ResponseError) は HTTP のステータスコードを返す
+	var s3Err interface{ HTTPStatusCode() int }
+	var azErr *azcore.ResponseError
+	switch {
+	case errors.As(err, &gErr):
+		return gErr.Code
+	case errors.As(err, &s3Err):
+		return s3Err.HTTPStatusCode()
+	case errors.As(err, &azErr):
+		return azErr.StatusCode
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,203 @@
+package remoteio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+// FaultInjectionEnv は、故障注入を有効にする環境変数の名前です。
+// 値の形式は ParseFaultConfig を参照してください。
+const FaultInjectionEnv = "REMOTEIO_FAULT_INJECTION"
+
+// FaultConfig は、リトライや再開処理の検証のために注入する故障の設定です。
+// 各 Rate は 0.0〜1.0 の確率で、操作 (Open または書き込み) ごとに判定されます。
+// テスト専用の機能であり、本番環境では使用しないでください。
+type FaultConfig struct {
+	TimeoutRate     float64       // 操作がタイムアウト (context.DeadlineExceeded) する確率
+	UnavailableRate float64       // 操作が 503 Service Unavailable で失敗する確率
+	PartialRate     float64       // ストリームが途中で切断 (io.ErrUnexpectedEOF) される確率
+	SlowRate        float64       // ストリームが低速になる確率
+	SlowDelay       time.Duration // 低速なストリームで Read ごとに挿入する待ち時間 (既定 100ms)
+	Seed            uint64        // 乱数シード (0 の場合は実行ごとに異なる)
+}
+
+// ParseFaultConfig は、"timeout=0.1,unavailable=0.05,partial=0.1,slow=0.2,slow_delay=50ms,seed=42"
+// 形式の文字列を FaultConfig に変換します。
+func ParseFaultConfig(spec string) (FaultConfig, error) {
+	var cfg FaultConfig
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return cfg, fmt.Errorf(Message("無効な故障注入の設定です: %q (key=value の形式で指定してください)"), field)
+		}
+
+		var err error
+		switch key {
+		case "timeout":
+			cfg.TimeoutRate, err = parseRate(value)
+		case "unavailable", "503":
+			cfg.UnavailableRate, err = parseRate(value)
+		case "partial":
+			cfg.PartialRate, err = parseRate(value)
+		case "slow":
+			cfg.SlowRate, err = parseRate(value)
+		case "slow_delay":
+			cfg.SlowDelay, err = time.ParseDuration(value)
+		case "seed":
+			cfg.Seed, err = strconv.ParseUint(value, 10, 64)
+		default:
+			return cfg, fmt.Errorf(Message("不明な故障注入の設定キーです: %q"), key)
+		}
+		if err != nil {
+			return cfg, fmt.Errorf(Message("故障注入の設定値が不正です (%s): %w"), field, err)
+		}
+	}
+	return cfg, nil
+}
+
+// parseRate は、0.0〜1.0 の確率を表す文字列を解析します。
+func parseRate(s string) (float64, error) {
+	rate, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if rate < 0 || rate > 1 {
+		return 0, fmt.Errorf(Message("確率は 0.0〜1.0 の範囲で指定してください: %v"), rate)
+	}
+	return rate, nil
+}
+
+// WithFaultInjection は、読み込みと書き込みに故障を注入します。
+// InputReader と OutputWriter の両方に適用されます。
+func WithFaultInjection(cfg FaultConfig) Option {
+	return func(c *config) {
+		c.faults = newFaultInjector(cfg)
+	}
+}
+
+// faultInjector は、FaultConfig に従って故障を発生させます。
+type faultInjector struct {
+	cfg FaultConfig
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func newFaultInjector(cfg FaultConfig) *faultInjector {
+	if cfg.SlowDelay <= 0 {
+		cfg.SlowDelay = 100 * time.Millisecond
+	}
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	return &faultInjector{cfg: cfg, rng: rand.New(rand.NewPCG(seed, seed))}
+}
+
+// roll は、確率 rate で true を返します。
+func (f *faultInjector) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rng.Float64() < rate
+}
+
+// beforeOp は、操作の開始時に判定されるタイムアウトや 503 エラーを返します。
+// f が nil (故障注入が無効) の場合は常に nil を返します。
+func (f *faultInjector) beforeOp(op, uri string) error {
+	if f == nil {
+		return nil
+	}
+	if f.roll(f.cfg.TimeoutRate) {
+		return fmt.Errorf(Message("故障注入: %s がタイムアウトしました (%s): %w"), op, uri, context.DeadlineExceeded)
+	}
+	if f.roll(f.cfg.UnavailableRate) {
+		return fmt.Errorf(Message("故障注入: %s が失敗しました (%s): %w"), op, uri, &googleapi.Error{
+			Code:    http.StatusServiceUnavailable,
+			Message: "fault injection: service unavailable",
+		})
+	}
+	return nil
+}
+
+// wrapStream は、ストリームに途中切断や遅延の故障を注入するリーダーを返します。
+// f が nil の場合は r をそのまま返します。
+func (f *faultInjector) wrapStream(r io.Reader) io.Reader {
+	if f == nil {
+		return r
+	}
+	fr := &faultyReader{r: r, cutoff: -1}
+	if f.roll(f.cfg.PartialRate) {
+		f.mu.Lock()
+		fr.cutoff = f.rng.Int64N(64 * 1024)
+		f.mu.Unlock()
+	}
+	if f.roll(f.cfg.SlowRate) {
+		fr.delay = f.cfg.SlowDelay
+	}
+	if fr.cutoff < 0 && fr.delay == 0 {
+		return r
+	}
+	return fr
+}
+
+// wrapReadCloser は、故障注入または進捗の通知が有効な場合に読み込みストリームをラップします。
+func (c *config) wrapReadCloser(rc io.ReadCloser) io.ReadCloser {
+	if c.faults == nil && c.progress == nil {
+		return rc
+	}
+	total := streamTotal(rc)
+	var r io.Reader = rc
+	if c.faults != nil {
+		r = c.faults.wrapStream(r)
+	}
+	if c.progress != nil {
+		r = NewProgressReader(r, total, c.progress)
+	}
+	return wrappedReadCloser{Reader: r, Closer: rc}
+}
+
+// faultyReader は、指定されたバイト数で切断、または Read ごとに遅延するリーダーです。
+type faultyReader struct {
+	r      io.Reader
+	cutoff int64 // 切断するまでの残りバイト数 (負の場合は切断しない)
+	delay  time.Duration
+}
+
+func (r *faultyReader) Read(p []byte) (int, error) {
+	if r.delay > 0 {
+		time.Sleep(r.delay)
+	}
+	if r.cutoff == 0 {
+		return 0, fmt.Errorf(Message("故障注入: ストリームが切断されました: %w"), io.ErrUnexpectedEOF)
+	}
+	if r.cutoff > 0 && int64(len(p)) > r.cutoff {
+		p = p[:r.cutoff]
+	}
+	n, err := r.r.Read(p)
+	if r.cutoff > 0 {
+		r.cutoff -= int64(n)
+	}
+	return n, err
+}
+
+// wrappedReadCloser は、ラップしたリーダー (faultyReader など) に元のストリームの Close を組み合わせます。
+type wrappedReadCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,373 @@
+package remoteio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+// =================================================================
+// 1. コンストラクタ
+// =================================================================
+
+// NewFS は、GCS バケットを読み取り専用の fs.FS として公開します。
+// 返される値は fs.ReadDirFS、fs.StatFS、fs.GlobFS も満たすため、fs.WalkDir や html/template.ParseFS など、
+// fs.FS を受け付ける標準ライブラリのコードへ、ローカルにファイルを展開せずにリモートのデータを渡せます。
+//
+// GCS にはディレクトリが存在しないため、"/" 区切りのオブジェクト名のプレフィックスをディレクトリとして扱います。
+// 開いたファイルは io.Seeker と io.ReaderAt も満たし、archive/zip などのランダムアクセスが必要な用途にも使用できます。
+// opts には WithRetryPolicy と WithBillingProject を指定でき、バケットへのリクエストに適用されます。
+func NewFS(client *storage.Client, bucket string, opts ...Option) fs.FS {
+	cfg := newConfig(opts)
+	return &gcsFS{ctx: context.Background(), bucket: cfg.gcsBucket(client, bucket)}
+}
+
+// ディレクトリの操作に関するエラー
+var (
+	errNotDirectory = NewMessageError("ディレクトリではありません")
+	errIsDirectory  = NewMessageError("ディレクトリです")
+)
+
+// gcsFS は、GCS バケットを fs.FS として扱うための実装です。
+// fs.FS のメソッドはコンテキストを受け取らないため、GCS へのリクエストには ctx を使用します。
+type gcsFS struct {
+	ctx    context.Context
+	bucket *storage.BucketHandle
+}
+
+// =================================================================
+// 2. fs.FS / fs.StatFS / fs.ReadDirFS / fs.GlobFS の実装
+// =================================================================
+
+// Open は fs.FS インターフェースを実装します。
+// オブジェクトの場合は読み込み用のファイルを、プレフィックスの場合はディレクトリを返します。
+func (f *gcsFS) Open(name string) (fs.File, error) {
+	info, err := f.stat("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return &gcsDir{fsys: f, name: name, info: info}, nil
+	}
+	return &gcsFile{fsys: f, name: name, info: info}, nil
+}
+
+// Stat は fs.StatFS インターフェースを実装します。
+func (f *gcsFS) Stat(name string) (fs.FileInfo, error) {
+	return f.stat("stat", name)
+}
+
+// ReadDir は fs.ReadDirFS インターフェースを実装し、name 直下のエントリを名前順で返します。
+func (f *gcsFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	entries, err := f.list(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	if len(entries) == 0 && name != "." {
+		// 空の一覧は、存在しないパスかオブジェクト (ファイル) のどちらか
+		info, err := f.stat("readdir", name)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, &fs.PathError{Op: "readdir", Path: name, Err: errNotDirectory}
+		}
+	}
+	return entries, nil
+}
+
+// Glob は fs.GlobFS インターフェースを実装します。
+// パターン中の最初のメタ文字より前の部分をプレフィックスとしてオブジェクトを一覧し、
+// 階層の数が一致するオブジェクト名とディレクトリ名をパターンと照合します。
+func (f *gcsFS) Glob(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	depth := strings.Count(pattern, "/") + 1
+
+	prefix := pattern
+	if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+		prefix = pattern[:i]
+	}
+
+	seen := map[string]bool{}
+	var matches []string
+	it := f.bucket.Objects(f.ctx, &storage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf(Message("GCSオブジェクトの一覧取得に失敗しました (prefix: %s): %w"), prefix, err)
+		}
+
+		// パターンと同じ階層数に切り詰めた名前 (より深いオブジェクトの場合はその親ディレクトリ) を照合する
+		parts := strings.SplitN(attrs.Name, "/", depth+1)
+		if len(parts) < depth {
+			continue
+		}
+		candidate := strings.Join(parts[:depth], "/")
+		if seen[candidate] || !fs.ValidPath(candidate) {
+			continue
+		}
+		seen[candidate] = true
+		if ok, _ := path.Match(pattern, candidate); ok {
+			matches = append(matches, candidate)
+		}
+	}
+	slices.Sort(matches)
+	return matches, nil
+}
+
+// =================================================================
+// 3. 内部ヘルパー
+// =================================================================
+
+// stat は、name がオブジェクトかプレフィックスかを判定して FileInfo を返します。
+func (f *gcsFS) stat(op, name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return dirInfo("."), nil
+	}
+
+	attrs, err := f.bucket.Object(name).Attrs(f.ctx)
+	if err == nil {
+		return objectInfo{attrs: attrs}, nil
+	}
+	if !errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+
+	// オブジェクトが存在しない場合は、name/ で始まるオブジェクトがあればディレクトリとして扱う
+	it := f.bucket.Objects(f.ctx, &storage.Query{Prefix: name + "/"})
+	it.PageInfo().MaxSize = 1
+	if _, err := it.Next(); err != nil {
+		if err == iterator.Done {
+			return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+		return nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	return dirInfo(path.Base(name)), nil
+}
+
+// list は、ディレクトリ name 直下のオブジェクトとプレフィックスを名前順で返します。
+func (f *gcsFS) list(name string) ([]fs.DirEntry, error) {
+	prefix := ""
+	if name != "." {
+		prefix = name + "/"
+	}
+
+	var entries []fs.DirEntry
+	it := f.bucket.Objects(f.ctx, &storage.Query{Prefix: prefix, Delimiter: "/"})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf(Message("GCSオブジェクトの一覧取得に失敗しました (prefix: %s): %w"), prefix, err)
+		}
+
+		if attrs.Prefix != "" {
+			dir := strings.TrimSuffix(strings.TrimPrefix(attrs.Prefix, prefix), "/")
+			if dir != "" {
+				entries = append(entries, fs.FileInfoToDirEntry(dirInfo(dir)))
+			}
+			continue
+		}
+		// コンソールなどで作成された "dir/" 形式のプレースホルダーオブジェクトは除外する
+		if attrs.Name == prefix || strings.HasSuffix(attrs.Name, "/") {
+			continue
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(objectInfo{attrs: attrs}))
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries, nil
+}
+
+// objectInfo は、GCS オブジェクトの fs.FileInfo です。Sys は *storage.ObjectAttrs を返します。
+type objectInfo struct {
+	attrs *storage.ObjectAttrs
+}
+
+func (i objectInfo) Name() string       { return path.Base(i.attrs.Name) }
+func (i objectInfo) Size() int64        { return i.attrs.Size }
+func (i objectInfo) Mode() fs.FileMode  { return 0o444 }
+func (i objectInfo) ModTime() time.Time { return i.attrs.Updated }
+func (i objectInfo) IsDir() bool        { return false }
+func (i objectInfo) Sys() any           { return i.attrs }
+
+// dirInfo は、プレフィックスを表すディレクトリの fs.FileInfo です。
+type dirInfo string
+
+func (i dirInfo) Name() string       { return string(i) }
+func (i dirInfo) Size() int64        { return 0 }
+func (i dirInfo) Mode() fs.FileMode  { return fs.ModeDir | 0o555 }
+func (i dirInfo) ModTime() time.Time { return time.Time{} }
+func (i dirInfo) IsDir() bool        { return true }
+func (i dirInfo) Sys() any           { return nil }
+
+// =================================================================
+// 4. ファイルとディレクトリ
+// =================================================================
+
+// gcsFile は、GCS オブジェクトを読み込む fs.File です。
+// 読み込みストリームは最初の Read で開き、Seek の後は新しい位置から開き直します。
+type gcsFile struct {
+	fsys   *gcsFS
+	name   string
+	info   fs.FileInfo
+	rc     io.ReadCloser
+	offset int64
+	closed bool
+}
+
+func (f *gcsFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+
+// Read は、現在の位置からオブジェクトの内容を読み込みます。
+func (f *gcsFile) Read(p []byte) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: fs.ErrClosed}
+	}
+	if f.offset >= f.info.Size() {
+		return 0, io.EOF
+	}
+	if f.rc == nil {
+		rc, err := f.fsys.bucket.Object(f.name).NewRangeReader(f.fsys.ctx, f.offset, -1)
+		if err != nil {
+			return 0, &fs.PathError{Op: "read", Path: f.name, Err: err}
+		}
+		f.rc = rc
+	}
+	n, err := f.rc.Read(p)
+	f.offset += int64(n)
+	return n, err
+}
+
+// Seek は io.Seeker を実装します。読み込み中のストリームは閉じられ、次の Read で新しい位置から開き直します。
+func (f *gcsFile) Seek(offset int64, whence int) (int64, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrClosed}
+	}
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.info.Size()
+	default:
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrInvalid}
+	}
+	if offset < 0 {
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrInvalid}
+	}
+	if offset != f.offset && f.rc != nil {
+		f.rc.Close()
+		f.rc = nil
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+// ReadAt は io.ReaderAt を実装し、範囲指定の読み込みで off から len(p) バイトを読み込みます。
+func (f *gcsFile) ReadAt(p []byte, off int64) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: fs.ErrClosed}
+	}
+	if off < 0 {
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: fs.ErrInvalid}
+	}
+	if off >= f.info.Size() {
+		return 0, io.EOF
+	}
+	rc, err := f.fsys.bucket.Object(f.name).NewRangeReader(f.fsys.ctx, off, int64(len(p)))
+	if err != nil {
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: err}
+	}
+	defer rc.Close()
+	n, err := io.ReadFull(rc, p)
+	if err == io.ErrUnexpectedEOF {
+		// オブジェクトの終端を越えて読み込もうとした場合
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Close は、読み込み中のストリームを閉じます。
+func (f *gcsFile) Close() error {
+	if f.closed {
+		return &fs.PathError{Op: "close", Path: f.name, Err: fs.ErrClosed}
+	}
+	f.closed = true
+	if f.rc != nil {
+		return f.rc.Close()
+	}
+	return nil
+}
+
+// gcsDir は、プレフィックスを表す fs.ReadDirFile です。
+type gcsDir struct {
+	fsys    *gcsFS
+	name    string
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	loaded  bool
+}
+
+func (d *gcsDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+
+func (d *gcsDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errIsDirectory}
+}
+
+func (d *gcsDir) Close() error { return nil }
+
+// ReadDir は fs.ReadDirFile インターフェースを実装します。
+// 最初の呼び出しでディレクトリ直下の一覧を取得し、以降の呼び出しでは続きのエントリを返します。
+func (d *gcsDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.loaded {
+		entries, err := d.fsys.list(d.name)
+		if err != nil {
+			return nil, &fs.PathError{Op: "readdir", Path: d.name, Err: err}
+		}
+		d.entries, d.loaded = entries, true
+	}
+
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(d.entries))
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
+
+// 型アサーションチェック
+var _ fs.ReadDirFS = (*gcsFS)(nil)
+var _ fs.StatFS = (*gcsFS)(nil)
+var _ fs.GlobFS = (*gcsFS)(nil)
+var _ fs.ReadDirFile = (*gcsDir)(nil)
+var _ io.ReadSeeker = (*gcsFile)(nil)
+var _ io.ReaderAt = (*gcsFile)(nil)
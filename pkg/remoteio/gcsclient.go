@@ -0,0 +1,39 @@
+package remoteio
+
+import "cloud.google.com/go/storage"
+
+// errNoGCSClient は、GCS クライアントが注入されておらず、WithGCSClientFunc も指定されていない場合のエラーです。
+var errNoGCSClient = NewMessageError("GCSクライアントが指定されていません")
+
+// WithGCSClientFunc は、NewLocalGCSInputReader / NewUniversalIOWriter に nil のクライアントを渡した場合に、
+// 最初の GCS へのアクセス時に fn でクライアントを取得します。
+// ローカルファイルや他のバックエンドのみを扱う場合は fn は呼び出されないため、GCP の認証情報がない環境でも動作します。
+// fn は並行して呼び出されても安全で、呼び出しごとに同じクライアントを返す必要があります (factory.ClientFactory.Client など)。
+// InputReader と OutputWriter の両方に適用されます。
+func WithGCSClientFunc(fn func() (*storage.Client, error)) Option {
+	return func(c *config) {
+		c.gcsClientFunc = fn
+	}
+}
+
+// gcsClientFor は、GCS へのリクエストに使用するクライアントを返します。
+// client が nil の場合は、WithGCSClientFunc で指定された関数で取得します。
+func (c *config) gcsClientFor(client *storage.Client) (*storage.Client, error) {
+	if client != nil {
+		return client, nil
+	}
+	if c.gcsClientFunc == nil {
+		return nil, errNoGCSClient
+	}
+	return c.gcsClientFunc()
+}
+
+// gcs は、InputReader が GCS へのリクエストに使用するクライアントを返します。
+func (r *LocalGCSInputReader) gcs() (*storage.Client, error) {
+	return r.cfg.gcsClientFor(r.gcsClient)
+}
+
+// gcs は、OutputWriter が GCS へのリクエストに使用するクライアントを返します。
+func (w *UniversalIOWriter) gcs() (*storage.Client, error) {
+	return w.cfg.gcsClientFor(w.gcsClient)
+}
@@ -0,0 +1,50 @@
+package remoteio
+
+// objectHeaders は、書き込み先に設定する HTTP ヘッダー (Content-Type 以外) を保持します。空の項目は設定しません。
+type objectHeaders struct {
+	cacheControl       string
+	contentEncoding    string
+	contentDisposition string
+	contentLanguage    string
+}
+
+// WithCacheControl は、書き込み先に設定する Cache-Control (例: "public, max-age=3600") を指定します。
+// GCS、S3 と Azure への書き込みに適用され、ローカルファイル、SFTP と RegisterScheme で登録したスキームへの書き込みでは無視されます。
+func WithCacheControl(value string) WriteOption {
+	return func(o *writeOptions) {
+		o.headers.cacheControl = value
+	}
+}
+
+// WithContentEncoding は、書き込み先に設定する Content-Encoding (例: "gzip") を指定します。
+// 内容は変換されないため、圧縮済みの内容を書き込む場合に指定します。
+// GCS、S3 と Azure への書き込みに適用され、ローカルファイル、SFTP と RegisterScheme で登録したスキームへの書き込みでは無視されます。
+func WithContentEncoding(value string) WriteOption {
+	return func(o *writeOptions) {
+		o.headers.contentEncoding = value
+	}
+}
+
+// WithContentDisposition は、書き込み先に設定する Content-Disposition (例: `attachment; filename="report.csv"`) を指定します。
+// GCS、S3 と Azure への書き込みに適用され、ローカルファイル、SFTP と RegisterScheme で登録したスキームへの書き込みでは無視されます。
+func WithContentDisposition(value string) WriteOption {
+	return func(o *writeOptions) {
+		o.headers.contentDisposition = value
+	}
+}
+
+// WithContentLanguage は、書き込み先に設定する Content-Language (例: "ja") を指定します。
+// GCS、S3 と Azure への書き込みに適用され、ローカルファイル、SFTP と RegisterScheme で登録したスキームへの書き込みでは無視されます。
+func WithContentLanguage(value string) WriteOption {
+	return func(o *writeOptions) {
+		o.headers.contentLanguage = value
+	}
+}
+
+// optional は、空の文字列の場合は nil を、それ以外の場合は s へのポインタを返します (S3 と Azure の省略可能な項目に使用します)。
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
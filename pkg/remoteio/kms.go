@@ -0,0 +1,19 @@
+package remoteio
+
+// WithKMSKeyName は、GCS への書き込みで作成するオブジェクトを、指定した Cloud KMS の鍵 (顧客管理の暗号鍵、CMEK) で暗号化します。
+// name は "projects/P/locations/L/keyRings/R/cryptoKeys/K" の形式で指定します。
+// Write と OpenWrite に加えて、GCS への CopyObject と Compose、並行複合アップロードと追記で作成するオブジェクトにも適用されます。
+// GCS 以外への書き込みでは無視されます。書き込みごとに指定する場合は WithWriteKMSKeyName を使用します。
+func WithKMSKeyName(name string) Option {
+	return func(c *config) {
+		c.kmsKeyName = name
+	}
+}
+
+// WithWriteKMSKeyName は、この書き込みに限り、WithKMSKeyName と同様に Cloud KMS の鍵で暗号化します。
+// GCS への書き込みでのみ指定でき、他の書き込み先ではエラーになります。
+func WithWriteKMSKeyName(name string) WriteOption {
+	return func(o *writeOptions) {
+		o.kmsKeyName = name
+	}
+}
@@ -0,0 +1,338 @@
+package remoteio
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// =================================================================
+// 1. インターフェース定義
+// =================================================================
+
+// ObjectLister は、ディレクトリまたはプレフィックス配下のファイルを一覧するためのインターフェースです。
+type ObjectLister interface {
+	// ListObjects は、prefixURI 配下のすべてのファイル (サブディレクトリ内を含む) を名前順で返します。
+	// WithDelimiter を指定した場合は、直下のファイルと共通プレフィックス (ディレクトリ) のみを返します。
+	ListObjects(ctx context.Context, prefixURI string, opts ...ListOption) ([]ObjectInfo, error)
+	// ListObjectsPage は、ListObjects の結果を WithPageSize で指定した件数ごとに1ページずつ返します。
+	// 次のページは、返された NextPageToken を WithPageToken に指定して取得します。
+	ListObjectsPage(ctx context.Context, prefixURI string, opts ...ListOption) (ObjectPage, error)
+}
+
+// ObjectInfo は、ListObjects と Stat が返すファイルまたはオブジェクトの情報です。
+// ContentType 以降のフィールドは Stat でのみ設定されます。
+type ObjectInfo struct {
+	URI          string    // ファイルのURI (ローカルファイルの場合はパス)
+	Name         string    // 一覧の起点からの相対パス ("/" 区切り)。Stat の場合はファイル名
+	Size         int64     // サイズ (バイト数)
+	Updated      time.Time // 最終更新日時
+	CRC32C       *uint32   // CRC32C チェックサム (Castagnoli)。バックエンドが提供しない場合は nil
+	StorageClass string    // ストレージクラス (GCS / S3) またはアクセス層 (Azure)。不明な場合は空
+	IsPrefix     bool      // WithDelimiter で集約された共通プレフィックス、または Stat でディレクトリの場合は true
+
+	ContentType     string            // Content-Type。不明な場合は空
+	ContentEncoding string            // Content-Encoding (例: gzip)。設定されていない場合は空
+	MD5             []byte            // MD5 ハッシュ。バックエンドが提供しない場合は nil
+	Generation      int64             // 世代番号 (GCS のみ)
+	Metageneration  int64             // メタデータの世代番号 (GCS のみ)
+	Metadata        map[string]string // ユーザー定義のメタデータ
+	KMSKeyName      string            // 暗号化に使用された Cloud KMS の鍵 (GCS のみ)。バケットの既定の暗号化の場合は空
+}
+
+// ObjectPage は、ListObjectsPage が返す一覧の1ページです。
+type ObjectPage struct {
+	Objects       []ObjectInfo // 名前順のファイルと共通プレフィックス
+	NextPageToken string       // 次のページを取得するためのトークン。最後のページの場合は空
+}
+
+// ListOption は、ListObjects / ListObjectsPage の動作を設定するための関数です。
+type ListOption func(*listOptions)
+
+type listOptions struct {
+	delimiter string
+	pageSize  int
+	pageToken string
+}
+
+// WithDelimiter は、名前の delimiter (通常は "/") より後ろを共通プレフィックスとして集約し、
+// 直下のファイルとディレクトリのみを一覧するように設定します。
+func WithDelimiter(delimiter string) ListOption {
+	return func(o *listOptions) {
+		o.delimiter = delimiter
+	}
+}
+
+// WithPageSize は、ListObjectsPage が1ページで返す最大件数を設定します。
+// ListObjects では、GCS へ1回のリクエストで要求する件数として使用されます。
+func WithPageSize(n int) ListOption {
+	return func(o *listOptions) {
+		o.pageSize = n
+	}
+}
+
+// WithPageToken は、前のページの NextPageToken を指定して、その続きから一覧するように設定します。
+func WithPageToken(token string) ListOption {
+	return func(o *listOptions) {
+		o.pageToken = token
+	}
+}
+
+// ListSettings は、ListOption を適用した一覧の設定です。
+// 独自の ObjectLister の実装で、呼び出し元が指定したオプションを取得する場合に使用します。
+type ListSettings struct {
+	Delimiter string // WithDelimiter で指定された区切り文字。指定されていない場合は空
+	PageSize  int    // WithPageSize で指定された件数。指定されていない場合は 0
+	PageToken string // WithPageToken で指定されたトークン。指定されていない場合は空
+}
+
+// ResolveListOptions は、opts を適用した一覧の設定を返します。
+func ResolveListOptions(opts ...ListOption) ListSettings {
+	o := newListOptions(opts)
+	return ListSettings{Delimiter: o.delimiter, PageSize: o.pageSize, PageToken: o.pageToken}
+}
+
+func newListOptions(opts []ListOption) listOptions {
+	var o listOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// =================================================================
+// 2. LocalGCSInputReader の実装
+// =================================================================
+
+// ListObjects は ObjectLister インターフェースを実装します。
+// prefixURI は、GCS / S3 / Azure の場合はディレクトリとして扱うプレフィックス ("gs://bucket/dir" は "dir/" 配下)、
+// SFTP とローカルの場合はディレクトリのパスです。"/" で終わるプレースホルダーオブジェクトは含まれません。
+func (r *LocalGCSInputReader) ListObjects(ctx context.Context, prefixURI string, opts ...ListOption) (_ []ObjectInfo, err error) {
+	defer classifyError(&err)
+	if err := r.cfg.faults.beforeOp("ListObjects", prefixURI); err != nil {
+		return nil, err
+	}
+
+	h, ok, err := lookupScheme(prefixURI)
+	if err != nil {
+		return nil, err
+	}
+	o := newListOptions(opts)
+	if !ok || h.listPage == nil {
+		// 一括で一覧するバックエンドでは、ページに分割せずにすべてを返す
+		o.pageSize = 0
+		page, err := r.listPage(ctx, h, ok, prefixURI, o)
+		return page.Objects, err
+	}
+
+	var objects []ObjectInfo
+	for {
+		page, err := h.listPage(ctx, r, prefixURI, o)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, page.Objects...)
+		if page.NextPageToken == "" {
+			break
+		}
+		o.pageToken = page.NextPageToken
+	}
+	sortObjects(objects)
+	return objects, nil
+}
+
+// ListObjectsPage は ObjectLister インターフェースを実装します。
+// GCS はサーバー側でページに分割し、それ以外のバックエンドはすべてを一覧してから名前順に分割します。
+// ページトークンはバックエンドごとに形式が異なるため、内容に依存しないでください。
+func (r *LocalGCSInputReader) ListObjectsPage(ctx context.Context, prefixURI string, opts ...ListOption) (_ ObjectPage, err error) {
+	defer classifyError(&err)
+	if err := r.cfg.faults.beforeOp("ListObjectsPage", prefixURI); err != nil {
+		return ObjectPage{}, err
+	}
+
+	h, ok, err := lookupScheme(prefixURI)
+	if err != nil {
+		return ObjectPage{}, err
+	}
+	return r.listPage(ctx, h, ok, prefixURI, newListOptions(opts))
+}
+
+// =================================================================
+// 3. 内部ヘルパー
+// =================================================================
+
+// listPage は、prefixURI 配下の1ページ分を名前順で一覧します。ok が false の場合はローカルディレクトリを一覧します。
+func (r *LocalGCSInputReader) listPage(ctx context.Context, h schemeHandler, ok bool, prefixURI string, o listOptions) (ObjectPage, error) {
+	var objects []ObjectInfo
+	var err error
+	switch {
+	case !ok:
+		objects, err = listLocalFiles(prefixURI, o.delimiter)
+	case h.listPage != nil:
+		page, err := h.listPage(ctx, r, prefixURI, o)
+		if err != nil {
+			return ObjectPage{}, err
+		}
+		sortObjects(page.Objects)
+		return page, nil
+	case h.list != nil:
+		objects, err = h.list(ctx, r, prefixURI)
+		if err == nil {
+			objects = groupByDelimiter(objects, prefixURI, o.delimiter)
+		}
+	default:
+		err = fmt.Errorf(Message("スキーム %s:// は一覧の取得をサポートしていません: %s"), SchemeOf(prefixURI), prefixURI)
+	}
+	if err != nil {
+		return ObjectPage{}, err
+	}
+	sortObjects(objects)
+	return paginate(objects, o), nil
+}
+
+// listLocalFiles は、ローカルディレクトリ配下の通常ファイルを再帰的に一覧します。
+// delimiter が "/" の場合は、ディレクトリ直下のみを一覧します。
+func listLocalFiles(root, delimiter string) ([]ObjectInfo, error) {
+	if delimiter == "/" {
+		return listLocalDir(root)
+	}
+
+	var objects []ObjectInfo
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() || path == root {
+			// ディレクトリと、root 自体がファイルの場合は含めない
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{
+			URI:     path,
+			Name:    filepath.ToSlash(rel),
+			Size:    info.Size(),
+			Updated: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf(Message("ローカルディレクトリ(%s)の一覧取得に失敗しました: %w"), root, err)
+	}
+	return groupByDelimiter(objects, root, delimiter), nil
+}
+
+// listLocalDir は、ローカルディレクトリ直下の通常ファイルとサブディレクトリを一覧します。
+func listLocalDir(root string) ([]ObjectInfo, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf(Message("ローカルディレクトリ(%s)の一覧取得に失敗しました: %w"), root, err)
+	}
+	var objects []ObjectInfo
+	for _, entry := range entries {
+		if entry.IsDir() {
+			objects = append(objects, ObjectInfo{
+				URI:      prefixEntryURI(root, entry.Name()+"/"),
+				Name:     entry.Name() + "/",
+				IsPrefix: true,
+			})
+			continue
+		}
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf(Message("ローカルファイルの情報の取得に失敗しました: %w"), err)
+		}
+		objects = append(objects, ObjectInfo{
+			URI:     filepath.Join(root, entry.Name()),
+			Name:    entry.Name(),
+			Size:    info.Size(),
+			Updated: info.ModTime(),
+		})
+	}
+	return objects, nil
+}
+
+// groupByDelimiter は、名前に delimiter を含むファイルを、最初の delimiter までの共通プレフィックスに集約します。
+// delimiter が空の場合は objects をそのまま返します。
+func groupByDelimiter(objects []ObjectInfo, base, delimiter string) []ObjectInfo {
+	if delimiter == "" {
+		return objects
+	}
+	var grouped []ObjectInfo
+	seen := make(map[string]bool)
+	for _, obj := range objects {
+		i := strings.Index(obj.Name, delimiter)
+		if i < 0 {
+			grouped = append(grouped, obj)
+			continue
+		}
+		prefix := obj.Name[:i+len(delimiter)]
+		if seen[prefix] {
+			continue
+		}
+		seen[prefix] = true
+		grouped = append(grouped, ObjectInfo{
+			URI:      prefixEntryURI(base, prefix),
+			Name:     prefix,
+			IsPrefix: true,
+		})
+	}
+	return grouped
+}
+
+// paginate は、名前順の objects から o.pageToken より後ろの最大 o.pageSize 件を1ページとして返します。
+// ページトークンには、ページ最後の名前を使用します。
+func paginate(objects []ObjectInfo, o listOptions) ObjectPage {
+	if o.pageToken != "" {
+		i, _ := slices.BinarySearchFunc(objects, o.pageToken, func(obj ObjectInfo, token string) int {
+			return strings.Compare(obj.Name, token)
+		})
+		// トークンと同じ名前は前のページに含まれている
+		if i < len(objects) && objects[i].Name == o.pageToken {
+			i++
+		}
+		objects = objects[i:]
+	}
+	if o.pageSize <= 0 || len(objects) <= o.pageSize {
+		return ObjectPage{Objects: objects}
+	}
+	objects = objects[:o.pageSize]
+	return ObjectPage{Objects: objects, NextPageToken: objects[len(objects)-1].Name}
+}
+
+// prefixEntryURI は、base 配下の共通プレフィックス name ("/" で終わる) のURIを返します。
+func prefixEntryURI(base, name string) string {
+	return strings.TrimSuffix(JoinURI(base, name), "/") + "/"
+}
+
+// sortObjects は、objects を名前順に並べ替えます。
+func sortObjects(objects []ObjectInfo) {
+	slices.SortFunc(objects, func(a, b ObjectInfo) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// listPrefix は、バケット内のパスを一覧用のプレフィックス (空、または "/" で終わる) に変換します。
+func listPrefix(objectPath string) string {
+	if objectPath == "" || strings.HasSuffix(objectPath, "/") {
+		return objectPath
+	}
+	return objectPath + "/"
+}
+
+// 型アサーションチェック
+var _ ObjectLister = (*LocalGCSInputReader)(nil)
@@ -0,0 +1,20 @@
+package remoteio
+
+import "log/slog"
+
+// WithLogger は、InputReader と OutputWriter が処理の開始・完了や失敗を記録するロガーを指定します。
+// 省略時は slog.Default() に出力します。組み込み先のアプリケーションのログにメッセージを混在させたくない場合は、
+// 独自のハンドラーを持つロガーや、slog.New(slog.DiscardHandler) (ログを出力しない) を指定します。
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *config) {
+		c.logger = logger
+	}
+}
+
+// log は、WithLogger で指定されたロガー (指定されていない場合は slog.Default()) を返します。
+func (c *config) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return slog.Default()
+}
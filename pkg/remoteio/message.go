@@ -0,0 +1,41 @@
+package remoteio
+
+import "sync/atomic"
+
+// translator は、SetMessageTranslator で設定された翻訳関数です。nil の場合は翻訳しません。
+var translator atomic.Pointer[func(msgid string) string]
+
+// SetMessageTranslator は、このモジュールのパッケージ (remoteio、transfer、proxy、factory など) が返すエラーと
+// 出力するログのメッセージを翻訳する関数 fn を設定します。メッセージIDは日本語の原文 (フォーマット文字列) で、
+// fn は翻訳できない場合にメッセージIDをそのまま返してください。nil を指定すると翻訳しません (既定)。
+// エラーのメッセージは作成した時点の設定で翻訳されるため、読み書きを開始する前に設定してください。
+func SetMessageTranslator(fn func(msgid string) string) {
+	if fn == nil {
+		translator.Store(nil)
+		return
+	}
+	translator.Store(&fn)
+}
+
+// Message は、SetMessageTranslator で設定された関数で msgid を翻訳したメッセージを返します。
+// 設定されていない場合は msgid をそのまま返します。
+func Message(msgid string) string {
+	if fn := translator.Load(); fn != nil {
+		return (*fn)(msgid)
+	}
+	return msgid
+}
+
+// messageError は、メッセージを Error の呼び出し時に翻訳するエラーです。
+type messageError struct {
+	msgid string
+}
+
+func (e *messageError) Error() string { return Message(e.msgid) }
+
+// NewMessageError は、Error が msgid を Message で翻訳したメッセージを返すエラーを返します。
+// パッケージ変数として定義し、errors.Is で判定するエラー (ErrNotFound など) に使用します。
+// 初期化の時点では翻訳関数が設定されていないため、errors.New の代わりに使用します。
+func NewMessageError(msgid string) error {
+	return &messageError{msgid: msgid}
+}
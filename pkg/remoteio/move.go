@@ -0,0 +1,134 @@
+package remoteio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"syscall"
+)
+
+// ErrMoveUnsupported は、データを転送せずに移動できない組み合わせ (異なるバックエンド間など) の場合に Move が返すエラーです。
+// この場合、呼び出し元はコピーしてからコピー元を削除してください。
+var ErrMoveUnsupported = NewMessageError("remoteio: サーバー側で移動できない組み合わせです")
+
+// Mover は、ファイルまたはオブジェクトを移動 (名前変更) するためのインターフェースです。
+type Mover interface {
+	// Move は、srcURI を dstURI へ移動します。移動が完了するまでコピー元は削除されません。
+	Move(ctx context.Context, srcURI, dstURI string) error
+}
+
+// Move は Mover インターフェースを実装します。
+// GCS 間 (バケットをまたぐ場合を含む) と S3 間はサーバー側のコピーと削除で、ローカルファイルは os.Rename で移動します。
+// ローカルファイルが別のファイルシステムへの移動の場合は、コピーしてから削除します。
+// サーバー側で移動する場合、コンテンツはストリーミングされないため、バリデータは適用されません。
+// srcURI と dstURI が同じファイルまたはオブジェクトを指す場合は、コピー元を削除してデータを失わないよう ErrInvalidURI を返します。
+func (w *UniversalIOWriter) Move(ctx context.Context, srcURI, dstURI string) (err error) {
+	defer classifyError(&err)
+	if err := w.cfg.faults.beforeOp("Move", srcURI); err != nil {
+		return err
+	}
+	if sameLocation(srcURI, dstURI) {
+		return invalidURIError("移動元と移動先が同じです: %s", srcURI)
+	}
+
+	h, ok, err := lookupScheme(srcURI)
+	if err != nil {
+		return err
+	}
+	switch {
+	case SchemeOf(srcURI) != SchemeOf(dstURI):
+		return fmt.Errorf("%w: %s -> %s", ErrMoveUnsupported, srcURI, dstURI)
+	case !ok:
+		err = w.moveLocal(ctx, srcURI, dstURI)
+	case h.copy != nil && h.remove != nil:
+		ctx, cancel := w.cfg.opContext(ctx)
+		defer cancel()
+		err = w.moveByCopy(ctx, h, srcURI, dstURI)
+	default:
+		return fmt.Errorf("%w: %s -> %s", ErrMoveUnsupported, srcURI, dstURI)
+	}
+	if err != nil {
+		return err
+	}
+
+	w.cfg.log().Info(Message("移動完了"), slog.String("source", srcURI), slog.String("destination", dstURI))
+	return nil
+}
+
+// moveLocal は、ローカルファイルを os.Rename で移動します。
+// 別のファイルシステムへの移動で名前変更できない場合は、WriteToLocal でコピーしてからコピー元を削除します。
+func (w *UniversalIOWriter) moveLocal(ctx context.Context, srcPath, dstPath string) error {
+	if err := w.cfg.mkdirParent(dstPath); err != nil {
+		return err
+	}
+	err := os.Rename(srcPath, dstPath)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return fmt.Errorf(Message("ローカルファイル(%s)の移動に失敗しました: %w"), srcPath, err)
+	}
+
+	w.cfg.log().Info(Message("別のファイルシステムへの移動のため、コピーしてから削除します"), slog.String("source", srcPath), slog.String("destination", dstPath))
+	file, err := os.Open(srcPath)
+	if err != nil {
+		return fmt.Errorf(Message("ローカルファイルのオープンに失敗しました: %w"), err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf(Message("ローカルファイルの情報の取得に失敗しました: %w"), err)
+	}
+	if err := w.WriteToLocal(ctx, dstPath, file); err != nil {
+		return err
+	}
+	if err := os.Chmod(dstPath, info.Mode().Perm()); err != nil {
+		return fmt.Errorf(Message("ローカルファイル(%s)のパーミッションの設定に失敗しました: %w"), dstPath, err)
+	}
+	if err := os.Remove(srcPath); err != nil {
+		return fmt.Errorf(Message("移動元のローカルファイル(%s)の削除に失敗しました: %w"), srcPath, err)
+	}
+	return nil
+}
+
+// moveByCopy は、サーバー側でコピーしてから、コピー元を削除します。
+func (w *UniversalIOWriter) moveByCopy(ctx context.Context, h schemeHandler, srcURI, dstURI string) error {
+	if err := h.copy(ctx, w, srcURI, dstURI); err != nil {
+		return err
+	}
+	if err := h.remove(ctx, w, srcURI); err != nil {
+		return fmt.Errorf(Message("移動元の削除に失敗しました (URI: %s): %w"), srcURI, err)
+	}
+	return nil
+}
+
+// sameLocation は、a と b が同じファイルまたはオブジェクトを指すかどうかを返します。
+// GCS の世代番号は除いて比較し、ローカルパスと SFTP のパスは正規化して比較します。
+func sameLocation(a, b string) bool {
+	if SchemeOf(a) != SchemeOf(b) {
+		return false
+	}
+	switch {
+	case IsGCSURI(a):
+		a, _ = SplitGCSGeneration(a)
+		b, _ = SplitGCSGeneration(b)
+		return a == b
+	case IsSFTPURI(a):
+		addrA, pathA, errA := ParseSFTPURI(a)
+		addrB, pathB, errB := ParseSFTPURI(b)
+		return errA == nil && errB == nil && addrA == addrB && path.Clean(pathA) == path.Clean(pathB)
+	case SchemeOf(a) == "":
+		absA, errA := filepath.Abs(a)
+		absB, errB := filepath.Abs(b)
+		return errA == nil && errB == nil && absA == absB
+	default:
+		return a == b
+	}
+}
+
+// 型アサーションチェック
+var _ Mover = (*UniversalIOWriter)(nil)
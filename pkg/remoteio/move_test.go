@@ -0,0 +1,61 @@
+package remoteio
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSameLocation(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{name: "同じ GCS オブジェクト", a: "gs://bucket/a.txt", b: "gs://bucket/a.txt", want: true},
+		{name: "世代番号の指定", a: "gs://bucket/a.txt#1700000000000000", b: "gs://bucket/a.txt", want: true},
+		{name: "別の GCS オブジェクト", a: "gs://bucket/a.txt", b: "gs://bucket/b.txt"},
+		{name: "別のバケット", a: "gs://bucket/a.txt", b: "gs://other/a.txt"},
+		{name: "同じ S3 オブジェクト", a: "s3://bucket/a.txt", b: "s3://bucket/a.txt", want: true},
+		{name: "異なるバックエンド", a: "gs://bucket/a.txt", b: "s3://bucket/a.txt"},
+		{name: "SFTP の既定のポート", a: "sftp://user@host/data/a.txt", b: "sftp://user@host:22/data//a.txt", want: true},
+		{name: "ローカルの相対パス", a: "dir/a.txt", b: "./dir/../dir/a.txt", want: true},
+		{name: "別のローカルファイル", a: "dir/a.txt", b: "dir/b.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameLocation(tt.a, tt.b); got != tt.want {
+				t.Errorf("sameLocation(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoveRejectsSameSourceAndDestination(t *testing.T) {
+	local := filepath.Join(t.TempDir(), "a.txt")
+	if err := os.WriteFile(local, []byte("data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		src, dst string
+	}{
+		{name: "GCS", src: "gs://bucket/a.txt", dst: "gs://bucket/a.txt"},
+		{name: "GCS の世代番号の指定", src: "gs://bucket/a.txt#1700000000000000", dst: "gs://bucket/a.txt"},
+		{name: "S3", src: "s3://bucket/a.txt", dst: "s3://bucket/a.txt"},
+		{name: "ローカルファイル", src: local, dst: filepath.Join(filepath.Dir(local), ".", "a.txt")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewUniversalIOWriter(nil).Move(context.Background(), tt.src, tt.dst)
+			if !errors.Is(err, ErrInvalidURI) {
+				t.Fatalf("Move(%q, %q) = %v, want %v", tt.src, tt.dst, err, ErrInvalidURI)
+			}
+		})
+	}
+	if _, err := os.Stat(local); err != nil {
+		t.Errorf("移動元のローカルファイルが失われました: %v", err)
+	}
+}
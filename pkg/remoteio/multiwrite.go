@@ -0,0 +1,136 @@
+package remoteio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteFailure は、MultiWrite で失敗した1つの書き込み先とそのエラーです。
+type WriteFailure struct {
+	URI string
+	Err error
+}
+
+// MultiWriteError は、MultiWrite で失敗した書き込み先をまとめたエラーです。
+type MultiWriteError struct {
+	Failures []WriteFailure // 失敗した書き込み先 (指定された順)
+	Total    int            // 書き込み先の総数
+}
+
+// Error は error インターフェースを実装します。
+func (e *MultiWriteError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, Message("%d 件中 %d 件の書き込み先への書き込みに失敗しました"), e.Total, len(e.Failures))
+	for _, f := range e.Failures {
+		fmt.Fprintf(&b, "\n  %s: %v", f.URI, f.Err)
+	}
+	return b.String()
+}
+
+// Unwrap は、失敗した書き込み先のエラーを返します。errors.Is / errors.As で個々のエラーを判定できます。
+func (e *MultiWriteError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, f := range e.Failures {
+		errs[i] = f.Err
+	}
+	return errs
+}
+
+// errAllWritesFailed は、すべての書き込み先が失敗したため、コピー元の読み込みを中止する場合に使用します。
+var errAllWritesFailed = NewMessageError("すべての書き込み先への書き込みに失敗しました")
+
+// MultiWrite は、r から一度だけ読み込んだ内容を、dstURIs のすべての書き込み先へ同時にストリーミングします。
+// io.MultiWriter と同様に、読み込んだ内容を各書き込み先へ順に渡すため、最も遅い書き込み先に合わせて読み込みます。
+// io.MultiWriter と異なり、いずれかの書き込み先が失敗しても、その書き込み先のみを確定させずに中止し、残りの書き込み先への書き込みを続けます。
+// opts はすべての書き込み先に適用します。
+//
+// 失敗した書き込み先がある場合は *MultiWriteError を返します (失敗していない書き込み先は確定しています)。
+// r の読み込みが失敗した場合は、すべての書き込み先を確定させずに中止し、読み込みのエラーを返します。
+func MultiWrite(ctx context.Context, writer OutputWriter, dstURIs []string, r io.Reader, opts ...WriteOption) error {
+	if len(dstURIs) == 0 {
+		return errors.New(Message("書き込み先が指定されていません"))
+	}
+	seen := make(map[string]bool, len(dstURIs))
+	for _, uri := range dstURIs {
+		if seen[uri] {
+			return invalidURIError("同じ書き込み先が重複しています: %s", uri)
+		}
+		seen[uri] = true
+	}
+	targets := make([]*multiWriteTarget, len(dstURIs))
+	for i, uri := range dstURIs {
+		pr, pw := io.Pipe()
+		t := &multiWriteTarget{uri: uri, pw: pw, done: make(chan error, 1)}
+		go func() {
+			err := writer.Write(ctx, uri, pr, opts...)
+			// 書き込みが途中で終了した場合は、以降の書き込みがエラーを返すようにする
+			pr.CloseWithError(err)
+			t.done <- err
+		}()
+		targets[i] = t
+	}
+
+	_, readErr := io.Copy(&fanOutWriter{targets: targets}, r)
+	if errors.Is(readErr, errAllWritesFailed) {
+		readErr = nil
+	}
+	if readErr != nil {
+		// 読み込みが失敗した場合は、不完全な内容を確定させない
+		readErr = fmt.Errorf(Message("コピー元の読み込みに失敗しました: %w"), readErr)
+	}
+
+	var failures []WriteFailure
+	for _, t := range targets {
+		t.pw.CloseWithError(readErr)
+		err := <-t.done
+		if err == nil {
+			// 書き込み先が内容を最後まで読み込まずに終了した場合も失敗とする
+			err = t.err
+		}
+		if err != nil && readErr == nil {
+			failures = append(failures, WriteFailure{URI: t.uri, Err: err})
+		}
+	}
+	if readErr != nil {
+		return readErr
+	}
+	if failures != nil {
+		return &MultiWriteError{Failures: failures, Total: len(dstURIs)}
+	}
+	return nil
+}
+
+// multiWriteTarget は、MultiWrite の1つの書き込み先です。
+type multiWriteTarget struct {
+	uri  string
+	pw   *io.PipeWriter
+	done chan error // Write の結果
+	err  error      // 内容を渡せなかった場合のエラー (nil でない場合は、以降の内容を渡さない)
+}
+
+// fanOutWriter は、書き込まれた内容を、失敗していないすべての書き込み先へ順に渡す io.Writer です。
+type fanOutWriter struct {
+	targets []*multiWriteTarget
+}
+
+// Write は io.Writer インターフェースを実装します。すべての書き込み先が失敗した場合は errAllWritesFailed を返します。
+func (f *fanOutWriter) Write(p []byte) (int, error) {
+	active := 0
+	for _, t := range f.targets {
+		if t.err != nil {
+			continue
+		}
+		if _, err := t.pw.Write(p); err != nil {
+			t.err = err
+			continue
+		}
+		active++
+	}
+	if active == 0 {
+		return 0, errAllWritesFailed
+	}
+	return len(p), nil
+}
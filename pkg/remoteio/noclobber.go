@@ -0,0 +1,52 @@
+package remoteio
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"google.golang.org/api/googleapi"
+)
+
+// ErrDestinationExists は、ErrAlreadyExists の別名です。
+// 上書きの防止 (WithNoClobber) が指定された書き込みで、書き込み先が既に存在する場合に返されます。
+var ErrDestinationExists = ErrAlreadyExists
+
+// WithNoClobber は、既存のファイルやオブジェクトを上書きせず、ErrAlreadyExists を返すようにします。
+// 存在の確認と書き込みは原子的に行われ、GCS では storage.Conditions{DoesNotExist: true}、S3 と Azure では If-None-Match: *、
+// ローカルファイルでは一時ファイルのハードリンク (ハードリンクをサポートしないファイルシステムでは O_EXCL)、SFTP では置き換えを行わない名前変更を使用します。RegisterScheme で登録したスキームへの書き込みはエラーになります。
+// 書き込みごとに指定する場合は WithWriteNoClobber を使用します。
+// OutputWriter の Write、OpenWrite、CopyObject と Compose に適用されます (Move には適用されません)。
+func WithNoClobber() Option {
+	return func(c *config) {
+		c.noClobber = true
+	}
+}
+
+// WithWriteNoClobber は、この書き込みに限り、WithNoClobber と同様に既存の書き込み先を上書きしないようにします。
+func WithWriteNoClobber() WriteOption {
+	return func(o *writeOptions) {
+		o.noClobber = true
+	}
+}
+
+// destinationExists は、uri が既に存在するため書き込まなかったことを示すエラーを返します。
+func destinationExists(uri string) error {
+	return fmt.Errorf("%w: %s", ErrAlreadyExists, uri)
+}
+
+// isPreconditionFailed は、err が書き込みの前提条件 (書き込み先が存在しないこと) を満たさなかったことを示すかどうかを、
+// バックエンドごとのエラーから判定します。
+func isPreconditionFailed(err error) bool {
+	var gErr *googleapi.Error
+	if errors.As(err, &gErr) && gErr.Code == http.StatusPreconditionFailed {
+		return true
+	}
+	// S3 のエラー (*awshttp.ResponseError) は HTTP のステータスコードを返す
+	var s3Err interface{ HTTPStatusCode() int }
+	if errors.As(err, &s3Err) && s3Err.HTTPStatusCode() == http.StatusPreconditionFailed {
+		return true
+	}
+	return bloberror.HasCode(err, bloberror.BlobAlreadyExists, bloberror.ConditionNotMet)
+}
@@ -0,0 +1,358 @@
+package remoteio
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option は、InputReader / OutputWriter の構成を変更する関数型オプションです。
+// 各オプションがどちらに適用されるかは、それぞれのドキュメントを参照してください。
+type Option func(*config)
+
+// config は、InputReader と OutputWriter が共有する構成を保持します。
+type config struct {
+	validators     []Validator
+	faults         *faultInjector                   // nil の場合は故障注入を行わない
+	s3Client       *s3.Client                       // nil の場合は s3:// を扱えない
+	azureClient    *azblob.Client                   // nil の場合は az:// を扱えない
+	gcsClientFunc  func() (*storage.Client, error)  // nil の場合は注入されたクライアントのみを使用する
+	sftp           *SFTPConfig                      // nil の場合は既定の設定で sftp:// に接続する
+	progress       ProgressFunc                     // nil の場合は進捗を通知しない
+	bufferSize     int                              // 0 の場合は io.Copy の既定のバッファ (32KiB)
+	chunkSize      *int                             // nil の場合は各バックエンドの既定値
+	retry          []storage.RetryOption            // nil の場合はクライアントの再試行の設定に従う
+	userProject    string                           // 空の場合は GCS へのリクエストに請求先のプロジェクトを指定しない
+	opTimeout      time.Duration                    // 0 の場合は操作の時間を制限しない
+	fileMode       fs.FileMode                      // 0 の場合は 0666 (umask が適用される)
+	dirMode        fs.FileMode                      // 0 の場合は 0755 (umask が適用される)
+	fsync          bool                             // true の場合はローカルファイルの書き込み後に fsync する
+	noClobber      bool                             // true の場合は既存の書き込み先を上書きしない
+	conditions     *storage.Conditions              // nil の場合は GCS への書き込みに世代番号の前提条件を指定しない (書き込みごとに設定される)
+	metadata       map[string]string                // nil の場合はカスタムメタデータを設定しない (書き込みごとに設定される)
+	headers        objectHeaders                    // 書き込み先に設定する HTTP ヘッダー (書き込みごとに設定される)
+	kmsKeyName     string                           // 空の場合は GCS のバケットの既定の暗号化を使用する
+	read           readOptions                      // InputReader の読み込みの既定の設定 (読み込みごとに OpenWith で上書きできる)
+	committed      func(attrs *storage.ObjectAttrs) // nil の場合は通知しない。GCS オブジェクトの書き込みの確定後に呼び出す (書き込みごとに設定される)
+	logger         *slog.Logger                     // nil の場合は slog.Default() に出力する
+	tracerProvider trace.TracerProvider             // nil の場合はスパンを記録しない
+}
+
+// newConfig は、オプションを適用した構成を返します。
+func newConfig(opts []Option) config {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// WithValidators は、書き込み時に転送内容を検査するバリデータを追加します。
+// OutputWriter にのみ適用されます。
+func WithValidators(validators ...Validator) Option {
+	return func(c *config) {
+		c.validators = append(c.validators, validators...)
+	}
+}
+
+// WithS3Client は、s3:// URI の読み書きに使用する S3 クライアントを設定します。
+// InputReader と OutputWriter の両方に適用されます。
+func WithS3Client(client *s3.Client) Option {
+	return func(c *config) {
+		c.s3Client = client
+	}
+}
+
+// WithAzureClient は、az:// URI の読み書きに使用する Azure Blob Storage クライアントを設定します。
+// InputReader と OutputWriter の両方に適用されます。
+func WithAzureClient(client *azblob.Client) Option {
+	return func(c *config) {
+		c.azureClient = client
+	}
+}
+
+// WithBufferSize は、内容をコピーする際のバッファのサイズを n バイトに設定します。
+// 0 以下の場合は io.Copy の既定 (32KiB) を使用します。
+// InputReader (分割ダウンロードとダウンロードの再開) と OutputWriter の両方に適用されます。
+func WithBufferSize(n int) Option {
+	return func(c *config) {
+		c.bufferSize = n
+	}
+}
+
+// WithChunkSize は、アップロードを分割して送信する単位を n バイトに設定します。
+// GCS では storage.Writer の ChunkSize (既定 16MiB。0 の場合はバッファリングせずに1回のリクエストで送信し、再試行されません)、
+// S3 ではマルチパートアップロードのパートのサイズ (5MiB 未満の場合は 5MiB)、Azure ではブロックのサイズ (0 の場合は既定値) として使用します。
+// アップロード中は同時にこのサイズのバッファを保持するため、並行して書き込む場合のメモリ使用量の調整に使用します。
+// OutputWriter にのみ適用されます。
+func WithChunkSize(n int) Option {
+	return func(c *config) {
+		c.chunkSize = &n
+	}
+}
+
+// WithFileMode は、作成・上書きするローカルファイルのパーミッションを perm に設定します。
+// 指定した場合は umask にかかわらず perm に設定し、省略した場合は 0666 から umask を除いたパーミッションで作成します (上書きする場合は既存のファイルのパーミッションを引き継ぎます)。
+// OutputWriter (ローカルファイルへの書き込み) と InputReader (ダウンロードの再開) に適用されます。
+func WithFileMode(perm fs.FileMode) Option {
+	return func(c *config) {
+		c.fileMode = perm.Perm()
+	}
+}
+
+// WithDirMode は、ローカルファイルの書き込み時に作成する出力ディレクトリのパーミッションを perm に設定します (省略時は 0755)。
+// 作成されるディレクトリには umask が適用されます。
+// OutputWriter (ローカルファイルへの書き込みと移動) と InputReader (ダウンロードの再開) に適用されます。
+func WithDirMode(perm fs.FileMode) Option {
+	return func(c *config) {
+		c.dirMode = perm.Perm()
+	}
+}
+
+// WithFsync は、ローカルファイルへの書き込みの完了前に、ファイルとその親ディレクトリを fsync します。
+// 書き込みが返った直後にクラッシュや電源断が発生しても、内容が失われないようにする (チェックポイントの保存など) 場合に使用します。
+// 書き込みごとに指定する場合は WithWriteFsync を使用します。
+// OutputWriter (ローカルファイルへの書き込み) と InputReader (ダウンロードの再開) に適用されます。
+func WithFsync() Option {
+	return func(c *config) {
+		c.fsync = true
+	}
+}
+
+// mkdirParent は、ローカルファイル path の親ディレクトリを WithDirMode のパーミッションで作成します。
+func (c *config) mkdirParent(path string) error {
+	dir := filepath.Dir(path)
+	if dir == "" || dir == "." {
+		return nil
+	}
+	perm := c.dirMode
+	if perm == 0 {
+		perm = 0755
+	}
+	if err := os.MkdirAll(dir, perm); err != nil {
+		return fmt.Errorf(Message("出力ディレクトリ(%s)の作成に失敗しました: %w"), dir, err)
+	}
+	return nil
+}
+
+// openLocalFile は、WithFileMode のパーミッションでローカルファイルを開きます。
+// パーミッションが指定されている場合は、既存のファイルを開いた場合も含めて、そのパーミッションに変更します。
+func (c *config) openLocalFile(path string, flag int) (*os.File, error) {
+	perm := c.fileMode
+	if perm == 0 {
+		perm = 0666
+	}
+	file, err := os.OpenFile(path, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	if c.fileMode != 0 {
+		if err := file.Chmod(c.fileMode); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+	return file, nil
+}
+
+// syncLocalFile は、WithFsync が指定されている場合に、書き込んだファイルとその親ディレクトリ (ファイルのエントリ) をストレージに書き出します。
+func (c *config) syncLocalFile(file *os.File) error {
+	if !c.fsync {
+		return nil
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf(Message("ローカルファイル(%s)の fsync に失敗しました: %w"), file.Name(), err)
+	}
+	return syncDir(filepath.Dir(file.Name()))
+}
+
+// syncDir は、ディレクトリ dir を fsync し、その中で作成・名前変更したエントリを確定させます。
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf(Message("ディレクトリ(%s)のオープンに失敗しました: %w"), dir, err)
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf(Message("ディレクトリ(%s)の fsync に失敗しました: %w"), dir, err)
+	}
+	return nil
+}
+
+// copyBuffer は、WithBufferSize で指定されたサイズのバッファで src から dst へコピーします。
+func (c *config) copyBuffer(dst io.Writer, src io.Reader) (int64, error) {
+	if c.bufferSize <= 0 {
+		return io.Copy(dst, src)
+	}
+	// io.ReaderFrom / io.WriterTo を実装する型 (*os.File など) でもバッファのサイズを守るよう、それらを隠してコピーする
+	return io.CopyBuffer(struct{ io.Writer }{dst}, struct{ io.Reader }{src}, make([]byte, c.bufferSize))
+}
+
+// ReadOption は、OptionOpener.OpenWith の1回の読み込みに対する設定を変更する関数型オプションです。
+// WithReadOptions で InputReader の既定の設定としても指定できます。
+type ReadOption func(*readOptions)
+
+// readOptions は、1回の読み込みに対する設定を保持します。
+type readOptions struct {
+	compressed bool // true の場合は Content-Encoding: gzip の GCS オブジェクトを展開せずに読み込む
+}
+
+// WithReadOptions は、InputReader のすべての読み込みに既定で適用する ReadOption を指定します。
+// InputReader にのみ適用されます。
+func WithReadOptions(opts ...ReadOption) Option {
+	return func(c *config) {
+		for _, opt := range opts {
+			opt(&c.read)
+		}
+	}
+}
+
+// WriteOption は、OutputWriter.Write の1回の書き込みに対する設定を変更する関数型オプションです。
+type WriteOption func(*writeOptions)
+
+// writeOptions は、1回の書き込みに対する設定を保持します。
+type writeOptions struct {
+	contentType           string            // 空の場合は拡張子または内容から判定する
+	bufferSize            int               // 0 の場合は OutputWriter の設定 (WithBufferSize)
+	chunkSize             *int              // nil の場合は OutputWriter の設定 (WithChunkSize)
+	modTime               time.Time         // ゼロ値の場合は書き込んだ時刻のまま
+	fsync                 bool              // true の場合は OutputWriter の設定にかかわらず fsync する
+	noClobber             bool              // true の場合は OutputWriter の設定にかかわらず上書きしない
+	ifGenerationMatch     *int64            // nil の場合は世代番号の前提条件を指定しない
+	ifMetagenerationMatch *int64            // nil の場合はメタデータの世代番号の前提条件を指定しない
+	metadata              map[string]string // nil の場合はカスタムメタデータを設定しない
+	headers               objectHeaders     // 空の項目は設定しない
+	kmsKeyName            string            // 空の場合は OutputWriter の設定 (WithKMSKeyName)
+	gzip                  bool              // true の場合は内容を gzip で圧縮して書き込む
+	verify                *verifyOptions    // nil の場合は書き込んだ内容を検証しない
+	transforms            []Transform       // 書き込む内容に順に適用する変換
+}
+
+// newWriteOptions は、オプションを適用した書き込み設定を返します。
+func newWriteOptions(opts []WriteOption) writeOptions {
+	var o writeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WriteSettings は、WriteOption を適用した書き込み設定のうち、OutputWriter の独自の実装 (テスト用のフェイクなど) が
+// 解釈する主な項目を公開したものです。
+type WriteSettings struct {
+	ContentType           string            // WithContentType で指定された Content-Type。指定されていない場合は空
+	Metadata              map[string]string // WithMetadata で指定されたカスタムメタデータ
+	NoClobber             bool              // WithWriteNoClobber が指定された場合は true
+	IfGenerationMatch     *int64            // WithIfGenerationMatch で指定された世代番号。指定されていない場合は nil
+	IfMetagenerationMatch *int64            // WithIfMetagenerationMatch で指定されたメタデータの世代番号。指定されていない場合は nil
+	CacheControl          string            // WithCacheControl で指定された Cache-Control
+	ContentEncoding       string            // WithContentEncoding で指定された Content-Encoding
+	ContentDisposition    string            // WithContentDisposition で指定された Content-Disposition
+	ContentLanguage       string            // WithContentLanguage で指定された Content-Language
+	KMSKeyName            string            // WithWriteKMSKeyName で指定された Cloud KMS の鍵の名前
+	Gzip                  bool              // WithGzip が指定された場合は true
+	Verify                bool              // WithVerify が指定された場合は true
+	VerifyMD5             bool              // WithVerify で MD5 の計算が指定された場合は true
+	VerifySums            *Checksums        // WithVerify で指定されたチェックサムの格納先。指定されていない場合は nil
+}
+
+// ResolveWriteOptions は、opts を適用した書き込み設定を返します。
+func ResolveWriteOptions(opts ...WriteOption) WriteSettings {
+	o := newWriteOptions(opts)
+	s := WriteSettings{
+		ContentType:           o.contentType,
+		Metadata:              o.metadata,
+		NoClobber:             o.noClobber,
+		IfGenerationMatch:     o.ifGenerationMatch,
+		IfMetagenerationMatch: o.ifMetagenerationMatch,
+		CacheControl:          o.headers.cacheControl,
+		ContentEncoding:       o.headers.contentEncoding,
+		ContentDisposition:    o.headers.contentDisposition,
+		ContentLanguage:       o.headers.contentLanguage,
+		KMSKeyName:            o.kmsKeyName,
+		Gzip:                  o.gzip,
+	}
+	if o.verify != nil {
+		s.Verify = true
+		s.VerifyMD5 = o.verify.withMD5
+		s.VerifySums = o.verify.sums
+	}
+	return s
+}
+
+// Settings は、Option を適用した構成のうち、InputReader / OutputWriter の独自の実装 (プロキシのクライアントなど) が
+// 解釈する主な項目を公開したものです。
+type Settings struct {
+	NoClobber  bool        // WithNoClobber が指定された場合は true
+	KMSKeyName string      // WithKMSKeyName で指定された Cloud KMS の鍵の名前
+	Validators []Validator // WithValidators で指定されたバリデータ
+}
+
+// ResolveOptions は、opts を適用した構成の設定を返します。
+func ResolveOptions(opts ...Option) Settings {
+	c := newConfig(opts)
+	return Settings{NoClobber: c.noClobber, KMSKeyName: c.kmsKeyName, Validators: c.validators}
+}
+
+// WithContentType は、書き込み先に設定する Content-Type を指定します。
+// 指定しない場合は、書き込み先の拡張子から mime.TypeByExtension で判定し、判定できない場合は内容の先頭 512 バイトを
+// http.DetectContentType で判定します (内容が空の場合は DefaultContentType)。
+// ローカルファイルと SFTP への書き込みでは無視されます。
+func WithContentType(contentType string) WriteOption {
+	return func(o *writeOptions) {
+		o.contentType = contentType
+	}
+}
+
+// WithWriteBufferSize は、この書き込みに限り、WithBufferSize の設定を n バイトで上書きします。
+func WithWriteBufferSize(n int) WriteOption {
+	return func(o *writeOptions) {
+		o.bufferSize = n
+	}
+}
+
+// WithWriteChunkSize は、この書き込みに限り、WithChunkSize の設定を n バイトで上書きします。
+func WithWriteChunkSize(n int) WriteOption {
+	return func(o *writeOptions) {
+		o.chunkSize = &n
+	}
+}
+
+// WithModTime は、書き込んだローカルファイルの更新日時 (mtime) を t に設定します。
+// コピー元の ObjectInfo.Updated を指定すると、同期のようにコピー元の更新日時を保ったコピーができます。
+// ローカルファイル以外への書き込みでは無視されます。
+func WithModTime(t time.Time) WriteOption {
+	return func(o *writeOptions) {
+		o.modTime = t
+	}
+}
+
+// WithMetadata は、書き込み先に設定するカスタムメタデータ (キーと値) を指定します。複数回指定した場合は統合されます。
+// GCS、S3 と Azure への書き込みに適用され、ローカルファイル、SFTP と RegisterScheme で登録したスキームへの書き込みでは無視されます。
+// Azure ではメタデータ名の "-" を "_" に置き換えて設定します。
+func WithMetadata(md map[string]string) WriteOption {
+	return func(o *writeOptions) {
+		if o.metadata == nil {
+			o.metadata = make(map[string]string, len(md))
+		}
+		maps.Copy(o.metadata, md)
+	}
+}
+
+// WithWriteFsync は、この書き込みに限り、WithFsync と同様にローカルファイルとその親ディレクトリを fsync します。
+// ローカルファイル以外への書き込みでは無視されます。
+func WithWriteFsync() WriteOption {
+	return func(o *writeOptions) {
+		o.fsync = true
+	}
+}
@@ -0,0 +1,71 @@
+package remoteio
+
+import (
+	"fmt"
+
+	"cloud.google.com/go/storage"
+)
+
+// ErrPreconditionFailed は、WithIfGenerationMatch または WithIfMetagenerationMatch で指定した前提条件を
+// 書き込み先の GCS オブジェクトが満たさなかった (他の書き込みによって更新された) 場合に返されるエラーです。
+// この場合、オブジェクトは変更されません。
+var ErrPreconditionFailed = NewMessageError("remoteio: 書き込み先が前提条件 (世代番号) を満たしません")
+
+// WithIfGenerationMatch は、書き込み先の GCS オブジェクトの世代番号 (generation) が gen の場合にのみ書き込みます。
+// gen に 0 を指定すると、オブジェクトが存在しない場合にのみ書き込みます。
+// 読み込んだ時点の世代番号 (ObjectInfo.Generation) を指定すると、複数のジョブが同じオブジェクトを更新する場合の楽観的ロックとして使用でき、
+// 他のジョブが先に更新していた場合は ErrPreconditionFailed を返します (更新の消失を防ぎます)。
+// GCS への書き込みでのみ指定でき、他の書き込み先ではエラーになります。
+func WithIfGenerationMatch(gen int64) WriteOption {
+	return func(o *writeOptions) {
+		o.ifGenerationMatch = &gen
+	}
+}
+
+// WithIfMetagenerationMatch は、書き込み先の GCS オブジェクトのメタデータの世代番号 (metageneration) が metagen の場合にのみ書き込みます。
+// GCS への書き込みでのみ指定でき、他の書き込み先ではエラーになります。
+func WithIfMetagenerationMatch(metagen int64) WriteOption {
+	return func(o *writeOptions) {
+		o.ifMetagenerationMatch = &metagen
+	}
+}
+
+// gcsConditions は、書き込みに指定された前提条件を storage.Conditions に変換します。前提条件がない場合は nil を返します。
+func (o writeOptions) gcsConditions() *storage.Conditions {
+	if o.ifGenerationMatch == nil && o.ifMetagenerationMatch == nil {
+		return nil
+	}
+	var cond storage.Conditions
+	if o.ifGenerationMatch != nil {
+		if *o.ifGenerationMatch == 0 {
+			cond.DoesNotExist = true
+		} else {
+			cond.GenerationMatch = *o.ifGenerationMatch
+		}
+	}
+	if o.ifMetagenerationMatch != nil {
+		cond.MetagenerationMatch = *o.ifMetagenerationMatch
+	}
+	return &cond
+}
+
+// writeConditions は、GCS への書き込みに適用する前提条件を返します。前提条件がない場合は nil を返します。
+// 上書きの防止 (WithNoClobber) は、オブジェクトが存在しないことを前提条件とします。
+func (c *config) writeConditions() *storage.Conditions {
+	if c.conditions != nil {
+		return c.conditions
+	}
+	if c.noClobber {
+		return &storage.Conditions{DoesNotExist: true}
+	}
+	return nil
+}
+
+// preconditionError は、前提条件を満たさなかったことを示すエラーを返します。
+// 上書きの防止による場合は ErrAlreadyExists を、世代番号の前提条件による場合は ErrPreconditionFailed を含みます。
+func (c *config) preconditionError(uri string) error {
+	if c.conditions == nil {
+		return destinationExists(uri)
+	}
+	return fmt.Errorf("%w: %s", ErrPreconditionFailed, uri)
+}
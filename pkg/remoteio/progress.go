@@ -0,0 +1,80 @@
+package remoteio
+
+import (
+	"io"
+	"os"
+
+	"cloud.google.com/go/storage"
+)
+
+// ProgressFunc は、転送の進捗を受け取る関数です。
+// bytesDone はこれまでに転送したバイト数、bytesTotal は総バイト数で、不明な場合は -1 です。
+// 読み込み・書き込みのたびに転送中のゴルーチンから呼び出されるため、時間のかかる処理は行わないでください。
+type ProgressFunc func(bytesDone, bytesTotal int64)
+
+// WithProgress は、読み込みと書き込みの進捗を fn で受け取ります。
+// InputReader では開いたストリームの読み込みごとに、OutputWriter では書き込む内容の読み込みごとに呼び出されます。
+// 分割ダウンロードや並行複合アップロードのように複数のストリームを並行して転送する場合は、ストリームごとに呼び出されます。
+func WithProgress(fn ProgressFunc) Option {
+	return func(c *config) {
+		c.progress = fn
+	}
+}
+
+// NewProgressReader は、r から読み込むたびに、それまでに読み込んだバイト数を fn へ通知するリーダーを返します。
+// total が不明な場合は -1 を指定します。
+func NewProgressReader(r io.Reader, total int64, fn ProgressFunc) io.Reader {
+	return &progressReader{r: r, total: total, fn: fn}
+}
+
+// progressReader は、読み込んだバイト数を ProgressFunc へ通知する io.Reader です。
+type progressReader struct {
+	r     io.Reader
+	done  int64
+	total int64
+	fn    ProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.done += int64(n)
+		p.fn(p.done, p.total)
+	}
+	return n, err
+}
+
+// wrapWriteStream は、書き込む内容に故障注入と進捗の通知を適用します。
+func (c *config) wrapWriteStream(r io.Reader) io.Reader {
+	total := streamTotal(r)
+	r = c.faults.wrapStream(r)
+	if c.progress != nil {
+		r = NewProgressReader(r, total, c.progress)
+	}
+	return r
+}
+
+// streamTotal は、r から読み込める残りのバイト数を返します。不明な場合は -1 を返します。
+func streamTotal(r io.Reader) int64 {
+	switch s := r.(type) {
+	case *idleReadCloser:
+		return streamTotal(s.rc)
+	case *storage.Reader:
+		return s.Remain()
+	case *io.SectionReader:
+		return s.Size()
+	case interface{ Len() int }: // bytes.Reader、strings.Reader、bytes.Buffer
+		return int64(s.Len())
+	case *os.File:
+		info, err := s.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := s.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - offset
+	}
+	return -1
+}
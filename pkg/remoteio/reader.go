@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"strings"
 
 	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
 )
 
 // =================================================================
@@ -21,6 +23,12 @@ type InputReader interface {
 	Open(ctx context.Context, filePath string) (io.ReadCloser, error)
 }
 
+// OptionOpener は、読み込みごとのオプション (ReadOption) を指定してストリームを開くためのインターフェースです。
+type OptionOpener interface {
+	// OpenWith は、opts を適用して、指定されたパスから io.ReadCloser を返します。
+	OpenWith(ctx context.Context, filePath string, opts ...ReadOption) (io.ReadCloser, error)
+}
+
 // =================================================================
 // 2. 具象構造体とコンストラクタ
 // =================================================================
@@ -29,13 +37,15 @@ type InputReader interface {
 // ローカルファイルと GCS オブジェクトの読み込みを処理します。
 type LocalGCSInputReader struct {
 	gcsClient *storage.Client
+	cfg       config
 }
 
 // NewLocalGCSInputReader は LocalGCSInputReader の新しいインスタンスを作成します。
 // 依存関係として GCS クライアントを注入します。
-func NewLocalGCSInputReader(gcsClient *storage.Client) *LocalGCSInputReader {
+func NewLocalGCSInputReader(gcsClient *storage.Client, opts ...Option) *LocalGCSInputReader {
 	return &LocalGCSInputReader{
 		gcsClient: gcsClient,
+		cfg:       newConfig(opts),
 	}
 }
 
@@ -43,53 +53,209 @@ func NewLocalGCSInputReader(gcsClient *storage.Client) *LocalGCSInputReader {
 // 3. コアロジック (実装)
 // =================================================================
 
-// Open は、ファイルパスを検査し、ローカルファイルまたはGCSからストリームを開きます。
-func (r *LocalGCSInputReader) Open(ctx context.Context, filePath string) (io.ReadCloser, error) {
-	// GCS URI 判定ロジック
-	if strings.HasPrefix(filePath, "gs://") {
-		return r.openGCSObject(ctx, filePath)
+// Open は、ファイルパスを検査し、ローカルファイル、またはURIのスキームに対応するバックエンドからストリームを開きます。
+// GCS URI は "gs://bucket/object#generation" の形式で世代番号を指定でき、その後オブジェクトが上書きされても指定した世代を読み込みます
+// (OpenRange と Stat も同様です)。
+func (r *LocalGCSInputReader) Open(ctx context.Context, filePath string) (_ io.ReadCloser, err error) {
+	defer classifyError(&err)
+	ctx, span := r.cfg.startSpan(ctx, "remoteio.Open", filePath)
+	defer func() {
+		if err != nil {
+			span.end(err)
+		}
+	}()
+	if err := r.cfg.faults.beforeOp("Open", filePath); err != nil {
+		return nil, err
+	}
+
+	// URI のスキームに登録されたバックエンド (gs, s3, az, sftp および RegisterScheme で登録されたもの) へ委譲する
+	h, ok, err := lookupScheme(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if h.open == nil {
+			return nil, fmt.Errorf(Message("スキーム %s:// は読み込みをサポートしていません: %s"), SchemeOf(filePath), filePath)
+		}
+		rc, err := r.cfg.openWatched(ctx, func(ctx context.Context) (io.ReadCloser, error) {
+			return h.open(ctx, r, filePath)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return span.traceReadCloser(r.cfg.wrapReadCloser(rc)), nil
 	}
 
 	// ローカルファイルパスの処理
 	file, err := os.Open(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("ローカルファイルのオープンに失敗しました: %w", err)
+		return nil, fmt.Errorf(Message("ローカルファイルのオープンに失敗しました: %w"), err)
 	}
-	return file, nil
+	return span.traceReadCloser(r.cfg.wrapReadCloser(file)), nil
+}
+
+// OpenWith は、この読み込みに限り opts を適用して、Open と同様にストリームを開きます。
+func (r *LocalGCSInputReader) OpenWith(ctx context.Context, filePath string, opts ...ReadOption) (_ io.ReadCloser, err error) {
+	defer classifyError(&err)
+	rr := *r
+	for _, opt := range opts {
+		opt(&rr.cfg.read)
+	}
+	return rr.Open(ctx, filePath)
 }
 
 // openGCSObject は、GCS URI からオブジェクトを読み込み、io.ReadCloser を返します。
 func (r *LocalGCSInputReader) openGCSObject(ctx context.Context, gcsURI string) (io.ReadCloser, error) {
-	if r.gcsClient == nil {
-		return nil, fmt.Errorf("GCSクライアントが初期化されていないため、GCSオブジェクトを読み込めません (URI: %s)", gcsURI)
+	obj, err := r.gcsObject(gcsURI)
+	if err != nil {
+		return nil, err
+	}
+
+	// GCS オブジェクトリーダーを作成
+	rc, err := obj.ReadCompressed(r.cfg.read.compressed).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(Message("GCSファイルの読み込みに失敗しました (URI: %s): %w"), gcsURI, err)
+	}
+	return rc, nil
+}
+
+// openGCSRange は、GCS オブジェクトの offset から length バイト (負の場合は終端まで) を読み込みます。
+func (r *LocalGCSInputReader) openGCSRange(ctx context.Context, gcsURI string, offset, length int64) (io.ReadCloser, error) {
+	obj, err := r.gcsObject(gcsURI)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := obj.ReadCompressed(r.cfg.read.compressed).NewRangeReader(ctx, offset, length)
+	if err != nil {
+		return nil, fmt.Errorf(Message("GCSファイルの範囲読み込みに失敗しました (URI: %s): %w"), gcsURI, err)
 	}
+	return rc, nil
+}
 
-	// URIのパースロジック
-	path := gcsURI[5:]                    // "gs://" を削除
+// statGCSObject は、GCS オブジェクトの属性を返します。
+func (r *LocalGCSInputReader) statGCSObject(ctx context.Context, gcsURI string) (ObjectInfo, error) {
+	obj, err := r.gcsObject(gcsURI)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	attrs, err := obj.Attrs(ctx)
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf(Message("GCSオブジェクトの属性の取得に失敗しました (URI: %s): %w"), gcsURI, err)
+	}
+	return ObjectInfo{
+		URI:             gcsURI,
+		Name:            path.Base(attrs.Name),
+		Size:            attrs.Size,
+		Updated:         attrs.Updated,
+		CRC32C:          &attrs.CRC32C,
+		StorageClass:    attrs.StorageClass,
+		ContentType:     attrs.ContentType,
+		ContentEncoding: attrs.ContentEncoding,
+		MD5:             attrs.MD5,
+		Generation:      attrs.Generation,
+		Metageneration:  attrs.Metageneration,
+		Metadata:        attrs.Metadata,
+		KMSKeyName:      attrs.KMSKeyName,
+	}, nil
+}
+
+// listGCSPage は、GCS のプレフィックス配下のオブジェクトを1ページ分一覧します。
+// o.pageSize が0以下の場合は、o.pageToken 以降のすべてのオブジェクトを1ページとして返します。
+func (r *LocalGCSInputReader) listGCSPage(ctx context.Context, gcsURI string, o listOptions) (ObjectPage, error) {
+	client, err := r.gcs()
+	if err != nil {
+		return ObjectPage{}, fmt.Errorf(Message("GCSクライアントが初期化されていないため、GCSオブジェクトを一覧できません (URI: %s): %w"), gcsURI, err)
+	}
+	bucketName, objectPath, err := ParseGCSURI(gcsURI)
+	if err != nil {
+		return ObjectPage{}, err
+	}
+
+	prefix := listPrefix(objectPath)
+	it := r.cfg.gcsBucket(client, bucketName).Objects(ctx, &storage.Query{Prefix: prefix, Delimiter: o.delimiter})
+
+	var page ObjectPage
+	var items []*storage.ObjectAttrs
+	if o.pageSize > 0 {
+		page.NextPageToken, err = iterator.NewPager(it, o.pageSize, o.pageToken).NextPage(&items)
+		if err != nil {
+			return ObjectPage{}, fmt.Errorf(Message("GCSオブジェクトの一覧取得に失敗しました (URI: %s): %w"), gcsURI, err)
+		}
+	} else {
+		it.PageInfo().Token = o.pageToken
+		for {
+			attrs, err := it.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return ObjectPage{}, fmt.Errorf(Message("GCSオブジェクトの一覧取得に失敗しました (URI: %s): %w"), gcsURI, err)
+			}
+			items = append(items, attrs)
+		}
+	}
+
+	for _, attrs := range items {
+		if attrs.Prefix != "" {
+			// Delimiter を指定した場合の共通プレフィックス
+			page.Objects = append(page.Objects, ObjectInfo{
+				URI:      fmt.Sprintf("gs://%s/%s", bucketName, attrs.Prefix),
+				Name:     strings.TrimPrefix(attrs.Prefix, prefix),
+				IsPrefix: true,
+			})
+			continue
+		}
+		if strings.HasSuffix(attrs.Name, "/") {
+			continue
+		}
+		page.Objects = append(page.Objects, ObjectInfo{
+			URI:          fmt.Sprintf("gs://%s/%s", bucketName, attrs.Name),
+			Name:         strings.TrimPrefix(attrs.Name, prefix),
+			Size:         attrs.Size,
+			Updated:      attrs.Updated,
+			CRC32C:       &attrs.CRC32C,
+			StorageClass: attrs.StorageClass,
+		})
+	}
+	return page, nil
+}
+
+// gcsObject は、GCS URI を検証し、オブジェクトのハンドルを返します。
+func (r *LocalGCSInputReader) gcsObject(gcsURI string) (*storage.ObjectHandle, error) {
+	client, err := r.gcs()
+	if err != nil {
+		return nil, fmt.Errorf(Message("GCSクライアントが初期化されていないため、GCSオブジェクトを読み込めません (URI: %s): %w"), gcsURI, err)
+	}
+
+	// URIのパースロジック ("#generation" で世代番号が指定された場合は、その世代を読み込む)
+	base, generation := SplitGCSGeneration(gcsURI)
+	path := base[5:]                      // "gs://" を削除
 	parts := strings.SplitN(path, "/", 2) // バケットとオブジェクトに分割
 
 	// 1. スラッシュの数が不正な場合（例: gs://bucket）
 	if len(parts) != 2 {
-		return nil, fmt.Errorf("無効なGCS URI形式です: %s (gs://bucket-name/object-name の形式で指定してください。スラッシュの数が不正です)", gcsURI)
+		return nil, invalidURIError("無効なGCS URI形式です: %s (gs://bucket-name/object-name の形式で指定してください。スラッシュの数が不正です)", gcsURI)
 	}
 	bucketName := parts[0]
 	objectName := parts[1]
 
 	// 2. バケット名が空の場合（例: gs:///object）
 	if bucketName == "" {
-		return nil, fmt.Errorf("無効なGCS URI形式です: %s (バケット名が空です)", gcsURI)
+		return nil, invalidURIError("無効なGCS URI形式です: %s (バケット名が空です)", gcsURI)
 	}
 
 	// 3. オブジェクト名が空の場合（例: gs://bucket/）
 	if objectName == "" {
-		return nil, fmt.Errorf("無効なGCS URI形式です: %s (オブジェクト名が空です。このInputReaderは単一のGCSオブジェクトの読み込みに特化しており、ディレクトリパスはサポートしていません)", gcsURI)
+		return nil, invalidURIError("無効なGCS URI形式です: %s (オブジェクト名が空です。このInputReaderは単一のGCSオブジェクトの読み込みに特化しており、ディレクトリパスはサポートしていません)", gcsURI)
 	}
 	// GCS URI パースロジック完了
 
-	// GCS オブジェクトリーダーを作成
-	rc, err := r.gcsClient.Bucket(bucketName).Object(objectName).NewReader(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("GCSファイルの読み込みに失敗しました (URI: %s): %w", gcsURI, err)
+	obj := r.cfg.gcsBucket(client, bucketName).Object(objectName)
+	if generation > 0 {
+		obj = obj.Generation(generation)
 	}
-	return rc, nil
+	return obj, nil
 }
+
+// 型アサーションチェック
+var _ OptionOpener = (*LocalGCSInputReader)(nil)
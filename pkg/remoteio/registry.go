@@ -0,0 +1,285 @@
+package remoteio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// =================================================================
+// 1. 公開API
+// =================================================================
+
+// OpenerFunc は、独自スキームの URI から読み込みストリームを開く関数です。
+type OpenerFunc func(ctx context.Context, uri string) (io.ReadCloser, error)
+
+// WriterFunc は、独自スキームの URI へ r の内容を書き込む関数です。
+// r の読み込みが io.EOF 以外のエラーを返した場合 (バリデータによる拒否を含む) は、書き込み先を確定してはいけません。
+type WriterFunc func(ctx context.Context, uri string, r io.Reader, contentType string) error
+
+// RegisterScheme は、scheme ("foo://..." の "foo") の URI を扱う関数を登録します。
+// 登録後は、InputReader.Open と OutputWriter.Write がこのスキームの URI を open / write へ委譲します。
+// 読み込み専用・書き込み専用のスキームでは、open または write に nil を指定できます。
+//
+// 同じスキームを二重に登録した場合 (組み込みの gs, s3, az, sftp を含む) は panic します。
+// 通常は init 関数から呼び出してください。
+func RegisterScheme(scheme string, open OpenerFunc, write WriterFunc) {
+	if !validScheme(scheme) {
+		panic(fmt.Sprintf("remoteio: 無効なスキーム名です: %q", scheme))
+	}
+	if open == nil && write == nil {
+		panic(fmt.Sprintf("remoteio: スキーム %q の open と write が両方とも nil です", scheme))
+	}
+
+	h := schemeHandler{}
+	if open != nil {
+		h.open = func(ctx context.Context, r *LocalGCSInputReader, uri string) (io.ReadCloser, error) {
+			return open(ctx, uri)
+		}
+	}
+	if write != nil {
+		h.write = func(ctx context.Context, w *UniversalIOWriter, uri string, rd io.Reader, contentType string) error {
+			return w.writeRegistered(ctx, scheme, write, uri, rd, contentType)
+		}
+	}
+	registerHandler(scheme, h)
+}
+
+// RegisteredSchemes は、登録されているスキームの一覧 (組み込みを含む) をソートして返します。
+func RegisteredSchemes() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	schemes := make([]string, 0, len(registry.handlers))
+	for s := range registry.handlers {
+		schemes = append(schemes, s)
+	}
+	slices.Sort(schemes)
+	return schemes
+}
+
+// SchemeOf は、URI のスキーム ("gs://bucket/obj" の "gs") を返します。
+// ローカルファイルパスなど、"scheme://" の形式でない場合は空文字列を返します。
+func SchemeOf(uri string) string {
+	scheme, _, ok := strings.Cut(uri, "://")
+	if !ok || !validScheme(scheme) {
+		return ""
+	}
+	return scheme
+}
+
+// =================================================================
+// 2. レジストリ
+// =================================================================
+
+// schemeHandler は、スキームごとの読み込み・書き込み処理です。
+// 組み込みのバックエンドは、リーダー・ライターが保持するクライアントと構成を使用します。
+// openRange、stat、list、listPage、remove と copy は省略可能で、openRange が nil の場合は先頭から読み飛ばして範囲読み込みを行います。
+// listPage が nil の場合は、list で取得したすべてのファイルから区切り文字による集約とページ分割を行います。
+type schemeHandler struct {
+	open      func(ctx context.Context, r *LocalGCSInputReader, uri string) (io.ReadCloser, error)
+	openRange func(ctx context.Context, r *LocalGCSInputReader, uri string, offset, length int64) (io.ReadCloser, error)
+	stat      func(ctx context.Context, r *LocalGCSInputReader, uri string) (ObjectInfo, error)
+	list      func(ctx context.Context, r *LocalGCSInputReader, uri string) ([]ObjectInfo, error)
+	listPage  func(ctx context.Context, r *LocalGCSInputReader, uri string, o listOptions) (ObjectPage, error)
+	write     func(ctx context.Context, w *UniversalIOWriter, uri string, rd io.Reader, contentType string) error
+	remove    func(ctx context.Context, w *UniversalIOWriter, uri string) error
+	copy      func(ctx context.Context, w *UniversalIOWriter, srcURI, dstURI string) error
+}
+
+var registry = struct {
+	sync.RWMutex
+	handlers map[string]schemeHandler
+}{handlers: map[string]schemeHandler{}}
+
+func init() {
+	registerHandler("gs", schemeHandler{
+		open: func(ctx context.Context, r *LocalGCSInputReader, uri string) (io.ReadCloser, error) {
+			return r.openGCSObject(ctx, uri)
+		},
+		openRange: func(ctx context.Context, r *LocalGCSInputReader, uri string, offset, length int64) (io.ReadCloser, error) {
+			return r.openGCSRange(ctx, uri, offset, length)
+		},
+		stat: func(ctx context.Context, r *LocalGCSInputReader, uri string) (ObjectInfo, error) {
+			return r.statGCSObject(ctx, uri)
+		},
+		listPage: func(ctx context.Context, r *LocalGCSInputReader, uri string, o listOptions) (ObjectPage, error) {
+			return r.listGCSPage(ctx, uri, o)
+		},
+		write: func(ctx context.Context, w *UniversalIOWriter, uri string, rd io.Reader, contentType string) error {
+			bucketName, objectPath, err := ParseGCSURI(uri)
+			if err != nil {
+				return fmt.Errorf(Message("GCS URIのパース失敗: %w"), err)
+			}
+			return w.WriteToGCS(ctx, bucketName, objectPath, rd, contentType)
+		},
+		remove: func(ctx context.Context, w *UniversalIOWriter, uri string) error {
+			return w.deleteGCSObject(ctx, uri)
+		},
+		copy: func(ctx context.Context, w *UniversalIOWriter, srcURI, dstURI string) error {
+			return w.copyGCSObject(ctx, srcURI, dstURI)
+		},
+	})
+	registerHandler("s3", schemeHandler{
+		open: func(ctx context.Context, r *LocalGCSInputReader, uri string) (io.ReadCloser, error) {
+			return openS3Object(ctx, r.cfg.s3Client, uri)
+		},
+		openRange: func(ctx context.Context, r *LocalGCSInputReader, uri string, offset, length int64) (io.ReadCloser, error) {
+			return openS3Range(ctx, r.cfg.s3Client, uri, offset, length)
+		},
+		stat: func(ctx context.Context, r *LocalGCSInputReader, uri string) (ObjectInfo, error) {
+			return statS3Object(ctx, r.cfg.s3Client, uri)
+		},
+		list: func(ctx context.Context, r *LocalGCSInputReader, uri string) ([]ObjectInfo, error) {
+			return listS3Objects(ctx, r.cfg.s3Client, uri)
+		},
+		write: func(ctx context.Context, w *UniversalIOWriter, uri string, rd io.Reader, contentType string) error {
+			bucketName, key, err := ParseS3URI(uri)
+			if err != nil {
+				return fmt.Errorf(Message("S3 URIのパース失敗: %w"), err)
+			}
+			return w.WriteToS3(ctx, bucketName, key, rd, contentType)
+		},
+		remove: func(ctx context.Context, w *UniversalIOWriter, uri string) error {
+			return deleteS3Object(ctx, w.cfg.s3Client, uri)
+		},
+		copy: func(ctx context.Context, w *UniversalIOWriter, srcURI, dstURI string) error {
+			return copyS3Object(ctx, w.cfg.s3Client, srcURI, dstURI, w.cfg.noClobber)
+		},
+	})
+	registerHandler("az", schemeHandler{
+		open: func(ctx context.Context, r *LocalGCSInputReader, uri string) (io.ReadCloser, error) {
+			return openAzureBlob(ctx, r.cfg.azureClient, uri)
+		},
+		openRange: func(ctx context.Context, r *LocalGCSInputReader, uri string, offset, length int64) (io.ReadCloser, error) {
+			return openAzureRange(ctx, r.cfg.azureClient, uri, offset, length)
+		},
+		stat: func(ctx context.Context, r *LocalGCSInputReader, uri string) (ObjectInfo, error) {
+			return statAzureBlob(ctx, r.cfg.azureClient, uri)
+		},
+		list: func(ctx context.Context, r *LocalGCSInputReader, uri string) ([]ObjectInfo, error) {
+			return listAzureBlobs(ctx, r.cfg.azureClient, uri)
+		},
+		write: func(ctx context.Context, w *UniversalIOWriter, uri string, rd io.Reader, contentType string) error {
+			containerName, blobName, err := ParseAzureURI(uri)
+			if err != nil {
+				return fmt.Errorf(Message("Azure URIのパース失敗: %w"), err)
+			}
+			return w.WriteToAzure(ctx, containerName, blobName, rd, contentType)
+		},
+		remove: func(ctx context.Context, w *UniversalIOWriter, uri string) error {
+			return deleteAzureBlob(ctx, w.cfg.azureClient, uri)
+		},
+	})
+	registerHandler("sftp", schemeHandler{
+		open: func(ctx context.Context, r *LocalGCSInputReader, uri string) (io.ReadCloser, error) {
+			return openSFTPFile(ctx, r.cfg.sftpConfig(), uri)
+		},
+		openRange: func(ctx context.Context, r *LocalGCSInputReader, uri string, offset, length int64) (io.ReadCloser, error) {
+			return openSFTPRange(ctx, r.cfg.sftpConfig(), uri, offset, length)
+		},
+		stat: func(ctx context.Context, r *LocalGCSInputReader, uri string) (ObjectInfo, error) {
+			return statSFTPFile(ctx, r.cfg.sftpConfig(), uri)
+		},
+		list: func(ctx context.Context, r *LocalGCSInputReader, uri string) ([]ObjectInfo, error) {
+			return listSFTPFiles(ctx, r.cfg.sftpConfig(), uri)
+		},
+		// SFTPサーバーへの書き込みでは contentType は無視される
+		write: func(ctx context.Context, w *UniversalIOWriter, uri string, rd io.Reader, contentType string) error {
+			address, filePath, err := ParseSFTPURI(uri)
+			if err != nil {
+				return fmt.Errorf(Message("SFTP URIのパース失敗: %w"), err)
+			}
+			return w.WriteToSFTP(ctx, address, filePath, rd)
+		},
+		remove: func(ctx context.Context, w *UniversalIOWriter, uri string) error {
+			return deleteSFTPFile(ctx, w.cfg.sftpConfig(), uri)
+		},
+	})
+}
+
+// registerHandler は、scheme のハンドラを登録します。二重登録の場合は panic します。
+func registerHandler(scheme string, h schemeHandler) {
+	registry.Lock()
+	defer registry.Unlock()
+	if _, dup := registry.handlers[scheme]; dup {
+		panic(fmt.Sprintf("remoteio: スキーム %q は既に登録されています", scheme))
+	}
+	registry.handlers[scheme] = h
+}
+
+// lookupScheme は、URI のスキームに対応するハンドラを返します。
+// ローカルファイルパスの場合は ok が false になり、未登録のスキームの場合はエラーを返します。
+func lookupScheme(uri string) (h schemeHandler, ok bool, err error) {
+	scheme := SchemeOf(uri)
+	if scheme == "" {
+		return schemeHandler{}, false, nil
+	}
+	registry.RLock()
+	defer registry.RUnlock()
+	h, ok = registry.handlers[scheme]
+	if !ok {
+		return schemeHandler{}, false, fmt.Errorf(Message("サポートされていないスキームです (%s://): %s"), scheme, uri)
+	}
+	return h, true, nil
+}
+
+// validScheme は、RFC 3986 のスキーム名として有効かを判定します。
+func validScheme(s string) bool {
+	if s == "" || !isASCIILetter(s[0]) {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if !isASCIILetter(c) && !('0' <= c && c <= '9') && c != '+' && c != '-' && c != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+func isASCIILetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// =================================================================
+// 3. 独自スキームへの書き込み
+// =================================================================
+
+// writeRegistered は、RegisterScheme で登録された write に、故障注入とバリデータを適用して書き込みます。
+// バリデータが拒否した場合、write には io.EOF の代わりにそのエラーが返されます。
+func (w *UniversalIOWriter) writeRegistered(ctx context.Context, scheme string, write WriterFunc, uri string, contentReader io.Reader, contentType string) error {
+	if err := w.cfg.faults.beforeOp("Write", uri); err != nil {
+		return err
+	}
+	contentReader = w.cfg.wrapWriteStream(contentReader)
+
+	if w.cfg.noClobber {
+		return fmt.Errorf(Message("スキーム %s:// は上書きの防止をサポートしていません: %s"), scheme, uri)
+	}
+
+	w.cfg.log().Info(Message("書き込み処理開始"), slog.String("uri", uri), slog.String("content_type", contentType))
+
+	info := TransferInfo{URI: uri, ContentType: contentType}
+	err := w.cfg.writeValidated(ctx, info, contentReader, func(ctx context.Context, r io.Reader, verdict func() error) error {
+		vr := &verdictReader{r: r, verdict: verdict}
+		err := write(ctx, uri, vr, contentType)
+		if vr.err != nil {
+			return vr.err
+		}
+		return err
+	}, writeTarget{
+		remove: func(ctx context.Context) error {
+			return fmt.Errorf(Message("スキーム %s:// は書き込み先の削除をサポートしていません"), scheme)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	w.cfg.log().Info(Message("書き込み処理完了"), slog.String("uri", uri))
+	return nil
+}
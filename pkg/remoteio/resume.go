@@ -0,0 +1,108 @@
+package remoteio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+)
+
+// ErrChecksumMismatch は、転送後の内容のチェックサムがコピー元と一致しない場合に返されるエラーです。
+var ErrChecksumMismatch = NewMessageError("remoteio: チェックサムがコピー元と一致しません")
+
+// castagnoliTable は、GCS のチェックサムと同じ CRC32C (Castagnoli) の計算に使用するテーブルです。
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ResumableDownloader は、中断されたローカルファイルへのダウンロードを再開するためのインターフェースです。
+type ResumableDownloader interface {
+	// ContinueDownload は、localPath に途中までダウンロードされた uri の続きをダウンロードします。
+	// 今回ダウンロードしたバイト数を返します。
+	ContinueDownload(ctx context.Context, uri, localPath string) (int64, error)
+}
+
+// ContinueDownload は ResumableDownloader インターフェースを実装します。
+// ローカルファイルの現在のサイズを位置として、範囲読み込みで残りを追記します。ローカルファイルがない場合は先頭からダウンロードします。
+// 完了後にファイル全体の CRC32C をコピー元と比較し、一致しない場合は (途中で内容が変更された場合など) ローカルファイルを削除して
+// ErrChecksumMismatch を返します。コピー元の CRC32C が取得できない場合 (ローカルファイルや SFTP など) は、サイズのみを確認します。
+func (r *LocalGCSInputReader) ContinueDownload(ctx context.Context, uri, localPath string) (_ int64, err error) {
+	defer classifyError(&err)
+	if err := r.cfg.faults.beforeOp("ContinueDownload", uri); err != nil {
+		return 0, err
+	}
+	info, err := r.Stat(ctx, uri)
+	if err != nil {
+		return 0, err
+	}
+	if info.IsPrefix {
+		return 0, fmt.Errorf(Message("ディレクトリはダウンロードできません: %s"), uri)
+	}
+
+	var offset int64
+	switch local, err := os.Stat(localPath); {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return 0, fmt.Errorf(Message("ローカルファイルの情報の取得に失敗しました: %w"), err)
+	case local.Size() > info.Size:
+		return 0, fmt.Errorf(Message("ローカルファイル(%s)がコピー元より大きいため再開できません (%d > %d バイト)"), localPath, local.Size(), info.Size)
+	default:
+		offset = local.Size()
+	}
+
+	if err := r.cfg.mkdirParent(localPath); err != nil {
+		return 0, err
+	}
+	file, err := r.cfg.openLocalFile(localPath, os.O_RDWR|os.O_CREATE|os.O_APPEND)
+	if err != nil {
+		return 0, fmt.Errorf(Message("ローカルファイルのオープンに失敗しました: %w"), err)
+	}
+	defer file.Close()
+
+	// 1. 残りの範囲を追記する
+	var n int64
+	if offset < info.Size {
+		r.cfg.log().Info(Message("ダウンロードを再開します"), slog.String("uri", uri), slog.String("path", localPath), slog.Int64("offset", offset), slog.Int64("size", info.Size))
+		rc, err := r.OpenRange(ctx, uri, offset, -1)
+		if err != nil {
+			return 0, err
+		}
+		defer rc.Close()
+		n, err = r.cfg.copyBuffer(file, rc)
+		if err != nil {
+			// 書き込めた分は次回の再開に使用するため、ファイルは残す
+			return n, fmt.Errorf(Message("ダウンロードが中断されました (%s): %w"), uri, err)
+		}
+	}
+
+	// 2. ファイル全体のサイズとチェックサムを確認する
+	if offset+n != info.Size {
+		return n, fmt.Errorf(Message("ダウンロードしたサイズがコピー元と一致しません (%s): %d / %d バイト"), uri, offset+n, info.Size)
+	}
+	if info.CRC32C != nil {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return n, fmt.Errorf(Message("ローカルファイルのシークに失敗しました: %w"), err)
+		}
+		h := crc32.New(castagnoliTable)
+		if _, err := io.Copy(h, file); err != nil {
+			return n, fmt.Errorf(Message("チェックサムの計算に失敗しました (%s): %w"), localPath, err)
+		}
+		if h.Sum32() != *info.CRC32C {
+			file.Close()
+			os.Remove(localPath)
+			return n, fmt.Errorf("%w: %s (CRC32C %08x != %08x)", ErrChecksumMismatch, uri, h.Sum32(), *info.CRC32C)
+		}
+	}
+
+	if err := r.cfg.syncLocalFile(file); err != nil {
+		return n, err
+	}
+
+	r.cfg.log().Info(Message("ダウンロード完了"), slog.String("uri", uri), slog.String("path", localPath), slog.Int64("resumed_from", offset))
+	return n, nil
+}
+
+// 型アサーションチェック
+var _ ResumableDownloader = (*LocalGCSInputReader)(nil)
@@ -0,0 +1,71 @@
+package remoteio
+
+import (
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/googleapis/gax-go/v2"
+)
+
+// RetryPolicy は、一時的なエラー (429、5xx、接続のリセットなど) で失敗した GCS へのリクエストを再試行する方針です。
+// 再試行するかどうかの判定には storage.ShouldRetry を使用します。
+// 待ち時間はバックオフの上限から 0 までのランダムな値 (ジッター) となり、多数のクライアントが同時に再試行するのを避けます。
+type RetryPolicy struct {
+	// MaxAttempts は、最初の試行を含む最大試行回数です。1 の場合は再試行しません。
+	// 0 の場合は回数を制限せず、コンテキストのタイムアウトまで再試行します。
+	MaxAttempts int
+	// InitialBackoff は、最初の再試行までの待ち時間の上限です。0 の場合は 1 秒です。
+	InitialBackoff time.Duration
+	// MaxBackoff は、再試行までの待ち時間の上限の最大値です。0 の場合は 30 秒です。
+	MaxBackoff time.Duration
+	// Multiplier は、再試行のたびに待ち時間の上限を増やす倍率です。1 以下の場合は 2 です。
+	Multiplier float64
+	// RetryNonIdempotent が true の場合は、前提条件 (世代番号の一致など) のない書き込みや削除のように、
+	// 再試行すると結果が変わる可能性のある操作も再試行します。false の場合は冪等な操作のみを再試行します。
+	RetryNonIdempotent bool
+}
+
+// GCSRetryOptions は、p を storage.Client.SetRetry や ObjectHandle.Retryer に指定するオプションに変換します。
+func (p RetryPolicy) GCSRetryOptions() []storage.RetryOption {
+	backoff := gax.Backoff{Initial: time.Second, Max: 30 * time.Second, Multiplier: 2}
+	if p.InitialBackoff > 0 {
+		backoff.Initial = p.InitialBackoff
+	}
+	if p.MaxBackoff > 0 {
+		backoff.Max = max(p.MaxBackoff, backoff.Initial)
+	}
+	if p.Multiplier > 1 {
+		backoff.Multiplier = p.Multiplier
+	}
+
+	policy := storage.RetryIdempotent
+	if p.RetryNonIdempotent {
+		policy = storage.RetryAlways
+	}
+	opts := []storage.RetryOption{storage.WithBackoff(backoff), storage.WithPolicy(policy)}
+	if p.MaxAttempts > 0 {
+		opts = append(opts, storage.WithMaxAttempts(p.MaxAttempts))
+	}
+	return opts
+}
+
+// WithRetryPolicy は、GCS への読み込み・書き込み・一覧などのリクエストを p に従って再試行します。
+// 指定しない場合は、クライアントの設定 (storage.Client.SetRetry、既定では冪等な操作のみを無制限に再試行) に従います。
+// InputReader と OutputWriter の両方に適用されます。
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *config) {
+		c.retry = p.GCSRetryOptions()
+	}
+}
+
+// gcsBucket は、WithRetryPolicy で指定された再試行の方針と、WithBillingProject で指定された請求先のプロジェクトを適用したバケットのハンドルを返します。
+func (c *config) gcsBucket(client *storage.Client, name string) *storage.BucketHandle {
+	bucket := client.Bucket(name)
+	if c.retry != nil {
+		bucket = bucket.Retryer(c.retry...)
+	}
+	if c.userProject != "" {
+		bucket = bucket.UserProject(c.userProject)
+	}
+	return bucket
+}
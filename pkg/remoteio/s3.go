@@ -0,0 +1,372 @@
+package remoteio
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// =================================================================
+// 1. インターフェース定義
+// =================================================================
+
+// S3OutputWriter は、Amazon S3 にコンテンツを書き込むためのインターフェースです。
+type S3OutputWriter interface {
+	// WriteToS3 は、指定されたバケットとオブジェクトキーに io.Reader からコンテンツを書き込みます。
+	WriteToS3(ctx context.Context, bucketName, key string, contentReader io.Reader, contentType string) error
+}
+
+// =================================================================
+// 2. 読み込み (S3InputReader)
+// =================================================================
+
+// S3InputReader は、S3 オブジェクトの読み込みのみを処理する InputReader の具象実装です。
+// ローカルファイルや GCS と併せて扱う場合は、WithS3Client を指定した LocalGCSInputReader を使用してください。
+type S3InputReader struct {
+	s3Client *s3.Client
+	cfg      config
+}
+
+// NewS3InputReader は S3InputReader の新しいインスタンスを作成します。
+func NewS3InputReader(client *s3.Client, opts ...Option) *S3InputReader {
+	return &S3InputReader{s3Client: client, cfg: newConfig(opts)}
+}
+
+// Open は、s3:// URI で指定されたオブジェクトのストリームを開きます。
+func (r *S3InputReader) Open(ctx context.Context, uri string) (io.ReadCloser, error) {
+	if err := r.cfg.faults.beforeOp("Open", uri); err != nil {
+		return nil, err
+	}
+	rc, err := openS3Object(ctx, r.s3Client, uri)
+	if err != nil {
+		return nil, err
+	}
+	return r.cfg.wrapReadCloser(rc), nil
+}
+
+// openS3Object は、S3 URI からオブジェクトを読み込み、io.ReadCloser を返します。
+func openS3Object(ctx context.Context, client *s3.Client, s3URI string) (io.ReadCloser, error) {
+	bucketName, key, err := s3ObjectKey(client, s3URI)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf(Message("S3ファイルの読み込みに失敗しました (URI: %s): %w"), s3URI, err)
+	}
+	return out.Body, nil
+}
+
+// openS3Range は、S3 オブジェクトの offset から length バイト (負の場合は終端まで) を Range 指定で読み込みます。
+func openS3Range(ctx context.Context, client *s3.Client, s3URI string, offset, length int64) (io.ReadCloser, error) {
+	bucketName, key, err := s3ObjectKey(client, s3URI)
+	if err != nil {
+		return nil, err
+	}
+
+	byteRange := fmt.Sprintf("bytes=%d-", offset)
+	if length > 0 {
+		byteRange += strconv.FormatInt(offset+length-1, 10)
+	}
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+		Range:  aws.String(byteRange),
+	})
+	if err != nil {
+		return nil, fmt.Errorf(Message("S3ファイルの範囲読み込みに失敗しました (URI: %s): %w"), s3URI, err)
+	}
+	return out.Body, nil
+}
+
+// statS3Object は、S3 オブジェクトの属性を返します。
+func statS3Object(ctx context.Context, client *s3.Client, s3URI string) (ObjectInfo, error) {
+	bucketName, key, err := s3ObjectKey(client, s3URI)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	out, err := client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf(Message("S3オブジェクトの属性の取得に失敗しました (URI: %s): %w"), s3URI, err)
+	}
+	info := ObjectInfo{
+		URI:             s3URI,
+		Name:            path.Base(key),
+		Size:            aws.ToInt64(out.ContentLength),
+		Updated:         aws.ToTime(out.LastModified),
+		StorageClass:    string(out.StorageClass),
+		ContentType:     aws.ToString(out.ContentType),
+		ContentEncoding: aws.ToString(out.ContentEncoding),
+		Metadata:        out.Metadata,
+	}
+	// マルチパートアップロード以外のオブジェクトでは、ETag が内容の MD5 になる
+	if etag := strings.Trim(aws.ToString(out.ETag), `"`); len(etag) == 32 {
+		if sum, err := hex.DecodeString(etag); err == nil {
+			info.MD5 = sum
+		}
+	}
+	return info, nil
+}
+
+// listS3Objects は、S3 のプレフィックス配下のオブジェクトを一覧します。
+func listS3Objects(ctx context.Context, client *s3.Client, s3URI string) ([]ObjectInfo, error) {
+	if client == nil {
+		return nil, fmt.Errorf(Message("S3クライアントが初期化されていないため、S3オブジェクトを一覧できません (URI: %s)"), s3URI)
+	}
+	bucketName, key, err := ParseS3URI(s3URI)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := listPrefix(key)
+	var objects []ObjectInfo
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucketName),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf(Message("S3オブジェクトの一覧取得に失敗しました (URI: %s): %w"), s3URI, err)
+		}
+		for _, obj := range page.Contents {
+			name := aws.ToString(obj.Key)
+			if strings.HasSuffix(name, "/") {
+				continue
+			}
+			objects = append(objects, ObjectInfo{
+				URI:          fmt.Sprintf("s3://%s/%s", bucketName, name),
+				Name:         strings.TrimPrefix(name, prefix),
+				Size:         aws.ToInt64(obj.Size),
+				Updated:      aws.ToTime(obj.LastModified),
+				StorageClass: string(obj.StorageClass),
+			})
+		}
+	}
+	return objects, nil
+}
+
+// s3ObjectKey は、読み込み対象の S3 URI を検証し、バケット名とオブジェクトキーを返します。
+func s3ObjectKey(client *s3.Client, s3URI string) (bucketName, key string, err error) {
+	if client == nil {
+		return "", "", fmt.Errorf(Message("S3クライアントが初期化されていないため、S3オブジェクトを読み込めません (URI: %s)"), s3URI)
+	}
+
+	bucketName, key, err = ParseS3URI(s3URI)
+	if err != nil {
+		return "", "", err
+	}
+	if key == "" {
+		return "", "", invalidURIError("無効なS3 URI形式です: %s (オブジェクトキーが空です)", s3URI)
+	}
+	return bucketName, key, nil
+}
+
+// =================================================================
+// 3. 書き込み (UniversalIOWriter)
+// =================================================================
+
+// WriteToS3 は S3OutputWriter インターフェースを実装します。
+// サイズが不明なストリームにも対応するため、マルチパートアップロードで書き込みます。
+// contentType が空の場合は、key の拡張子または内容から Content-Type を判定します。
+func (w *UniversalIOWriter) WriteToS3(ctx context.Context, bucketName, key string, contentReader io.Reader, contentType string) (err error) {
+	defer classifyError(&err)
+	targetURI := fmt.Sprintf("s3://%s/%s", bucketName, key)
+
+	if bucketName == "" {
+		return invalidURIError("S3への書き込みに失敗しました: バケット名が空です")
+	}
+	if key == "" {
+		return invalidURIError("S3への書き込みに失敗しました: オブジェクトキーが空です")
+	}
+	client := w.cfg.s3Client
+	if client == nil {
+		return errors.New(Message("S3への書き込みに失敗しました: S3クライアントが初期化されていません"))
+	}
+
+	if err := w.cfg.faults.beforeOp("WriteToS3", targetURI); err != nil {
+		return err
+	}
+	contentReader = w.cfg.wrapWriteStream(contentReader)
+	// Content-Type が指定されていない場合は、拡張子または内容から判定する
+	contentType, contentReader, err = resolveContentType(key, contentType, contentReader)
+	if err != nil {
+		return err
+	}
+
+	w.cfg.log().Info(Message("S3書き込み処理開始"), slog.String("uri", targetURI), slog.String("content_type", contentType))
+
+	uploader := manager.NewUploader(client, func(u *manager.Uploader) {
+		if w.cfg.chunkSize != nil {
+			u.PartSize = max(int64(*w.cfg.chunkSize), manager.MinUploadPartSize)
+		}
+	})
+	info := TransferInfo{URI: targetURI, ContentType: contentType}
+	err = w.cfg.writeValidated(ctx, info, contentReader, func(ctx context.Context, r io.Reader, verdict func() error) error {
+		// 検査で拒否された場合は、ストリームの終端の代わりにエラーを返してアップロードを中止させる
+		vr := &verdictReader{r: r, verdict: verdict}
+		input := &s3.PutObjectInput{
+			Bucket:             aws.String(bucketName),
+			Key:                aws.String(key),
+			Body:               vr,
+			ContentType:        aws.String(contentType),
+			Metadata:           w.cfg.metadata,
+			CacheControl:       optional(w.cfg.headers.cacheControl),
+			ContentEncoding:    optional(w.cfg.headers.contentEncoding),
+			ContentDisposition: optional(w.cfg.headers.contentDisposition),
+			ContentLanguage:    optional(w.cfg.headers.contentLanguage),
+		}
+		if w.cfg.noClobber {
+			// マルチパートアップロードでは、完了のリクエストにも引き継がれる
+			input.IfNoneMatch = aws.String("*")
+		}
+		_, err := uploader.Upload(ctx, input)
+		if vr.err != nil {
+			return vr.err
+		}
+		if w.cfg.noClobber && isPreconditionFailed(err) {
+			return destinationExists(targetURI)
+		}
+		if err != nil {
+			w.cfg.log().Error(Message("S3へのコンテンツ書き込み中にエラーが発生"), slog.String("uri", targetURI), slog.String("error", err.Error()))
+			return fmt.Errorf(Message("S3へのコンテンツ書き込み中にエラーが発生しました: %w"), err)
+		}
+		return nil
+	}, writeTarget{
+		remove: func(ctx context.Context) error {
+			_, err := client.DeleteObject(ctx, &s3.DeleteObjectInput{
+				Bucket: aws.String(bucketName),
+				Key:    aws.String(key),
+			})
+			return err
+		},
+		setMetadata: func(ctx context.Context, md map[string]string) error {
+			// S3 のメタデータは変更できないため、同じキーへのコピーで置き換える
+			_, err := client.CopyObject(ctx, &s3.CopyObjectInput{
+				Bucket:            aws.String(bucketName),
+				Key:               aws.String(key),
+				CopySource:        aws.String(s3CopySource(bucketName, key)),
+				Metadata:          md,
+				MetadataDirective: types.MetadataDirectiveReplace,
+				// メタデータの置き換えでは HTTP ヘッダーも置き換えられるため、書き込み時と同じ値を指定する
+				ContentType:        aws.String(contentType),
+				CacheControl:       optional(w.cfg.headers.cacheControl),
+				ContentEncoding:    optional(w.cfg.headers.contentEncoding),
+				ContentDisposition: optional(w.cfg.headers.contentDisposition),
+				ContentLanguage:    optional(w.cfg.headers.contentLanguage),
+			})
+			return err
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	w.cfg.log().Info(Message("S3書き込み処理完了"), slog.String("uri", targetURI))
+	return nil
+}
+
+// copyS3Object は、S3 オブジェクトをサーバー側でコピーします。メタデータはコピー元から引き継がれます。
+// noClobber が true の場合は、dstURI が既に存在すれば ErrAlreadyExists を返します。
+func copyS3Object(ctx context.Context, client *s3.Client, srcURI, dstURI string, noClobber bool) error {
+	srcBucket, srcKey, err := s3ObjectKey(client, srcURI)
+	if err != nil {
+		return err
+	}
+	dstBucket, dstKey, err := s3ObjectKey(client, dstURI)
+	if err != nil {
+		return err
+	}
+	input := &s3.CopyObjectInput{
+		Bucket:     aws.String(dstBucket),
+		Key:        aws.String(dstKey),
+		CopySource: aws.String(s3CopySource(srcBucket, srcKey)),
+	}
+	if noClobber {
+		input.IfNoneMatch = aws.String("*")
+	}
+	_, err = client.CopyObject(ctx, input)
+	if noClobber && isPreconditionFailed(err) {
+		return destinationExists(dstURI)
+	}
+	if err != nil {
+		return fmt.Errorf(Message("S3オブジェクトのコピーに失敗しました (%s -> %s): %w"), srcURI, dstURI, err)
+	}
+	return nil
+}
+
+// deleteS3Object は、S3 URI で指定されたオブジェクトを削除します。
+func deleteS3Object(ctx context.Context, client *s3.Client, s3URI string) error {
+	bucketName, key, err := s3ObjectKey(client, s3URI)
+	if err != nil {
+		return err
+	}
+	_, err = client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf(Message("S3オブジェクトの削除に失敗しました (URI: %s): %w"), s3URI, err)
+	}
+	return nil
+}
+
+// =================================================================
+// 4. 内部ヘルパー
+// =================================================================
+
+// verdictReader は、ストリームの終端で verdict を呼び出し、拒否された場合は io.EOF の代わりにそのエラーを返します。
+// アップロードを確定する前に検査結果を反映させるために使用します。
+type verdictReader struct {
+	r       io.Reader
+	verdict func() error
+	err     error // verdict が返したエラー
+	done    bool
+}
+
+func (v *verdictReader) Read(p []byte) (int, error) {
+	if v.err != nil {
+		return 0, v.err
+	}
+	n, err := v.r.Read(p)
+	if err == io.EOF && !v.done {
+		v.done = true
+		if verr := v.verdict(); verr != nil {
+			v.err = verr
+			return n, verr
+		}
+	}
+	return n, err
+}
+
+// s3CopySource は、CopyObject の CopySource に指定する URL エンコード済みの "bucket/key" を返します。
+func s3CopySource(bucketName, key string) string {
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return bucketName + "/" + strings.Join(segments, "/")
+}
+
+// 型アサーションチェック
+var _ InputReader = (*S3InputReader)(nil)
+var _ S3OutputWriter = (*UniversalIOWriter)(nil)
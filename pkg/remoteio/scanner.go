@@ -0,0 +1,120 @@
+package remoteio
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// ErrThreatDetected は、コンテンツスキャンで脅威が検出されたことを示すエラーです。
+var ErrThreatDetected = NewMessageError("コンテンツスキャンで脅威が検出されました")
+
+// スキャン結果としてオブジェクトメタデータに記録するキー
+const (
+	MetadataScanEngine = "scan-engine" // スキャンに使用したエンジン
+	MetadataScanResult = "scan-result" // スキャン結果 (clean)
+	MetadataScanTime   = "scan-time"   // スキャン完了時刻 (RFC 3339)
+)
+
+// clamdChunkSize は、clamd の INSTREAM コマンドで一度に送信する最大バイト数です。
+const clamdChunkSize = 64 * 1024
+
+// ClamAVScanner は、書き込まれる内容を clamd (ClamAV デーモン) の INSTREAM コマンドでスキャンする Validator です。
+// 脅威が検出された場合は転送を中止し、書き込み先を確定しません。
+// スキャン結果は MetadataScan* のキーで書き込み先のメタデータに記録されます。
+//
+// clamd の StreamMaxLength (既定 25MB) を超える内容はスキャンできずエラーとなるため、
+// 用途に応じて clamd 側の設定を調整してください。
+type ClamAVScanner struct {
+	Network string        // "tcp" または "unix"
+	Address string        // "host:port" またはソケットのパス
+	Timeout time.Duration // clamd への接続・応答待ちのタイムアウト (0 の場合は無制限)
+}
+
+// NewClamAVScanner は、address で待ち受ける clamd を使用する ClamAVScanner を作成します。
+// address が "unix:" で始まる場合は Unix ドメインソケット、それ以外は "host:port" 形式の TCP として扱います。
+func NewClamAVScanner(address string) *ClamAVScanner {
+	if path, ok := strings.CutPrefix(address, "unix:"); ok {
+		return &ClamAVScanner{Network: "unix", Address: path}
+	}
+	return &ClamAVScanner{Network: "tcp", Address: address}
+}
+
+// ValidateStream は、r の内容を clamd へ送信してスキャンします。
+func (s *ClamAVScanner) ValidateStream(ctx context.Context, info TransferInfo, r io.Reader) error {
+	dialer := net.Dialer{Timeout: s.Timeout}
+	conn, err := dialer.DialContext(ctx, s.Network, s.Address)
+	if err != nil {
+		return fmt.Errorf(Message("clamd (%s) への接続に失敗しました: %w"), s.Address, err)
+	}
+	defer conn.Close()
+
+	// コンテキストのキャンセル時は接続を閉じて送受信を中断する
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if _, err := io.WriteString(conn, "zINSTREAM\x00"); err != nil {
+		return fmt.Errorf(Message("clamd へのコマンド送信に失敗しました: %w"), err)
+	}
+
+	buf := make([]byte, clamdChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return fmt.Errorf(Message("clamd へのデータ送信に失敗しました: %w"), err)
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return fmt.Errorf(Message("clamd へのデータ送信に失敗しました: %w"), err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+
+	// 長さ 0 のチャンクでストリームの終端を通知する
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return fmt.Errorf(Message("clamd へのデータ送信に失敗しました: %w"), err)
+	}
+	if s.Timeout > 0 {
+		conn.SetReadDeadline(time.Now().Add(s.Timeout))
+	}
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		return fmt.Errorf(Message("clamd からの応答の読み取りに失敗しました: %w"), err)
+	}
+
+	// 応答例: "stream: OK", "stream: Eicar-Signature FOUND", "INSTREAM size limit exceeded. ERROR"
+	reply = strings.TrimSpace(strings.TrimSuffix(reply, "\x00"))
+	result := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case result == "OK":
+		info.SetMetadata(MetadataScanEngine, "clamav")
+		info.SetMetadata(MetadataScanResult, "clean")
+		info.SetMetadata(MetadataScanTime, time.Now().UTC().Format(time.RFC3339))
+		return nil
+	case strings.HasSuffix(result, " FOUND"):
+		return fmt.Errorf("%w (%s): %s", ErrThreatDetected, strings.TrimSuffix(result, " FOUND"), info.URI)
+	default:
+		return fmt.Errorf(Message("clamd がエラーを返しました: %s"), reply)
+	}
+}
+
+// ValidateResult は何も検査しません。
+func (s *ClamAVScanner) ValidateResult(ctx context.Context, info TransferInfo) error {
+	return nil
+}
+
+// 型アサーションチェック
+var _ Validator = (*ClamAVScanner)(nil)
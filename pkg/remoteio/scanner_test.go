@@ -0,0 +1,121 @@
+package remoteio
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestNewClamAVScanner(t *testing.T) {
+	tests := []struct {
+		address     string
+		wantNetwork string
+		wantAddress string
+	}{
+		{address: "127.0.0.1:3310", wantNetwork: "tcp", wantAddress: "127.0.0.1:3310"},
+		{address: "clamd:3310", wantNetwork: "tcp", wantAddress: "clamd:3310"},
+		{address: "unix:/run/clamd.sock", wantNetwork: "unix", wantAddress: "/run/clamd.sock"},
+	}
+	for _, tt := range tests {
+		s := NewClamAVScanner(tt.address)
+		if s.Network != tt.wantNetwork || s.Address != tt.wantAddress {
+			t.Errorf("NewClamAVScanner(%q) = %s %s, want %s %s", tt.address, s.Network, s.Address, tt.wantNetwork, tt.wantAddress)
+		}
+	}
+}
+
+// fakeClamd は、INSTREAM コマンドで受信した内容を received へ送り、reply を応答する clamd のフェイクを起動し、アドレスを返します。
+func fakeClamd(t *testing.T, reply string, received chan<- []byte) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		if cmd, err := r.ReadString(0); err != nil || cmd != "zINSTREAM\x00" {
+			return
+		}
+		var data bytes.Buffer
+		for {
+			var size uint32
+			if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			if _, err := io.CopyN(&data, r, int64(size)); err != nil {
+				return
+			}
+		}
+		received <- data.Bytes()
+		io.WriteString(conn, reply+"\x00")
+	}()
+	return ln.Addr().String()
+}
+
+func TestClamAVScanner(t *testing.T) {
+	content := bytes.Repeat([]byte("x"), clamdChunkSize+1) // 複数のチャンクに分けて送信する
+	tests := []struct {
+		name       string
+		reply      string
+		wantErr    error // nil 以外の場合は errors.Is で判定する
+		wantFail   bool
+		wantResult string // 記録されるスキャン結果のメタデータ
+	}{
+		{name: "脅威なし", reply: "stream: OK", wantResult: "clean"},
+		{name: "脅威の検出", reply: "stream: Eicar-Signature FOUND", wantErr: ErrThreatDetected},
+		{name: "clamd のエラー", reply: "INSTREAM size limit exceeded. ERROR", wantFail: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := make(chan []byte, 1)
+			s := NewClamAVScanner(fakeClamd(t, tt.reply, received))
+			s.Timeout = 5 * time.Second
+			info := TransferInfo{URI: "gs://bucket/a.bin", metadata: &transferMetadata{values: map[string]string{}}}
+
+			err := s.ValidateStream(context.Background(), info, bytes.NewReader(content))
+			switch {
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Fatalf("ValidateStream() = %v, want %v", err, tt.wantErr)
+			case tt.wantFail && err == nil:
+				t.Fatal("ValidateStream() = nil, want error")
+			case tt.wantErr == nil && !tt.wantFail && err != nil:
+				t.Fatalf("ValidateStream() = %v", err)
+			}
+			if got := <-received; !bytes.Equal(got, content) {
+				t.Errorf("clamd が受信した内容 = %d バイト, want %d バイト", len(got), len(content))
+			}
+			if got := info.metadata.values[MetadataScanResult]; got != tt.wantResult {
+				t.Errorf("%s = %q, want %q", MetadataScanResult, got, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestClamAVScannerConnectionFailure(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close() // 待ち受けていないアドレス
+
+	err = NewClamAVScanner(addr).ValidateStream(context.Background(), TransferInfo{URI: "gs://bucket/a"}, bytes.NewReader([]byte("x")))
+	if err == nil || errors.Is(err, ErrThreatDetected) {
+		t.Fatalf("ValidateStream() = %v, want 接続のエラー", err)
+	}
+}
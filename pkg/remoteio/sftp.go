@@ -0,0 +1,477 @@
+package remoteio
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// defaultSFTPTimeout は、SFTPConfig.Timeout が指定されていない場合の接続タイムアウトです。
+const defaultSFTPTimeout = 30 * time.Second
+
+// =================================================================
+// 1. 設定とインターフェース定義
+// =================================================================
+
+// SFTPConfig は、sftp:// URI へ接続する際の鍵認証とホスト鍵検証の設定です。
+// ゼロ値の場合は、ssh-agent と ~/.ssh の既定の鍵で認証し、~/.ssh/known_hosts でホスト鍵を検証します。
+type SFTPConfig struct {
+	KeyFile               string        // 秘密鍵ファイルのパス (空の場合は ssh-agent と ~/.ssh/id_ed25519, id_ecdsa, id_rsa)
+	KeyPassphrase         string        // 秘密鍵のパスフレーズ (暗号化されている場合)
+	KnownHostsFile        string        // known_hosts ファイルのパス (空の場合は ~/.ssh/known_hosts)
+	InsecureIgnoreHostKey bool          // ホスト鍵を検証しない (テスト環境専用)
+	Timeout               time.Duration // 接続タイムアウト (0 の場合は 30 秒)
+}
+
+// WithSFTPConfig は、sftp:// URI の読み書きに使用する接続設定を指定します。
+// InputReader と OutputWriter の両方に適用されます。
+func WithSFTPConfig(cfg SFTPConfig) Option {
+	return func(c *config) {
+		c.sftp = &cfg
+	}
+}
+
+// SFTPOutputWriter は、SFTP サーバーにコンテンツを書き込むためのインターフェースです。
+type SFTPOutputWriter interface {
+	// WriteToSFTP は、address ("user@host:port") のサーバー上の path に io.Reader からコンテンツを書き込みます。
+	WriteToSFTP(ctx context.Context, address, path string, contentReader io.Reader) error
+}
+
+// =================================================================
+// 2. 読み込み (SFTPInputReader)
+// =================================================================
+
+// SFTPInputReader は、SFTP サーバー上のファイルの読み込みのみを処理する InputReader の具象実装です。
+// ローカルファイルや GCS と併せて扱う場合は、LocalGCSInputReader を使用してください。
+type SFTPInputReader struct {
+	cfg config
+}
+
+// NewSFTPInputReader は SFTPInputReader の新しいインスタンスを作成します。
+// 接続設定は WithSFTPConfig で指定します。
+func NewSFTPInputReader(opts ...Option) *SFTPInputReader {
+	return &SFTPInputReader{cfg: newConfig(opts)}
+}
+
+// Open は、sftp:// URI で指定されたファイルのストリームを開きます。
+func (r *SFTPInputReader) Open(ctx context.Context, uri string) (_ io.ReadCloser, err error) {
+	defer classifyError(&err)
+	if err := r.cfg.faults.beforeOp("Open", uri); err != nil {
+		return nil, err
+	}
+	rc, err := openSFTPFile(ctx, r.cfg.sftpConfig(), uri)
+	if err != nil {
+		return nil, err
+	}
+	return r.cfg.wrapReadCloser(rc), nil
+}
+
+// openSFTPFile は、SFTP URI のファイルを開き、接続と併せてクローズされる io.ReadCloser を返します。
+func openSFTPFile(ctx context.Context, cfg SFTPConfig, sftpURI string) (io.ReadCloser, error) {
+	address, filePath, err := ParseSFTPURI(sftpURI)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := dialSFTP(ctx, cfg, address)
+	if err != nil {
+		return nil, err
+	}
+	file, err := conn.Open(filePath)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf(Message("SFTPファイルの読み込みに失敗しました (URI: %s): %w"), sftpURI, err)
+	}
+	return &sftpReadCloser{File: file, conn: conn}, nil
+}
+
+// openSFTPRange は、SFTP サーバー上のファイルの offset から length バイト (負の場合は終端まで) を読み込みます。
+func openSFTPRange(ctx context.Context, cfg SFTPConfig, sftpURI string, offset, length int64) (io.ReadCloser, error) {
+	rc, err := openSFTPFile(ctx, cfg, sftpURI)
+	if err != nil {
+		return nil, err
+	}
+	f := rc.(*sftpReadCloser)
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, fmt.Errorf(Message("SFTPファイルのシークに失敗しました (URI: %s): %w"), sftpURI, err)
+	}
+	return limitReadCloser(f, length), nil
+}
+
+// statSFTPFile は、SFTP サーバー上のファイルの情報を返します。
+func statSFTPFile(ctx context.Context, cfg SFTPConfig, sftpURI string) (ObjectInfo, error) {
+	address, filePath, err := ParseSFTPURI(sftpURI)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	conn, err := dialSFTP(ctx, cfg, address)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	defer conn.Close()
+
+	info, err := conn.Stat(filePath)
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf(Message("SFTPファイルの情報の取得に失敗しました (URI: %s): %w"), sftpURI, err)
+	}
+	return ObjectInfo{
+		URI:      sftpURI,
+		Name:     info.Name(),
+		Size:     info.Size(),
+		Updated:  info.ModTime(),
+		IsPrefix: info.IsDir(),
+	}, nil
+}
+
+// listSFTPFiles は、SFTP サーバー上のディレクトリ配下のファイルを再帰的に一覧します。
+func listSFTPFiles(ctx context.Context, cfg SFTPConfig, sftpURI string) ([]ObjectInfo, error) {
+	address, root, err := ParseSFTPURI(sftpURI)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := dialSFTP(ctx, cfg, address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if root == "" {
+		root = "/"
+	}
+	base := strings.TrimSuffix(sftpURI, "/")
+	var objects []ObjectInfo
+	walker := conn.Walk(root)
+	for walker.Step() {
+		if err := walker.Err(); err != nil {
+			return nil, fmt.Errorf(Message("SFTPディレクトリの一覧取得に失敗しました (URI: %s): %w"), sftpURI, err)
+		}
+		info := walker.Stat()
+		rel := strings.TrimPrefix(strings.TrimPrefix(walker.Path(), root), "/")
+		if !info.Mode().IsRegular() || rel == "" {
+			// ディレクトリと、root 自体がファイルの場合は含めない
+			continue
+		}
+		objects = append(objects, ObjectInfo{
+			URI:     base + "/" + rel,
+			Name:    rel,
+			Size:    info.Size(),
+			Updated: info.ModTime(),
+		})
+	}
+	return objects, nil
+}
+
+// sftpReadCloser は、ファイルのクローズ時に SFTP 接続も閉じる io.ReadCloser です。
+type sftpReadCloser struct {
+	*sftp.File
+	conn *sftpConn
+}
+
+func (r *sftpReadCloser) Close() error {
+	return errors.Join(r.File.Close(), r.conn.Close())
+}
+
+// =================================================================
+// 3. 書き込み (UniversalIOWriter)
+// =================================================================
+
+// WriteToSFTP は SFTPOutputWriter インターフェースを実装します。
+// 一時ファイルへ書き込んだ後にリネームするため、中止された書き込みが path に残ることはありません。
+func (w *UniversalIOWriter) WriteToSFTP(ctx context.Context, address, filePath string, contentReader io.Reader) (err error) {
+	defer classifyError(&err)
+	targetURI := fmt.Sprintf("sftp://%s%s", address, filePath)
+
+	if address == "" {
+		return invalidURIError("SFTPへの書き込みに失敗しました: 接続先が空です")
+	}
+	if filePath == "" || filePath == "/" {
+		return invalidURIError("SFTPへの書き込みに失敗しました: ファイルパスが空です")
+	}
+
+	if err := w.cfg.faults.beforeOp("WriteToSFTP", targetURI); err != nil {
+		return err
+	}
+	contentReader = w.cfg.wrapWriteStream(contentReader)
+
+	w.cfg.log().Info(Message("SFTP書き込み処理開始"), slog.String("uri", targetURI))
+
+	conn, err := dialSFTP(ctx, w.cfg.sftpConfig(), address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if dir := path.Dir(filePath); dir != "." && dir != "/" {
+		if err := conn.MkdirAll(dir); err != nil {
+			return fmt.Errorf(Message("SFTPの出力ディレクトリ(%s)の作成に失敗しました: %w"), dir, err)
+		}
+	}
+
+	info := TransferInfo{URI: targetURI}
+	err = w.cfg.writeValidated(ctx, info, contentReader, func(ctx context.Context, r io.Reader, verdict func() error) error {
+		tmpPath, err := sftpTempPath(filePath)
+		if err != nil {
+			return err
+		}
+		file, err := conn.Create(tmpPath)
+		if err != nil {
+			return fmt.Errorf(Message("SFTPファイル(%s)の作成に失敗しました: %w"), tmpPath, err)
+		}
+
+		// 失敗・中止時は一時ファイルを残さない
+		abort := func(err error) error {
+			file.Close()
+			conn.Remove(tmpPath)
+			return err
+		}
+		if _, err := w.cfg.copyBuffer(file, r); err != nil {
+			w.cfg.log().Error(Message("SFTPへのコンテンツ書き込み中にエラーが発生"), slog.String("uri", targetURI), slog.String("error", err.Error()))
+			return abort(fmt.Errorf(Message("SFTPへのコンテンツ書き込み中にエラーが発生しました: %w"), err))
+		}
+		if err := verdict(); err != nil {
+			return abort(err)
+		}
+		if err := file.Close(); err != nil {
+			conn.Remove(tmpPath)
+			return fmt.Errorf(Message("SFTPファイルのクローズに失敗しました: %w"), err)
+		}
+		if w.cfg.noClobber {
+			// SFTP の名前変更は、変更先が存在する場合は失敗する
+			if err := conn.Rename(tmpPath, filePath); err != nil {
+				conn.Remove(tmpPath)
+				if _, statErr := conn.Stat(filePath); statErr == nil {
+					return destinationExists(targetURI)
+				}
+				return fmt.Errorf(Message("SFTPファイル(%s)の確定に失敗しました: %w"), filePath, err)
+			}
+			return nil
+		}
+		if err := conn.renameOver(tmpPath, filePath); err != nil {
+			conn.Remove(tmpPath)
+			return fmt.Errorf(Message("SFTPファイル(%s)の確定に失敗しました: %w"), filePath, err)
+		}
+		return nil
+	}, writeTarget{
+		remove: func(ctx context.Context) error {
+			return conn.Remove(filePath)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	w.cfg.log().Info(Message("SFTP書き込み処理完了"), slog.String("uri", targetURI))
+	return nil
+}
+
+// deleteSFTPFile は、SFTP URI で指定されたファイルを削除します。
+func deleteSFTPFile(ctx context.Context, cfg SFTPConfig, sftpURI string) error {
+	address, filePath, err := ParseSFTPURI(sftpURI)
+	if err != nil {
+		return err
+	}
+	conn, err := dialSFTP(ctx, cfg, address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.Remove(filePath); err != nil {
+		return fmt.Errorf(Message("SFTPファイルの削除に失敗しました (URI: %s): %w"), sftpURI, err)
+	}
+	return nil
+}
+
+// =================================================================
+// 4. 接続
+// =================================================================
+
+// sftpConn は、SSH 接続とその上の SFTP セッションをまとめて保持します。
+type sftpConn struct {
+	*sftp.Client
+	ssh *ssh.Client
+}
+
+func (c *sftpConn) Close() error {
+	return errors.Join(c.Client.Close(), c.ssh.Close())
+}
+
+// renameOver は、oldPath を newPath にリネームし、既存のファイルを置き換えます。
+func (c *sftpConn) renameOver(oldPath, newPath string) error {
+	// posix-rename 拡張に対応したサーバーでは原子的に置き換える
+	if _, ok := c.HasExtension("posix-rename@openssh.com"); ok {
+		return c.PosixRename(oldPath, newPath)
+	}
+	if err := c.Remove(newPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return c.Rename(oldPath, newPath)
+}
+
+// dialSFTP は、address ("user@host:port") の SFTP サーバーへ接続します。
+func dialSFTP(ctx context.Context, cfg SFTPConfig, address string) (*sftpConn, error) {
+	user, hostPort, err := splitSFTPAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	clientConfig, err := cfg.clientConfig(user)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := net.Dialer{Timeout: clientConfig.Timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", hostPort)
+	if err != nil {
+		return nil, fmt.Errorf(Message("SFTPサーバー(%s)への接続に失敗しました: %w"), hostPort, err)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(netConn, hostPort, clientConfig)
+	if err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf(Message("SFTPサーバー(%s)とのSSHハンドシェイクに失敗しました: %w"), hostPort, err)
+	}
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		sshClient.Close()
+		return nil, fmt.Errorf(Message("SFTPセッションの開始に失敗しました (%s): %w"), hostPort, err)
+	}
+	return &sftpConn{Client: client, ssh: sshClient}, nil
+}
+
+// clientConfig は、鍵認証とホスト鍵検証を設定した ssh.ClientConfig を作成します。
+func (c SFTPConfig) clientConfig(user string) (*ssh.ClientConfig, error) {
+	home, _ := os.UserHomeDir()
+
+	var auth []ssh.AuthMethod
+	if c.KeyFile != "" {
+		signer, err := loadSFTPKey(c.KeyFile, c.KeyPassphrase)
+		if err != nil {
+			return nil, err
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	} else {
+		// ssh-agent が利用可能であれば優先する
+		if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+			if agentConn, err := net.Dial("unix", sock); err == nil {
+				auth = append(auth, ssh.PublicKeysCallback(agent.NewClient(agentConn).Signers))
+			}
+		}
+		var signers []ssh.Signer
+		for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+			signer, err := loadSFTPKey(filepath.Join(home, ".ssh", name), c.KeyPassphrase)
+			if err == nil {
+				signers = append(signers, signer)
+			}
+		}
+		if len(signers) > 0 {
+			auth = append(auth, ssh.PublicKeys(signers...))
+		}
+	}
+	if len(auth) == 0 {
+		return nil, errors.New(Message("SFTPの認証に使用できる鍵が見つかりません (鍵ファイルを指定するか、ssh-agent を起動してください)"))
+	}
+
+	var hostKeyCallback ssh.HostKeyCallback
+	if c.InsecureIgnoreHostKey {
+		hostKeyCallback = ssh.InsecureIgnoreHostKey()
+	} else {
+		knownHostsFile := c.KnownHostsFile
+		if knownHostsFile == "" {
+			knownHostsFile = filepath.Join(home, ".ssh", "known_hosts")
+		}
+		callback, err := knownhosts.New(knownHostsFile)
+		if err != nil {
+			return nil, fmt.Errorf(Message("known_hosts (%s) の読み込みに失敗しました: %w"), knownHostsFile, err)
+		}
+		hostKeyCallback = callback
+	}
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultSFTPTimeout
+	}
+	return &ssh.ClientConfig{
+		User:            user,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         timeout,
+	}, nil
+}
+
+// loadSFTPKey は、秘密鍵ファイルを読み込みます。
+func loadSFTPKey(keyFile, passphrase string) (ssh.Signer, error) {
+	pem, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf(Message("秘密鍵(%s)の読み込みに失敗しました: %w"), keyFile, err)
+	}
+	var signer ssh.Signer
+	if passphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(pem, []byte(passphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey(pem)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(Message("秘密鍵(%s)の解析に失敗しました: %w"), keyFile, err)
+	}
+	return signer, nil
+}
+
+// sftpConfig は、設定された SFTPConfig (未設定の場合はゼロ値) を返します。
+func (c *config) sftpConfig() SFTPConfig {
+	if c.sftp == nil {
+		return SFTPConfig{}
+	}
+	return *c.sftp
+}
+
+// =================================================================
+// 5. 内部ヘルパー
+// =================================================================
+
+// splitSFTPAddress は、"user@host:port" をユーザー名と "host:port" に分割します。
+func splitSFTPAddress(address string) (string, string, error) {
+	u, err := url.Parse("sftp://" + address)
+	if err != nil || u.Host == "" {
+		return "", "", invalidURIError("無効なSFTPの接続先です: %s", address)
+	}
+	return u.User.Username(), u.Host, nil
+}
+
+// sftpTempPath は、path と同じディレクトリに作成する一時ファイルのパスを返します。
+func sftpTempPath(filePath string) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return path.Join(path.Dir(filePath), "."+path.Base(filePath)+".remoteio-"+hex.EncodeToString(b)), nil
+}
+
+// currentUsername は、URI でユーザーが省略された場合に使用するローカルのユーザー名を返します。
+func currentUsername() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// 型アサーションチェック
+var _ InputReader = (*SFTPInputReader)(nil)
+var _ SFTPOutputWriter = (*UniversalIOWriter)(nil)
@@ -0,0 +1,82 @@
+package remoteio
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+)
+
+// DefaultSignedURLExpiry は、署名付きURLの有効期間の既定値です。
+const DefaultSignedURLExpiry = 15 * time.Minute
+
+// maxSignedURLExpiry は、V4 署名付きURLに指定できる有効期間の上限 (7日) です。
+const maxSignedURLExpiry = 7 * 24 * time.Hour
+
+// SignOption は、SignedURL の動作を設定するための関数です。
+type SignOption func(*signOptions)
+
+type signOptions struct {
+	method      string
+	expiry      time.Duration
+	contentType string
+}
+
+// WithSignMethod は、署名付きURLで許可する HTTP メソッド (GET、PUT など) を設定します。既定値は GET です。
+func WithSignMethod(method string) SignOption {
+	return func(o *signOptions) {
+		o.method = strings.ToUpper(method)
+	}
+}
+
+// WithSignExpiry は、署名付きURLの有効期間を設定します。既定値は DefaultSignedURLExpiry で、最大は7日です。
+func WithSignExpiry(d time.Duration) SignOption {
+	return func(o *signOptions) {
+		o.expiry = d
+	}
+}
+
+// WithSignContentType は、署名付きURLへのリクエストで送信する必要がある Content-Type を設定します。
+// PUT でのアップロードを許可する場合に、アップロードされる内容の Content-Type を固定するために使用します。
+func WithSignContentType(contentType string) SignOption {
+	return func(o *signOptions) {
+		o.contentType = contentType
+	}
+}
+
+// SignedURL は、gcsURI のオブジェクトにアクセスするための V4 署名付きURLを生成します。
+// 署名には client の認証情報を使用します。サービスアカウントの鍵ファイル以外 (メタデータサーバーなど) の場合は、
+// IAM Credentials API の signBlob で署名するため、サービスアカウントに iam.serviceAccounts.signBlob の権限が必要です。
+func SignedURL(client *storage.Client, gcsURI string, opts ...SignOption) (string, error) {
+	if client == nil {
+		return "", fmt.Errorf(Message("GCSクライアントが初期化されていないため、署名付きURLを生成できません (URI: %s)"), gcsURI)
+	}
+	bucketName, objectPath, err := ParseGCSURI(gcsURI)
+	if err != nil {
+		return "", fmt.Errorf(Message("GCS URIのパース失敗: %w"), err)
+	}
+	if objectPath == "" {
+		return "", invalidURIError("無効なGCS URI形式です: %s (オブジェクト名が空です)", gcsURI)
+	}
+
+	o := signOptions{method: http.MethodGet, expiry: DefaultSignedURLExpiry}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.expiry <= 0 || o.expiry > maxSignedURLExpiry {
+		return "", fmt.Errorf(Message("署名付きURLの有効期間は0より長く7日以内で指定してください: %s"), o.expiry)
+	}
+
+	signedURL, err := client.Bucket(bucketName).SignedURL(objectPath, &storage.SignedURLOptions{
+		Scheme:      storage.SigningSchemeV4,
+		Method:      o.method,
+		Expires:     time.Now().Add(o.expiry),
+		ContentType: o.contentType,
+	})
+	if err != nil {
+		return "", fmt.Errorf(Message("署名付きURLの生成に失敗しました (URI: %s): %w"), gcsURI, err)
+	}
+	return signedURL, nil
+}
@@ -0,0 +1,95 @@
+package remoteio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// slicetestObject は、"slicetest://" のスキームで読み込むオブジェクトです。
+// Stat は stated の情報を返し、範囲読み込みは data から読み込むため、読み込みの途中での上書きを再現できます。
+var slicetestObject struct {
+	sync.Mutex
+	stated []byte
+	data   []byte
+}
+
+func init() {
+	registerHandler("slicetest", schemeHandler{
+		stat: func(ctx context.Context, r *LocalGCSInputReader, uri string) (ObjectInfo, error) {
+			slicetestObject.Lock()
+			defer slicetestObject.Unlock()
+			sum := crc32.Checksum(slicetestObject.stated, castagnoliTable)
+			return ObjectInfo{URI: uri, Size: int64(len(slicetestObject.stated)), CRC32C: &sum}, nil
+		},
+		openRange: func(ctx context.Context, r *LocalGCSInputReader, uri string, offset, length int64) (io.ReadCloser, error) {
+			slicetestObject.Lock()
+			defer slicetestObject.Unlock()
+			return io.NopCloser(bytes.NewReader(slicetestObject.data[offset : offset+length])), nil
+		},
+	})
+}
+
+func TestCRC32Combine(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 1000)
+	for _, split := range []int{0, 1, 7, 4096, len(data) - 1, len(data)} {
+		a, b := data[:split], data[split:]
+		got := crc32Combine(crc32.Checksum(a, castagnoliTable), crc32.Checksum(b, castagnoliTable), int64(len(b)))
+		if want := crc32.Checksum(data, castagnoliTable); got != want {
+			t.Errorf("crc32Combine(分割位置 %d) = %08x, want %08x", split, got, want)
+		}
+	}
+}
+
+func TestSlicedReadVerifiesChecksum(t *testing.T) {
+	original := bytes.Repeat([]byte("a"), 10*1024)
+	overwritten := bytes.Repeat([]byte("b"), len(original)) // 同じサイズで上書きされた内容
+	opts := []SliceOption{WithSliceSize(1024), WithSliceParallelism(3)}
+
+	tests := []struct {
+		name    string
+		data    []byte // 範囲読み込みで返す内容
+		wantErr error
+	}{
+		{name: "変更なし", data: original},
+		{name: "読み込み中の上書き", data: overwritten, wantErr: ErrChecksumMismatch},
+	}
+	for _, tt := range tests {
+		slicetestObject.Lock()
+		slicetestObject.stated, slicetestObject.data = original, tt.data
+		slicetestObject.Unlock()
+		reader := NewLocalGCSInputReader(nil)
+
+		t.Run(tt.name+"/DownloadSliced", func(t *testing.T) {
+			file, err := os.Create(filepath.Join(t.TempDir(), "out.bin"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			_, err = reader.DownloadSliced(context.Background(), "slicetest://bucket/a.bin", file, opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DownloadSliced() = %v, want %v", err, tt.wantErr)
+			}
+		})
+		t.Run(tt.name+"/OpenSliced", func(t *testing.T) {
+			rc, err := reader.OpenSliced(context.Background(), "slicetest://bucket/a.bin", opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rc.Close()
+			got, err := io.ReadAll(rc)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("OpenSliced() の読み込み = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !bytes.Equal(got, original) {
+				t.Errorf("読み込んだ内容 = %d バイト, want %d バイト", len(got), len(original))
+			}
+		})
+	}
+}
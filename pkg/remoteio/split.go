@@ -0,0 +1,91 @@
+package remoteio
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// SplitPart は、SplitWrite で書き込んだ1つのパートです。
+type SplitPart struct {
+	URI  string // パートのURI (destURI.part0001 など)
+	Size int64  // パートのサイズ (バイト数)
+}
+
+// SplitPartURI は、destURI を分割した n 番目 (1 始まり) のパートのURI (destURI.part0001 など) を返します。
+// 9999 を超える番号は桁数を増やします (destURI.part10000)。
+func SplitPartURI(destURI string, n int) string {
+	return fmt.Sprintf("%s.part%04d", destURI, n)
+}
+
+// SplitWrite は、r の内容を partSize バイトごとに、連番のパート (destURI.part0001、destURI.part0002、...) へ順に書き込みます。
+// 1回の書き込みに大きさの上限がある書き込み先へ、大きなストリームを分けて保存するために使用します。
+// 各パートは r から読み込みながら書き込むため、パート全体をメモリに保持しません。r が空の場合は空のパートを1つ書き込みます。
+// opts はすべてのパートに適用します。
+//
+// 書き込んだパートを順に返します。失敗した場合は、失敗するまでに書き込んだパートとエラーを返します (書き込んだパートは削除しません)。
+// 以前の書き込みで残っている後続のパート (より多くのパートに分割していた場合) は削除しないため、SplitParts で連結する前に削除してください。
+func SplitWrite(ctx context.Context, writer OutputWriter, destURI string, r io.Reader, partSize int64, opts ...WriteOption) ([]SplitPart, error) {
+	if partSize <= 0 {
+		return nil, fmt.Errorf(Message("パートのサイズには正の値を指定してください: %d"), partSize)
+	}
+	br := bufio.NewReader(r)
+	var parts []SplitPart
+	for n := 1; ; n++ {
+		if err := ctx.Err(); err != nil {
+			return parts, err
+		}
+		// 2つ目以降のパートは、続きの内容がある場合にのみ書き込む
+		if n > 1 {
+			if _, err := br.Peek(1); errors.Is(err, io.EOF) {
+				return parts, nil
+			} else if err != nil {
+				return parts, err
+			}
+		}
+		uri := SplitPartURI(destURI, n)
+		cr := &countingReader{r: io.LimitReader(br, partSize)}
+		if err := writer.Write(ctx, uri, cr, opts...); err != nil {
+			return parts, fmt.Errorf(Message("パートの書き込みに失敗しました (%s): %w"), uri, err)
+		}
+		parts = append(parts, SplitPart{URI: uri, Size: cr.n})
+		if cr.n < partSize {
+			return parts, nil
+		}
+	}
+}
+
+// SplitParts は、SplitWrite で destURI を分割して書き込んだパートのURIを、番号の順に返します。
+// destURI.part0001 から、存在しない番号が見つかるまでのパートを返します。1つ目のパートが存在しない場合は ErrNotFound を返します。
+func SplitParts(ctx context.Context, stater Stater, destURI string) ([]string, error) {
+	var uris []string
+	for n := 1; ; n++ {
+		uri := SplitPartURI(destURI, n)
+		ok, err := stater.Exists(ctx, uri)
+		if err != nil {
+			return nil, fmt.Errorf(Message("パートの確認に失敗しました (%s): %w"), uri, err)
+		}
+		if !ok {
+			break
+		}
+		uris = append(uris, uri)
+	}
+	if len(uris) == 0 {
+		return nil, fmt.Errorf(Message("分割されたパートが見つかりません (%s): %w"), SplitPartURI(destURI, 1), ErrNotFound)
+	}
+	return uris, nil
+}
+
+// countingReader は、読み込んだバイト数を数える io.Reader です。
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,86 @@
+package remoteio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// Stater は、ファイルまたはオブジェクトの情報を取得するためのインターフェースです。
+type Stater interface {
+	// Stat は、uri (GCS URI、S3 URI、Azure URI、SFTP URI、またはローカルファイルパス) の情報を返します。
+	Stat(ctx context.Context, uri string) (ObjectInfo, error)
+	// Exists は、uri のファイルまたはオブジェクトが存在するかどうかを返します。
+	// 存在しない場合は false と nil を返し、確認できなかった場合 (権限不足など) はエラーを返します。
+	Exists(ctx context.Context, uri string) (bool, error)
+}
+
+// Stat は Stater インターフェースを実装します。
+// 取得できる項目はバックエンドによって異なり、ローカルファイルと SFTP ではサイズ、更新日時とディレクトリかどうかのみが設定されます。
+func (r *LocalGCSInputReader) Stat(ctx context.Context, uri string) (_ ObjectInfo, err error) {
+	defer classifyError(&err)
+	if err := r.cfg.faults.beforeOp("Stat", uri); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	h, ok, err := lookupScheme(uri)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	switch {
+	case !ok:
+		info, err := os.Stat(uri)
+		if err != nil {
+			return ObjectInfo{}, fmt.Errorf(Message("ローカルファイルの情報の取得に失敗しました: %w"), err)
+		}
+		return ObjectInfo{
+			URI:      uri,
+			Name:     info.Name(),
+			Size:     info.Size(),
+			Updated:  info.ModTime(),
+			IsPrefix: info.IsDir(),
+		}, nil
+	case h.stat != nil:
+		ctx, cancel := r.cfg.opContext(ctx)
+		defer cancel()
+		return h.stat(ctx, r, uri)
+	default:
+		return ObjectInfo{}, fmt.Errorf(Message("スキーム %s:// は情報の取得をサポートしていません: %s"), SchemeOf(uri), uri)
+	}
+}
+
+// Exists は Stater インターフェースを実装します。
+// ローカルパスと SFTP ではディレクトリも存在するとみなします。GCS などのプレフィックス ("ディレクトリ") は、
+// 同名のオブジェクトがない限り存在しないとみなします。
+func (r *LocalGCSInputReader) Exists(ctx context.Context, uri string) (_ bool, err error) {
+	defer classifyError(&err)
+	_, err = r.Stat(ctx, uri)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, ErrNotFound):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// isNotExist は、err がファイルまたはオブジェクトが存在しないことを示すかどうかを、バックエンドごとのエラーから判定します。
+func isNotExist(err error) bool {
+	var s3NotFound *types.NotFound
+	var s3NoSuchKey *types.NoSuchKey
+	return errors.Is(err, fs.ErrNotExist) ||
+		errors.Is(err, storage.ErrObjectNotExist) ||
+		errors.As(err, &s3NotFound) ||
+		errors.As(err, &s3NoSuchKey) ||
+		bloberror.HasCode(err, bloberror.BlobNotFound)
+}
+
+// 型アサーションチェック
+var _ Stater = (*LocalGCSInputReader)(nil)
@@ -0,0 +1,75 @@
+package remoteio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// StreamOutputWriter は、書き込み先を io.WriteCloser として開くためのインターフェースです。
+// エンコーダーや圧縮器のように、内容を少しずつ書き込むプロデューサーから直接利用できます。
+type StreamOutputWriter interface {
+	// OpenWrite は、destURI への書き込みを開始し、内容を書き込むための io.WriteCloser を返します。
+	// 書き込み先は Close が成功した時点で確定されます。
+	OpenWrite(ctx context.Context, destURI string, opts ...WriteOption) (io.WriteCloser, error)
+}
+
+// OpenWrite は StreamOutputWriter インターフェースを実装します。
+// 書き込んだ内容はパイプ経由で Write へストリーミングされるため、書き込み先の種類やバリデータ、故障注入の扱いは Write と同じです。
+//
+// Close は書き込み先が確定する (GCS ではアップロードが完了する) まで待ち、その結果を返します。
+// 返される io.WriteCloser は CloseWithError(err error) error も実装しており、
+// プロデューサーが途中で失敗した場合に呼び出すと、書き込み先を確定せずに中止します。ctx のキャンセルでも中止されます。
+func (w *UniversalIOWriter) OpenWrite(ctx context.Context, destURI string, opts ...WriteOption) (_ io.WriteCloser, err error) {
+	defer classifyError(&err)
+	// 書き込めないURIは、書き込みを始める前にエラーにする
+	h, ok, err := lookupScheme(destURI)
+	if err != nil {
+		return nil, err
+	}
+	if ok && h.write == nil {
+		return nil, fmt.Errorf(Message("スキーム %s:// は書き込みをサポートしていません: %s"), SchemeOf(destURI), destURI)
+	}
+
+	pr, pw := io.Pipe()
+	sw := &streamWriter{pw: pw, done: make(chan error, 1)}
+	go func() {
+		err := w.Write(ctx, destURI, pr, opts...)
+		// 書き込みが途中で終了した場合は、以降の Write がエラーを返すようにする
+		pr.CloseWithError(err)
+		sw.done <- err
+	}()
+	return sw, nil
+}
+
+// streamWriter は、OpenWrite が返す io.WriteCloser です。
+type streamWriter struct {
+	pw   *io.PipeWriter
+	done chan error
+	once sync.Once
+	err  error
+}
+
+// Write は、p を書き込み先へストリーミングします。書き込み先でエラーが発生した場合はそのエラーを返します。
+func (s *streamWriter) Write(p []byte) (int, error) {
+	return s.pw.Write(p)
+}
+
+// Close は、書き込みを終了し、書き込み先が確定されるまで待ちます。
+func (s *streamWriter) Close() error {
+	return s.CloseWithError(nil)
+}
+
+// CloseWithError は、cause が nil でない場合に書き込みを中止し、書き込み先を確定させずに終了します。
+// cause が nil の場合は Close と同じです。2回目以降の呼び出しは最初の結果を返します。
+func (s *streamWriter) CloseWithError(cause error) error {
+	s.once.Do(func() {
+		s.pw.CloseWithError(cause)
+		s.err = <-s.done
+	})
+	return s.err
+}
+
+// 型アサーションチェック
+var _ StreamOutputWriter = (*UniversalIOWriter)(nil)
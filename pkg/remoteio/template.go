@@ -0,0 +1,67 @@
+package remoteio
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ExpandURITemplate は、uri に含まれるプレースホルダー ({{ ... }}) を展開した URI を返します。
+// 定期実行するジョブで、固定のコマンドラインから日付ごとのパーティションなどを指定するために使用します。
+// プレースホルダーを含まない uri はそのまま返します。
+//
+// 使用できる関数は次のとおりです (構文は text/template です)。
+//
+//   - {{date "2006/01/02"}}: now を Go の時刻のレイアウトで書式化します。2つ目の引数に "-24h" などの
+//     time.ParseDuration の形式の期間を指定すると、now からずらした時刻を書式化します (前日のログなど)。
+//   - {{utcdate "2006/01/02"}}: date と同じですが、UTC で書式化します。
+//   - {{env "HOSTNAME"}}: 環境変数の値です。設定されていない (空の) 環境変数はエラーになります。
+//
+// 書式が誤っている場合や、設定されていない環境変数を参照した場合は、ErrInvalidURI を含むエラーを返します。
+func ExpandURITemplate(uri string, now time.Time) (string, error) {
+	if !strings.Contains(uri, "{{") {
+		return uri, nil
+	}
+	shift := func(offset []string) (time.Time, error) {
+		switch len(offset) {
+		case 0:
+			return now, nil
+		case 1:
+			d, err := time.ParseDuration(offset[0])
+			if err != nil {
+				return time.Time{}, err
+			}
+			return now.Add(d), nil
+		default:
+			return time.Time{}, fmt.Errorf(Message("ずらす期間は1つだけ指定してください: %q"), offset)
+		}
+	}
+	funcs := template.FuncMap{
+		"date": func(layout string, offset ...string) (string, error) {
+			t, err := shift(offset)
+			return t.Format(layout), err
+		},
+		"utcdate": func(layout string, offset ...string) (string, error) {
+			t, err := shift(offset)
+			return t.UTC().Format(layout), err
+		},
+		"env": func(name string) (string, error) {
+			v := os.Getenv(name)
+			if v == "" {
+				return "", fmt.Errorf(Message("環境変数 %s が設定されていません"), name)
+			}
+			return v, nil
+		},
+	}
+	tmpl, err := template.New("uri").Funcs(funcs).Option("missingkey=error").Parse(uri)
+	if err != nil {
+		return "", invalidURIError("URI のプレースホルダーの解析に失敗しました (%s): %w", uri, err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, nil); err != nil {
+		return "", invalidURIError("URI のプレースホルダーの展開に失敗しました (%s): %w", uri, err)
+	}
+	return b.String(), nil
+}
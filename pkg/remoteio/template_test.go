@@ -0,0 +1,49 @@
+package remoteio
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExpandURITemplate(t *testing.T) {
+	t.Setenv("REMOTEIO_TEST_HOST", "web-1")
+	t.Setenv("REMOTEIO_TEST_EMPTY", "")
+	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2024, 3, 1, 8, 30, 0, 0, jst) // UTC では 2024-02-29 23:30
+
+	tests := []struct {
+		name    string
+		uri     string
+		want    string
+		wantErr bool
+	}{
+		{name: "プレースホルダーなし", uri: "gs://bucket/logs/app.log", want: "gs://bucket/logs/app.log"},
+		{name: "日付", uri: "gs://bucket/logs/{{date \"2006/01/02\"}}/app.log", want: "gs://bucket/logs/2024/03/01/app.log"},
+		{name: "前日の日付", uri: "gs://bucket/logs/{{date \"2006-01-02\" \"-24h\"}}/", want: "gs://bucket/logs/2024-02-29/"},
+		{name: "UTC の日付", uri: "gs://bucket/{{utcdate \"2006/01/02/15\"}}/", want: "gs://bucket/2024/02/29/23/"},
+		{name: "環境変数", uri: "gs://bucket/{{env \"REMOTEIO_TEST_HOST\"}}/app.log", want: "gs://bucket/web-1/app.log"},
+		{name: "空の環境変数", uri: "gs://bucket/{{env \"REMOTEIO_TEST_EMPTY\"}}/", wantErr: true},
+		{name: "不正な期間", uri: "gs://bucket/{{date \"2006\" \"yesterday\"}}/", wantErr: true},
+		{name: "複数の期間", uri: "gs://bucket/{{date \"2006\" \"-1h\" \"-2h\"}}/", wantErr: true},
+		{name: "閉じられていないプレースホルダー", uri: "gs://bucket/{{date \"2006\"/", wantErr: true},
+		{name: "未定義の関数", uri: "gs://bucket/{{hostname}}/", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandURITemplate(tt.uri, now)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidURI) {
+					t.Fatalf("ExpandURITemplate(%q) = %q, %v, want %v", tt.uri, got, err, ErrInvalidURI)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExpandURITemplate(%q) = %v", tt.uri, err)
+			}
+			if got != tt.want {
+				t.Errorf("ExpandURITemplate(%q) = %q, want %q", tt.uri, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package remoteio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// WithOpTimeout は、1回の操作にかける時間の上限を d に設定します。0 以下の場合は制限しません (既定)。
+//
+// Stat、Delete、CopyObject、Compose、Move のように内容をストリーミングしない操作は、開始から d を過ぎると中断します。
+// Open と OpenRange で開いたストリームの読み込みと、OutputWriter の書き込みは、大きなファイルの転送を途中で打ち切らないよう、
+// d の間データが転送されなかった場合 (停止しているとみなせる場合) に中断します。
+// GCS へのアップロードは WithChunkSize のチャンクごとに送信され、その間は内容を読み込まないため、d はチャンクの送信にかかる時間より長くしてください。
+// いずれの場合も、返されるエラーは context.DeadlineExceeded を含みます。
+// InputReader と OutputWriter の両方に適用されます。
+func WithOpTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.opTimeout = d
+	}
+}
+
+// opContext は、WithOpTimeout で指定された時間で打ち切るコンテキストを返します。
+func (c *config) opContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.opTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.opTimeout)
+}
+
+// openWatched は、open で開いたストリームを、WithOpTimeout で指定された時間データが読み込まれなかった場合に中断します。
+func (c *config) openWatched(ctx context.Context, open func(ctx context.Context) (io.ReadCloser, error)) (io.ReadCloser, error) {
+	if c.opTimeout <= 0 {
+		return open(ctx)
+	}
+	watch := newIdleWatch(ctx, c.opTimeout)
+	rc, err := open(watch.ctx)
+	if err != nil {
+		watch.stop()
+		return nil, watch.wrap(err)
+	}
+	return &idleReadCloser{Reader: watch.reader(rc), rc: rc, watch: watch}, nil
+}
+
+// idleWatch は、一定時間データが転送されなかった場合にコンテキストをキャンセルする監視です。
+type idleWatch struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	timer  *time.Timer
+	d      time.Duration
+	cause  error
+}
+
+// newIdleWatch は、ctx から派生したコンテキストを d の間データが転送されなかった場合にキャンセルする監視を開始します。
+func newIdleWatch(ctx context.Context, d time.Duration) *idleWatch {
+	w := &idleWatch{d: d, cause: fmt.Errorf(Message("%w: %s の間データが転送されなかったため中断しました"), context.DeadlineExceeded, d)}
+	w.ctx, w.cancel = context.WithCancelCause(ctx)
+	w.timer = time.AfterFunc(d, func() { w.cancel(w.cause) })
+	return w
+}
+
+// touch は、データが転送されたことを記録し、監視の期限を延長します。
+func (w *idleWatch) touch() {
+	w.timer.Reset(w.d)
+}
+
+// stop は、監視を終了します。
+func (w *idleWatch) stop() {
+	w.timer.Stop()
+	w.cancel(nil)
+}
+
+// wrap は、監視によって中断された場合に、err に中断の理由を加えます。
+// 読み込み側と書き込み側の両方で監視している場合などに、理由を重ねて加えないよう、既にタイムアウトを示すエラーはそのまま返します。
+func (w *idleWatch) wrap(err error) error {
+	if err == nil || context.Cause(w.ctx) != w.cause || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", w.cause, err)
+}
+
+// reader は、r からの読み込みごとに監視の期限を延長するリーダーを返します。
+func (w *idleWatch) reader(r io.Reader) io.Reader {
+	return &idleReader{r: r, watch: w}
+}
+
+// idleReader は、読み込みごとに idleWatch の期限を延長する io.Reader です。
+type idleReader struct {
+	r     io.Reader
+	watch *idleWatch
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.watch.touch()
+	}
+	if err != nil && err != io.EOF {
+		err = r.watch.wrap(err)
+	}
+	return n, err
+}
+
+// idleReadCloser は、openWatched が返すストリームです。Close で監視を終了します。
+type idleReadCloser struct {
+	io.Reader
+	rc    io.ReadCloser
+	watch *idleWatch
+}
+
+func (r *idleReadCloser) Close() error {
+	err := r.rc.Close()
+	r.watch.stop()
+	return err
+}
@@ -0,0 +1,133 @@
+package remoteio
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName は、remoteio が作成するスパンの計装ライブラリ名です。
+const tracerName = "github.com/shouni/go-remote-io/pkg/remoteio"
+
+// スパンに設定する属性のキー
+const (
+	attrURI        = attribute.Key("remoteio.uri")
+	attrBucket     = attribute.Key("remoteio.bucket") // GCS のバケット、S3 のバケット、Azure のコンテナなど (URI のホスト部分)
+	attrObject     = attribute.Key("remoteio.object") // バケット内のオブジェクトのパス
+	attrBytes      = attribute.Key("remoteio.bytes")  // 読み込んだ、または書き込んだバイト数
+	attrDurationMs = attribute.Key("remoteio.duration_ms")
+)
+
+// WithTracerProvider は、Open、WriteToGCS と WriteToLocal の処理を tp の OpenTelemetry のスパンとして記録します。
+// スパンには URI、バケット、オブジェクトのパス、転送したバイト数と所要時間が属性として設定されます。
+// Open のスパンは、返されたストリームを閉じるまでの読み込みを含みます。
+// 省略した場合はスパンを記録しません。グローバルの TracerProvider を使用する場合は otel.GetTracerProvider() を指定します。
+// InputReader と OutputWriter の両方に適用されます。
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// opSpan は、1回の読み込みまたは書き込みのスパンです。nil の場合 (トレースしない場合) のメソッドは何もしません。
+type opSpan struct {
+	span  trace.Span
+	start time.Time
+	bytes atomic.Int64
+	once  sync.Once
+}
+
+// startSpan は、uri に対する操作 name のスパンを開始します。WithTracerProvider が指定されていない場合は ctx と nil を返します。
+func (c *config) startSpan(ctx context.Context, name, uri string) (context.Context, *opSpan) {
+	if c.tracerProvider == nil {
+		return ctx, nil
+	}
+	attrs := []attribute.KeyValue{attrURI.String(uri)}
+	if _, rest, ok := strings.Cut(uri, "://"); ok && SchemeOf(uri) != "" {
+		bucket, object, _ := strings.Cut(rest, "/")
+		attrs = append(attrs, attrBucket.String(bucket), attrObject.String(object))
+	}
+	ctx, span := c.tracerProvider.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+	return ctx, &opSpan{span: span, start: time.Now()}
+}
+
+// countReader は、r から読み込まれたバイト数をスパンに加算するリーダーを返します。
+func (s *opSpan) countReader(r io.Reader) io.Reader {
+	if s == nil {
+		return r
+	}
+	return &spanReader{r: r, s: s}
+}
+
+// end は、転送したバイト数と所要時間を設定してスパンを終了します。err が nil でない場合はエラーとして記録します。
+// 2回目以降の呼び出しは何もしません。
+func (s *opSpan) end(err error) {
+	if s == nil {
+		return
+	}
+	s.once.Do(func() {
+		s.span.SetAttributes(
+			attrBytes.Int64(s.bytes.Load()),
+			attrDurationMs.Int64(time.Since(s.start).Milliseconds()),
+		)
+		if err != nil {
+			s.span.RecordError(err)
+			s.span.SetStatus(codes.Error, err.Error())
+		}
+		s.span.End()
+	})
+}
+
+// spanReader は、読み込んだバイト数をスパンに加算する io.Reader です。
+type spanReader struct {
+	r io.Reader
+	s *opSpan
+}
+
+func (r *spanReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.s.bytes.Add(int64(n))
+	return n, err
+}
+
+// tracedReadCloser は、閉じた時点でスパンを終了する io.ReadCloser です。
+// 読み込み中に EOF 以外のエラーが発生した場合は、そのエラーをスパンに記録します。
+type tracedReadCloser struct {
+	rc   io.ReadCloser
+	s    *opSpan
+	rerr error
+}
+
+// traceReadCloser は、rc の読み込みを s に記録するストリームを返します。s が nil の場合は rc をそのまま返します。
+func (s *opSpan) traceReadCloser(rc io.ReadCloser) io.ReadCloser {
+	if s == nil {
+		return rc
+	}
+	return &tracedReadCloser{rc: rc, s: s}
+}
+
+func (t *tracedReadCloser) Read(p []byte) (int, error) {
+	n, err := t.rc.Read(p)
+	t.s.bytes.Add(int64(n))
+	if err != nil && err != io.EOF && t.rerr == nil {
+		t.rerr = err
+	}
+	return n, err
+}
+
+func (t *tracedReadCloser) Close() error {
+	err := t.rc.Close()
+	if t.rerr != nil {
+		t.s.end(t.rerr)
+	} else {
+		t.s.end(err)
+	}
+	return err
+}
@@ -0,0 +1,128 @@
+package remoteio
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+)
+
+// Transform は、書き込む内容を読み込みながら変換するリーダーを返す関数です (圧縮、暗号化、行の絞り込み、形式の変換など)。
+// 返したリーダーは、r を最後まで読み込んだ後に io.EOF を返す必要があります。
+// 書き込みを再試行する場合は、新しい r に対して再び呼び出されるため、状態は返すリーダーに持たせてください。
+type Transform func(r io.Reader) io.Reader
+
+// WithTransforms は、書き込む内容に ts を指定した順に適用してから書き込み先へ書き込みます (最初の ts がコピー元に最も近い変換です)。
+// 複数回指定した場合は、指定した順に連結されます。
+// 変換は Content-Type の判定、WithGzip の圧縮、書き込みの検証と進捗の通知より前に適用されるため、
+// これらは変換後の内容に対して行われます。変換を適用したリーダーがエラーを返した場合は、書き込み先を確定させずに失敗します。
+// OutputWriter の独自の実装は、ApplyTransforms で同じ変換を適用できます。
+func WithTransforms(ts ...Transform) WriteOption {
+	return func(o *writeOptions) {
+		o.transforms = append(o.transforms, ts...)
+	}
+}
+
+// ApplyTransforms は、opts の WithTransforms で指定された変換を順に r へ適用したリーダーを返します。
+// 変換が指定されていない場合は r をそのまま返します。
+func ApplyTransforms(r io.Reader, opts ...WriteOption) io.Reader {
+	return newWriteOptions(opts).applyTransforms(r)
+}
+
+// applyTransforms は、WithTransforms で指定された変換を順に r へ適用したリーダーを返します。
+func (o writeOptions) applyTransforms(r io.Reader) io.Reader {
+	for _, t := range o.transforms {
+		r = t(r)
+	}
+	return r
+}
+
+// GzipTransform は、内容を gzip で圧縮する Transform を返します。
+// WithGzip と異なり Content-Encoding を設定しないため、.gz のファイルとして保存する場合に使用します。
+func GzipTransform() Transform {
+	return func(r io.Reader) io.Reader {
+		return newGzipReader(r)
+	}
+}
+
+// EncryptTransform は、内容を EncryptReader で暗号化する Transform を返します。
+// データ鍵の生成やラップに失敗した場合は、返したリーダーの読み込みがそのエラーを返します。
+func EncryptTransform(ctx context.Context, kw KeyWrapper) Transform {
+	return func(r io.Reader) io.Reader {
+		er, err := EncryptReader(ctx, r, kw)
+		if err != nil {
+			return &errorReader{err: err}
+		}
+		return er
+	}
+}
+
+// FilterLines は、fn が true を返した行だけを残す Transform を返します。
+// fn に渡す行は末尾の改行を含みません。残した行は元の改行 (最後の行に改行がない場合はなし) とともに出力します。
+func FilterLines(fn func(line []byte) bool) Transform {
+	return MapLines(func(line []byte) ([]byte, bool) {
+		return line, fn(line)
+	})
+}
+
+// MapLines は、各行を fn が返した内容に置き換える Transform を返します。fn が false を返した行は出力しません。
+// fn に渡す行は末尾の改行を含まず、次の呼び出しまでしか有効ではありません。
+// CSV から JSON Lines への変換など、行ごとの形式の変換に使用します。
+func MapLines(fn func(line []byte) ([]byte, bool)) Transform {
+	return func(r io.Reader) io.Reader {
+		return &lineReader{br: bufio.NewReader(r), fn: fn}
+	}
+}
+
+// lineReader は、行ごとに変換した内容を返す io.Reader です。
+type lineReader struct {
+	br      *bufio.Reader
+	fn      func(line []byte) ([]byte, bool)
+	line    []byte // 読み込み中の行 (bufio のバッファより長い行を連結するため)
+	pending []byte // 変換済みでまだ返していない内容
+	err     error  // コピー元の読み込みで発生したエラー (io.EOF を含む)
+}
+
+// Read は io.Reader インターフェースを実装します。
+func (l *lineReader) Read(p []byte) (int, error) {
+	for len(l.pending) == 0 {
+		if l.err != nil {
+			return 0, l.err
+		}
+		chunk, err := l.br.ReadSlice('\n')
+		l.line = append(l.line, chunk...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			l.err = err
+			if len(l.line) == 0 {
+				continue
+			}
+		}
+		// 行の内容と改行 (\n または \r\n) を分ける
+		end := len(l.line)
+		if bytes.HasSuffix(l.line, []byte("\r\n")) {
+			end -= 2
+		} else if bytes.HasSuffix(l.line, []byte("\n")) {
+			end--
+		}
+		line, eol := l.line[:end], l.line[end:]
+		if out, ok := l.fn(line); ok {
+			l.pending = append(append(l.pending[:0], out...), eol...)
+		}
+		l.line = l.line[:0]
+	}
+	n := copy(p, l.pending)
+	l.pending = l.pending[n:]
+	return n, nil
+}
+
+// errorReader は、読み込みのたびに err を返す io.Reader です。
+type errorReader struct {
+	err error
+}
+
+func (e *errorReader) Read([]byte) (int, error) {
+	return 0, e.err
+}
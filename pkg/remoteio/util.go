@@ -1,7 +1,10 @@
 package remoteio
 
 import (
-	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -14,7 +17,7 @@ func IsGCSURI(uri string) bool {
 // URIが "gs://" で始まっていない場合、または形式が正しくない場合はエラーを返します。
 func ParseGCSURI(uri string) (bucketName string, objectPath string, err error) {
 	if !IsGCSURI(uri) { // ★IsGCSURIを利用してチェックをリファクタ
-		return "", "", fmt.Errorf("無効なGCS URI形式: 'gs://'で始まる必要があります")
+		return "", "", invalidURIError("無効なGCS URI形式: 'gs://'で始まる必要があります")
 	}
 
 	path := uri[len("gs://"):] // ★定数またはlen()を使ってマジックナンバーを排除
@@ -29,8 +32,112 @@ func ParseGCSURI(uri string) (bucketName string, objectPath string, err error) {
 	objectPath = path[idx+1:]
 
 	if bucketName == "" {
-		return "", "", fmt.Errorf("GCS URIのバケット名が空です: %s", uri)
+		return "", "", invalidURIError("GCS URIのバケット名が空です: %s", uri)
 	}
 
 	return bucketName, objectPath, nil
 }
+
+// SplitGCSGeneration は、"gs://bucket/object#generation" の形式で世代番号を指定した GCS URI を、
+// 世代番号を除いた URI と世代番号に分割します。世代番号が指定されていない場合は、uri と 0 を返します。
+// "#" の後が正の整数でない場合は、"#" を含むオブジェクト名とみなします。
+func SplitGCSGeneration(uri string) (string, int64) {
+	i := strings.LastIndex(uri, "#")
+	if !IsGCSURI(uri) || i < 0 {
+		return uri, 0
+	}
+	gen, err := strconv.ParseInt(uri[i+1:], 10, 64)
+	if err != nil || gen <= 0 {
+		return uri, 0
+	}
+	return uri[:i], gen
+}
+
+// GCSGenerationURI は、GCS URI に世代番号 gen を指定した "gs://bucket/object#generation" の形式の URI を返します。
+// 既に世代番号が指定されている場合は、gen で置き換えます。
+func GCSGenerationURI(uri string, gen int64) string {
+	base, _ := SplitGCSGeneration(uri)
+	return base + "#" + strconv.FormatInt(gen, 10)
+}
+
+// IsS3URI は、URIが Amazon S3 (s3://) を指しているかどうかをチェックします。
+func IsS3URI(uri string) bool {
+	return strings.HasPrefix(uri, "s3://")
+}
+
+// ParseS3URI は、指定されたs3://URIをバケット名とオブジェクトキーにパースします。
+// URIが "s3://" で始まっていない場合、または形式が正しくない場合はエラーを返します。
+func ParseS3URI(uri string) (bucketName string, key string, err error) {
+	if !IsS3URI(uri) {
+		return "", "", invalidURIError("無効なS3 URI形式: 's3://'で始まる必要があります")
+	}
+
+	bucketName, key, _ = strings.Cut(uri[len("s3://"):], "/")
+	if bucketName == "" {
+		return "", "", invalidURIError("S3 URIのバケット名が空です: %s", uri)
+	}
+
+	return bucketName, key, nil
+}
+
+// IsAzureURI は、URIが Azure Blob Storage (az://) を指しているかどうかをチェックします。
+func IsAzureURI(uri string) bool {
+	return strings.HasPrefix(uri, "az://")
+}
+
+// ParseAzureURI は、指定されたaz://URIをコンテナ名とBlob名にパースします。
+// URIが "az://" で始まっていない場合、または形式が正しくない場合はエラーを返します。
+func ParseAzureURI(uri string) (containerName string, blobName string, err error) {
+	if !IsAzureURI(uri) {
+		return "", "", invalidURIError("無効なAzure URI形式: 'az://'で始まる必要があります")
+	}
+
+	containerName, blobName, _ = strings.Cut(uri[len("az://"):], "/")
+	if containerName == "" {
+		return "", "", invalidURIError("Azure URIのコンテナ名が空です: %s", uri)
+	}
+
+	return containerName, blobName, nil
+}
+
+// IsSFTPURI は、URIが SFTP サーバー (sftp://) を指しているかどうかをチェックします。
+func IsSFTPURI(uri string) bool {
+	return strings.HasPrefix(uri, "sftp://")
+}
+
+// ParseSFTPURI は、指定されたsftp://user@host:port/path 形式のURIを、
+// 接続先 ("user@host:port") とサーバー上のファイルパスにパースします。
+// ユーザー名を省略した場合はローカルのユーザー名、ポートを省略した場合は 22 を補います。
+func ParseSFTPURI(uri string) (address string, filePath string, err error) {
+	if !IsSFTPURI(uri) {
+		return "", "", invalidURIError("無効なSFTP URI形式: 'sftp://'で始まる必要があります")
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", "", invalidURIError("SFTP URIのパースに失敗しました: %w", err)
+	}
+	if u.Hostname() == "" {
+		return "", "", invalidURIError("SFTP URIのホスト名が空です: %s", uri)
+	}
+
+	username := u.User.Username()
+	if username == "" {
+		username = currentUsername()
+	}
+	port := u.Port()
+	if port == "" {
+		port = "22"
+	}
+
+	return username + "@" + net.JoinHostPort(u.Hostname(), port), u.Path, nil
+}
+
+// JoinURI は、URI またはローカルパスの base に "/" 区切りの相対パス rel を連結します。
+// base がURIの場合は "/" で、ローカルパスの場合は OS のパス区切り文字で連結します。
+func JoinURI(base, rel string) string {
+	if SchemeOf(base) == "" {
+		return filepath.Join(base, filepath.FromSlash(rel))
+	}
+	return strings.TrimSuffix(base, "/") + "/" + rel
+}
@@ -0,0 +1,271 @@
+package remoteio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// =================================================================
+// 1. インターフェース定義
+// =================================================================
+
+// TransferInfo は、バリデータに渡される転送の情報です。
+type TransferInfo struct {
+	URI         string // 書き込み先 (GCS URI またはローカルパス)
+	ContentType string // 書き込み時に指定された Content-Type (ローカルファイルの場合は空)
+	Size        int64  // 書き込まれたバイト数 (ValidateResult でのみ有効)
+
+	metadata *transferMetadata // バリデータが記録する検査結果
+}
+
+// SetMetadata は、検査結果などを書き込み先のメタデータとして記録します。
+// 記録は転送の確定後に行われ、GCS ではオブジェクトのカスタムメタデータになります。
+// ローカルファイルへの書き込みでは無視されます。
+func (i TransferInfo) SetMetadata(key, value string) {
+	if i.metadata == nil {
+		return
+	}
+	i.metadata.mu.Lock()
+	defer i.metadata.mu.Unlock()
+	i.metadata.values[key] = value
+}
+
+// transferMetadata は、並行して実行されるバリデータから記録されるメタデータを保持します。
+type transferMetadata struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+// Validator は、転送内容を検査し、ポリシーに反する転送を拒否するためのフックです。
+// 1つのバリデータは複数の転送で並行して使用される可能性があるため、状態を持たないように実装してください。
+type Validator interface {
+	// ValidateStream は、書き込まれる内容の複製 r を読み取りながら検査します。
+	// エラーを返すと転送は中止され、書き込み先は確定されません。r を最後まで読み取る必要はありません。
+	ValidateStream(ctx context.Context, info TransferInfo, r io.Reader) error
+	// ValidateResult は、書き込みの完了後に呼び出されます。
+	// エラーを返すと、書き込まれた内容は削除されます。
+	ValidateResult(ctx context.Context, info TransferInfo) error
+}
+
+// ErrValidationFailed は、バリデータによって転送が拒否されたことを示すエラーです。
+// バリデータが返したエラーと併せてラップされるため、errors.Is で判別できます。
+var ErrValidationFailed = NewMessageError("転送がバリデータにより拒否されました")
+
+// =================================================================
+// 2. 組み込みバリデータ
+// =================================================================
+
+// ResultValidatorFunc は、書き込み完了後の検査のみを行う関数を Validator として扱うためのアダプタです。
+type ResultValidatorFunc func(ctx context.Context, info TransferInfo) error
+
+// ValidateStream は何も検査しません。
+func (f ResultValidatorFunc) ValidateStream(ctx context.Context, info TransferInfo, r io.Reader) error {
+	return nil
+}
+
+// ValidateResult は f を呼び出します。
+func (f ResultValidatorFunc) ValidateResult(ctx context.Context, info TransferInfo) error {
+	return f(ctx, info)
+}
+
+// maxSizeValidator は、書き込まれる内容のサイズが上限を超えた時点で転送を拒否します。
+type maxSizeValidator struct {
+	limit int64
+}
+
+// MaxSize は、limit バイトを超える転送を拒否するバリデータを返します。
+// 上限を超えた時点でストリームの途中でも転送を中止します。
+func MaxSize(limit int64) Validator {
+	return maxSizeValidator{limit: limit}
+}
+
+func (v maxSizeValidator) ValidateStream(ctx context.Context, info TransferInfo, r io.Reader) error {
+	n, err := io.Copy(io.Discard, io.LimitReader(r, v.limit+1))
+	if err != nil {
+		return err
+	}
+	if n > v.limit {
+		return fmt.Errorf(Message("サイズの上限 (%d バイト) を超えています: %s"), v.limit, info.URI)
+	}
+	return nil
+}
+
+func (v maxSizeValidator) ValidateResult(ctx context.Context, info TransferInfo) error {
+	return nil
+}
+
+// contentTypeValidator は、ストリーム先頭から判定した Content-Type が許可リストに含まれるかを検査します。
+type contentTypeValidator struct {
+	allowed []string
+}
+
+// AllowContentTypes は、ストリーム先頭 512 バイトから http.DetectContentType で判定した
+// メディアタイプが allowed のいずれにも一致しない転送を拒否するバリデータを返します。
+// "image/" のように "/" で終わる値、または "image/*" はタイプ全体に一致します。
+func AllowContentTypes(allowed ...string) Validator {
+	return contentTypeValidator{allowed: allowed}
+}
+
+func (v contentTypeValidator) ValidateStream(ctx context.Context, info TransferInfo, r io.Reader) error {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	detected, _, err := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if err != nil {
+		return fmt.Errorf(Message("Content-Typeの判定に失敗しました: %w"), err)
+	}
+	for _, a := range v.allowed {
+		a = strings.TrimSuffix(a, "*")
+		if detected == a || (strings.HasSuffix(a, "/") && strings.HasPrefix(detected, a)) {
+			return nil
+		}
+	}
+	return fmt.Errorf(Message("許可されていないContent-Typeです (%s): %s"), detected, info.URI)
+}
+
+func (v contentTypeValidator) ValidateResult(ctx context.Context, info TransferInfo) error {
+	return nil
+}
+
+// =================================================================
+// 3. 書き込み処理への適用
+// =================================================================
+
+// writeTarget は、検査対象の書き込み先に対する後処理を提供します。
+type writeTarget struct {
+	// remove は、拒否された書き込み先を削除します。
+	remove func(ctx context.Context) error
+	// setMetadata は、バリデータが記録したメタデータを書き込み先に反映します (nil の場合は反映しない)。
+	setMetadata func(ctx context.Context, md map[string]string) error
+}
+
+// validatedWriteFunc は、バリデータを適用して実行される書き込み処理です。
+// r から読み取った内容を書き込み、書き込み先を確定する直前に verdict を呼び出します。
+// verdict がエラーを返した場合は、書き込み先を確定せずに中止しなければなりません。
+type validatedWriteFunc func(ctx context.Context, r io.Reader, verdict func() error) error
+
+// writeValidated は、構成されたバリデータを適用しながら write を実行します。
+// 書き込み完了後の検査で拒否された場合は、target.remove で書き込み先を削除します。
+// WithOpTimeout が指定されている場合は、その時間 r からデータが読み込まれなかった場合に中断します。
+func (c *config) writeValidated(ctx context.Context, info TransferInfo, r io.Reader, write validatedWriteFunc, target writeTarget) (err error) {
+	if c.opTimeout > 0 {
+		watch := newIdleWatch(ctx, c.opTimeout)
+		defer watch.stop()
+		defer func() { err = watch.wrap(err) }()
+		ctx, r = watch.ctx, watch.reader(r)
+	}
+	if len(c.validators) == 0 {
+		return write(ctx, r, func() error { return nil })
+	}
+	info.metadata = &transferMetadata{values: map[string]string{}}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	// 各バリデータに内容の複製をパイプで渡す
+	errs := make([]error, len(c.validators))
+	pipes := make([]io.Writer, len(c.validators))
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		rejection error // 書き込み中にバリデータが拒否した理由
+		writeDone bool  // 書き込み処理が終了し、以降のエラーがパイプのクローズに起因しうるか
+	)
+	for i, v := range c.validators {
+		pr, pw := io.Pipe()
+		pipes[i] = pw
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := v.ValidateStream(ctx, info, pr); err != nil {
+				errs[i] = err
+				mu.Lock()
+				if !writeDone && rejection == nil {
+					rejection = err
+				}
+				mu.Unlock()
+				cancel(err)
+				pr.CloseWithError(err)
+				return
+			}
+			// 読み残しを破棄して、書き込み側をブロックさせない
+			_, _ = io.Copy(io.Discard, pr)
+		}()
+	}
+
+	closePipes := func(err error) {
+		for _, p := range pipes {
+			p.(*io.PipeWriter).CloseWithError(err)
+		}
+	}
+
+	counter := &byteCounter{r: io.TeeReader(r, io.MultiWriter(pipes...))}
+	verdict := func() error {
+		closePipes(nil)
+		wg.Wait()
+		if err := errors.Join(errs...); err != nil {
+			return fmt.Errorf("%w: %w", ErrValidationFailed, err)
+		}
+		return nil
+	}
+
+	if err := write(ctx, counter, verdict); err != nil {
+		mu.Lock()
+		writeDone = true
+		rejected := rejection
+		mu.Unlock()
+		closePipes(err)
+		wg.Wait()
+		if rejected != nil && !errors.Is(err, ErrValidationFailed) {
+			return fmt.Errorf("%w: %w", ErrValidationFailed, rejected)
+		}
+		return err
+	}
+
+	// 書き込み完了後の検査
+	info.Size = counter.n
+	for _, v := range c.validators {
+		if err := v.ValidateResult(ctx, info); err != nil {
+			if rmErr := target.remove(context.WithoutCancel(ctx)); rmErr != nil {
+				return fmt.Errorf(Message("%w: %w (書き込み先の削除にも失敗しました: %v)"), ErrValidationFailed, err, rmErr)
+			}
+			return fmt.Errorf("%w: %w", ErrValidationFailed, err)
+		}
+	}
+
+	// 検査結果のメタデータを反映
+	if target.setMetadata != nil && len(info.metadata.values) > 0 {
+		// 書き込み時に指定されたメタデータを置き換えないよう、統合して反映する
+		md := maps.Clone(c.metadata)
+		if md == nil {
+			md = make(map[string]string, len(info.metadata.values))
+		}
+		maps.Copy(md, info.metadata.values)
+		if err := target.setMetadata(ctx, md); err != nil {
+			return fmt.Errorf(Message("検査結果のメタデータの記録に失敗しました: %w"), err)
+		}
+	}
+	return nil
+}
+
+// byteCounter は、読み込んだバイト数を数える io.Reader です。
+type byteCounter struct {
+	r io.Reader
+	n int64
+}
+
+func (b *byteCounter) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	b.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,135 @@
+package remoteio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"maps"
+	"strings"
+	"testing"
+)
+
+// pngHeader は、http.DetectContentType が image/png と判定する内容の先頭です。
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator Validator
+		content   []byte
+		wantErr   bool
+	}{
+		{name: "上限ちょうどのサイズ", validator: MaxSize(5), content: []byte("hello")},
+		{name: "上限を超えるサイズ", validator: MaxSize(4), content: []byte("hello"), wantErr: true},
+		{name: "空の内容", validator: MaxSize(0), content: nil},
+		{name: "一致するメディアタイプ", validator: AllowContentTypes("image/png"), content: pngHeader},
+		{name: "タイプ全体 (*)", validator: AllowContentTypes("image/*"), content: pngHeader},
+		{name: "タイプ全体 (/)", validator: AllowContentTypes("text/", "image/"), content: pngHeader},
+		{name: "パラメータを除いて判定", validator: AllowContentTypes("text/plain"), content: []byte("hello")},
+		{name: "許可されていないメディアタイプ", validator: AllowContentTypes("image/*"), content: []byte("hello"), wantErr: true},
+		{name: "前方一致しないタイプ", validator: AllowContentTypes("image/p"), content: pngHeader, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validator.ValidateStream(context.Background(), TransferInfo{URI: "gs://bucket/a"}, bytes.NewReader(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateStream() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// recordedTarget は、writeValidated による書き込み先の後処理 (削除とメタデータの記録) を記録します。
+type recordedTarget struct {
+	removed   bool
+	removeErr error
+	metadata  map[string]string
+}
+
+func (r *recordedTarget) target() writeTarget {
+	return writeTarget{
+		remove: func(ctx context.Context) error {
+			r.removed = true
+			return r.removeErr
+		},
+		setMetadata: func(ctx context.Context, md map[string]string) error {
+			r.metadata = md
+			return nil
+		},
+	}
+}
+
+func TestWriteValidated(t *testing.T) {
+	errRejected := errors.New("拒否しました")
+	errWrite := errors.New("書き込みに失敗しました")
+	tag := ResultValidatorFunc(func(ctx context.Context, info TransferInfo) error {
+		info.SetMetadata("scan-result", "clean")
+		return nil
+	})
+	reject := ResultValidatorFunc(func(ctx context.Context, info TransferInfo) error { return errRejected })
+
+	tests := []struct {
+		name          string
+		cfg           config
+		writeErr      error // 書き込み処理が返すエラー
+		removeErr     error
+		wantCommitted bool
+		wantRemoved   bool
+		wantMetadata  map[string]string
+		wantErr       error // nil 以外の場合は errors.Is で判定する
+		notWantErr    error // nil 以外の場合は、errors.Is で一致しないことを判定する
+	}{
+		{name: "バリデータなし", wantCommitted: true},
+		{name: "ストリームの検査を通過", cfg: config{validators: []Validator{MaxSize(5)}}, wantCommitted: true},
+		{name: "ストリームの検査で拒否", cfg: config{validators: []Validator{MaxSize(4)}}, wantErr: ErrValidationFailed},
+		{name: "書き込み完了後の検査で拒否", cfg: config{validators: []Validator{reject}}, wantCommitted: true, wantRemoved: true, wantErr: errRejected},
+		{
+			name: "拒否した書き込み先の削除に失敗", cfg: config{validators: []Validator{reject}}, removeErr: errWrite,
+			wantCommitted: true, wantRemoved: true, wantErr: ErrValidationFailed,
+		},
+		{
+			name: "検査結果のメタデータを統合して記録", cfg: config{validators: []Validator{tag}, metadata: map[string]string{"owner": "team-a"}},
+			wantCommitted: true, wantMetadata: map[string]string{"owner": "team-a", "scan-result": "clean"},
+		},
+		{name: "書き込みの失敗", cfg: config{validators: []Validator{MaxSize(5)}}, writeErr: errWrite, wantErr: errWrite, notWantErr: ErrValidationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recordedTarget{removeErr: tt.removeErr}
+			committed := false
+			write := func(ctx context.Context, r io.Reader, verdict func() error) error {
+				if _, err := io.ReadAll(r); err != nil {
+					return err
+				}
+				if tt.writeErr != nil {
+					return tt.writeErr
+				}
+				if err := verdict(); err != nil {
+					return err
+				}
+				committed = true
+				return nil
+			}
+
+			err := tt.cfg.writeValidated(context.Background(), TransferInfo{URI: "gs://bucket/a"}, strings.NewReader("hello"), write, rec.target())
+			switch {
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Fatalf("writeValidated() = %v, want %v", err, tt.wantErr)
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("writeValidated() = %v", err)
+			case tt.notWantErr != nil && errors.Is(err, tt.notWantErr):
+				t.Fatalf("writeValidated() = %v, want not %v", err, tt.notWantErr)
+			}
+			if committed != tt.wantCommitted {
+				t.Errorf("確定 = %v, want %v", committed, tt.wantCommitted)
+			}
+			if rec.removed != tt.wantRemoved {
+				t.Errorf("削除 = %v, want %v", rec.removed, tt.wantRemoved)
+			}
+			if !maps.Equal(rec.metadata, tt.wantMetadata) {
+				t.Errorf("メタデータ = %v, want %v", rec.metadata, tt.wantMetadata)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package remoteio
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"log/slog"
+
+	"cloud.google.com/go/storage"
+)
+
+// Checksums は、転送した内容から計算したサイズとチェックサムです。
+type Checksums struct {
+	Size   int64  // バイト数
+	CRC32C uint32 // CRC32C チェックサム (Castagnoli)
+	MD5    []byte // MD5 ハッシュ。計算しなかった場合は nil
+}
+
+// CRC32CBase64 は、CRC32C を GCS のオブジェクトの属性と同じ Base64 (ビッグエンディアン) の形式で返します。
+func (c Checksums) CRC32CBase64() string {
+	return base64.StdEncoding.EncodeToString(binary.BigEndian.AppendUint32(nil, c.CRC32C))
+}
+
+// Verify は、計算したサイズとチェックサムを info (コピー元または書き込み先の属性) と比較します。
+// info が提供するもの (CRC32C は GCS、MD5 は GCS の非コンポジットオブジェクトや Azure など) のみを比較し、
+// 一致しない場合は ErrChecksumMismatch を返します。
+func (c Checksums) Verify(info ObjectInfo) error {
+	if c.Size != info.Size {
+		return fmt.Errorf(Message("%w: %s (サイズ %d != %d バイト)"), ErrChecksumMismatch, info.URI, c.Size, info.Size)
+	}
+	if info.CRC32C != nil && c.CRC32C != *info.CRC32C {
+		return fmt.Errorf("%w: %s (CRC32C %08x != %08x)", ErrChecksumMismatch, info.URI, c.CRC32C, *info.CRC32C)
+	}
+	if c.MD5 != nil && info.MD5 != nil && !bytes.Equal(c.MD5, info.MD5) {
+		return fmt.Errorf("%w: %s (MD5 %x != %x)", ErrChecksumMismatch, info.URI, c.MD5, info.MD5)
+	}
+	return nil
+}
+
+// NewCRC32C は、GCS のチェックサムと同じ CRC32C (Castagnoli) を計算する hash.Hash32 を返します。
+func NewCRC32C() hash.Hash32 {
+	return crc32.New(castagnoliTable)
+}
+
+// ChecksumReader は、読み込んだ内容のサイズとチェックサムを計算する io.Reader です。
+// ダウンロードしたストリームを包み、読み終えた後に Checksums().Verify でコピー元の属性と比較するために使用します。
+type ChecksumReader struct {
+	r    io.Reader
+	n    int64
+	crc  hash.Hash32
+	md5  hash.Hash // nil の場合は MD5 を計算しない
+	hash io.Writer
+}
+
+// NewChecksumReader は、r から読み込んだ内容の CRC32C (withMD5 が true の場合は MD5 も) を計算するリーダーを返します。
+func NewChecksumReader(r io.Reader, withMD5 bool) *ChecksumReader {
+	c := &ChecksumReader{r: r, crc: NewCRC32C()}
+	c.hash = c.crc
+	if withMD5 {
+		c.md5 = md5.New()
+		c.hash = io.MultiWriter(c.crc, c.md5)
+	}
+	return c
+}
+
+// Read は io.Reader インターフェースを実装します。
+func (c *ChecksumReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if n > 0 {
+		c.n += int64(n)
+		c.hash.Write(p[:n])
+	}
+	return n, err
+}
+
+// Checksums は、これまでに読み込んだ内容のサイズとチェックサムを返します。
+func (c *ChecksumReader) Checksums() Checksums {
+	sums := Checksums{Size: c.n, CRC32C: c.crc.Sum32()}
+	if c.md5 != nil {
+		sums.MD5 = c.md5.Sum(nil)
+	}
+	return sums
+}
+
+// verifyOptions は、WithVerify で指定された書き込みの検証の設定です。
+type verifyOptions struct {
+	sums    *Checksums // 計算したチェックサムの格納先。nil の場合は格納しない
+	withMD5 bool       // true の場合は MD5 も計算して比較する
+}
+
+// WithVerify は、書き込む内容 (WithGzip などで変換した後の、書き込み先に保存される内容) のサイズと CRC32C
+// (withMD5 が true の場合は MD5 も) を計算し、sums が nil でなければ書き込み後に格納します。
+// GCS への書き込みでは、確定したオブジェクトの属性と比較し、一致しない場合はそのオブジェクトを削除して ErrChecksumMismatch を返します。
+// 他の書き込み先 (ローカルファイルを含む) では、計算のみを行います。
+func WithVerify(sums *Checksums, withMD5 bool) WriteOption {
+	return func(o *writeOptions) {
+		o.verify = &verifyOptions{sums: sums, withMD5: withMD5}
+	}
+}
+
+// verifyWritten は、書き込んだ内容のチェックサム sums を、確定した GCS オブジェクトの属性 attrs と比較します。
+// 一致しない場合は、そのオブジェクト (世代) を削除して ErrChecksumMismatch を返します。
+func (w *UniversalIOWriter) verifyWritten(ctx context.Context, destURI string, sums Checksums, attrs *storage.ObjectAttrs) error {
+	info := ObjectInfo{URI: destURI, Size: attrs.Size, CRC32C: &attrs.CRC32C, MD5: attrs.MD5}
+	err := sums.Verify(info)
+	if err == nil {
+		w.cfg.log().Info(Message("書き込み先のチェックサムを検証しました"), slog.String("uri", destURI), slog.String("crc32c", sums.CRC32CBase64()))
+		return nil
+	}
+	client, clientErr := w.gcs()
+	if clientErr != nil {
+		return fmt.Errorf(Message("%w (書き込み先の削除にも失敗しました: %v)"), err, clientErr)
+	}
+	obj := w.cfg.gcsBucket(client, attrs.Bucket).Object(attrs.Name).Generation(attrs.Generation)
+	if rmErr := obj.Delete(context.WithoutCancel(ctx)); rmErr != nil {
+		return fmt.Errorf(Message("%w (書き込み先の削除にも失敗しました: %v)"), err, rmErr)
+	}
+	return err
+}
@@ -2,31 +2,34 @@ package remoteio
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
-	"strings"
+	"time"
 
 	"cloud.google.com/go/storage"
 )
 
+// DefaultContentType は、Content-Type が指定されず、拡張子と内容からも判定できない (内容が空の) 場合に設定される Content-Type です。
 const DefaultContentType = "text/plain; charset=utf-8"
 
 // =================================================================
 // 1. インターフェース定義
 // =================================================================
 
-// OutputWriter は、GCSおよびローカルファイルシステムへの書き込みを抽象化する汎用インターフェースです。
+// OutputWriter は、URIで指定された書き込み先へコンテンツを書き込むための統一インターフェースです。
+// 書き込み先の種類は URI のスキームで判別されるため、呼び出し元でのURI判別や型アサーションは不要です。
+// バックエンド固有の詳細な制御が必要な場合は、GCSOutputWriter などのインターフェースへ型アサーションしてください。
 type OutputWriter interface {
-	// Write は、GCSまたはローカルファイルパス(uri)を受け取り、データ(reader)を書き込みます。
-	// GCSOutputWriterとLocalOutputWriterのメソッドは、歴史的経緯や詳細な制御のために残すことができますが、
-	// 汎用的な利用には Write を推奨します。
-	Write(ctx context.Context, uri string, contentReader io.Reader, contentType string) error
-
-	GCSOutputWriter
-	LocalOutputWriter
+	// Write は、destURI (GCS URI、S3 URI、Azure URI、SFTP URI、RegisterScheme で登録したスキームのURI、
+	// またはローカルファイルパス) に r の内容を書き込みます。
+	Write(ctx context.Context, destURI string, r io.Reader, opts ...WriteOption) error
 }
 
 // GCSOutputWriter は、Google Cloud Storage (GCS) にコンテンツを書き込むためのインターフェースです。
@@ -45,113 +48,450 @@ type LocalOutputWriter interface {
 // 2. 具象構造体とコンストラクタ (UniversalIOWriterへ統合)
 // =================================================================
 
-// UniversalIOWriter は GCSOutputWriter と LocalOutputWriter の両方を満たす具象型です。
+// UniversalIOWriter は GCSOutputWriter、S3OutputWriter、AzureOutputWriter、SFTPOutputWriter と LocalOutputWriter を満たす具象型です。
+// S3 への書き込みには WithS3Client、Azure への書き込みには WithAzureClient でクライアントを指定する必要があります。
 type UniversalIOWriter struct {
 	gcsClient *storage.Client
+	cfg       config
 	// LocalFileWriter の機能は外部依存がないため、フィールドは不要
 }
 
 // NewUniversalIOWriter は新しい UniversalIOWriter インスタンスを作成します。
 // Factoryはこの関数を使って、GCSクライアントを注入したI/Oライターを生成します。
-func NewUniversalIOWriter(client *storage.Client) *UniversalIOWriter {
-	return &UniversalIOWriter{gcsClient: client}
+func NewUniversalIOWriter(client *storage.Client, opts ...Option) *UniversalIOWriter {
+	return &UniversalIOWriter{gcsClient: client, cfg: newConfig(opts)}
 }
 
 // =================================================================
 // 3. コアロジック (実装)
 // =================================================================
 
-// Write は OutputWriter インターフェースの汎用メソッドを実装します。
-// パスのプレフィックスを見て WriteToGCS または WriteToLocal へ処理を委譲します。
-func (w *UniversalIOWriter) Write(ctx context.Context, uri string, contentReader io.Reader, contentType string) error {
-	if strings.HasPrefix(uri, "gs://") {
-		// GCSへの書き込み
-		bucketName, objectPath, err := ParseGCSURI(uri)
+// Write は OutputWriter インターフェースを実装します。
+// URIのスキームに登録されたバックエンド (WriteToGCS、WriteToS3、WriteToAzure、WriteToSFTP、
+// または RegisterScheme で登録された関数) へ処理を委譲し、スキームがない場合は WriteToLocal へ委譲します。
+func (w *UniversalIOWriter) Write(ctx context.Context, destURI string, r io.Reader, opts ...WriteOption) (err error) {
+	defer classifyError(&err)
+	wo := newWriteOptions(opts)
+	r = wo.applyTransforms(r)
+	if wo.gzip {
+		switch SchemeOf(destURI) {
+		case "gs", "s3", "az":
+		default:
+			return fmt.Errorf(Message("gzip による圧縮は GCS、S3 と Azure への書き込みでのみ指定できます: %s"), destURI)
+		}
+		// Content-Type は圧縮前の内容から判定する
+		contentType, cr, err := resolveContentType(destURI, wo.contentType, r)
 		if err != nil {
-			return fmt.Errorf("GCS URIのパース失敗: %w", err)
+			return err
+		}
+		r = newGzipReader(cr)
+		wo.contentType = contentType
+		wo.headers.contentEncoding = "gzip"
+	}
+	var checksums *ChecksumReader
+	var committed *storage.ObjectAttrs
+	if wo.verify != nil {
+		checksums = NewChecksumReader(r, wo.verify.withMD5)
+		r = checksums
+	}
+	if wo.kmsKeyName != "" && SchemeOf(destURI) != "gs" {
+		return fmt.Errorf(Message("Cloud KMS の鍵は GCS への書き込みでのみ指定できます: %s"), destURI)
+	}
+	conditions := wo.gcsConditions()
+	if conditions != nil {
+		if SchemeOf(destURI) != "gs" {
+			return fmt.Errorf(Message("世代番号の前提条件は GCS への書き込みでのみ指定できます: %s"), destURI)
+		}
+		if w.cfg.noClobber || wo.noClobber {
+			return fmt.Errorf(Message("上書きの防止と世代番号の前提条件は同時に指定できません: %s"), destURI)
+		}
+	}
+	if wo.bufferSize > 0 || wo.chunkSize != nil || wo.fsync || wo.noClobber || conditions != nil || wo.metadata != nil || wo.headers != (objectHeaders{}) || wo.kmsKeyName != "" || wo.verify != nil {
+		// 書き込みごとの設定は、構成を上書きしたコピーで処理する
+		override := *w
+		if wo.bufferSize > 0 {
+			override.cfg.bufferSize = wo.bufferSize
+		}
+		if wo.chunkSize != nil {
+			override.cfg.chunkSize = wo.chunkSize
+		}
+		if wo.fsync {
+			override.cfg.fsync = true
+		}
+		if wo.noClobber {
+			override.cfg.noClobber = true
+		}
+		if conditions != nil {
+			override.cfg.conditions = conditions
 		}
-		return w.WriteToGCS(ctx, bucketName, objectPath, contentReader, contentType)
-	} else {
-		// ローカルファイルへの書き込み (contentTypeは無視される)
-		return w.WriteToLocal(ctx, uri, contentReader)
+		if wo.metadata != nil {
+			override.cfg.metadata = wo.metadata
+		}
+		override.cfg.headers = wo.headers
+		if wo.kmsKeyName != "" {
+			override.cfg.kmsKeyName = wo.kmsKeyName
+		}
+		if wo.verify != nil {
+			override.cfg.committed = func(attrs *storage.ObjectAttrs) { committed = attrs }
+		}
+		w = &override
+	}
+
+	h, ok, err := lookupScheme(destURI)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !ok:
+		// ローカルファイルへの書き込み (Content-Typeは無視される)
+		if err := w.WriteToLocal(ctx, destURI, r); err != nil {
+			return err
+		}
+		if !wo.modTime.IsZero() {
+			if err := os.Chtimes(destURI, time.Time{}, wo.modTime); err != nil {
+				return fmt.Errorf(Message("ローカルファイル(%s)の更新日時の設定に失敗しました: %w"), destURI, err)
+			}
+		}
+	case h.write == nil:
+		return fmt.Errorf(Message("スキーム %s:// は書き込みをサポートしていません: %s"), SchemeOf(destURI), destURI)
+	default:
+		if err := h.write(ctx, w, destURI, r, wo.contentType); err != nil {
+			return err
+		}
+	}
+	if checksums == nil {
+		return nil
+	}
+	sums := checksums.Checksums()
+	if wo.verify.sums != nil {
+		*wo.verify.sums = sums
 	}
+	if committed != nil {
+		return w.verifyWritten(ctx, destURI, sums, committed)
+	}
+	return nil
 }
 
 // WriteToGCS は GCSOutputWriter インターフェースを実装します。
-func (w *UniversalIOWriter) WriteToGCS(ctx context.Context, bucketName, objectPath string, contentReader io.Reader, contentType string) error {
+// contentType が空の場合は、objectPath の拡張子または内容から Content-Type を判定します。
+// contentReader は標準入力のような長さが不明なストリームでもよく、チャンクサイズ (WithChunkSize、既定では 16MiB) ごとに
+// 再開可能なアップロードで送信するため、内容全体をメモリに保持しません。読み込みが失敗した場合はオブジェクトを作成しません。
+func (w *UniversalIOWriter) WriteToGCS(ctx context.Context, bucketName, objectPath string, contentReader io.Reader, contentType string) (err error) {
+	defer classifyError(&err)
 	targetURI := fmt.Sprintf("gs://%s/%s", bucketName, objectPath)
+	ctx, span := w.cfg.startSpan(ctx, "remoteio.WriteToGCS", targetURI)
+	defer func() { span.end(err) }()
 
 	if bucketName == "" {
-		return fmt.Errorf("GCSへの書き込みに失敗しました: バケット名が空です")
+		return invalidURIError("GCSへの書き込みに失敗しました: バケット名が空です")
 	}
 	if objectPath == "" {
-		return fmt.Errorf("GCSへの書き込みに失敗しました: オブジェクトパスが空です")
+		return invalidURIError("GCSへの書き込みに失敗しました: オブジェクトパスが空です")
+	}
+	// クライアントは最初の GCS へのアクセス時に作成される場合がある (WithGCSClientFunc)
+	client, err := w.gcs()
+	if err != nil {
+		return fmt.Errorf(Message("GCSへの書き込みに失敗しました: GCSクライアントが初期化されていません: %w"), err)
 	}
-	if w.gcsClient == nil {
-		// このチェックはFactory側でもされるが、堅牢性向上のため
-		return fmt.Errorf("GCSへの書き込みに失敗しました: GCSクライアントが初期化されていません")
+
+	if err := w.cfg.faults.beforeOp("WriteToGCS", targetURI); err != nil {
+		return err
+	}
+	contentReader = span.countReader(w.cfg.wrapWriteStream(contentReader))
+	// Content-Type が指定されていない場合は、拡張子または内容から判定する
+	contentType, contentReader, err = resolveContentType(objectPath, contentType, contentReader)
+	if err != nil {
+		return err
 	}
 
-	slog.Info("GCS書き込み処理開始", slog.String("uri", targetURI), slog.String("content_type", contentType))
+	w.cfg.log().Info(Message("GCS書き込み処理開始"), slog.String("uri", targetURI), slog.String("content_type", contentType))
 
-	bucket := w.gcsClient.Bucket(bucketName)
+	bucket := w.cfg.gcsBucket(client, bucketName)
 	obj := bucket.Object(objectPath)
 
-	wc := obj.NewWriter(ctx)
+	info := TransferInfo{URI: targetURI, ContentType: contentType}
+	err = w.cfg.writeValidated(ctx, info, contentReader, func(ctx context.Context, r io.Reader, verdict func() error) error {
+		// 中止時にアップロードを確定させないよう、Writer専用のコンテキストを用意する
+		writeCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
 
-	if contentType == "" {
-		wc.ContentType = DefaultContentType
-	} else {
+		// 上書きを防止する場合や世代番号が指定された場合は、それを前提条件としてアップロードする
+		wobj := obj
+		if cond := w.cfg.writeConditions(); cond != nil {
+			wobj = obj.If(*cond)
+		}
+		wc := wobj.NewWriter(writeCtx)
 		wc.ContentType = contentType
-	}
+		wc.Metadata = w.cfg.metadata
+		wc.KMSKeyName = w.cfg.kmsKeyName
+		wc.CacheControl = w.cfg.headers.cacheControl
+		wc.ContentEncoding = w.cfg.headers.contentEncoding
+		wc.ContentDisposition = w.cfg.headers.contentDisposition
+		wc.ContentLanguage = w.cfg.headers.contentLanguage
+		if w.cfg.chunkSize != nil {
+			wc.ChunkSize = max(*w.cfg.chunkSize, 0)
+		}
 
-	if _, err := io.Copy(wc, contentReader); err != nil {
-		// Copy失敗時はwriterをクローズし、エラーを返す
-		wc.Close()
-		slog.Error("GCSへのコンテンツ書き込み中にエラーが発生", slog.String("uri", targetURI), slog.String("error", err.Error()))
-		return fmt.Errorf("GCSへのコンテンツ書き込み中にエラーが発生しました: %w", err)
-	}
+		if _, err := w.cfg.copyBuffer(wc, r); err != nil {
+			// Copy失敗時はコンテキストのキャンセルのみで中止し、不完全なオブジェクトを確定させない
+			// (wc.Close はストリームの終端を送るため、キャンセルより先に処理されるとアップロードが確定してしまう)
+			cancel()
+			if w.cfg.writeConditions() != nil && isPreconditionFailed(err) {
+				return w.cfg.preconditionError(targetURI)
+			}
+			w.cfg.log().Error(Message("GCSへのコンテンツ書き込み中にエラーが発生"), slog.String("uri", targetURI), slog.String("error", err.Error()))
+			return fmt.Errorf(Message("GCSへのコンテンツ書き込み中にエラーが発生しました: %w"), err)
+		}
 
-	if err := wc.Close(); err != nil {
-		slog.Error("GCS Writerのクローズに失敗", slog.String("uri", targetURI), slog.String("error", err.Error()))
-		return fmt.Errorf("GCS Writerのクローズに失敗しました (アップロード処理中のエラー): %w", err)
+		if err := verdict(); err != nil {
+			cancel()
+			return err
+		}
+
+		if err := wc.Close(); err != nil {
+			if w.cfg.writeConditions() != nil && isPreconditionFailed(err) {
+				return w.cfg.preconditionError(targetURI)
+			}
+			w.cfg.log().Error(Message("GCS Writerのクローズに失敗"), slog.String("uri", targetURI), slog.String("error", err.Error()))
+			return fmt.Errorf(Message("GCS Writerのクローズに失敗しました (アップロード処理中のエラー): %w"), err)
+		}
+		if w.cfg.committed != nil {
+			w.cfg.committed(wc.Attrs())
+		}
+		return nil
+	}, writeTarget{
+		remove: obj.Delete,
+		setMetadata: func(ctx context.Context, md map[string]string) error {
+			_, err := obj.Update(ctx, storage.ObjectAttrsToUpdate{Metadata: md})
+			return err
+		},
+	})
+	if err != nil {
+		return err
 	}
 
-	slog.Info("GCS書き込み処理完了", slog.String("uri", targetURI))
+	w.cfg.log().Info(Message("GCS書き込み処理完了"), slog.String("uri", targetURI))
+	return nil
+}
+
+// deleteGCSObject は、GCS URI で指定されたオブジェクトを削除します。
+func (w *UniversalIOWriter) deleteGCSObject(ctx context.Context, gcsURI string) error {
+	client, err := w.gcs()
+	if err != nil {
+		return fmt.Errorf(Message("GCSクライアントが初期化されていないため、GCSオブジェクトを削除できません (URI: %s): %w"), gcsURI, err)
+	}
+	bucketName, objectPath, err := ParseGCSURI(gcsURI)
+	if err != nil {
+		return fmt.Errorf(Message("GCS URIのパース失敗: %w"), err)
+	}
+	if objectPath == "" {
+		return invalidURIError("無効なGCS URI形式です: %s (オブジェクト名が空です)", gcsURI)
+	}
+	if err := w.cfg.gcsBucket(client, bucketName).Object(objectPath).Delete(ctx); err != nil {
+		return fmt.Errorf(Message("GCSオブジェクトの削除に失敗しました (URI: %s): %w"), gcsURI, err)
+	}
 	return nil
 }
 
 // WriteToLocal は LocalOutputWriter インターフェースを実装します。
-func (w *UniversalIOWriter) WriteToLocal(ctx context.Context, path string, contentReader io.Reader) error {
-	// Contextは、ローカルファイルの操作では通常使用されないが、シグネチャを合わせる
-	_ = ctx
-	slog.Info("ローカル書き込み処理開始", slog.String("path", path))
+func (w *UniversalIOWriter) WriteToLocal(ctx context.Context, path string, contentReader io.Reader) (err error) {
+	defer classifyError(&err)
+	ctx, span := w.cfg.startSpan(ctx, "remoteio.WriteToLocal", path)
+	defer func() { span.end(err) }()
+	if err := w.cfg.faults.beforeOp("WriteToLocal", path); err != nil {
+		return err
+	}
+	contentReader = span.countReader(w.cfg.wrapWriteStream(contentReader))
+
+	w.cfg.log().Info(Message("ローカル書き込み処理開始"), slog.String("path", path))
 
 	// ★修正適用: 出力先のディレクトリが存在しない場合は作成 (os.MkdirAll)
-	outputDir := filepath.Dir(path)
-	if outputDir != "" && outputDir != "." {
-		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			slog.Error("出力ディレクトリの作成に失敗", slog.String("path", path), slog.String("error", err.Error()))
-			return fmt.Errorf("出力ディレクトリ(%s)の作成に失敗しました: %w", outputDir, err)
+	if err := w.cfg.mkdirParent(path); err != nil {
+		w.cfg.log().Error(Message("出力ディレクトリの作成に失敗"), slog.String("path", path), slog.String("error", err.Error()))
+		return err
+	}
+
+	info := TransferInfo{URI: path}
+	err = w.cfg.writeValidated(ctx, info, contentReader, func(ctx context.Context, r io.Reader, verdict func() error) error {
+		// ローカルファイルへの書き込み自体はキャンセルできないため、読み込みごとにコンテキストを確認して中断する
+		r = newContextReader(ctx, r)
+		existing, statErr := os.Stat(path)
+		if w.cfg.noClobber && statErr == nil {
+			return destinationExists(path)
+		}
+		if statErr == nil && !existing.Mode().IsRegular() {
+			// デバイスや名前付きパイプなどは置き換えずに、そのまま書き込む
+			return w.writeLocalInPlace(path, r, verdict)
+		}
+		// 失敗・拒否された場合に既存のファイルを失わないよう、同じディレクトリの一時ファイルに書き込んでから置き換える
+		tmpPath, err := localTempPath(path)
+		if err != nil {
+			return err
+		}
+		file, err := w.cfg.openLocalFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_EXCL)
+		if err != nil {
+			w.cfg.log().Error(Message("ローカルファイルの作成に失敗"), slog.String("path", path), slog.String("error", err.Error()))
+			return fmt.Errorf(Message("ローカルファイル(%s)の作成に失敗しました: %w"), path, err)
+		}
+
+		// 失敗・中止時は一時ファイルを残さない
+		abort := func(err error) error {
+			file.Close()
+			os.Remove(tmpPath)
+			return err
 		}
+		if _, err := w.cfg.copyBuffer(file, r); err != nil {
+			w.cfg.log().Error(Message("ローカルファイルへのコンテンツ書き込み中にエラーが発生"), slog.String("path", path), slog.String("error", err.Error()))
+			return abort(fmt.Errorf(Message("ローカルファイル(%s)へのコンテンツ書き込み中にエラーが発生しました: %w"), path, err))
+		}
+		if err := verdict(); err != nil {
+			return abort(err)
+		}
+		if w.cfg.fileMode == 0 && statErr == nil {
+			// 上書きする場合は、一時ファイルではなく既存のファイルのパーミッションを引き継ぐ
+			if err := file.Chmod(existing.Mode().Perm()); err != nil {
+				return abort(fmt.Errorf(Message("ローカルファイル(%s)のパーミッションの設定に失敗しました: %w"), path, err))
+			}
+		}
+		if w.cfg.fsync {
+			if err := file.Sync(); err != nil {
+				w.cfg.log().Error(Message("ローカルファイルの fsync に失敗"), slog.String("path", path), slog.String("error", err.Error()))
+				return abort(fmt.Errorf(Message("ローカルファイル(%s)の fsync に失敗しました: %w"), path, err))
+			}
+		}
+		if err := file.Close(); err != nil {
+			os.Remove(tmpPath)
+			return fmt.Errorf(Message("ローカルファイル(%s)のクローズに失敗しました: %w"), path, err)
+		}
+		if err := w.cfg.commitLocalFile(tmpPath, path); err != nil {
+			os.Remove(tmpPath)
+			return err
+		}
+		if w.cfg.fsync {
+			// 一時ファイルの名前の変更 (置き換え) を確定させる
+			return syncDir(filepath.Dir(path))
+		}
+		return nil
+	}, writeTarget{
+		remove: func(ctx context.Context) error {
+			if info, err := os.Lstat(path); err == nil && !info.Mode().IsRegular() {
+				return nil
+			}
+			return os.Remove(path)
+		},
+	})
+	if err != nil {
+		return err
 	}
 
-	file, err := os.Create(path)
+	w.cfg.log().Info(Message("ローカル書き込み処理完了"), slog.String("path", path))
+	return nil
+}
+
+// writeLocalInPlace は、通常のファイルではない既存の path (デバイスなど) を開いて、r の内容をそのまま書き込みます。
+func (w *UniversalIOWriter) writeLocalInPlace(path string, r io.Reader, verdict func() error) error {
+	file, err := w.cfg.openLocalFile(path, os.O_WRONLY|os.O_TRUNC)
 	if err != nil {
-		slog.Error("ローカルファイルの作成に失敗", slog.String("path", path), slog.String("error", err.Error()))
-		return fmt.Errorf("ローカルファイル(%s)の作成に失敗しました: %w", path, err)
+		return fmt.Errorf(Message("ローカルファイル(%s)の作成に失敗しました: %w"), path, err)
 	}
 	defer file.Close()
+	if _, err := w.cfg.copyBuffer(file, r); err != nil {
+		return fmt.Errorf(Message("ローカルファイル(%s)へのコンテンツ書き込み中にエラーが発生しました: %w"), path, err)
+	}
+	return verdict()
+}
 
-	if _, err := io.Copy(file, contentReader); err != nil {
-		slog.Error("ローカルファイルへのコンテンツ書き込み中にエラーが発生", slog.String("path", path), slog.String("error", err.Error()))
-		return fmt.Errorf("ローカルファイル(%s)へのコンテンツ書き込み中にエラーが発生しました: %w", path, err)
+// localTempPath は、path と同じディレクトリに作成する一時ファイルのパスを返します。
+// 名前の変更で置き換えられるよう、一時ファイルは常に書き込み先と同じファイルシステムに作成します。
+func localTempPath(path string) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
 	}
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".remoteio-"+hex.EncodeToString(b)), nil
+}
 
-	slog.Info("ローカル書き込み処理完了", slog.String("path", path))
-	return nil
+// commitLocalFile は、書き込みを終えた一時ファイル tmpPath を path に置き換えます。
+// WithNoClobber が指定されている場合は、path が既に存在すれば置き換えずに失敗します。
+func (c *config) commitLocalFile(tmpPath, path string) error {
+	if !c.noClobber {
+		if err := os.Rename(tmpPath, path); err != nil {
+			return fmt.Errorf(Message("ローカルファイル(%s)の確定に失敗しました: %w"), path, err)
+		}
+		return nil
+	}
+	// ハードリンクは、リンク先が存在する場合は失敗するため、存在の確認と確定を原子的に行える
+	err := linkFile(tmpPath, path)
+	switch {
+	case err == nil:
+		return os.Remove(tmpPath)
+	case errors.Is(err, fs.ErrExist):
+		return destinationExists(path)
+	case errors.Is(err, errors.ErrUnsupported), errors.Is(err, fs.ErrPermission):
+		// ハードリンクをサポートしないファイルシステム (FAT、exFAT、一部の SMB・FUSE など) では、O_EXCL で作成してコピーする
+		return c.copyLocalFileExclusive(tmpPath, path)
+	default:
+		return fmt.Errorf(Message("ローカルファイル(%s)の確定に失敗しました: %w"), path, err)
+	}
+}
+
+// linkFile は、commitLocalFile がハードリンクの作成に使用する関数です (テストで置き換えます)。
+var linkFile = os.Link
+
+// copyLocalFileExclusive は、path を O_EXCL で作成して一時ファイル tmpPath の内容をコピーし、tmpPath を削除します。
+// path が既に存在する場合は ErrAlreadyExists を返し、既存のファイルは変更しません。
+func (c *config) copyLocalFileExclusive(tmpPath, path string) error {
+	src, err := os.Open(tmpPath)
+	if err != nil {
+		return fmt.Errorf(Message("ローカルファイル(%s)の確定に失敗しました: %w"), path, err)
+	}
+	defer src.Close()
+	dst, err := c.openLocalFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL)
+	if errors.Is(err, fs.ErrExist) {
+		return destinationExists(path)
+	}
+	if err != nil {
+		return fmt.Errorf(Message("ローカルファイル(%s)の作成に失敗しました: %w"), path, err)
+	}
+	// 作成したファイルは、コピーが完了するまで不完全なため、失敗した場合は削除する
+	if _, err := c.copyBuffer(dst, src); err != nil {
+		dst.Close()
+		os.Remove(path)
+		return fmt.Errorf(Message("ローカルファイル(%s)へのコンテンツ書き込み中にエラーが発生しました: %w"), path, err)
+	}
+	if c.fsync {
+		if err := dst.Sync(); err != nil {
+			dst.Close()
+			os.Remove(path)
+			return fmt.Errorf(Message("ローカルファイル(%s)の fsync に失敗しました: %w"), path, err)
+		}
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path)
+		return fmt.Errorf(Message("ローカルファイル(%s)のクローズに失敗しました: %w"), path, err)
+	}
+	return os.Remove(tmpPath)
+}
+
+// contextReader は、読み込みの前にコンテキストを確認し、キャンセルされていればそのエラーを返す io.Reader です。
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// newContextReader は、ctx がキャンセルされた時点で読み込みを中断する r のラッパーを返します。
+func newContextReader(ctx context.Context, r io.Reader) io.Reader {
+	return &contextReader{ctx: ctx, r: r}
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
 }
 
-// 型アサーションチェック (UniversalIOWriterが両方のインターフェースを満たしていることを確認)
+// 型アサーションチェック (UniversalIOWriterが各インターフェースを満たしていることを確認)
+var _ OutputWriter = (*UniversalIOWriter)(nil)
 var _ GCSOutputWriter = (*UniversalIOWriter)(nil)
 var _ LocalOutputWriter = (*UniversalIOWriter)(nil)
@@ -0,0 +1,193 @@
+package remoteio
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"errors"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// errReader は、内容の途中で失敗する io.Reader です。
+type errReader struct{}
+
+func (errReader) Read(p []byte) (int, error) {
+	return 0, errors.New("読み込みに失敗しました")
+}
+
+func TestWriteToLocal(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string // 空の場合は書き込み先を事前に作成しない
+		opts     []Option
+		content  io.Reader
+		want     string // 書き込み後の書き込み先の内容
+		wantErr  error  // nil 以外の場合は errors.Is で判定する
+		wantFail bool
+	}{
+		{name: "新規作成", content: strings.NewReader("new"), want: "new"},
+		{name: "上書き", existing: "old", content: strings.NewReader("new"), want: "new"},
+		{name: "上書きの防止で新規作成", opts: []Option{WithNoClobber()}, content: strings.NewReader("new"), want: "new"},
+		{name: "上書きの防止で既存", existing: "old", opts: []Option{WithNoClobber()}, content: strings.NewReader("new"), want: "old", wantErr: ErrAlreadyExists},
+		{name: "読み込みの失敗で既存を残す", existing: "old", content: io.MultiReader(strings.NewReader("partial"), errReader{}), want: "old", wantFail: true},
+		{name: "拒否で既存を残す", existing: "old", opts: []Option{WithValidators(MaxSize(2))}, content: strings.NewReader("new"), want: "old", wantErr: ErrValidationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "out.txt")
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			err := NewUniversalIOWriter(nil, tt.opts...).Write(context.Background(), path, tt.content)
+			switch {
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Fatalf("Write() = %v, want %v", err, tt.wantErr)
+			case tt.wantFail && err == nil:
+				t.Fatal("Write() = nil, want error")
+			case tt.wantErr == nil && !tt.wantFail && err != nil:
+				t.Fatalf("Write() = %v", err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("内容 = %q, want %q", got, tt.want)
+			}
+			assertNoTempFiles(t, dir)
+		})
+	}
+}
+
+func TestWriteToLocalKeepsPermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.txt")
+	if err := os.WriteFile(path, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewUniversalIOWriter(nil).Write(context.Background(), path, strings.NewReader("new")); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := info.Mode().Perm(); got != 0640 {
+		t.Errorf("パーミッション = %o, want %o", got, 0640)
+	}
+}
+
+func TestWriteToLocalWithVerify(t *testing.T) {
+	content := "hello, world"
+	tests := []struct {
+		name    string
+		withMD5 bool
+	}{
+		{name: "CRC32C のみ"},
+		{name: "MD5 も計算"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out.txt")
+			var sums Checksums
+			w := NewUniversalIOWriter(nil)
+			if err := w.Write(context.Background(), path, strings.NewReader(content), WithVerify(&sums, tt.withMD5)); err != nil {
+				t.Fatal(err)
+			}
+			if sums.Size != int64(len(content)) {
+				t.Errorf("Size = %d, want %d", sums.Size, len(content))
+			}
+			if want := crc32.Checksum([]byte(content), castagnoliTable); sums.CRC32C != want {
+				t.Errorf("CRC32C = %08x, want %08x", sums.CRC32C, want)
+			}
+			if want := md5.Sum([]byte(content)); tt.withMD5 && !bytes.Equal(sums.MD5, want[:]) {
+				t.Errorf("MD5 = %x, want %x", sums.MD5, want)
+			}
+			if !tt.withMD5 && sums.MD5 != nil {
+				t.Errorf("MD5 = %x, want nil", sums.MD5)
+			}
+		})
+	}
+}
+
+func TestCommitLocalFileNoClobberWithoutHardLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		linkErr  error
+		existing bool
+		wantErr  error
+	}{
+		{name: "ハードリンク未サポート", linkErr: errors.ErrUnsupported},
+		{name: "ハードリンクの権限なし", linkErr: fs.ErrPermission},
+		{name: "ハードリンク未サポートで既存", linkErr: errors.ErrUnsupported, existing: true, wantErr: ErrAlreadyExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := linkFile
+			linkFile = func(oldname, newname string) error {
+				return &os.LinkError{Op: "link", Old: oldname, New: newname, Err: tt.linkErr}
+			}
+			t.Cleanup(func() { linkFile = orig })
+
+			dir := t.TempDir()
+			path := filepath.Join(dir, "out.txt")
+			want := "new"
+			if tt.existing {
+				want = "old"
+				if err := os.WriteFile(path, []byte(want), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c := newConfig([]Option{WithNoClobber()})
+			tmpPath, err := localTempPath(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(tmpPath, []byte("new"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			err = c.commitLocalFile(tmpPath, path)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("commitLocalFile() = %v, want %v", err, tt.wantErr)
+				}
+				os.Remove(tmpPath)
+			} else if err != nil {
+				t.Fatalf("commitLocalFile() = %v", err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != want {
+				t.Errorf("内容 = %q, want %q", got, want)
+			}
+			assertNoTempFiles(t, dir)
+		})
+	}
+}
+
+// assertNoTempFiles は、dir に書き込み用の一時ファイルが残っていないことを確認します。
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.Contains(e.Name(), ".remoteio-") {
+			t.Errorf("一時ファイルが残っています: %s", e.Name())
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package remoteiotest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// Reader は、Store からオブジェクトを読み込む remoteio.InputReader のインメモリ実装です。
+// remoteio.OptionOpener と remoteio.Stater も満たします。
+type Reader struct {
+	store *Store
+}
+
+// NewReader は、store を読み込む Reader を作成します。
+func NewReader(store *Store) *Reader {
+	return &Reader{store: store}
+}
+
+// Open は InputReader インターフェースを実装します。
+// 存在しない URI の場合は fs.ErrNotExist を含むエラーを返します。
+func (r *Reader) Open(ctx context.Context, filePath string) (io.ReadCloser, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.begin(ctx, OpOpen, filePath); err != nil {
+		return nil, s.record(OpOpen, filePath, err)
+	}
+	obj, ok := s.objects[filePath]
+	if !ok {
+		return nil, s.record(OpOpen, filePath, notExist("open", filePath))
+	}
+	s.record(OpOpen, filePath, nil)
+	// 読み込み中に上書きされても影響を受けないよう、内容をコピーする
+	return io.NopCloser(bytes.NewReader(bytes.Clone(obj.Data))), nil
+}
+
+// OpenWith は OptionOpener インターフェースを実装します。ストアは圧縮を扱わないため、opts は無視されます。
+func (r *Reader) OpenWith(ctx context.Context, filePath string, opts ...remoteio.ReadOption) (io.ReadCloser, error) {
+	return r.Open(ctx, filePath)
+}
+
+// Stat は Stater インターフェースを実装します。CRC32C と MD5 は内容から計算します。
+func (r *Reader) Stat(ctx context.Context, uri string) (remoteio.ObjectInfo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.begin(ctx, OpStat, uri); err != nil {
+		return remoteio.ObjectInfo{}, s.record(OpStat, uri, err)
+	}
+	obj, ok := s.objects[uri]
+	if !ok {
+		return remoteio.ObjectInfo{}, s.record(OpStat, uri, notExist("stat", uri))
+	}
+	s.record(OpStat, uri, nil)
+	return info(uri, obj), nil
+}
+
+// Exists は Stater インターフェースを実装します。
+func (r *Reader) Exists(ctx context.Context, uri string) (bool, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.begin(ctx, OpExists, uri); err != nil {
+		return false, s.record(OpExists, uri, err)
+	}
+	_, ok := s.objects[uri]
+	s.record(OpExists, uri, nil)
+	return ok, nil
+}
+
+// notExist は、uri が存在しないことを示す、remoteio.ErrNotFound と fs.ErrNotExist を含むエラーを返します。
+func notExist(op, uri string) error {
+	return remoteio.Classify(&fs.PathError{Op: op, Path: uri, Err: fs.ErrNotExist})
+}
+
+// 型アサーションチェック
+var _ remoteio.InputReader = (*Reader)(nil)
+var _ remoteio.OptionOpener = (*Reader)(nil)
+var _ remoteio.Stater = (*Reader)(nil)
@@ -0,0 +1,184 @@
+// Package remoteiotest は、remoteio のインターフェースのインメモリ実装 (テスト用のフェイク) を提供します。
+//
+// Store はオブジェクトを URI をキーとするマップに保持し、Reader と Writer はそのストアを読み書きします。
+// GCS エミュレータや一時ディレクトリを用意せずに、InputReader や OutputWriter に依存するコードを単体テストできます。
+// 呼び出しは Calls で確認でき、FailOn で特定の操作にエラーを注入できます。
+package remoteiotest
+
+import (
+	"context"
+	"crypto/md5"
+	"hash/crc32"
+	"path"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// 操作の名前 (Call.Op と FailOn の op に指定する値)
+const (
+	OpOpen         = "Open"
+	OpWrite        = "Write"
+	OpWriteToGCS   = "WriteToGCS"
+	OpWriteToLocal = "WriteToLocal"
+	OpStat         = "Stat"
+	OpExists       = "Exists"
+	OpDelete       = "Delete"
+)
+
+// Object は、Store が保持する1つのオブジェクトです。
+type Object struct {
+	Data        []byte
+	ContentType string
+	Metadata    map[string]string
+	Updated     time.Time
+	Generation  int64 // 書き込みごとに増加する世代番号 (WithIfGenerationMatch の判定に使用)
+}
+
+// Call は、Reader または Writer に対する1回の呼び出しの記録です。
+type Call struct {
+	Op  string // 操作の名前 (OpOpen など)
+	URI string // 対象の URI (WriteToGCS の場合は "gs://bucket/object")
+	Err error  // 呼び出しが返したエラー
+}
+
+// fault は、FailOn で登録されたエラーの注入です。
+type fault struct {
+	op  string
+	uri string
+	err error
+}
+
+// Store は、URI (GCS URI、S3 URI、ローカルファイルパスなど) をキーとしてオブジェクトを保持するインメモリのストアです。
+// ゼロ値は使用できないため、NewStore で作成してください。複数のゴルーチンから並行して使用できます。
+type Store struct {
+	mu         sync.Mutex
+	objects    map[string]*Object
+	generation int64
+	calls      []Call
+	faults     []fault
+}
+
+// NewStore は、空の Store を作成します。
+func NewStore() *Store {
+	return &Store{objects: make(map[string]*Object)}
+}
+
+// Put は、uri に data を内容とするオブジェクトを格納します。既存のオブジェクトは置き換えられます。
+// テストの前提となるデータの準備に使用し、呼び出しとしては記録されません。
+func (s *Store) Put(uri string, data []byte) {
+	s.PutObject(uri, Object{Data: data})
+}
+
+// PutObject は、uri に obj を格納します。Updated と Generation が設定されていない場合は、現在時刻と新しい世代番号を設定します。
+func (s *Store) PutObject(uri string, obj Object) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.put(uri, obj)
+}
+
+// put は、ロックを保持した状態で uri に obj を格納します。
+func (s *Store) put(uri string, obj Object) {
+	obj.Data = slices.Clone(obj.Data)
+	if obj.Updated.IsZero() {
+		obj.Updated = time.Now()
+	}
+	if obj.Generation == 0 {
+		s.generation++
+		obj.Generation = s.generation
+	}
+	s.objects[uri] = &obj
+}
+
+// Get は、uri のオブジェクトのコピーを返します。存在しない場合は false を返します。
+func (s *Store) Get(uri string) (Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.objects[uri]
+	if !ok {
+		return Object{}, false
+	}
+	cp := *obj
+	cp.Data = slices.Clone(obj.Data)
+	return cp, true
+}
+
+// URIs は、格納されているオブジェクトの URI をソートして返します。
+func (s *Store) URIs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	uris := make([]string, 0, len(s.objects))
+	for uri := range s.objects {
+		uris = append(uris, uri)
+	}
+	slices.Sort(uris)
+	return uris
+}
+
+// FailOn は、op の操作 (OpOpen など) が uri に対して呼び出された場合に err を返すよう設定します。
+// op または uri に空文字列を指定すると、すべての操作またはすべての URI に一致します。
+// 設定は ClearFaults を呼び出すまで有効です。先に登録されたものが優先されます。
+func (s *Store) FailOn(op, uri string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, fault{op: op, uri: uri, err: err})
+}
+
+// ClearFaults は、FailOn で設定したエラーの注入をすべて解除します。
+func (s *Store) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Calls は、これまでの呼び出しの記録を呼び出された順に返します。
+func (s *Store) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.calls)
+}
+
+// ResetCalls は、呼び出しの記録を消去します。
+func (s *Store) ResetCalls() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+}
+
+// begin は、ロックを保持した状態で、op の呼び出しに注入するエラー (またはコンテキストのエラー) を返します。
+func (s *Store) begin(ctx context.Context, op, uri string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for _, f := range s.faults {
+		if (f.op == "" || f.op == op) && (f.uri == "" || f.uri == uri) {
+			return f.err
+		}
+	}
+	return nil
+}
+
+// record は、ロックを保持した状態で呼び出しを記録し、err をそのまま返します。
+func (s *Store) record(op, uri string, err error) error {
+	s.calls = append(s.calls, Call{Op: op, URI: uri, Err: err})
+	return err
+}
+
+// info は、uri のオブジェクト obj の ObjectInfo を返します。
+func info(uri string, obj *Object) remoteio.ObjectInfo {
+	crc := crc32.Checksum(obj.Data, crc32.MakeTable(crc32.Castagnoli))
+	sum := md5.Sum(obj.Data)
+	return remoteio.ObjectInfo{
+		URI:         uri,
+		Name:        path.Base(uri),
+		Size:        int64(len(obj.Data)),
+		Updated:     obj.Updated,
+		CRC32C:      &crc,
+		ContentType: obj.ContentType,
+		MD5:         sum[:],
+		Generation:  obj.Generation,
+		Metadata:    obj.Metadata,
+	}
+}
@@ -0,0 +1,121 @@
+package remoteiotest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// Writer は、Store へオブジェクトを書き込む remoteio.OutputWriter のインメモリ実装です。
+// remoteio.GCSOutputWriter、remoteio.LocalOutputWriter と remoteio.Deleter も満たします。
+type Writer struct {
+	store *Store
+}
+
+// NewWriter は、store へ書き込む Writer を作成します。
+func NewWriter(store *Store) *Writer {
+	return &Writer{store: store}
+}
+
+// Write は OutputWriter インターフェースを実装し、destURI をキーとして r の内容を格納します。
+// WithContentType、WithMetadata、WithWriteNoClobber、WithIfGenerationMatch と WithTransforms を解釈し、その他のオプションは無視します。
+// r の読み込みがエラーを返した場合は、何も格納せずにそのエラーを返します。
+func (w *Writer) Write(ctx context.Context, destURI string, r io.Reader, opts ...remoteio.WriteOption) error {
+	return w.write(ctx, OpWrite, destURI, remoteio.ApplyTransforms(r, opts...), remoteio.ResolveWriteOptions(opts...))
+}
+
+// WriteToGCS は GCSOutputWriter インターフェースを実装し、"gs://bucketName/objectPath" をキーとして格納します。
+func (w *Writer) WriteToGCS(ctx context.Context, bucketName, objectPath string, contentReader io.Reader, contentType string) error {
+	if bucketName == "" || objectPath == "" {
+		return fmt.Errorf("GCSへの書き込みに失敗しました: バケット名またはオブジェクトパスが空です")
+	}
+	uri := fmt.Sprintf("gs://%s/%s", bucketName, objectPath)
+	return w.write(ctx, OpWriteToGCS, uri, contentReader, remoteio.WriteSettings{ContentType: contentType})
+}
+
+// WriteToLocal は LocalOutputWriter インターフェースを実装し、path をキーとして格納します (ファイルシステムには書き込みません)。
+func (w *Writer) WriteToLocal(ctx context.Context, path string, contentReader io.Reader) error {
+	if path == "" {
+		return fmt.Errorf("ローカルファイルへの書き込みに失敗しました: パスが空です")
+	}
+	return w.write(ctx, OpWriteToLocal, path, contentReader, remoteio.WriteSettings{})
+}
+
+// Delete は Deleter インターフェースを実装します。存在しない URI の場合は fs.ErrNotExist を含むエラーを返します。
+func (w *Writer) Delete(ctx context.Context, uri string) error {
+	s := w.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.begin(ctx, OpDelete, uri); err != nil {
+		return s.record(OpDelete, uri, err)
+	}
+	if _, ok := s.objects[uri]; !ok {
+		return s.record(OpDelete, uri, notExist("remove", uri))
+	}
+	delete(s.objects, uri)
+	return s.record(OpDelete, uri, nil)
+}
+
+// write は、r の内容を読み込み、settings の前提条件を確認してから uri に格納します。
+func (w *Writer) write(ctx context.Context, op, uri string, r io.Reader, settings remoteio.WriteSettings) error {
+	s := w.store
+	s.mu.Lock()
+	err := s.begin(ctx, op, uri)
+	if err != nil {
+		s.record(op, uri, err)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// 読み込みはロックの外で行う (r が同じストアを読み込む場合に備える)
+	data, err := io.ReadAll(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		return s.record(op, uri, fmt.Errorf("書き込む内容の読み込みに失敗しました (%s): %w", uri, err))
+	}
+	existing, exists := s.objects[uri]
+	switch {
+	case settings.NoClobber && exists:
+		return s.record(op, uri, fmt.Errorf("%w: %s", remoteio.ErrAlreadyExists, uri))
+	case settings.IfGenerationMatch != nil:
+		gen := *settings.IfGenerationMatch
+		if (gen == 0 && exists) || (gen != 0 && (!exists || existing.Generation != gen)) {
+			return s.record(op, uri, fmt.Errorf("%w: %s", remoteio.ErrPreconditionFailed, uri))
+		}
+	}
+
+	contentType := settings.ContentType
+	if contentType == "" {
+		contentType = detectContentType(uri, data)
+	}
+	s.put(uri, Object{Data: data, ContentType: contentType, Metadata: maps.Clone(settings.Metadata)})
+	return s.record(op, uri, nil)
+}
+
+// detectContentType は、remoteio の書き込みと同様に、拡張子、内容の先頭の順で Content-Type を判定します。
+func detectContentType(name string, data []byte) string {
+	if byExt := mime.TypeByExtension(path.Ext(name)); byExt != "" {
+		return byExt
+	}
+	if len(data) == 0 {
+		return remoteio.DefaultContentType
+	}
+	return http.DetectContentType(data)
+}
+
+// 型アサーションチェック
+var _ remoteio.OutputWriter = (*Writer)(nil)
+var _ remoteio.GCSOutputWriter = (*Writer)(nil)
+var _ remoteio.LocalOutputWriter = (*Writer)(nil)
+var _ remoteio.Deleter = (*Writer)(nil)
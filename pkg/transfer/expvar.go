@@ -0,0 +1,36 @@
+package transfer
+
+import "expvar"
+
+// ExpvarRecorder は、転送ごとの計測結果を expvar の変数として公開する Recorder です。
+// net/http/pprof などと同様に、/debug/vars から JSON で取得できます。
+type ExpvarRecorder struct {
+	vars *expvar.Map
+}
+
+// NewExpvarRecorder は、name の expvar.Map に次の累計を公開する ExpvarRecorder を作成します。
+//
+//	transfers    完了した転送の数 (失敗を含む)
+//	failures     再試行しても失敗した転送の数
+//	retries      再試行の回数
+//	bytes        転送したバイト数
+//	duration_ms  転送の所要時間の合計 (ミリ秒)
+//
+// expvar の変数名はプロセス内で一意である必要があるため、同じ name で2回呼び出すと panic します。
+func NewExpvarRecorder(name string) *ExpvarRecorder {
+	return &ExpvarRecorder{vars: expvar.NewMap(name)}
+}
+
+// RecordJob は Recorder インターフェースを実装します。
+func (r *ExpvarRecorder) RecordJob(stats JobStats) {
+	r.vars.Add("transfers", 1)
+	if stats.Err != nil {
+		r.vars.Add("failures", 1)
+	}
+	r.vars.Add("retries", int64(stats.Retries))
+	r.vars.Add("bytes", stats.Bytes)
+	r.vars.Add("duration_ms", stats.Duration.Milliseconds())
+}
+
+// 型アサーションチェック
+var _ Recorder = (*ExpvarRecorder)(nil)
@@ -0,0 +1,316 @@
+package transfer
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/shouni/go-remote-io/pkg/factory"
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// =================================================================
+// 1. 1件の転送のオプション
+// =================================================================
+
+// ReadTransform は、コピー元から読み込んだ r を、書き込む前に変換したストリームを返す関数です (展開、復号など)。
+// 返した io.ReadCloser は転送後に Close されます。r は閉じないでください。
+type ReadTransform func(ctx context.Context, r io.Reader) (io.ReadCloser, error)
+
+// RunOption は、Run と Copy の動作を設定するための関数です。
+type RunOption func(*runOptions)
+
+// runOptions は、1件の転送に対する設定を保持します。
+type runOptions struct {
+	engine     []Option               // 再試行、ロガー、トレース (Run のみ。ロガーは Copy でも使用する)
+	readerOpts []remoteio.Option      // Run が作成する InputReader のオプション
+	writerOpts []remoteio.Option      // Run が作成する OutputWriter のオプション
+	writeOpts  []remoteio.WriteOption // 書き込みに指定するオプション
+	sliceOpts  []remoteio.SliceOption // 指定された場合は、リモートのコピー元を範囲ごとに並行して先読みする
+	compressed bool                   // Content-Encoding: gzip の GCS オブジェクトを展開せずに読み込む
+	transform  ReadTransform          // nil の場合は変換しない
+	progress   remoteio.ProgressFunc  // nil の場合は通知しない
+	verify     bool                   // 読み込んだ内容と書き込んだ内容のチェックサムを検証する
+	verifyMD5  bool                   // verify で MD5 も検証する
+}
+
+// WithEngineOptions は、Run で失敗した転送の再試行 (WithRetries、WithRetryBackoff、WithRetryIf)、
+// ロガー (WithLogger) とトレース (WithTracerProvider) を、Engine と同じオプションで設定します。
+// 並行数など、複数の転送に対するオプションは無視されます。省略時は再試行しません。
+func WithEngineOptions(opts ...Option) RunOption {
+	return func(o *runOptions) {
+		o.engine = append(o.engine, opts...)
+	}
+}
+
+// WithReaderOptions は、Run がファクトリから作成する InputReader のオプションを指定します。
+func WithReaderOptions(opts ...remoteio.Option) RunOption {
+	return func(o *runOptions) {
+		o.readerOpts = append(o.readerOpts, opts...)
+	}
+}
+
+// WithWriterOptions は、Run がファクトリから作成する OutputWriter のオプション (バリデータなど) を指定します。
+func WithWriterOptions(opts ...remoteio.Option) RunOption {
+	return func(o *runOptions) {
+		o.writerOpts = append(o.writerOpts, opts...)
+	}
+}
+
+// WithWriteOptions は、書き込みに指定するオプション (メタデータ、前提条件など) を指定します。
+// 書き込みのオプションはサーバー側のコピーには適用できないため、指定した場合は常に内容を転送して書き込みます。
+func WithWriteOptions(opts ...remoteio.WriteOption) RunOption {
+	return func(o *runOptions) {
+		o.writeOpts = append(o.writeOpts, opts...)
+	}
+}
+
+// WithSliceOptions は、リモートのコピー元を opts の範囲ごとに並行して先読みしながら読み込みます。
+// InputReader が remoteio.SlicedInputReader を実装していない場合とローカルファイルでは、通常どおり読み込みます。
+func WithSliceOptions(opts ...remoteio.SliceOption) RunOption {
+	return func(o *runOptions) {
+		o.sliceOpts = append(o.sliceOpts, opts...)
+	}
+}
+
+// WithCompressedRead は、Content-Encoding: gzip の GCS オブジェクトを展開せずに、保存されている内容のまま読み込みます。
+func WithCompressedRead() RunOption {
+	return func(o *runOptions) {
+		o.compressed = true
+	}
+}
+
+// WithReadTransform は、読み込んだ内容を fn で変換してから書き込みます。
+// 変換した内容はコピー元と異なるため、指定した場合は常に内容を転送して書き込みます。
+func WithReadTransform(fn ReadTransform) RunOption {
+	return func(o *runOptions) {
+		o.transform = fn
+	}
+}
+
+// WithProgress は、転送の進捗を fn で受け取ります。bytesTotal はコピー元のサイズで、取得できない場合は -1 です。
+// サーバー側でコピーした場合は、完了後に1回だけ呼び出されます。
+func WithProgress(fn remoteio.ProgressFunc) RunOption {
+	return func(o *runOptions) {
+		o.progress = fn
+	}
+}
+
+// WithVerification は、読み込んだ内容のサイズと CRC32C (withMD5 が true の場合は MD5 も) を計算してコピー元の属性と比較し、
+// 書き込んだ内容も remoteio.WithVerify で検証します。一致しない場合は書き込み先を削除して失敗します。
+// 検証のため、指定した場合は常に内容を転送して書き込みます。
+func WithVerification(withMD5 bool) RunOption {
+	return func(o *runOptions) {
+		o.verify = true
+		o.verifyMD5 = withMD5
+	}
+}
+
+// streamsContent は、内容をクライアントで読み込んで書き込む必要があるかどうかを返します。
+func (o *runOptions) streamsContent() bool {
+	return len(o.writeOpts) > 0 || o.transform != nil || o.verify
+}
+
+// =================================================================
+// 2. 1件の転送
+// =================================================================
+
+// Run は、ファクトリ f から作成した InputReader と OutputWriter で、srcURI を dstURI へ転送します。
+// 失敗した場合は、WithEngineOptions の再試行の設定に従って再試行します。
+// 転送の方法 (サーバー側のコピー、範囲ごとの先読み、ストリームの書き込み) は Copy と同じです。
+func Run(ctx context.Context, f factory.Factory, srcURI, dstURI string, opts ...RunOption) error {
+	o := newRunOptions(opts)
+	reader, err := f.NewInputReader(o.readerOpts...)
+	if err != nil {
+		return fmt.Errorf(remoteio.Message("InputReaderの作成に失敗しました: %w"), err)
+	}
+	writer, err := f.NewOutputWriter(o.writerOpts...)
+	if err != nil {
+		return fmt.Errorf(remoteio.Message("OutputWriterの作成に失敗しました: %w"), err)
+	}
+	e := New(o.engine...)
+	stats := e.runJob(ctx, Job{Source: srcURI, Destination: dstURI}, func(ctx context.Context, job Job) error {
+		return e.copy(ctx, reader, writer, job.Source, job.Destination, o)
+	})
+	return stats.Err
+}
+
+// Copy は、reader で srcURI を開き、writer で dstURI へ1回だけ転送します (再試行しません)。
+// サーバー側でコピーできる組み合わせ (GCS 間、S3 間) の場合は、内容をクライアントに転送せずにコピーします。
+// ただし、書き込みのオプション、内容の変換または検証が指定された場合は、内容を転送して書き込みます。
+// Engine の転送関数から呼び出した場合は、転送したバイト数を AddBytes と同様に報告します。
+func Copy(ctx context.Context, reader remoteio.InputReader, writer remoteio.OutputWriter, srcURI, dstURI string, opts ...RunOption) error {
+	o := newRunOptions(opts)
+	return New(o.engine...).copy(ctx, reader, writer, srcURI, dstURI, o)
+}
+
+// newRunOptions は、opts を適用した runOptions を返します。
+func newRunOptions(opts []RunOption) *runOptions {
+	o := &runOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// copy は Copy の実装です。ロガーには e のロガーを使用します。
+func (e *Engine) copy(ctx context.Context, reader remoteio.InputReader, writer remoteio.OutputWriter, src, dst string, o *runOptions) error {
+	if !o.streamsContent() {
+		copied, err := serverSideCopy(ctx, writer, src, dst)
+		if err != nil {
+			return err
+		}
+		if copied {
+			_, counted := ctx.Value(bytesKey{}).(*atomic.Int64)
+			if counted || o.progress != nil {
+				size := sourceSize(ctx, reader, src)
+				AddBytes(ctx, size)
+				if o.progress != nil {
+					o.progress(size, size)
+				}
+			}
+			return nil
+		}
+	}
+
+	rc, err := o.open(ctx, reader, src)
+	if err != nil {
+		return fmt.Errorf(remoteio.Message("入力ストリームのオープンに失敗しました (%s): %w"), src, err)
+	}
+	defer rc.Close()
+
+	r := CountReader(ctx, rc)
+	if o.progress != nil {
+		r = remoteio.NewProgressReader(r, sourceSize(ctx, reader, src), o.progress)
+	}
+	verification, r, err := o.startVerification(ctx, reader, src, r)
+	if err != nil {
+		return err
+	}
+	if o.transform != nil {
+		tr, err := o.transform(ctx, r)
+		if err != nil {
+			return err
+		}
+		defer tr.Close()
+		r = tr
+	}
+	writeOpts := o.writeOpts
+	if o.verify {
+		writeOpts = append(writeOpts[:len(writeOpts):len(writeOpts)], remoteio.WithVerify(nil, o.verifyMD5))
+	}
+	if err := writer.Write(ctx, dst, r, writeOpts...); err != nil {
+		return fmt.Errorf(remoteio.Message("出力先への書き込みに失敗しました (%s): %w"), dst, err)
+	}
+	return verification.finish(ctx, writer, dst, e.logger)
+}
+
+// open は、コピー元 src を開きます。
+func (o *runOptions) open(ctx context.Context, reader remoteio.InputReader, src string) (io.ReadCloser, error) {
+	if slicer, ok := reader.(remoteio.SlicedInputReader); ok && o.sliceOpts != nil && remoteio.SchemeOf(src) != "" {
+		return slicer.OpenSliced(ctx, src, o.sliceOpts...)
+	}
+	if o.compressed {
+		if opener, ok := reader.(remoteio.OptionOpener); ok {
+			return opener.OpenWith(ctx, src, remoteio.WithReadCompressed(true))
+		}
+	}
+	return reader.Open(ctx, src)
+}
+
+// serverSideCopy は、writer が remoteio.Copier を実装している場合に、src を dst へサーバー側でコピーします。
+// サーバー側でコピーできない組み合わせの場合は、false と nil を返します。
+func serverSideCopy(ctx context.Context, writer remoteio.OutputWriter, src, dst string) (bool, error) {
+	copier, ok := writer.(remoteio.Copier)
+	if !ok {
+		return false, nil
+	}
+	err := copier.CopyObject(ctx, src, dst)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, remoteio.ErrCopyUnsupported):
+		return false, nil
+	default:
+		return false, fmt.Errorf(remoteio.Message("サーバー側のコピーに失敗しました (%s -> %s): %w"), src, dst, err)
+	}
+}
+
+// sourceSize は、uri のサイズを返します。取得できない場合は -1 を返します。
+func sourceSize(ctx context.Context, reader remoteio.InputReader, uri string) int64 {
+	stater, ok := reader.(remoteio.Stater)
+	if !ok {
+		return -1
+	}
+	info, err := stater.Stat(ctx, uri)
+	if err != nil {
+		return -1
+	}
+	return info.Size
+}
+
+// =================================================================
+// 3. 検証
+// =================================================================
+
+// verification は、WithVerification でコピー元から読み込んだ内容を、コピー元の属性と比較するための状態です。
+type verification struct {
+	src        string
+	info       remoteio.ObjectInfo
+	reader     *remoteio.ChecksumReader
+	transcoded bool // コピー元が GCS で展開されて読み込まれ、保存されている内容のチェックサムと比較できない場合は true
+}
+
+// startVerification は、WithVerification が指定された場合にコピー元 src の属性を取得し、r をチェックサムを計算するリーダーで包みます。
+// 指定されていない場合は、nil と r をそのまま返します。
+func (o *runOptions) startVerification(ctx context.Context, reader remoteio.InputReader, src string, r io.Reader) (*verification, io.Reader, error) {
+	if !o.verify {
+		return nil, r, nil
+	}
+	stater, ok := reader.(remoteio.Stater)
+	if !ok {
+		return nil, nil, errors.New(remoteio.Message("InputReaderが情報の取得をサポートしていません"))
+	}
+	info, err := stater.Stat(ctx, src)
+	if err != nil {
+		return nil, nil, fmt.Errorf(remoteio.Message("コピー元の情報の取得に失敗しました (%s): %w"), src, err)
+	}
+	v := &verification{
+		src:        src,
+		info:       info,
+		reader:     remoteio.NewChecksumReader(r, o.verifyMD5),
+		transcoded: info.ContentEncoding == "gzip" && remoteio.IsGCSURI(src) && !o.compressed,
+	}
+	return v, v.reader, nil
+}
+
+// finish は、読み込んだ内容のチェックサムをコピー元の属性と比較します。一致しない場合は、書き込んだ dst を削除します。
+// v が nil の場合は何もしません。
+func (v *verification) finish(ctx context.Context, writer remoteio.OutputWriter, dst string, logger *slog.Logger) error {
+	if v == nil {
+		return nil
+	}
+	sums := v.reader.Checksums()
+	if v.transcoded {
+		logger.Warn(remoteio.Message("コピー元は展開されて読み込まれたため、チェックサムを検証できません"), slog.String("source", v.src))
+		return nil
+	}
+	if err := sums.Verify(v.info); err != nil {
+		if deleter, ok := writer.(remoteio.Deleter); ok {
+			if rmErr := deleter.Delete(context.WithoutCancel(ctx), dst); rmErr != nil {
+				logger.Warn(remoteio.Message("書き込み先の削除に失敗しました"), slog.String("destination", dst), slog.String("error", rmErr.Error()))
+			}
+		}
+		return fmt.Errorf(remoteio.Message("コピー元の内容の検証に失敗しました (%s): %w"), v.src, err)
+	}
+	attrs := []any{slog.String("source", v.src), slog.Int64("bytes", sums.Size), slog.String("crc32c", sums.CRC32CBase64())}
+	if sums.MD5 != nil {
+		attrs = append(attrs, slog.String("md5", base64.StdEncoding.EncodeToString(sums.MD5)))
+	}
+	logger.Info(remoteio.Message("コピー元のチェックサムを検証しました"), attrs...)
+	return nil
+}
@@ -0,0 +1,150 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shouni/go-remote-io/pkg/factory"
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+	"github.com/shouni/go-remote-io/pkg/remoteiotest"
+)
+
+// upperTransform は、読み込んだ内容を大文字に変換する ReadTransform です。
+func upperTransform(ctx context.Context, r io.Reader) (io.ReadCloser, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(bytes.ToUpper(data))), nil
+}
+
+func TestRun(t *testing.T) {
+	errTransient := errors.New("一時的なエラー")
+	tests := []struct {
+		name      string
+		src       string
+		opts      []RunOption
+		fault     error // コピー元の Open に注入するエラー
+		wantData  string
+		wantErr   error
+		wantOpens int
+	}{
+		{name: "コピー", src: "gs://bucket/src.txt", wantData: "hello", wantOpens: 1},
+		{name: "内容の変換", src: "gs://bucket/src.txt", opts: []RunOption{WithReadTransform(upperTransform)}, wantData: "HELLO", wantOpens: 1},
+		{name: "検証", src: "gs://bucket/src.txt", opts: []RunOption{WithVerification(true)}, wantData: "hello", wantOpens: 1},
+		{name: "存在しないコピー元", src: "gs://bucket/missing.txt", wantErr: remoteio.ErrNotFound, wantOpens: 1},
+		{
+			name:      "失敗した転送の再試行",
+			src:       "gs://bucket/src.txt",
+			opts:      []RunOption{WithEngineOptions(WithRetries(2), WithRetryBackoff(time.Millisecond))},
+			fault:     errTransient,
+			wantErr:   errTransient,
+			wantOpens: 3,
+		},
+		{
+			name:      "再試行しないエラー",
+			src:       "gs://bucket/src.txt",
+			opts:      []RunOption{WithEngineOptions(WithRetries(2), WithRetryBackoff(time.Millisecond), WithRetryIf(func(error) bool { return false }))},
+			fault:     errTransient,
+			wantErr:   errTransient,
+			wantOpens: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := remoteiotest.NewStore()
+			store.Put("gs://bucket/src.txt", []byte("hello"))
+			if tt.fault != nil {
+				store.FailOn(remoteiotest.OpOpen, tt.src, tt.fault)
+			}
+			err := Run(context.Background(), factory.NewFakeFactory(store), tt.src, "gs://bucket/dst.txt", tt.opts...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Run() = %v, want %v", err, tt.wantErr)
+				}
+				if _, ok := store.Get("gs://bucket/dst.txt"); ok {
+					t.Error("失敗した転送の書き込み先が作成されました")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Run() = %v", err)
+				}
+				obj, ok := store.Get("gs://bucket/dst.txt")
+				if !ok || string(obj.Data) != tt.wantData {
+					t.Errorf("書き込み先 = %q, %v, want %q", obj.Data, ok, tt.wantData)
+				}
+			}
+			opens := 0
+			for _, c := range store.Calls() {
+				if c.Op == remoteiotest.OpOpen {
+					opens++
+				}
+			}
+			if opens != tt.wantOpens {
+				t.Errorf("Open の呼び出し = %d 回, want %d", opens, tt.wantOpens)
+			}
+		})
+	}
+}
+
+func TestRunClosedFactory(t *testing.T) {
+	f := factory.NewFakeFactory(remoteiotest.NewStore())
+	f.Close()
+	err := Run(context.Background(), f, "gs://bucket/src.txt", "gs://bucket/dst.txt")
+	if !errors.Is(err, remoteio.ErrClientClosed) {
+		t.Fatalf("Run() = %v, want %v", err, remoteio.ErrClientClosed)
+	}
+}
+
+func TestCopy(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []RunOption
+		wantData string
+		wantErr  error
+	}{
+		{name: "上書き", wantData: "new"},
+		{name: "上書きの防止", opts: []RunOption{WithWriteOptions(remoteio.WithWriteNoClobber())}, wantData: "old", wantErr: remoteio.ErrAlreadyExists},
+		{name: "書き込みのオプション", opts: []RunOption{WithWriteOptions(remoteio.WithContentType("text/x-test"))}, wantData: "new"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := remoteiotest.NewStore()
+			store.Put("gs://bucket/src.txt", []byte("new"))
+			store.Put("gs://bucket/dst.txt", []byte("old"))
+			var progressed int64
+			opts := append(tt.opts, WithProgress(func(done, total int64) { progressed = done }))
+
+			err := Copy(context.Background(), remoteiotest.NewReader(store), remoteiotest.NewWriter(store), "gs://bucket/src.txt", "gs://bucket/dst.txt", opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Copy() = %v, want %v", err, tt.wantErr)
+			}
+			if obj, _ := store.Get("gs://bucket/dst.txt"); string(obj.Data) != tt.wantData {
+				t.Errorf("書き込み先 = %q, want %q", obj.Data, tt.wantData)
+			}
+			if tt.wantErr == nil && progressed != int64(len("new")) {
+				t.Errorf("進捗 = %d, want %d", progressed, len("new"))
+			}
+		})
+	}
+}
+
+func TestRunErrorsAreTranslated(t *testing.T) {
+	remoteio.SetMessageTranslator(func(msgid string) string {
+		if msgid == "入力ストリームのオープンに失敗しました (%s): %w" {
+			return "failed to open the input stream (%s): %w"
+		}
+		return msgid
+	})
+	t.Cleanup(func() { remoteio.SetMessageTranslator(nil) })
+
+	err := Run(context.Background(), factory.NewFakeFactory(remoteiotest.NewStore()), "gs://bucket/missing.txt", "gs://bucket/dst.txt")
+	if err == nil || !strings.HasPrefix(err.Error(), "failed to open the input stream (gs://bucket/missing.txt): ") {
+		t.Fatalf("Run() = %v", err)
+	}
+}
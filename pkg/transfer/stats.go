@@ -0,0 +1,121 @@
+package transfer
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// JobStats は、1件の転送の計測結果です。
+type JobStats struct {
+	Job      Job
+	Bytes    int64         // 最後の試行で転送したバイト数 (転送関数が AddBytes または CountReader で報告したもの)
+	Duration time.Duration // 再試行の待機時間を含む、最初の試行から完了までの時間
+	Retries  int           // 再試行した回数
+	Err      error         // 再試行しても失敗した場合のエラー。成功した場合は nil
+}
+
+// Throughput は、転送速度 (バイト/秒) を返します。所要時間が 0 の場合は 0 を返します。
+func (s JobStats) Throughput() float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+	return float64(s.Bytes) / s.Duration.Seconds()
+}
+
+// Stats は、RunWithStats で実行した転送の計測結果をまとめたものです。
+type Stats struct {
+	Jobs    []JobStats    // 開始した転送の計測結果 (jobs の順。キャンセルにより開始しなかった転送は含まない)
+	Elapsed time.Duration // 全体の所要時間
+}
+
+// Bytes は、すべての転送で転送したバイト数の合計を返します。
+func (s *Stats) Bytes() int64 {
+	var n int64
+	for _, j := range s.Jobs {
+		n += j.Bytes
+	}
+	return n
+}
+
+// Succeeded は、成功した転送の数を返します。
+func (s *Stats) Succeeded() int {
+	return len(s.Jobs) - s.Failed()
+}
+
+// Failed は、再試行しても失敗した転送の数を返します。
+func (s *Stats) Failed() int {
+	n := 0
+	for _, j := range s.Jobs {
+		if j.Err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Retries は、すべての転送の再試行の回数の合計を返します。
+func (s *Stats) Retries() int {
+	n := 0
+	for _, j := range s.Jobs {
+		n += j.Retries
+	}
+	return n
+}
+
+// Throughput は、全体の所要時間あたりの転送速度 (バイト/秒) を返します。所要時間が 0 の場合は 0 を返します。
+func (s *Stats) Throughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Bytes()) / s.Elapsed.Seconds()
+}
+
+// Recorder は、転送ごとの計測結果を受け取るインターフェースです。Prometheus や expvar などのメトリクスへの登録に使用します。
+// RecordJob は複数のワーカーから並行して呼び出されます。
+type Recorder interface {
+	// RecordJob は、1件の転送が完了 (再試行しても失敗した場合を含む) するたびに呼び出されます。
+	RecordJob(stats JobStats)
+}
+
+// WithRecorder は、転送ごとの計測結果を r に通知します。
+func WithRecorder(r Recorder) Option {
+	return func(e *Engine) {
+		e.recorders = append(e.recorders, r)
+	}
+}
+
+// bytesKey は、context.Context に転送中のバイト数のカウンタを格納するための非公開キー
+type bytesKey struct{}
+
+// AddBytes は、ctx で実行中の転送が n バイトを転送したことを報告します。
+// 転送関数に渡されたコンテキストでのみ有効で、それ以外のコンテキストでは何もしません。
+// サーバー側のコピーなど、内容がクライアントを経由しない転送のバイト数の報告に使用します。
+func AddBytes(ctx context.Context, n int64) {
+	if c, ok := ctx.Value(bytesKey{}).(*atomic.Int64); ok && n > 0 {
+		c.Add(n)
+	}
+}
+
+// CountReader は、r から読み込まれたバイト数を、ctx で実行中の転送のバイト数として報告するリーダーを返します。
+// 転送関数に渡されたコンテキストでない場合は、r をそのまま返します。
+func CountReader(ctx context.Context, r io.Reader) io.Reader {
+	c, ok := ctx.Value(bytesKey{}).(*atomic.Int64)
+	if !ok {
+		return r
+	}
+	return &countingReader{r: r, n: c}
+}
+
+// countingReader は、読み込んだバイト数を n に加算する io.Reader です。
+type countingReader struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
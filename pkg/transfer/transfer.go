@@ -0,0 +1,311 @@
+// Package transfer は、複数のファイルやオブジェクトの転送を、上限付きのワーカープールで並行して実行する転送エンジンを提供します。
+// 再帰コピーや同期など、複数のファイルを扱う操作で使用します。
+// 1件の転送は、スキームに応じてサーバー側のコピーと内容の転送を振り分ける Run と Copy で行えます。
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/shouni/go-remote-io/pkg/remoteio"
+)
+
+// DefaultParallelism は、同時に実行する転送数の既定値です。
+const DefaultParallelism = 4
+
+// tracerName は、転送エンジンが作成するスパンの計装ライブラリ名です。
+const tracerName = "github.com/shouni/go-remote-io/pkg/transfer"
+
+// DefaultRetryBackoff は、再試行までの待機時間の初期値の既定値です。再試行のたびに2倍になります。
+const DefaultRetryBackoff = 500 * time.Millisecond
+
+// =================================================================
+// 1. 型定義
+// =================================================================
+
+// Job は、1件の転送 (コピー元からコピー先へ) です。
+type Job struct {
+	Source      string
+	Destination string
+}
+
+// Func は、1件の転送を実行する関数です。エラーを返した場合は、再試行の対象となります。
+type Func func(ctx context.Context, job Job) error
+
+// Failure は、再試行しても失敗した1件の転送とそのエラーです。
+type Failure struct {
+	Job Job
+	Err error
+}
+
+// Error は、Run で失敗した転送をまとめたエラーです。
+type Error struct {
+	Failures   []Failure
+	Total      int // 実行した転送の総数
+	NotStarted int // WithStopOnFailure により開始しなかった転送の数
+}
+
+// Error は error インターフェースを実装します。
+func (e *Error) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, remoteio.Message("%d 件中 %d 件の転送に失敗しました"), e.Total, len(e.Failures))
+	if e.NotStarted > 0 {
+		fmt.Fprintf(&b, remoteio.Message(" (%d 件は開始せずに中止しました)"), e.NotStarted)
+	}
+	for _, f := range e.Failures {
+		fmt.Fprintf(&b, "\n  %s -> %s: %v", f.Job.Source, f.Job.Destination, f.Err)
+	}
+	return b.String()
+}
+
+// Unwrap は、失敗した転送のエラーを返します。errors.Is / errors.As で個々のエラーを判定できます。
+func (e *Error) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, f := range e.Failures {
+		errs[i] = f.Err
+	}
+	return errs
+}
+
+// =================================================================
+// 2. オプション
+// =================================================================
+
+// Option は、Engine の動作を設定するための関数です。
+type Option func(*Engine)
+
+// WithParallelism は、同時に実行する転送数の上限を設定します。1 未満の場合は 1 とみなします。
+func WithParallelism(n int) Option {
+	return func(e *Engine) {
+		e.parallelism = max(n, 1)
+	}
+}
+
+// WithRetries は、失敗した転送を再試行する回数を設定します。既定値は 0 (再試行しない) です。
+func WithRetries(n int) Option {
+	return func(e *Engine) {
+		e.retries = max(n, 0)
+	}
+}
+
+// WithRetryBackoff は、再試行までの待機時間の初期値を設定します。
+func WithRetryBackoff(d time.Duration) Option {
+	return func(e *Engine) {
+		e.backoff = d
+	}
+}
+
+// WithRetryIf は、失敗した転送を再試行するかどうかを判定する関数を設定します。
+// 省略時は、すべてのエラーを再試行します。内容の検査で拒否された場合など、再試行しても成功しないエラーを除外するために使用します。
+func WithRetryIf(retryable func(error) bool) Option {
+	return func(e *Engine) {
+		e.retryable = retryable
+	}
+}
+
+// WithStopOnFailure は、再試行しても失敗した転送があった時点で、未開始の転送を開始せずに終了します。
+// 実行中の転送は完了を待ちます。省略時は、失敗した転送があっても残りの転送を続行します。
+func WithStopOnFailure() Option {
+	return func(e *Engine) {
+		e.stopOnFailure = true
+	}
+}
+
+// WithTracerProvider は、Run と各転送を tp の OpenTelemetry のスパンとして記録します。
+// 各転送のスパンは Run のスパンの子となり、転送関数にはそのスパンを含むコンテキストが渡されます。
+// 省略した場合はスパンを記録しません。
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(e *Engine) {
+		e.tracer = tp.Tracer(tracerName)
+	}
+}
+
+// WithLogger は、再試行などを記録するロガーを設定します。省略時は slog.Default() に出力します。
+func WithLogger(logger *slog.Logger) Option {
+	return func(e *Engine) {
+		e.logger = logger
+	}
+}
+
+// =================================================================
+// 3. エンジン
+// =================================================================
+
+// Engine は、上限付きのワーカープールで転送を並行して実行します。
+type Engine struct {
+	parallelism   int
+	retries       int
+	backoff       time.Duration
+	retryable     func(error) bool // nil の場合はすべてのエラーを再試行する
+	logger        *slog.Logger
+	tracer        trace.Tracer // WithTracerProvider が指定されていない場合は何も記録しない
+	recorders     []Recorder
+	stopOnFailure bool
+}
+
+// New は、新しい Engine を作成します。
+func New(opts ...Option) *Engine {
+	e := &Engine{parallelism: DefaultParallelism, backoff: DefaultRetryBackoff, logger: slog.Default(), tracer: noop.NewTracerProvider().Tracer(tracerName)}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Run は、jobs を最大で並行数の上限まで同時に fn で実行し、すべての転送が終わるまで待ちます。
+// 失敗した転送は再試行し、それでも失敗した場合も残りの転送は続行します (WithStopOnFailure の場合は未開始の転送を中止します)。
+// 失敗した転送がある場合は、それらをまとめた *Error を返します。ctx がキャンセルされた場合は、未開始の転送を実行せずに ctx のエラーを返します。
+func (e *Engine) Run(ctx context.Context, jobs []Job, fn Func) error {
+	_, err := e.RunWithStats(ctx, jobs, fn)
+	return err
+}
+
+// RunWithStats は、Run と同様に jobs を実行し、転送ごとのバイト数、所要時間と再試行の回数を併せて返します。
+// 計測結果は、エラーを返す場合も返します。バイト数は、fn が AddBytes または CountReader で報告したものです。
+func (e *Engine) RunWithStats(ctx context.Context, jobs []Job, fn Func) (stats *Stats, err error) {
+	ctx, span := e.tracer.Start(ctx, "transfer.Run", trace.WithAttributes(
+		attribute.Int("transfer.jobs", len(jobs)),
+		attribute.Int("transfer.parallelism", e.parallelism),
+	))
+	start := time.Now()
+	defer func() {
+		span.SetAttributes(attribute.Int64("transfer.duration_ms", time.Since(start).Milliseconds()))
+		endSpan(span, err)
+	}()
+
+	// 各ワーカーは jobs のインデックスを受け取り、結果を同じインデックスに格納する (jobs の順に報告するため)
+	queue := make(chan int)
+	results := make([]JobStats, len(jobs))
+	started := make([]bool, len(jobs))
+	stop := make(chan struct{}) // WithStopOnFailure で、失敗した転送があった場合に閉じる
+	var stopOnce sync.Once
+	var wg sync.WaitGroup
+	for range min(e.parallelism, len(jobs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				started[i] = true
+				results[i] = e.runJob(ctx, jobs[i], fn)
+				for _, r := range e.recorders {
+					r.RecordJob(results[i])
+				}
+				if results[i].Err != nil && e.stopOnFailure {
+					stopOnce.Do(func() { close(stop) })
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range jobs {
+		// 失敗した転送がある場合は、空いているワーカーがあっても次の転送を開始しない
+		select {
+		case <-stop:
+			break dispatch
+		default:
+		}
+		select {
+		case queue <- i:
+		case <-stop:
+			break dispatch
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	stats = &Stats{Elapsed: time.Since(start)}
+	var failures []Failure
+	notStarted := 0
+	for i, r := range results {
+		if !started[i] {
+			notStarted++
+			continue
+		}
+		stats.Jobs = append(stats.Jobs, r)
+		if r.Err != nil {
+			failures = append(failures, Failure{Job: jobs[i], Err: r.Err})
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return stats, err
+	}
+	if len(failures) > 0 {
+		span.SetAttributes(attribute.Int("transfer.failures", len(failures)))
+		return stats, &Error{Failures: failures, Total: len(jobs), NotStarted: notStarted}
+	}
+	return stats, nil
+}
+
+// runJob は、1件の転送を実行し、失敗した場合は待機時間を2倍にしながら再試行します。
+func (e *Engine) runJob(ctx context.Context, job Job, fn Func) (stats JobStats) {
+	ctx, span := e.tracer.Start(ctx, "transfer.Job", trace.WithAttributes(
+		attribute.String("transfer.source", job.Source),
+		attribute.String("transfer.destination", job.Destination),
+	))
+	var bytes atomic.Int64
+	ctx = context.WithValue(ctx, bytesKey{}, &bytes)
+	start := time.Now()
+	defer func() {
+		stats.Job = job
+		stats.Bytes = bytes.Load()
+		stats.Duration = time.Since(start)
+		span.SetAttributes(
+			attribute.Int("transfer.attempts", stats.Retries+1),
+			attribute.Int64("transfer.bytes", stats.Bytes),
+			attribute.Int64("transfer.duration_ms", stats.Duration.Milliseconds()),
+		)
+		endSpan(span, stats.Err)
+	}()
+
+	backoff := e.backoff
+	for attempt := 0; ; attempt++ {
+		stats.Retries = attempt
+		bytes.Store(0) // 失敗した試行で転送したバイト数は含めない
+		err := fn(ctx, job)
+		if err == nil || attempt >= e.retries || ctx.Err() != nil {
+			stats.Err = err
+			return stats
+		}
+		if e.retryable != nil && !e.retryable(err) {
+			stats.Err = err
+			return stats
+		}
+
+		e.logger.Warn(remoteio.Message("転送に失敗したため再試行します"),
+			slog.String("source", job.Source),
+			slog.String("destination", job.Destination),
+			slog.Int("attempt", attempt+1),
+			slog.String("error", err.Error()),
+		)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			stats.Err = errors.Join(err, ctx.Err())
+			return stats
+		}
+		backoff *= 2
+	}
+}
+
+// endSpan は、err が nil でない場合はエラーとして記録してから span を終了します。
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}